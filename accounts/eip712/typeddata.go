@@ -0,0 +1,405 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package eip712 implements hashing of typed structured data as specified by
+// EIP-712, used by eth_signTypedData_v4.
+package eip712
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/math"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// domainType is the name of the type describing the signing domain.
+const domainType = "EIP712Domain"
+
+// Type is a single field of a struct type.
+type Type struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types maps struct type names to their fields.
+type Types map[string][]Type
+
+// TypedDataMessage holds the values of a struct, keyed by field name.
+type TypedDataMessage = map[string]interface{}
+
+// TypedDataDomain is the EIP712Domain of a signature. Empty fields are left
+// out of the domain separator.
+type TypedDataDomain struct {
+	Name              string                `json:"name"`
+	Version           string                `json:"version"`
+	ChainId           *math.HexOrDecimal256 `json:"chainId"`
+	VerifyingContract string                `json:"verifyingContract"`
+	Salt              string                `json:"salt"`
+}
+
+// Map returns the non-empty domain fields keyed by their EIP-712 name.
+func (domain *TypedDataDomain) Map() map[string]interface{} {
+	dataMap := map[string]interface{}{}
+	if domain.ChainId != nil {
+		dataMap["chainId"] = domain.ChainId
+	}
+	if len(domain.Name) > 0 {
+		dataMap["name"] = domain.Name
+	}
+	if len(domain.Version) > 0 {
+		dataMap["version"] = domain.Version
+	}
+	if len(domain.VerifyingContract) > 0 {
+		dataMap["verifyingContract"] = domain.VerifyingContract
+	}
+	if len(domain.Salt) > 0 {
+		dataMap["salt"] = domain.Salt
+	}
+	return dataMap
+}
+
+// TypedData is the typed structured data accepted by eth_signTypedData_v4.
+type TypedData struct {
+	Types       Types            `json:"types"`
+	PrimaryType string           `json:"primaryType"`
+	Domain      TypedDataDomain  `json:"domain"`
+	Message     TypedDataMessage `json:"message"`
+}
+
+// UnmarshalJSON decodes the typed data, keeping integers as json.Number so
+// uint256 values survive without losing precision.
+func (typedData *TypedData) UnmarshalJSON(input []byte) error {
+	type typedDataJSON TypedData
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+	var data typedDataJSON
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+	*typedData = TypedData(data)
+	return nil
+}
+
+// TypedDataAndHash returns the digest to sign for the typed data together
+// with the raw "\x19\x01" ‖ domainSeparator ‖ hashStruct(message) preimage.
+func TypedDataAndHash(typedData TypedData) ([]byte, string, error) {
+	if err := typedData.validate(); err != nil {
+		return nil, "", err
+	}
+	domainSeparator, err := typedData.HashStruct(domainType, typedData.Domain.Map())
+	if err != nil {
+		return nil, "", err
+	}
+	rawData := "\x19\x01" + string(domainSeparator)
+	if typedData.PrimaryType != domainType {
+		typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+		if err != nil {
+			return nil, "", err
+		}
+		rawData += string(typedDataHash)
+	}
+	return crypto.Keccak256([]byte(rawData)), rawData, nil
+}
+
+// validate checks that the type definitions are usable.
+func (typedData *TypedData) validate() error {
+	if _, ok := typedData.Types[domainType]; !ok {
+		return fmt.Errorf("missing %s type", domainType)
+	}
+	if _, ok := typedData.Types[typedData.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q is not defined", typedData.PrimaryType)
+	}
+	for typeName, fields := range typedData.Types {
+		if typeName == "" {
+			return errors.New("empty type name")
+		}
+		for _, field := range fields {
+			if field.Name == "" || field.Type == "" {
+				return fmt.Errorf("type %q has a field without name or type", typeName)
+			}
+		}
+	}
+	return nil
+}
+
+// HashStruct returns hashStruct(s) = keccak256(typeHash ‖ encodeData(s)).
+func (typedData *TypedData) HashStruct(primaryType string, data TypedDataMessage) (hexutil.Bytes, error) {
+	encodedData, err := typedData.EncodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encodedData), nil
+}
+
+// TypeHash returns keccak256(encodeType(primaryType)).
+func (typedData *TypedData) TypeHash(primaryType string) hexutil.Bytes {
+	return crypto.Keccak256(typedData.EncodeType(primaryType))
+}
+
+// Dependencies returns the struct types referenced by primaryType, including
+// itself, in discovery order.
+func (typedData *TypedData) Dependencies(primaryType string, found []string) []string {
+	primaryType = baseType(primaryType)
+	if slices.Contains(found, primaryType) {
+		return found
+	}
+	if typedData.Types[primaryType] == nil {
+		return found
+	}
+	found = append(found, primaryType)
+	for _, field := range typedData.Types[primaryType] {
+		found = typedData.Dependencies(field.Type, found)
+	}
+	return found
+}
+
+// EncodeType returns the type encoding of primaryType, e.g.
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+// Referenced struct types follow the primary type in alphabetical order.
+func (typedData *TypedData) EncodeType(primaryType string) hexutil.Bytes {
+	deps := typedData.Dependencies(primaryType, nil)
+	if len(deps) > 0 {
+		sort.Strings(deps[1:])
+	}
+	var buffer bytes.Buffer
+	for _, dep := range deps {
+		buffer.WriteString(dep)
+		buffer.WriteString("(")
+		for i, field := range typedData.Types[dep] {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString(field.Type)
+			buffer.WriteString(" ")
+			buffer.WriteString(field.Name)
+		}
+		buffer.WriteString(")")
+	}
+	return buffer.Bytes()
+}
+
+// EncodeData returns typeHash ‖ encodeData(s) for a struct value: every field
+// is encoded into 32 bytes, dynamic values, arrays and nested structs by
+// their hash.
+func (typedData *TypedData) EncodeData(primaryType string, data map[string]interface{}) (hexutil.Bytes, error) {
+	fields := typedData.Types[primaryType]
+	for name := range data {
+		if !slices.ContainsFunc(fields, func(field Type) bool { return field.Name == name }) {
+			return nil, fmt.Errorf("field %q is not defined in type %s", name, primaryType)
+		}
+	}
+	var buffer bytes.Buffer
+	buffer.Write(typedData.TypeHash(primaryType))
+
+	for _, field := range fields {
+		encValue, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for field %s of type %s", field.Name, primaryType)
+		}
+		encoded, err := typedData.encodeValue(field.Type, encValue)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		buffer.Write(encoded)
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeValue returns the 32 byte encoding of a single value.
+func (typedData *TypedData) encodeValue(encType string, encValue interface{}) ([]byte, error) {
+	if strings.HasSuffix(encType, "]") {
+		return typedData.encodeArrayValue(encType, encValue)
+	}
+	if typedData.Types[encType] != nil {
+		mapValue, ok := encValue.(map[string]interface{})
+		if !ok {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		return typedData.HashStruct(encType, mapValue)
+	}
+	return encodePrimitiveValue(encType, encValue)
+}
+
+// encodeArrayValue returns the hash of the concatenated element encodings of
+// an array, checking the length of fixed size arrays.
+func (typedData *TypedData) encodeArrayValue(encType string, encValue interface{}) ([]byte, error) {
+	items, ok := encValue.([]interface{})
+	if !ok {
+		return nil, dataMismatchError(encType, encValue)
+	}
+	open := strings.LastIndex(encType, "[")
+	if open < 0 {
+		return nil, fmt.Errorf("invalid array type %q", encType)
+	}
+	if size := encType[open+1 : len(encType)-1]; size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid array type %q", encType)
+		}
+		if n != len(items) {
+			return nil, fmt.Errorf("array of type %s has %d elements", encType, len(items))
+		}
+	}
+	elemType := encType[:open]
+
+	var buffer bytes.Buffer
+	for _, item := range items {
+		encoded, err := typedData.encodeValue(elemType, item)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encoded)
+	}
+	return crypto.Keccak256(buffer.Bytes()), nil
+}
+
+// encodePrimitiveValue returns the 32 byte encoding of an atomic or dynamic
+// Solidity value.
+func encodePrimitiveValue(encType string, encValue interface{}) ([]byte, error) {
+	switch encType {
+	case "address":
+		str, ok := encValue.(string)
+		if !ok || !types.IsHexAddress(str) {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		retval := make([]byte, 32)
+		copy(retval[12:], types.HexToAddress(str).Bytes())
+		return retval, nil
+	case "bool":
+		boolValue, ok := encValue.(bool)
+		if !ok {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		retval := make([]byte, 32)
+		if boolValue {
+			retval[31] = 1
+		}
+		return retval, nil
+	case "string":
+		strVal, ok := encValue.(string)
+		if !ok {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		return crypto.Keccak256([]byte(strVal)), nil
+	case "bytes":
+		bytesValue, ok := parseBytes(encValue)
+		if !ok {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		return crypto.Keccak256(bytesValue), nil
+	}
+	if strings.HasPrefix(encType, "bytes") {
+		length, err := strconv.Atoi(strings.TrimPrefix(encType, "bytes"))
+		if err != nil || length < 1 || length > 32 {
+			return nil, fmt.Errorf("invalid size on bytes: %s", encType)
+		}
+		bytesValue, ok := parseBytes(encValue)
+		if !ok || len(bytesValue) != length {
+			return nil, dataMismatchError(encType, encValue)
+		}
+		// Fixed size bytes are right padded
+		retval := make([]byte, 32)
+		copy(retval, bytesValue)
+		return retval, nil
+	}
+	if strings.HasPrefix(encType, "int") || strings.HasPrefix(encType, "uint") {
+		b, err := parseInteger(encType, encValue)
+		if err != nil {
+			return nil, err
+		}
+		return math.U256Bytes(new(big.Int).Set(b)), nil
+	}
+	return nil, fmt.Errorf("unrecognized type %q", encType)
+}
+
+// parseBytes decodes a 0x prefixed hex string.
+func parseBytes(encValue interface{}) ([]byte, bool) {
+	str, ok := encValue.(string)
+	if !ok {
+		return nil, false
+	}
+	b, err := hexutil.Decode(str)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// parseInteger parses a JSON number or a hex or decimal string and checks it
+// fits into the given Solidity integer type.
+func parseInteger(encType string, encValue interface{}) (*big.Int, error) {
+	signed := strings.HasPrefix(encType, "int")
+	length := 256
+	if size := strings.TrimPrefix(strings.TrimPrefix(encType, "u"), "int"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 8 || n > 256 || n%8 != 0 {
+			return nil, fmt.Errorf("invalid size on integer: %s", encType)
+		}
+		length = n
+	}
+	var b *big.Int
+	switch v := encValue.(type) {
+	case *math.HexOrDecimal256:
+		b = (*big.Int)(v)
+	case json.Number:
+		b, _ = math.ParseBig256(string(v))
+	case string:
+		negative := strings.HasPrefix(v, "-")
+		if b, _ = math.ParseBig256(strings.TrimPrefix(v, "-")); b != nil && negative {
+			b.Neg(b)
+		}
+	case float64:
+		if float64(int64(v)) == v {
+			b = big.NewInt(int64(v))
+		}
+	}
+	if b == nil {
+		return nil, dataMismatchError(encType, encValue)
+	}
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(length-1))
+		if b.Cmp(limit) >= 0 || b.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("integer %v out of range for %s", b, encType)
+		}
+		return b, nil
+	}
+	if b.Sign() < 0 || b.BitLen() > length {
+		return nil, fmt.Errorf("integer %v out of range for %s", b, encType)
+	}
+	return b, nil
+}
+
+// baseType strips array suffixes from a type name.
+func baseType(typeName string) string {
+	if i := strings.Index(typeName, "["); i >= 0 {
+		return typeName[:i]
+	}
+	return typeName
+}
+
+func dataMismatchError(encType string, encValue interface{}) error {
+	return fmt.Errorf("provided data '%v' doesn't match type '%s'", encValue, encType)
+}
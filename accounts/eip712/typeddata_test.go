@@ -0,0 +1,129 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/hexutil"
+)
+
+// mailJSON is the example from the EIP-712 specification.
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataHash(t *testing.T) {
+	var typedData TypedData
+	if err := json.Unmarshal([]byte(mailJSON), &typedData); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(typedData.EncodeType("Mail")), "Mail(Person from,Person to,string contents)Person(string name,address wallet)"; have != want {
+		t.Errorf("EncodeType = %s, want %s", have, want)
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := domainSeparator.String(), "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; have != want {
+		t.Errorf("domain separator = %s, want %s", have, want)
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := messageHash.String(), "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"; have != want {
+		t.Errorf("message hash = %s, want %s", have, want)
+	}
+	digest, _, err := TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hexutil.Encode(digest), "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; have != want {
+		t.Errorf("digest = %s, want %s", have, want)
+	}
+
+	key := crypto.ToECDSAUnsafe(crypto.Keccak256([]byte("cow")))
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hexutil.Encode(sig[:64]), "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"; have != want {
+		t.Errorf("signature = %s, want %s", have, want)
+	}
+	if sig[64]+27 != 28 {
+		t.Errorf("signature V = %d, want 28", sig[64]+27)
+	}
+}
+
+func TestTypedDataErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		message string
+	}{
+		{"extra field", `[{"name": "a", "type": "uint8"}]`, `{"a": 1, "b": 2}`},
+		{"missing field", `[{"name": "a", "type": "uint8"}, {"name": "b", "type": "uint8"}]`, `{"a": 1}`},
+		{"uint overflow", `[{"name": "a", "type": "uint8"}]`, `{"a": 256}`},
+		{"negative uint", `[{"name": "a", "type": "uint256"}]`, `{"a": "-1"}`},
+		{"int overflow", `[{"name": "a", "type": "int8"}]`, `{"a": 128}`},
+		{"bytes length", `[{"name": "a", "type": "bytes4"}]`, `{"a": "0x010203"}`},
+		{"fixed array length", `[{"name": "a", "type": "uint8[2]"}]`, `{"a": [1]}`},
+		{"bad address", `[{"name": "a", "type": "address"}]`, `{"a": "0x1234"}`},
+		{"unknown type", `[{"name": "a", "type": "uint7"}]`, `{"a": 1}`},
+	}
+	for _, tt := range tests {
+		input := `{"types": {"EIP712Domain": [], "T": ` + tt.fields + `}, "primaryType": "T", "domain": {}, "message": ` + tt.message + `}`
+		var typedData TypedData
+		if err := json.Unmarshal([]byte(input), &typedData); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if _, _, err := TypedDataAndHash(typedData); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"errors"
+	"io"
+)
+
+// errUnsupportedPlatform is returned when hardware wallets are requested on an
+// operating system without a HID transport.
+var errUnsupportedPlatform = errors.New("usb wallets are not supported on this platform")
+
+// deviceInfo describes a HID device found during enumeration.
+type deviceInfo struct {
+	Path      string // Platform specific device path, used to open the device
+	VendorID  uint16 // USB vendor identifier
+	ProductID uint16 // USB product identifier
+	UsagePage uint16 // Usage page of the first collection in the report descriptor
+	Interface int    // USB interface number, -1 if unknown
+	Product   string // Product name reported by the device
+}
+
+// device is an open HID connection exchanging 64 byte reports.
+type device interface {
+	io.ReadWriteCloser
+}
+
+var (
+	// enumerateDevices lists the HID devices attached to the machine.
+	enumerateDevices = enumerateHID
+
+	// openDevice opens a connection to an enumerated HID device.
+	openDevice = openHID
+)
+
+// usagePage extracts the usage page of the first collection from a raw HID
+// report descriptor, or 0 if the descriptor declares none.
+func usagePage(desc []byte) uint16 {
+	for i := 0; i < len(desc); {
+		prefix := desc[i]
+		// Long items carry their data size in the next byte and never set a usage page
+		if prefix == 0xfe {
+			if i+1 >= len(desc) {
+				return 0
+			}
+			i += 3 + int(desc[i+1])
+			continue
+		}
+		size := int(prefix & 0x03)
+		if size == 3 {
+			size = 4
+		}
+		if i+1+size > len(desc) {
+			return 0
+		}
+		// Global item, tag 0: Usage Page
+		if prefix&0xfc == 0x04 {
+			var page uint32
+			for j := size - 1; j >= 0; j-- {
+				page = page<<8 | uint32(desc[i+1+j])
+			}
+			return uint16(page)
+		}
+		i += 1 + size
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package usbwallet
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// hidSupported reports whether the platform has a HID transport.
+const hidSupported = true
+
+// hidrawClass is where the kernel lists the raw HID devices.
+const hidrawClass = "/sys/class/hidraw"
+
+// enumerateHID lists the raw HID devices known to the kernel. Devices that
+// cannot be inspected are skipped, as are all devices if hidraw is missing.
+func enumerateHID() ([]deviceInfo, error) {
+	entries, err := os.ReadDir(hidrawClass)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var infos []deviceInfo
+	for _, entry := range entries {
+		dir := filepath.Join(hidrawClass, entry.Name(), "device")
+		uevent, err := os.ReadFile(filepath.Join(dir, "uevent"))
+		if err != nil {
+			continue
+		}
+		info := deviceInfo{Path: filepath.Join("/dev", entry.Name()), Interface: -1}
+		for _, line := range strings.Split(string(uevent), "\n") {
+			key, value, _ := strings.Cut(line, "=")
+			switch key {
+			case "HID_ID":
+				// Formatted as bus:vendor:product, e.g. 0003:00002C97:00004011
+				parts := strings.Split(value, ":")
+				if len(parts) != 3 {
+					continue
+				}
+				vendor, _ := strconv.ParseUint(parts[1], 16, 32)
+				product, _ := strconv.ParseUint(parts[2], 16, 32)
+				info.VendorID, info.ProductID = uint16(vendor), uint16(product)
+			case "HID_NAME":
+				info.Product = value
+			}
+		}
+		// The HID device sits below its USB interface, named like 1-2:1.0
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			iface := filepath.Base(filepath.Dir(real))
+			if i := strings.LastIndexByte(iface, '.'); i >= 0 {
+				if n, err := strconv.Atoi(iface[i+1:]); err == nil {
+					info.Interface = n
+				}
+			}
+		}
+		if desc, err := os.ReadFile(filepath.Join(dir, "report_descriptor")); err == nil {
+			info.UsagePage = usagePage(desc)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// openHID opens a raw HID device node. Reads return one input report each and
+// writes send one output report, with the report number as the first byte.
+func openHID(info deviceInfo) (device, error) {
+	return os.OpenFile(info.Path, os.O_RDWR, 0)
+}
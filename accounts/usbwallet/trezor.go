@@ -0,0 +1,512 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// This file contains the implementation for interacting with the Trezor hardware
+// wallets. The wire protocol spec can be found on the SatoshiLabs website:
+// https://docs.trezor.io/trezor-firmware/common/communication/index.html
+
+package usbwallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ErrTrezorPINNeeded is returned if opening the trezor requires a PIN code. In
+// this case, the calling application should display a pinpad and send back the
+// encoded passphrase.
+var ErrTrezorPINNeeded = errors.New("trezor: pin needed")
+
+// ErrTrezorPassphraseNeeded is returned if opening the trezor requires a passphrase
+var ErrTrezorPassphraseNeeded = errors.New("trezor: passphrase needed")
+
+// errTrezorReplyInvalidHeader is the error message returned by a Trezor data exchange
+// if the device replies with a mismatching header. This usually means the device
+// is in browser mode.
+var errTrezorReplyInvalidHeader = errors.New("trezor: invalid reply header")
+
+// Trezor message types, as defined in messages.proto of the Trezor firmware.
+const (
+	trezorInitialize               uint16 = 0
+	trezorPing                     uint16 = 1
+	trezorSuccess                  uint16 = 2
+	trezorFailure                  uint16 = 3
+	trezorFeatures                 uint16 = 17
+	trezorPinMatrixRequest         uint16 = 18
+	trezorPinMatrixAck             uint16 = 19
+	trezorButtonRequest            uint16 = 26
+	trezorButtonAck                uint16 = 27
+	trezorPassphraseRequest        uint16 = 41
+	trezorPassphraseAck            uint16 = 42
+	trezorEthereumGetAddress       uint16 = 56
+	trezorEthereumAddress          uint16 = 57
+	trezorEthereumSignTx           uint16 = 58
+	trezorEthereumTxRequest        uint16 = 59
+	trezorEthereumTxAck            uint16 = 60
+	trezorEthereumSignMessage      uint16 = 64
+	trezorEthereumMessageSignature uint16 = 66
+	trezorEthereumSignTxEIP1559    uint16 = 452
+)
+
+// trezorMessage is an encoded protobuf message of the Trezor wire protocol.
+// Only the few messages needed to derive addresses and sign are supported, so
+// they are assembled by hand rather than from generated code.
+type trezorMessage struct {
+	kind uint16
+	body []byte
+}
+
+// trezorFields holds the decoded fields of a Trezor reply. Repeated fields keep
+// their last value, none of the replies used rely on them.
+type trezorFields struct {
+	varints map[protowire.Number]uint64
+	bytes   map[protowire.Number][]byte
+}
+
+// decodeTrezorFields parses a protobuf encoded Trezor reply.
+func decodeTrezorFields(b []byte) (trezorFields, error) {
+	fields := trezorFields{varints: make(map[protowire.Number]uint64), bytes: make(map[protowire.Number][]byte)}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fields, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return fields, protowire.ParseError(n)
+			}
+			fields.varints[num] = v
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return fields, protowire.ParseError(n)
+			}
+			fields.bytes[num] = v
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return fields, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return fields, nil
+}
+
+// appendTrezorPath encodes a derivation path as the repeated address_n field.
+func appendTrezorPath(b []byte, path accounts.DerivationPath) []byte {
+	for _, component := range path {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(component))
+	}
+	return b
+}
+
+// appendTrezorBytes encodes a length delimited field.
+func appendTrezorBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// appendTrezorVarint encodes a varint field.
+func appendTrezorVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// trezorDriver implements the communication with a Trezor hardware wallet.
+type trezorDriver struct {
+	device         io.ReadWriter // USB device connection to communicate through
+	version        [3]uint32     // Current version of the Trezor firmware
+	label          string        // Current textual label of the Trezor device
+	pinwait        bool          // Flags whether the device is waiting for PIN entry
+	passphrasewait bool          // Flags whether the device is waiting for passphrase entry
+	failure        error         // Any failure that would make the device unusable
+	log            log.Logger    // Contextual logger to tag the trezor with its id
+}
+
+// newTrezorDriver creates a new instance of a Trezor USB protocol driver.
+func newTrezorDriver(logger log.Logger) driver {
+	return &trezorDriver{
+		log: logger,
+	}
+}
+
+// Status implements accounts.Wallet, always whether the Trezor is opened, closed
+// or whether the Ethereum app was not started on it.
+func (w *trezorDriver) Status() (string, error) {
+	if w.failure != nil {
+		return fmt.Sprintf("Trezor v%d.%d.%d '%s' failed: %v", w.version[0], w.version[1], w.version[2], w.label, w.failure), w.failure
+	}
+	if w.device == nil {
+		return "Closed", w.failure
+	}
+	if w.pinwait {
+		return fmt.Sprintf("Trezor v%d.%d.%d '%s' waiting for PIN", w.version[0], w.version[1], w.version[2], w.label), w.failure
+	}
+	return fmt.Sprintf("Trezor v%d.%d.%d '%s' online", w.version[0], w.version[1], w.version[2], w.label), w.failure
+}
+
+// Open implements usbwallet.driver, attempting to initialize the connection to
+// the Trezor hardware wallet. Initializing the Trezor is a two or three phase operation:
+//   - The first phase is to initialize the connection and read the wallet's
+//     features. This phase is invoked if the provided passphrase is empty. The
+//     device will display the pinpad as a result and will return an appropriate
+//     error to notify the user that a second open phase is needed.
+//   - The second phase is to unlock access to the Trezor, which is done by the
+//     user actually providing a passphrase mapping a keyboard keypad to the pin
+//     number of the user (shuffled according to the pinpad displayed).
+//   - If needed the device will ask for passphrase which will require calling
+//     open again with the actual passphrase (3rd phase)
+func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
+	w.device, w.failure = device, nil
+
+	// If phase 1 is requested, init the connection and wait for user callback
+	if passphrase == "" && !w.passphrasewait {
+		// If we're already waiting for a PIN entry, insta-return
+		if w.pinwait {
+			return ErrTrezorPINNeeded
+		}
+		// Initialize a connection to the device
+		_, features, err := w.trezorExchange(trezorMessage{kind: trezorInitialize}, trezorFeatures)
+		if err != nil {
+			return err
+		}
+		w.version = [3]uint32{uint32(features.varints[2]), uint32(features.varints[3]), uint32(features.varints[4])}
+		w.label = string(features.bytes[10])
+
+		// Do a manual ping, forcing the device to ask for its PIN and Passphrase
+		var ping []byte
+		ping = appendTrezorVarint(ping, 3, 1) // pin_protection
+		ping = appendTrezorVarint(ping, 4, 1) // passphrase_protection
+		res, _, err := w.trezorExchange(trezorMessage{kind: trezorPing, body: ping}, trezorPinMatrixRequest, trezorPassphraseRequest, trezorSuccess)
+		if err != nil {
+			return err
+		}
+		// Only return the PIN request if the device wasn't unlocked until now
+		switch res {
+		case 0:
+			w.pinwait = true
+			return ErrTrezorPINNeeded
+		case 1:
+			w.pinwait = false
+			w.passphrasewait = true
+			return ErrTrezorPassphraseNeeded
+		case 2:
+			return nil // responded with Success
+		}
+	}
+	// Phase 2 requested with actual PIN entry
+	if w.pinwait {
+		w.pinwait = false
+		res, _, err := w.trezorExchange(trezorMessage{kind: trezorPinMatrixAck, body: appendTrezorBytes(nil, 1, []byte(passphrase))}, trezorSuccess, trezorPassphraseRequest)
+		if err != nil {
+			w.failure = err
+			return err
+		}
+		if res == 1 {
+			w.passphrasewait = true
+			return ErrTrezorPassphraseNeeded
+		}
+	} else if w.passphrasewait {
+		w.passphrasewait = false
+		if _, _, err := w.trezorExchange(trezorMessage{kind: trezorPassphraseAck, body: appendTrezorBytes(nil, 1, []byte(passphrase))}, trezorSuccess); err != nil {
+			w.failure = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements usbwallet.driver, cleaning up and metadata maintained within
+// the Trezor driver.
+func (w *trezorDriver) Close() error {
+	w.version, w.label, w.pinwait = [3]uint32{}, "", false
+	return nil
+}
+
+// Heartbeat implements usbwallet.driver, performing a sanity check against the
+// Trezor to see if it's still online.
+func (w *trezorDriver) Heartbeat() error {
+	if _, _, err := w.trezorExchange(trezorMessage{kind: trezorPing}, trezorSuccess); err != nil {
+		w.failure = err
+		return err
+	}
+	return nil
+}
+
+// Derive implements usbwallet.driver, sending a derivation request to the Trezor
+// and returning the Ethereum address located on that derivation path.
+func (w *trezorDriver) Derive(path accounts.DerivationPath) (types.Address, error) {
+	return w.trezorDerive(path)
+}
+
+// SignTx implements usbwallet.driver, sending the transaction to the Trezor and
+// waiting for the user to confirm or deny the transaction.
+func (w *trezorDriver) SignTx(path accounts.DerivationPath, tx *transaction.Transaction, chainID *big.Int) (types.Address, *transaction.Transaction, error) {
+	if w.device == nil {
+		return types.Address{}, nil, accounts.ErrWalletClosed
+	}
+	return w.trezorSign(path, tx, chainID)
+}
+
+// SignText implements usbwallet.driver, sending the personal message to the
+// Trezor and waiting for the user to confirm or deny it.
+func (w *trezorDriver) SignText(path accounts.DerivationPath, text []byte) ([]byte, error) {
+	if w.device == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	body := appendTrezorPath(nil, path)
+	body = appendTrezorBytes(body, 2, text)
+
+	_, reply, err := w.trezorExchange(trezorMessage{kind: trezorEthereumSignMessage, body: body}, trezorEthereumMessageSignature)
+	if err != nil {
+		return nil, err
+	}
+	signature := reply.bytes[2]
+	if len(signature) != 65 {
+		return nil, errors.New("reply lacks signature")
+	}
+	// The Trezor replies with V as 27 or 28, convert to the canonical 0 or 1
+	signature[64] -= 27
+	return signature, nil
+}
+
+// trezorDerive sends a derivation request to the Trezor device and returns the
+// Ethereum address located on that path.
+func (w *trezorDriver) trezorDerive(derivationPath accounts.DerivationPath) (types.Address, error) {
+	_, reply, err := w.trezorExchange(trezorMessage{kind: trezorEthereumGetAddress, body: appendTrezorPath(nil, derivationPath)}, trezorEthereumAddress)
+	if err != nil {
+		return types.Address{}, err
+	}
+	// Newer firmwares reply with a hex string, older ones with the raw bytes
+	if addr := reply.bytes[2]; len(addr) > 0 {
+		if !types.IsHexAddress(string(addr)) {
+			return types.Address{}, fmt.Errorf("invalid address %q", addr)
+		}
+		return types.HexToAddress(string(addr)), nil
+	}
+	if addr := reply.bytes[1]; len(addr) == types.AddressLength {
+		return types.BytesToAddress(addr), nil
+	}
+	return types.Address{}, errors.New("missing derived address")
+}
+
+// trezorSign sends the transaction to the Trezor wallet, and waits for the user
+// to confirm or deny the transaction.
+func (w *trezorDriver) trezorSign(derivationPath accounts.DerivationPath, tx *transaction.Transaction, chainID *big.Int) (types.Address, *transaction.Transaction, error) {
+	if chainID == nil {
+		return types.Address{}, nil, errors.New("trezor: replay unprotected transactions are not supported")
+	}
+	// Create the transaction initiation message
+	data := tx.Data()
+	length := uint32(len(data))
+
+	var (
+		request  trezorMessage
+		initData []byte
+	)
+	if length > 1024 { // Send the data chunked if that was requested
+		initData, data = data[:1024], data[1024:]
+	} else {
+		initData, data = data, nil
+	}
+	body := appendTrezorPath(nil, derivationPath)
+	body = appendTrezorBytes(body, 2, new(big.Int).SetUint64(tx.Nonce()).Bytes())
+
+	switch tx.Type() {
+	case transaction.LegacyTxType:
+		body = appendTrezorBytes(body, 3, tx.GasPrice().Bytes())
+		body = appendTrezorBytes(body, 4, new(big.Int).SetUint64(tx.Gas()).Bytes())
+		body = appendTrezorBytes(body, 6, tx.Value().Bytes())
+		body = appendTrezorBytes(body, 7, initData)
+		body = appendTrezorVarint(body, 8, uint64(length))
+		body = appendTrezorVarint(body, 9, chainID.Uint64())
+		if to := tx.To(); to != nil {
+			body = appendTrezorBytes(body, 11, []byte(to.Hex()))
+		}
+		request = trezorMessage{kind: trezorEthereumSignTx, body: body}
+
+	case transaction.DynamicFeeTxType:
+		body = appendTrezorBytes(body, 3, tx.GasFeeCap().Bytes())
+		body = appendTrezorBytes(body, 4, tx.GasTipCap().Bytes())
+		body = appendTrezorBytes(body, 5, new(big.Int).SetUint64(tx.Gas()).Bytes())
+		if to := tx.To(); to != nil {
+			body = appendTrezorBytes(body, 6, []byte(to.Hex()))
+		}
+		body = appendTrezorBytes(body, 7, tx.Value().Bytes())
+		body = appendTrezorBytes(body, 8, initData)
+		body = appendTrezorVarint(body, 9, uint64(length))
+		body = appendTrezorVarint(body, 10, chainID.Uint64())
+		for _, tuple := range tx.AccessList() {
+			entry := appendTrezorBytes(nil, 1, []byte(tuple.Address.Hex()))
+			for _, key := range tuple.StorageKeys {
+				entry = appendTrezorBytes(entry, 2, key[:])
+			}
+			body = appendTrezorBytes(body, 11, entry)
+		}
+		request = trezorMessage{kind: trezorEthereumSignTxEIP1559, body: body}
+
+	default:
+		return types.Address{}, nil, transaction.ErrTxTypeNotSupported
+	}
+	// Send the initiation message and stream content until a signature is returned
+	_, response, err := w.trezorExchange(request, trezorEthereumTxRequest)
+	if err != nil {
+		return types.Address{}, nil, err
+	}
+	for {
+		requested, ok := response.varints[1]
+		if !ok || int(requested) > len(data) {
+			break
+		}
+		chunk := data[:requested]
+		data = data[requested:]
+
+		if _, response, err = w.trezorExchange(trezorMessage{kind: trezorEthereumTxAck, body: appendTrezorBytes(nil, 1, chunk)}, trezorEthereumTxRequest); err != nil {
+			return types.Address{}, nil, err
+		}
+	}
+	// Extract the Ethereum signature and do a sanity validation
+	r, s, v := response.bytes[3], response.bytes[4], response.varints[2]
+	if len(r) == 0 || len(s) == 0 || len(r) > 32 || len(s) > 32 {
+		return types.Address{}, nil, errors.New("reply lacks signature")
+	}
+	signature := make([]byte, 65)
+	copy(signature[32-len(r):32], r)
+	copy(signature[64-len(s):64], s)
+
+	// Legacy replies carry the EIP-155 V, typed ones the bare recovery id
+	if tx.Type() == transaction.LegacyTxType {
+		v -= chainID.Uint64()*2 + 35
+	}
+	signature[64] = byte(v)
+
+	signer := transaction.LatestSignerForChainID(chainID)
+	signed, err := tx.WithSignature(signer, signature)
+	if err != nil {
+		return types.Address{}, nil, err
+	}
+	sender, err := transaction.Sender(signer, signed)
+	if err != nil {
+		return types.Address{}, nil, err
+	}
+	return sender, signed, nil
+}
+
+// trezorExchange performs a data exchange with the Trezor wallet, sending it a
+// message and retrieving the response. If multiple responses are possible, the
+// method will also return the index of the destination object used.
+func (w *trezorDriver) trezorExchange(req trezorMessage, results ...uint16) (int, trezorFields, error) {
+	// Construct the original message payload to chunk up
+	payload := make([]byte, 8+len(req.body))
+	copy(payload, []byte{0x23, 0x23})
+	binary.BigEndian.PutUint16(payload[2:], req.kind)
+	binary.BigEndian.PutUint32(payload[4:], uint32(len(req.body)))
+	copy(payload[8:], req.body)
+
+	// Stream all the chunks to the device
+	chunk := make([]byte, 64)
+	chunk[0] = 0x3f // Report ID magic number
+
+	for len(payload) > 0 {
+		// Construct the new message to stream, padding with zeroes if needed
+		if len(payload) > 63 {
+			copy(chunk[1:], payload[:63])
+			payload = payload[63:]
+		} else {
+			copy(chunk[1:], payload)
+			copy(chunk[1+len(payload):], make([]byte, 63-len(payload)))
+			payload = nil
+		}
+		// Send over to the device
+		w.log.Trace("Data chunk sent to the Trezor", "chunk", hexutil.Bytes(chunk))
+		if _, err := w.device.Write(chunk); err != nil {
+			return 0, trezorFields{}, err
+		}
+	}
+	// Stream the reply back from the wallet in 64 byte chunks
+	var (
+		kind  uint16
+		reply []byte
+	)
+	for {
+		// Read the next chunk from the Trezor wallet
+		if _, err := io.ReadFull(w.device, chunk); err != nil {
+			return 0, trezorFields{}, err
+		}
+		w.log.Trace("Data chunk received from the Trezor", "chunk", hexutil.Bytes(chunk))
+
+		// Make sure the transport header matches
+		if chunk[0] != 0x3f || (len(reply) == 0 && (chunk[1] != 0x23 || chunk[2] != 0x23)) {
+			return 0, trezorFields{}, errTrezorReplyInvalidHeader
+		}
+		// If it's the first chunk, retrieve the reply message type and total message length
+		var payload []byte
+
+		if len(reply) == 0 {
+			kind = binary.BigEndian.Uint16(chunk[3:5])
+			reply = make([]byte, 0, int(binary.BigEndian.Uint32(chunk[5:9])))
+			payload = chunk[9:]
+		} else {
+			payload = chunk[1:]
+		}
+		// Append to the reply and stop when filled up
+		if left := cap(reply) - len(reply); left > len(payload) {
+			reply = append(reply, payload...)
+		} else {
+			reply = append(reply, payload[:left]...)
+			break
+		}
+	}
+	fields, err := decodeTrezorFields(reply)
+	if err != nil {
+		return 0, trezorFields{}, err
+	}
+	// Try to parse the reply into the requested reply message
+	if kind == trezorFailure {
+		return 0, trezorFields{}, errors.New("trezor: " + string(fields.bytes[2]))
+	}
+	if kind == trezorButtonRequest {
+		// Trezor is waiting for user confirmation, ack and wait for the next message
+		return w.trezorExchange(trezorMessage{kind: trezorButtonAck}, results...)
+	}
+	for i, res := range results {
+		if res == kind {
+			return i, fields, nil
+		}
+	}
+	expected := make([]string, len(results))
+	for i, res := range results {
+		expected[i] = fmt.Sprint(res)
+	}
+	return 0, trezorFields{}, fmt.Errorf("trezor: expected reply types %s, got %d", expected, kind)
+}
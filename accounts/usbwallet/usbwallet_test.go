@@ -0,0 +1,303 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/rlp"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ledgerEmulator speaks the Ledger HID transport and signs with a local key,
+// standing in for a device running the Ethereum app.
+type ledgerEmulator struct {
+	key     *ecdsa.PrivateKey
+	chainID uint64
+
+	apdu    []byte   // APDU being reassembled from written chunks
+	apduLen int      // Total length of the APDU being reassembled
+	payload []byte   // Signing payload accumulated across APDUs
+	replies [][]byte // Reply chunks waiting to be read
+}
+
+func (e *ledgerEmulator) Write(chunk []byte) (int, error) {
+	if chunk[0] != 0x01 || chunk[1] != 0x01 || chunk[2] != 0x05 {
+		return 0, errors.New("invalid request header")
+	}
+	if binary.BigEndian.Uint16(chunk[3:5]) == 0 {
+		e.apduLen = int(binary.BigEndian.Uint16(chunk[5:7]))
+		e.apdu = append([]byte{}, chunk[7:]...)
+	} else {
+		e.apdu = append(e.apdu, chunk[5:]...)
+	}
+	if len(e.apdu) >= e.apduLen {
+		e.reply(e.handle(e.apdu[:e.apduLen]))
+	}
+	return len(chunk), nil
+}
+
+func (e *ledgerEmulator) Read(chunk []byte) (int, error) {
+	if len(e.replies) == 0 {
+		return 0, errors.New("no pending reply")
+	}
+	n := copy(chunk, e.replies[0])
+	e.replies = e.replies[1:]
+	return n, nil
+}
+
+func (e *ledgerEmulator) Close() error { return nil }
+
+// reply frames the response data followed by the success status word.
+func (e *ledgerEmulator) reply(data []byte) {
+	data = append(data, 0x90, 0x00)
+	msg := binary.BigEndian.AppendUint16(nil, uint16(len(data)))
+	msg = append(msg, data...)
+	for seq := 0; len(msg) > 0; seq++ {
+		chunk := make([]byte, 64)
+		copy(chunk, []byte{0x01, 0x01, 0x05})
+		binary.BigEndian.PutUint16(chunk[3:], uint16(seq))
+		msg = msg[copy(chunk[5:], msg):]
+		e.replies = append(e.replies, chunk)
+	}
+}
+
+// handle executes an APDU, returning nil data for accepted partial payloads.
+func (e *ledgerEmulator) handle(apdu []byte) []byte {
+	ins, p1, data := apdu[1], apdu[2], apdu[5:5+int(apdu[4])]
+	switch ins {
+	case byte(ledgerOpGetConfiguration):
+		return []byte{0x01, 1, 10, 3}
+
+	case byte(ledgerOpRetrieveAddress):
+		pubkey := crypto.FromECDSAPub(&e.key.PublicKey)
+		addr := crypto.PubkeyToAddress(e.key.PublicKey)
+		reply := append([]byte{byte(len(pubkey))}, pubkey...)
+		reply = append(reply, 40)
+		return append(reply, hex.EncodeToString(addr[:])...)
+
+	case byte(ledgerOpSignTransaction):
+		if p1 == byte(ledgerP1InitTransactionData) {
+			e.payload = append([]byte{}, data[1+4*int(data[0]):]...)
+		} else {
+			e.payload = append(e.payload, data...)
+		}
+		// Wait until the whole transaction arrived
+		body := e.payload
+		if body[0] < 0x7f {
+			body = body[1:]
+		}
+		if _, _, err := rlp.SplitList(body); err != nil {
+			return nil
+		}
+		sig, _ := crypto.Sign(crypto.Keccak256(e.payload), e.key)
+		v := sig[64]
+		if e.payload[0] >= 0xc0 {
+			v += byte(e.chainID*2 + 35)
+		}
+		return append([]byte{v}, sig[:64]...)
+
+	case byte(ledgerOpSignPersonalMessage):
+		if p1 == byte(ledgerP1InitTransactionData) {
+			e.payload = append([]byte{}, data[1+4*int(data[0]):]...)
+		} else {
+			e.payload = append(e.payload, data...)
+		}
+		if size := int(binary.BigEndian.Uint32(e.payload)); len(e.payload) < 4+size {
+			return nil
+		}
+		sig, _ := crypto.Sign(accounts.TextHash(e.payload[4:]), e.key)
+		return append([]byte{sig[64] + 27}, sig[:64]...)
+	}
+	return nil
+}
+
+func TestLedgerWallet(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	emulator := &ledgerEmulator{key: key, chainID: 94}
+
+	defer func(enumerate func() ([]deviceInfo, error), open func(deviceInfo) (device, error)) {
+		enumerateDevices, openDevice = enumerate, open
+	}(enumerateDevices, openDevice)
+
+	enumerateDevices = func() ([]deviceInfo, error) {
+		return []deviceInfo{
+			{Path: "/dev/hidraw0", VendorID: 0x2c97, ProductID: 0x4011, Interface: 1},
+			{Path: "/dev/hidraw1", VendorID: 0x2c97, ProductID: 0x4011, Interface: 0},
+			{Path: "/dev/hidraw2", VendorID: 0x046d, ProductID: 0xc52b, Interface: 0},
+		}, nil
+	}
+	openDevice = func(deviceInfo) (device, error) { return emulator, nil }
+
+	hub, err := NewLedgerHub()
+	if err != nil {
+		t.Fatalf("failed to create hub: %v", err)
+	}
+	wallets := hub.Wallets()
+	if len(wallets) != 1 {
+		t.Fatalf("wallets = %d, want 1", len(wallets))
+	}
+	wallet := wallets[0]
+	if url := wallet.URL().String(); url != "ledger:///dev/hidraw1" {
+		t.Errorf("url = %s, want ledger:///dev/hidraw1", url)
+	}
+	if err := wallet.Open(""); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	if status, err := wallet.Status(); err != nil || status != "Ethereum app v1.10.3 online" {
+		t.Errorf("status = %q (%v)", status, err)
+	}
+	wallet.SelfDerive([]accounts.DerivationPath{accounts.DefaultBaseDerivationPath}, nil)
+	accs := wallet.Accounts()
+	if len(accs) != 1 || accs[0].Address != address {
+		t.Fatalf("accounts = %v, want %s", accs, address.Hex())
+	}
+	account := accs[0]
+
+	to := types.HexToAddress("0x000000000000000000000000000000000000aaaa")
+	txs := map[string]*transaction.Transaction{
+		"legacy": transaction.NewTx(&transaction.LegacyTx{
+			Nonce: 1, To: &to, Value: uint256.NewInt(1), Gas: 21000, GasPrice: uint256.NewInt(1e9),
+		}),
+		"dynamic fee": transaction.NewTx(&transaction.DynamicFeeTx{
+			ChainID: uint256.NewInt(94), Nonce: 2, To: &to, Value: uint256.NewInt(1), Gas: 21000,
+			GasTipCap: uint256.NewInt(1e9), GasFeeCap: uint256.NewInt(2e9), Data: bytes.Repeat([]byte{0xab}, 600),
+		}),
+	}
+	for name, tx := range txs {
+		signed, err := wallet.SignTx(account, tx, big.NewInt(94))
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", name, err)
+		}
+		sender, err := transaction.Sender(transaction.LatestSignerForChainID(big.NewInt(94)), signed)
+		if err != nil || sender != address {
+			t.Errorf("%s: sender = %s (%v), want %s", name, sender.Hex(), err, address.Hex())
+		}
+	}
+
+	text := []byte("withdraw validator rewards")
+	sig, err := wallet.SignText(account, text)
+	if err != nil {
+		t.Fatalf("failed to sign text: %v", err)
+	}
+	if sig[64] > 1 {
+		t.Errorf("signature V = %d, want 0 or 1", sig[64])
+	}
+	if _, err := wallet.SignText(accounts.Account{Address: to}, text); err != accounts.ErrUnknownAccount {
+		t.Errorf("signing with unknown account: err = %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+}
+
+// trezorEmulator replies to every request with a fixed message.
+type trezorEmulator struct {
+	request []byte
+	reply   trezorMessage
+	chunks  [][]byte
+}
+
+func (e *trezorEmulator) Write(chunk []byte) (int, error) {
+	e.request = append(e.request, chunk[1:]...)
+	if size := int(binary.BigEndian.Uint32(e.request[4:8])); len(e.request) >= 8+size {
+		msg := []byte{0x23, 0x23}
+		msg = binary.BigEndian.AppendUint16(msg, e.reply.kind)
+		msg = binary.BigEndian.AppendUint32(msg, uint32(len(e.reply.body)))
+		msg = append(msg, e.reply.body...)
+		for len(msg) > 0 {
+			chunk := make([]byte, 64)
+			chunk[0] = 0x3f
+			msg = msg[copy(chunk[1:], msg):]
+			e.chunks = append(e.chunks, chunk)
+		}
+	}
+	return len(chunk), nil
+}
+
+func (e *trezorEmulator) Read(chunk []byte) (int, error) {
+	n := copy(chunk, e.chunks[0])
+	e.chunks = e.chunks[1:]
+	return n, nil
+}
+
+func TestTrezorDerive(t *testing.T) {
+	want := types.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	emulator := &trezorEmulator{reply: trezorMessage{
+		kind: trezorEthereumAddress,
+		body: appendTrezorBytes(nil, 2, []byte(want.Hex())),
+	}}
+	driver := newTrezorDriver(log.New()).(*trezorDriver)
+	driver.device = emulator
+
+	path := accounts.DefaultBaseDerivationPath
+	addr, err := driver.Derive(path)
+	if err != nil {
+		t.Fatalf("failed to derive: %v", err)
+	}
+	if addr != want {
+		t.Errorf("address = %s, want %s", addr.Hex(), want.Hex())
+	}
+	// The request must carry the path as repeated address_n
+	if kind := binary.BigEndian.Uint16(emulator.request[2:4]); kind != trezorEthereumGetAddress {
+		t.Fatalf("request type = %d, want %d", kind, trezorEthereumGetAddress)
+	}
+	size := binary.BigEndian.Uint32(emulator.request[4:8])
+	var got accounts.DerivationPath
+	for body := emulator.request[8 : 8+size]; len(body) > 0; {
+		num, typ, n := protowire.ConsumeTag(body)
+		v, m := protowire.ConsumeVarint(body[n:])
+		if num != 1 || typ != protowire.VarintType || m < 0 {
+			t.Fatalf("unexpected field %d of type %d", num, typ)
+		}
+		got = append(got, uint32(v))
+		body = body[n+m:]
+	}
+	if got.String() != path.String() {
+		t.Errorf("path = %s, want %s", got, path)
+	}
+}
+
+func TestUsagePage(t *testing.T) {
+	tests := []struct {
+		desc []byte
+		want uint16
+	}{
+		{[]byte{0x06, 0xa0, 0xff, 0x09, 0x01, 0xa1, 0x01}, 0xffa0}, // Ledger
+		{[]byte{0x06, 0x00, 0xff, 0x09, 0x01, 0xa1, 0x01}, 0xff00}, // Trezor
+		{[]byte{0x05, 0x01, 0x09, 0x06, 0xa1, 0x01}, 0x0001},       // Keyboard
+		{[]byte{0x09, 0x01, 0xa1, 0x01}, 0},
+		{[]byte{0x06, 0xa0}, 0},
+	}
+	for i, tt := range tests {
+		if got := usagePage(tt.desc); got != tt.want {
+			t.Errorf("test %d: usage page = %#x, want %#x", i, got, tt.want)
+		}
+	}
+}
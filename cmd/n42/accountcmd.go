@@ -17,10 +17,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"github.com/n42blockchain/N42/log"
 	"github.com/urfave/cli/v2"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/n42blockchain/N42/cmd/utils"
 
@@ -71,7 +75,8 @@ passwordfile as argument containing the wallet password in plaintext.`,
 		Description: `
 
 Manage accounts, list all existing accounts, import a private key into a new
-account, create a new account or update an existing account.
+account, create a new account, export an account as keystore JSON or update an
+existing account.
 
 It supports interactive mode, when you are prompted for password as well as
 non-interactive mode where passwords are supplied via a given password file.
@@ -98,7 +103,7 @@ Make sure you backup your keys regularly.`,
 					KeyStoreDirFlag,
 				},
 				Description: `
-			Print a short summary of all accounts`,
+			Print a short summary of all accounts, including the time each key was created`,
 			},
 			{
 				Name:   "new",
@@ -133,6 +138,7 @@ password to file or expose in any other way.
 				Flags: []cli.Flag{
 					DataDirFlag,
 					KeyStoreDirFlag,
+					PasswordFileFlag,
 					LightKDFFlag,
 				},
 				Description: `
@@ -146,12 +152,12 @@ for a password to unlock the account and another to save the updated file.
 This same command can therefore be used to migrate an account of a deprecated
 format to the newest format or change the password for an account.
 
-For non-interactive use the password can be specified with the --password flag:
+For non-interactive use the passwords can be specified with the --password flag:
 
     N42 account update [options] <address>
 
-Since only one password can be given, only format update can be performed,
-changing your password is only possible interactively.
+The first line of the password file unlocks the account and the second line is
+the new password. If the file holds a single line, only the format is updated.
 `,
 			},
 			{
@@ -185,6 +191,31 @@ Note:
 As you can directly copy your encrypted accounts to another ethereum instance,
 this import mechanism is not needed when you transfer an account between
 nodes.
+`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export an account as encrypted keystore JSON",
+				Action:    accountExport,
+				ArgsUsage: "<address> [<file>]",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					KeyStoreDirFlag,
+					PasswordFileFlag,
+					LightKDFFlag,
+				},
+				Description: `
+    N42 account export <address> [<file>]
+
+Exports the key of an existing account as scrypt encrypted keystore (version 3)
+JSON. The JSON is written to <file>, or printed if no file is given.
+
+You are prompted for the password unlocking the account and for the password
+protecting the exported key, which may differ from the current one.
+
+For non-interactive use the passwords can be specified with the --password flag:
+the first line unlocks the account and the second line, if present, encrypts
+the exported key.
 `,
 			},
 		},
@@ -217,7 +248,10 @@ func importWallet(ctx *cli.Context) error {
 	return nil
 }
 
-func accountList(ctx *cli.Context) error {
+// makeKeyStore opens the keystore defined by the CLI flags and the config file
+// without bringing up the rest of the node, so accounts can be managed while a
+// node is running on the same data directory.
+func makeKeyStore() *keystore.KeyStore {
 	cfg := DefaultConfig
 	// Load config file.
 	if len(cfgFile) > 0 {
@@ -226,17 +260,47 @@ func accountList(ctx *cli.Context) error {
 		}
 	}
 
-	stack, err := node.NewNode(ctx, &cfg)
+	keydir, err := cfg.NodeCfg.KeyDirConfig()
 	if err != nil {
-		return err
+		utils.Fatalf("Failed to read configuration: %v", err)
+	}
+	scryptN := keystore.StandardScryptN
+	scryptP := keystore.StandardScryptP
+	if cfg.NodeCfg.UseLightweightKDF {
+		scryptN = keystore.LightScryptN
+		scryptP = keystore.LightScryptP
+	}
+	return keystore.NewKeyStore(keydir, scryptN, scryptP)
+}
+
+// keyCreationTime returns when a key file was created, taken from the UTC
+// timestamp the keystore puts in its file names and falling back to the
+// modification time for files named otherwise.
+func keyCreationTime(path string) (time.Time, error) {
+	name := filepath.Base(path)
+	if rest, ok := strings.CutPrefix(name, "UTC--"); ok {
+		if ts, _, ok := strings.Cut(rest, "--"); ok {
+			if created, err := time.Parse("2006-01-02T15-04-05.999999999Z07:00", ts); err == nil {
+				return created, nil
+			}
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
 	}
+	return info.ModTime(), nil
+}
+
+func accountList(ctx *cli.Context) error {
+	ks := makeKeyStore()
 
-	var index int
-	for _, wallet := range stack.AccountManager().Wallets() {
-		for _, account := range wallet.Accounts() {
-			fmt.Printf("Account #%d: {%s} %s\n", index, account.Address, &account.URL)
-			index++
+	for index, account := range ks.Accounts() {
+		created := "unknown"
+		if t, err := keyCreationTime(account.URL.Path); err == nil {
+			created = t.UTC().Format(time.DateTime) + " UTC"
 		}
+		fmt.Printf("Account #%d: {%s} %s (created %s)\n", index, account.Address, &account.URL, created)
 	}
 	return nil
 }
@@ -342,19 +406,16 @@ func accountUpdate(ctx *cli.Context) error {
 		utils.Fatalf("No accounts specified to update")
 	}
 
-	stack, err := node.NewNode(ctx, &DefaultConfig)
-	if err != nil {
-		return err
-	}
-
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	ks := makeKeyStore()
+	passwords := MakePasswordList(ctx)
 
 	for _, addr := range ctx.Args().Slice() {
-		account, oldPassword := unlockAccount(ks, addr, 0, nil)
-		newPassword := utils.GetPassPhraseWithList("Please give a new password. Do not forget this password.", true, 0, nil)
+		account, oldPassword := unlockAccount(ks, addr, 0, passwords)
+		newPassword := utils.GetPassPhraseWithList("Please give a new password. Do not forget this password.", true, 1, passwords)
 		if err := ks.Update(account, oldPassword, newPassword); err != nil {
 			utils.Fatalf("Could not update the account: %v", err)
 		}
+		fmt.Printf("Updated account {%s}\n", account.Address.Hex())
 	}
 	return nil
 }
@@ -369,18 +430,49 @@ func accountImport(ctx *cli.Context) error {
 		utils.Fatalf("Failed to load the private key: %v", err)
 	}
 
-	stack, err := node.NewNode(ctx, &DefaultConfig)
-	if err != nil {
-		return err
-	}
-
+	ks := makeKeyStore()
 	passphrase := utils.GetPassPhraseWithList("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, MakePasswordList(ctx))
 
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 	acct, err := ks.ImportECDSA(key, passphrase)
+	if errors.Is(err, keystore.ErrAccountAlreadyExists) {
+		utils.Fatalf("Account {%s} already exists in the keystore", acct.Address.Hex())
+	}
 	if err != nil {
 		utils.Fatalf("Could not create the account: %v", err)
 	}
-	fmt.Printf("Address: {%x}\n", acct.Address)
+	fmt.Printf("Address: {%s}\n", acct.Address.Hex())
+	return nil
+}
+
+// accountExport writes the key of an account as keystore JSON, encrypted with
+// a password that may differ from the one protecting the local key file.
+func accountExport(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 || ctx.Args().Len() > 2 {
+		utils.Fatalf("the account and an optional output file must be given as arguments")
+	}
+	ks := makeKeyStore()
+	passwords := MakePasswordList(ctx)
+
+	account, password := unlockAccount(ks, ctx.Args().First(), 0, passwords)
+	newPassword := utils.GetPassPhraseWithList("Please give a password for the exported key.", true, 1, passwords)
+	keyJSON, err := ks.Export(account, password, newPassword)
+	if err != nil {
+		utils.Fatalf("Could not export the account: %v", err)
+	}
+
+	if ctx.Args().Len() == 1 {
+		fmt.Println(string(keyJSON))
+		return nil
+	}
+	// Never clobber an existing file, it may well be another key
+	out, err := os.OpenFile(ctx.Args().Get(1), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		utils.Fatalf("Could not create the output file: %v", err)
+	}
+	defer out.Close()
+	if _, err := out.Write(keyJSON); err != nil {
+		utils.Fatalf("Could not write the output file: %v", err)
+	}
+	fmt.Printf("Exported account {%s} to %s\n", account.Address.Hex(), out.Name())
 	return nil
 }
@@ -40,27 +40,14 @@ import (
 )
 
 func appRun(ctx *cli.Context) error {
-	if len(cfgFile) > 0 {
-		if err := conf.LoadConfigFromFile(cfgFile, &DefaultConfig); err != nil {
-			return err
-		}
-	} else {
-		DefaultConfig.NetworkCfg.ListenersAddress = listenAddress.Value()
-		DefaultConfig.NetworkCfg.BootstrapPeers = bootstraps.Value()
-		if len(privateKey) > 0 {
-			DefaultConfig.NetworkCfg.LocalPeerKey = privateKey
-		}
-
-		DefaultConfig.P2PCfg.StaticPeers = p2pStaticPeers.Value()
-		DefaultConfig.P2PCfg.BootstrapNodeAddr = p2pBootstrapNode.Value()
-		DefaultConfig.P2PCfg.DenyListCIDR = p2pDenyList.Value()
-
-		//
-		DefaultConfig.P2PCfg.DataDir = DefaultConfig.NodeCfg.DataDir
+	if err := resolveConfig(); err != nil {
+		return err
 	}
 
 	log.Init(DefaultConfig.NodeCfg, DefaultConfig.LoggerCfg)
 
+	applyResourceLimits(&DefaultConfig.ResourceCfg)
+
 	if DefaultConfig.PprofCfg.Pprof {
 		if DefaultConfig.PprofCfg.MaxCpu > 0 {
 			runtime.GOMAXPROCS(DefaultConfig.PprofCfg.MaxCpu)
@@ -87,6 +74,7 @@ func appRun(ctx *cli.Context) error {
 	}
 
 	StartNode(ctx, stack, false)
+	go reloadLoggerOnHangup()
 
 	// Unlock any account specifically requested
 	unlockAccounts(ctx, stack, &DefaultConfig)
@@ -133,6 +121,58 @@ func appRun(ctx *cli.Context) error {
 	return nil
 }
 
+// resolveConfig merges the config file or, without one, the list flags into
+// DefaultConfig and validates the result. Scalar flags are written by the
+// flag parser directly.
+func resolveConfig() error {
+	if len(cfgFile) > 0 {
+		if err := conf.LoadConfigFromFile(cfgFile, &DefaultConfig); err != nil {
+			return err
+		}
+	} else {
+		DefaultConfig.NetworkCfg.ListenersAddress = listenAddress.Value()
+		DefaultConfig.NetworkCfg.BootstrapPeers = bootstraps.Value()
+		if len(privateKey) > 0 {
+			DefaultConfig.NetworkCfg.LocalPeerKey = privateKey
+		}
+
+		DefaultConfig.P2PCfg.StaticPeers = p2pStaticPeers.Value()
+		DefaultConfig.P2PCfg.BootstrapNodeAddr = p2pBootstrapNode.Value()
+		DefaultConfig.P2PCfg.DenyListCIDR = p2pDenyList.Value()
+		DefaultConfig.NodeCfg.Backfill = backfillIndexes.Value()
+		DefaultConfig.TxPoolCfg.Locals = txPoolLocals.Value()
+
+		//
+		DefaultConfig.P2PCfg.DataDir = DefaultConfig.NodeCfg.DataDir
+	}
+	return DefaultConfig.P2PCfg.Validate()
+}
+
+// reloadLoggerOnHangup re-reads the logging section of the config file on
+// every SIGHUP and applies it without restarting the node.
+func reloadLoggerOnHangup() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	for range sighup {
+		if len(cfgFile) == 0 {
+			log.Warn("Ignoring SIGHUP, the node was not started with a config file")
+			continue
+		}
+		// Keys missing from the file keep their current value.
+		cfg := conf.Config{LoggerCfg: DefaultConfig.LoggerCfg}
+		if err := conf.LoadConfigFromFile(cfgFile, &cfg); err != nil {
+			log.Error("Failed to reload config file", "file", cfgFile, "err", err)
+			continue
+		}
+		log.Info("Reloading logger config", "file", cfgFile, "level", cfg.LoggerCfg.Level)
+		if err := log.Reload(DefaultConfig.NodeCfg, cfg.LoggerCfg); err != nil {
+			log.Error("Failed to reload logger", "file", cfgFile, "err", err)
+			continue
+		}
+		DefaultConfig.LoggerCfg = cfg.LoggerCfg
+	}
+}
+
 // unlockAccounts unlocks any account specifically requested.
 func unlockAccounts(ctx *cli.Context, stack *node.Node, cfg *conf.Config) {
 	var unlocks []string
@@ -211,7 +251,10 @@ func StartNode(ctx *cli.Context, stack *node.Node, isConsole bool) {
 			ticker := time.NewTicker(1 * time.Second)
 			defer ticker.Stop()
 			
-			timeout := time.After(30 * time.Second)
+			var timeout <-chan time.Time
+			if DefaultConfig.NodeCfg.ShutdownTimeout > 0 {
+				timeout = time.After(DefaultConfig.NodeCfg.ShutdownTimeout)
+			}
 			elapsed := 0
 			
 			for {
@@ -223,13 +266,13 @@ func StartNode(ctx *cli.Context, stack *node.Node, isConsole bool) {
 					return
 					
 				case <-timeout:
-					log.Warn("Shutdown timeout (30s), forcing exit...")
+					log.Warn("Shutdown timed out, forcing exit...", "timeout", DefaultConfig.NodeCfg.ShutdownTimeout, "phase", stack.ShutdownPhase())
 					os.Exit(1)
 					
 				case <-ticker.C:
 					elapsed++
 					if elapsed%5 == 0 {
-						log.Info("Still shutting down...", "elapsed", fmt.Sprintf("%ds", elapsed))
+						log.Info("Still shutting down...", "elapsed", fmt.Sprintf("%ds", elapsed), "phase", stack.ShutdownPhase())
 					}
 					
 				case <-sigc:
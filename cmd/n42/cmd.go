@@ -17,6 +17,12 @@
 package main
 
 import (
+	"fmt"
+	"math/big"
+
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/internal/consensus"
+	"github.com/n42blockchain/N42/params"
 	"github.com/n42blockchain/N42/params/networkname"
 	"github.com/urfave/cli/v2"
 )
@@ -33,6 +39,10 @@ var (
 	p2pStaticPeers   = cli.NewStringSlice()
 	p2pBootstrapNode = cli.NewStringSlice()
 	p2pDenyList      = cli.NewStringSlice()
+
+	backfillIndexes = cli.NewStringSlice()
+
+	txPoolLocals = cli.NewStringSlice()
 )
 
 var rootCmd []*cli.Command
@@ -71,6 +81,47 @@ var nodeFlg = []cli.Flag{
 		Value:       "",
 		Destination: &DefaultConfig.NodeCfg.NodePrivate,
 	},
+	&cli.IntFlag{
+		Name:        "sync.verify.workers",
+		Usage:       "并行校验导入区块头的线程数 (0=CPU 核数)",
+		Category:    "NODE",
+		Destination: &DefaultConfig.NodeCfg.VerifyWorkers,
+	},
+	&cli.IntFlag{
+		Name:        "sync.verify.ahead",
+		Usage:       "区块头校验最多领先区块执行的区块数",
+		Category:    "NODE",
+		Value:       consensus.DefaultVerifyAhead,
+		Destination: &DefaultConfig.NodeCfg.VerifyAhead,
+	},
+	&cli.IntFlag{
+		Name:        "sync.commit-every",
+		Usage:       "同步时每个数据库事务提交的区块数，大于 1 时批量提交 (例如 100)",
+		Category:    "NODE",
+		Value:       DefaultConfig.NodeCfg.CommitEvery,
+		Destination: &DefaultConfig.NodeCfg.CommitEvery,
+	},
+	&cli.IntFlag{
+		Name:        "sync.commit-size",
+		Usage:       "批量提交时单个事务的状态变更上限 (MB，0=不限)",
+		Category:    "NODE",
+		Value:       DefaultConfig.NodeCfg.CommitSize,
+		Destination: &DefaultConfig.NodeCfg.CommitSize,
+	},
+	&cli.Uint64Flag{
+		Name:        "chain.max-reorg-depth",
+		Usage:       "允许自动执行的最大重组深度，更深的重组需通过 admin_allowReorg 确认 (0=不限)",
+		Category:    "NODE",
+		Value:       DefaultConfig.NodeCfg.MaxReorgDepth,
+		Destination: &DefaultConfig.NodeCfg.MaxReorgDepth,
+	},
+	&cli.DurationFlag{
+		Name:        "shutdown.timeout",
+		Usage:       "优雅关闭的最长等待时间，超时后强制退出 (0=一直等待)",
+		Category:    "NODE",
+		Value:       DefaultConfig.NodeCfg.ShutdownTimeout,
+		Destination: &DefaultConfig.NodeCfg.ShutdownTimeout,
+	},
 }
 
 var rpcFlags = []cli.Flag{
@@ -119,6 +170,34 @@ var rpcFlags = []cli.Flag{
 		Value:       "",
 		Destination: &DefaultConfig.NodeCfg.HTTPCors,
 	},
+	&cli.StringFlag{
+		Name:        "http.apikeys",
+		Usage:       "API Key 文件 (每行: <key> [名称])，设置后 HTTP-RPC 与 WebSocket 请求须通过 X-API-Key 头或 /<key> 路径携带 key",
+		Category:    "HTTP-RPC",
+		Value:       "",
+		Destination: &DefaultConfig.NodeCfg.HTTPAPIKeys,
+	},
+	&cli.Float64Flag{
+		Name:        "http.apikeys.rate",
+		Usage:       "每个 API Key 每秒允许的请求数 (0=不限制)",
+		Category:    "HTTP-RPC",
+		Value:       0,
+		Destination: &DefaultConfig.NodeCfg.HTTPAPIKeyRate,
+	},
+	&cli.Int64Flag{
+		Name:        "http.apikeys.burst",
+		Usage:       "每个 API Key 允许的突发请求数 (0=等于每秒请求数)",
+		Category:    "HTTP-RPC",
+		Value:       0,
+		Destination: &DefaultConfig.NodeCfg.HTTPAPIKeyBurst,
+	},
+	&cli.StringFlag{
+		Name:        "http.apikeys.admin",
+		Usage:       "管理员 API Key，携带该 key 访问 /apikeys/usage 可查看所有 key 的用量",
+		Category:    "HTTP-RPC",
+		Value:       "",
+		Destination: &DefaultConfig.NodeCfg.HTTPAPIAdminKey,
+	},
 
 	// WebSocket RPC 配置
 	&cli.BoolFlag{
@@ -173,6 +252,34 @@ var consensusFlag = []cli.Flag{
 		Value:       "",
 		Destination: &DefaultConfig.Miner.Etherbase,
 	},
+	&cli.StringFlag{
+		Name:        "miner.ordering",
+		Usage:       "打包区块时的交易排序策略 (price: 有效小费优先, fifo: 先到先打包)",
+		Category:    "MINER",
+		Value:       "price",
+		Destination: &DefaultConfig.Miner.Ordering,
+	},
+	&cli.DurationFlag{
+		Name:        "miner.recommit",
+		Usage:       "重建待出块区块以打包新到交易的时间间隔, 也是单次打包的最长时间 (最小 1s)",
+		Category:    "MINER",
+		Value:       DefaultConfig.Miner.Recommit,
+		Destination: &DefaultConfig.Miner.Recommit,
+	},
+	&cli.Uint64Flag{
+		Name:        "miner.gaslimit",
+		Usage:       "出块的目标 Gas 上限",
+		Category:    "MINER",
+		Value:       DefaultConfig.Miner.GasCeil,
+		Destination: &DefaultConfig.Miner.GasCeil,
+	},
+	&cli.StringFlag{
+		Name:        "miner.extradata",
+		Usage:       "写入区块 extra-data 的内容 (最多 32 字节)",
+		Category:    "MINER",
+		Value:       "",
+		Destination: &DefaultConfig.Miner.ExtraData,
+	},
 }
 
 var configFlag = []cli.Flag{
@@ -223,6 +330,180 @@ var pprofCfg = []cli.Flag{
 	},
 }
 
+var resourceFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:        "gc.percent",
+		Usage:       "GC 触发百分比 (GOGC，数值越小内存占用越低、CPU 开销越高)",
+		Category:    "RESOURCES",
+		Value:       100,
+		Destination: &DefaultConfig.ResourceCfg.GCPercent,
+	},
+	&cli.Uint64Flag{
+		Name:        "gc.memlimit",
+		Usage:       "Go 运行时软内存上限 (MiB，0=按 --gc.memlimit.ratio 计算)",
+		Category:    "RESOURCES",
+		Value:       0,
+		Destination: &DefaultConfig.ResourceCfg.MemoryLimit,
+	},
+	&cli.Float64Flag{
+		Name:        "gc.memlimit.ratio",
+		Usage:       "按总内存比例设置软内存上限 (0-1，0=不限制)",
+		Category:    "RESOURCES",
+		Value:       0,
+		Destination: &DefaultConfig.ResourceCfg.MemoryLimitRatio,
+	},
+	&cli.Uint64Flag{
+		Name:        "cache",
+		Usage:       "数据库缓存大小 (MiB，0=按总内存自动调整)",
+		Category:    "RESOURCES",
+		Value:       0,
+		Destination: &DefaultConfig.ResourceCfg.Cache,
+	},
+	&cli.Uint64Flag{
+		Name:        "cache.code",
+		Usage:       "执行与 RPC 共享的合约代码缓存大小 (MiB，0=禁用)",
+		Category:    "RESOURCES",
+		Value:       64,
+		Destination: &DefaultConfig.ResourceCfg.CodeCache,
+	},
+	&cli.IntFlag{
+		Name:        "cache.accounts",
+		Usage:       "执行与 RPC 共享的账户缓存条目数 (0=禁用)",
+		Category:    "RESOURCES",
+		Value:       100000,
+		Destination: &DefaultConfig.ResourceCfg.AccountCache,
+	},
+}
+
+var watchdogFlags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:        "watchdog.stall",
+		Usage:       "链头停止前进且对等节点更高多久后告警 (0=关闭看门狗)",
+		Category:    "WATCHDOG",
+		Value:       DefaultConfig.WatchdogCfg.StallTimeout,
+		Destination: &DefaultConfig.WatchdogCfg.StallTimeout,
+	},
+	&cli.StringFlag{
+		Name:        "watchdog.webhook",
+		Usage:       "链头停滞/恢复时 POST JSON 通知的 URL",
+		Category:    "WATCHDOG",
+		Value:       "",
+		Destination: &DefaultConfig.WatchdogCfg.Webhook,
+	},
+	&cli.BoolFlag{
+		Name:        "watchdog.resync",
+		Usage:       "检测到链头停滞时自动从最佳对等节点重新同步",
+		Category:    "WATCHDOG",
+		Value:       false,
+		Destination: &DefaultConfig.WatchdogCfg.Resync,
+	},
+}
+
+// overrideFlags move the activation of upcoming forks for testing on private
+// networks, without editing the genesis.
+var overrideFlags = func() []cli.Flag {
+	flags := make([]cli.Flag, 0, len(params.OverridableForks))
+	for _, fork := range params.OverridableForks {
+		name, usage := fork.Name, "覆盖 %s 分叉的激活区块号 (仅用于测试)"
+		if fork.Time {
+			usage = "覆盖 %s 分叉的激活时间戳 (仅用于测试)"
+		}
+		flags = append(flags, &cli.Uint64Flag{
+			Name:     "override." + name,
+			Usage:    fmt.Sprintf(usage, name),
+			Category: "NETWORK",
+			Action: func(ctx *cli.Context, v uint64) error {
+				if DefaultConfig.Overrides == nil {
+					DefaultConfig.Overrides = make(map[string]uint64)
+				}
+				DefaultConfig.Overrides[name] = v
+				return nil
+			},
+		})
+	}
+	return flags
+}()
+
+var txPoolFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:        "txpool.locals",
+		Usage:       "按本地交易处理的账户地址 (逗号分隔)",
+		Category:    "TRANSACTION POOL",
+		Value:       cli.NewStringSlice(),
+		Destination: txPoolLocals,
+	},
+	&cli.BoolFlag{
+		Name:        "txpool.nolocals",
+		Usage:       "不对 RPC 提交的本地交易做价格豁免",
+		Category:    "TRANSACTION POOL",
+		Value:       false,
+		Destination: &DefaultConfig.TxPoolCfg.NoLocals,
+	},
+	&cli.StringFlag{
+		Name:        "txpool.journal",
+		Usage:       "本地交易日志文件 (相对数据目录，留空关闭)",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.Journal,
+		Destination: &DefaultConfig.TxPoolCfg.Journal,
+	},
+	&cli.DurationFlag{
+		Name:        "txpool.rejournal",
+		Usage:       "重新生成本地交易日志的间隔",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.Rejournal,
+		Destination: &DefaultConfig.TxPoolCfg.Rejournal,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.pricelimit",
+		Usage:       "远程交易进入交易池的最低 gas 价格 (wei)",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.PriceLimit,
+		Destination: &DefaultConfig.TxPoolCfg.PriceLimit,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.pricebump",
+		Usage:       "替换同 nonce 交易所需的最低涨价百分比",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.PriceBump,
+		Destination: &DefaultConfig.TxPoolCfg.PriceBump,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.accountslots",
+		Usage:       "每个账户保证的可执行交易槽位数",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.AccountSlots,
+		Destination: &DefaultConfig.TxPoolCfg.AccountSlots,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.globalslots",
+		Usage:       "所有账户可执行交易槽位上限",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.GlobalSlots,
+		Destination: &DefaultConfig.TxPoolCfg.GlobalSlots,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.accountqueue",
+		Usage:       "每个账户排队 (不可执行) 交易上限",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.AccountQueue,
+		Destination: &DefaultConfig.TxPoolCfg.AccountQueue,
+	},
+	&cli.Uint64Flag{
+		Name:        "txpool.globalqueue",
+		Usage:       "所有账户排队 (不可执行) 交易上限",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.GlobalQueue,
+		Destination: &DefaultConfig.TxPoolCfg.GlobalQueue,
+	},
+	&cli.DurationFlag{
+		Name:        "txpool.lifetime",
+		Usage:       "不可执行交易在队列中保留的最长时间",
+		Category:    "TRANSACTION POOL",
+		Value:       DefaultConfig.TxPoolCfg.Lifetime,
+		Destination: &DefaultConfig.TxPoolCfg.Lifetime,
+	},
+}
+
 var loggerFlag = []cli.Flag{
 	&cli.StringFlag{
 		Name:        "log.level",
@@ -411,6 +692,14 @@ var (
 		Destination: &DefaultConfig.P2PCfg.MinSyncPeers,
 	}
 
+	// P2PCompression selects the compression preferred on block and body req/resp protocols.
+	P2PCompression = &cli.StringFlag{
+		Name:        "p2p.compression",
+		Usage:       "The compression preferred for block and body sync messages (zstd, snappy). Peers without zstd support fall back to snappy.",
+		Value:       "zstd",
+		Destination: &DefaultConfig.P2PCfg.Compression,
+	}
+
 	// P2PBlockBatchLimit specifies the requested block batch size.
 	P2PBlockBatchLimit = &cli.IntFlag{
 		Name:        "p2p.limit.block-batch",
@@ -453,6 +742,45 @@ var (
 		Destination: &DefaultConfig.NodeCfg.MinFreeDiskSpace,
 	}
 
+	BackfillFlag = &cli.StringSliceFlag{
+		Name:        "backfill",
+		Usage:       "为启用前已写入的区块在后台补建索引 (txlookup, addressbloom)，中断后可续建",
+		Category:    "DATA",
+		Value:       cli.NewStringSlice(),
+		Destination: backfillIndexes,
+	}
+
+	StateCommitmentFlag = &cli.BoolFlag{
+		Name:        "state.commitment",
+		Usage:       "在后台根据状态变更集增量维护每个区块的状态树根，可用 verify-state-root 校验",
+		Category:    "DATA",
+		Value:       false,
+		Destination: &DefaultConfig.NodeCfg.StateCommitment,
+	}
+
+	ReadOnlyFlag = &cli.BoolFlag{
+		Name:        "readonly",
+		Usage:       "以只读方式打开数据库，与同步节点共享数据目录，仅提供 RPC 服务",
+		Category:    "DATA",
+		Value:       false,
+		Destination: &DefaultConfig.NodeCfg.ReadOnly,
+	}
+
+	AncientDirFlag = &cli.StringFlag{
+		Name:        "ancient.dir",
+		Usage:       "冻结区块存储目录，相对路径基于数据目录 (默认 ancient)",
+		Category:    "DATA",
+		Destination: &DefaultConfig.DatabaseCfg.AncientDir,
+	}
+
+	FreezeThresholdFlag = &cli.Uint64Flag{
+		Name:        "ancient.threshold",
+		Usage:       "数据库中保留的最近区块数，更早的不可变区块移入冻结存储 (0 表示关闭)",
+		Category:    "DATA",
+		Value:       0,
+		Destination: &DefaultConfig.DatabaseCfg.FreezeThreshold,
+	}
+
 	FromDataDirFlag = &cli.StringFlag{
 		Name:     "chaindata.from",
 		Usage:    "源数据目录 (用于数据迁移)",
@@ -504,6 +832,36 @@ var (
 	}
 )
 
+var (
+	KVRPCFlag = &cli.BoolFlag{
+		Name:        "kv.rpc",
+		Usage:       "通过 gRPC 向远程 RPC 节点提供链数据库的只读访问，使用 --authrpc.jwtsecret 认证",
+		Category:    "KV-RPC",
+		Value:       false,
+		Destination: &DefaultConfig.NodeCfg.KVRPC,
+	}
+	KVRPCListenFlag = &cli.StringFlag{
+		Name:        "kv.addr",
+		Usage:       "数据库服务监听地址，连接不加密，请仅在内网开放",
+		Category:    "KV-RPC",
+		Value:       "127.0.0.1",
+		Destination: &DefaultConfig.NodeCfg.KVAddr,
+	}
+	KVRPCPortFlag = &cli.IntFlag{
+		Name:        "kv.port",
+		Usage:       "数据库服务监听端口",
+		Category:    "KV-RPC",
+		Value:       8548,
+		Destination: &DefaultConfig.NodeCfg.KVPort,
+	}
+	RemoteKVFlag = &cli.StringFlag{
+		Name:        "kv.remote",
+		Usage:       "远程数据库地址 (如 10.0.0.1:8548)，节点以只读方式从中提供 RPC，需要与服务节点相同的 --authrpc.jwtsecret",
+		Category:    "KV-RPC",
+		Destination: &DefaultConfig.NodeCfg.RemoteKV,
+	}
+)
+
 var (
 	// 账户设置
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -521,10 +879,11 @@ var (
 		Destination: &DefaultConfig.NodeCfg.PasswordFile,
 	}
 	LightKDFFlag = &cli.BoolFlag{
-		Name:     "lightkdf",
-		Aliases:  []string{"account.lightkdf"},
-		Usage:    "降低密钥派生的资源消耗 (牺牲安全性)",
-		Category: "ACCOUNT",
+		Name:        "lightkdf",
+		Aliases:     []string{"account.lightkdf"},
+		Usage:       "降低密钥派生的资源消耗 (牺牲安全性)",
+		Category:    "ACCOUNT",
+		Destination: &DefaultConfig.NodeCfg.UseLightweightKDF,
 	}
 	KeyStoreDirFlag = &cli.PathFlag{
 		Name:        "keystore",
@@ -542,6 +901,12 @@ var (
 		Value:       false,
 		Destination: &DefaultConfig.NodeCfg.InsecureUnlockAllowed,
 	}
+	USBFlag = &cli.BoolFlag{
+		Name:        "usb",
+		Usage:       "启用 USB 硬件钱包 (Ledger/Trezor) 的监测与签名",
+		Category:    "ACCOUNT",
+		Destination: &DefaultConfig.NodeCfg.USB,
+	}
 
 	// 指标收集设置
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -565,6 +930,127 @@ var (
 		Value:       6061,
 		Destination: &DefaultConfig.MetricsCfg.Port,
 	}
+	MetricsPushIntervalFlag = &cli.DurationFlag{
+		Name:        "metrics.push.interval",
+		Usage:       "推送指标到 InfluxDB 或 pushgateway 的间隔",
+		Category:    "METRICS",
+		Value:       DefaultConfig.MetricsCfg.PushInterval,
+		Destination: &DefaultConfig.MetricsCfg.PushInterval,
+	}
+	MetricsEnableInfluxDBFlag = &cli.BoolFlag{
+		Name:        "metrics.influxdb",
+		Usage:       "启用指标推送到 InfluxDB v1",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.EnableInfluxDB,
+	}
+	MetricsEnableInfluxDBV2Flag = &cli.BoolFlag{
+		Name:        "metrics.influxdbv2",
+		Usage:       "启用指标推送到 InfluxDB v2",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.EnableInfluxDBV2,
+	}
+	MetricsInfluxDBEndpointFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.endpoint",
+		Usage:       "InfluxDB API 地址",
+		Category:    "METRICS",
+		Value:       "http://localhost:8086",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBEndpoint,
+	}
+	MetricsInfluxDBDatabaseFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.database",
+		Usage:       "InfluxDB 数据库名 (v1)",
+		Category:    "METRICS",
+		Value:       "n42",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBDatabase,
+	}
+	MetricsInfluxDBUsernameFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.username",
+		Usage:       "InfluxDB 用户名 (v1)",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBUsername,
+	}
+	MetricsInfluxDBPasswordFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.password",
+		Usage:       "InfluxDB 密码 (v1)",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBPassword,
+	}
+	MetricsInfluxDBTokenFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.token",
+		Usage:       "InfluxDB 访问令牌 (v2)",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBToken,
+	}
+	MetricsInfluxDBBucketFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.bucket",
+		Usage:       "InfluxDB bucket 名称 (v2)",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBBucket,
+	}
+	MetricsInfluxDBOrganizationFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.organization",
+		Usage:       "InfluxDB 组织名称 (v2)",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBOrg,
+	}
+	MetricsInfluxDBTagsFlag = &cli.StringFlag{
+		Name:        "metrics.influxdb.tags",
+		Usage:       "附加到所有指标的标签，逗号分隔的 key=value",
+		Category:    "METRICS",
+		Value:       "host=localhost",
+		Destination: &DefaultConfig.MetricsCfg.InfluxDBTags,
+	}
+	MetricsPushGatewayFlag = &cli.StringFlag{
+		Name:        "metrics.pushgateway",
+		Usage:       "Prometheus pushgateway 地址，设置后定期推送指标",
+		Category:    "METRICS",
+		Destination: &DefaultConfig.MetricsCfg.PushGateway,
+	}
+	MetricsPushGatewayJobFlag = &cli.StringFlag{
+		Name:        "metrics.pushgateway.job",
+		Usage:       "推送到 pushgateway 时使用的 job 名称",
+		Category:    "METRICS",
+		Value:       "n42",
+		Destination: &DefaultConfig.MetricsCfg.PushGatewayJob,
+	}
+)
+
+var (
+	// Gas 价格预言机设置
+	GpoBlocksFlag = &cli.IntFlag{
+		Name:        "gpo.blocks",
+		Usage:       "Gas 价格预言机采样的最近区块数",
+		Category:    "GAS PRICE ORACLE",
+		Value:       conf.FullNodeGPO.Blocks,
+		Destination: &DefaultConfig.GPO.Blocks,
+	}
+	GpoPercentileFlag = &cli.IntFlag{
+		Name:        "gpo.percentile",
+		Usage:       "建议 Gas 价格取采样交易小费的百分位 (0-100)",
+		Category:    "GAS PRICE ORACLE",
+		Value:       conf.FullNodeGPO.Percentile,
+		Destination: &DefaultConfig.GPO.Percentile,
+	}
+	GpoMaxGasPriceFlag = &cli.Uint64Flag{
+		Name:     "gpo.maxprice",
+		Usage:    "建议 Gas 价格上限 (wei)",
+		Category: "GAS PRICE ORACLE",
+		Value:    conf.DefaultMaxPrice.Uint64(),
+		Action: func(ctx *cli.Context, v uint64) error {
+			DefaultConfig.GPO.MaxPrice = new(big.Int).SetUint64(v)
+			return nil
+		},
+	}
+	GpoIgnoreGasPriceFlag = &cli.Uint64Flag{
+		Name:     "gpo.ignoreprice",
+		Usage:    "采样时忽略低于此值的交易小费 (wei)",
+		Category: "GAS PRICE ORACLE",
+		Value:    conf.DefaultIgnorePrice.Uint64(),
+		Action: func(ctx *cli.Context, v uint64) error {
+			DefaultConfig.GPO.IgnorePrice = new(big.Int).SetUint64(v)
+			return nil
+		},
+	}
 )
 
 var (
@@ -574,10 +1060,21 @@ var (
 		AuthRPCPortFlag,
 		JWTSecretFlag,
 	}
+	kvRPCFlags = []cli.Flag{
+		KVRPCFlag,
+		KVRPCListenFlag,
+		KVRPCPortFlag,
+		RemoteKVFlag,
+	}
 	settingFlag = []cli.Flag{
 		DataDirFlag,
 		ChainFlag,
 		MinFreeDiskSpaceFlag,
+		BackfillFlag,
+		StateCommitmentFlag,
+		AncientDirFlag,
+		FreezeThresholdFlag,
+		ReadOnlyFlag,
 	}
 	accountFlag = []cli.Flag{
 		PasswordFileFlag,
@@ -585,12 +1082,33 @@ var (
 		LightKDFFlag,
 		InsecureUnlockAllowedFlag,
 		UnlockedAccountFlag,
+		USBFlag,
+	}
+
+	gpoFlags = []cli.Flag{
+		GpoBlocksFlag,
+		GpoPercentileFlag,
+		GpoMaxGasPriceFlag,
+		GpoIgnoreGasPriceFlag,
 	}
 
 	metricsFlags = []cli.Flag{
 		MetricsEnabledFlag,
 		MetricsHTTPFlag,
 		MetricsPortFlag,
+		MetricsPushIntervalFlag,
+		MetricsEnableInfluxDBFlag,
+		MetricsEnableInfluxDBV2Flag,
+		MetricsInfluxDBEndpointFlag,
+		MetricsInfluxDBDatabaseFlag,
+		MetricsInfluxDBUsernameFlag,
+		MetricsInfluxDBPasswordFlag,
+		MetricsInfluxDBTokenFlag,
+		MetricsInfluxDBBucketFlag,
+		MetricsInfluxDBOrganizationFlag,
+		MetricsInfluxDBTagsFlag,
+		MetricsPushGatewayFlag,
+		MetricsPushGatewayJobFlag,
 	}
 
 	p2pFlags = []cli.Flag{
@@ -610,6 +1128,7 @@ var (
 		P2PUDPPort,
 		P2PTCPPort,
 		P2PMinSyncPeers,
+		P2PCompression,
 	}
 
 	p2pLimitFlags = []cli.Flag{
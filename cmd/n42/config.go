@@ -52,6 +52,16 @@ var DefaultConfig = conf.Config{
 
 		// 网络
 		Chain: "mainnet",
+
+		// 优雅关闭最长等待时间
+		ShutdownTimeout: 30 * time.Second,
+
+		// 同步时每个数据库事务写入的区块
+		CommitEvery: 1,
+		CommitSize:  256,
+
+		// 超过该深度的重组需要运维人员确认
+		MaxReorgDepth: 64,
 	},
 
 	// 网络配置
@@ -83,6 +93,16 @@ var DefaultConfig = conf.Config{
 		Pprof:      false,
 	},
 
+	// 资源限制 - 默认不限制内存，缓存按内存自动调整
+	ResourceCfg: conf.ResourceConfig{
+		GCPercent:        100,
+		MemoryLimit:      0,
+		MemoryLimitRatio: 0,
+		Cache:            0,
+		CodeCache:        64,
+		AccountCache:     100000,
+	},
+
 	// 数据库配置
 	DatabaseCfg: conf.DatabaseConfig{
 		DBType:     "lmdb",
@@ -100,6 +120,31 @@ var DefaultConfig = conf.Config{
 		Enable: false,
 		Port:   DefaultMetricsPort,
 		HTTP:   "127.0.0.1",
+
+		PushInterval:     10 * time.Second,
+		InfluxDBEndpoint: "http://localhost:8086",
+		InfluxDBDatabase: "n42",
+		InfluxDBTags:     "host=localhost",
+		PushGatewayJob:   "n42",
+	},
+
+	// 链头看门狗 - 默认 5 分钟未前进且对等节点更高时告警，不自动重新同步
+	WatchdogCfg: conf.WatchdogConfig{
+		StallTimeout: 5 * time.Minute,
+		Resync:       false,
+	},
+
+	// 交易池 - 本地交易写入日志，重启后恢复
+	TxPoolCfg: conf.TxPoolConfig{
+		Journal:      "transactions.journal",
+		Rejournal:    time.Hour,
+		PriceLimit:   1,
+		PriceBump:    10,
+		AccountSlots: 16,
+		GlobalSlots:  4096 + 1024,
+		AccountQueue: 64,
+		GlobalQueue:  1024,
+		Lifetime:     3 * time.Hour,
 	},
 
 	// P2P 配置
@@ -110,6 +155,7 @@ var DefaultConfig = conf.Config{
 		MinSyncPeers: DefaultMinSyncPeers,
 		StaticPeerID: true,
 		NoDiscovery:  false,
+		Compression:  "zstd",
 		P2PLimit: &conf.P2PLimit{
 			BlockBatchLimit:            64,
 			BlockBatchLimitBurstFactor: 2,
@@ -125,6 +171,7 @@ var DefaultConfig = conf.Config{
 		GasCeil:  30000000,
 		GasPrice: big.NewInt(params.GWei),
 		Recommit: 4 * time.Second,
+		Ordering: "price",
 	},
 
 	// 开发配置
@@ -0,0 +1,415 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/node"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/ethdb/dbstat"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/turbo/backup"
+)
+
+var (
+	dbJSONFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as JSON",
+	}
+	dbKeepBackupFlag = &cli.BoolFlag{
+		Name:  "keep-backup",
+		Usage: "Keep the uncompacted database next to the compacted one",
+	}
+	dbDryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Only list the migrations without applying them",
+	}
+
+	dbCommand = &cli.Command{
+		Name:  "db",
+		Usage: "Inspect and maintain the chain database",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "stat",
+				Usage:  "Show database size, free-list usage and check free-list health",
+				Action: dbStat,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					dbJSONFlag,
+				},
+				Description: `
+Reports the data file geometry, the space held by tables and by the MDBX
+free-list, and verifies that every free-list record is well formed and points
+inside the allocated pages. Exits with an error when the check fails.`,
+			},
+			{
+				Name:   "inspect",
+				Usage:  "List every table with its key count and size",
+				Action: dbInspect,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					dbJSONFlag,
+				},
+			},
+			{
+				Name:   "compact",
+				Usage:  "Rewrite the database to reclaim free pages",
+				Action: dbCompact,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					dbKeepBackupFlag,
+				},
+				Description: `
+Copies every table into a fresh database and swaps it in place of the old one.
+The node must be stopped, and the datadir needs enough free space for a second
+copy of the live data.`,
+			},
+			{
+				Name:   "compress",
+				Usage:  "Compress block bodies and receipts written before compression was enabled",
+				Action: dbCompress,
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+Rewrites the values of the block transaction and receipt tables that are still
+stored uncompressed with zstd. New blocks are always written compressed, and
+both forms are read transparently, so the command can be interrupted and run
+again. The node must be stopped. Nodes do the same through the
+compress_chain_tables migration on startup. Run "db compact" afterwards to
+return the freed space to the file system.`,
+			},
+			{
+				Name:   "dedup-code",
+				Usage:  "Count the references to contract code and delete code nothing uses",
+				Action: dbDedupCode,
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+Contract code is stored once per code hash and shared by every contract
+running it, e.g. proxy clones. The node never deletes code by itself, since
+the account history may still point to code a contract no longer runs. The
+command recounts the contracts referencing each code and deletes the code
+that neither a contract, an account nor the account history references. The
+node must be stopped.`,
+			},
+			{
+				Name:   "migrate",
+				Usage:  "Show the schema version and apply pending database migrations",
+				Action: dbMigrate,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					dbDryRunFlag,
+				},
+				Description: `
+Lists the schema migrations with the time they were applied, then applies the
+pending ones in order. The node applies them on startup as well, the command
+allows running them ahead of an upgrade or checking what would change with
+--dry-run. The node must be stopped.`,
+			},
+		},
+	}
+)
+
+// lockedDB releases the datadir lock and the ancient store once the database
+// is closed.
+type lockedDB struct {
+	kv.RwDB
+	lock     *node.DatadirLock
+	ancients *rawdb.Freezer
+}
+
+func (db *lockedDB) Close() {
+	db.RwDB.Close()
+	if db.ancients != nil {
+		rawdb.SetAncients(nil)
+		db.ancients.Close()
+	}
+	db.lock.Unlock()
+}
+
+// openChainDB opens the chain database of the configured datadir for writing.
+// It fails when a node or another command is already using the datadir.
+func openChainDB() (kv.RwDB, error) {
+	lock, err := node.LockDatadir(DefaultConfig.NodeCfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	db, err := node.OpenDatabase(&DefaultConfig, nil, kv.ChainDB.String())
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	ancients, err := node.OpenAncients(&DefaultConfig)
+	if err != nil {
+		db.Close()
+		lock.Unlock()
+		return nil, err
+	}
+	return &lockedDB{RwDB: db, lock: lock, ancients: ancients}, nil
+}
+
+func collectDBStat(ctx *cli.Context) (*dbstat.Stat, error) {
+	db, err := dbstat.OpenReadonly(DefaultConfig.NodeCfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return dbstat.Collect(ctx.Context, db)
+}
+
+func dbStat(ctx *cli.Context) error {
+	stat, err := collectDBStat(ctx)
+	if err != nil {
+		return err
+	}
+	health := stat.Verify()
+
+	if ctx.Bool(dbJSONFlag.Name) {
+		out := struct {
+			*dbstat.Stat
+			Healthy bool   `json:"healthy"`
+			Problem string `json:"problem,omitempty"`
+		}{Stat: stat, Healthy: health == nil}
+		if health != nil {
+			out.Problem = health.Error()
+		}
+		if err := printJSON(out); err != nil {
+			return err
+		}
+		return health
+	}
+
+	fmt.Printf("Page size:      %s\n", types.StorageSize(stat.PageSize))
+	fmt.Printf("File size:      %s\n", types.StorageSize(stat.FileSize))
+	fmt.Printf("Allocated:      %s\n", types.StorageSize(stat.UsedSize))
+	fmt.Printf("Map size limit: %s\n", types.StorageSize(stat.MapSize))
+	fmt.Printf("Tables:         %d (%s)\n", len(stat.Tables), types.StorageSize(stat.TablesSize))
+	fmt.Printf("Free-list:      %d records, %d pages (%s), tree %s\n",
+		stat.FreeList.Records, stat.FreeList.FreePages, types.StorageSize(stat.FreeList.FreeSize), types.StorageSize(stat.FreeList.TreeSize))
+	fmt.Printf("Unallocated:    %s\n", types.StorageSize(stat.FileSize-min(stat.FileSize, stat.UsedSize)))
+	fmt.Printf("Readers:        %d\n", stat.Readers)
+	fmt.Printf("Last txn:       %d\n", stat.LastTxnID)
+	if health != nil {
+		fmt.Printf("Free-list:      UNHEALTHY\n")
+		return health
+	}
+	fmt.Printf("Free-list:      OK\n")
+	return nil
+}
+
+func dbInspect(ctx *cli.Context) error {
+	stat, err := collectDBStat(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(dbJSONFlag.Name) {
+		return printJSON(stat.Tables)
+	}
+
+	fmt.Printf("%-30s %12s %6s %10s %10s %10s %12s %7s\n",
+		"Table", "Entries", "Depth", "Branch", "Leaf", "Overflow", "Size", "Share")
+	for _, t := range stat.Tables {
+		var share float64
+		if stat.TablesSize > 0 {
+			share = float64(t.Size) * 100 / float64(stat.TablesSize)
+		}
+		fmt.Printf("%-30s %12d %6d %10d %10d %10d %12s %6.2f%%\n",
+			t.Name, t.Entries, t.Depth, t.BranchPages, t.LeafPages, t.OverflowPages, types.StorageSize(t.Size), share)
+	}
+	fmt.Printf("total %s in %d tables\n", types.StorageSize(stat.TablesSize), len(stat.Tables))
+	return nil
+}
+
+func dbCompact(ctx *cli.Context) error {
+	path := filepath.Join(DefaultConfig.NodeCfg.DataDir, kv.ChainDB.String())
+	if _, err := os.Stat(filepath.Join(path, "mdbx.dat")); err != nil {
+		return fmt.Errorf("no chain database at %s: %w", path, err)
+	}
+	lock, err := node.LockDatadir(DefaultConfig.NodeCfg.DataDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	compacted, old := path+".compact", path+".old"
+	for _, p := range []string{compacted, old} {
+		if _, err := os.Stat(p); err == nil {
+			return fmt.Errorf("%s already exists, remove it first", p)
+		}
+	}
+	before := fileSize(filepath.Join(path, "mdbx.dat"))
+
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	log.Info("Compacting chain database", "path", path, "size", types.StorageSize(before))
+	src, dst := backup.OpenPair(path, compacted, kv.ChainDB, 0)
+	err = backup.Kv2kv(ctx.Context, src, dst, nil, backup.ReadAheadThreads)
+	src.Close()
+	dst.Close()
+	if err != nil {
+		os.RemoveAll(compacted)
+		return err
+	}
+
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(compacted, path); err != nil {
+		// Put the original back so the datadir stays usable.
+		if rerr := os.Rename(old, path); rerr != nil {
+			return fmt.Errorf("%w (restoring %s failed: %v)", err, old, rerr)
+		}
+		return err
+	}
+	if !ctx.Bool(dbKeepBackupFlag.Name) {
+		if err := os.RemoveAll(old); err != nil {
+			return err
+		}
+	}
+
+	after := fileSize(filepath.Join(path, "mdbx.dat"))
+	log.Info("Chain database compacted", "before", types.StorageSize(before), "after", types.StorageSize(after),
+		"saved", types.StorageSize(before-min(before, after)))
+	return nil
+}
+
+func dbCompress(ctx *cli.Context) error {
+	db, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables := make([]string, 0, len(rawdb.CompressedTables))
+	for table := range rawdb.CompressedTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		log.Info("Compressing table", "table", table)
+		stats, err := rawdb.CompressTable(ctx.Context, db, table)
+		if err != nil {
+			return fmt.Errorf("compress %s: %w", table, err)
+		}
+		log.Info("Table compressed", "table", table, "entries", stats.Entries, "compressed", stats.Compressed,
+			"before", types.StorageSize(stats.Before), "after", types.StorageSize(stats.After))
+	}
+	log.Info("Run \"db compact\" to shrink the data file")
+	return nil
+}
+
+func dbDedupCode(ctx *cli.Context) error {
+	db, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := state.DedupCode(ctx.Context, db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Contracts:      %d\n", stats.Contracts)
+	fmt.Printf("Distinct codes: %d (%s)\n", stats.Codes, types.StorageSize(stats.Size))
+	fmt.Printf("Shared:         %s not stored thanks to deduplication\n", types.StorageSize(stats.Shared))
+	fmt.Printf("History:        %d codes kept for the account history\n", stats.History)
+	fmt.Printf("Deleted:        %d unreferenced codes (%s)\n", stats.Orphans, types.StorageSize(stats.Freed))
+	if stats.Orphans > 0 {
+		log.Info("Run \"db compact\" to shrink the data file")
+	}
+	return nil
+}
+
+func dbMigrate(ctx *cli.Context) error {
+	db, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var (
+		applied map[string]*rawdb.MigrationRecord
+		version uint64
+	)
+	if err := db.View(ctx.Context, func(tx kv.Tx) (err error) {
+		if applied, err = rawdb.ReadMigrations(tx); err != nil {
+			return err
+		}
+		version, err = rawdb.ReadSchemaVersion(tx)
+		return err
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("schema version %d\n", version)
+	for _, m := range rawdb.Migrations {
+		status := "pending"
+		if rec := applied[m.Name]; rec != nil {
+			status = "applied " + time.Unix(int64(rec.Applied), 0).Format(time.RFC3339)
+			if rec.Skipped {
+				status += " (new database)"
+			}
+		}
+		fmt.Printf("%-30s %-40s %s\n", m.Name, status, m.Description)
+	}
+
+	dryRun := ctx.Bool(dbDryRunFlag.Name)
+	pending, err := rawdb.RunMigrations(ctx.Context, db, rawdb.Migrations, dryRun)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(pending) == 0:
+		fmt.Println("database is up to date")
+	case dryRun:
+		fmt.Printf("%d migrations would be applied\n", len(pending))
+	default:
+		fmt.Printf("applied %d migrations\n", len(pending))
+	}
+	return nil
+}
+
+func fileSize(path string) uint64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return uint64(info.Size())
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
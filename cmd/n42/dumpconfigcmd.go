@@ -0,0 +1,60 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/conf"
+)
+
+var dumpConfigCommand = &cli.Command{
+	Name:      "dumpconfig",
+	Usage:     "Print the effective configuration as YAML",
+	ArgsUsage: "[<file>]",
+	Action:    dumpConfig,
+	Description: `
+Resolves the configuration exactly as the node does at startup, from the
+defaults, the command line options and the config file, and writes it to the
+given file or to stdout. Node options go before the command, for example
+
+    n42 --http --data.dir /data/n42 dumpconfig n42.yaml
+
+The result can be loaded back with --config. Loading rejects unknown keys.`,
+}
+
+func dumpConfig(ctx *cli.Context) error {
+	if err := resolveConfig(); err != nil {
+		return err
+	}
+
+	path := ctx.Args().First()
+	if path == "" {
+		return conf.WriteConfig(os.Stdout, &DefaultConfig)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := conf.WriteConfig(f, &DefaultConfig); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
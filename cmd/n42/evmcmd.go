@@ -0,0 +1,336 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/holiman/uint256"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/tracers/logger"
+	"github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/internal/vm/runtime"
+)
+
+var (
+	eofFileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "Read hex encoded containers from this file, one per line",
+	}
+
+	evmCodeFlag = &cli.StringFlag{
+		Name:  "code",
+		Usage: "Hex encoded EVM bytecode to run",
+	}
+	evmCodeFileFlag = &cli.StringFlag{
+		Name:  "codefile",
+		Usage: "File containing hex encoded EVM bytecode to run, '-' for stdin",
+	}
+	evmInputFlag = &cli.StringFlag{
+		Name:  "input",
+		Usage: "Hex encoded call data",
+	}
+	evmGasFlag = &cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "Gas limit of the call",
+		Value: 10000000,
+	}
+	evmValueFlag = &cli.StringFlag{
+		Name:  "value",
+		Usage: "Value sent with the call, in wei",
+		Value: "0",
+	}
+	evmCreateFlag = &cli.BoolFlag{
+		Name:  "create",
+		Usage: "Run the code as initcode of a contract creation",
+	}
+	evmForkFlag = &cli.StringFlag{
+		Name:  "fork",
+		Usage: "Fork whose rules apply, with every earlier fork active (" + strings.Join(runtime.Forks(), ", ") + ")",
+		Value: "Osaka",
+	}
+	evmTraceFlag = &cli.BoolFlag{
+		Name:  "trace",
+		Usage: "Print a struct log of the execution to stderr",
+	}
+	evmJSONFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the struct log as JSON",
+	}
+	evmStateTestFlag = &cli.StringFlag{
+		Name:  "statetest",
+		Usage: "Run the transactions of a state test fixture file instead of bytecode",
+	}
+
+	evmCommand = &cli.Command{
+		Name:  "evm",
+		Usage: "EVM developer tools",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "run",
+				Usage:  "Run EVM bytecode or a state test",
+				Action: runEVM,
+				Flags: []cli.Flag{
+					evmCodeFlag,
+					evmCodeFileFlag,
+					evmInputFlag,
+					evmGasFlag,
+					evmValueFlag,
+					evmCreateFlag,
+					evmForkFlag,
+					evmTraceFlag,
+					evmJSONFlag,
+					evmStateTestFlag,
+				},
+				Description: `
+Runs the given bytecode in an empty in-memory state under the rules of the
+chosen fork and prints the returned data, the gas used and the error, if any.
+
+With --statetest, the transaction of every post state of an Ethereum state
+test fixture is executed instead, checking that it is accepted or rejected
+as the fixture expects and that the post state root and logs hash match.`,
+			},
+			{
+				Name:      "validate-eof",
+				Usage:     "Validate EOF containers",
+				ArgsUsage: "[<hex> ...]",
+				Action:    validateEOF,
+				Flags:     []cli.Flag{eofFileFlag},
+				Description: `
+Checks that each hex encoded container is valid EOF code which could be
+deployed once the Osaka fork is active, and prints its sections or the
+reason it is rejected. Stack heights are not verified.`,
+			},
+		},
+	}
+)
+
+func validateEOF(ctx *cli.Context) error {
+	inputs := ctx.Args().Slice()
+	if path := ctx.String(eofFileFlag.Name); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if len(inputs) == 0 {
+		return errors.New("no containers given")
+	}
+
+	var invalid int
+	for i, input := range inputs {
+		code, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(input), "0x"))
+		if err == nil {
+			err = vm.ValidateEOF(code)
+		}
+		if err != nil {
+			invalid++
+			fmt.Printf("%d: invalid: %v\n", i, err)
+			continue
+		}
+		container, _ := vm.ParseEOF(code)
+		fmt.Printf("%d: OK, %d code sections, %d containers, %d bytes of data\n",
+			i, container.NumCodeSections(), container.NumContainers(), len(container.Data))
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d containers are invalid", invalid, len(inputs))
+	}
+	return nil
+}
+
+// evmTracer returns the vm config for the trace flags of ctx, and the struct
+// logger if tracing is enabled
+func evmTracer(ctx *cli.Context) (vm.Config, *logger.StructLogger) {
+	if !ctx.Bool(evmTraceFlag.Name) {
+		return vm.Config{}, nil
+	}
+	tracer := logger.NewStructLogger(&logger.Config{EnableMemory: true, EnableReturnData: true})
+	return vm.Config{Debug: true, Tracer: tracer}, tracer
+}
+
+// printTrace writes the struct log collected by tracer to stderr
+func printTrace(ctx *cli.Context, tracer *logger.StructLogger) error {
+	if tracer == nil {
+		return nil
+	}
+	if ctx.Bool(evmJSONFlag.Name) {
+		result, err := tracer.GetResult()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, string(result))
+		return nil
+	}
+	logger.WriteTrace(os.Stderr, tracer.StructLogs())
+	return nil
+}
+
+func readEVMCode(ctx *cli.Context) ([]byte, error) {
+	hexcode := ctx.String(evmCodeFlag.Name)
+	switch path := ctx.String(evmCodeFileFlag.Name); {
+	case path == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		hexcode = string(data)
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		hexcode = string(data)
+	}
+	if hexcode == "" {
+		return nil, errors.New("no code given, use --code, --codefile or --statetest")
+	}
+	return hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(hexcode), "0x"))
+}
+
+func runEVM(ctx *cli.Context) error {
+	if path := ctx.String(evmStateTestFlag.Name); path != "" {
+		return runStateTests(ctx, path)
+	}
+
+	code, err := readEVMCode(ctx)
+	if err != nil {
+		return fmt.Errorf("invalid code: %w", err)
+	}
+	input, err := hex.DecodeString(strings.TrimPrefix(ctx.String(evmInputFlag.Name), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid input: %w", err)
+	}
+	value, err := uint256.FromDecimal(ctx.String(evmValueFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	config, err := runtime.ForkConfig(ctx.String(evmForkFlag.Name))
+	if err != nil {
+		return err
+	}
+	ibs, release, err := runtime.NewState()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	vmConfig, tracer := evmTracer(ctx)
+	cfg := &runtime.Config{
+		ChainConfig: config,
+		Origin:      types.HexToAddress("0x1000000000000000000000000000000000000000"),
+		GasLimit:    ctx.Uint64(evmGasFlag.Name),
+		Value:       value,
+		Time:        new(big.Int),
+		EVMConfig:   vmConfig,
+		State:       ibs,
+	}
+	// The sender can always afford the value
+	ibs.SetBalance(cfg.Origin, value)
+
+	var (
+		ret         []byte
+		leftOverGas uint64
+	)
+	if tracer != nil {
+		tracer.CaptureTxStart(cfg.GasLimit)
+	}
+	if ctx.Bool(evmCreateFlag.Name) {
+		ret, _, leftOverGas, err = runtime.Create(code, cfg, 0)
+	} else {
+		receiver := types.HexToAddress("0x2000000000000000000000000000000000000000")
+		ibs.SetCode(receiver, code)
+		ret, leftOverGas, err = runtime.Call(receiver, input, cfg)
+	}
+	if tracer != nil {
+		tracer.CaptureTxEnd(leftOverGas)
+	}
+	if err := printTrace(ctx, tracer); err != nil {
+		return err
+	}
+
+	fmt.Printf("0x%x\n", ret)
+	fmt.Fprintf(os.Stderr, "gas used: %d\n", cfg.GasLimit-leftOverGas)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	}
+	return nil
+}
+
+func runStateTests(ctx *cli.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var tests map[string]*runtime.StateTest
+	if err := json.Unmarshal(data, &tests); err != nil {
+		return fmt.Errorf("invalid state test fixture: %w", err)
+	}
+
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var run, failed int
+	for _, name := range names {
+		test := tests[name]
+		for _, subtest := range test.Subtests() {
+			vmConfig, tracer := evmTracer(ctx)
+			result, err := test.Run(subtest, vmConfig)
+			if err := printTrace(ctx, tracer); err != nil {
+				return err
+			}
+			run++
+			switch {
+			case err != nil:
+				failed++
+				fmt.Printf("%s %s/%d: FAIL: %v\n", name, subtest.Fork, subtest.Index, err)
+			case result == nil:
+				fmt.Printf("%s %s/%d: OK, rejected\n", name, subtest.Fork, subtest.Index)
+			default:
+				fmt.Printf("%s %s/%d: OK, gas used %d, error %v\n", name, subtest.Fork, subtest.Index, result.UsedGas, result.Err)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subtests failed", failed, run)
+	}
+	return nil
+}
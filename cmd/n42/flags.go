@@ -77,17 +77,21 @@ var QuickStartFlags = []cli.Flag{
 	},
 	&cli.BoolFlag{
 		Name:     "dev",
-		Usage:    "启动开发者模式 (本地单节点，无需同步)",
+		Usage:    "启动开发者模式 (本地单节点，预置资金账户，即时出块，开放全部 API)",
 		Category: "QUICK START",
 		Action: func(ctx *cli.Context, b bool) error {
 			if b {
-				DefaultConfig.NodeCfg.Chain = "private"
-				DefaultConfig.P2PCfg.NoDiscovery = true
-				DefaultConfig.P2PCfg.MaxPeers = 0
+				applyDevMode(ctx)
 			}
 			return nil
 		},
 	},
+	&cli.DurationFlag{
+		Name:        "dev.period",
+		Usage:       "开发者模式出块间隔 (0 表示收到交易即出块)",
+		Category:    "QUICK START",
+		Destination: &DefaultConfig.DevCfg.Period,
+	},
 
 	// 快速端口设置
 	&cli.IntFlag{
@@ -139,6 +143,34 @@ var QuickStartFlags = []cli.Flag{
 	},
 }
 
+// devAPIs 是开发者模式默认开放的 API 模块
+const devAPIs = "eth,web3,net,debug,txpool,admin,personal,staking,evm"
+
+// applyDevMode 配置开发者链：单节点、自动挖矿、开放全部 API，
+// 命令行显式设置的 RPC 参数优先。
+func applyDevMode(ctx *cli.Context) {
+	DefaultConfig.DevCfg.Enabled = true
+	DefaultConfig.NodeCfg.Chain = "private"
+	DefaultConfig.NodeCfg.Miner = true
+	DefaultConfig.NodeCfg.UseLightweightKDF = true
+	DefaultConfig.NodeCfg.InsecureUnlockAllowed = true
+	DefaultConfig.P2PCfg.NoDiscovery = true
+	DefaultConfig.P2PCfg.MaxPeers = 0
+
+	if !ctx.IsSet("http") {
+		DefaultConfig.NodeCfg.HTTP = true
+	}
+	if !ctx.IsSet("http.api") {
+		DefaultConfig.NodeCfg.HTTPApi = devAPIs
+	}
+	if !ctx.IsSet("http.corsdomain") {
+		DefaultConfig.NodeCfg.HTTPCors = "*"
+	}
+	if !ctx.IsSet("ws.api") {
+		DefaultConfig.NodeCfg.WSApi = devAPIs
+	}
+}
+
 // AllFlags 返回所有命令行参数，按分类排序
 // 优先级：QuickStartFlags > cmd.go 中的详细参数
 func AllFlags() []cli.Flag {
@@ -152,13 +184,19 @@ func AllFlags() []cli.Flag {
 	flags = append(flags, settingFlag...)
 	flags = append(flags, rpcFlags...)
 	flags = append(flags, authRPCFlag...)
+	flags = append(flags, kvRPCFlags...)
 	flags = append(flags, consensusFlag...)
 	flags = append(flags, loggerFlag...)
 	flags = append(flags, pprofCfg...)
+	flags = append(flags, resourceFlags...)
 	flags = append(flags, nodeFlg...)
 	flags = append(flags, configFlag...)
 	flags = append(flags, accountFlag...)
 	flags = append(flags, metricsFlags...)
+	flags = append(flags, watchdogFlags...)
+	flags = append(flags, txPoolFlags...)
+	flags = append(flags, overrideFlags...)
+	flags = append(flags, gpoFlags...)
 	flags = append(flags, p2pFlags...)
 	flags = append(flags, p2pLimitFlags...)
 	flags = append(flags, devFlags...)
@@ -3,34 +3,55 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/n42blockchain/N42/cmd/utils"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/internal"
 	"github.com/n42blockchain/N42/internal/node"
+	"github.com/n42blockchain/N42/internal/vm"
 	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
 	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/params"
 	"github.com/urfave/cli/v2"
+	"math/big"
 	"os"
 )
 
 var (
+	initGenesisFlag = &cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Path to the JSON genesis spec",
+	}
+	initDryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Validate the genesis spec and print its hash without touching the datadir",
+	}
+
 	initCommand = &cli.Command{
 		Name:      "init",
 		Usage:     "Bootstrap and initialize a new genesis block",
-		ArgsUsage: "<genesisPath>",
+		ArgsUsage: "[<genesisPath>]",
 		Action:    initGenesis,
 		Flags: []cli.Flag{
 			DataDirFlag,
+			initGenesisFlag,
+			initDryRunFlag,
 		},
 		Description: `
 The init command initializes a new genesis block and definition for the network.
 This is a destructive action and changes the network in which you will be
 participating.
 
-It expects the genesis file as argument.`,
+It expects the genesis file via --genesis or as argument. The spec is validated
+(chain config, fork ordering and allocations) and the resulting genesis hash and
+state root are printed. A datadir that already holds the same genesis is left
+untouched, one holding a different genesis is refused.`,
 	}
 )
 
@@ -43,7 +64,10 @@ func initGenesis(cliCtx *cli.Context) error {
 	)
 
 	// Make sure we have a valid genesis JSON
-	genesisPath := cliCtx.Args().First()
+	genesisPath := cliCtx.String(initGenesisFlag.Name)
+	if len(genesisPath) == 0 {
+		genesisPath = cliCtx.Args().First()
+	}
 	if len(genesisPath) == 0 {
 		utils.Fatalf("Must supply path to genesis JSON file")
 	}
@@ -58,10 +82,94 @@ func initGenesis(cliCtx *cli.Context) error {
 	if err := json.NewDecoder(file).Decode(genesis); err != nil {
 		utils.Fatalf("invalid genesis file: %v", err)
 	}
+	if err := validateGenesis(genesis); err != nil {
+		utils.Fatalf("invalid genesis file: %v", err)
+	}
+
+	if cliCtx.Bool(initDryRunFlag.Name) {
+		genesisBlock, err = genesisToBlock(genesis)
+		if err != nil {
+			utils.Fatalf("Failed to compute genesis block: %v", err)
+		}
+	} else {
+		genesisBlock, err = writeGenesis(genesis)
+		if err != nil {
+			utils.Fatalf("Failed to wrote genesis state to database: %v", err)
+		}
+		log.Info("Successfully wrote genesis state", "hash", genesisBlock.Hash())
+	}
+	fmt.Printf("Genesis hash: %s\n", genesisBlock.Hash())
+	fmt.Printf("State root:   %s\n", genesisBlock.StateRoot())
+	return nil
+}
 
-	chaindb, err := node.OpenDatabase(&DefaultConfig, nil, kv.ChainDB.String())
+// validateGenesis rejects specs which would fail or panic while the genesis
+// block is being built.
+func validateGenesis(genesis *conf.Genesis) error {
+	cfg := genesis.Config
+	if cfg == nil {
+		return errors.New("missing chain config")
+	}
+	if cfg.ChainID == nil || cfg.ChainID.Sign() <= 0 {
+		return errors.New("chain config has no valid chainId")
+	}
+	switch cfg.Consensus {
+	case params.CliqueConsensus:
+		if cfg.Clique == nil {
+			return errors.New("clique consensus requires a clique config")
+		}
+	case params.AposConsensu:
+		if cfg.Apos == nil {
+			return errors.New("apos consensus requires an apos config")
+		}
+	case params.Faker:
+	default:
+		return fmt.Errorf("unsupported consensus engine %q", cfg.Consensus)
+	}
+	if err := cfg.CheckConfigForkOrder(); err != nil {
+		return err
+	}
+	if err := vm.ValidateCustomPrecompiles(cfg); err != nil {
+		return err
+	}
+	for _, miner := range genesis.Miners {
+		if _, err := types.HexToString(miner); err != nil {
+			return fmt.Errorf("invalid miner %s: %w", miner, err)
+		}
+	}
+	for addr, account := range genesis.Alloc {
+		b, ok := new(big.Int).SetString(account.Balance, 10)
+		if !ok || b.Sign() < 0 {
+			return fmt.Errorf("invalid balance %q for %s", account.Balance, addr)
+		}
+		if _, overflow := uint256.FromBig(b); overflow {
+			return fmt.Errorf("balance of %s overflows uint256", addr)
+		}
+	}
+	return nil
+}
+
+// genesisToBlock builds the genesis block without writing it anywhere.
+func genesisToBlock(genesis *conf.Genesis) (*block.Block, error) {
+	// The state root is computed in a scratch database using the chain tables.
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	genesisBlock, _, err := (&internal.GenesisBlock{GenesisConfig: genesis}).ToBlock()
+	return genesisBlock, err
+}
+
+// writeGenesis commits the genesis block and chain config to a fresh datadir.
+// A datadir that already holds the same genesis is left as is.
+func writeGenesis(genesis *conf.Genesis) (*block.Block, error) {
+	genesisBlock, err := genesisToBlock(genesis)
 	if err != nil {
-		utils.Fatalf("Failed to open database: %v", err)
+		return nil, err
+	}
+
+	chaindb, err := openChainDB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 	defer chaindb.Close()
 
@@ -72,20 +180,25 @@ func initGenesis(cliCtx *cli.Context) error {
 		}
 
 		if storedHash != (types.Hash{}) {
-			return fmt.Errorf("genesis state are already exists")
-
+			if storedHash != genesisBlock.Hash() {
+				return fmt.Errorf("datadir holds a different genesis: have %s, want %s", storedHash, genesisBlock.Hash())
+			}
+			log.Info("Genesis already initialized", "hash", storedHash)
+			return nil
 		}
-		genesisBlock, err = node.WriteGenesisBlock(tx, genesis)
+		written, err := node.WriteGenesisBlock(tx, genesis)
 		if nil != err {
 			return err
 		}
+		if written.Hash() != genesisBlock.Hash() {
+			return fmt.Errorf("genesis hash mismatch: computed %s, wrote %s", genesisBlock.Hash(), written.Hash())
+		}
 		if err := node.WriteChainConfig(tx, genesisBlock.Hash(), genesis); err != nil {
 			return err
 		}
 		return nil
 	}); err != nil {
-		utils.Fatalf("Failed to wrote genesis state to database: %w", err)
+		return nil, err
 	}
-	log.Info("Successfully wrote genesis state", "hash", genesisBlock.Hash())
-	return nil
+	return genesisBlock, nil
 }
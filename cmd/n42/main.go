@@ -57,12 +57,12 @@ const usageText = `n42 [options] [command]
   n42 init --help                 初始化命令`
 
 func main() {
-	fmt.Print(banner)
+	fmt.Fprint(os.Stderr, banner)
 
 	// 使用新的参数结构（已整合所有旧参数）
 	flags := AllFlags()
 
-	rootCmd = append(rootCmd, walletCommand, accountCommand, exportCommand, initCommand)
+	rootCmd = append(rootCmd, walletCommand, accountCommand, exportCommand, initCommand, snapshotCommand, dbCommand, rewardsCommand, evmCommand, reexecCommand, rollbackCommand, verifyStateRootCommand, verkleWitnessCommand, dumpConfigCommand)
 	commands := rootCmd
 
 	app := &cli.App{
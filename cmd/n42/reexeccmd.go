@@ -0,0 +1,214 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/node"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+var (
+	reexecFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to re-execute",
+		Value: 1,
+	}
+	reexecToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block to re-execute (0 = current head)",
+	}
+
+	reexecCommand = &cli.Command{
+		Name:   "reexec",
+		Usage:  "Re-execute a block range from the local database and check the results",
+		Action: reexec,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			reexecFromFlag,
+			reexecToFlag,
+		},
+		Description: `
+Replays every block of the range on top of the historical state of its parent,
+without networking, and compares the recomputed gas used, receipts, receipt
+root and state root against the stored ones. Stops at the first divergence.
+The node must be stopped.`,
+	}
+)
+
+func reexec(ctx *cli.Context) error {
+	chaindb, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	tx, err := chaindb.BeginRo(ctx.Context)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from, to := ctx.Uint64(reexecFromFlag.Name), ctx.Uint64(reexecToFlag.Name)
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		head := rawdb.ReadCurrentBlock(tx)
+		if head == nil {
+			return fmt.Errorf("chain database has no head block")
+		}
+		to = head.Number64().Uint64()
+	}
+	if from > to {
+		return fmt.Errorf("empty block range %d-%d", from, to)
+	}
+
+	processor, closeProcessor, err := newOfflineProcessor(ctx, chaindb, tx)
+	if err != nil {
+		return err
+	}
+	defer closeProcessor()
+
+	log.Info("Re-executing blocks", "from", from, "to", to)
+	var (
+		start    = time.Now()
+		reported = time.Now()
+		txs      int
+	)
+	for n := from; n <= to; n++ {
+		if err := ctx.Context.Err(); err != nil {
+			return err
+		}
+		blk, err := rawdb.ReadBlockByNumber(tx, n)
+		if blk == nil {
+			return fmt.Errorf("block %d is missing: %v", n, err)
+		}
+		if err := reexecBlock(tx, processor, blk); err != nil {
+			log.Error("Block diverges", "number", n, "hash", blk.Hash(), "err", err)
+			return fmt.Errorf("first divergence at block %d (%s): %w", n, blk.Hash(), err)
+		}
+		txs += len(blk.Transactions())
+		if time.Since(reported) > 8*time.Second {
+			log.Info("Re-executing blocks", "number", n, "txs", txs, "elapsed", time.Since(start))
+			reported = time.Now()
+		}
+	}
+	log.Info("Re-execution matches stored chain", "blocks", to-from+1, "txs", txs, "elapsed", time.Since(start))
+	return nil
+}
+
+// newOfflineProcessor sets up a state processor for the chain in chaindb,
+// without networking. The returned function releases it.
+func newOfflineProcessor(ctx *cli.Context, chaindb kv.RwDB, tx kv.Tx) (*internal.StateProcessor, func(), error) {
+	genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+	if err != nil || genesisHash == (types.Hash{}) {
+		return nil, nil, fmt.Errorf("chain database has no genesis block")
+	}
+	chainConfig, err := rawdb.ReadChainConfig(tx, genesisHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	genesisBlock, err := rawdb.ReadBlockByHash(tx, genesisHash)
+	if genesisBlock == nil {
+		return nil, nil, fmt.Errorf("genesis block is missing: %v", err)
+	}
+
+	engine, err := node.CreateConsensusEngine(chainConfig, chaindb)
+	if err != nil {
+		return nil, nil, err
+	}
+	bc, err := internal.NewBlockChain(ctx.Context, genesisBlock, engine, chaindb, nil, chainConfig)
+	if err != nil {
+		engine.Close()
+		return nil, nil, err
+	}
+	processor := internal.NewStateProcessor(chainConfig, bc.(*internal.BlockChain), engine)
+	return processor, func() {
+		bc.Close()
+		engine.Close()
+	}, nil
+}
+
+// reexecBlock executes blk on the state at the beginning of the block and
+// compares the outcome with the stored header and receipts.
+func reexecBlock(tx kv.Tx, processor *internal.StateProcessor, blk *block.Block) error {
+	header := blk.Header().(*block.Header)
+	number := header.Number64().Uint64()
+
+	reader := state.NewPlainState(tx, number)
+	ibs := state.New(reader)
+	getHeader := func(hash types.Hash, number uint64) *block.Header {
+		return rawdb.ReadHeader(tx, hash, number)
+	}
+	receipts, _, _, usedGas, err := processor.Process(blk, ibs, reader, state.NewNoopWriter(), internal.GetHashFn(header, getHeader))
+	if err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	if usedGas != header.GasUsed {
+		return fmt.Errorf("gas used mismatch (stored: %d local: %d)", header.GasUsed, usedGas)
+	}
+	if stored := rawdb.ReadRawReceipts(tx, number); stored != nil {
+		if err := compareReceipts(stored, receipts); err != nil {
+			return err
+		}
+	}
+	if root := internal.DeriveSha(receipts); root != header.ReceiptHash {
+		return fmt.Errorf("receipt root mismatch (stored: %x local: %x)", header.ReceiptHash, root)
+	}
+	if root := ibs.IntermediateRoot(); root != header.StateRoot() {
+		return fmt.Errorf("state root mismatch (stored: %x local: %x)", header.StateRoot(), root)
+	}
+	return nil
+}
+
+// compareReceipts reports the first consensus field that differs between the
+// stored and the recomputed receipts.
+func compareReceipts(stored, local block.Receipts) error {
+	if len(stored) != len(local) {
+		return fmt.Errorf("receipt count mismatch (stored: %d local: %d)", len(stored), len(local))
+	}
+	for i, want := range stored {
+		have := local[i]
+		switch {
+		case want.Status != have.Status:
+			return fmt.Errorf("receipt %d status mismatch (stored: %d local: %d)", i, want.Status, have.Status)
+		case want.CumulativeGasUsed != have.CumulativeGasUsed:
+			return fmt.Errorf("receipt %d cumulative gas mismatch (stored: %d local: %d)", i, want.CumulativeGasUsed, have.CumulativeGasUsed)
+		case want.GasUsed != have.GasUsed:
+			return fmt.Errorf("receipt %d gas used mismatch (stored: %d local: %d)", i, want.GasUsed, have.GasUsed)
+		case len(want.Logs) != len(have.Logs):
+			return fmt.Errorf("receipt %d log count mismatch (stored: %d local: %d)", i, len(want.Logs), len(have.Logs))
+		case want.Bloom != have.Bloom:
+			return fmt.Errorf("receipt %d bloom mismatch", i)
+		case want.ContractAddress != have.ContractAddress:
+			return fmt.Errorf("receipt %d contract address mismatch (stored: %s local: %s)", i, want.ContractAddress, have.ContractAddress)
+		}
+	}
+	return nil
+}
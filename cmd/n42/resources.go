@@ -0,0 +1,112 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/pbnjay/memory"
+
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+const (
+	// Automatic cache sizing takes a quarter of the available memory,
+	// bounded so small hosts still sync and large hosts leave room for others.
+	autoCacheDivisor = 4
+	minAutoCacheMiB  = 256
+	maxAutoCacheMiB  = 4096
+
+	mib = 1024 * 1024
+)
+
+// applyResourceLimits configures the Go runtime garbage collector and resolves
+// the database cache size, and sizes the shared state cache. It must run
+// before the node is created.
+func applyResourceLimits(cfg *conf.ResourceConfig) {
+	total := totalMemory()
+
+	if cfg.GCPercent != 100 {
+		debug.SetGCPercent(cfg.GCPercent)
+	}
+
+	limit := cfg.MemoryLimit * mib
+	if limit == 0 && cfg.MemoryLimitRatio > 0 && total > 0 {
+		ratio := cfg.MemoryLimitRatio
+		if ratio > 1 {
+			log.Warn("Memory limit ratio above 1, clamping", "ratio", ratio)
+			ratio = 1
+		}
+		limit = uint64(float64(total) * ratio)
+	}
+	if limit > 0 {
+		debug.SetMemoryLimit(int64(limit))
+	}
+
+	if cfg.Cache == 0 {
+		cfg.Cache = autoCacheSize(total, limit)
+	}
+	state.SetCacheSizes(cfg.CodeCache*mib, cfg.AccountCache)
+
+	log.Info("Resource limits configured",
+		"total_mem_mib", total/mib,
+		"gc_percent", cfg.GCPercent,
+		"mem_limit_mib", limit/mib,
+		"cache_mib", cfg.Cache,
+		"code_cache_mib", cfg.CodeCache,
+		"account_cache", cfg.AccountCache)
+}
+
+// autoCacheSize derives the database cache in MiB from the memory available
+// to the process. When a soft memory limit is set the cache never takes more
+// than half of it.
+func autoCacheSize(total, limit uint64) uint64 {
+	cache := total / mib / autoCacheDivisor
+	if limit > 0 && cache > limit/mib/2 {
+		cache = limit / mib / 2
+	}
+	if cache < minAutoCacheMiB {
+		cache = minAutoCacheMiB
+	}
+	if cache > maxAutoCacheMiB {
+		cache = maxAutoCacheMiB
+	}
+	return cache
+}
+
+// totalMemory returns the memory available to the process in bytes, taking a
+// cgroup v2 limit into account when running inside a container.
+func totalMemory() uint64 {
+	total := memory.TotalMemory()
+	data, err := os.ReadFile("/sys/fs/cgroup/memory.max")
+	if err != nil {
+		return total
+	}
+	v := strings.TrimSpace(string(data))
+	if v == "max" {
+		return total
+	}
+	if limit, err := strconv.ParseUint(v, 10, 64); err == nil && limit > 0 && (total == 0 || limit < total) {
+		return limit
+	}
+	return total
+}
@@ -0,0 +1,117 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/consensus/apos"
+	"github.com/n42blockchain/N42/internal/node"
+)
+
+var (
+	rewardsFromEpochFlag = &cli.Uint64Flag{
+		Name:  "from-epoch",
+		Usage: "First reward epoch of the report",
+	}
+	rewardsToEpochFlag = &cli.Uint64Flag{
+		Name:        "to-epoch",
+		Usage:       "Last reward epoch of the report (default: latest)",
+		Value:       math.MaxUint64,
+		DefaultText: "latest",
+	}
+	rewardsAddressFlag = &cli.StringSliceFlag{
+		Name:  "address",
+		Usage: "Only report the rewards of these accounts",
+	}
+	rewardsOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Write the CSV to this file instead of stdout",
+	}
+
+	rewardsCommand = &cli.Command{
+		Name:  "rewards",
+		Usage: "Report the rewards paid to verifiers",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Export the rewards of a range of epochs as CSV",
+				Action: exportRewards,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					rewardsFromEpochFlag,
+					rewardsToEpochFlag,
+					rewardsAddressFlag,
+					rewardsOutputFlag,
+				},
+				Description: `
+Lists every reward paid by the epoch blocks of the range, one "paid" row per
+account and epoch, followed by one "unpaid" row per account with the reward
+accumulated at the head of the chain that has not reached the payout limit.
+Amounts are given in the smallest unit and in N42.`,
+			},
+		},
+	}
+)
+
+func exportRewards(ctx *cli.Context) error {
+	var addrs []types.Address
+	for _, hex := range ctx.StringSlice(rewardsAddressFlag.Name) {
+		if !types.IsHexAddress(hex) {
+			return fmt.Errorf("invalid address %q", hex)
+		}
+		addrs = append(addrs, types.HexToAddress(hex))
+	}
+
+	stack, err := node.NewNode(ctx, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	var report *apos.RewardReport
+	err = stack.Database().View(ctx.Context, func(tx kv.Tx) (err error) {
+		report, err = apos.NewRewardReport(stack.BlockChain(), tx, ctx.Uint64(rewardsFromEpochFlag.Name), ctx.Uint64(rewardsToEpochFlag.Name), addrs)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if path := ctx.String(rewardsOutputFlag.Name); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := report.WriteCSV(out); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "epochs %d-%d: %d rewards to %d accounts, total %s\n",
+		report.FromEpoch, report.ToEpoch, len(report.Entries), len(report.Accounts), report.Total.Dec())
+	return nil
+}
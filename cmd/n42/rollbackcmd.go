@@ -0,0 +1,68 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/log"
+)
+
+var (
+	rollbackToFlag = &cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "Block number that becomes the new head",
+		Required: true,
+	}
+
+	rollbackCommand = &cli.Command{
+		Name:   "rollback",
+		Usage:  "Roll the chain back to a given block",
+		Action: rollback,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			rollbackToFlag,
+		},
+		Description: `
+Unwinds the state to the end of the given block and deletes every later block
+with its receipts, transaction lookups, address blooms, state roots, change
+sets and history, so the node re-imports them on the next start. Frozen blocks
+can not be rolled back. Account rewards and deposits are not versioned and keep
+their current values. The node must be stopped.`,
+	}
+)
+
+func rollback(ctx *cli.Context) error {
+	chaindb, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	var head *block.Block
+	if err := chaindb.Update(ctx.Context, func(tx kv.RwTx) (err error) {
+		head, err = internal.UnwindChain(ctx.Context, tx, ctx.Uint64(rollbackToFlag.Name))
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Info("Chain rolled back", "number", head.Number64(), "hash", head.Hash(), "root", head.StateRoot())
+	return nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+var (
+	snapshotBlockFlag = &cli.Int64Flag{
+		Name:  "block",
+		Usage: "Block number whose post-state is dumped (-1 = current head)",
+		Value: -1,
+	}
+
+	snapshotCommand = &cli.Command{
+		Name:  "snapshot",
+		Usage: "Export or import the full account/storage state",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "dump",
+				Usage:     "Dump the state at a block into a portable genesis file",
+				ArgsUsage: "<file>",
+				Action:    snapshotDump,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					snapshotBlockFlag,
+				},
+				Description: `
+Serializes every account, its code and storage as of the given block into a
+genesis file carrying the source chain config. Files ending in .gz are gzip
+compressed. The result can be loaded with "n42 snapshot restore" or "n42 init".`,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Bootstrap a new datadir from a state snapshot",
+				ArgsUsage: "<file>",
+				Action:    snapshotRestore,
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+Initializes an empty datadir with a genesis block whose state is the snapshot
+allocation. This starts a new chain and is meant for network resets and
+testnet seeding.`,
+			},
+		},
+	}
+)
+
+func snapshotDump(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return fmt.Errorf("missing snapshot file argument")
+	}
+
+	chaindb, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	tx, err := chaindb.BeginRo(ctx.Context)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	genesis, err := dumpState(tx, ctx.Int64(snapshotBlockFlag.Name))
+	if err != nil {
+		return err
+	}
+	if err := writeSnapshot(path, genesis); err != nil {
+		return err
+	}
+	log.Info("State snapshot written", "file", path, "accounts", len(genesis.Alloc))
+	return nil
+}
+
+func snapshotRestore(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return fmt.Errorf("missing snapshot file argument")
+	}
+	genesis, err := readSnapshot(path)
+	if err != nil {
+		return err
+	}
+	if genesis.Config == nil {
+		return fmt.Errorf("snapshot %s carries no chain config", path)
+	}
+	genesisBlock, err := writeGenesis(genesis)
+	if err != nil {
+		return err
+	}
+	log.Info("Restored state snapshot", "file", path, "accounts", len(genesis.Alloc), "genesis", genesisBlock.Hash())
+	return nil
+}
+
+// dumpState collects the post-state of the given block (or the head when
+// number is negative) into a genesis definition.
+func dumpState(tx kv.Tx, number int64) (*conf.Genesis, error) {
+	// The head header hash is only written at genesis, so follow the head block.
+	head := rawdb.ReadCurrentBlock(tx)
+	if head == nil {
+		return nil, fmt.Errorf("database has no head block")
+	}
+	blockNr := head.Number64().Uint64()
+	if number >= 0 {
+		if uint64(number) > blockNr {
+			return nil, fmt.Errorf("block %d is beyond the head %d", number, blockNr)
+		}
+		blockNr = uint64(number)
+	}
+
+	genesisHeader := rawdb.ReadHeaderByNumber(tx, 0)
+	header := rawdb.ReadHeaderByNumber(tx, blockNr)
+	if genesisHeader == nil || header == nil {
+		return nil, fmt.Errorf("missing header for block %d", blockNr)
+	}
+	chainConfig, err := rawdb.ReadChainConfig(tx, genesisHeader.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	genesis := &conf.Genesis{
+		Config:     chainConfig,
+		Timestamp:  header.Time,
+		GasLimit:   header.GasLimit,
+		Difficulty: uint256.NewInt(0).Set(genesisHeader.Difficulty),
+		Coinbase:   genesisHeader.Coinbase,
+		Miners:     genesisSigners(chainConfig, genesisHeader.Extra),
+		Alloc:      make(conf.GenesisAlloc),
+		BaseFee:    header.BaseFee,
+	}
+
+	// History is indexed by the block that changed a value, so the state after
+	// block N is read as of N+1.
+	asOf := blockNr + 1
+	if err := state.WalkAsOfAccounts(tx, types.Address{}, asOf, func(k, v []byte) (bool, error) {
+		if len(k) != types.AddressLength {
+			return true, nil
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(v); err != nil {
+			return false, fmt.Errorf("decode account %x: %w", k, err)
+		}
+		addr := types.BytesToAddress(k)
+		alloc, err := dumpAccount(tx, addr, &acc, asOf)
+		if err != nil {
+			return false, err
+		}
+		genesis.Alloc[addr] = alloc
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// dumpAccount reads the code and storage of a single account.
+func dumpAccount(tx kv.Tx, addr types.Address, acc *account.StateAccount, asOf uint64) (conf.GenesisAccount, error) {
+	alloc := conf.GenesisAccount{
+		Balance: acc.Balance.ToBig().String(),
+		Nonce:   acc.Nonce,
+	}
+	if acc.Incarnation == 0 {
+		return alloc, nil
+	}
+
+	codeHash := acc.CodeHash
+	if acc.IsEmptyCodeHash() {
+		h, err := tx.GetOne(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(addr[:], acc.Incarnation))
+		if err != nil {
+			return alloc, err
+		}
+		codeHash = types.BytesToHash(h)
+	}
+	if codeHash != (types.Hash{}) {
+		code, err := tx.GetOne(modules.Code, codeHash[:])
+		if err != nil {
+			return alloc, err
+		}
+		alloc.Code = code
+	}
+
+	storage := make(map[types.Hash]types.Hash)
+	if err := state.WalkAsOfStorage(tx, addr, acc.Incarnation, types.Hash{}, asOf, func(_, loc, v []byte) (bool, error) {
+		if len(v) > 0 {
+			storage[types.BytesToHash(loc)] = types.BytesToHash(v)
+		}
+		return true, nil
+	}); err != nil {
+		return alloc, fmt.Errorf("walk storage of %x: %w", addr, err)
+	}
+	if len(storage) > 0 {
+		alloc.Storage = storage
+	}
+	return alloc, nil
+}
+
+// genesisSigners recovers the initial signer list embedded in the genesis
+// extra-data, so the restored chain is sealed by the same authorities.
+func genesisSigners(cfg *params.ChainConfig, extra []byte) []string {
+	if cfg == nil || (cfg.Consensus != params.CliqueConsensus && cfg.Consensus != params.AposConsensu) {
+		return nil
+	}
+	const vanity, seal = 32, 65
+	if len(extra) < vanity+seal {
+		return nil
+	}
+	signers := extra[vanity : len(extra)-seal]
+	miners := make([]string, 0, len(signers)/types.AddressLength)
+	for i := 0; i+types.AddressLength <= len(signers); i += types.AddressLength {
+		miners = append(miners, types.BytesToAddress(signers[i:i+types.AddressLength]).Hex())
+	}
+	return miners
+}
+
+func writeSnapshot(path string, genesis *conf.Genesis) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	var w io.Writer = bw
+	var zw *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		zw = gzip.NewWriter(bw)
+		w = zw
+	}
+	if err := json.NewEncoder(w).Encode(genesis); err != nil {
+		return err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func readSnapshot(path string) (*conf.Genesis, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	// Detect gzip by its magic bytes rather than trusting the file name.
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	genesis := new(conf.Genesis)
+	if err := json.NewDecoder(r).Decode(genesis); err != nil {
+		return nil, fmt.Errorf("invalid snapshot file: %w", err)
+	}
+	return genesis, nil
+}
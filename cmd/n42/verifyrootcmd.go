@@ -0,0 +1,94 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/internal/commitment"
+	"github.com/n42blockchain/N42/log"
+)
+
+var (
+	verifyRootBlockFlag = &cli.Int64Flag{
+		Name:  "block",
+		Usage: "Block number whose state root is recomputed (-1 = last committed block)",
+		Value: -1,
+	}
+
+	verifyStateRootCommand = &cli.Command{
+		Name:   "verify-state-root",
+		Usage:  "Recompute the state trie root of a block and compare it with the committed one",
+		Action: verifyStateRoot,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			verifyRootBlockFlag,
+		},
+		Description: `
+Rebuilds the state trie of the block from scratch out of the plain state and
+the change sets, and compares its root with the one maintained incrementally
+by a node running with --state.commitment. The node must be stopped.`,
+	}
+)
+
+func verifyStateRoot(ctx *cli.Context) error {
+	chaindb, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	tx, err := chaindb.BeginRo(ctx.Context)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	number := ctx.Int64(verifyRootBlockFlag.Name)
+	if number < 0 {
+		p, err := commitment.ReadProgress(tx)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("no state commitment, run the node with --%s", StateCommitmentFlag.Name)
+		}
+		number = int64(p.Number)
+	}
+	hash, committed, ok, err := commitment.ReadRoot(tx, uint64(number))
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	root, err := commitment.ComputeRoot(tx, uint64(number))
+	if err != nil {
+		return fmt.Errorf("recompute state root of block %d: %w", number, err)
+	}
+	log.Info("Recomputed state root", "number", number, "root", root, "elapsed", time.Since(start))
+	switch {
+	case !ok:
+		return fmt.Errorf("block %d has no committed state root", number)
+	case root != committed:
+		return fmt.Errorf("state root mismatch at block %d (%s): committed %s, recomputed %s", number, hash, committed, root)
+	}
+	log.Info("State root matches commitment", "number", number, "hash", hash, "root", root)
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/commitment"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+var (
+	witnessBlockFlag = &cli.Uint64Flag{
+		Name:     "block",
+		Usage:    "Block number whose witness is generated",
+		Required: true,
+	}
+
+	verkleWitnessCommand = &cli.Command{
+		Name:      "verkle-witness",
+		Usage:     "Generate the Verkle witness of a block for stateless verification experiments (experimental)",
+		Action:    verkleWitness,
+		ArgsUsage: "[<file>]",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			witnessBlockFlag,
+		},
+		Description: `
+Re-executes the block on top of the state of its parent, records the accounts
+and storage slots it reads and writes, and prints (or writes to <file>) the
+Verkle tree nodes on the paths to them together with the pre-state root. The
+witness carries whole nodes instead of IPA opening proofs and is checked by
+recomputing the commitments up to the root. The node must be stopped.`,
+	}
+)
+
+func verkleWitness(ctx *cli.Context) error {
+	if ctx.Args().Len() > 1 {
+		return fmt.Errorf("expected at most one output file")
+	}
+	number := ctx.Uint64(witnessBlockFlag.Name)
+	if number == 0 {
+		return fmt.Errorf("the genesis block has no witness")
+	}
+
+	chaindb, err := openChainDB()
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	tx, err := chaindb.BeginRo(ctx.Context)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	blk, err := rawdb.ReadBlockByNumber(tx, number)
+	if blk == nil {
+		return fmt.Errorf("block %d is missing: %v", number, err)
+	}
+	processor, closeProcessor, err := newOfflineProcessor(ctx, chaindb, tx)
+	if err != nil {
+		return err
+	}
+	defer closeProcessor()
+
+	header := blk.Header().(*block.Header)
+	reader := state.NewRecordingReader(state.NewPlainState(tx, number))
+	getHeader := func(hash types.Hash, number uint64) *block.Header {
+		return rawdb.ReadHeader(tx, hash, number)
+	}
+	if _, _, _, _, err := processor.Process(blk, state.New(reader), reader, state.NewNoopWriter(), internal.GetHashFn(header, getHeader)); err != nil {
+		return fmt.Errorf("execute block %d: %w", number, err)
+	}
+
+	start := time.Now()
+	w, err := commitment.BuildWitness(tx, number, reader.Keys())
+	if err != nil {
+		return err
+	}
+	if err := w.Verify(); err != nil {
+		return fmt.Errorf("generated witness does not verify: %w", err)
+	}
+	log.Info("Generated Verkle witness", "number", number, "root", w.Root, "inner", len(w.Inner), "leaves", len(w.Leaves), "elapsed", time.Since(start))
+
+	if ctx.Args().Len() == 0 {
+		return printJSON(w)
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ctx.Args().First(), data, 0644)
+}
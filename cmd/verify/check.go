@@ -0,0 +1,55 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/n42blockchain/N42/internal/witness"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// checkWitnessFile verifies a single execution witness, as returned by
+// debug_executionWitness, against the state root of its header.
+func checkWitnessFile(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var msg state.EntireCode
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("invalid witness: %w", err)
+	}
+	if msg.Entire.Header == nil || msg.Entire.Snap == nil {
+		return fmt.Errorf("invalid witness: missing header or state")
+	}
+	root, err := witness.Verify(ctx, params.MainnetChainConfig, &msg)
+	if err != nil {
+		return err
+	}
+	header := msg.Entire.Header
+	if root != header.Root {
+		return fmt.Errorf("state root mismatch at block %d: header %s, executed %s", header.Number.Uint64(), header.Root, root)
+	}
+	log.Info("Witness verified", "number", header.Number.Uint64(), "hash", header.Hash(), "root", root)
+	return nil
+}
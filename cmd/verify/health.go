@@ -0,0 +1,83 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// healthStatus is the response of the health endpoint
+type healthStatus struct {
+	Connected     bool           `json:"connected"`
+	Keys          int            `json:"keys"`
+	Subscriptions int            `json:"subscriptions"`
+	Reconnects    int            `json:"reconnects"`
+	Queued        int            `json:"queued"`
+	LastVerified  *verifiedBlock `json:"lastVerified,omitempty"`
+}
+
+// health returns the current state of the verifier
+func (v *verifier) health() healthStatus {
+	v.writeMu.Lock()
+	connected := v.con != nil
+	v.writeMu.Unlock()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	status := healthStatus{
+		Connected:     connected,
+		Keys:          len(v.accounts),
+		Subscriptions: len(v.subs),
+		Queued:        v.queue.len(),
+	}
+	if v.connections > 1 {
+		status.Reconnects = v.connections - 1
+	}
+	if v.last.Number > 0 {
+		last := v.last
+		status.LastVerified = &last
+	}
+	return status
+}
+
+// serveHealth serves the verifier state as JSON on addr until ctx is done.
+// It answers 503 while the verifier is not subscribed to the node.
+func serveHealth(ctx context.Context, addr string, v *verifier) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		status := v.health()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Connected || status.Subscriptions == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
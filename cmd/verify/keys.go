@@ -0,0 +1,288 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/n42blockchain/N42/accounts/keystore"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/remotesigner"
+	"github.com/n42blockchain/N42/log"
+)
+
+// validatorKey is the identity a verifier signs with. Like the node, the BLS
+// key is derived from the same 32 bytes as the ECDSA key of the address.
+type validatorKey struct {
+	address types.Address
+	secret  bls.SecretKey
+}
+
+func newValidatorKey(priv *ecdsa.PrivateKey) (*validatorKey, error) {
+	var sb [32]byte
+	copy(sb[:], crypto.FromECDSA(priv))
+	secret, err := bls.SecretKeyFromRandom32Byte(sb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BLS secret key: %w", err)
+	}
+	return &validatorKey{address: crypto.PubkeyToAddress(priv.PublicKey), secret: secret}, nil
+}
+
+// loadKeys reads the validator keys from the raw hex keys in EnvPrivateKey
+// and the key files in EnvKeystore. It is called again on every reload, so
+// keys can be rotated by editing the keystore and password file.
+func loadKeys() ([]*validatorKey, error) {
+	var keys []*validatorKey
+	for _, s := range strings.Split(os.Getenv(EnvPrivateKey), ",") {
+		s = strings.TrimPrefix(strings.TrimSpace(s), "0x")
+		if s == "" {
+			continue
+		}
+		sByte, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode private key: %w", err)
+		}
+		if len(sByte) != 32 {
+			return nil, errors.New("invalid private key length, expected 32 bytes")
+		}
+		priv, err := crypto.ToECDSA(sByte)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ECDSA private key: %w", err)
+		}
+		key, err := newValidatorKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if dir := os.Getenv(EnvKeystore); dir != "" {
+		stored, err := loadKeystore(dir, os.Getenv(EnvPasswordFile), os.Getenv(EnvAccounts))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, stored...)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no validator keys, set %s or %s", EnvPrivateKey, EnvKeystore)
+	}
+	return keys, nil
+}
+
+// loadKeystore decrypts the key files in dir. If accounts is not empty, only
+// the comma separated addresses it lists are loaded. Passwords are read from
+// passwordFile, one per line in the order of accounts, or of the key file
+// names if no accounts are given; the last password is used for all
+// remaining keys, like the node does when unlocking accounts.
+func loadKeystore(dir, passwordFile, accounts string) ([]*validatorKey, error) {
+	if passwordFile == "" {
+		return nil, fmt.Errorf("%s is required to unlock the keystore", EnvPasswordFile)
+	}
+	text, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password file: %w", err)
+	}
+	passwords := strings.Split(strings.TrimRight(string(text), "\r\n"), "\n")
+	for i := range passwords {
+		passwords[i] = strings.TrimRight(passwords[i], "\r")
+	}
+
+	var wanted []types.Address
+	for _, s := range strings.Split(accounts, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !types.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid account %q in %s", s, EnvAccounts)
+		}
+		wanted = append(wanted, types.HexToAddress(s))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	// Index the key files by the address they declare
+	files := make(map[types.Address]string)
+	var order []types.Address
+	for _, entry := range entries {
+		// Skip editor backups, hidden files and directories, like the keystore
+		if name := entry.Name(); strings.HasSuffix(name, "~") || strings.HasPrefix(name, ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		keyJSON, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var header struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(keyJSON, &header); err != nil || !types.IsHexAddress(header.Address) {
+			log.Debug("Ignoring file in keystore", "path", path)
+			continue
+		}
+		addr := types.HexToAddress(header.Address)
+		if _, ok := files[addr]; !ok {
+			order = append(order, addr)
+		}
+		files[addr] = path
+	}
+	if len(wanted) == 0 {
+		wanted = order
+	}
+
+	keys := make([]*validatorKey, 0, len(wanted))
+	for i, addr := range wanted {
+		path, ok := files[addr]
+		if !ok {
+			return nil, fmt.Errorf("account %s not found in keystore %s", addr, dir)
+		}
+		keyJSON, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		password := passwords[len(passwords)-1]
+		if i < len(passwords) {
+			password = passwords[i]
+		}
+		stored, err := keystore.DecryptKey(keyJSON, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		key, err := newValidatorKey(stored.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// keyRing holds the keys the verifier currently signs with
+type keyRing struct {
+	mu   sync.RWMutex
+	keys map[types.Address]*validatorKey
+}
+
+func newKeyRing() *keyRing {
+	return &keyRing{keys: make(map[types.Address]*validatorKey)}
+}
+
+// get returns the key of addr, if it is still in the ring
+func (r *keyRing) get(addr types.Address) (*validatorKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[addr]
+	return key, ok
+}
+
+// addresses returns the addresses of all keys in the ring
+func (r *keyRing) addresses() []types.Address {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	addrs := make([]types.Address, 0, len(r.keys))
+	for addr := range r.keys {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// replace swaps the keys of the ring and reports which addresses were added
+// and which were removed
+func (r *keyRing) replace(keys []*validatorKey) (added, removed []types.Address) {
+	next := make(map[types.Address]*validatorKey, len(keys))
+	for _, key := range keys {
+		next[key.address] = key
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for addr := range next {
+		if _, ok := r.keys[addr]; !ok {
+			added = append(added, addr)
+		}
+	}
+	for addr := range r.keys {
+		if _, ok := next[addr]; !ok {
+			removed = append(removed, addr)
+		}
+	}
+	r.keys = next
+	return added, removed
+}
+
+// localSigner signs with the keys of a keyRing. It backs the verifier when
+// no remote signer is configured, and is what a signer host serves.
+type localSigner struct {
+	keys       *keyRing
+	protection *remotesigner.Protection
+}
+
+// Keys returns the addresses and BLS public keys of the ring
+func (s *localSigner) Keys(ctx context.Context) ([]remotesigner.Key, error) {
+	s.keys.mu.RLock()
+	defer s.keys.mu.RUnlock()
+	keys := make([]remotesigner.Key, 0, len(s.keys.keys))
+	for addr, key := range s.keys.keys {
+		k := remotesigner.Key{Address: addr}
+		copy(k.PublicKey[:], key.secret.PublicKey().Marshal())
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Address.String() < keys[j].Address.String() })
+	return keys, nil
+}
+
+// Sign signs the state root with the BLS key of req.Address, unless it
+// conflicts with a root the address signed before
+func (s *localSigner) Sign(ctx context.Context, req *remotesigner.SignRequest) (types.Signature, error) {
+	key, ok := s.keys.get(req.Address)
+	if !ok {
+		return types.Signature{}, fmt.Errorf("%w: %s", remotesigner.ErrUnknownAddress, req.Address)
+	}
+	if err := s.protection.Check(req); err != nil {
+		return types.Signature{}, err
+	}
+	var sig types.Signature
+	copy(sig[:], key.secret.Sign(req.StateRoot[:]).Marshal())
+	return sig, nil
+}
+
+// reload reads the keys again. The current keys stay in use if loading fails.
+func (s *localSigner) reload() error {
+	keys, err := loadKeys()
+	if err != nil {
+		return err
+	}
+	s.keys.replace(keys)
+	return nil
+}
@@ -17,35 +17,41 @@ package main
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/go-kit/kit/transport/http/jsonrpc"
-	"github.com/gorilla/websocket"
-
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/common/crypto/bls"
-	"github.com/n42blockchain/N42/common/types"
-	"github.com/n42blockchain/N42/internal/api"
+	"github.com/n42blockchain/N42/internal/remotesigner"
 	"github.com/n42blockchain/N42/log"
-	"github.com/n42blockchain/N42/modules/state"
 )
 
 // Environment variable names for configuration
 const (
-	EnvPrivateKey  = "N42_VERIFY_PRIVATE_KEY"
+	EnvPrivateKey   = "N42_VERIFY_PRIVATE_KEY"   // Comma separated raw hex keys
+	EnvKeystore     = "N42_VERIFY_KEYSTORE"      // Keystore directory of the node
+	EnvPasswordFile = "N42_VERIFY_PASSWORD_FILE" // Passwords of the keystore, one per line
+	EnvAccounts     = "N42_VERIFY_ACCOUNTS"      // Comma separated keystore accounts to use (default: all)
 	EnvWebSocketURL = "N42_VERIFY_WS_URL"
-	DefaultWSURL   = "ws://127.0.0.1:20013"
+	EnvHealthAddr   = "N42_VERIFY_HEALTH_ADDR"   // Listen address of the health endpoint (default: disabled)
+	EnvQueueDir     = "N42_VERIFY_QUEUE_DIR"     // Directory of the pending verification jobs
+	EnvSignerURL    = "N42_VERIFY_SIGNER_URL"    // Remote signer to sign with instead of local keys
+	EnvSignerToken  = "N42_VERIFY_SIGNER_TOKEN"  // Bearer token of the remote signer
+	EnvSignerListen = "N42_VERIFY_SIGNER_LISTEN" // Serve the local keys as a remote signer on this address
+	EnvSignerCert   = "N42_VERIFY_SIGNER_CERT"   // TLS certificate of the served signer
+	EnvSignerKey    = "N42_VERIFY_SIGNER_KEY"    // TLS key of the served signer
+	EnvSignedRoots  = "N42_VERIFY_SIGNED_ROOTS"  // File recording the last root signed by each local key
+	DefaultWSURL    = "ws://127.0.0.1:20013"
+	DefaultQueueDir = "verify-queue"
+
+	DefaultSignedRoots = "signed-roots.json"
 )
 
-var privateKey bls.SecretKey
-var addressKey types.Address
-
 func RootContext() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -67,42 +73,47 @@ func RootContext() (context.Context, context.CancelFunc) {
 }
 
 func main() {
-	// SECURITY: Read private key from environment variable instead of hardcoding
-	privateKeyHex := os.Getenv(EnvPrivateKey)
-	if privateKeyHex == "" {
-		log.Error("Private key not set. Please set environment variable: " + EnvPrivateKey)
-		os.Exit(1)
-	}
-
-	var err error
-	sByte, err := hex.DecodeString(privateKeyHex)
-	if err != nil {
-		log.Error("Failed to decode private key", "error", err)
-		os.Exit(1)
-	}
+	ctx, cancel := RootContext()
+	defer cancel()
 
-	if len(sByte) != 32 {
-		log.Error("Invalid private key length, expected 32 bytes")
-		os.Exit(1)
+	// verify <file> checks one witness and exits
+	if len(os.Args) == 2 {
+		if err := checkWitnessFile(ctx, os.Args[1]); err != nil {
+			log.Error("Witness verification failed", "file", os.Args[1], "error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	var sb [32]byte
-	copy(sb[:], sByte)
-	privateKey, err = bls.SecretKeyFromRandom32Byte(sb)
-	if err != nil {
-		log.Error("Failed to create BLS secret key", "error", err)
-		os.Exit(1)
-	}
+	var signer remotesigner.Signer
+	if url := os.Getenv(EnvSignerURL); url != "" {
+		signer = remotesigner.NewClient(url, os.Getenv(EnvSignerToken))
+		log.Info("Signing with remote signer", "url", url)
+	} else {
+		path := os.Getenv(EnvSignedRoots)
+		if path == "" {
+			path = DefaultSignedRoots
+		}
+		protection, err := remotesigner.OpenProtection(path)
+		if err != nil {
+			log.Error("Failed to open signed roots", "file", path, "error", err)
+			os.Exit(1)
+		}
+		local := &localSigner{keys: newKeyRing(), protection: protection}
+		if err := local.reload(); err != nil {
+			log.Error("Failed to load validator keys", "error", err)
+			os.Exit(1)
+		}
+		signer = local
 
-	ecdPk, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		log.Error("Failed to create ECDSA private key", "error", err)
-		os.Exit(1)
+		if addr := os.Getenv(EnvSignerListen); addr != "" {
+			if err := serveSigner(ctx, addr, local); err != nil {
+				log.Error("Signer failed", "addr", addr, "error", err)
+				os.Exit(1)
+			}
+			return
+		}
 	}
-	addressKey = crypto.PubkeyToAddress(ecdPk.PublicKey)
-
-	ctx, cancel := RootContext()
-	defer cancel()
 
 	// Get WebSocket URL from environment or use default
 	wsURL := os.Getenv(EnvWebSocketURL)
@@ -110,112 +121,136 @@ func main() {
 		wsURL = DefaultWSURL
 	}
 
-	con, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	queueDir := os.Getenv(EnvQueueDir)
+	if queueDir == "" {
+		queueDir = DefaultQueueDir
+	}
+	queue, err := openJobQueue(queueDir)
 	if err != nil {
-		log.Error("Failed to connect to WebSocket", "url", wsURL, "error", err)
+		log.Error("Failed to open job queue", "dir", queueDir, "error", err)
 		os.Exit(1)
 	}
-	defer con.Close()
 
-	end := make(chan struct{})
-	defer close(end)
+	v := newVerifier(signer, queue)
+	if _, _, err := v.refresh(ctx); err != nil {
+		log.Error("Failed to fetch validator keys", "error", err)
+		os.Exit(1)
+	}
+	go v.process(ctx)
+
+	if addr := os.Getenv(EnvHealthAddr); addr != "" {
+		go func() {
+			if err := serveHealth(ctx, addr, v); err != nil {
+				log.Error("Health endpoint failed", "addr", addr, "error", err)
+				cancel()
+			}
+		}()
+	}
 
+	// Reload the keys on SIGHUP, so they can be rotated without a restart
 	go func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
 		for {
 			select {
+			case <-hup:
+				v.reload(ctx)
 			case <-ctx.Done():
-				end <- struct{}{}
 				return
-			default:
-				typ, msg, err := con.ReadMessage()
-				if nil != err {
-					log.Errorf("read msg failed: %v", err)
-					continue
-				}
-				if typ == websocket.TextMessage {
-					fmt.Println("read msg: ", string(msg))
-					params, err := unwrapJSONRPC(msg)
-					if nil != err {
-						log.Warn(err.Error())
-						continue
-					}
-
-					bean := new(state.EntireCode)
-					if err := json.Unmarshal(params, bean); err != nil {
-						log.Errorf("unmarshal entire failed, %v", err)
-						continue
-					}
-
-					root := verify(ctx, bean)
-					res := api.AggSign{}
-					res.Number = bean.Entire.Header.Number.Uint64()
-					res.Address = addressKey
-					res.StateRoot = root
-					copy(res.Sign[:], privateKey.Sign(root[:]).Marshal())
-					in, err := json.Marshal(res)
-					if err != nil {
-						log.Error("Failed to marshal response", "error", err)
-						continue
-					}
-
-					wrapRequest, _ := wrapJSONRPCRequest(in)
-					if err := con.WriteMessage(websocket.TextMessage, wrapRequest); nil != err {
-						log.Error("write msg failed: ", err)
-					}
-					log.Infof("write msg: %s", wrapRequest)
-				}
 			}
 		}
 	}()
 
-	if err = con.PingHandler()(""); err != nil {
-		log.Error("Ping failed", "error", err)
-		os.Exit(1)
-	}
-
-	subscribeMsg := fmt.Sprintf(`{
-		"jsonrpc": "2.0",
-		"method": "eth_subscribe",
-		"params": [
-		  "minedBlock",
-		  "%s"
-		],
-		"id": 1
-	  }`, addressKey.String())
+	v.run(ctx, wsURL)
+}
 
-	if err := con.WriteMessage(websocket.TextMessage, []byte(subscribeMsg)); err != nil {
-		log.Error("Failed to subscribe", "error", err)
-		cancel()
+// serveSigner serves the keys of signer over the remote signer protocol on
+// addr until ctx is done. Keys are reloaded on SIGHUP. Addresses other than
+// loopback are only served over TLS.
+func serveSigner(ctx context.Context, addr string, signer *localSigner) error {
+	cert, key := os.Getenv(EnvSignerCert), os.Getenv(EnvSignerKey)
+	if (cert == "") != (key == "") {
+		return fmt.Errorf("%s and %s must be set together", EnvSignerCert, EnvSignerKey)
+	}
+	if cert == "" && !isLoopback(addr) {
+		return fmt.Errorf("serving the signer on %s requires TLS, set %s and %s", addr, EnvSignerCert, EnvSignerKey)
 	}
 
-	<-end
-}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for {
+			select {
+			case <-hup:
+				if err := signer.reload(); err != nil {
+					log.Error("Failed to reload validator keys", "error", err)
+				} else {
+					log.Info("Reloaded validator keys", "keys", len(signer.keys.addresses()))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
 
-func unwrapJSONRPC(in []byte) ([]byte, error) {
-	//"{\"jsonrpc\":\"2.0\",\"id\":1,\"error\":{\"code\":-32000,\"message\":\"unauthed address: 0xeB156a42dcaFcf155B07f3638892440C7dE5d564\"}}\n"
-	//ws consumer received msg:%!(EXTRA string=ws consumer received msg:, string={"jsonrpc":"2.0","id":1,"result":"0x96410b68a9f8875bb20fde06823eb861"}
-	req := new(jsonrpc.Request)
-	if err := json.Unmarshal(in, req); err != nil {
-		return nil, err
+	token := os.Getenv(EnvSignerToken)
+	if token == "" {
+		log.Warn("Remote signer is not protected by a token", "env", EnvSignerToken)
 	}
-	if len(req.Params) == 0 {
-		return []byte{}, errors.New("empty request params")
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           remotesigner.NewHandler(signer, signer.protection, token),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
-
-	//type innerProtocolEntire struct {
-	//	Entire json.RawMessage `json:"Entire"`
-	//}
-	type innerProtocol struct {
-		Subscription string          `json:"subscription"`
-		Result       json.RawMessage `json:"result"`
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.Info("Serving remote signer", "addr", addr, "keys", len(signer.keys.addresses()), "tls", cert != "")
+	var err error
+	if cert != "" {
+		err = srv.ListenAndServeTLS(cert, key)
+	} else {
+		err = srv.ListenAndServe()
 	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
 
-	innerReq := new(innerProtocol)
-	if err := json.Unmarshal(req.Params, innerReq); err != nil {
-		return nil, err
+// isLoopback reports whether the listen address addr only accepts local
+// connections
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
 
-	return innerReq.Result, nil
+// jsonrpcMessage is a response or notification sent by the node
+type jsonrpcMessage struct {
+	ID     *int            `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// subscriptionResult is the payload of an eth_subscription notification
+type subscriptionResult struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
 }
 
 type JSONRPCRequest struct {
@@ -225,13 +260,19 @@ type JSONRPCRequest struct {
 	Params  []json.RawMessage `json:"params"`
 }
 
-func wrapJSONRPCRequest(in []byte) ([]byte, error) {
+func wrapJSONRPCRequest(id int, method string, params ...interface{}) ([]byte, error) {
 	d := &JSONRPCRequest{
 		JsonRpc: "2.0",
-		Method:  "eth_submitSign",
-		ID:      1,
-		Params:  make([]json.RawMessage, 1),
+		Method:  method,
+		ID:      id,
+		Params:  make([]json.RawMessage, len(params)),
+	}
+	for i, param := range params {
+		raw, err := json.Marshal(param)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		d.Params[i] = raw
 	}
-	d.Params[0] = in
 	return json.Marshal(d)
 }
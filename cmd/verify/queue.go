@@ -0,0 +1,241 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+)
+
+const (
+	jobSuffix  = ".job"
+	signedFile = "signed.json"
+)
+
+// verifyJob is a block pushed to the subscription of an address that still
+// has to be verified and signed
+type verifyJob struct {
+	Number  uint64
+	Address types.Address
+	path    string
+}
+
+// jobQueue persists verification jobs in a directory, one file per job
+// holding the EntireCode payload as received. Jobs survive restarts and are
+// handed out in block order. The queue also records the highest block every
+// address has signed, so blocks pushed again after a restart are skipped.
+type jobQueue struct {
+	dir  string
+	wake chan struct{}
+
+	mu     sync.Mutex
+	jobs   []*verifyJob // Sorted by block number, then address
+	signed map[types.Address]uint64
+}
+
+// openJobQueue opens the queue in dir, creating it if needed, and loads the
+// jobs left from a previous run.
+func openJobQueue(dir string) (*jobQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	q := &jobQueue{
+		dir:    dir,
+		wake:   make(chan struct{}, 1),
+		signed: make(map[types.Address]uint64),
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, signedFile)); err == nil {
+		if err := json.Unmarshal(data, &q.signed); err != nil {
+			return nil, fmt.Errorf("failed to read signed heights: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".tmp") {
+			// Interrupted while writing, the job was never acknowledged
+			os.Remove(path)
+			continue
+		}
+		if !strings.HasSuffix(name, jobSuffix) {
+			continue
+		}
+		job, err := parseJobName(strings.TrimSuffix(name, jobSuffix))
+		if err != nil {
+			log.Warn("Ignoring file in job queue", "path", path, "error", err)
+			continue
+		}
+		job.path = path
+		q.jobs = append(q.jobs, job)
+	}
+	q.sort()
+	if len(q.jobs) > 0 {
+		log.Info("Resuming queued verification jobs", "jobs", len(q.jobs), "dir", dir)
+	}
+	return q, nil
+}
+
+// parseJobName parses a job file name of the form <number>-<address>
+func parseJobName(name string) (*verifyJob, error) {
+	number, address, ok := strings.Cut(name, "-")
+	if !ok || !types.IsHexAddress(address) {
+		return nil, errors.New("invalid job name")
+	}
+	n, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyJob{Number: n, Address: types.HexToAddress(address)}, nil
+}
+
+func (q *jobQueue) sort() {
+	sort.Slice(q.jobs, func(i, j int) bool {
+		if q.jobs[i].Number != q.jobs[j].Number {
+			return q.jobs[i].Number < q.jobs[j].Number
+		}
+		return q.jobs[i].Address.String() < q.jobs[j].Address.String()
+	})
+}
+
+// push stores payload as the job of addr for block number. Blocks the
+// address already signed or queued are ignored.
+func (q *jobQueue) push(addr types.Address, number uint64, payload []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if last, ok := q.signed[addr]; ok && number <= last {
+		return nil
+	}
+	job := &verifyJob{Number: number, Address: addr}
+	job.path = filepath.Join(q.dir, fmt.Sprintf("%020d-%s%s", number, addr.Hex(), jobSuffix))
+	for _, queued := range q.jobs {
+		if queued.path == job.path {
+			return nil
+		}
+	}
+
+	if err := writeFileAtomic(job.path, payload); err != nil {
+		return err
+	}
+	q.jobs = append(q.jobs, job)
+	q.sort()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next waits for the first job in the queue. The job stays queued until it
+// is completed with done.
+func (q *jobQueue) next(ctx context.Context) (*verifyJob, error) {
+	for {
+		q.mu.Lock()
+		if len(q.jobs) > 0 {
+			job := q.jobs[0]
+			q.mu.Unlock()
+			return job, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.wake:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// payload reads the EntireCode payload of job
+func (q *jobQueue) payload(job *verifyJob) ([]byte, error) {
+	return os.ReadFile(job.path)
+}
+
+// done removes job from the queue. If signed is set, the block is recorded
+// as signed by the job's address.
+func (q *jobQueue) done(job *verifyJob, signed bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, queued := range q.jobs {
+		if queued == job {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+			break
+		}
+	}
+	if signed && job.Number > q.signed[job.Address] {
+		q.signed[job.Address] = job.Number
+		data, err := json.Marshal(q.signed)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(q.dir, signedFile), data); err != nil {
+			return err
+		}
+	}
+	return os.Remove(job.path)
+}
+
+// len returns the number of queued jobs
+func (q *jobQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path,
+// so a crash never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,515 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/api"
+	"github.com/n42blockchain/N42/internal/remotesigner"
+	"github.com/n42blockchain/N42/internal/witness"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+const (
+	// rootCacheSize bounds the number of verified blocks whose state root is
+	// kept, so a block pushed to several subscriptions is executed only once.
+	rootCacheSize = 16
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+
+	// retryDelay is the pause before a job whose signature could not be
+	// created or submitted is tried again
+	retryDelay = time.Second
+
+	// The node is pinged every pingInterval; a connection that stays silent
+	// for pongWait is considered dead.
+	pingInterval = 30 * time.Second
+	pongWait     = 2 * pingInterval
+	writeWait    = 10 * time.Second
+)
+
+var (
+	errNotConnected = errors.New("not connected to the node")
+	errSubmit       = errors.New("failed to submit signature")
+	errSigner       = errors.New("signer unavailable")
+)
+
+// reloader is implemented by signers whose keys are loaded by the verifier
+type reloader interface {
+	reload() error
+}
+
+// verifier re-executes the blocks pushed to the minedBlock subscriptions of
+// the signer's keys and submits a signature of the resulting state root for
+// each. Pushed blocks are buffered in a persistent queue and processed in
+// order, independently of the connection that received them.
+type verifier struct {
+	signer remotesigner.Signer
+	queue  *jobQueue
+
+	writeMu sync.Mutex // Serialises writes to con
+	con     *websocket.Conn
+
+	mu          sync.Mutex
+	accounts    map[types.Address]struct{} // Addresses the signer holds keys for
+	nextID      int
+	pending     map[int]types.Address     // Subscribe requests awaiting their subscription id
+	subs        map[string]types.Address  // Subscription ids and the address they were made for
+	roots       map[types.Hash]types.Hash // State roots of recently verified blocks
+	connections int                       // Connections established to the node so far
+	last        verifiedBlock
+}
+
+// verifiedBlock is the last block the verifier submitted a signature for
+type verifiedBlock struct {
+	Number uint64     `json:"number"`
+	Hash   types.Hash `json:"hash"`
+	Root   types.Hash `json:"stateRoot"`
+	Time   time.Time  `json:"time"`
+}
+
+func newVerifier(signer remotesigner.Signer, queue *jobQueue) *verifier {
+	return &verifier{
+		signer:   signer,
+		queue:    queue,
+		accounts: make(map[types.Address]struct{}),
+		pending:  make(map[int]types.Address),
+		subs:     make(map[string]types.Address),
+		roots:    make(map[types.Hash]types.Hash),
+	}
+}
+
+// refresh fetches the keys of the signer and reports which addresses were
+// added and which were removed since the last refresh
+func (v *verifier) refresh(ctx context.Context) (added, removed []types.Address, err error) {
+	keys, err := v.signer.Keys(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	next := make(map[types.Address]struct{}, len(keys))
+	for _, key := range keys {
+		next[key.Address] = struct{}{}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for addr := range next {
+		if _, ok := v.accounts[addr]; !ok {
+			added = append(added, addr)
+		}
+	}
+	for addr := range v.accounts {
+		if _, ok := next[addr]; !ok {
+			removed = append(removed, addr)
+		}
+	}
+	v.accounts = next
+	return added, removed, nil
+}
+
+// addresses returns the addresses the verifier signs for
+func (v *verifier) addresses() []types.Address {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	addrs := make([]types.Address, 0, len(v.accounts))
+	for addr := range v.accounts {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// signsFor reports whether the verifier still signs for addr
+func (v *verifier) signsFor(addr types.Address) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, ok := v.accounts[addr]
+	return ok
+}
+
+// run keeps the verifier connected to the node at url until ctx is done.
+// Lost connections are re-established with exponential backoff and all keys
+// are subscribed again.
+func (v *verifier) run(ctx context.Context, url string) {
+	delay := minReconnectDelay
+	for {
+		connected, err := v.serve(ctx, url)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = minReconnectDelay
+		}
+		log.Warn("Connection to node lost, reconnecting", "url", url, "error", err, "retry", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// serve connects to url, subscribes all keys and handles messages until the
+// connection fails. It reports whether the connection was established.
+func (v *verifier) serve(ctx context.Context, url string) (bool, error) {
+	con, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return false, err
+	}
+	defer con.Close()
+	v.attach(con)
+	defer v.attach(nil)
+
+	// Unblock the read loop on shutdown and keep the connection alive
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				v.writeMu.Lock()
+				err := con.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+				v.writeMu.Unlock()
+				if err != nil {
+					con.Close()
+					return
+				}
+			case <-ctx.Done():
+				con.Close()
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	con.SetPongHandler(func(string) error {
+		return con.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for _, addr := range v.addresses() {
+		if err := v.subscribe(addr); err != nil {
+			return true, err
+		}
+	}
+	log.Info("Connected to node", "url", url)
+
+	for {
+		if err := con.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+			return true, err
+		}
+		typ, msg, err := con.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		if typ == websocket.TextMessage {
+			v.handle(ctx, msg)
+		}
+	}
+}
+
+// attach switches the verifier to a new connection, or detaches it if con is
+// nil. Subscriptions do not outlive their connection.
+func (v *verifier) attach(con *websocket.Conn) {
+	v.writeMu.Lock()
+	v.con = con
+	v.writeMu.Unlock()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pending = make(map[int]types.Address)
+	v.subs = make(map[string]types.Address)
+	if con != nil {
+		v.connections++
+	}
+}
+
+// write sends a JSON-RPC request with the given id
+func (v *verifier) write(id int, method string, params ...interface{}) error {
+	req, err := wrapJSONRPCRequest(id, method, params...)
+	if err != nil {
+		return err
+	}
+	v.writeMu.Lock()
+	defer v.writeMu.Unlock()
+	if v.con == nil {
+		return errNotConnected
+	}
+	if err := v.con.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return v.con.WriteMessage(websocket.TextMessage, req)
+}
+
+// call sends a JSON-RPC request whose response needs no handling
+func (v *verifier) call(method string, params ...interface{}) error {
+	v.mu.Lock()
+	v.nextID++
+	id := v.nextID
+	v.mu.Unlock()
+	return v.write(id, method, params...)
+}
+
+// subscribe requests the blocks addr has to verify
+func (v *verifier) subscribe(addr types.Address) error {
+	v.mu.Lock()
+	v.nextID++
+	id := v.nextID
+	v.pending[id] = addr
+	v.mu.Unlock()
+
+	if err := v.write(id, "eth_subscribe", "minedBlock", addr.String()); err != nil {
+		v.mu.Lock()
+		delete(v.pending, id)
+		v.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// unsubscribe cancels the subscriptions made for addr
+func (v *verifier) unsubscribe(addr types.Address) {
+	var ids []string
+	v.mu.Lock()
+	for id, a := range v.subs {
+		if a == addr {
+			ids = append(ids, id)
+			delete(v.subs, id)
+		}
+	}
+	v.mu.Unlock()
+
+	for _, id := range ids {
+		if err := v.call("eth_unsubscribe", id); err != nil {
+			log.Warn("Failed to unsubscribe", "address", addr, "subscription", id, "error", err)
+		}
+	}
+}
+
+// reload reads the keys again, subscribing for new and unsubscribing removed
+// addresses. Local keys are loaded from disk again, a remote signer is asked
+// for its current keys. The current keys stay in use if loading fails.
+func (v *verifier) reload(ctx context.Context) {
+	if r, ok := v.signer.(reloader); ok {
+		if err := r.reload(); err != nil {
+			log.Error("Failed to reload validator keys", "error", err)
+			return
+		}
+	}
+	added, removed, err := v.refresh(ctx)
+	if err != nil {
+		log.Error("Failed to reload validator keys", "error", err)
+		return
+	}
+	for _, addr := range removed {
+		v.unsubscribe(addr)
+	}
+	for _, addr := range added {
+		// Without a connection the key is subscribed once it reconnects
+		if err := v.subscribe(addr); err != nil && !errors.Is(err, errNotConnected) {
+			log.Error("Failed to subscribe", "address", addr, "error", err)
+		}
+	}
+	log.Info("Reloaded validator keys", "keys", len(v.addresses()), "added", len(added), "removed", len(removed))
+}
+
+// handle processes a message received from the node
+func (v *verifier) handle(ctx context.Context, in []byte) {
+	msg := new(jsonrpcMessage)
+	if err := json.Unmarshal(in, msg); err != nil {
+		log.Warn("Failed to decode message", "error", err)
+		return
+	}
+
+	if msg.Method == "" {
+		v.handleResponse(msg)
+		return
+	}
+	if msg.Method != "eth_subscription" {
+		return
+	}
+	notification := new(subscriptionResult)
+	if err := json.Unmarshal(msg.Params, notification); err != nil {
+		log.Warn("Failed to decode notification", "error", err)
+		return
+	}
+
+	v.mu.Lock()
+	addr, ok := v.subs[notification.Subscription]
+	v.mu.Unlock()
+	if !ok {
+		return
+	}
+	if !v.signsFor(addr) {
+		return
+	}
+
+	bean := new(state.EntireCode)
+	if err := json.Unmarshal(notification.Result, bean); err != nil {
+		log.Errorf("unmarshal entire failed, %v", err)
+		return
+	}
+	number := bean.Entire.Header.Number.Uint64()
+	if err := v.queue.push(addr, number, notification.Result); err != nil {
+		log.Error("Failed to queue block", "number", number, "address", addr, "error", err)
+	}
+}
+
+// process verifies and signs the queued jobs one at a time, in block order.
+// A job stays queued until its signature was submitted, so jobs that could
+// not be signed or sent are retried once the signer or node is back.
+func (v *verifier) process(ctx context.Context) {
+	for {
+		job, err := v.queue.next(ctx)
+		if err != nil {
+			return
+		}
+		signed, err := v.sign(ctx, job)
+		if errors.Is(err, errSubmit) || errors.Is(err, errSigner) {
+			log.Debug("Retrying verification job", "number", job.Number, "address", job.Address, "error", err)
+			select {
+			case <-time.After(retryDelay):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			log.Error("Dropping verification job", "number", job.Number, "address", job.Address, "error", err)
+		}
+		if err := v.queue.done(job, signed); err != nil {
+			log.Error("Failed to remove verification job", "number", job.Number, "address", job.Address, "error", err)
+		}
+	}
+}
+
+// sign verifies the block of job and submits the signature of its state
+// root. It reports whether the signature was submitted.
+func (v *verifier) sign(ctx context.Context, job *verifyJob) (bool, error) {
+	if !v.signsFor(job.Address) {
+		// The key was rotated out while the job was queued
+		return false, nil
+	}
+	payload, err := v.queue.payload(job)
+	if err != nil {
+		return false, err
+	}
+	bean := new(state.EntireCode)
+	if err := json.Unmarshal(payload, bean); err != nil {
+		return false, err
+	}
+
+	root, err := v.stateRoot(ctx, bean)
+	if err != nil {
+		return false, fmt.Errorf("block verification failed: %w", err)
+	}
+
+	sig, err := v.signer.Sign(ctx, &remotesigner.SignRequest{Address: job.Address, Number: job.Number, StateRoot: root})
+	if errors.Is(err, remotesigner.ErrUnknownAddress) {
+		return false, nil
+	}
+	if errors.Is(err, remotesigner.ErrSlashable) {
+		// Retrying cannot help, the signer will never sign this root
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", errSigner, err)
+	}
+
+	res := api.AggSign{}
+	res.Number = job.Number
+	res.Address = job.Address
+	res.StateRoot = root
+	res.Sign = sig
+	if err := v.call("eth_submitSign", res); err != nil {
+		return false, fmt.Errorf("%w: %v", errSubmit, err)
+	}
+
+	v.mu.Lock()
+	v.last = verifiedBlock{Number: job.Number, Hash: bean.Entire.Header.Hash(), Root: root, Time: time.Now()}
+	v.mu.Unlock()
+	log.Info("Submitted signature", "number", job.Number, "address", res.Address, "root", root)
+	return true, nil
+}
+
+// handleResponse records the subscription id of a subscribe request and
+// reports failed requests
+func (v *verifier) handleResponse(msg *jsonrpcMessage) {
+	if msg.ID == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	addr, isSubscribe := v.pending[*msg.ID]
+	delete(v.pending, *msg.ID)
+
+	if msg.Error != nil {
+		log.Error("Request failed", "id", *msg.ID, "address", addr, "error", msg.Error.Message)
+		return
+	}
+	if !isSubscribe {
+		return
+	}
+	var id string
+	if err := json.Unmarshal(msg.Result, &id); err != nil {
+		log.Error("Invalid subscription id", "address", addr, "error", err)
+		return
+	}
+	v.subs[id] = addr
+	log.Info("Subscribed to mined blocks", "address", addr, "subscription", id)
+}
+
+// stateRoot verifies the block of msg, reusing the result if it was already
+// verified for another key
+func (v *verifier) stateRoot(ctx context.Context, msg *state.EntireCode) (types.Hash, error) {
+	hash := msg.Entire.Header.Hash()
+	v.mu.Lock()
+	root, ok := v.roots[hash]
+	v.mu.Unlock()
+	if ok {
+		return root, nil
+	}
+
+	root, err := witness.Verify(ctx, params.MainnetChainConfig, msg)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	v.mu.Lock()
+	if len(v.roots) >= rootCacheSize {
+		v.roots = make(map[types.Hash]types.Hash, rootCacheSize)
+	}
+	v.roots[hash] = root
+	v.mu.Unlock()
+	return root, nil
+}
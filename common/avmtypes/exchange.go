@@ -404,6 +404,8 @@ func (tx *Transaction) ToastTransaction(chainConfig *params.ChainConfig, blockNu
 	}
 
 	astTx := transaction.NewTx(inner)
+	// The pool and the block processor look the sender up by hash
+	transaction.CacheSender(astTx, *ToastAddress(&from))
 	return astTx, nil
 }
 
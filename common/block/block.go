@@ -265,6 +265,7 @@ func (b *Block) SendersToTxs(senders []types.Address) {
 	//todo
 }
 
+// Uncles returns nil, N42 blocks do not reference uncles.
 func (b *Block) Uncles() []*Header {
 	return nil
 }
@@ -73,6 +73,11 @@ type Header struct {
 	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
 	BaseFee *uint256.Int `json:"baseFeePerGas" rlp:"optional"`
 
+	// BlobGasUsed and ExcessBlobGas were added by EIP-4844. They are omitted
+	// while zero so that the hashes of older headers do not change.
+	BlobGasUsed   uint64 `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas uint64 `json:"excessBlobGas,omitempty"`
+
 	hash atomic.Value
 
 	Signature types.Signature `json:"signature"`
@@ -135,6 +140,9 @@ func (h *Header) ToProtoMessage() proto.Message {
 		Signature:   utils.ConvertSignatureToH768(h.Signature),
 		Bloom:       utils.ConvertBytesToH2048(h.Bloom.Bytes()),
 		MixDigest:   utils.ConvertHashToH256(h.MixDigest),
+
+		BlobGasUsed:   h.BlobGasUsed,
+		ExcessBlobGas: h.ExcessBlobGas,
 	}
 }
 
@@ -164,6 +172,8 @@ func (h *Header) FromProtoMessage(message proto.Message) error {
 	h.Signature = utils.ConvertH768ToSignature(pbHeader.Signature)
 	h.Bloom = utils.ConvertH2048ToBloom(pbHeader.Bloom)
 	h.MixDigest = utils.ConvertH256ToHash(pbHeader.MixDigest)
+	h.BlobGasUsed = pbHeader.BlobGasUsed
+	h.ExcessBlobGas = pbHeader.ExcessBlobGas
 	return nil
 }
 
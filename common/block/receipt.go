@@ -116,6 +116,10 @@ type Receipt struct {
 	BlockHash        types.Hash   `json:"blockHash,omitempty"`
 	BlockNumber      *uint256.Int `json:"blockNumber,omitempty"`
 	TransactionIndex uint         `json:"transactionIndex"`
+
+	// RevertReason is the return data of a reverted transaction, captured at
+	// execution time. It is kept in a side table, not in the receipt encoding.
+	RevertReason []byte `json:"-"`
 }
 
 func (r *Receipt) Marshal() ([]byte, error) {
@@ -17,10 +17,13 @@
 package common
 
 import (
+	"context"
+
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 )
 
@@ -30,6 +33,20 @@ type IHeaderChain interface {
 	GetBlockByHash(h types.Hash) (block.IBlock, error)
 }
 
+// IChainContextReader provides chain reads bound to a caller's context, so a
+// cancelled request stops before opening further database transactions.
+type IChainContextReader interface {
+	GetBlockContext(ctx context.Context, hash types.Hash, number uint64) (block.IBlock, error)
+	GetBlockByHashContext(ctx context.Context, h types.Hash) (block.IBlock, error)
+	GetBlockByNumberContext(ctx context.Context, number *uint256.Int) (block.IBlock, error)
+	GetHeaderByHashContext(ctx context.Context, h types.Hash) (block.IHeader, error)
+	GetHeaderByNumberContext(ctx context.Context, number *uint256.Int) (block.IHeader, error)
+	GetReceiptsContext(ctx context.Context, blockHash types.Hash) (block.Receipts, error)
+	GetLogsContext(ctx context.Context, blockHash types.Hash) ([][]*block.Log, error)
+	// StateAtContext is StateAt with reads that fail once ctx is done.
+	StateAtContext(ctx context.Context, tx kv.Tx, blockNr uint64) interface{}
+}
+
 // IBlockChain is the main blockchain interface.
 // It embeds ChainHeaderReader (defined in common/engine.go) to ensure
 // compatibility with consensus engine requirements.
@@ -40,8 +57,8 @@ type IHeaderChain interface {
 type IBlockChain interface {
 	IHeaderChain
 	ChainHeaderReader // Defined in common/engine.go
+	IChainContextReader
 
-	Blocks() []block.IBlock
 	Start() error
 	GenesisBlock() block.IBlock
 	NewBlockHandler(payload []byte, peer peer.ID) error
@@ -91,4 +108,11 @@ type IBlockChain interface {
 type IMiner interface {
 	Start()
 	PendingBlockAndReceipts() (block.IBlock, block.Receipts)
+	Mining() bool
+	Coinbase() types.Address
+	Hashrate() uint64
+	AddBundle(bundle *transaction.Bundle) error
+	Mine()
+	TimeOffset() int64
+	SetTimeOffset(offset int64)
 }
@@ -56,6 +56,12 @@ const (
 	BytesPerProof = 48
 )
 
+// Implemented reports whether this package verifies KZG proofs. The context
+// below has no trusted setup: commitments are plain hashes and proofs are not
+// checked, so nothing may be accepted on the strength of them until a real
+// backend (go-kzg-4844 or c-kzg) replaces it.
+const Implemented = false
+
 // Type aliases for clarity
 type (
 	Blob       = transaction.Blob
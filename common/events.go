@@ -20,6 +20,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
 )
 
 // NewLocalTxsEvent local txs
@@ -28,6 +29,17 @@ type NewLocalTxsEvent struct{ Txs []*transaction.Transaction }
 // NewTxsEvent txs
 type NewTxsEvent struct{ Txs []*transaction.Transaction }
 
+// DroppedTx is a transaction which left the pool without being included.
+type DroppedTx struct {
+	Tx          *transaction.Transaction
+	Reason      string     // Why the transaction was dropped, e.g. "replaced" or "underpriced"
+	Replacement types.Hash // Hash of the replacing transaction, if it was replaced
+}
+
+// DroppedTxsEvent is posted when the transaction pool replaces or discards
+// transactions.
+type DroppedTxsEvent struct{ Drops []DroppedTx }
+
 // NewLogsEvent new logs
 type NewLogsEvent struct{ Logs []*block.Log }
 
@@ -43,12 +55,37 @@ type PeerJoinEvent struct{ Peer peer.ID }
 // PeerDropEvent Peer drop
 type PeerDropEvent struct{ Peer peer.ID }
 
+// MinerStartedEvent is posted when the local miner starts sealing blocks.
+type MinerStartedEvent struct{ Coinbase types.Address }
+
+// MinerStoppedEvent is posted when the local miner stops sealing blocks.
+type MinerStoppedEvent struct{}
+
+// ChainSideEvent is posted on ChainSideTopic for an imported block that did
+// not become part of the canonical chain.
+type ChainSideEvent struct {
+	Block block.Block
+}
+
+// DeepReorgEvent is posted when a reorg deeper than the configured limit is
+// refused, or followed because the operator allowed it.
+type DeepReorgEvent struct {
+	CommonBlock types.Hash // last block both chains share
+	Number      uint64     // number of the common block
+	OldHead     types.Hash
+	NewHead     types.Hash
+	Depth       uint64 // canonical blocks the reorg drops
+	Overridden  bool   // whether the reorg went ahead on operator override
+}
+
 // DownloaderStartEvent start download
 type DownloaderStartEvent struct{}
 
 // DownloaderFinishEvent finish download
 type DownloaderFinishEvent struct{}
 
+// ChainHighestBlock is posted on ChainHeadTopic when the canonical head
+// changes, once per imported batch.
 type ChainHighestBlock struct {
 	Block    block.Block
 	Inserted bool
@@ -147,6 +147,7 @@ type ITxsPool interface {
 	GetTx(hash types.Hash) *transaction.Transaction
 	AddRemotes(txs []*transaction.Transaction) []error
 	AddLocal(tx *transaction.Transaction) error
+	DropLocal(hash types.Hash) error
 	Stats() (int, int, int, int)
 	Nonce(addr types.Address) uint64
 	Content() (map[types.Address][]*transaction.Transaction, map[types.Address][]*transaction.Transaction)
@@ -61,6 +61,15 @@ func (i *HexOrDecimal256) UnmarshalText(input []byte) error {
 	return nil
 }
 
+// UnmarshalJSON implements json.Unmarshaler. Besides quoted hex or decimal
+// strings it accepts plain JSON numbers.
+func (i *HexOrDecimal256) UnmarshalJSON(input []byte) error {
+	if len(input) > 1 && input[0] == '"' {
+		input = input[1 : len(input)-1]
+	}
+	return i.UnmarshalText(input)
+}
+
 // MarshalText implements encoding.TextMarshaler.
 func (i *HexOrDecimal256) MarshalText() ([]byte, error) {
 	if i == nil {
@@ -26,21 +26,15 @@ import (
 
 	"net/http"
 	"sort"
+	"sync"
 )
 
 // Handler returns an HTTP handler which dump metrics in Prometheus format.
 // Output format can be cheched here: https://o11y.tools/metricslint/
 func Handler(reg Registry) http.Handler {
-	prometheus.DefaultRegisterer.MustRegister(defaultSet)
+	registerDefaultSet()
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Gather and pre-sort the metrics to avoid random listings
-		var names []string
-		reg.Each(func(name string, i interface{}) {
-			names = append(names, name)
-		})
-		sort.Strings(names)
-
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 
 		metrics2.WritePrometheus(w, true)
@@ -55,41 +49,61 @@ func Handler(reg Registry) http.Handler {
 			enc.Encode(m)
 		}
 
-		// Aggregate all the metris into a Prometheus collector
-		c := newCollector()
-		c.buff.WriteRune('\n')
-
-		var typeName string
-		var prevTypeName string
-
-		for _, name := range names {
-			i := reg.Get(name)
-
-			typeName = stripLabels(name)
-
-			switch m := i.(type) {
-			case *metrics2.Counter:
-				if m.IsGauge() {
-					c.writeGauge(name, m.Get(), typeName != prevTypeName)
-				} else {
-					c.writeCounter(name, m.Get(), typeName != prevTypeName)
-				}
-			case *metrics2.Gauge:
-				c.writeGauge(name, m, typeName != prevTypeName)
-			case *metrics2.FloatCounter:
-				c.writeFloatCounter(name, m, typeName != prevTypeName)
-			case *metrics2.Histogram:
-				c.writeHistogram(name, m, typeName != prevTypeName)
-			case *metrics2.Summary:
-				c.writeTimer(name, m, typeName != prevTypeName)
-			default:
-				log.Warn("Unknown Prometheus metric type", "type", fmt.Sprintf("%T", i))
-			}
-
-			prevTypeName = typeName
-		}
+		c := collect(reg)
 		w.Header().Add("Content-Type", "text/plain")
 		w.Header().Add("Content-Length", fmt.Sprint(c.buff.Len()))
 		w.Write(c.buff.Bytes())
 	})
 }
+
+var registerOnce sync.Once
+
+// registerDefaultSet exposes the metrics of defaultSet through the Prometheus
+// client gatherer. It is safe to call more than once.
+func registerDefaultSet() {
+	registerOnce.Do(func() { prometheus.DefaultRegisterer.MustRegister(defaultSet) })
+}
+
+// collect aggregates all the metrics of reg into a Prometheus collector.
+func collect(reg Registry) *collector {
+	// Gather and pre-sort the metrics to avoid random listings
+	var names []string
+	reg.Each(func(name string, i interface{}) {
+		names = append(names, name)
+	})
+	sort.Strings(names)
+
+	c := newCollector()
+	c.buff.WriteRune('\n')
+
+	var typeName string
+	var prevTypeName string
+
+	for _, name := range names {
+		i := reg.Get(name)
+
+		typeName = stripLabels(name)
+
+		switch m := i.(type) {
+		case *metrics2.Counter:
+			if m.IsGauge() {
+				c.writeGauge(name, m.Get(), typeName != prevTypeName)
+			} else {
+				c.writeCounter(name, m.Get(), typeName != prevTypeName)
+			}
+		case *metrics2.Gauge:
+			c.writeGauge(name, m, typeName != prevTypeName)
+		case *metrics2.FloatCounter:
+			c.writeFloatCounter(name, m, typeName != prevTypeName)
+		case *metrics2.Histogram:
+			c.writeHistogram(name, m, typeName != prevTypeName)
+		case *metrics2.Summary:
+			c.writeTimer(name, m, typeName != prevTypeName)
+		default:
+			log.Warn("Unknown Prometheus metric type", "type", fmt.Sprintf("%T", i))
+		}
+
+		prevTypeName = typeName
+	}
+	return c
+}
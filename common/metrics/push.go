@@ -0,0 +1,292 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics2 "github.com/VictoriaMetrics/metrics"
+	"github.com/n42blockchain/N42/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+// pushTimeout bounds a single push so that a stalled backend does not delay
+// the next report.
+const pushTimeout = 10 * time.Second
+
+// Reporter ships a snapshot of the metrics to an external backend.
+type Reporter interface {
+	Name() string
+	Report(ctx context.Context, families []*dto.MetricFamily) error
+}
+
+// Gather collects the metrics served by Handler as metric families. Families
+// exported by more than one source are reported once.
+func Gather(reg Registry) ([]*dto.MetricFamily, error) {
+	registerDefaultSet()
+
+	byName := make(map[string]*dto.MetricFamily)
+	add := func(families []*dto.MetricFamily) {
+		for _, mf := range families {
+			if _, ok := byName[mf.GetName()]; !ok {
+				byName[mf.GetName()] = mf
+			}
+		}
+	}
+
+	gathered, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	add(gathered)
+
+	var buf bytes.Buffer
+	metrics2.WritePrometheus(&buf, true)
+	buf.Write(collect(reg).buff.Bytes())
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+	parsed, err := parser.TextToMetricFamilies(&buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, mf := range parsed {
+		add([]*dto.MetricFamily{mf})
+	}
+
+	families := make([]*dto.MetricFamily, 0, len(byName))
+	for _, mf := range byName {
+		families = append(families, mf)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].GetName() < families[j].GetName() })
+	return families, nil
+}
+
+// Push reports the metrics of reg to every reporter once per interval until
+// ctx is cancelled. A final report is sent on cancellation so that short-lived
+// nodes do not lose their last samples.
+func Push(ctx context.Context, reg Registry, interval time.Duration, reporters ...Reporter) {
+	push := func() {
+		families, err := Gather(reg)
+		if err != nil {
+			log.Warn("Failed to gather metrics", "err", err)
+			return
+		}
+		for _, r := range reporters {
+			pctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+			if err := r.Report(pctx, families); err != nil {
+				log.Warn("Failed to push metrics", "reporter", r.Name(), "err", err)
+			}
+			cancel()
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			push()
+		case <-ctx.Done():
+			push()
+			return
+		}
+	}
+}
+
+// PushGateway pushes metrics to a Prometheus pushgateway, replacing the
+// previous samples of the job on every report.
+type PushGateway struct {
+	url    string
+	client *http.Client
+}
+
+// NewPushGateway creates a reporter pushing to the given job and instance
+// grouping of the pushgateway at endpoint.
+func NewPushGateway(endpoint, job, instance string) *PushGateway {
+	u := strings.TrimSuffix(endpoint, "/") + "/metrics/job/" + url.PathEscape(job)
+	if instance != "" {
+		u += "/instance/" + url.PathEscape(instance)
+	}
+	return &PushGateway{url: u, client: &http.Client{}}
+}
+
+func (p *PushGateway) Name() string { return "pushgateway" }
+
+func (p *PushGateway) Report(ctx context.Context, families []*dto.MetricFamily) error {
+	var buf bytes.Buffer
+	enc := expfmt.NewEncoder(&buf, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	return send(p.client, req)
+}
+
+// InfluxDB writes metrics to an InfluxDB server using the line protocol. It
+// talks to the v1 write API unless a token is configured, in which case the
+// v2 API is used with the organization and bucket.
+type InfluxDB struct {
+	url    string
+	token  string
+	user   string
+	pass   string
+	tags   string
+	client *http.Client
+}
+
+// NewInfluxDB creates a reporter for an InfluxDB v1 database.
+func NewInfluxDB(endpoint, database, username, password string, tags map[string]string) *InfluxDB {
+	q := url.Values{"db": {database}, "precision": {"ms"}}
+	return &InfluxDB{
+		url:    strings.TrimSuffix(endpoint, "/") + "/write?" + q.Encode(),
+		user:   username,
+		pass:   password,
+		tags:   formatTags(tags),
+		client: &http.Client{},
+	}
+}
+
+// NewInfluxDBV2 creates a reporter for an InfluxDB v2 bucket.
+func NewInfluxDBV2(endpoint, token, organization, bucket string, tags map[string]string) *InfluxDB {
+	q := url.Values{"org": {organization}, "bucket": {bucket}, "precision": {"ms"}}
+	return &InfluxDB{
+		url:    strings.TrimSuffix(endpoint, "/") + "/api/v2/write?" + q.Encode(),
+		token:  token,
+		tags:   formatTags(tags),
+		client: &http.Client{},
+	}
+}
+
+func (i *InfluxDB) Name() string { return "influxdb" }
+
+func (i *InfluxDB) Report(ctx context.Context, families []*dto.MetricFamily) error {
+	var buf bytes.Buffer
+	writeLineProtocol(&buf, families, i.tags, time.Now())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	} else if i.user != "" {
+		req.SetBasicAuth(i.user, i.pass)
+	}
+	return send(i.client, req)
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// writeLineProtocol encodes families as InfluxDB points. Every metric becomes
+// a point of the family's measurement tagged with its labels; counters and
+// gauges carry a single value field, summaries and histograms their count,
+// sum and one field per quantile or bucket.
+func writeLineProtocol(w *bytes.Buffer, families []*dto.MetricFamily, tags string, now time.Time) {
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			var fields []string
+			field := func(key string, v float64) {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					return
+				}
+				fields = append(fields, escapeKey(key)+"="+strconv.FormatFloat(v, 'g', -1, 64))
+			}
+			switch {
+			case m.Counter != nil:
+				field("value", m.GetCounter().GetValue())
+			case m.Gauge != nil:
+				field("value", m.GetGauge().GetValue())
+			case m.Untyped != nil:
+				field("value", m.GetUntyped().GetValue())
+			case m.Summary != nil:
+				field("count", float64(m.GetSummary().GetSampleCount()))
+				field("sum", m.GetSummary().GetSampleSum())
+				for _, q := range m.GetSummary().GetQuantile() {
+					field("p"+strconv.FormatFloat(q.GetQuantile()*100, 'f', -1, 64), q.GetValue())
+				}
+			case m.Histogram != nil:
+				field("count", float64(m.GetHistogram().GetSampleCount()))
+				field("sum", m.GetHistogram().GetSampleSum())
+				for _, b := range m.GetHistogram().GetBucket() {
+					field("le_"+strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64), float64(b.GetCumulativeCount()))
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			w.WriteString(escapeKey(mf.GetName()))
+			w.WriteString(tags)
+			for _, l := range m.GetLabel() {
+				if l.GetValue() == "" {
+					continue
+				}
+				w.WriteString("," + escapeKey(l.GetName()) + "=" + escapeKey(l.GetValue()))
+			}
+			w.WriteString(" " + strings.Join(fields, ",") + " " + ts + "\n")
+		}
+	}
+}
+
+// formatTags renders tags as a sorted line protocol tag set.
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString("," + escapeKey(k) + "=" + escapeKey(tags[k]))
+	}
+	return b.String()
+}
+
+var keyEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+func escapeKey(s string) string {
+	return keyEscaper.Replace(s)
+}
@@ -0,0 +1,138 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func testFamilies() []*dto.MetricFamily {
+	return []*dto.MetricFamily{
+		{
+			Name: proto.String("chain_head_block"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Gauge: &dto.Gauge{Value: proto.Float64(42)},
+			}},
+		},
+		{
+			Name: proto.String("db_pages"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{{Name: proto.String("type"), Value: proto.String("leaf page")}},
+				Gauge: &dto.Gauge{Value: proto.Float64(7)},
+			}},
+		},
+		{
+			Name: proto.String("chain_insert_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(3),
+					SampleSum:   proto.Float64(1.5),
+					Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)}},
+				},
+			}},
+		},
+	}
+}
+
+func TestWriteLineProtocol(t *testing.T) {
+	var buf bytes.Buffer
+	writeLineProtocol(&buf, testFamilies(), formatTags(map[string]string{"host": "a", "env": "bench"}), time.UnixMilli(1000))
+
+	want := strings.Join([]string{
+		"chain_head_block,env=bench,host=a value=42 1000",
+		`db_pages,env=bench,host=a,type=leaf\ page value=7 1000`,
+		"chain_insert_seconds,env=bench,host=a count=3,sum=1.5,le_0.5=2 1000",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("line protocol mismatch\nhave:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestReporters(t *testing.T) {
+	var (
+		method, path, auth string
+		body               []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.RequestURI(), r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := NewPushGateway(srv.URL, "n42", "node-1").Report(ctx, testFamilies()); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/n42/instance/node-1" {
+		t.Errorf("pushgateway request %s %s", method, path)
+	}
+	if !strings.Contains(string(body), "chain_head_block 42") {
+		t.Errorf("pushgateway body missing sample:\n%s", body)
+	}
+
+	if err := NewInfluxDBV2(srv.URL, "secret", "org", "bucket", nil).Report(ctx, testFamilies()); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || !strings.HasPrefix(path, "/api/v2/write?") || auth != "Token secret" {
+		t.Errorf("influxdb v2 request %s %s auth %q", method, path, auth)
+	}
+	if !strings.Contains(string(body), "chain_head_block value=42 ") {
+		t.Errorf("influxdb body missing sample:\n%s", body)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database not found", http.StatusNotFound)
+	})
+	err := NewInfluxDB(srv.URL, "n42", "", "", nil).Report(ctx, testFamilies())
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Errorf("expected backend error, got %v", err)
+	}
+}
+
+func TestGather(t *testing.T) {
+	GetOrCreateCounter("push_test_gauge", true).Set(3)
+	GetOrCreateGaugeFunc(`push_test_func{type="a"}`, func() float64 { return 2 })
+
+	families, err := Gather(DefaultRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, mf := range families {
+		found[mf.GetName()] = true
+	}
+	for _, name := range []string{"push_test_gauge", "push_test_func"} {
+		if !found[name] {
+			t.Errorf("metric %s missing from %d gathered families", name, len(families))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SyncStage is the number of blocks that passed one stage of block import and
+// the time spent on them.
+type SyncStage struct {
+	Blocks   uint64
+	Duration time.Duration
+}
+
+// SyncStageMeter accumulates the blocks and time of one import stage.
+type SyncStageMeter struct {
+	blocks atomic.Uint64
+	nanos  atomic.Int64
+}
+
+// Observe records that blocks passed the stage in d.
+func (m *SyncStageMeter) Observe(blocks int, d time.Duration) {
+	m.blocks.Add(uint64(blocks))
+	m.nanos.Add(int64(d))
+}
+
+// Snapshot returns the totals recorded so far.
+func (m *SyncStageMeter) Snapshot() SyncStage {
+	return SyncStage{Blocks: m.blocks.Load(), Duration: time.Duration(m.nanos.Load())}
+}
+
+// SyncStages are the stages a downloaded block goes through. The downloader
+// records headers and bodies, the chain records execution and indexing, which
+// covers writing the block, its receipts and its state. Concurrent requests
+// add up, so a download stage may report more time than has passed.
+type SyncStages struct {
+	Headers   SyncStage
+	Bodies    SyncStage
+	Execution SyncStage
+	Indexing  SyncStage
+}
+
+// Sub returns the blocks and time spent between base and s.
+func (s SyncStages) Sub(base SyncStages) SyncStages {
+	sub := func(a, b SyncStage) SyncStage {
+		return SyncStage{Blocks: a.Blocks - b.Blocks, Duration: a.Duration - b.Duration}
+	}
+	return SyncStages{
+		Headers:   sub(s.Headers, base.Headers),
+		Bodies:    sub(s.Bodies, base.Bodies),
+		Execution: sub(s.Execution, base.Execution),
+		Indexing:  sub(s.Indexing, base.Indexing),
+	}
+}
+
+// Process wide stage meters, fed by whichever component runs the stage.
+var (
+	SyncHeadersMeter   = new(SyncStageMeter)
+	SyncBodiesMeter    = new(SyncStageMeter)
+	SyncExecutionMeter = new(SyncStageMeter)
+	SyncIndexingMeter  = new(SyncStageMeter)
+)
+
+// ReadSyncStages returns the totals of all stage meters.
+func ReadSyncStages() SyncStages {
+	return SyncStages{
+		Headers:   SyncHeadersMeter.Snapshot(),
+		Bodies:    SyncBodiesMeter.Snapshot(),
+		Execution: SyncExecutionMeter.Snapshot(),
+		Indexing:  SyncIndexingMeter.Snapshot(),
+	}
+}
+
+// SyncProgress is the state of a running sync.
+type SyncProgress struct {
+	StartingBlock uint64 // head when the sync started
+	CurrentBlock  uint64 // current head
+	HighestBlock  uint64 // block the sync is heading to
+	Stages        SyncStages
+}
+
+// ISyncer reports the progress of block download.
+type ISyncer interface {
+	// Progress returns the progress of the running sync and false when the
+	// node is not syncing.
+	Progress() (SyncProgress, bool)
+}
@@ -0,0 +1,48 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
+)
+
+// Typed topics of the chain, log and transaction events. The chain publishes
+// heads, side blocks and refused deep reorgs, the chain and the miner publish
+// logs, and the transaction pool publishes transactions.
+var (
+	ChainHeadTopic   = eventv3.NewTopic[ChainHighestBlock]("chain_head")
+	ChainSideTopic   = eventv3.NewTopic[ChainSideEvent]("chain_side")
+	DeepReorgTopic   = eventv3.NewTopic[DeepReorgEvent]("deep_reorg")
+	NewLogsTopic     = eventv3.NewTopic[NewLogsEvent]("logs")
+	RemovedLogsTopic = eventv3.NewTopic[RemovedLogsEvent]("removed_logs")
+	PendingLogsTopic = eventv3.NewTopic[NewPendingLogsEvent]("pending_logs")
+	NewTxsTopic      = eventv3.NewTopic[NewTxsEvent]("txs")
+	NewLocalTxsTopic = eventv3.NewTopic[NewLocalTxsEvent]("local_txs")
+	DroppedTxsTopic  = eventv3.NewTopic[DroppedTxsEvent]("dropped_txs")
+)
+
+// Typed topics of the sync and mining state. The downloader and the initial
+// sync publish when they start and finish, which the miner waits for, and the
+// miner publishes when it starts and stops sealing and the entire state of
+// every block it seals.
+var (
+	DownloaderStartTopic  = eventv3.NewTopic[DownloaderStartEvent]("downloader_start")
+	DownloaderFinishTopic = eventv3.NewTopic[DownloaderFinishEvent]("downloader_finish")
+	MinerStartedTopic     = eventv3.NewTopic[MinerStartedEvent]("miner_started")
+	MinerStoppedTopic     = eventv3.NewTopic[MinerStoppedEvent]("miner_stopped")
+	MinedEntireTopic      = eventv3.NewTopic[MinedEntireEvent]("mined_entire")
+)
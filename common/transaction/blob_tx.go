@@ -22,6 +22,8 @@ package transaction
 
 import (
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common/avmutil"
 	"github.com/n42blockchain/N42/common/hash"
 	"github.com/n42blockchain/N42/common/types"
 )
@@ -74,18 +76,20 @@ type BlobTx struct {
 	GasFeeCap  *uint256.Int   // Max fee per gas (EIP-1559)
 	Gas        uint64         // Gas limit
 	To         types.Address  // Recipient (cannot be nil for blob tx)
+	From       *types.Address `rlp:"nil"` // Sender, set like the other tx types
 	Value      *uint256.Int   // Wei amount
 	Data       []byte         // Call data
 	AccessList AccessList     // EIP-2930 access list
-	
+
 	// EIP-4844 specific fields
-	BlobFeeCap *uint256.Int   // Max fee per blob gas
-	BlobHashes []types.Hash   // Versioned blob hashes
+	BlobFeeCap *uint256.Int // Max fee per blob gas
+	BlobHashes []types.Hash // Versioned blob hashes
 
 	// Signature values
-	V *uint256.Int
-	R *uint256.Int
-	S *uint256.Int
+	Sign []byte
+	V    *uint256.Int
+	R    *uint256.Int
+	S    *uint256.Int
 
 	// Sidecar (optional, for transaction propagation)
 	Sidecar *BlobTxSidecar `rlp:"-"` // Not RLP encoded in network messages
@@ -117,7 +121,9 @@ func (tx *BlobTx) copy() TxData {
 	cpy := &BlobTx{
 		Nonce: tx.Nonce,
 		To:    tx.To,
+		From:  copyAddressPtr(tx.From),
 		Data:  make([]byte, len(tx.Data)),
+		Sign:  avmutil.CopyBytes(tx.Sign),
 		Gas:   tx.Gas,
 		// Initialize uint256 fields
 		ChainID:    new(uint256.Int),
@@ -180,8 +186,8 @@ func (tx *BlobTx) gasFeeCap() *uint256.Int { return tx.GasFeeCap }
 func (tx *BlobTx) value() *uint256.Int     { return tx.Value }
 func (tx *BlobTx) nonce() uint64           { return tx.Nonce }
 func (tx *BlobTx) to() *types.Address      { return &tx.To }
-func (tx *BlobTx) from() *types.Address    { return nil } // Computed from signature
-func (tx *BlobTx) sign() []byte            { return nil }
+func (tx *BlobTx) from() *types.Address    { return tx.From }
+func (tx *BlobTx) sign() []byte            { return tx.Sign }
 
 func (tx *BlobTx) hash() types.Hash {
 	return hash.PrefixedRlpHash(BlobTxType, []interface{}{
@@ -245,6 +251,64 @@ func (tx *BlobTx) HasSidecar() bool {
 	return tx.Sidecar != nil && len(tx.Sidecar.Blobs) > 0
 }
 
+// WithoutSidecar returns a copy of the transaction without its blobs, as it is
+// included in a block.
+func (tx *BlobTx) WithoutSidecar() *BlobTx {
+	cpy := tx.copy().(*BlobTx)
+	cpy.Sidecar = nil
+	return cpy
+}
+
+// blobTxToProto sets the blob fields of pbTx. The sidecar is only encoded
+// while the transaction is gossiped, blocks carry the versioned hashes alone.
+func blobTxToProto(tx *BlobTx, pbTx *types_pb.Transaction) {
+	if tx.BlobFeeCap != nil {
+		pbTx.BlobFeeCap = tx.BlobFeeCap.Bytes()
+	}
+	pbTx.BlobHashes = make([][]byte, len(tx.BlobHashes))
+	for i := range tx.BlobHashes {
+		pbTx.BlobHashes[i] = tx.BlobHashes[i].Bytes()
+	}
+	if tx.Sidecar == nil {
+		return
+	}
+	for i := range tx.Sidecar.Blobs {
+		pbTx.Blobs = append(pbTx.Blobs, tx.Sidecar.Blobs[i][:])
+	}
+	for i := range tx.Sidecar.Commitments {
+		pbTx.Commitments = append(pbTx.Commitments, tx.Sidecar.Commitments[i][:])
+	}
+	for i := range tx.Sidecar.Proofs {
+		pbTx.Proofs = append(pbTx.Proofs, tx.Sidecar.Proofs[i][:])
+	}
+}
+
+// blobSidecarFromProto decodes the sidecar of pbTx, or returns nil if it has
+// none.
+func blobSidecarFromProto(pbTx *types_pb.Transaction) (*BlobTxSidecar, error) {
+	if len(pbTx.Blobs) == 0 && len(pbTx.Commitments) == 0 && len(pbTx.Proofs) == 0 {
+		return nil, nil
+	}
+	n := len(pbTx.Blobs)
+	if len(pbTx.Commitments) != n || len(pbTx.Proofs) != n || n > MaxBlobsPerBlock {
+		return nil, ErrInvalidBlobSidecar
+	}
+	sidecar := &BlobTxSidecar{
+		Blobs:       make([]Blob, n),
+		Commitments: make([]Commitment, n),
+		Proofs:      make([]Proof, n),
+	}
+	for i := 0; i < n; i++ {
+		if len(pbTx.Blobs[i]) != BlobSize || len(pbTx.Commitments[i]) != len(Commitment{}) || len(pbTx.Proofs[i]) != len(Proof{}) {
+			return nil, ErrInvalidBlobSidecar
+		}
+		copy(sidecar.Blobs[i][:], pbTx.Blobs[i])
+		copy(sidecar.Commitments[i][:], pbTx.Commitments[i])
+		copy(sidecar.Proofs[i][:], pbTx.Proofs[i])
+	}
+	return sidecar, nil
+}
+
 // =============================================================================
 // BlobTxSidecar Methods
 // =============================================================================
@@ -383,6 +447,9 @@ var (
 
 	// ErrBlobSidecarMissing is returned when blob sidecar is required but missing
 	ErrBlobSidecarMissing = &blobError{"blob sidecar missing"}
+
+	// ErrInvalidBlobSidecar is returned when a sidecar is malformed
+	ErrInvalidBlobSidecar = &blobError{"invalid blob sidecar"}
 )
 
 type blobError struct {
@@ -392,4 +459,3 @@ type blobError struct {
 func (e *blobError) Error() string {
 	return e.msg
 }
-
@@ -17,10 +17,14 @@
 package transaction
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/types"
+	"google.golang.org/protobuf/proto"
 )
 
 // =============================================================================
@@ -397,3 +401,80 @@ func BenchmarkBlobTxSidecarCopy(b *testing.B) {
 	}
 }
 
+func TestBlobTxProtoRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sidecar := &BlobTxSidecar{Blobs: make([]Blob, 2), Commitments: make([]Commitment, 2), Proofs: make([]Proof, 2)}
+	sidecar.Blobs[1][0], sidecar.Commitments[1][0], sidecar.Proofs[1][0] = 1, 2, 3
+	signer := NewCancunSigner(big.NewInt(7))
+	tx, err := SignNewTx(key, signer, &BlobTx{
+		ChainID:    uint256.NewInt(7),
+		Nonce:      3,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(10),
+		Gas:        21000,
+		To:         types.Address{0x01},
+		From:       &from,
+		Value:      uint256.NewInt(5),
+		BlobFeeCap: uint256.NewInt(100),
+		BlobHashes: []types.Hash{{0x01, 0x0a}, {0x01, 0x0b}},
+		Sidecar:    sidecar,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := Sender(signer, tx); err != nil || sender != from {
+		t.Fatalf("sender = %v, %v; want %v", sender, err, from)
+	}
+
+	data, err := tx.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pbTx types_pb.Transaction
+	if err := proto.Unmarshal(data, &pbTx); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := FromProtoMessage(&pbTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch: have %v, want %v", decoded.Hash(), tx.Hash())
+	}
+	if decoded.BlobFeeCap().Uint64() != 100 || len(decoded.BlobHashes()) != 2 || decoded.BlobHashes()[1] != tx.BlobHashes()[1] {
+		t.Errorf("blob fields not decoded: %v %v", decoded.BlobFeeCap(), decoded.BlobHashes())
+	}
+	if got := decoded.BlobTxSidecar(); got == nil || got.Blobs[1][0] != 1 || got.Commitments[1][0] != 2 || got.Proofs[1][0] != 3 {
+		t.Error("sidecar not decoded")
+	}
+
+	// Blocks carry the transaction without its sidecar
+	stripped := tx.WithoutBlobTxSidecar()
+	if stripped.BlobTxSidecar() != nil || tx.BlobTxSidecar() == nil {
+		t.Fatal("sidecar not stripped from a copy")
+	}
+	if stripped.Hash() != tx.Hash() || stripped.Size() >= tx.Size() {
+		t.Errorf("stripped tx: hash %v, size %d (was %d)", stripped.Hash(), stripped.Size(), tx.Size())
+	}
+	if cost := tx.Cost().Uint64(); cost != 10*21000+5+100*2*BlobTxBlobGasPerBlob {
+		t.Errorf("cost = %d", cost)
+	}
+}
+
+func TestBlobSidecarFromProtoInvalid(t *testing.T) {
+	pbTx := &types_pb.Transaction{
+		Blobs:       [][]byte{make([]byte, BlobSize)},
+		Commitments: [][]byte{make([]byte, 48)},
+	}
+	if _, err := blobSidecarFromProto(pbTx); err != ErrInvalidBlobSidecar {
+		t.Errorf("missing proof: got %v", err)
+	}
+	pbTx.Proofs = [][]byte{make([]byte, 47)}
+	if _, err := blobSidecarFromProto(pbTx); err != ErrInvalidBlobSidecar {
+		t.Errorf("short proof: got %v", err)
+	}
+}
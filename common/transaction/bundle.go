@@ -0,0 +1,68 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// Bundle is an ordered list of transactions, e.g. of a searcher, which a block
+// producer includes atomically at the top of the block numbered BlockNumber.
+type Bundle struct {
+	Txs               []*Transaction
+	BlockNumber       uint64
+	MinTimestamp      uint64       // Earliest block timestamp, 0 for any
+	MaxTimestamp      uint64       // Latest block timestamp, 0 for any
+	RevertingTxHashes []types.Hash // Transactions allowed to revert without invalidating the bundle
+}
+
+// Hash returns the keccak256 hash of the concatenated transaction hashes.
+func (b *Bundle) Hash() types.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*types.HashLength)
+	for _, tx := range b.Txs {
+		hash := tx.Hash()
+		hashes = append(hashes, hash[:]...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// CanRevert reports whether the transaction hash may revert without
+// invalidating the bundle.
+func (b *Bundle) CanRevert(hash types.Hash) bool {
+	for _, h := range b.RevertingTxHashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Eligible reports whether the bundle may be included in the block with the
+// given number and timestamp.
+func (b *Bundle) Eligible(number, timestamp uint64) bool {
+	if b.BlockNumber != number {
+		return false
+	}
+	if b.MinTimestamp != 0 && timestamp < b.MinTimestamp {
+		return false
+	}
+	return b.MaxTimestamp == 0 || timestamp <= b.MaxTimestamp
+}
+
+// MaxBundleTxs is the maximum number of transactions of a bundle.
+const MaxBundleTxs = 64
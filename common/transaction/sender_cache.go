@@ -0,0 +1,56 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	lru "github.com/hashicorp/golang-lru/v2"
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// senderCacheSize bounds the recovered senders kept across transaction
+// copies; a few full blocks plus the pending pool fit comfortably.
+const senderCacheSize = 64 * 1024
+
+var (
+	senderCacheHits   = prometheus.GetOrCreateCounter(`tx_sender_cache_hits_total`)
+	senderCacheMisses = prometheus.GetOrCreateCounter(`tx_sender_cache_misses_total`)
+
+	// senders maps transaction hashes to their recovered senders. The
+	// per-transaction cache in Transaction.from is lost whenever a
+	// transaction is decoded again, e.g. when a pooled transaction comes
+	// back in a block or is read from the database for an RPC call.
+	senders, _ = lru.New[types.Hash, types.Address](senderCacheSize)
+)
+
+// CacheSender records the sender recovered from tx's signature outside of
+// Sender, so later lookups of any copy of tx skip the recovery.
+func CacheSender(tx *Transaction, from types.Address) {
+	senders.Add(tx.Hash(), from)
+}
+
+// cachedSender returns the sender recovered earlier for a transaction with
+// the same hash.
+func cachedSender(tx *Transaction) (types.Address, bool) {
+	from, ok := senders.Get(tx.Hash())
+	if ok {
+		senderCacheHits.Inc()
+	} else {
+		senderCacheMisses.Inc()
+	}
+	return from, ok
+}
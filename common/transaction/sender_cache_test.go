@@ -0,0 +1,72 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// TestSenderCache checks that a sender recovered once is served by hash to
+// other copies of the transaction, and that carried senders win.
+func TestSenderCache(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	signer := NewLondonSigner(big.NewInt(7))
+	tx, err := SignNewTx(key, signer, &DynamicFeeTx{
+		ChainID:   uint256.NewInt(7),
+		Nonce:     1,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(10),
+		Gas:       21000,
+		To:        &types.Address{0x01},
+		Value:     uint256.NewInt(5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := tx.Sender(signer); err != nil || sender != from {
+		t.Fatalf("sender = %v, %v; want %v", sender, err, from)
+	}
+
+	// A decoded copy has no per-transaction cache
+	cpy := NewTx(tx.inner)
+	hits := senderCacheHits.Get()
+	if sender, err := Sender(signer, cpy); err != nil || sender != from {
+		t.Fatalf("copy sender = %v, %v; want %v", sender, err, from)
+	}
+	if senderCacheHits.Get() != hits+1 {
+		t.Fatal("copy sender recovered again")
+	}
+	msg, err := NewTx(tx.inner).AsMessage(signer, nil)
+	if err != nil || msg.From() != from {
+		t.Fatalf("message from = %v, %v; want %v", msg.From(), err, from)
+	}
+
+	carried := types.Address{0x02}
+	cpy.SetFrom(carried)
+	if sender, err := cpy.Sender(signer); err != nil || sender != carried {
+		t.Fatalf("carried sender = %v, %v; want %v", sender, err, carried)
+	}
+}
@@ -22,10 +22,13 @@ package transaction
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"errors"
 	"math/big"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common/avmutil"
 	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/hash"
 	"github.com/n42blockchain/N42/common/rlp"
@@ -56,12 +59,12 @@ var (
 // Authorization represents an EIP-7702 authorization tuple.
 // It allows an EOA to temporarily delegate its code to a contract address.
 type Authorization struct {
-	ChainID uint64         `json:"chainId"` // Chain ID of the authorization
-	Address types.Address  `json:"address"` // Contract address to delegate to
-	Nonce   uint64         `json:"nonce"`   // Nonce of the authorizing account
-	V       *uint256.Int   `json:"v"`       // Signature V value
-	R       *uint256.Int   `json:"r"`       // Signature R value
-	S       *uint256.Int   `json:"s"`       // Signature S value
+	ChainID uint64        `json:"chainId"` // Chain ID of the authorization
+	Address types.Address `json:"address"` // Contract address to delegate to
+	Nonce   uint64        `json:"nonce"`   // Nonce of the authorizing account
+	V       *uint256.Int  `json:"v"`       // Signature V value
+	R       *uint256.Int  `json:"r"`       // Signature R value
+	S       *uint256.Int  `json:"s"`       // Signature S value
 }
 
 // Copy creates a deep copy of the authorization
@@ -169,15 +172,16 @@ type SetCodeTx struct {
 	Data       []byte            // Call data
 	AccessList AccessList        // Access list
 	AuthList   AuthorizationList // Authorization list for EIP-7702
+	From       *types.Address    // Sender, set like the other tx types
 
 	// Signature values
-	V *uint256.Int
-	R *uint256.Int
-	S *uint256.Int
+	Sign []byte
+	V    *uint256.Int
+	R    *uint256.Int
+	S    *uint256.Int
 
 	// Derived fields (cached)
-	txHash     types.Hash
-	fromCache  *types.Address
+	txHash types.Hash
 }
 
 // txType returns the transaction type
@@ -191,6 +195,8 @@ func (tx *SetCodeTx) copy() TxData {
 		Data:       make([]byte, len(tx.Data)),
 		AccessList: copyAccessList(tx.AccessList),
 		AuthList:   tx.AuthList.Copy(),
+		From:       copyAddressPtr(tx.From),
+		Sign:       avmutil.CopyBytes(tx.Sign),
 	}
 	copy(cpy.Data, tx.Data)
 
@@ -232,8 +238,8 @@ func (tx *SetCodeTx) gasFeeCap() *uint256.Int { return tx.GasFeeCap }
 func (tx *SetCodeTx) value() *uint256.Int     { return tx.Value }
 func (tx *SetCodeTx) nonce() uint64           { return tx.Nonce }
 func (tx *SetCodeTx) to() *types.Address      { return tx.To }
-func (tx *SetCodeTx) from() *types.Address    { return tx.fromCache }
-func (tx *SetCodeTx) sign() []byte            { return nil }
+func (tx *SetCodeTx) from() *types.Address    { return tx.From }
+func (tx *SetCodeTx) sign() []byte            { return tx.Sign }
 func (tx *SetCodeTx) accessList() AccessList  { return tx.AccessList }
 
 // authList returns the authorization list
@@ -306,6 +312,50 @@ func (tx *SetCodeTx) EncodeRLP() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// SignAuthorization signs auth with prv and returns a signed copy.
+func SignAuthorization(prv *ecdsa.PrivateKey, auth Authorization) (*Authorization, error) {
+	h := auth.SigningHash()
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	signed := auth.Copy()
+	signed.R = new(uint256.Int).SetBytes(sig[:32])
+	signed.S = new(uint256.Int).SetBytes(sig[32:64])
+	signed.V = uint256.NewInt(uint64(sig[64]))
+	return signed, nil
+}
+
+// setCodeTxToProto sets the access and authorization lists of pbTx. Both are
+// rlp encoded, the authorizations one by one; encoding them cannot fail.
+func setCodeTxToProto(tx *SetCodeTx, pbTx *types_pb.Transaction) {
+	if len(tx.AccessList) > 0 {
+		pbTx.AccessList, _ = rlp.EncodeToBytes(tx.AccessList)
+	}
+	pbTx.AuthList = make([][]byte, len(tx.AuthList))
+	for i, auth := range tx.AuthList {
+		pbTx.AuthList[i], _ = rlp.EncodeToBytes(auth)
+	}
+}
+
+// setCodeListsFromProto decodes the access and authorization lists of pbTx.
+func setCodeListsFromProto(pbTx *types_pb.Transaction) (AccessList, AuthorizationList, error) {
+	var al AccessList
+	if len(pbTx.AccessList) > 0 {
+		if err := rlp.DecodeBytes(pbTx.AccessList, &al); err != nil {
+			return nil, nil, err
+		}
+	}
+	authList := make(AuthorizationList, len(pbTx.AuthList))
+	for i, enc := range pbTx.AuthList {
+		authList[i] = new(Authorization)
+		if err := rlp.DecodeBytes(enc, authList[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return al, authList, nil
+}
+
 // DelegationPrefix is the prefix used to identify delegated accounts (EIP-7702)
 // An account with code starting with this prefix is considered delegated
 var DelegationPrefix = []byte{0xef, 0x01, 0x00}
@@ -329,4 +379,3 @@ func AddressToDelegation(addr types.Address) []byte {
 	copy(code[3:], addr[:])
 	return code
 }
-
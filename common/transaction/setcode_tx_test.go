@@ -17,10 +17,14 @@
 package transaction
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/types"
+	"google.golang.org/protobuf/proto"
 )
 
 // =============================================================================
@@ -355,6 +359,90 @@ func TestCopyAccessListNil(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Signing and Encoding Tests
+// =============================================================================
+
+func TestSignAuthorization(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := SignAuthorization(key, Authorization{ChainID: 7, Address: types.Address{0x0b}, Nonce: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := auth.RecoverSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Errorf("signer = %v, want %v", signer, want)
+	}
+	auth.Nonce++
+	if signer, err := auth.RecoverSigner(); err == nil && signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Error("modified authorization recovered the original signer")
+	}
+}
+
+func TestSetCodeTxProtoRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	auth, err := SignAuthorization(key, Authorization{ChainID: 7, Address: types.Address{0x0b}, Nonce: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := types.Address{0x01}
+	signer := NewPectraSigner(big.NewInt(7))
+	tx, err := SignNewTx(key, signer, &SetCodeTx{
+		ChainID:    uint256.NewInt(7),
+		Nonce:      3,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(10),
+		Gas:        50000,
+		To:         &to,
+		From:       &from,
+		Value:      uint256.NewInt(5),
+		AccessList: AccessList{{Address: to, StorageKeys: []types.Hash{{0x0a}}}},
+		AuthList:   AuthorizationList{auth},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := Sender(signer, tx); err != nil || sender != from {
+		t.Fatalf("sender = %v, %v; want %v", sender, err, from)
+	}
+
+	data, err := tx.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pbTx types_pb.Transaction
+	if err := proto.Unmarshal(data, &pbTx); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := FromProtoMessage(&pbTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Type() != SetCodeTxType || decoded.Hash() != tx.Hash() {
+		t.Fatalf("decoded type %d hash %v, want %d %v", decoded.Type(), decoded.Hash(), SetCodeTxType, tx.Hash())
+	}
+	if len(decoded.AccessList()) != 1 || decoded.AccessList()[0].StorageKeys[0] != (types.Hash{0x0a}) {
+		t.Errorf("access list not decoded: %v", decoded.AccessList())
+	}
+	authList := decoded.AuthList()
+	if len(authList) != 1 {
+		t.Fatalf("got %d authorizations, want 1", len(authList))
+	}
+	if signer, err := authList[0].RecoverSigner(); err != nil || signer != from {
+		t.Errorf("authority = %v, %v; want %v", signer, err, from)
+	}
+}
+
 // =============================================================================
 // Benchmarks
 // =============================================================================
@@ -194,6 +194,72 @@ func txDataFromProtoMessage(message proto.Message) (TxData, error) {
 		dftt.From = utils.ConvertH160ToPAddress(pbTx.From)
 		dftt.Sign = pbTx.Sign
 		inner = &dftt
+	case BlobTxType:
+		var btx BlobTx
+		btx.ChainID = uint256.NewInt(pbTx.ChainID)
+		btx.Nonce = pbTx.Nonce
+		btx.Gas = pbTx.Gas
+		btx.GasFeeCap = utils.ConvertH256ToUint256Int(pbTx.FeePerGas)
+		btx.GasTipCap = utils.ConvertH256ToUint256Int(pbTx.PriorityFeePerGas)
+		btx.BlobFeeCap = new(uint256.Int).SetBytes(pbTx.BlobFeeCap)
+		btx.Value = utils.ConvertH256ToUint256Int(pbTx.Value)
+		if nil != pbTx.V {
+			btx.V = utils.ConvertH256ToUint256Int(pbTx.V)
+		}
+		if nil != pbTx.R {
+			btx.R = utils.ConvertH256ToUint256Int(pbTx.R)
+		}
+		if nil != pbTx.S {
+			btx.S = utils.ConvertH256ToUint256Int(pbTx.S)
+		}
+		btx.Data = pbTx.Data
+		if nil == pbTx.To {
+			return nil, ErrBlobTxCreate
+		}
+		btx.To = utils.ConvertH160toAddress(pbTx.To)
+		btx.From = utils.ConvertH160ToPAddress(pbTx.From)
+		btx.Sign = pbTx.Sign
+		btx.BlobHashes = make([]types.Hash, len(pbTx.BlobHashes))
+		for i, h := range pbTx.BlobHashes {
+			btx.BlobHashes[i] = types.BytesToHash(h)
+		}
+		sidecar, err := blobSidecarFromProto(pbTx)
+		if err != nil {
+			return nil, err
+		}
+		btx.Sidecar = sidecar
+		inner = &btx
+	case SetCodeTxType:
+		var sctx SetCodeTx
+		sctx.ChainID = uint256.NewInt(pbTx.ChainID)
+		sctx.Nonce = pbTx.Nonce
+		sctx.Gas = pbTx.Gas
+		sctx.GasFeeCap = utils.ConvertH256ToUint256Int(pbTx.FeePerGas)
+		sctx.GasTipCap = utils.ConvertH256ToUint256Int(pbTx.PriorityFeePerGas)
+		sctx.Value = utils.ConvertH256ToUint256Int(pbTx.Value)
+		if nil != pbTx.V {
+			sctx.V = utils.ConvertH256ToUint256Int(pbTx.V)
+		}
+		if nil != pbTx.R {
+			sctx.R = utils.ConvertH256ToUint256Int(pbTx.R)
+		}
+		if nil != pbTx.S {
+			sctx.S = utils.ConvertH256ToUint256Int(pbTx.S)
+		}
+		sctx.Data = pbTx.Data
+		if nil != pbTx.To {
+			sctx.To = utils.ConvertH160ToPAddress(pbTx.To)
+		}
+		sctx.From = utils.ConvertH160ToPAddress(pbTx.From)
+		sctx.Sign = pbTx.Sign
+		al, authList, err := setCodeListsFromProto(pbTx)
+		if err != nil {
+			return nil, err
+		}
+		sctx.AccessList, sctx.AuthList = al, authList
+		inner = &sctx
+	default:
+		return nil, ErrTxTypeNotSupported
 	}
 
 	// todo
@@ -249,6 +315,30 @@ func (tx *Transaction) ToProtoMessage() proto.Message {
 		pbTx.Sign = t.Sign
 		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
 		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+	case *BlobTx:
+		pbTx.ChainID = t.ChainID.Uint64()
+		pbTx.Nonce = tx.Nonce()
+		pbTx.Gas = tx.Gas()
+		pbTx.GasPrice = utils.ConvertUint256IntToH256(tx.GasPrice())
+		pbTx.Value = utils.ConvertUint256IntToH256(tx.Value())
+		pbTx.Data = tx.Data()
+		pbTx.From = utils.ConvertAddressToH160(*tx.From())
+		pbTx.Sign = t.Sign
+		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
+		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+		blobTxToProto(t, &pbTx)
+	case *SetCodeTx:
+		pbTx.ChainID = t.ChainID.Uint64()
+		pbTx.Nonce = tx.Nonce()
+		pbTx.Gas = tx.Gas()
+		pbTx.GasPrice = utils.ConvertUint256IntToH256(tx.GasPrice())
+		pbTx.Value = utils.ConvertUint256IntToH256(tx.Value())
+		pbTx.Data = tx.Data()
+		pbTx.From = utils.ConvertAddressToH160(*tx.From())
+		pbTx.Sign = t.Sign
+		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
+		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+		setCodeTxToProto(t, &pbTx)
 	}
 	if tx.To() != nil {
 		pbTx.To = utils.ConvertAddressToH160(*tx.To())
@@ -278,6 +368,11 @@ func (tx *Transaction) setDecoded(inner TxData, size int) {
 	//}
 }
 
+// Time returns the time the transaction was first seen locally.
+func (tx *Transaction) Time() time.Time {
+	return tx.time
+}
+
 func (tx *Transaction) RawSignatureValues() (v, r, s *uint256.Int) {
 	return tx.inner.rawSignatureValues()
 }
@@ -324,6 +419,30 @@ func (tx Transaction) Marshal() ([]byte, error) {
 		pbTx.Sign = t.Sign
 		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
 		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+	case *BlobTx:
+		pbTx.ChainID = t.ChainID.Uint64()
+		pbTx.Nonce = tx.Nonce()
+		pbTx.Gas = tx.Gas()
+		pbTx.GasPrice = utils.ConvertUint256IntToH256(tx.GasPrice())
+		pbTx.Value = utils.ConvertUint256IntToH256(tx.Value())
+		pbTx.Data = tx.Data()
+		pbTx.From = utils.ConvertAddressToH160(*tx.From())
+		pbTx.Sign = t.Sign
+		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
+		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+		blobTxToProto(t, &pbTx)
+	case *SetCodeTx:
+		pbTx.ChainID = t.ChainID.Uint64()
+		pbTx.Nonce = tx.Nonce()
+		pbTx.Gas = tx.Gas()
+		pbTx.GasPrice = utils.ConvertUint256IntToH256(tx.GasPrice())
+		pbTx.Value = utils.ConvertUint256IntToH256(tx.Value())
+		pbTx.Data = tx.Data()
+		pbTx.From = utils.ConvertAddressToH160(*tx.From())
+		pbTx.Sign = t.Sign
+		pbTx.FeePerGas = utils.ConvertUint256IntToH256(t.GasFeeCap)
+		pbTx.PriorityFeePerGas = utils.ConvertUint256IntToH256(t.GasTipCap)
+		setCodeTxToProto(t, &pbTx)
 	}
 	if tx.To() != nil {
 		pbTx.To = utils.ConvertAddressToH160(*tx.To())
@@ -417,6 +536,15 @@ func (tx *Transaction) From() *types.Address {
 	return tx.inner.from()
 }
 
+// Sender returns the sender carried in the transaction, recovering it from
+// the signature when the transaction does not carry one.
+func (tx *Transaction) Sender(signer Signer) (types.Address, error) {
+	if from := tx.From(); from != nil {
+		return *from, nil
+	}
+	return Sender(signer, tx)
+}
+
 func (tx *Transaction) SetFrom(addr types.Address) {
 	switch t := tx.inner.(type) {
 	case *AccessListTx:
@@ -425,6 +553,10 @@ func (tx *Transaction) SetFrom(addr types.Address) {
 		t.From = &addr
 	case *DynamicFeeTx:
 		t.From = &addr
+	case *BlobTx:
+		t.From = &addr
+	case *SetCodeTx:
+		t.From = &addr
 	}
 }
 
@@ -436,6 +568,10 @@ func (tx *Transaction) SetNonce(nonce uint64) {
 		t.Nonce = nonce
 	case *DynamicFeeTx:
 		t.Nonce = nonce
+	case *BlobTx:
+		t.Nonce = nonce
+	case *SetCodeTx:
+		t.Nonce = nonce
 	}
 }
 
@@ -448,6 +584,10 @@ func (tx *Transaction) Cost() *uint256.Int {
 	gas := uint256.NewInt(tx.inner.gas())
 	total := new(uint256.Int).Mul(price, gas)
 	total = total.Add(total, tx.Value())
+	if blobTx, ok := tx.inner.(*BlobTx); ok && blobTx.BlobFeeCap != nil {
+		blobFee := new(uint256.Int).Mul(blobTx.BlobFeeCap, uint256.NewInt(blobTx.BlobGas()))
+		total.Add(total, blobFee)
+	}
 	return total
 }
 
@@ -460,6 +600,17 @@ func (tx *Transaction) Hash() types.Hash {
 	return h
 }
 
+// Size returns the encoded size of the transaction in bytes, caching it after
+// the first call.
+func (tx *Transaction) Size() uint64 {
+	if size := tx.size.Load(); size != nil {
+		return size.(uint64)
+	}
+	size := uint64(proto.Size(tx.ToProtoMessage()))
+	tx.size.Store(size)
+	return size
+}
+
 // BlobHashes returns the blob hashes for EIP-4844 blob transactions
 // Returns nil for non-blob transactions
 func (tx *Transaction) BlobHashes() []types.Hash {
@@ -487,6 +638,41 @@ func (tx *Transaction) BlobGas() uint64 {
 	return 0
 }
 
+// AuthList returns the authorizations of an EIP-7702 set code transaction, or
+// nil for other transactions.
+func (tx *Transaction) AuthList() AuthorizationList {
+	if setCodeTx, ok := tx.inner.(*SetCodeTx); ok {
+		return setCodeTx.AuthList
+	}
+	return nil
+}
+
+// BlobTxSidecar returns the sidecar of a blob transaction, or nil if it has
+// none.
+func (tx *Transaction) BlobTxSidecar() *BlobTxSidecar {
+	if blobTx, ok := tx.inner.(*BlobTx); ok {
+		return blobTx.Sidecar
+	}
+	return nil
+}
+
+// WithoutBlobTxSidecar returns tx without the blobs of its sidecar. Blocks only
+// include this form; other transactions are returned unchanged.
+func (tx *Transaction) WithoutBlobTxSidecar() *Transaction {
+	blobTx, ok := tx.inner.(*BlobTx)
+	if !ok || blobTx.Sidecar == nil {
+		return tx
+	}
+	cpy := &Transaction{inner: blobTx.WithoutSidecar(), time: tx.time}
+	if h := tx.hash.Load(); h != nil {
+		cpy.hash.Store(h)
+	}
+	if sc := tx.from.Load(); sc != nil {
+		cpy.from.Store(sc)
+	}
+	return cpy
+}
+
 // GasFeeCapCmp compares the fee cap of two transactions.
 func (tx *Transaction) GasFeeCapCmp(other *Transaction) int {
 	return tx.inner.gasFeeCap().Cmp(other.inner.gasFeeCap())
@@ -531,25 +717,24 @@ func (tx *Transaction) EffectiveGasTipCmp(other *Transaction, baseFee *uint256.I
 }
 
 // EffectiveGasTip returns the effective miner gasTipCap for the given base fee.
-// Note: if the effective gasTipCap is negative, this method returns both error
-// the actual negative value, _and_ ErrGasFeeCapTooLow
+// Note: if the fee cap is below the base fee, this method returns a zero tip
+// _and_ ErrGasFeeCapTooLow, as the unsigned tip cannot be negative.
 func (tx *Transaction) EffectiveGasTip(baseFee *uint256.Int) (*uint256.Int, error) {
 	if baseFee == nil {
 		return tx.GasTipCap(), nil
 	}
-	var err error
 	gasFeeCap := tx.GasFeeCap()
 	if gasFeeCap.Cmp(baseFee) == -1 {
-		err = ErrGasFeeCapTooLow
+		return new(uint256.Int), ErrGasFeeCapTooLow
 	}
-	return uint256Min(tx.GasTipCap(), new(uint256.Int).Sub(gasFeeCap, baseFee)), err
+	return uint256Min(tx.GasTipCap(), new(uint256.Int).Sub(gasFeeCap, baseFee)), nil
 }
 
 func uint256Min(x, y *uint256.Int) *uint256.Int {
 	if x.Cmp(y) == 1 {
-		return x
+		return y
 	}
-	return y
+	return x
 }
 
 func isProtectedV(V *big.Int) bool {
@@ -641,6 +826,10 @@ type Message struct {
 	accessList AccessList
 	checkNonce bool
 	isFree     bool
+
+	blobHashes    []types.Hash
+	blobGasFeeCap *uint256.Int
+	authList      AuthorizationList
 }
 
 func NewMessage(from types.Address, to *types.Address, nonce uint64, amount *uint256.Int, gasLimit uint64, gasPrice *uint256.Int, feeCap, tip *uint256.Int, data []byte, accessList AccessList, checkNonce bool, isFree bool) Message {
@@ -683,6 +872,9 @@ func (tx *Transaction) AsMessage(s Signer, baseFee *uint256.Int) (Message, error
 		accessList: tx.AccessList(),
 		checkNonce: false,
 		//isFake:     false,
+		blobHashes:    tx.BlobHashes(),
+		blobGasFeeCap: tx.BlobFeeCap(),
+		authList:      tx.AuthList(),
 	}
 
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
@@ -693,12 +885,11 @@ func (tx *Transaction) AsMessage(s Signer, baseFee *uint256.Int) (Message, error
 			msg.gasPrice = msg.feeCap
 		}
 	}
-	//var err error
-	//msg.from, err = Sender(s, tx)
-	//if nil != err {
-	//	return msg, err
-	//}
-	msg.from = *tx.From()
+	var err error
+	msg.from, err = tx.Sender(s)
+	if nil != err {
+		return msg, err
+	}
 
 	return msg, nil
 }
@@ -717,6 +908,19 @@ func (m *Message) SetCheckNonce(checkNonce bool) {
 	m.checkNonce = checkNonce
 }
 func (m Message) IsFree() bool { return m.isFree }
+
+// BlobHashes returns the versioned hashes of the blobs of an EIP-4844 message.
+func (m Message) BlobHashes() []types.Hash { return m.blobHashes }
+
+// BlobGas returns the blob gas consumed by the message.
+func (m Message) BlobGas() uint64 { return uint64(len(m.blobHashes)) * BlobTxBlobGasPerBlob }
+
+// BlobGasFeeCap returns the maximum fee per blob gas, or nil if the message
+// carries no blobs.
+func (m Message) BlobGasFeeCap() *uint256.Int { return m.blobGasFeeCap }
+
+// AuthList returns the EIP-7702 authorizations of the message.
+func (m Message) AuthList() AuthorizationList { return m.authList }
 func (m *Message) SetIsFree(isFree bool) {
 	m.isFree = isFree
 }
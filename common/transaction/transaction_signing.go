@@ -46,6 +46,10 @@ type sigCache struct {
 func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
 	var signer Signer
 	switch {
+	case config.IsPectra(blockNumber.Uint64()):
+		signer = NewPectraSigner(config.ChainID)
+	case config.IsCancun(blockNumber.Uint64()):
+		signer = NewCancunSigner(config.ChainID)
 	case config.IsLondon(blockNumber.Uint64()):
 		signer = NewLondonSigner(config.ChainID)
 	case config.IsBerlin(blockNumber.Uint64()):
@@ -71,7 +75,7 @@ func LatestSignerForChainID(chainID *big.Int) Signer {
 	if chainID == nil {
 		return HomesteadSigner{}
 	}
-	return NewLondonSigner(chainID)
+	return NewPectraSigner(chainID)
 }
 
 // SignNewTx creates a transaction and signs it.
@@ -101,7 +105,9 @@ func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, err
 //
 // Sender may cache the address, allowing it to be used regardless of
 // signing method. The cache is invalidated if the cached signer does
-// not match the signer used in the current call.
+// not match the signer used in the current call. Senders are also kept
+// by transaction hash, so decoded copies of a transaction seen before are
+// not recovered again.
 func Sender(signer Signer, tx *Transaction) (types.Address, error) {
 	if sc := tx.from.Load(); sc != nil {
 		sigCache := sc.(sigCache)
@@ -112,12 +118,17 @@ func Sender(signer Signer, tx *Transaction) (types.Address, error) {
 			return sigCache.from, nil
 		}
 	}
+	if addr, ok := cachedSender(tx); ok {
+		tx.from.Store(sigCache{signer: signer, from: addr})
+		return addr, nil
+	}
 
 	addr, err := signer.Sender(tx)
 	if err != nil {
 		return types.Address{}, err
 	}
 	tx.from.Store(sigCache{signer: signer, from: addr})
+	CacheSender(tx, addr)
 	return addr, nil
 }
 
@@ -144,6 +155,134 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+type pectraSigner struct{ cancunSigner }
+
+// NewPectraSigner returns a signer that accepts
+// - EIP-7702 set code transactions
+// - EIP-4844 blob transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewPectraSigner(chainId *big.Int) Signer {
+	return pectraSigner{cancunSigner{londonSigner{eip2930Signer{NewEIP155Signer(chainId)}}}}
+}
+
+func (s pectraSigner) Sender(tx *Transaction) (types.Address, error) {
+	if tx.Type() != SetCodeTxType {
+		return s.cancunSigner.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Set code txs are defined to use 0 and 1 as their recovery
+	// id, add 27 to become equivalent to unprotected Homestead signatures.
+	V1 := new(big.Int).Add(V.ToBig(), big.NewInt(27))
+	chainId, _ := uint256.FromBig(s.chainId)
+	id := tx.ChainId()
+	if id.Cmp(chainId) != 0 {
+		return types.Address{}, ErrInvalidChainId
+	}
+	return recoverPlain(s.Hash(tx), R.ToBig(), S.ToBig(), V1, true)
+}
+
+func (s pectraSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(pectraSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0
+}
+
+func (s pectraSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*SetCodeTx)
+	if !ok {
+		return s.cancunSigner.SignatureValues(tx, sig)
+	}
+	chainId, _ := uint256.FromBig(s.chainId)
+	if txdata.ChainID != nil && txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(chainId) != 0 {
+		return nil, nil, nil, ErrInvalidChainId
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s pectraSigner) Hash(tx *Transaction) types.Hash {
+	txdata, ok := tx.inner.(*SetCodeTx)
+	if !ok {
+		return s.cancunSigner.Hash(tx)
+	}
+	return txdata.signingHash(s.chainId)
+}
+
+type cancunSigner struct{ londonSigner }
+
+// NewCancunSigner returns a signer that accepts
+// - EIP-4844 blob transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewCancunSigner(chainId *big.Int) Signer {
+	return cancunSigner{londonSigner{eip2930Signer{NewEIP155Signer(chainId)}}}
+}
+
+func (s cancunSigner) Sender(tx *Transaction) (types.Address, error) {
+	if tx.Type() != BlobTxType {
+		return s.londonSigner.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Blob txs are defined to use 0 and 1 as their recovery
+	// id, add 27 to become equivalent to unprotected Homestead signatures.
+	V1 := new(big.Int).Add(V.ToBig(), big.NewInt(27))
+	chainId, _ := uint256.FromBig(s.chainId)
+	id := tx.ChainId()
+	if id.Cmp(chainId) != 0 {
+		return types.Address{}, ErrInvalidChainId
+	}
+	return recoverPlain(s.Hash(tx), R.ToBig(), S.ToBig(), V1, true)
+}
+
+func (s cancunSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(cancunSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0
+}
+
+func (s cancunSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*BlobTx)
+	if !ok {
+		return s.londonSigner.SignatureValues(tx, sig)
+	}
+	chainId, _ := uint256.FromBig(s.chainId)
+	if txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(chainId) != 0 {
+		return nil, nil, nil, ErrInvalidChainId
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s cancunSigner) Hash(tx *Transaction) types.Hash {
+	if tx.Type() != BlobTxType {
+		return s.londonSigner.Hash(tx)
+	}
+	return hash.PrefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			tx.BlobFeeCap(),
+			tx.BlobHashes(),
+		})
+}
+
 type londonSigner struct{ eip2930Signer }
 
 // NewLondonSigner returns a signer that accepts
@@ -21,6 +21,7 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/n42blockchain/N42/common/types"
+	"google.golang.org/protobuf/proto"
 )
 
 // =============================================================================
@@ -307,6 +308,38 @@ func TestCopyAddressPtr(t *testing.T) {
 	t.Logf("✓ copyAddressPtr works correctly")
 }
 
+// =============================================================================
+// Transaction Size Tests
+// =============================================================================
+
+func TestTransactionSize(t *testing.T) {
+	from := types.Address{0x02}
+	to := types.Address{0x01}
+	newTx := func(data []byte) *Transaction {
+		return NewTx(&LegacyTx{
+			Nonce:    1,
+			GasPrice: uint256.NewInt(1000),
+			Gas:      21000,
+			To:       &to,
+			From:     &from,
+			Value:    uint256.NewInt(100),
+			Data:     data,
+		})
+	}
+
+	small := newTx(nil)
+	if want := uint64(proto.Size(small.ToProtoMessage())); small.Size() != want {
+		t.Errorf("Size() = %d, want encoded length %d", small.Size(), want)
+	}
+
+	large := newTx(make([]byte, 1024))
+	if large.Size() < small.Size()+1024 {
+		t.Errorf("Size() = %d, should grow with the data (small %d)", large.Size(), small.Size())
+	}
+
+	t.Logf("✓ Transaction.Size matches the encoded length")
+}
+
 // =============================================================================
 // Benchmark Tests
 // =============================================================================
@@ -382,3 +415,23 @@ func BenchmarkAccessListTxAccessors(b *testing.B) {
 		_ = tx.to()
 	}
 }
+
+func TestEffectiveGasTip(t *testing.T) {
+	tx := NewTx(&DynamicFeeTx{ChainID: uint256.NewInt(1), GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(10), Gas: 21000})
+	tests := []struct {
+		baseFee *uint256.Int
+		tip     uint64
+		err     error
+	}{
+		{nil, 2, nil},                               // the tip cap without a base fee
+		{uint256.NewInt(5), 2, nil},                 // capped by the tip cap
+		{uint256.NewInt(9), 1, nil},                 // capped by the fee cap
+		{uint256.NewInt(11), 0, ErrGasFeeCapTooLow}, // fee cap below the base fee
+	}
+	for i, test := range tests {
+		tip, err := tx.EffectiveGasTip(test.baseFee)
+		if tip.Uint64() != test.tip || err != test.err {
+			t.Errorf("test %d: got tip %v, err %v, want %d, %v", i, tip, err, test.tip, test.err)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/n42blockchain/N42/params"
 	"gopkg.in/yaml.v2"
+	"io"
 	"os"
 )
 
@@ -30,15 +31,20 @@ type Config struct {
 	LoggerCfg   LoggerConfig        `json:"logger" yaml:"logger"`
 	DatabaseCfg DatabaseConfig      `json:"database" yaml:"database"`
 	PprofCfg    PprofConfig         `json:"pprof" yaml:"pprof"`
+	ResourceCfg ResourceConfig      `json:"resource" yaml:"resource"`
 	ChainCfg    *params.ChainConfig `json:"chain" yaml:"chain"`
 	AccountCfg  AccountConfig       `json:"account" yaml:"account"`
 	MetricsCfg  MetricsConfig       `json:"metrics" yaml:"metrics"`
+	WatchdogCfg WatchdogConfig      `json:"watchdog" yaml:"watchdog"`
+	TxPoolCfg   TxPoolConfig        `json:"txpool" yaml:"txpool"`
 	P2PCfg      *P2PConfig          `json:"p2p" yaml:"p2p"`
 	// Gas Price Oracle options
 	GPO   GpoConfig   `json:"gpo" yaml:"gpo"`
 	Miner MinerConfig `json:"miner"`
 	// Development configuration
 	DevCfg DevConfig `json:"dev" yaml:"dev"`
+	// Overrides moves the activation of the named forks, see params.OverridableForks
+	Overrides map[string]uint64 `json:"overrides,omitempty" yaml:"overrides,omitempty"`
 }
 
 func SaveConfigToFile(file string, config Config) error {
@@ -52,10 +58,19 @@ func SaveConfigToFile(file string, config Config) error {
 		return err
 	}
 	defer fd.Close()
-	return yaml.NewEncoder(fd).Encode(config)
+	return WriteConfig(fd, &config)
 	//return toml.NewEncoder(fd).Encode(blockchain)
 }
 
+// WriteConfig encodes config as YAML in the format read by LoadConfigFromFile.
+func WriteConfig(w io.Writer, config *Config) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(config); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
 func LoadConfigFromFile(file string, config *Config) error {
 	if len(file) <= 0 {
 		return fmt.Errorf("failed to load blockchain from file, file is nil")
@@ -68,5 +83,16 @@ func LoadConfigFromFile(file string, config *Config) error {
 	defer fd.Close()
 	reader := bufio.NewReader(fd)
 	//return toml.NewDecoder(reader).Decode(blockchain)
-	return yaml.NewDecoder(reader).Decode(config)
+	return ReadConfig(reader, config)
+}
+
+// ReadConfig decodes a YAML configuration into config. Keys that do not map to
+// a config field are rejected rather than silently ignored.
+func ReadConfig(r io.Reader, config *Config) error {
+	dec := yaml.NewDecoder(r)
+	dec.SetStrict(true)
+	if err := dec.Decode(config); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	cfg := Config{
+		NodeCfg:     NodeConfig{HTTP: true, HTTPPort: "8545", DataDir: "/data/n42"},
+		LoggerCfg:   LoggerConfig{Level: "debug", MaxSize: 50},
+		WatchdogCfg: WatchdogConfig{StallTimeout: 90 * time.Second, Resync: true},
+		P2PCfg:      &P2PConfig{StaticPeers: []string{"/ip4/127.0.0.1/tcp/61016"}, MaxPeers: 10},
+	}
+	var buf bytes.Buffer
+	if err := WriteConfig(&buf, &cfg); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	want := buf.String()
+
+	var loaded Config
+	if err := ReadConfig(&buf, &loaded); err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	if loaded.WatchdogCfg != cfg.WatchdogCfg || loaded.P2PCfg.MaxPeers != 10 {
+		t.Errorf("loaded config differs: %+v", loaded)
+	}
+	var again bytes.Buffer
+	if err := WriteConfig(&again, &loaded); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+	if again.String() != want {
+		t.Errorf("config changed in round trip:\nhave %s\nwant %s", again.String(), want)
+	}
+}
+
+func TestReadConfigUnknownKey(t *testing.T) {
+	var cfg Config
+	err := ReadConfig(strings.NewReader("node:\n  http: true\n  htpp_port: \"8545\"\n"), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "htpp_port") {
+		t.Errorf("unknown key not reported: %v", err)
+	}
+}
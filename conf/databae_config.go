@@ -25,4 +25,12 @@ type DatabaseConfig struct {
 	IsMem      bool     `json:"memory" yaml:"memory"`
 	MaxDB      uint64   `json:"max_db" yaml:"max_db"`
 	MaxReaders uint64   `json:"max_readers" yaml:"max_readers"`
+
+	// AncientDir is the directory of the freezer holding the immutable part
+	// of the chain. A relative path is resolved against the data directory,
+	// empty means "ancient".
+	AncientDir string `json:"ancient_dir" yaml:"ancient_dir"`
+	// FreezeThreshold is the number of recent blocks kept in the database,
+	// older immutable blocks are moved to the freezer. Zero disables freezing.
+	FreezeThreshold uint64 `json:"freeze_threshold" yaml:"freeze_threshold"`
 }
@@ -20,6 +20,13 @@ import "time"
 
 // DevConfig holds development and testing configuration.
 type DevConfig struct {
+	// Enabled runs a single node developer chain with prefunded accounts
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Period is the block interval of the developer chain, zero seals a block
+	// as soon as transactions arrive
+	Period time.Duration `json:"period" yaml:"period"`
+
 	// TxGen enables automatic transaction generation for testing
 	TxGenEnabled bool `json:"tx_gen_enabled" yaml:"tx_gen_enabled"`
 
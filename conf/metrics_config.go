@@ -16,8 +16,28 @@
 
 package conf
 
+import "time"
+
 type MetricsConfig struct {
 	Enable bool   `json:"enable" yaml:"enable"`
 	Port   int    `json:"port" yaml:"port"`
 	HTTP   string `json:"http" yaml:"http"`
+
+	// Periodic push to InfluxDB or a Prometheus pushgateway, for nodes that
+	// are too short-lived to be scraped.
+	PushInterval time.Duration `json:"push_interval" yaml:"push_interval"`
+
+	EnableInfluxDB   bool   `json:"influxdb" yaml:"influxdb"`
+	EnableInfluxDBV2 bool   `json:"influxdbv2" yaml:"influxdbv2"`
+	InfluxDBEndpoint string `json:"influxdb_endpoint" yaml:"influxdb_endpoint"`
+	InfluxDBDatabase string `json:"influxdb_database" yaml:"influxdb_database"`
+	InfluxDBUsername string `json:"influxdb_username" yaml:"influxdb_username"`
+	InfluxDBPassword string `json:"influxdb_password" yaml:"influxdb_password"`
+	InfluxDBToken    string `json:"influxdb_token" yaml:"influxdb_token"`
+	InfluxDBBucket   string `json:"influxdb_bucket" yaml:"influxdb_bucket"`
+	InfluxDBOrg      string `json:"influxdb_organization" yaml:"influxdb_organization"`
+	InfluxDBTags     string `json:"influxdb_tags" yaml:"influxdb_tags"` // Comma separated key=value pairs
+
+	PushGateway    string `json:"pushgateway" yaml:"pushgateway"`
+	PushGatewayJob string `json:"pushgateway_job" yaml:"pushgateway_job"`
 }
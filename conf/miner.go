@@ -26,4 +26,7 @@ type MinerConfig struct {
 	GasCeil   uint64        // Target gas ceiling for mined blocks.
 	GasPrice  *big.Int      // Minimum gas price for mining a transaction
 	Recommit  time.Duration // The time interval for miner to re-create mining work
+	Ordering  string        // Name of the transaction ordering used to fill blocks
+	ExtraData string        // Block extra-data set by the miner, at most 32 bytes
+	Instant   bool          // Seal a block as soon as transactions arrive and never an empty one
 }
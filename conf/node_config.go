@@ -19,6 +19,7 @@ package conf
 import (
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const (
@@ -35,6 +36,18 @@ type NodeConfig struct {
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
 	HTTPCors string `json:"http_cors" yaml:"http_cors"`
+	// HTTPAPIKeys is the path to a file of API keys. When set, every HTTP
+	// JSON-RPC request and WebSocket connection must carry one of them and is
+	// accounted to it.
+	HTTPAPIKeys string `json:"http_api_keys" yaml:"http_api_keys"`
+	// HTTPAPIKeyRate limits the requests per second of each API key, zero
+	// means unlimited. HTTPAPIKeyBurst is the number of requests a key may
+	// send at once.
+	HTTPAPIKeyRate  float64 `json:"http_api_key_rate" yaml:"http_api_key_rate"`
+	HTTPAPIKeyBurst int64   `json:"http_api_key_burst" yaml:"http_api_key_burst"`
+	// HTTPAPIAdminKey reads the usage of all API keys from the usage
+	// endpoint. Without it every key only sees its own usage.
+	HTTPAPIAdminKey string `json:"http_api_admin_key" yaml:"http_api_admin_key"`
 
 	WS     bool   `json:"ws" yaml:"ws" `
 	WSHost string `json:"ws_host" yaml:"ws_host" `
@@ -76,6 +89,9 @@ type NodeConfig struct {
 	// ExternalSigner specifies an external URI for a clef-type signer
 	ExternalSigner string `json:"external_signer" yaml:"external_signer"`
 
+	// USB enables monitoring for and managing USB hardware wallets.
+	USB bool `json:"usb" yaml:"usb"`
+
 	// UseLightweightKDF lowers the memory and CPU requirements of the key store
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `json:"use_lightweight_kdf" yaml:"use_lightweight_kdf"`
@@ -84,6 +100,50 @@ type NodeConfig struct {
 	InsecureUnlockAllowed bool `json:"insecure_unlock_allowed" yaml:"insecure_unlock_allowed"`
 
 	PasswordFile string `json:"password_file" yaml:"password_file"`
+
+	// Backfill lists the indexes to build for blocks written before they were
+	// enabled, e.g. "txlookup".
+	Backfill []string `json:"backfill" yaml:"backfill"`
+
+	// StateCommitment maintains a state trie root for every block in the
+	// background, see "n42 verify-state-root".
+	StateCommitment bool `json:"state_commitment" yaml:"state_commitment"`
+
+	// ReadOnly opens the database read-only to serve RPC next to the node
+	// owning the data directory. The node does not sync, mine or write.
+	ReadOnly bool `json:"readonly" yaml:"readonly"`
+
+	// KVRPC serves the chain database over gRPC to remote RPC front-ends on
+	// KVAddr and KVPort. Calls are authenticated with JWTSecret.
+	KVRPC  bool   `json:"kv_rpc" yaml:"kv_rpc"`
+	KVAddr string `json:"kv_addr" yaml:"kv_addr"`
+	KVPort int    `json:"kv_port" yaml:"kv_port"`
+
+	// RemoteKV is the host:port of the database served by another node,
+	// which shares JWTSecret. When set, the node is read-only and serves RPC
+	// from that database.
+	RemoteKV string `json:"remote_kv" yaml:"remote_kv"`
+
+	// VerifyWorkers is the number of goroutines verifying the headers of
+	// imported blocks, zero means one per CPU. VerifyAhead bounds how many
+	// headers verification runs ahead of block execution.
+	VerifyWorkers int `json:"verify_workers" yaml:"verify_workers"`
+	VerifyAhead   int `json:"verify_ahead" yaml:"verify_ahead"`
+
+	// CommitEvery is the number of imported blocks written per database
+	// transaction, CommitSize caps the state changes of such a batch in
+	// megabytes. Batching speeds up the initial sync; a crash loses at most
+	// the batch being written.
+	CommitEvery int `json:"commit_every" yaml:"commit_every"`
+	CommitSize  int `json:"commit_size" yaml:"commit_size"`
+
+	// MaxReorgDepth is the number of canonical blocks a reorg may drop before
+	// it is refused until the operator allows it. Zero disables the limit.
+	MaxReorgDepth uint64 `json:"max_reorg_depth" yaml:"max_reorg_depth"`
+
+	// ShutdownTimeout bounds how long a graceful shutdown may take before the
+	// process exits anyway. Zero waits indefinitely.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
 }
 
 // KeyDirConfig determines the settings for keydirectory
@@ -16,6 +16,14 @@
 
 package conf
 
+import "fmt"
+
+// Compressions preferred on block-carrying req/resp protocols
+const (
+	CompressionZstd   = "zstd"
+	CompressionSnappy = "snappy"
+)
+
 type NetWorkConfig struct {
 	ListenersAddress []string `json:"listeners" yaml:"listeners"`
 	BootstrapPeers   []string `json:"bootstraps" yaml:"bootstraps"`
@@ -43,10 +51,24 @@ type P2PConfig struct {
 	AllowListCIDR       string   `json:"allow_list_cidr" yaml:"allow_list_cidr"`
 	DenyListCIDR        []string `json:"deny_list_cidr" yaml:"deny_list_cidr"`
 	MinSyncPeers        int      `json:"min_sync_peers" yaml:"min_sync_peers"`
+	// Compression selects the preferred encoding offered on block-carrying req/resp
+	// protocols ("zstd" or "snappy", empty means "zstd"). ssz_snappy is always
+	// accepted as a fallback.
+	Compression string `json:"compression" yaml:"compression"`
 
 	P2PLimit *P2PLimit
 }
 
+// Validate checks the settings that are not checked when the p2p service
+// starts.
+func (c *P2PConfig) Validate() error {
+	switch c.Compression {
+	case "", CompressionZstd, CompressionSnappy:
+		return nil
+	}
+	return fmt.Errorf("unknown p2p compression %q (%s or %s)", c.Compression, CompressionZstd, CompressionSnappy)
+}
+
 type P2PLimit struct {
 	BlockBatchLimit            int `json:"block_batch_limit" yaml:"block_batch_limit"`
 	BlockBatchLimitBurstFactor int `json:"block_batch_limit_burst_factor" yaml:"block_batch_limit_burst_factor"`
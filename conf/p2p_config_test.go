@@ -0,0 +1,34 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+import "testing"
+
+func TestP2PConfigValidate(t *testing.T) {
+	for _, compression := range []string{"", CompressionZstd, CompressionSnappy} {
+		cfg := P2PConfig{Compression: compression}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("compression %q: %v", compression, err)
+		}
+	}
+	for _, compression := range []string{"gzip", "Snappy", "zstd "} {
+		cfg := P2PConfig{Compression: compression}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("compression %q: have no error", compression)
+		}
+	}
+}
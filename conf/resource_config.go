@@ -0,0 +1,36 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+// ResourceConfig bounds the memory used by the node, so it behaves
+// predictably when co-located with other services.
+type ResourceConfig struct {
+	// GCPercent is passed to debug.SetGCPercent; 100 is the Go default.
+	GCPercent int `json:"gc_percent" yaml:"gc_percent"`
+	// MemoryLimit is the soft memory limit in MiB (0 = derive from MemoryLimitRatio).
+	MemoryLimit uint64 `json:"memory_limit" yaml:"memory_limit"`
+	// MemoryLimitRatio sizes the soft memory limit as a fraction of total RAM
+	// when MemoryLimit is not set (0 = no limit).
+	MemoryLimitRatio float64 `json:"memory_limit_ratio" yaml:"memory_limit_ratio"`
+	// Cache is the database cache in MiB (0 = size automatically from RAM).
+	Cache uint64 `json:"cache" yaml:"cache"`
+	// CodeCache is the contract code cache shared by block execution and the
+	// RPC state readers in MiB (0 = disabled).
+	CodeCache uint64 `json:"code_cache" yaml:"code_cache"`
+	// AccountCache is the number of accounts in the same cache (0 = disabled).
+	AccountCache int `json:"account_cache" yaml:"account_cache"`
+}
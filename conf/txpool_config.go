@@ -0,0 +1,39 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+import "time"
+
+// TxPoolConfig holds the limits and local transaction handling of the
+// transaction pool.
+type TxPoolConfig struct {
+	Locals   []string `json:"locals" yaml:"locals"`       // Addresses whose transactions are treated as local
+	NoLocals bool     `json:"no_locals" yaml:"no_locals"` // Treat transactions of RPC clients as remote
+
+	Journal   string        `json:"journal" yaml:"journal"`     // Journal of local transactions surviving restarts (relative to the data dir)
+	Rejournal time.Duration `json:"rejournal" yaml:"rejournal"` // Interval of regenerating the journal
+
+	PriceLimit uint64 `json:"price_limit" yaml:"price_limit"` // Minimum gas price for acceptance of remote transactions
+	PriceBump  uint64 `json:"price_bump" yaml:"price_bump"`   // Minimum price bump percentage to replace a transaction
+
+	AccountSlots uint64 `json:"account_slots" yaml:"account_slots"` // Executable transaction slots guaranteed per account
+	GlobalSlots  uint64 `json:"global_slots" yaml:"global_slots"`   // Maximum number of executable transaction slots
+	AccountQueue uint64 `json:"account_queue" yaml:"account_queue"` // Maximum number of non-executable transactions per account
+	GlobalQueue  uint64 `json:"global_queue" yaml:"global_queue"`   // Maximum number of non-executable transactions
+
+	Lifetime time.Duration `json:"lifetime" yaml:"lifetime"` // Maximum time non-executable transactions are queued
+}
@@ -0,0 +1,31 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+import "time"
+
+// WatchdogConfig controls the chain head watchdog, which detects an insert
+// loop that stopped advancing while peers keep announcing higher blocks.
+type WatchdogConfig struct {
+	// StallTimeout is how long the head may stay unchanged while peers are
+	// ahead before the watchdog alerts (0 = disabled).
+	StallTimeout time.Duration `json:"stall_timeout" yaml:"stall_timeout"`
+	// Webhook receives a JSON POST for every stall and recovery (optional).
+	Webhook string `json:"webhook" yaml:"webhook"`
+	// Resync restarts initial sync from the best peers when a stall is detected.
+	Resync bool `json:"resync" yaml:"resync"`
+}
@@ -17,80 +17,26 @@
 package fujideposit
 
 import (
-	"bytes"
-	"embed"
-	"github.com/holiman/uint256"
-	"github.com/n42blockchain/N42/accounts/abi"
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/types"
-	"github.com/n42blockchain/N42/log"
-	"github.com/pkg/errors"
-	"math/big"
+	"github.com/n42blockchain/N42/contracts/deposit/bindings"
 )
 
-//go:embed abi.json
-var abiJson embed.FS
-var contractAbi abi.ABI
-var depositSignature = crypto.Keccak256Hash([]byte("DepositEvent(bytes,uint256,bytes)"))
-var withdrawnSignature = crypto.Keccak256Hash([]byte("WithdrawnEvent(uint256)"))
-
-func init() {
-	var (
-		depositAbiCode []byte
-		err            error
-	)
-	if depositAbiCode, err = abiJson.ReadFile("abi.json"); err != nil {
-		panic("Could not open abi.json")
-	}
-
-	if contractAbi, err = abi.JSON(bytes.NewReader(depositAbiCode)); err != nil {
-		panic("unable to parse N42 deposit contract abi")
-	}
-}
-
+// Contract is the FUJI staking contract, staking a token with deposit(bytes,bytes,uint256).
 type Contract struct {
 }
 
-func (c Contract) IsDepositAction(sigdata [4]byte) bool {
-	var (
-		method *abi.Method
-		err    error
-	)
-	if method, err = contractAbi.MethodById(sigdata[:]); err != nil {
-		return false
-	}
-
-	if !bytes.Equal(method.ID, contractAbi.Methods["deposit"].ID) {
-		return false
-	}
-	return true
-}
-
 func (Contract) WithdrawnSignature() types.Hash {
-	return withdrawnSignature
+	return bindings.WithdrawnEventID
 }
 
 func (Contract) DepositSignature() types.Hash {
-	return depositSignature
+	return bindings.DepositEventID
+}
+
+func (Contract) IsDepositAction(sigdata [4]byte) bool {
+	return sigdata == bindings.TokenDepositMethodID
 }
 
-func (Contract) UnpackDepositLogData(data []byte) (publicKey []byte, signature []byte, depositAmount *uint256.Int, err error) {
-	var (
-		unpackedLogs []interface{}
-		overflow     bool
-	)
-	//
-	if unpackedLogs, err = contractAbi.Unpack("DepositEvent", data); err != nil {
-		err = errors.Wrap(err, "unable to unpack logs")
-		return
-	}
-	//
-	if depositAmount, overflow = uint256.FromBig(unpackedLogs[1].(*big.Int)); overflow {
-		err = errors.New("unable to unpack amount")
-		return
-	}
-	publicKey, signature = unpackedLogs[0].([]byte), unpackedLogs[2].([]byte)
-	log.Debug("unpacked DepositEvent Logs", "publicKey", hexutil.Encode(unpackedLogs[0].([]byte)), "signature", hexutil.Encode(unpackedLogs[2].([]byte)), "message", hexutil.Encode(depositAmount.Bytes()))
-	return
+func (Contract) UnpackDepositLogData(data []byte) (*bindings.DepositData, error) {
+	return bindings.UnpackDepositEvent(data)
 }
@@ -0,0 +1,261 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bindings provides typed Go bindings for the IDeposit interface
+// implemented by the N42 deposit contracts, together with helpers to build,
+// verify and hash deposit data. Consensus, the RPC and the CLI use it instead
+// of parsing their own copies of the contract ABI.
+package bindings
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/accounts/abi"
+	"github.com/n42blockchain/N42/accounts/abi/bind"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+//go:embed abi.json
+var abiJson embed.FS
+
+// DepositABI is the parsed ABI of the IDeposit interface.
+var DepositABI abi.ABI
+
+var (
+	// DepositEventID is the topic of DepositEvent(bytes,uint256,bytes).
+	DepositEventID types.Hash
+	// WithdrawnEventID is the topic of WithdrawnEvent(uint256).
+	WithdrawnEventID types.Hash
+	// DepositMethodID is the selector of deposit(bytes,bytes).
+	DepositMethodID [4]byte
+	// TokenDepositMethodID is the selector of deposit(bytes,bytes,uint256) of
+	// the NFT staking contracts (FUJI, NFT), which stake a token instead of value.
+	TokenDepositMethodID [4]byte
+	// WithdrawMethodID is the selector of withdraw().
+	WithdrawMethodID [4]byte
+)
+
+func init() {
+	var (
+		depositAbiCode []byte
+		err            error
+	)
+	if depositAbiCode, err = abiJson.ReadFile("abi.json"); err != nil {
+		panic("Could not open abi.json")
+	}
+	if DepositABI, err = abi.JSON(bytes.NewReader(depositAbiCode)); err != nil {
+		panic("unable to parse N42 deposit contract abi")
+	}
+	DepositEventID = DepositABI.Events["DepositEvent"].ID
+	WithdrawnEventID = DepositABI.Events["WithdrawnEvent"].ID
+	copy(DepositMethodID[:], DepositABI.Methods["deposit"].ID)
+	copy(TokenDepositMethodID[:], crypto.Keccak256([]byte("deposit(bytes,bytes,uint256)")))
+	copy(WithdrawMethodID[:], DepositABI.Methods["withdraw"].ID)
+}
+
+var errNoEventSignature = errors.New("no event signature")
+
+// DepositEvent represents a DepositEvent raised by a deposit contract.
+type DepositEvent struct {
+	Pubkey    []byte
+	WeiAmount *big.Int
+	Signature []byte
+	Raw       block.Log
+}
+
+// WithdrawnEvent represents a WithdrawnEvent raised by a deposit contract.
+type WithdrawnEvent struct {
+	WeiAmount *big.Int
+	Raw       block.Log
+}
+
+// Deposit is a binding around a deployed deposit contract.
+type Deposit struct {
+	DepositCaller
+	DepositTransactor
+	DepositFilterer
+}
+
+// DepositCaller is a read-only binding around a deposit contract.
+type DepositCaller struct {
+	contract *bind.BoundContract
+}
+
+// DepositTransactor is a write-only binding around a deposit contract.
+type DepositTransactor struct {
+	contract *bind.BoundContract
+}
+
+// DepositFilterer is a log filtering binding around a deposit contract.
+type DepositFilterer struct {
+	contract *bind.BoundContract
+}
+
+// NewDeposit creates a new instance of Deposit, bound to a specific deployed
+// contract.
+func NewDeposit(address types.Address, backend bind.ContractBackend) *Deposit {
+	contract := bind.NewBoundContract(address, DepositABI, backend, backend, backend)
+	return &Deposit{
+		DepositCaller:     DepositCaller{contract: contract},
+		DepositTransactor: DepositTransactor{contract: contract},
+		DepositFilterer:   DepositFilterer{contract: contract},
+	}
+}
+
+// NewDepositCaller creates a new read-only instance of Deposit.
+func NewDepositCaller(address types.Address, caller bind.ContractCaller) *DepositCaller {
+	return &DepositCaller{contract: bind.NewBoundContract(address, DepositABI, caller, nil, nil)}
+}
+
+// NewDepositFilterer creates a new log filterer instance of Deposit.
+func NewDepositFilterer(address types.Address, filterer bind.ContractFilterer) *DepositFilterer {
+	return &DepositFilterer{contract: bind.NewBoundContract(address, DepositABI, nil, nil, filterer)}
+}
+
+// DepositsOf returns the amount deposited by payee.
+//
+// Solidity: function depositsOf(address payee) view returns(uint256)
+func (c *DepositCaller) DepositsOf(opts *bind.CallOpts, payee types.Address) (*uint256.Int, error) {
+	var out []interface{}
+	if err := c.contract.Call(opts, &out, "depositsOf", payee); err != nil {
+		return nil, err
+	}
+	return toUint256(out[0])
+}
+
+// DepositUnlockingTimestamp returns the time after which payee may withdraw.
+//
+// Solidity: function depositUnlockingTimestamp(address payee) view returns(uint64)
+func (c *DepositCaller) DepositUnlockingTimestamp(opts *bind.CallOpts, payee types.Address) (uint64, error) {
+	var out []interface{}
+	if err := c.contract.Call(opts, &out, "depositUnlockingTimestamp", payee); err != nil {
+		return 0, err
+	}
+	return *abi.ConvertType(out[0], new(uint64)).(*uint64), nil
+}
+
+// GetDepositCount returns the total amount currently deposited.
+//
+// Solidity: function getDepositCount() view returns(uint256)
+func (c *DepositCaller) GetDepositCount(opts *bind.CallOpts) (*uint256.Int, error) {
+	var out []interface{}
+	if err := c.contract.Call(opts, &out, "getDepositCount"); err != nil {
+		return nil, err
+	}
+	return toUint256(out[0])
+}
+
+// Deposit stakes opts.Value for the validator identified by data.
+//
+// Solidity: function deposit(bytes pubkey, bytes signature) payable returns()
+func (t *DepositTransactor) Deposit(opts *bind.TransactOpts, data *DepositData) (*transaction.Transaction, error) {
+	return t.contract.Transact(opts, "deposit", data.PublicKey[:], data.Signature[:])
+}
+
+// Withdraw releases the deposit of the sender once it is unlocked.
+//
+// Solidity: function withdraw() payable returns()
+func (t *DepositTransactor) Withdraw(opts *bind.TransactOpts) (*transaction.Transaction, error) {
+	return t.contract.Transact(opts, "withdraw")
+}
+
+// FilterDepositEvents returns the deposit events emitted in the given range.
+func (f *DepositFilterer) FilterDepositEvents(opts *bind.FilterOpts) ([]*DepositEvent, error) {
+	logs, sub, err := f.contract.FilterLogs(opts, "DepositEvent")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var events []*DepositEvent
+	for {
+		select {
+		case l := <-logs:
+			ev, err := ParseDepositEvent(l)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ev)
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, err
+			}
+			// The producer may finish while logs are still buffered.
+			for {
+				select {
+				case l := <-logs:
+					ev, err := ParseDepositEvent(l)
+					if err != nil {
+						return nil, err
+					}
+					events = append(events, ev)
+				default:
+					return events, nil
+				}
+			}
+		}
+	}
+}
+
+// ParseDepositEvent decodes a DepositEvent log.
+func ParseDepositEvent(l block.Log) (*DepositEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, errNoEventSignature
+	}
+	if l.Topics[0] != DepositEventID {
+		return nil, fmt.Errorf("not a DepositEvent log: topic %x", l.Topics[0])
+	}
+	ev := &DepositEvent{Raw: l}
+	if err := DepositABI.UnpackIntoInterface(ev, "DepositEvent", l.Data); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// ParseWithdrawnEvent decodes a WithdrawnEvent log.
+func ParseWithdrawnEvent(l block.Log) (*WithdrawnEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, errNoEventSignature
+	}
+	if l.Topics[0] != WithdrawnEventID {
+		return nil, fmt.Errorf("not a WithdrawnEvent log: topic %x", l.Topics[0])
+	}
+	ev := &WithdrawnEvent{Raw: l}
+	if err := DepositABI.UnpackIntoInterface(ev, "WithdrawnEvent", l.Data); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func toUint256(v interface{}) (*uint256.Int, error) {
+	b, ok := v.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected return type %T", v)
+	}
+	u, overflow := uint256.FromBig(b)
+	if overflow {
+		return nil, fmt.Errorf("value %v overflows uint256", b)
+	}
+	return u, nil
+}
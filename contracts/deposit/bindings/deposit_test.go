@@ -0,0 +1,107 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package bindings
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+func newTestDeposit(t *testing.T, amount uint64) *DepositData {
+	t.Helper()
+	sk, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewDepositData(sk, uint256.NewInt(amount))
+}
+
+func TestDepositDataVerify(t *testing.T) {
+	d := newTestDeposit(t, 50000)
+	if err := d.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	d.Amount = uint256.NewInt(50001)
+	if err := d.Verify(); err != ErrInvalidSignature {
+		t.Fatalf("Verify with tampered amount: have %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestPackUnpackDeposit(t *testing.T) {
+	d := newTestDeposit(t, 1)
+	input, err := PackDeposit(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnpackDeposit(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PublicKey != d.PublicKey || got.Signature != d.Signature {
+		t.Errorf("round trip mismatch")
+	}
+	if _, err := UnpackDeposit([]byte{1, 2, 3, 4}); err != ErrNotDepositCall {
+		t.Errorf("have %v, want %v", err, ErrNotDepositCall)
+	}
+}
+
+func TestParseDepositEvent(t *testing.T) {
+	d := newTestDeposit(t, 42)
+	data, err := DepositABI.Events["DepositEvent"].Inputs.Pack(d.PublicKey[:], d.Amount.ToBig(), d.Signature[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := ParseDepositEvent(block.Log{Topics: []types.Hash{DepositEventID}, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ev.DepositData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hash() != d.Hash() {
+		t.Errorf("event hash %x, want %x", got.Hash(), d.Hash())
+	}
+	if err := got.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+	if _, err := ParseDepositEvent(block.Log{Topics: []types.Hash{WithdrawnEventID}, Data: data}); err == nil {
+		t.Errorf("expected error for a WithdrawnEvent topic")
+	}
+}
+
+func TestDepositRoot(t *testing.T) {
+	if root := DepositRoot(nil); root != (types.Hash{}) {
+		t.Errorf("empty root = %x", root)
+	}
+	deposits := []*DepositData{newTestDeposit(t, 1), newTestDeposit(t, 2), newTestDeposit(t, 3)}
+	if root := DepositRoot(deposits[:1]); root != deposits[0].Hash() {
+		t.Errorf("single deposit root should equal its hash")
+	}
+	root := DepositRoot(deposits)
+	if root != DepositRoot(deposits) {
+		t.Errorf("root is not deterministic")
+	}
+	reordered := []*DepositData{deposits[1], deposits[0], deposits[2]}
+	if root == DepositRoot(reordered) {
+		t.Errorf("root should depend on deposit order")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package bindings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+var (
+	ErrInvalidSignature = errors.New("deposit signature does not match public key and amount")
+	ErrNotDepositCall   = errors.New("input is not a deposit call")
+)
+
+// DepositData is the payload of a deposit: a BLS public key and its signature
+// over the big-endian deposit amount.
+type DepositData struct {
+	PublicKey types.PublicKey
+	Signature types.Signature
+	Amount    *uint256.Int
+}
+
+// NewDepositData signs amount with sk.
+func NewDepositData(sk bls.SecretKey, amount *uint256.Int) *DepositData {
+	d := &DepositData{Amount: amount.Clone()}
+	copy(d.PublicKey[:], sk.PublicKey().Marshal())
+	copy(d.Signature[:], sk.Sign(amount.Bytes()).Marshal())
+	return d
+}
+
+// Verify checks the signature the same way consensus does when it processes
+// a DepositEvent.
+func (d *DepositData) Verify() error {
+	if d.Amount == nil {
+		return errors.New("deposit amount is not set")
+	}
+	sig, err := bls.SignatureFromBytes(d.Signature[:])
+	if err != nil {
+		return fmt.Errorf("invalid BLS signature: %w", err)
+	}
+	pub, err := bls.PublicKeyFromBytes(d.PublicKey[:])
+	if err != nil {
+		return fmt.Errorf("invalid BLS public key: %w", err)
+	}
+	if !sig.Verify(pub, d.Amount.Bytes()) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Hash returns keccak256(pubkey || amount || signature), with the amount
+// encoded as 32 big-endian bytes.
+func (d *DepositData) Hash() types.Hash {
+	var amount [32]byte
+	if d.Amount != nil {
+		amount = d.Amount.Bytes32()
+	}
+	return crypto.Keccak256Hash(d.PublicKey[:], amount[:], d.Signature[:])
+}
+
+// PackDeposit returns the calldata of a deposit(bytes,bytes) call. The amount
+// is not part of the calldata, it must be sent as the transaction value.
+func PackDeposit(d *DepositData) ([]byte, error) {
+	return DepositABI.Pack("deposit", d.PublicKey[:], d.Signature[:])
+}
+
+// UnpackDeposit decodes the public key and signature from deposit calldata.
+// The returned DepositData has no amount.
+func UnpackDeposit(input []byte) (*DepositData, error) {
+	if len(input) < 4 || !bytes.Equal(input[:4], DepositMethodID[:]) {
+		return nil, ErrNotDepositCall
+	}
+	args, err := DepositABI.Methods["deposit"].Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	return newDepositData(args[0].([]byte), args[1].([]byte), nil)
+}
+
+// UnpackDepositEvent decodes the data of a DepositEvent log.
+func UnpackDepositEvent(data []byte) (*DepositData, error) {
+	args, err := DepositABI.Unpack("DepositEvent", data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unpack logs: %w", err)
+	}
+	return newDepositData(args[0].([]byte), args[2].([]byte), args[1].(*big.Int))
+}
+
+// DepositData converts the event into DepositData.
+func (ev *DepositEvent) DepositData() (*DepositData, error) {
+	return newDepositData(ev.Pubkey, ev.Signature, ev.WeiAmount)
+}
+
+func newDepositData(pub, sig []byte, amount *big.Int) (*DepositData, error) {
+	if len(pub) != types.PublicKeyLength {
+		return nil, fmt.Errorf("public key has %d bytes, want %d", len(pub), types.PublicKeyLength)
+	}
+	if len(sig) != types.SignatureLength {
+		return nil, fmt.Errorf("signature has %d bytes, want %d", len(sig), types.SignatureLength)
+	}
+	d := new(DepositData)
+	copy(d.PublicKey[:], pub)
+	copy(d.Signature[:], sig)
+	if amount != nil {
+		var overflow bool
+		if d.Amount, overflow = uint256.FromBig(amount); overflow {
+			return nil, fmt.Errorf("deposit amount %v overflows uint256", amount)
+		}
+	}
+	return d, nil
+}
+
+// DepositRoot returns the root of a binary keccak256 Merkle tree over the
+// deposit hashes, in order. Levels with an odd number of nodes are padded
+// with the zero hash; an empty list has the zero root.
+func DepositRoot(deposits []*DepositData) types.Hash {
+	if len(deposits) == 0 {
+		return types.Hash{}
+	}
+	level := make([]types.Hash, len(deposits))
+	for i, d := range deposits {
+		level[i] = d.Hash()
+	}
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, types.Hash{})
+		}
+		next := level[:len(level)/2]
+		for i := range next {
+			next[i] = crypto.Keccak256Hash(level[2*i][:], level[2*i+1][:])
+		}
+		level = next
+	}
+	return level[0]
+}
@@ -21,18 +21,20 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/n42blockchain/N42/common"
-	"github.com/n42blockchain/N42/common/crypto/bls"
 	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/contracts/deposit/bindings"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/params"
 	"sync"
 )
 
 const (
+	// logsChanSize is the number of log events buffered for the deposit handler.
+	logsChanSize = 16
 	//
 	DayPerMonth = 30
 	//
@@ -63,7 +65,7 @@ const (
 type DepositContract interface {
 	WithdrawnSignature() types.Hash
 	DepositSignature() types.Hash
-	UnpackDepositLogData(data []byte) (publicKey []byte, signature []byte, depositAmount *uint256.Int, err error)
+	UnpackDepositLogData(data []byte) (*bindings.DepositData, error)
 	IsDepositAction(sig [4]byte) bool
 }
 
@@ -138,11 +140,8 @@ type Deposit struct {
 	blockChain common.IBlockChain
 	db         kv.RwDB
 
-	logsSub   event.Subscription // Subscription for new log event
-	rmLogsSub event.Subscription // Subscription for removed log event
-
-	logsCh   chan common.NewLogsEvent     // Channel to receive new log event
-	rmLogsCh chan common.RemovedLogsEvent // Channel to receive removed log event
+	logsSub   *eventv3.Subscription[common.NewLogsEvent]     // Subscription for new log event
+	rmLogsSub *eventv3.Subscription[common.RemovedLogsEvent] // Subscription for removed log event
 
 	depositContracts map[types.Address]DepositContract
 }
@@ -154,17 +153,13 @@ func NewDeposit(ctx context.Context, bc common.IBlockChain, db kv.RwDB, depositC
 		cancel:           cancel,
 		blockChain:       bc,
 		db:               db,
-		logsCh:           make(chan common.NewLogsEvent),
-		rmLogsCh:         make(chan common.RemovedLogsEvent),
 		depositContracts: depositContracts,
 	}
 
-	d.logsSub = event.GlobalEvent.Subscribe(d.logsCh)
-	d.rmLogsSub = event.GlobalEvent.Subscribe(d.rmLogsCh)
-
-	if d.logsSub == nil || d.rmLogsSub == nil {
-		log.Error("Subscribe for event system failed")
-	}
+	// Deposits and withdrawals must not be missed, so the chain waits for
+	// the deposit handler
+	d.logsSub = common.NewLogsTopic.Subscribe(logsChanSize, eventv3.Block)
+	d.rmLogsSub = common.RemovedLogsTopic.Subscribe(logsChanSize, eventv3.Block)
 	return d
 }
 
@@ -216,7 +211,7 @@ func (d *Deposit) eventLoop() {
 
 	for {
 		select {
-		case logEvent := <-d.logsCh:
+		case logEvent := <-d.logsSub.Chan():
 			for _, l := range logEvent.Logs {
 				if depositContract, found := d.depositContracts[l.Address]; found {
 					if l.Topics[0] == depositContract.DepositSignature() {
@@ -226,7 +221,7 @@ func (d *Deposit) eventLoop() {
 					}
 				}
 			}
-		case logRemovedEvent := <-d.rmLogsCh:
+		case logRemovedEvent := <-d.rmLogsSub.Chan():
 			for _, l := range logRemovedEvent.Logs {
 				log.Info("logEvent", "address", l.Address, "data", l.Data, "")
 			}
@@ -242,26 +237,15 @@ func (d *Deposit) eventLoop() {
 
 func (d *Deposit) handleDepositEvent(txHash types.Hash, data []byte, depositContract DepositContract) {
 	// 1
-	pb, sig, amount, err := depositContract.UnpackDepositLogData(data)
+	deposit, err := depositContract.UnpackDepositLogData(data)
 	if err != nil {
 		log.Warn("cannot unpack deposit log data", "err", err)
 		return
 	}
+	amount := deposit.Amount
 	// 2
-	signature, err := bls.SignatureFromBytes(sig)
-	if err != nil {
-		log.Warn("cannot unpack BLS signature", "signature", hexutil.Encode(sig), "err", err)
-		return
-	}
-	// 3
-	publicKey, err := bls.PublicKeyFromBytes(pb)
-	if err != nil {
-		log.Warn("cannot unpack BLS publicKey", "publicKey", hexutil.Encode(pb), "err", err)
-		return
-	}
-	// 4
-	log.Trace("DepositEvent verify:", "signature", hexutil.Encode(signature.Marshal()), "publicKey", hexutil.Encode(publicKey.Marshal()), "msg", hexutil.Encode(amount.Bytes()))
-	if signature.Verify(publicKey, amount.Bytes()) {
+	log.Trace("DepositEvent verify:", "signature", hexutil.Encode(deposit.Signature[:]), "publicKey", hexutil.Encode(deposit.PublicKey[:]), "msg", hexutil.Encode(amount.Bytes()))
+	if err = deposit.Verify(); err == nil {
 		var tx *transaction.Transaction
 		rwTx, err := d.db.BeginRw(d.ctx)
 		defer rwTx.Rollback()
@@ -278,14 +262,11 @@ func (d *Deposit) handleDepositEvent(txHash types.Hash, data []byte, depositCont
 		if tx != nil {
 			log.Info("add Deposit info", "address", tx.From(), "amount", amount.String())
 
-			var pub types.PublicKey
-			pub.SetBytes(publicKey.Marshal())
-			//
-			rawdb.PutDeposit(rwTx, *tx.From(), pub, *amount)
+			rawdb.PutDeposit(rwTx, *tx.From(), deposit.PublicKey, *amount)
 			rwTx.Commit()
 		}
 	} else {
-		log.Error("DepositEvent cannot Verify signature", "signature", hexutil.Encode(sig), "publicKey", hexutil.Encode(pb), "message", hexutil.Encode(amount.Bytes()), "err", err)
+		log.Error("DepositEvent cannot Verify signature", "signature", hexutil.Encode(deposit.Signature[:]), "publicKey", hexutil.Encode(deposit.PublicKey[:]), "message", hexutil.Encode(amount.Bytes()), "err", err)
 	}
 }
 
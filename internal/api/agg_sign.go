@@ -30,10 +30,11 @@ import (
 	"github.com/n42blockchain/N42/contracts/deposit"
 	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/state"
 	"golang.org/x/crypto/sha3"
+	"time"
 )
 
 var sigChannel = make(chan AggSign, 10)
@@ -171,6 +172,11 @@ func (s *AggSign) Check(root types.Hash) bool {
 	if s.StateRoot != root {
 		return false
 	}
+	return s.verify()
+}
+
+// verify checks the signature over the state root the sign claims.
+func (s *AggSign) verify() bool {
 	sig, err := bls.SignatureFromBytes(s.Sign[:])
 	if nil != err {
 		return false
@@ -202,27 +208,41 @@ func IsDeposit(db kv.RwDB, addr types.Address) (bool, error) {
 	return rawdb.IsDeposit(tx, addr), nil
 }
 
+// minAggSigns is the number of verifier signatures a block needs.
+const minAggSigns = 3
+
 func SignMerge(ctx context.Context, header *block.Header, depositNum uint64) (types.Signature, []*block.Verify, error) {
 	aggrSigns := make([]bls.Signature, 0)
 	verifiers := make([]*block.Verify, 0)
 	uniq := make(map[types.Address]struct{})
 
+	number := header.Number.Uint64()
+	start := time.Now()
+	defer func() {
+		signMergeTimer.Observe(time.Since(start).Seconds())
+		verifyRoundSigns.Set(uint64(len(aggrSigns)))
+		verifyRounds.finish(number)
+	}()
+
 LOOP:
 	for {
 		select {
 		case s := <-sigChannel:
 			log.Tracef("accept sign, %+v", s)
-			if s.Number != header.Number.Uint64() {
-				log.Tracef("discard sign: need block number %d, get %d", header.Number.Uint64(), s.Number)
+			if s.Number != number {
+				log.Tracef("discard sign: need block number %d, get %d", number, s.Number)
+				verifySignsStale.Inc()
 				continue
 			}
 
 			if _, ok := uniq[s.Address]; ok {
+				verifySignsDuplicate.Inc()
 				continue
 			}
 
 			if !s.Check(header.Root) {
 				log.Tracef("discard sign: sign check failed! %v", s)
+				verifySignsInvalid.Inc()
 				continue
 			}
 			sig, err := bls.SignatureFromBytes(s.Sign[:])
@@ -236,6 +256,16 @@ LOOP:
 				PublicKey: s.PublicKey,
 			})
 			uniq[s.Address] = struct{}{}
+			verifySignsAccepted.Inc()
+			if elapsed, ok := verifyRounds.since(number, time.Now()); ok {
+				verifySignTimer.Observe(elapsed.Seconds())
+				if len(aggrSigns) == 1 {
+					verifyFirstSignTimer.Observe(elapsed.Seconds())
+				}
+				if len(aggrSigns) == minAggSigns {
+					verifyQuorumTimer.Observe(elapsed.Seconds())
+				}
+			}
 		case <-ctx.Done():
 			break LOOP
 		}
@@ -244,9 +274,11 @@ LOOP:
 	// 1
 	// uint64(len(aggrSigns)) < depositNum/2
 	// uint64(len(aggrSigns)) < 7
-	if uint64(len(aggrSigns)) < 3 {
+	if len(aggrSigns) < minAggSigns {
+		verifyRoundsInsufficient.Inc()
 		return types.Signature{}, nil, consensus.ErrNotEnoughSign
 	}
+	verifyRoundsOK.Inc()
 
 	aggS := blst.AggregateSignatures(aggrSigns)
 	var aggSign types.Signature
@@ -255,9 +287,9 @@ LOOP:
 }
 
 func MachineVerify(ctx context.Context) error {
-	entire := make(chan common.MinedEntireEvent)
-	blocksSub := event.GlobalEvent.Subscribe(entire)
+	blocksSub := common.MinedEntireTopic.Subscribe(0, eventv3.Block)
 	defer blocksSub.Unsubscribe()
+	entire := blocksSub.Chan()
 
 	errs := make(chan error)
 	defer close(errs)
@@ -21,12 +21,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/holiman/uint256"
-	"github.com/n42blockchain/N42/conf"
 	"github.com/n42blockchain/N42/internal"
 	"github.com/n42blockchain/N42/internal/api/filters"
 	vm2 "github.com/n42blockchain/N42/internal/vm"
 	"github.com/n42blockchain/N42/internal/vm/evmtypes"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/modules/state"
 	"github.com/n42blockchain/N42/turbo/rpchelper"
 
@@ -47,6 +46,7 @@ import (
 	avmcommon "github.com/n42blockchain/N42/common/avmutil"
 	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
 	"github.com/n42blockchain/N42/internal/consensus"
+	"github.com/n42blockchain/N42/internal/consensus/misc"
 	"github.com/n42blockchain/N42/log"
 	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
@@ -70,7 +70,12 @@ type API struct {
 	accountManager *accounts.Manager
 	chainConfig    *params.ChainConfig
 
-	gpo *Oracle
+	gpo    *Oracle
+	miner  common.IMiner
+	syncer common.ISyncer
+
+	extRPCEnabled bool
+	readOnly      bool
 }
 
 // NewAPI creates a new protocol API.
@@ -89,9 +94,43 @@ func (api *API) SetGpo(gpo *Oracle) {
 	api.gpo = gpo
 }
 
+// SetMiner attaches the local sealing service so the mining RPCs report its
+// state.
+func (api *API) SetMiner(miner common.IMiner) {
+	api.miner = miner
+}
+
+// SetSyncer attaches the block downloader so eth_syncing reports its
+// progress.
+func (api *API) SetSyncer(syncer common.ISyncer) {
+	api.syncer = syncer
+}
+
+// SetExtRPCEnabled records whether the RPC endpoints are reachable from
+// outside the node, which disables password based account unlocking unless
+// insecure unlocking is allowed.
+func (api *API) SetExtRPCEnabled(enabled bool) {
+	api.extRPCEnabled = enabled
+}
+
+// SetReadOnly records whether the node serves a database it cannot write,
+// which also means transactions cannot be propagated.
+func (api *API) SetReadOnly(readOnly bool) {
+	api.readOnly = readOnly
+}
+
+// Miner returns the local sealing service, or nil if none is attached.
+func (api *API) Miner() common.IMiner {
+	return api.miner
+}
+
+func (api *API) Syncer() common.ISyncer {
+	return api.syncer
+}
+
 func (api *API) Apis() []jsonrpc.API {
 	nonceLock := new(AddrLocker)
-	return []jsonrpc.API{
+	apis := []jsonrpc.API{
 		{
 			Namespace: "eth",
 			Service:   NewBlockChainAPI(api),
@@ -115,17 +154,43 @@ func (api *API) Apis() []jsonrpc.API {
 		{
 			Namespace: "txpool",
 			Service:   NewTxsPoolAPI(api),
+		}, {
+			Namespace:     "txpool",
+			Service:       NewTxsPoolAdminAPI(api),
+			Authenticated: true,
+		}, {
+			Namespace:     "eth",
+			Service:       NewBundleAPI(api),
+			Authenticated: true,
+		}, {
+			Namespace: "admin",
+			Service:   NewAdminAPI(api),
+		}, {
+			Namespace: "staking",
+			Service:   NewStakingAPI(api),
 		}, {
 			Namespace: "eth",
 			Service:   filters.NewFilterAPI(api, 5*time.Minute),
 		},
 	}
+	// Accounts cannot be unlocked over endpoints reachable from outside the
+	// node, so the personal namespace is only served when they can be.
+	if !api.extRPCEnabled || (api.accountManager != nil && api.accountManager.Config().InsecureUnlockAllowed) {
+		apis = append(apis, jsonrpc.API{
+			Namespace: "personal",
+			Service:   NewPersonalAPI(api, nonceLock),
+		})
+	}
+	return apis
 }
 
 func (n *API) TxsPool() common.ITxsPool       { return n.txspool }
 func (n *API) Database() kv.RwDB              { return n.db }
 func (n *API) Engine() consensus.Engine       { return n.engine }
 func (n *API) BlockChain() common.IBlockChain { return n.bc }
+func (n *API) RPCTransaction(tx *transaction.Transaction) interface{} {
+	return newRPCPendingTransaction(tx, n.bc.CurrentBlock().Header())
+}
 func (n *API) GetEvm(ctx context.Context, msg internal.Message, ibs evmtypes.IntraBlockState, header block.IHeader, vmConfig *vm2.Config) (*vm2.EVM, func() error, error) {
 	vmError := func() error { return nil }
 
@@ -142,20 +207,33 @@ func (n *API) GetEvm(ctx context.Context, msg internal.Message, ibs evmtypes.Int
 	return vm2.NewEVM(context, txContext, ibs, n.GetChainConfig(), *vmConfig), vmError, nil
 }
 
-func (n *API) State(tx kv.Tx, blockNrOrHash jsonrpc.BlockNumberOrHash) evmtypes.IntraBlockState {
-
-	_, blockHash, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx)
+func (n *API) State(ctx context.Context, tx kv.Tx, blockNrOrHash jsonrpc.BlockNumberOrHash) evmtypes.IntraBlockState {
+	ibs, err := n.StateAt(ctx, tx, blockNrOrHash)
 	if err != nil {
 		return nil
 	}
+	return ibs
+}
+
+// errHeaderNotFound is returned for state queries at blocks the node does not
+// have.
+var errHeaderNotFound = errors.New("header not found")
 
+// StateAt returns the state after the canonical block blockNrOrHash refers
+// to, read through tx. The state of blocks below the head is reconstructed
+// from the change sets and history indices, so every block since genesis is
+// served. Its reads fail once ctx is done.
+func (n *API) StateAt(ctx context.Context, tx kv.Tx, blockNrOrHash jsonrpc.BlockNumberOrHash) (*state.IntraBlockState, error) {
+	_, blockHash, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx)
+	if err != nil {
+		return nil, err
+	}
 	blockNr := rawdb.ReadHeaderNumber(tx, blockHash)
-	if nil == blockNr {
-		return nil
+	if blockNr == nil {
+		return nil, errHeaderNotFound
 	}
-
-	stateReader := state.NewPlainState(tx, *blockNr+1)
-	return state.New(stateReader)
+	reader := state.NewHistoricalStateReader(tx, *blockNr)
+	return state.New(state.NewContextReader(ctx, state.NewCachedReader(reader, tx.ViewID(), *blockNr+1))), nil
 }
 
 func (n *API) GetChainConfig() *params.ChainConfig {
@@ -178,19 +256,14 @@ func NewN42API(api *API) *n42API {
 
 // GasPrice returns a suggestion for a gas price for legacy transactions.
 func (s *n42API) GasPrice(ctx context.Context) (*hexutil.Big, error) {
-	conf.LightClientGPO.Default = big.NewInt(params.GWei)
-	//oracle := NewOracle(s.api.BlockChain(), conf.LightClientGPO)
 	tipcap, err := s.api.gpo.SuggestTipCap(ctx, s.api.GetChainConfig())
 	if err != nil {
 		return nil, err
 	}
-	if head := s.api.BlockChain().CurrentBlock().Header(); head.BaseFee64() != uint256.NewInt(0) {
+	if head := s.api.BlockChain().CurrentBlock().Header(); head.BaseFee64() != nil && !head.BaseFee64().IsZero() {
 		tipcap.Add(tipcap, head.BaseFee64().ToBig())
 	}
 	return (*hexutil.Big)(tipcap), nil
-	//todo hardcode 13Gwei
-	//tipcap := 13000000000
-	//return (*hexutil.Big)(new(big.Int).SetUint64(uint64(tipcap))), nil
 }
 
 // MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
@@ -202,6 +275,13 @@ func (s *n42API) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error)
 	return (*hexutil.Big)(tipcap), err
 }
 
+// BlobBaseFee returns the base fee per blob gas of the next block.
+func (s *n42API) BlobBaseFee(ctx context.Context) *hexutil.Big {
+	head := s.api.BlockChain().CurrentBlock().Header().(*block.Header)
+	excessBlobGas := misc.CalcExcessBlobGas(s.api.GetChainConfig(), head)
+	return (*hexutil.Big)(transaction.CalcBlobFee(excessBlobGas).ToBig())
+}
+
 type feeHistoryResult struct {
 	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
 	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
@@ -337,9 +417,9 @@ func (s *BlockChainAPI) GetBalance(ctx context.Context, address avmcommon.Addres
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
-	if state == nil {
-		return nil, nil
+	state, err := s.api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	balance := state.GetBalance(*avmtypes.ToastAddress(&address))
 	return (*hexutil.Big)(balance.ToBig()), nil
@@ -359,9 +439,9 @@ func (s *BlockChainAPI) GetCode(ctx context.Context, address avmcommon.Address,
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
-	if state == nil {
-		return nil, nil
+	state, err := s.api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	code := state.GetCode(*avmtypes.ToastAddress(&address))
 	return code, nil
@@ -377,39 +457,28 @@ func (s *BlockChainAPI) GetStorageAt(ctx context.Context, address types.Address,
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
-	if state == nil {
-		return nil, nil
+	state, err := s.api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	var va uint256.Int
 	k := types.HexToHash(key)
 	state.GetState(address, &k, &va)
-	return va.Bytes(), nil
+	value := va.Bytes32()
+	return value[:], nil
 }
 
-// GetUncleCountByBlockHash returns number of uncles in the block for the given block hash
+// GetUncleCountByBlockHash returns number of uncles in the block for the given
+// block hash, always zero for a known block, see uncleCount.
 func (s *BlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, blockHash avmcommon.Hash) *hexutil.Uint {
-	if block, _ := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(blockHash)); block != nil {
-		//POA donot have Uncles
-		n := hexutil.Uint(0)
-		return &n
-	}
-	return nil
+	b, _ := s.api.BlockChain().GetBlockByHashContext(ctx, avmtypes.ToastHash(blockHash))
+	return uncleCount(b)
 }
 
-// GetUncleByBlockHashAndIndex returns the uncle block for the given block hash and index.
+// GetUncleByBlockHashAndIndex returns the uncle block for the given block hash
+// and index. N42 blocks have no uncles, so it always returns null.
 func (s *BlockChainAPI) GetUncleByBlockHashAndIndex(ctx context.Context, blockHash avmcommon.Hash, index hexutil.Uint) (map[string]interface{}, error) {
-	b, err := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(blockHash))
-	if b != nil {
-		//POA donot have Uncles
-		var uncles []struct{}
-		if index >= hexutil.Uint(len(uncles)) {
-			return nil, nil
-		}
-		block := block.NewBlock(&block.Header{}, nil)
-		return RPCMarshalBlock(block, s.api.BlockChain(), false, false)
-	}
-	return nil, err
+	return nil, nil
 }
 
 // Result structs for GetProof
@@ -538,11 +607,11 @@ func DoCall(ctx context.Context, api *API, args TransactionArgs, blockNrOrHash j
 		if blockNr < jsonrpc.EarliestBlockNumber {
 			header = api.BlockChain().CurrentBlock().Header()
 		} else {
-			header = api.BlockChain().GetHeaderByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+			header, err = api.BlockChain().GetHeaderByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 		}
 	}
 	if hash, ok := blockNrOrHash.Hash(); ok {
-		header, err = api.BlockChain().GetHeaderByHash(hash)
+		header, err = api.BlockChain().GetHeaderByHashContext(ctx, hash)
 	}
 	if err != nil {
 		return nil, err
@@ -556,11 +625,11 @@ func DoCall(ctx context.Context, api *API, args TransactionArgs, blockNrOrHash j
 
 	//reader := state.NewPlainStateReader(tx)
 	//ibs := state.New(reader)
-	ibs := api.State(tx, blockNrOrHash)
-	if ibs == nil {
-		return nil, errors.New("cannot load state")
+	ibs, err := api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
-	if err := overrides.Apply(ibs.(*state.IntraBlockState)); err != nil {
+	if err := overrides.Apply(ibs); err != nil {
 		return nil, err
 	}
 	// Setup context so it may be cancelled the call has completed
@@ -621,6 +690,23 @@ func newRevertError(result *internal.ExecutionResult) *revertError {
 	}
 }
 
+// revertReason returns the revert data captured when the transaction of a
+// failed receipt was imported, or nil if none was stored.
+func (api *API) revertReason(ctx context.Context, receipt *block.Receipt) hexutil.Bytes {
+	if receipt.Status != block.ReceiptStatusFailed {
+		return nil
+	}
+	var reason []byte
+	if err := api.Database().View(ctx, func(tx kv.Tx) (err error) {
+		reason, err = rawdb.ReadRevertReason(tx, receipt.TxHash)
+		return err
+	}); err != nil {
+		log.Debug("Failed to read revert reason", "hash", receipt.TxHash, "err", err)
+		return nil
+	}
+	return reason
+}
+
 // revertError is an API error that encompassas an EVM revertal with JSON error
 // code and a binary data blob.
 type revertError struct {
@@ -654,8 +740,8 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 	if err != nil {
 		return nil, err
 	}
-	// If the result contains a revert reason, try to unpack and return it.
-	if len(result.Revert()) > 0 {
+	// If the call reverted, return the revert data with the error.
+	if errors.Is(result.Err, vm2.ErrExecutionReverted) {
 		return nil, newRevertError(result)
 	}
 	return result.Return(), result.Err
@@ -673,13 +759,34 @@ func BlockByNumber(ctx context.Context, number jsonrpc.BlockNumber, n *API) (blo
 		iblock := n.BlockChain().CurrentBlock()
 		return iblock, nil
 	}
-	iblock, err := n.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(number)))
+	if number == jsonrpc.FinalizedBlockNumber || number == jsonrpc.SafeBlockNumber {
+		finality, err := finalityBlockNumber(ctx, n.db, number)
+		if err != nil {
+			return nil, err
+		}
+		return n.BlockChain().GetBlockByNumberContext(ctx, finality)
+	}
+	iblock, err := n.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(number)))
 	if err != nil {
 		return nil, err
 	}
 	return iblock, nil
 }
 
+// finalityBlockNumber resolves the "finalized" and "safe" block tags to the
+// number of the checkpoint tracked by the blockchain.
+func finalityBlockNumber(ctx context.Context, db kv.RoDB, number jsonrpc.BlockNumber) (blockNumber *uint256.Int, err error) {
+	err = db.View(ctx, func(tx kv.Tx) error {
+		if number == jsonrpc.FinalizedBlockNumber {
+			blockNumber, err = rpchelper.GetFinalizedBlockNumber(tx)
+		} else {
+			blockNumber, err = rpchelper.GetSafeBlockNumber(tx)
+		}
+		return err
+	})
+	return blockNumber, err
+}
+
 func BlockByNumberOrHash(ctx context.Context, blockNrOrHash jsonrpc.BlockNumberOrHash, api *API) (block.IBlock, error) {
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		if blockNr == jsonrpc.PendingBlockNumber {
@@ -688,7 +795,7 @@ func BlockByNumberOrHash(ctx context.Context, blockNrOrHash jsonrpc.BlockNumberO
 		return BlockByNumber(ctx, blockNr, api)
 	}
 	if hash, ok := blockNrOrHash.Hash(); ok {
-		iblock, err := api.BlockChain().GetBlockByHash(types.Hash(hash))
+		iblock, err := api.BlockChain().GetBlockByHashContext(ctx, types.Hash(hash))
 		if err != nil {
 			return nil, err
 		}
@@ -757,7 +864,7 @@ func BlockByNumberOrHash(ctx context.Context, blockNrOrHash jsonrpc.BlockNumberO
 //	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 //}
 
-func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrHash jsonrpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrHash jsonrpc.BlockNumberOrHash, overrides *StateOverride, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  = params.TxGas - 1
@@ -800,9 +907,12 @@ func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrH
 			return 0, err
 		}
 		defer tx.Rollback()
-		statedb := n.State(tx, blockNrOrHash)
-		if statedb == nil {
-			return 0, errors.New("cannot load stateDB")
+		statedb, err := n.StateAt(ctx, tx, blockNrOrHash)
+		if err != nil {
+			return 0, err
+		}
+		if err := overrides.Apply(statedb); err != nil {
+			return 0, err
 		}
 		balance := statedb.GetBalance(*avmtypes.ToastAddress(args.From)) // from
 
@@ -837,7 +947,7 @@ func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrH
 	// Create a helper to check if a gas allowance results in an executable transaction
 	executable := func(gas uint64) (bool, *internal.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
-		result, err := DoCall(ctx, n, args, blockNrOrHash, nil, 0, gasCap)
+		result, err := DoCall(ctx, n, args, blockNrOrHash, overrides, 0, gasCap)
 		if err != nil {
 			if errors.Is(err, internal.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -871,7 +981,7 @@ func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrH
 		}
 		if failed {
 			if result != nil && !errors.Is(result.Err, vm2.ErrOutOfGas) {
-				if len(result.Revert()) > 0 {
+				if errors.Is(result.Err, vm2.ErrExecutionReverted) {
 					return 0, newRevertError(result)
 				}
 				return 0, result.Err
@@ -885,13 +995,14 @@ func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrH
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *jsonrpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block, or the given block
+// with the state overrides applied.
+func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *jsonrpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Uint64, error) {
 	bNrOrHash := jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, s.api, args, bNrOrHash, rpcGasCap)
+	return DoEstimateGas(ctx, s.api, args, bNrOrHash, overrides, rpcGasCap)
 }
 
 // GetBlockByNumber returns the requested canonical block.
@@ -909,8 +1020,10 @@ func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number jsonrpc.Blo
 	if number == jsonrpc.LatestBlockNumber {
 		block = s.api.BlockChain().CurrentBlock()
 		err = nil
+	} else if number == jsonrpc.FinalizedBlockNumber || number == jsonrpc.SafeBlockNumber {
+		block, err = BlockByNumber(ctx, number, s.api)
 	} else {
-		block, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(number.Int64())))
+		block, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(number.Int64())))
 	}
 
 	if block != nil && err == nil {
@@ -929,7 +1042,7 @@ func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number jsonrpc.Blo
 
 // GetBlockByHash get block by hash
 func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash avmcommon.Hash, fullTx bool) (map[string]interface{}, error) {
-	block, err := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(hash))
+	block, err := s.api.BlockChain().GetBlockByHashContext(ctx, avmtypes.ToastHash(hash))
 
 	if block != nil {
 		return RPCMarshalBlock(block, s.api.BlockChain(), true, fullTx)
@@ -952,8 +1065,8 @@ func (s *BlockChainAPI) MinedBlock(ctx context.Context, address types.Address) (
 
 	rpcSub := notifier.CreateSubscription()
 	go func() {
-		entire := make(chan common.MinedEntireEvent, 20)
-		blocksSub := event.GlobalEvent.Subscribe(entire)
+		blocksSub := common.MinedEntireTopic.Subscribe(20, eventv3.Block)
+		entire := blocksSub.Chan()
 		for {
 			select {
 			case b := <-entire:
@@ -990,6 +1103,7 @@ func (s *BlockChainAPI) SubmitSign(sign AggSign) error {
 		return fmt.Errorf("unauthed address: %s", sign.Address)
 	}
 	sign.PublicKey.SetBytes(info.PublicKey.Bytes())
+	recordDoubleSign(s.api.db, &sign)
 	go func() {
 		sigChannel <- sign
 	}()
@@ -1023,9 +1137,9 @@ func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address avmcom
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
-	if state == nil {
-		return nil, nil
+	state, err := s.api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 	nonce := state.GetNonce(*avmtypes.ToastAddress(&address))
 	return (*hexutil.Uint64)(&nonce), nil
@@ -1099,7 +1213,7 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash avmcomm
 	//	return nil, nil
 	//}
 	//log.Infof("GetTransactionReceipt, hash %+v , %+v, %+v, %+v", tx, blockHash, blockNumber, index)
-	receipts, err := s.api.BlockChain().GetReceipts(blockHash)
+	receipts, err := s.api.BlockChain().GetReceiptsContext(ctx, blockHash)
 
 	//log.Infof("GetTransactionReceipt, receipts %+v", receipts)
 	if err != nil {
@@ -1125,7 +1239,7 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash avmcomm
 		"type":              hexutil.Uint(tx.Type()),
 	}
 	// Assign the effective gas price paid
-	header, err := s.api.BlockChain().GetHeaderByHash(blockHash)
+	header, err := s.api.BlockChain().GetHeaderByHashContext(ctx, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -1137,6 +1251,9 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash avmcomm
 	} else {
 		fields["status"] = hexutil.Uint(receipt.Status)
 	}
+	if reason := s.api.revertReason(ctx, receipt); len(reason) > 0 {
+		fields["revertReason"] = reason
+	}
 	if receipt.Logs == nil {
 		fields["logs"] = []*avmtypes.Log{}
 	} else {
@@ -1154,7 +1271,7 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash avmcomm
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
 func (s *TransactionAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash avmcommon.Hash) *hexutil.Uint {
-	if block, _ := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(blockHash)); block != nil {
+	if block, _ := s.api.BlockChain().GetBlockByHashContext(ctx, avmtypes.ToastHash(blockHash)); block != nil {
 		n := hexutil.Uint(len(block.Transactions()))
 		return &n
 	}
@@ -1189,7 +1306,10 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash avmcommo
 	//}
 
 	if tx != nil {
-		header := s.api.BlockChain().GetHeaderByNumber(uint256.NewInt(blockNumber))
+		header, err := s.api.BlockChain().GetHeaderByNumberContext(ctx, uint256.NewInt(blockNumber))
+		if err != nil {
+			return nil, err
+		}
 		if header == nil {
 			return nil, nil
 		}
@@ -1205,7 +1325,7 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash avmcommo
 
 // GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
 func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash avmcommon.Hash, index hexutil.Uint) *RPCTransaction {
-	if block, _ := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(blockHash)); block != nil {
+	if block, _ := s.api.BlockChain().GetBlockByHashContext(ctx, avmtypes.ToastHash(blockHash)); block != nil {
 		for i, tx := range block.Transactions() {
 			if i == int(index) {
 				return newRPCTransaction(tx, avmtypes.ToastHash(blockHash), block.Number64().Uint64(), uint64(index), block.Header().BaseFee64().ToBig())
@@ -1215,8 +1335,15 @@ func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context,
 	return nil
 }
 
+// errReadOnlyNode is returned for transactions sent to a read-only node, which
+// has no peers to propagate them to.
+var errReadOnlyNode = errors.New("read-only node does not accept transactions")
+
 // SubmitTransaction ?
 func SubmitTransaction(ctx context.Context, api *API, tx *transaction.Transaction) (avmcommon.Hash, error) {
+	if api.readOnly {
+		return avmcommon.Hash{}, errReadOnlyNode
+	}
 
 	if err := checkTxFee(*tx.GasPrice(), tx.Gas(), baseFee); err != nil {
 		return avmcommon.Hash{}, err
@@ -1261,8 +1388,7 @@ func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionAr
 	if err != nil {
 		return avmcommon.Hash{}, err
 	}
-	// todo sign?
-	//signed := tx
+	signed.SetFrom(args.from())
 	return SubmitTransaction(ctx, s.api, signed)
 }
 
@@ -1302,8 +1428,8 @@ func NewDebugAPI(api *API) *DebugAPI {
 }
 
 // SetHead rewinds the head of the blockchain to a previous block.
-func (api *DebugAPI) SetHead(number hexutil.Uint64) {
-	api.api.BlockChain().SetHead(uint64(number))
+func (api *DebugAPI) SetHead(number hexutil.Uint64) error {
+	return api.api.BlockChain().SetHead(uint64(number))
 }
 
 func (debug *DebugAPI) GetAccount(ctx context.Context, address types.Address) {
@@ -69,27 +69,23 @@ func (b *API) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*type
 	if number == rpc.LatestBlockNumber {
 		return b.bc.CurrentBlock().Header().(*types.Header), nil
 	}
-	//if number == rpc.FinalizedBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'finalized' tag not supported on pre-merge network")
-	//	}
-	//	block := b.eth.blockchain.CurrentFinalBlock()
-	//	if block != nil {
-	//		return block, nil
-	//	}
-	//	return nil, errors.New("finalized block not found")
-	//}
-	//if number == rpc.SafeBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'safe' tag not supported on pre-merge network")
-	//	}
-	//	block := b.eth.blockchain.CurrentSafeBlock()
-	//	if block != nil {
-	//		return block, nil
-	//	}
-	//	return nil, errors.New("safe block not found")
-	//}
-	return b.bc.GetHeaderByNumber(uint256.NewInt(uint64(number.Int64()))).(*types.Header), nil
+	if number == rpc.FinalizedBlockNumber || number == rpc.SafeBlockNumber {
+		finality, err := finalityBlockNumber(ctx, b.db, number)
+		if err != nil {
+			return nil, err
+		}
+		return b.headerByNumber(ctx, finality)
+	}
+	return b.headerByNumber(ctx, uint256.NewInt(uint64(number.Int64())))
+}
+
+// headerByNumber reads the canonical header at number, bound to ctx.
+func (b *API) headerByNumber(ctx context.Context, number *uint256.Int) (*types.Header, error) {
+	header, err := b.bc.GetHeaderByNumberContext(ctx, number)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return header.(*types.Header), nil
 }
 
 func (b *API) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
@@ -97,7 +93,10 @@ func (b *API) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockN
 		return b.HeaderByNumber(ctx, blockNr)
 	}
 	if hash, ok := blockNrOrHash.Hash(); ok {
-		header, _ := b.bc.GetHeaderByHash(hash)
+		header, err := b.bc.GetHeaderByHashContext(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
 		if header == nil {
 			return nil, errors.New("header for hash not found")
 		}
@@ -110,7 +109,13 @@ func (b *API) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockN
 }
 
 func (b *API) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
-	iHeader, _ := b.bc.GetHeaderByHash(hash)
+	iHeader, err := b.bc.GetHeaderByHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if iHeader == nil {
+		return nil, errors.New("header for hash not found")
+	}
 	return iHeader.(*types.Header), nil
 }
 
@@ -125,29 +130,32 @@ func (b *API) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types
 		header := b.bc.CurrentBlock()
 		return b.bc.GetBlock(header.Hash(), header.Number64().Uint64()).(*types.Block), nil
 	}
-	//if number == rpc.FinalizedBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'finalized' tag not supported on pre-merge network")
-	//	}
-	//	header := b.eth.blockchain.CurrentFinalBlock()
-	//	return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
-	//}
-	//if number == rpc.SafeBlockNumber {
-	//	//if !b.eth.Merger().TDDReached() {
-	//	//	return nil, errors.New("'safe' tag not supported on pre-merge network")
-	//	//}
-	//	header := b.BlockChain().CurrentBlock()
-	//	return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
-	//}
-	iBlock, err := b.bc.GetBlockByNumber(uint256.NewInt(uint64(number)))
-	if nil != err {
+	if number == rpc.FinalizedBlockNumber || number == rpc.SafeBlockNumber {
+		finality, err := finalityBlockNumber(ctx, b.db, number)
+		if err != nil {
+			return nil, err
+		}
+		iBlock, err := b.bc.GetBlockByNumberContext(ctx, finality)
+		if nil != err || iBlock == nil {
+			return nil, err
+		}
+		return iBlock.(*types.Block), nil
+	}
+	iBlock, err := b.bc.GetBlockByNumberContext(ctx, uint256.NewInt(uint64(number)))
+	if nil != err || iBlock == nil {
 		return nil, err
 	}
 	return iBlock.(*types.Block), nil
 }
 
 func (b *API) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	iBlock, _ := b.bc.GetBlockByHash(hash)
+	iBlock, err := b.bc.GetBlockByHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if iBlock == nil {
+		return nil, errors.New("block for hash not found")
+	}
 	return iBlock.(*types.Block), nil
 }
 
@@ -167,7 +175,10 @@ func (b *API) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNu
 		return b.BlockByNumber(ctx, blockNr)
 	}
 	if hash, ok := blockNrOrHash.Hash(); ok {
-		header, _ := b.bc.GetHeaderByHash(hash)
+		header, err := b.bc.GetHeaderByHashContext(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
 		if header == nil {
 			return nil, errors.New("header for hash not found")
 		}
@@ -227,7 +238,7 @@ func (b *API) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNu
 //}
 
 func (b *API) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.bc.GetReceipts(hash)
+	return b.bc.GetReceiptsContext(ctx, hash)
 
 }
 
@@ -236,7 +247,7 @@ func (b *API) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts
 //}
 
 func (b *API) GetTd(ctx context.Context, hash common.Hash) *uint256.Int {
-	if header, _ := b.bc.GetHeaderByHash(hash); header != nil {
+	if header, _ := b.bc.GetHeaderByHashContext(ctx, hash); header != nil {
 		return b.bc.GetTd(hash, header.Number64())
 	}
 	return nil
@@ -521,7 +532,7 @@ func (eth *API) StateAtBlock(ctx context.Context, tx kv.Tx, blk *types.Block) (s
 	// on top to prevent garbage collection and return a release
 	// function to deref it.
 	// Type assert StateAt result from interface{} to *state.IntraBlockState
-	stateIface := eth.BlockChain().StateAt(tx, origin)
+	stateIface := eth.BlockChain().StateAtContext(ctx, tx, origin)
 	statedb, _ = stateIface.(*state.IntraBlockState)
 	//statedb.Database().TrieDB().Reference(block.Root(), common.Hash{})
 	return statedb, nil
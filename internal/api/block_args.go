@@ -25,11 +25,13 @@ import (
 	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
-	"math/big"
 )
 
 func RPCMarshalBlock(block block.IBlock, chain common.IBlockChain, inclTx bool, fullTx bool) (map[string]interface{}, error) {
 	fields := RPCMarshalHeader(block.Header())
+	if chain != nil && chain.Config().IsCancun(block.Number64().Uint64()) {
+		rpcMarshalBlobGas(fields, block.Header())
+	}
 
 	if inclTx {
 		formatTx := func(tx *transaction.Transaction) (interface{}, error) {
@@ -80,7 +82,7 @@ func RPCMarshalBlock(block block.IBlock, chain common.IBlockChain, inclTx bool,
 		fields["totalDifficulty"] = (*hexutil.Big)(td.ToBig())
 
 	}
-	// POA
+	// N42 blocks have no uncles, see uncleCount
 	uncleHashes := make([]types.Hash, 0)
 	fields["uncles"] = uncleHashes
 
@@ -104,7 +106,14 @@ func newRPCTransactionFromBlockIndex(b block.IBlock, index uint64) *RPCTransacti
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	return newRPCTransaction(txs[index], b.Hash(), b.Number64().Uint64(), index, big.NewInt(baseFee))
+	return newRPCTransaction(txs[index], b.Hash(), b.Number64().Uint64(), index, b.Header().BaseFee64().ToBig())
+}
+
+// rpcMarshalBlobGas adds the EIP-4844 fields of a Cancun header to fields.
+func rpcMarshalBlobGas(fields map[string]interface{}, head block.IHeader) {
+	header := head.(*block.Header)
+	fields["blobGasUsed"] = hexutil.Uint64(header.BlobGasUsed)
+	fields["excessBlobGas"] = hexutil.Uint64(header.ExcessBlobGas)
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -30,20 +30,29 @@ package api
 //   - eth_getTransactionByBlockNumberAndIndex ✅
 //   - eth_getUncleCountByBlockNumber ✅
 //   - eth_getBlockReceipts ✅ (新版 Blockscout 需要)
+//
+// 扩展接口:
+//   - eth_getAddressActivity (跳过地址从未出现的区块范围)
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common"
 	avmcommon "github.com/n42blockchain/N42/common/avmutil"
 	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+	"github.com/ledgerwatch/erigon-lib/kv"
 )
 
+var errNoEtherbase = errors.New("etherbase must be explicitly specified")
+
 // =============================================================================
 // 同步状态接口
 // =============================================================================
@@ -59,64 +68,92 @@ type SyncProgress struct {
 	SyncedAccounts hexutil.Uint64 `json:"syncedAccounts,omitempty"`
 	SyncedStorage  hexutil.Uint64 `json:"syncedStorage,omitempty"`
 	HealedTrienodes hexutil.Uint64 `json:"healedTrienodes,omitempty"`
+	// 非标准字段：各同步阶段处理的区块数与耗时
+	Stages *SyncStages `json:"stages,omitempty"`
+}
+
+// SyncStage is the blocks that passed a sync stage and the seconds spent.
+// 某一同步阶段处理的区块数与耗时（秒）。
+type SyncStage struct {
+	Blocks  hexutil.Uint64 `json:"blocks"`
+	Seconds float64        `json:"seconds"`
+}
+
+// SyncStages reports where the running sync spends its time.
+// 当前同步在各阶段的耗时分布。
+type SyncStages struct {
+	Headers   SyncStage `json:"headers"`
+	Bodies    SyncStage `json:"bodies"`
+	Execution SyncStage `json:"execution"`
+	Indexing  SyncStage `json:"indexing"`
+}
+
+func newSyncStage(s common.SyncStage) SyncStage {
+	return SyncStage{Blocks: hexutil.Uint64(s.Blocks), Seconds: s.Duration.Seconds()}
+}
+
+// newSyncProgress converts the downloader progress into its RPC form.
+func newSyncProgress(p common.SyncProgress) *SyncProgress {
+	return &SyncProgress{
+		StartingBlock: hexutil.Uint64(p.StartingBlock),
+		CurrentBlock:  hexutil.Uint64(p.CurrentBlock),
+		HighestBlock:  hexutil.Uint64(p.HighestBlock),
+		Stages: &SyncStages{
+			Headers:   newSyncStage(p.Stages.Headers),
+			Bodies:    newSyncStage(p.Stages.Bodies),
+			Execution: newSyncStage(p.Stages.Execution),
+			Indexing:  newSyncStage(p.Stages.Indexing),
+		},
+	}
 }
 
 // Syncing returns false when the node is fully synced, otherwise returns sync progress.
 // 返回 false 表示节点已完全同步，否则返回同步进度对象。
 func (s *BlockChainAPI) Syncing() (interface{}, error) {
-	// 获取当前区块高度
-	currentBlock := s.api.BlockChain().CurrentBlock()
-	if currentBlock == nil {
+	syncer := s.api.Syncer()
+	if syncer == nil {
 		return false, nil
 	}
-	currentHeight := currentBlock.Number64().Uint64()
-
-	// TODO: 实际实现中应该从 P2P 层获取网络最高区块
-	// 目前简化处理：如果节点有区块数据，就认为已同步
-	// 实际应该对比网络中其他节点报告的最高区块
-	highestBlock := currentHeight
-
-	// 如果当前高度等于最高高度，表示已同步
-	if currentHeight >= highestBlock {
+	progress, ok := syncer.Progress()
+	// 同步轮次结束或已追上目标高度，视为已同步
+	if !ok || progress.CurrentBlock >= progress.HighestBlock {
 		return false, nil
 	}
-
-	// 返回同步进度
-	return &SyncProgress{
-		StartingBlock: hexutil.Uint64(0),
-		CurrentBlock:  hexutil.Uint64(currentHeight),
-		HighestBlock:  hexutil.Uint64(highestBlock),
-	}, nil
+	return newSyncProgress(progress), nil
 }
 
 // =============================================================================
 // 挖矿相关接口
 // =============================================================================
 
-// Coinbase returns the current coinbase address.
-// 返回当前的挖矿收益地址。
+// Coinbase returns the etherbase configured on the local miner.
+// 返回本地 miner 配置的挖矿收益地址。
 func (s *BlockChainAPI) Coinbase() (types.Address, error) {
-	// 从当前区块获取 coinbase
-	currentBlock := s.api.BlockChain().CurrentBlock()
-	if currentBlock == nil {
-		return types.Address{}, nil
+	miner := s.api.Miner()
+	if miner == nil {
+		return types.Address{}, errNoEtherbase
 	}
-	return currentBlock.Coinbase(), nil
+	if coinbase := miner.Coinbase(); coinbase != (types.Address{}) {
+		return coinbase, nil
+	}
+	return types.Address{}, errNoEtherbase
 }
 
 // Mining returns an indication if this node is currently mining.
 // 返回节点是否正在挖矿。
 func (s *BlockChainAPI) Mining() bool {
-	// TODO: 需要从 miner 模块获取实际状态
-	// 目前返回 false，实际实现应检查 miner.Mining()
-	return false
+	miner := s.api.Miner()
+	return miner != nil && miner.Mining()
 }
 
-// Hashrate returns the POW hashrate.
-// 返回 POW 算力（N42 使用 POS/POA，返回 0）。
+// Hashrate returns the local block production rate.
+// N42 使用 POS/POA 共识，没有 POW 算力，返回最近一小时内本地出块的数量。
 func (s *BlockChainAPI) Hashrate() hexutil.Uint64 {
-	// N42 使用 POS/POA 共识，没有 hashrate
-	return hexutil.Uint64(0)
+	miner := s.api.Miner()
+	if miner == nil {
+		return 0
+	}
+	return hexutil.Uint64(miner.Hashrate())
 }
 
 // =============================================================================
@@ -133,7 +170,7 @@ func (s *BlockChainAPI) GetBlockTransactionCountByNumber(ctx context.Context, bl
 	if blockNr == jsonrpc.PendingBlockNumber || blockNr == jsonrpc.LatestBlockNumber {
 		blk = s.api.BlockChain().CurrentBlock()
 	} else {
-		blk, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+		blk, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 	}
 
 	if err != nil {
@@ -151,9 +188,21 @@ func (s *BlockChainAPI) GetBlockTransactionCountByNumber(ctx context.Context, bl
 // Uncle 相关接口
 // =============================================================================
 
+// uncleCount returns the number of uncles of b, or nil for an unknown block.
+// N42 blocks cannot reference uncles (see consensus.Engine.VerifyUncles), so
+// the uncle methods answer as for a chain without any: zero uncles for known
+// blocks, null for unknown ones, and null for every uncle index.
+// 注意：N42 使用 POA/POS 共识，区块没有 Uncle。
+func uncleCount(b block.IBlock) *hexutil.Uint {
+	if b == nil {
+		return nil
+	}
+	n := hexutil.Uint(0)
+	return &n
+}
+
 // GetUncleCountByBlockNumber returns number of uncles in the block for the given block number.
-// 返回指定区块号的区块中的 Uncle 数量。
-// 注意：N42 使用 POA/POS 共识，没有 Uncle 区块。
+// 返回指定区块号的区块中的 Uncle 数量, 已知区块始终为 0, 未知区块返回 null。
 func (s *BlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr jsonrpc.BlockNumber) (*hexutil.Uint, error) {
 	var blk block.IBlock
 	var err error
@@ -161,26 +210,19 @@ func (s *BlockChainAPI) GetUncleCountByBlockNumber(ctx context.Context, blockNr
 	if blockNr == jsonrpc.PendingBlockNumber || blockNr == jsonrpc.LatestBlockNumber {
 		blk = s.api.BlockChain().CurrentBlock()
 	} else {
-		blk, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+		blk, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 	}
 
 	if err != nil {
 		return nil, err
 	}
-	if blk == nil {
-		return nil, nil
-	}
-
-	// POA/POS 没有 Uncle
-	n := hexutil.Uint(0)
-	return &n, nil
+	return uncleCount(blk), nil
 }
 
 // GetUncleByBlockNumberAndIndex returns the uncle block for the given block hash and index.
 // 返回指定区块号和索引的 Uncle 区块。
-// 注意：N42 使用 POA/POS 共识，没有 Uncle 区块，始终返回 nil。
+// 注意：N42 的区块没有 Uncle，始终返回 null。
 func (s *BlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context, blockNr jsonrpc.BlockNumber, index hexutil.Uint) (map[string]interface{}, error) {
-	// POA/POS 没有 Uncle
 	return nil, nil
 }
 
@@ -197,7 +239,7 @@ func (s *TransactionAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context
 	if blockNr == jsonrpc.PendingBlockNumber || blockNr == jsonrpc.LatestBlockNumber {
 		blk = s.api.BlockChain().CurrentBlock()
 	} else {
-		blk, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+		blk, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 	}
 
 	if err != nil || blk == nil {
@@ -239,6 +281,7 @@ type BlockReceipt struct {
 	EffectiveGasPrice hexutil.Uint64    `json:"effectiveGasPrice"`
 	Type              hexutil.Uint64    `json:"type"`
 	Root              hexutil.Bytes     `json:"root,omitempty"`
+	RevertReason      hexutil.Bytes     `json:"revertReason,omitempty"`
 }
 
 // GetBlockReceipts returns all transaction receipts for a given block.
@@ -253,10 +296,10 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash json
 		if blockNr == jsonrpc.PendingBlockNumber || blockNr == jsonrpc.LatestBlockNumber {
 			blk = s.api.BlockChain().CurrentBlock()
 		} else {
-			blk, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+			blk, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 		}
 	} else if hash, ok := blockNrOrHash.Hash(); ok {
-		blk, err = s.api.BlockChain().GetBlockByHash(types.Hash(hash))
+		blk, err = s.api.BlockChain().GetBlockByHashContext(ctx, types.Hash(hash))
 	}
 
 	if err != nil {
@@ -267,7 +310,7 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash json
 	}
 
 	// 获取收据
-	receipts, err := s.api.BlockChain().GetReceipts(blk.Hash())
+	receipts, err := s.api.BlockChain().GetReceiptsContext(ctx, blk.Hash())
 	if err != nil {
 		return nil, err
 	}
@@ -330,12 +373,74 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash json
 			br.Root = receipt.PostState
 		}
 
+		// 导入时记录的 revert 数据
+		br.RevertReason = s.api.revertReason(ctx, receipt)
+
 		result[i] = br
 	}
 
 	return result, nil
 }
 
+// =============================================================================
+// 地址活跃度接口
+// =============================================================================
+
+// AddressActivityRange is a block range in which an address may appear.
+type AddressActivityRange struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+}
+
+// GetAddressActivity returns the block ranges within [fromBlock, toBlock] in
+// which address may have sent, received or emitted anything. Blocks outside
+// the returned ranges definitely do not involve the address, so history scans
+// can skip them. Ranges not covered by the address index are always returned.
+// 返回地址可能出现过的区块范围，用于跳过无关区块。
+func (s *BlockChainAPI) GetAddressActivity(ctx context.Context, address types.Address, fromBlock, toBlock jsonrpc.BlockNumber) ([]AddressActivityRange, error) {
+	head := s.api.BlockChain().CurrentBlock().Number64().Uint64()
+	resolve := func(n jsonrpc.BlockNumber) uint64 {
+		if n < 0 || uint64(n) > head {
+			return head
+		}
+		return uint64(n)
+	}
+	from, to := resolve(fromBlock), resolve(toBlock)
+	if from > to {
+		return nil, errors.New("fromBlock is after toBlock")
+	}
+
+	var ranges []AddressActivityRange
+	err := s.api.Database().View(ctx, func(tx kv.Tx) error {
+		for start := from; start <= to; {
+			end := min(to, start-start%rawdb.AddressBloomRange+rawdb.AddressBloomRange-1)
+			active, err := rawdb.AddressActivity(tx, address, start, end)
+			if err != nil {
+				return err
+			}
+			if active {
+				if n := len(ranges); n > 0 && uint64(ranges[n-1].ToBlock)+1 == start {
+					ranges[n-1].ToBlock = hexutil.Uint64(end)
+				} else {
+					ranges = append(ranges, AddressActivityRange{FromBlock: hexutil.Uint64(start), ToBlock: hexutil.Uint64(end)})
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			start = end + 1
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ranges == nil {
+		ranges = []AddressActivityRange{}
+	}
+	return ranges, nil
+}
+
 // =============================================================================
 // 账户相关接口
 // =============================================================================
@@ -366,9 +471,9 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address types.Address, sto
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
-	if state == nil {
-		return nil, nil
+	state, err := s.api.StateAt(ctx, tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
 
 	// 获取账户信息
@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
@@ -63,6 +65,66 @@ func TestSyncProgress(t *testing.T) {
 	t.Log("✓ SyncProgress structure is correct")
 }
 
+type stubSyncer struct {
+	progress common.SyncProgress
+	syncing  bool
+}
+
+func (s *stubSyncer) Progress() (common.SyncProgress, bool) { return s.progress, s.syncing }
+
+// TestSyncingStages 测试 eth_syncing 返回同步阶段信息
+func TestSyncingStages(t *testing.T) {
+	api := &API{}
+	bc := NewBlockChainAPI(api)
+
+	if res, _ := bc.Syncing(); res != false {
+		t.Fatalf("syncing without downloader: have %v, want false", res)
+	}
+	syncer := &stubSyncer{progress: common.SyncProgress{StartingBlock: 10, CurrentBlock: 50, HighestBlock: 100}}
+	api.SetSyncer(syncer)
+	if res, _ := bc.Syncing(); res != false {
+		t.Fatalf("syncing outside a round: have %v, want false", res)
+	}
+
+	syncer.syncing = true
+	syncer.progress.Stages.Execution = common.SyncStage{Blocks: 40, Duration: 1500 * time.Millisecond}
+	res, err := bc.Syncing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		StartingBlock hexutil.Uint64 `json:"startingBlock"`
+		HighestBlock  hexutil.Uint64 `json:"highestBlock"`
+		Stages        map[string]struct {
+			Blocks  hexutil.Uint64 `json:"blocks"`
+			Seconds float64        `json:"seconds"`
+		} `json:"stages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.StartingBlock != 10 || result.HighestBlock != 100 {
+		t.Errorf("range mismatch: have %d-%d, want 10-100", result.StartingBlock, result.HighestBlock)
+	}
+	for _, stage := range []string{"headers", "bodies", "execution", "indexing"} {
+		if _, ok := result.Stages[stage]; !ok {
+			t.Errorf("missing stage %q", stage)
+		}
+	}
+	if exec := result.Stages["execution"]; exec.Blocks != 40 || exec.Seconds != 1.5 {
+		t.Errorf("execution stage mismatch: have %+v", exec)
+	}
+
+	syncer.progress.CurrentBlock = 100
+	if res, _ := bc.Syncing(); res != false {
+		t.Fatalf("syncing at target: have %v, want false", res)
+	}
+}
+
 // TestBlockReceipt 测试 BlockReceipt 结构
 func TestBlockReceipt(t *testing.T) {
 	receipt := &BlockReceipt{
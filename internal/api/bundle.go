@@ -0,0 +1,283 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common"
+	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
+	avmcommon "github.com/n42blockchain/N42/common/avmutil"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/avm/abi"
+	"github.com/n42blockchain/N42/internal/consensus/misc"
+	vm2 "github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/turbo/rpchelper"
+)
+
+var errNoBlockProducer = errors.New("bundles need a local block producer")
+
+// BundleAPI offers the Flashbots compatible bundle methods to searchers and
+// builders. It is only served behind authentication.
+type BundleAPI struct {
+	api *API
+}
+
+// NewBundleAPI creates a new bundle service.
+func NewBundleAPI(api *API) *BundleAPI {
+	return &BundleAPI{api}
+}
+
+// SendBundleArgs are the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes  `json:"txs"`
+	BlockNumber       hexutil.Uint64   `json:"blockNumber"`
+	MinTimestamp      *uint64          `json:"minTimestamp"`
+	MaxTimestamp      *uint64          `json:"maxTimestamp"`
+	RevertingTxHashes []avmcommon.Hash `json:"revertingTxHashes"`
+}
+
+// SendBundleResult is the result of eth_sendBundle.
+type SendBundleResult struct {
+	BundleHash avmcommon.Hash `json:"bundleHash"`
+}
+
+// CallBundleArgs are the arguments of eth_callBundle. The bundle is simulated
+// in a block numbered BlockNumber on top of the state of StateBlockNumber.
+type CallBundleArgs struct {
+	Txs              []hexutil.Bytes           `json:"txs"`
+	BlockNumber      hexutil.Uint64            `json:"blockNumber"`
+	StateBlockNumber jsonrpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Coinbase         *avmcommon.Address        `json:"coinbase"`
+	Timestamp        *uint64                   `json:"timestamp"`
+	GasLimit         *uint64                   `json:"gasLimit"`
+}
+
+// CallBundleTxResult is the outcome of one transaction of eth_callBundle.
+type CallBundleTxResult struct {
+	TxHash            avmcommon.Hash     `json:"txHash"`
+	FromAddress       *avmcommon.Address `json:"fromAddress"`
+	ToAddress         *avmcommon.Address `json:"toAddress"`
+	GasUsed           hexutil.Uint64     `json:"gasUsed"`
+	GasPrice          *hexutil.Big       `json:"gasPrice"`
+	GasFees           *hexutil.Big       `json:"gasFees"`
+	CoinbaseDiff      *hexutil.Big       `json:"coinbaseDiff"`
+	EthSentToCoinbase *hexutil.Big       `json:"ethSentToCoinbase"`
+	Value             hexutil.Bytes      `json:"value,omitempty"`
+	Error             string             `json:"error,omitempty"`
+	Revert            string             `json:"revert,omitempty"`
+}
+
+// CallBundleResult is the result of eth_callBundle.
+type CallBundleResult struct {
+	BundleHash        avmcommon.Hash        `json:"bundleHash"`
+	BundleGasPrice    *hexutil.Big          `json:"bundleGasPrice"`
+	CoinbaseDiff      *hexutil.Big          `json:"coinbaseDiff"`
+	GasFees           *hexutil.Big          `json:"gasFees"`
+	EthSentToCoinbase *hexutil.Big          `json:"ethSentToCoinbase"`
+	Results           []*CallBundleTxResult `json:"results"`
+	StateBlockNumber  hexutil.Uint64        `json:"stateBlockNumber"`
+	TotalGasUsed      hexutil.Uint64        `json:"totalGasUsed"`
+}
+
+// decodeBundleTxs decodes the raw signed transactions of a bundle.
+func (s *BundleAPI) decodeBundleTxs(raw []hexutil.Bytes) ([]*transaction.Transaction, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("bundle has no transactions")
+	}
+	if len(raw) > transaction.MaxBundleTxs {
+		return nil, fmt.Errorf("bundle has more than %d transactions", transaction.MaxBundleTxs)
+	}
+	number := s.api.BlockChain().CurrentBlock().Header().Number64().ToBig()
+	txs := make([]*transaction.Transaction, len(raw))
+	for i, input := range raw {
+		tx := new(avmtypes.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		metaTx, err := tx.ToastTransaction(s.api.GetChainConfig(), number)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		txs[i] = metaTx
+	}
+	return txs, nil
+}
+
+// SendBundle queues a bundle for atomic inclusion at the top of the block
+// numbered BlockNumber by the local block producer. Transactions listed in
+// RevertingTxHashes may revert, any other failure drops the whole bundle.
+func (s *BundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error) {
+	miner := s.api.Miner()
+	if miner == nil {
+		return nil, errNoBlockProducer
+	}
+	txs, err := s.decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &transaction.Bundle{Txs: txs, BlockNumber: uint64(args.BlockNumber)}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = *args.MinTimestamp
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = *args.MaxTimestamp
+	}
+	for _, hash := range args.RevertingTxHashes {
+		bundle.RevertingTxHashes = append(bundle.RevertingTxHashes, avmtypes.ToastHash(hash))
+	}
+	if err := miner.AddBundle(bundle); err != nil {
+		return nil, err
+	}
+	return &SendBundleResult{BundleHash: avmtypes.FromastHash(bundle.Hash())}, nil
+}
+
+// CallBundle simulates a bundle in a block following StateBlockNumber and
+// reports the gas used, fees and coinbase payments of every transaction.
+// The timestamp defaults to one second after the state block and the
+// coinbase and gas limit to those of the state block.
+func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := s.decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	dbtx, err := s.api.Database().BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dbtx.Rollback()
+
+	parentNumber, parentHash, err := rpchelper.GetCanonicalBlockNumber(args.StateBlockNumber, dbtx)
+	if err != nil {
+		return nil, err
+	}
+	parent := rawdb.ReadHeader(dbtx, parentHash, parentNumber.Uint64())
+	if parent == nil {
+		return nil, fmt.Errorf("state block %v not found", parentNumber)
+	}
+	ibs := s.api.State(ctx, dbtx, args.StateBlockNumber)
+	if ibs == nil {
+		return nil, errors.New("cannot load state")
+	}
+	header := s.bundleHeader(parent, args)
+
+	var (
+		bundle  = &transaction.Bundle{Txs: txs, BlockNumber: header.Number.Uint64()}
+		gp      = new(common.GasPool).AddGas(header.GasLimit)
+		usedGas uint64
+		hashFn  = internal.GetHashFn(header, func(hash types.Hash, number uint64) *block.Header {
+			return rawdb.ReadHeader(dbtx, hash, number)
+		})
+	)
+	results, err := internal.ApplyBundle(s.api.GetChainConfig(), hashFn, s.api.Engine(), &header.Coinbase, gp, ibs.(*state.IntraBlockState), header, bundle, 0, &usedGas, vm2.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &CallBundleResult{
+		BundleHash:       avmtypes.FromastHash(bundle.Hash()),
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		TotalGasUsed:     hexutil.Uint64(usedGas),
+		Results:          make([]*CallBundleTxResult, 0, len(results)),
+	}
+	coinbaseDiff, gasFees := new(big.Int), new(big.Int)
+	for _, res := range results {
+		txRes := newCallBundleTxResult(res, header.BaseFee)
+		coinbaseDiff.Add(coinbaseDiff, res.CoinbaseDiff)
+		gasFees.Add(gasFees, txRes.GasFees.ToInt())
+		ret.Results = append(ret.Results, txRes)
+	}
+	ret.CoinbaseDiff = (*hexutil.Big)(coinbaseDiff)
+	ret.GasFees = (*hexutil.Big)(gasFees)
+	ret.EthSentToCoinbase = (*hexutil.Big)(new(big.Int).Sub(coinbaseDiff, gasFees))
+	ret.BundleGasPrice = (*hexutil.Big)(new(big.Int))
+	if usedGas > 0 {
+		ret.BundleGasPrice = (*hexutil.Big)(new(big.Int).Div(coinbaseDiff, new(big.Int).SetUint64(usedGas)))
+	}
+	return ret, nil
+}
+
+// bundleHeader returns the header of the block simulating a bundle on top of
+// parent.
+func (s *BundleAPI) bundleHeader(parent *block.Header, args CallBundleArgs) *block.Header {
+	config := s.api.GetChainConfig()
+	number := uint64(args.BlockNumber)
+	if number == 0 {
+		number = parent.Number.Uint64() + 1
+	}
+	header := &block.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Number:     uint256.NewInt(number),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: parent.Difficulty,
+		BaseFee:    uint256.NewInt(0),
+	}
+	if config.IsLondon(number) {
+		header.BaseFee, _ = uint256.FromBig(misc.CalcBaseFee(config, parent))
+	}
+	if args.Coinbase != nil {
+		header.Coinbase = *avmtypes.ToastAddress(args.Coinbase)
+	}
+	if args.Timestamp != nil {
+		header.Time = *args.Timestamp
+	}
+	if args.GasLimit != nil {
+		header.GasLimit = *args.GasLimit
+	}
+	return header
+}
+
+// newCallBundleTxResult converts the outcome of a bundle transaction.
+func newCallBundleTxResult(res *internal.BundleTxResult, baseFee *uint256.Int) *CallBundleTxResult {
+	tx := res.Tx
+	tip := tx.EffectiveGasTipValue(baseFee)
+	price := new(uint256.Int).Add(tip, baseFee)
+	gasFees := new(big.Int).Mul(tip.ToBig(), new(big.Int).SetUint64(res.Receipt.GasUsed))
+	txRes := &CallBundleTxResult{
+		TxHash:            avmtypes.FromastHash(tx.Hash()),
+		FromAddress:       avmtypes.FromastAddress(tx.From()),
+		ToAddress:         avmtypes.FromastAddress(tx.To()),
+		GasUsed:           hexutil.Uint64(res.Receipt.GasUsed),
+		GasPrice:          (*hexutil.Big)(price.ToBig()),
+		GasFees:           (*hexutil.Big)(gasFees),
+		CoinbaseDiff:      (*hexutil.Big)(res.CoinbaseDiff),
+		EthSentToCoinbase: (*hexutil.Big)(new(big.Int).Sub(res.CoinbaseDiff, gasFees)),
+	}
+	if res.Receipt.Status == block.ReceiptStatusFailed {
+		txRes.Error = "execution reverted"
+		if reason, err := abi.UnpackRevert(res.Return); err == nil {
+			txRes.Revert = reason
+		}
+	} else {
+		txRes.Value = res.Return
+	}
+	return txRes
+}
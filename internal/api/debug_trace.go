@@ -122,7 +122,7 @@ func (debug *DebugAPI) TraceTransaction(ctx context.Context, hash types.Hash, co
 	_ = blockNumber // Used for context
 
 	// Get the block
-	blk, err := debug.api.BlockChain().GetBlockByHash(blockHash)
+	blk, err := debug.api.BlockChain().GetBlockByHashContext(ctx, blockHash)
 	if err != nil || blk == nil {
 		return nil, fmt.Errorf("block %x not found", blockHash)
 	}
@@ -301,7 +301,7 @@ func (debug *DebugAPI) TraceBlockByNumber(ctx context.Context, number jsonrpc.Bl
 	if number == jsonrpc.LatestBlockNumber || number == jsonrpc.PendingBlockNumber {
 		blk = debug.api.BlockChain().CurrentBlock()
 	} else {
-		blk, err = debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(number.Int64())))
+		blk, err = debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(number.Int64())))
 	}
 
 	if err != nil || blk == nil {
@@ -314,7 +314,7 @@ func (debug *DebugAPI) TraceBlockByNumber(ctx context.Context, number jsonrpc.Bl
 // TraceBlockByHash returns the structured logs created during the execution of
 // EVM and returns them as a JSON object.
 func (debug *DebugAPI) TraceBlockByHash(ctx context.Context, hash types.Hash, config *TraceConfig) ([]*txTraceResult, error) {
-	blk, err := debug.api.BlockChain().GetBlockByHash(hash)
+	blk, err := debug.api.BlockChain().GetBlockByHashContext(ctx, hash)
 	if err != nil || blk == nil {
 		return nil, fmt.Errorf("block %x not found", hash)
 	}
@@ -358,10 +358,10 @@ func (debug *DebugAPI) TraceCall(ctx context.Context, args TransactionArgs, bloc
 		if blockNr == jsonrpc.LatestBlockNumber || blockNr == jsonrpc.PendingBlockNumber {
 			blk = debug.api.BlockChain().CurrentBlock()
 		} else {
-			blk, err = debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+			blk, err = debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 		}
 	} else if hash, ok := blockNrOrHash.Hash(); ok {
-		blk, err = debug.api.BlockChain().GetBlockByHash(hash)
+		blk, err = debug.api.BlockChain().GetBlockByHashContext(ctx, hash)
 	}
 
 	if err != nil || blk == nil {
@@ -491,10 +491,10 @@ func (s *BlockChainAPI) CreateAccessList(ctx context.Context, args TransactionAr
 		if blockNr == jsonrpc.LatestBlockNumber || blockNr == jsonrpc.PendingBlockNumber {
 			blk = s.api.BlockChain().CurrentBlock()
 		} else {
-			blk, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+			blk, err = s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 		}
 	} else if hash, ok := bNrOrHash.Hash(); ok {
-		blk, err = s.api.BlockChain().GetBlockByHash(hash)
+		blk, err = s.api.BlockChain().GetBlockByHashContext(ctx, hash)
 	}
 
 	if err != nil || blk == nil {
@@ -554,7 +554,7 @@ func (s *BlockChainAPI) CreateAccessList(ctx context.Context, args TransactionAr
 
 // GetBlockRlp retrieves the RLP encoded for of a single block.
 func (debug *DebugAPI) GetBlockRlp(ctx context.Context, number uint64) (hexutil.Bytes, error) {
-	blk, err := debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(number))
+	blk, err := debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(number))
 	if err != nil || blk == nil {
 		return nil, fmt.Errorf("block #%d not found", number)
 	}
@@ -568,7 +568,10 @@ func (debug *DebugAPI) GetBlockRlp(ctx context.Context, number uint64) (hexutil.
 
 // GetHeaderRlp retrieves the RLP encoded for of a single header.
 func (debug *DebugAPI) GetHeaderRlp(ctx context.Context, number uint64) (hexutil.Bytes, error) {
-	header := debug.api.BlockChain().GetHeaderByNumber(uint256.NewInt(number))
+	header, err := debug.api.BlockChain().GetHeaderByNumberContext(ctx, uint256.NewInt(number))
+	if err != nil {
+		return nil, err
+	}
 	if header == nil {
 		return nil, fmt.Errorf("header #%d not found", number)
 	}
@@ -582,7 +585,7 @@ func (debug *DebugAPI) GetHeaderRlp(ctx context.Context, number uint64) (hexutil
 
 // PrintBlock retrieves a block and returns its pretty printed form.
 func (debug *DebugAPI) PrintBlock(ctx context.Context, number uint64) (string, error) {
-	blk, err := debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(number))
+	blk, err := debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(number))
 	if err != nil || blk == nil {
 		return "", fmt.Errorf("block #%d not found", number)
 	}
@@ -640,17 +643,17 @@ func (debug *DebugAPI) StorageRangeAt(ctx context.Context, blockHashOrNumber int
 		// Try to parse as hash first
 		if len(v) == 66 && v[:2] == "0x" {
 			hash := types.HexToHash(v)
-			blk, err = debug.api.BlockChain().GetBlockByHash(hash)
+			blk, err = debug.api.BlockChain().GetBlockByHashContext(ctx, hash)
 		} else {
 			// Parse as number
 			num, parseErr := strconv.ParseUint(v, 0, 64)
 			if parseErr != nil {
 				return nil, fmt.Errorf("invalid block identifier: %v", v)
 			}
-			blk, err = debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(num))
+			blk, err = debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(num))
 		}
 	case float64:
-		blk, err = debug.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(v)))
+		blk, err = debug.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(v)))
 	default:
 		return nil, fmt.Errorf("invalid block identifier type")
 	}
@@ -662,7 +665,7 @@ func (debug *DebugAPI) StorageRangeAt(ctx context.Context, blockHashOrNumber int
 	// Get state at the block
 	var stateDB *state.IntraBlockState
 	if err := debug.api.Database().View(ctx, func(tx kv.Tx) error {
-		ibs := debug.api.State(tx, jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.BlockNumber(blk.Number64().Uint64())))
+		ibs := debug.api.State(ctx, tx, jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.BlockNumber(blk.Number64().Uint64())))
 		if ibs != nil {
 			stateDB = ibs.(*state.IntraBlockState)
 		}
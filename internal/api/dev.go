@@ -0,0 +1,168 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/types"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+)
+
+// devMineTimeout bounds how long evm_mine waits for the block to be sealed.
+const devMineTimeout = time.Minute
+
+var errNoMiner = errors.New("the node is not producing blocks")
+
+// devSnapshot is the chain and clock state saved by evm_snapshot.
+type devSnapshot struct {
+	id         uint64
+	number     uint64
+	hash       types.Hash
+	timeOffset int64
+}
+
+// DevAPI offers the evm namespace of ganache and anvil on the dev chain, so
+// test suites written against them run unmodified. It is only served with --dev.
+type DevAPI struct {
+	api *API
+
+	mu        sync.Mutex
+	snapshots []devSnapshot // ordered by id
+	lastID    uint64
+}
+
+// NewDevAPI creates a new dev chain testing service.
+func NewDevAPI(api *API) *DevAPI {
+	return &DevAPI{api: api}
+}
+
+// DevApis returns the testing APIs of the dev chain.
+func (api *API) DevApis() []jsonrpc.API {
+	return []jsonrpc.API{
+		{
+			Namespace: "evm",
+			Service:   NewDevAPI(api),
+		},
+	}
+}
+
+// Snapshot saves the current head and clock, and returns the id to revert to.
+// Ids start at 1 and increase, so a sequence of calls is reproducible.
+func (s *DevAPI) Snapshot() hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	head := s.api.BlockChain().CurrentBlock()
+	s.lastID++
+	snap := devSnapshot{id: s.lastID, number: head.Number64().Uint64(), hash: head.Hash()}
+	if miner := s.api.Miner(); miner != nil {
+		snap.timeOffset = miner.TimeOffset()
+	}
+	s.snapshots = append(s.snapshots, snap)
+	return hexutil.Uint64(snap.id)
+}
+
+// Revert rewinds the chain and the clock to the snapshot id. The snapshot and
+// all those taken after it are discarded, transactions mined since are lost.
+// It returns false if the snapshot is unknown or its block was replaced.
+func (s *DevAPI) Revert(id jsonrpc.DecimalOrHex) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := 0
+	for i < len(s.snapshots) && s.snapshots[i].id != uint64(id) {
+		i++
+	}
+	if i == len(s.snapshots) {
+		return false, nil
+	}
+	snap := s.snapshots[i]
+	s.snapshots = s.snapshots[:i]
+
+	bc := s.api.BlockChain()
+	if blk, _ := bc.GetBlockByNumber(uint256.NewInt(snap.number)); blk == nil || blk.Hash() != snap.hash {
+		return false, nil
+	}
+	if bc.CurrentBlock().Number64().Uint64() > snap.number {
+		if err := bc.SetHead(snap.number); err != nil {
+			return false, err
+		}
+	}
+	if miner := s.api.Miner(); miner != nil {
+		miner.SetTimeOffset(snap.timeOffset)
+	}
+	return true, nil
+}
+
+// Mine seals a block, even an empty one, and returns once it is the new head.
+// With a timestamp the clock is set to it first, see SetTime.
+func (s *DevAPI) Mine(ctx context.Context, timestamp *jsonrpc.DecimalOrHex) (string, error) {
+	miner := s.api.Miner()
+	if miner == nil || !miner.Mining() {
+		return "", errNoMiner
+	}
+	if timestamp != nil {
+		if _, err := s.SetTime(*timestamp); err != nil {
+			return "", err
+		}
+	}
+
+	sub := common.ChainHeadTopic.Subscribe(1, eventv3.DropOldest)
+	defer sub.Unsubscribe()
+
+	number := s.api.BlockChain().CurrentBlock().Number64().Uint64()
+	miner.Mine()
+
+	timeout := time.NewTimer(devMineTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case head := <-sub.Chan():
+			if head.Inserted && head.Block.Number64().Uint64() > number {
+				return "0x0", nil
+			}
+		case err := <-sub.Err():
+			return "", err
+		case <-timeout.C:
+			return "", fmt.Errorf("no block sealed within %v", devMineTimeout)
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// SetTime sets the clock used to stamp new blocks to timestamp, in seconds
+// since the epoch, and returns the resulting offset from the wall clock.
+func (s *DevAPI) SetTime(timestamp jsonrpc.DecimalOrHex) (int64, error) {
+	miner := s.api.Miner()
+	if miner == nil {
+		return 0, errNoMiner
+	}
+	offset := int64(timestamp) - time.Now().Unix()
+	miner.SetTimeOffset(offset)
+	return offset, nil
+}
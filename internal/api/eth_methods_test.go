@@ -17,13 +17,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/accounts/keystore"
 	avmcommon "github.com/n42blockchain/N42/common/avmutil"
+	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
 )
@@ -451,3 +456,143 @@ func TestRevertError(t *testing.T) {
 	// errExecutionReverted 是内部变量，测试结构定义
 	t.Logf("✓ revertError structure defined correctly")
 }
+
+func TestSignTypedDataV4(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(crypto.ToECDSAUnsafe(crypto.Keccak256([]byte("cow"))), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatal(err)
+	}
+	s := NewTransactionAPI(&API{accountManager: accounts.NewManager(&accounts.Config{}, ks)}, new(AddrLocker))
+
+	// Mail example of the EIP-712 specification, sent as a JSON string like
+	// wallet libraries do
+	mail := `{"types":{"EIP712Domain":[{"name":"name","type":"string"},{"name":"version","type":"string"},{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"}],` +
+		`"Person":[{"name":"name","type":"string"},{"name":"wallet","type":"address"}],` +
+		`"Mail":[{"name":"from","type":"Person"},{"name":"to","type":"Person"},{"name":"contents","type":"string"}]},` +
+		`"primaryType":"Mail","domain":{"name":"Ether Mail","version":"1","chainId":1,"verifyingContract":"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},` +
+		`"message":{"from":{"name":"Cow","wallet":"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},"to":{"name":"Bob","wallet":"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},"contents":"Hello, Bob!"}}`
+	param, _ := json.Marshal(mail)
+	var data TypedDataArgs
+	if err := json.Unmarshal(param, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := s.SignTypedData_v4(context.Background(), avmcommon.Address(account.Address), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+	if sig.String() != want {
+		t.Errorf("signature = %s, want %s", sig, want)
+	}
+}
+
+func TestRPCTransactionTypes(t *testing.T) {
+	from := types.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := types.HexToAddress("0x2222222222222222222222222222222222222222")
+	chainID := uint256.NewInt(94)
+	al := transaction.AccessList{{Address: to, StorageKeys: []types.Hash{{0x01}}}}
+	sig := func() (*uint256.Int, *uint256.Int, *uint256.Int) {
+		return uint256.NewInt(1), uint256.NewInt(0x1234), uint256.NewInt(0x5678)
+	}
+
+	tests := []struct {
+		name    string
+		tx      transaction.TxData
+		present map[string]string
+		absent  []string
+	}{
+		{
+			name: "legacy",
+			tx: &transaction.LegacyTx{Nonce: 1, GasPrice: uint256.NewInt(7), Gas: 21000, To: &to, From: &from, Value: uint256.NewInt(1),
+				V: uint256.NewInt(94*2 + 35), R: uint256.NewInt(0x1234), S: uint256.NewInt(0x5678)},
+			present: map[string]string{"type": `"0x0"`, "chainId": `"0x5e"`, "gasPrice": `"0x7"`, "v": `"0xdf"`},
+			absent:  []string{"accessList", "yParity", "maxFeePerGas", "maxPriorityFeePerGas"},
+		},
+		{
+			name: "access list",
+			tx: func() transaction.TxData {
+				v, r, s := sig()
+				return &transaction.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: uint256.NewInt(7), Gas: 21000, To: &to, From: &from, Value: uint256.NewInt(1), AccessList: al, V: v, R: r, S: s}
+			}(),
+			present: map[string]string{
+				"type":       `"0x1"`,
+				"chainId":    `"0x5e"`,
+				"accessList": `[{"address":"0x2222222222222222222222222222222222222222","storageKeys":["0x0100000000000000000000000000000000000000000000000000000000000000"]}]`,
+				"yParity":    `"0x1"`,
+				"v":          `"0x1"`,
+			},
+			absent: []string{"maxFeePerGas", "maxPriorityFeePerGas"},
+		},
+		{
+			name: "dynamic fee",
+			tx: func() transaction.TxData {
+				v, r, s := sig()
+				return &transaction.DynamicFeeTx{ChainID: chainID, Nonce: 1, GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(20), Gas: 21000, To: &to, From: &from, Value: uint256.NewInt(1), V: v, R: r, S: s}
+			}(),
+			present: map[string]string{
+				"type":                 `"0x2"`,
+				"accessList":           `[]`,
+				"maxFeePerGas":         `"0x14"`,
+				"maxPriorityFeePerGas": `"0x2"`,
+				"gasPrice":             `"0xc"`, // baseFee 10 + tip 2
+				"yParity":              `"0x1"`,
+			},
+			absent: []string{"maxFeePerBlobGas", "blobVersionedHashes", "authorizationList"},
+		},
+		{
+			name: "blob",
+			tx: func() transaction.TxData {
+				v, r, s := sig()
+				return &transaction.BlobTx{ChainID: chainID, Nonce: 1, GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(20), Gas: 21000, To: to, From: &from, Value: uint256.NewInt(1),
+					BlobFeeCap: uint256.NewInt(3), BlobHashes: []types.Hash{{0x01}}, V: v, R: r, S: s}
+			}(),
+			present: map[string]string{
+				"type":                `"0x3"`,
+				"accessList":          `[]`,
+				"maxFeePerBlobGas":    `"0x3"`,
+				"blobVersionedHashes": `["0x0100000000000000000000000000000000000000000000000000000000000000"]`,
+				"yParity":             `"0x1"`,
+			},
+		},
+		{
+			name: "set code",
+			tx: func() transaction.TxData {
+				v, r, s := sig()
+				return &transaction.SetCodeTx{ChainID: chainID, Nonce: 1, GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(20), Gas: 50000, To: &to, From: &from, Value: uint256.NewInt(0),
+					AuthList: transaction.AuthorizationList{{ChainID: 94, Address: to, Nonce: 3, V: uint256.NewInt(0), R: uint256.NewInt(1), S: uint256.NewInt(2)}}, V: v, R: r, S: s}
+			}(),
+			present: map[string]string{
+				"type":              `"0x4"`,
+				"accessList":        `[]`,
+				"authorizationList": `[{"chainId":"0x5e","address":"0x2222222222222222222222222222222222222222","nonce":"0x3","yParity":"0x0","r":"0x1","s":"0x2"}]`,
+				"yParity":           `"0x1"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		rpcTx := newRPCTransaction(transaction.NewTx(tt.tx), types.Hash{0xbb}, 10, 0, big.NewInt(10))
+		data, err := json.Marshal(rpcTx)
+		if err != nil {
+			t.Fatalf("%s: marshal failed: %v", tt.name, err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("%s: unmarshal failed: %v", tt.name, err)
+		}
+		for name, want := range tt.present {
+			if have := string(fields[name]); have != want {
+				t.Errorf("%s: %s = %s, want %s", tt.name, name, have, want)
+			}
+		}
+		for _, name := range tt.absent {
+			if _, ok := fields[name]; ok {
+				t.Errorf("%s: unexpected field %s = %s", tt.name, name, fields[name])
+			}
+		}
+	}
+}
@@ -21,7 +21,7 @@ package api
 // =============================================================================
 //
 // This file contains eth_* RPC methods for:
-// - Message signing (eth_sign)
+// - Message signing (eth_sign, eth_signTypedData_v4)
 // - Transaction signing (eth_signTransaction)
 // - Raw transaction data retrieval (eth_getRawTransaction*)
 //
@@ -29,13 +29,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/holiman/uint256"
 	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/accounts/eip712"
 	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
 	avmcommon "github.com/n42blockchain/N42/common/avmutil"
+	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/modules/rawdb"
@@ -87,6 +90,60 @@ func (s *TransactionAPI) Sign(ctx context.Context, address avmcommon.Address, da
 	return signature, nil
 }
 
+// TypedDataArgs is the typed data parameter of eth_signTypedData_v4. Wallet
+// libraries send it either as a JSON object or as a JSON encoded string.
+type TypedDataArgs struct {
+	eip712.TypedData
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (args *TypedDataArgs) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		var str string
+		if err := json.Unmarshal(input, &str); err != nil {
+			return err
+		}
+		input = []byte(str)
+	}
+	return json.Unmarshal(input, &args.TypedData)
+}
+
+// SignTypedData_v4 signs EIP-712 typed structured data with:
+// sign(keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)))
+//
+// Note: the address must be an unlocked account managed by this node.
+//
+// Parameters:
+//   - address: The address to sign with
+//   - data: The typed data, as object or JSON string
+//
+// Returns:
+//   - The signature, with V set to 27 or 28
+func (s *TransactionAPI) SignTypedData_v4(ctx context.Context, address avmcommon.Address, data TypedDataArgs) (hexutil.Bytes, error) {
+	account := accounts.Account{Address: types.Address(address)}
+
+	if s.api.accountManager == nil {
+		return nil, errors.New("account manager not available")
+	}
+
+	wallet, err := s.api.accountManager.Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("account not found: %v", err)
+	}
+
+	_, rawData, err := eip712.TypedDataAndHash(data.TypedData)
+	if err != nil {
+		return nil, err
+	}
+	// The wallet signs keccak256(rawData), the EIP-712 digest
+	signature, err := wallet.SignData(account, accounts.MimetypeTypedData, []byte(rawData))
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	return signature, nil
+}
+
 // SignTransaction signs a transaction without submitting it to the network.
 // This allows offline transaction creation.
 //
@@ -203,7 +260,7 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash avmco
 // Returns:
 //   - The RLP-encoded transaction bytes
 func (s *TransactionAPI) GetRawTransactionByBlockHashAndIndex(ctx context.Context, blockHash avmcommon.Hash, index hexutil.Uint) (hexutil.Bytes, error) {
-	block, err := s.api.BlockChain().GetBlockByHash(avmtypes.ToastHash(blockHash))
+	block, err := s.api.BlockChain().GetBlockByHashContext(ctx, avmtypes.ToastHash(blockHash))
 	if err != nil || block == nil {
 		return nil, err
 	}
@@ -251,7 +308,7 @@ func (s *TransactionAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Cont
 		return raw, nil
 	}
 
-	blk, err := s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(blockNr.Int64())))
+	blk, err := s.api.BlockChain().GetBlockByNumberContext(ctx, uint256.NewInt(uint64(blockNr.Int64())))
 	if err != nil || blk == nil {
 		return nil, err
 	}
@@ -306,27 +363,39 @@ func (s *TransactionAPI) PendingTransactions() ([]*RPCTransaction, error) {
 	return transactions, nil
 }
 
-// Resend accepts an existing transaction and replaces it with a new one.
-// This is used for bumping gas price on stuck transactions.
+// Resend replaces a transaction waiting in the pool with a copy paying a
+// higher price. This is used for bumping gas price on stuck transactions.
 //
 // Parameters:
-//   - sendArgs: The original transaction arguments
-//   - gasPrice: New gas price (optional)
+//   - sendArgs: Identifies the transaction by its from and nonce fields
+//   - gasPrice: New gas price, or fee cap for EIP-1559 transactions (optional,
+//     defaults to the old fees raised by resendPriceBump percent)
 //   - gasLimit: New gas limit (optional)
 //
 // Returns:
 //   - The hash of the new transaction
 func (s *TransactionAPI) Resend(ctx context.Context, sendArgs TransactionArgs, gasPrice *hexutil.Big, gasLimit *hexutil.Uint64) (avmcommon.Hash, error) {
-	// Override gas price if provided
-	if gasPrice != nil {
-		sendArgs.GasPrice = gasPrice
+	if sendArgs.From == nil || sendArgs.Nonce == nil {
+		return avmcommon.Hash{}, errors.New("missing transaction sender or nonce in transaction spec")
 	}
-
-	// Override gas limit if provided
-	if gasLimit != nil {
-		sendArgs.Gas = gasLimit
+	from, nonce := sendArgs.from(), uint64(*sendArgs.Nonce)
+	old := findPoolTx(s.api.TxsPool(), from, nonce)
+	if old == nil {
+		return avmcommon.Hash{}, fmt.Errorf("no pool transaction from %v with nonce %d", from, nonce)
 	}
 
-	// Send the transaction (this will replace the old one in the pool)
-	return s.SendTransaction(ctx, sendArgs)
+	account := accounts.Account{Address: from}
+	wallet, err := s.api.accountManager.Find(account)
+	if err != nil {
+		return avmcommon.Hash{}, err
+	}
+	args, err := resendArgs(old, s.api.GetChainConfig().ChainID, gasPrice, gasLimit)
+	if err != nil {
+		return avmcommon.Hash{}, err
+	}
+	signed, err := wallet.SignTx(account, args.toTransaction(), s.api.GetChainConfig().ChainID)
+	if err != nil {
+		return avmcommon.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.api, signed)
 }
@@ -184,8 +184,11 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd jsonrpc.Bloc
 		case jsonrpc.LatestBlockNumber:
 			// Retrieved above.
 			resolved = headBlock
-		//case jsonrpc.SafeBlockNumber:
-		//case jsonrpc.FinalizedBlockNumber:
+		case jsonrpc.SafeBlockNumber, jsonrpc.FinalizedBlockNumber:
+			var number *uint256.Int
+			if number, err = finalityBlockNumber(ctx, oracle.backend.DB(), reqEnd); err == nil {
+				resolved = oracle.backend.GetHeaderByNumber(number)
+			}
 		case jsonrpc.EarliestBlockNumber:
 			resolved = oracle.backend.GetHeaderByNumber(uint256.NewInt(0))
 		}
@@ -2,8 +2,11 @@ package filters
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
@@ -111,33 +114,116 @@ func (filterApi *FilterAPI) NewPendingTransactionFilter() jsonrpc.ID {
 	return pendingTxSub.ID
 }
 
+// PendingTxsOptions selects the notifications of a newPendingTransactions
+// subscription. It decodes from a bool, which sets FullTx, or from an object.
+type PendingTxsOptions struct {
+	FullTx         bool `json:"fullTx"`         // send transaction objects instead of hashes
+	IncludeDropped bool `json:"includeDropped"` // also report replaced and discarded transactions
+}
+
+// UnmarshalJSON accepts both `true` and `{"fullTx":true,"includeDropped":true}`.
+func (o *PendingTxsOptions) UnmarshalJSON(data []byte) error {
+	var fullTx bool
+	if err := json.Unmarshal(data, &fullTx); err == nil {
+		*o = PendingTxsOptions{FullTx: fullTx}
+		return nil
+	}
+	type options PendingTxsOptions
+	return json.Unmarshal(data, (*options)(o))
+}
+
+// Kinds of PendingTxEvent.
+const (
+	PendingTxAdded    = "pending"
+	PendingTxReplaced = "replaced"
+	PendingTxDropped  = "dropped"
+)
+
+// PendingTxEvent is a notification of a newPendingTransactions subscription
+// which includes dropped transactions.
+type PendingTxEvent struct {
+	Type        string      `json:"type"`
+	Hash        types.Hash  `json:"hash"`
+	Transaction interface{} `json:"transaction,omitempty"` // only with FullTx
+	Reason      string      `json:"reason,omitempty"`
+	ReplacedBy  *types.Hash `json:"replacedBy,omitempty"`
+}
+
+// newDroppedTxEvent converts a pool drop into its notification.
+func newDroppedTxEvent(drop common.DroppedTx) *PendingTxEvent {
+	ev := &PendingTxEvent{Type: PendingTxDropped, Hash: drop.Tx.Hash(), Reason: drop.Reason}
+	if drop.Replacement != (types.Hash{}) {
+		by := drop.Replacement
+		ev.Type, ev.ReplacedBy = PendingTxReplaced, &by
+	}
+	return ev
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a transaction
-// enters the transaction pool and was signed from one of the transactions this nodes manages.
-func (filterApi *FilterAPI) NewPendingTransactions(ctx context.Context) (*jsonrpc.Subscription, error) {
+// enters the transaction pool. By default only the transaction hash is sent, opts can
+// request the full transactions and notifications of replaced or discarded transactions.
+func (filterApi *FilterAPI) NewPendingTransactions(ctx context.Context, opts *PendingTxsOptions) (*jsonrpc.Subscription, error) {
 	notifier, supported := jsonrpc.NotifierFromContext(ctx)
 	if !supported {
 		return &jsonrpc.Subscription{}, jsonrpc.ErrNotificationsUnsupported
 	}
+	if opts == nil {
+		opts = new(PendingTxsOptions)
+	}
 
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
-		txHashes := make(chan []types.Hash, 128)
-		pendingTxSub := filterApi.events.SubscribePendingTxs(txHashes)
+		var (
+			txHashes = make(chan []types.Hash, 128)
+			txs      = make(chan []*transaction.Transaction, 128)
+			drops    = make(chan []common.DroppedTx, 128)
+			subs     []*Subscription
+		)
+		if opts.FullTx {
+			subs = append(subs, filterApi.events.SubscribeFullPendingTxs(txs))
+		} else {
+			subs = append(subs, filterApi.events.SubscribePendingTxs(txHashes))
+		}
+		if opts.IncludeDropped {
+			subs = append(subs, filterApi.events.SubscribeDroppedTxs(drops))
+		}
+		defer func() {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+		}()
 
 		for {
 			select {
 			case hashes := <-txHashes:
 				// To keep the original behaviour, send a single tx hash in one notification.
-				// TODO(rjl493456442) Send a batch of tx hashes in one notification
 				for _, h := range hashes {
-					notifier.Notify(rpcSub.ID, h)
+					if opts.IncludeDropped {
+						notifier.Notify(rpcSub.ID, &PendingTxEvent{Type: PendingTxAdded, Hash: h})
+					} else {
+						notifier.Notify(rpcSub.ID, h)
+					}
+				}
+			case batch := <-txs:
+				for _, tx := range batch {
+					if opts.IncludeDropped {
+						notifier.Notify(rpcSub.ID, &PendingTxEvent{Type: PendingTxAdded, Hash: tx.Hash(), Transaction: filterApi.api.RPCTransaction(tx)})
+					} else {
+						notifier.Notify(rpcSub.ID, filterApi.api.RPCTransaction(tx))
+					}
+				}
+			case batch := <-drops:
+				for _, drop := range batch {
+					ev := newDroppedTxEvent(drop)
+					if opts.FullTx {
+						ev.Transaction = filterApi.api.RPCTransaction(drop.Tx)
+					}
+					notifier.Notify(rpcSub.ID, ev)
 				}
 			case <-rpcSub.Err():
-				pendingTxSub.Unsubscribe()
 				return
 			case <-notifier.Closed():
-				pendingTxSub.Unsubscribe()
 				return
 			}
 		}
@@ -230,8 +316,7 @@ func (filterApi *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*jso
 			select {
 			case logs := <-matchedLogs:
 				for _, log := range logs {
-					log := log
-					notifier.Notify(rpcSub.ID, &log)
+					notifier.Notify(rpcSub.ID, avmtypes.FromastLog(log))
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
@@ -290,7 +375,7 @@ func (filterApi *FilterAPI) NewFilter(crit FilterCriteria) (jsonrpc.ID, error) {
 }
 
 // GetLogs returns logs matching the given argument that are stored within the state.
-func (filterApi *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*block.Log, error) {
+func (filterApi *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*avmtypes.Log, error) {
 	var filter *Filter
 	if crit.BlockHash != (types.Hash{}) {
 		// Block filter requested, construct a single-shot filter
@@ -333,7 +418,7 @@ func (filterApi *FilterAPI) UninstallFilter(id jsonrpc.ID) bool {
 
 // GetFilterLogs returns the logs for the filter with the given id.
 // If the filter could not be found an empty array of logs is returned.
-func (filterApi *FilterAPI) GetFilterLogs(ctx context.Context, id jsonrpc.ID) ([]*block.Log, error) {
+func (filterApi *FilterAPI) GetFilterLogs(ctx context.Context, id jsonrpc.ID) ([]*avmtypes.Log, error) {
 	filterApi.filtersMu.Lock()
 	f, found := filterApi.filters[id]
 	filterApi.filtersMu.Unlock()
@@ -408,11 +493,11 @@ func returnHashes(hashes []types.Hash) []types.Hash {
 	return hashes
 }
 
-// returnLogs is a helper that will return an empty log array in case the given logs array is nil,
-// otherwise the given logs array is returned.
-func returnLogs(logs []*block.Log) []*block.Log {
-	if logs == nil {
-		return []*block.Log{}
+// returnLogs is a helper that converts the given logs to their RPC encoding. It
+// returns an empty log array in case there are no logs.
+func returnLogs(logs []*block.Log) []*avmtypes.Log {
+	if len(logs) == 0 {
+		return []*avmtypes.Log{}
 	}
-	return logs
+	return avmtypes.FromastLogs(logs)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal"
 	"github.com/n42blockchain/N42/internal/consensus"
@@ -22,6 +23,8 @@ type Api interface {
 	Engine() consensus.Engine
 	BlockChain() common.IBlockChain
 	GetEvm(ctx context.Context, msg internal.Message, ibs evmtypes.IntraBlockState, header block.IHeader, vmConfig *vm2.Config) (*vm2.EVM, func() error, error)
+	// RPCTransaction returns the RPC representation of a pending transaction.
+	RPCTransaction(tx *transaction.Transaction) interface{}
 }
 
 // Filter can be used to retrieve and filter logs.
@@ -96,7 +99,7 @@ func newFilter(api Api, addresses []types.Address, topics [][]types.Hash) *Filte
 func (f *Filter) Logs(ctx context.Context) ([]*block.Log, error) {
 	// If we're doing singleton block filtering, execute and return
 	if f.block != (types.Hash{}) {
-		header, err := f.api.BlockChain().GetHeaderByHash(f.block)
+		header, err := f.api.BlockChain().GetHeaderByHashContext(ctx, f.block)
 		if err != nil {
 			return nil, err
 		}
@@ -128,25 +131,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*block.Log, error) {
 	if f.end == jsonrpc.LatestBlockNumber.Int64() || f.end == jsonrpc.PendingBlockNumber.Int64() {
 		end = head
 	}
-	// Gather all indexed logs, and finish with non indexed ones
-	var (
-		logs           []*block.Log
-		err            error
-		size, sections = uint64(4096), uint64(10)
-		//todo
-	)
-	if indexed := sections * size; indexed > uint64(f.begin) {
-		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
-		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
-		}
-		if err != nil {
-			return logs, err
-		}
-	}
-	rest, err := f.unindexedLogs(ctx, end)
-	logs = append(logs, rest...)
+	// No bloom bits matcher is wired up yet, so indexedLogs would wait forever
+	// on its matches; scan the whole range block by block instead.
+	logs, err := f.unindexedLogs(ctx, end)
 	if pending {
 		pendingLogs, err := f.pendingLogs()
 		if err != nil {
@@ -188,7 +175,10 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*block.Log, err
 			f.begin = int64(number) + 1
 
 			// Retrieve the suggested block and pull any truly matching logs
-			header := f.api.BlockChain().GetHeaderByNumber(uint256.NewInt(number))
+			header, err := f.api.BlockChain().GetHeaderByNumberContext(ctx, uint256.NewInt(number))
+			if err != nil {
+				return logs, err
+			}
 			if header == nil {
 				return logs, nil
 			}
@@ -210,7 +200,10 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*block.Log, e
 	var logs []*block.Log
 
 	for ; f.begin <= int64(end); f.begin++ {
-		header := f.api.BlockChain().GetHeaderByNumber(uint256.NewInt(uint64(f.begin)))
+		header, err := f.api.BlockChain().GetHeaderByNumberContext(ctx, uint256.NewInt(uint64(f.begin)))
+		if err != nil {
+			return logs, err
+		}
 		if header == nil {
 			return logs, nil
 		}
@@ -225,23 +218,17 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*block.Log, e
 
 // blockLogs returns the logs matching the filter criteria within a single block.
 func (f *Filter) blockLogs(ctx context.Context, header block.IHeader) (logs []*block.Log, err error) {
-	//todo header.Bloom
-	bloom, _ := types.NewBloom(100)
-	if bloomFilter(bloom, f.addresses, f.topics) {
-		found, err := f.checkMatches(ctx, header)
-		if err != nil {
-			return logs, err
-		}
-		logs = append(logs, found...)
+	if h, ok := header.(*block.Header); ok && !bloomFilter(h.Bloom, f.addresses, f.topics) {
+		return nil, nil
 	}
-	return logs, nil
+	return f.checkMatches(ctx, header)
 }
 
 // checkMatches checks if the receipts belonging to the given header contain any log events that
 // match the filter criteria. This function is called when the bloom filter signals a potential match.
 func (f *Filter) checkMatches(ctx context.Context, header block.IHeader) (logs []*block.Log, err error) {
 	// Get the logs of the block
-	logsList, err := f.api.BlockChain().GetLogs(header.Hash())
+	logsList, err := f.api.BlockChain().GetLogsContext(ctx, header.Hash())
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +240,7 @@ func (f *Filter) checkMatches(ctx context.Context, header block.IHeader) (logs [
 	if len(logs) > 0 {
 		// We have matching logs, check if we need to resolve full logs via the light client
 		if logs[0].TxHash == (types.Hash{}) {
-			receipts, err := f.api.BlockChain().GetReceipts(header.Hash())
+			receipts, err := f.api.BlockChain().GetReceiptsContext(ctx, header.Hash())
 			if err != nil {
 				return nil, err
 			}
@@ -328,11 +315,11 @@ Logs:
 	return ret
 }
 
-func bloomFilter(bloom *types.Bloom, addresses []types.Address, topics [][]types.Hash) bool {
+func bloomFilter(bloom block.Bloom, addresses []types.Address, topics [][]types.Hash) bool {
 	if len(addresses) > 0 {
 		var included bool
 		for _, addr := range addresses {
-			if bloom.Contain(addr.Bytes()) {
+			if bloom.Test(addr.Bytes()) {
 				included = true
 				break
 			}
@@ -345,7 +332,7 @@ func bloomFilter(bloom *types.Bloom, addresses []types.Address, topics [][]types
 	for _, sub := range topics {
 		included := len(sub) == 0 // empty rule set == wildcard
 		for _, topic := range sub {
-			if bloom.Contain(topic.Bytes()) {
+			if bloom.Test(topic.Bytes()) {
 				included = true
 				break
 			}
@@ -107,7 +107,7 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 				if err != nil {
 					return err
 				}
-				args.Topics[i] = []types.Hash{avmtypes.ToastHash(top)}
+				args.Topics[i] = []types.Hash{top}
 
 			case []interface{}:
 				// or case e.g. [null, "topic0", "topic1"]
@@ -122,7 +122,7 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 						if err != nil {
 							return err
 						}
-						args.Topics[i] = append(args.Topics[i], avmtypes.ToastHash(parsed))
+						args.Topics[i] = append(args.Topics[i], parsed)
 					} else {
 						return fmt.Errorf("invalid topic(s)")
 					}
@@ -144,10 +144,10 @@ func decodeAddress(s string) (avmcommon.Address, error) {
 	return avmcommon.BytesToAddress(b), err
 }
 
-func decodeTopic(s string) (avmcommon.Hash, error) {
+func decodeTopic(s string) (types.Hash, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != avmcommon.HashLength {
 		err = fmt.Errorf("hex has invalid length %d after decoding; expected %d for topic", len(b), avmcommon.HashLength)
 	}
-	return avmcommon.BytesToHash(b), err
+	return types.BytesToHash(b), err
 }
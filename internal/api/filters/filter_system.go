@@ -6,9 +6,9 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
-	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
 	"sync"
@@ -33,6 +33,9 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// DroppedTransactionsSubscription queries transactions replaced in or
+	// discarded from the transaction pool
+	DroppedTransactionsSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -56,6 +59,8 @@ type subscription struct {
 	logsCrit  FilterCriteria
 	logs      chan []*block.Log
 	hashes    chan []types.Hash
+	txs       chan []*transaction.Transaction // full pending transactions instead of hashes, if set
+	drops     chan []common.DroppedTx
 	headers   chan block.IHeader
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
@@ -69,20 +74,16 @@ type EventSystem struct {
 	lastHead  block.IHeader
 
 	// Subscriptions
-	txsSub         event.Subscription // Subscription for new transaction event
-	logsSub        event.Subscription // Subscription for new log event
-	rmLogsSub      event.Subscription // Subscription for removed log event
-	pendingLogsSub event.Subscription // Subscription for pending log event
-	chainSub       event.Subscription // Subscription for new chain event
+	txsSub         *eventv3.Subscription[common.NewTxsEvent]         // Subscription for new transaction event
+	logsSub        *eventv3.Subscription[common.NewLogsEvent]        // Subscription for new log event
+	rmLogsSub      *eventv3.Subscription[common.RemovedLogsEvent]    // Subscription for removed log event
+	pendingLogsSub *eventv3.Subscription[common.NewPendingLogsEvent] // Subscription for pending log event
+	chainSub       *eventv3.Subscription[common.ChainHighestBlock]   // Subscription for new chain event
+	dropsSub       *eventv3.Subscription[common.DroppedTxsEvent]     // Subscription for dropped transactions event
 
 	// Channels
-	install       chan *subscription              // install filter for event notification
-	uninstall     chan *subscription              // remove filter for event notification
-	txsCh         chan common.NewTxsEvent         // Channel to receive new transactions event
-	logsCh        chan common.NewLogsEvent        // Channel to receive new log event
-	pendingLogsCh chan common.NewPendingLogsEvent // Channel to receive new log event
-	rmLogsCh      chan common.RemovedLogsEvent    // Channel to receive removed log event
-	chainCh       chan common.ChainHighestBlock   // Channel to receive new chain event
+	install   chan *subscription // install filter for event notification
+	uninstall chan *subscription // remove filter for event notification
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -94,28 +95,22 @@ type EventSystem struct {
 func NewEventSystem(api Api) *EventSystem {
 
 	m := &EventSystem{
-		api:           api,
-		lightMode:     false,
-		install:       make(chan *subscription),
-		uninstall:     make(chan *subscription),
-		txsCh:         make(chan common.NewTxsEvent),
-		logsCh:        make(chan common.NewLogsEvent),
-		rmLogsCh:      make(chan common.RemovedLogsEvent),
-		pendingLogsCh: make(chan common.NewPendingLogsEvent),
-		chainCh:       make(chan common.ChainHighestBlock),
+		api:       api,
+		lightMode: false,
+		install:   make(chan *subscription),
+		uninstall: make(chan *subscription),
 	}
 
-	// Subscribe events
-	m.txsSub = event.GlobalEvent.Subscribe(m.txsCh)
-	m.logsSub = event.GlobalEvent.Subscribe(m.logsCh)
-	m.rmLogsSub = event.GlobalEvent.Subscribe(m.rmLogsCh)
-	m.chainSub = event.GlobalEvent.Subscribe(m.chainCh)
-	m.pendingLogsSub = event.GlobalEvent.Subscribe(m.pendingLogsCh)
-
-	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil || m.pendingLogsSub == nil {
-		log.Error("Subscribe for event system failed")
-	}
+	// Subscribe events. Mined and removed logs and new heads must reach the
+	// filters, so they hold up the chain when the filters fall behind; pool
+	// notifications and pending logs are dropped instead of stalling the pool
+	// or the miner.
+	m.txsSub = common.NewTxsTopic.Subscribe(txChanSize, eventv3.DropNewest)
+	m.dropsSub = common.DroppedTxsTopic.Subscribe(txChanSize, eventv3.DropNewest)
+	m.logsSub = common.NewLogsTopic.Subscribe(logsChanSize, eventv3.Block)
+	m.rmLogsSub = common.RemovedLogsTopic.Subscribe(rmLogsChanSize, eventv3.Block)
+	m.pendingLogsSub = common.PendingLogsTopic.Subscribe(logsChanSize, eventv3.DropOldest)
+	m.chainSub = common.ChainHeadTopic.Subscribe(chainEvChanSize, eventv3.Block)
 
 	go m.eventLoop()
 	return m
@@ -148,6 +143,8 @@ func (sub *Subscription) Unsubscribe() {
 				break uninstallLoop
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
+			case <-sub.f.txs:
+			case <-sub.f.drops:
 			case <-sub.f.headers:
 			}
 		}
@@ -288,6 +285,40 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []types.Hash) *Subscripti
 	return es.subscribe(sub)
 }
 
+// SubscribeFullPendingTxs creates a subscription that writes the transactions
+// entering the transaction pool.
+func (es *EventSystem) SubscribeFullPendingTxs(txs chan []*transaction.Transaction) *Subscription {
+	sub := &subscription{
+		id:        jsonrpc.NewID(),
+		typ:       PendingTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*block.Log),
+		hashes:    make(chan []types.Hash),
+		txs:       txs,
+		headers:   make(chan block.IHeader),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeDroppedTxs creates a subscription that writes the transactions
+// replaced in or discarded from the transaction pool.
+func (es *EventSystem) SubscribeDroppedTxs(drops chan []common.DroppedTx) *Subscription {
+	sub := &subscription{
+		id:        jsonrpc.NewID(),
+		typ:       DroppedTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*block.Log),
+		hashes:    make(chan []types.Hash),
+		drops:     drops,
+		headers:   make(chan block.IHeader),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 type filterIndex map[Type]map[jsonrpc.ID]*subscription
 
 func (es *EventSystem) handleLogs(filters filterIndex, ev common.NewLogsEvent) {
@@ -330,7 +361,17 @@ func (es *EventSystem) handleTxsEvent(filters filterIndex, ev common.NewTxsEvent
 		hashes = append(hashes, hash)
 	}
 	for _, f := range filters[PendingTransactionsSubscription] {
-		f.hashes <- hashes
+		if f.txs != nil {
+			f.txs <- ev.Txs
+		} else {
+			f.hashes <- hashes
+		}
+	}
+}
+
+func (es *EventSystem) handleDroppedTxsEvent(filters filterIndex, ev common.DroppedTxsEvent) {
+	for _, f := range filters[DroppedTransactionsSubscription] {
+		f.drops <- ev.Drops
 	}
 }
 
@@ -394,9 +435,7 @@ func (es *EventSystem) lightFilterNewHead(newHeader block.IHeader, callBack func
 
 // filter logs of a single header in light client mode
 func (es *EventSystem) lightFilterLogs(header block.IHeader, addresses []types.Address, topics [][]types.Hash, remove bool) []*block.Log {
-	//todo header.Bloom
-	bloom, _ := types.NewBloom(100)
-	if bloomFilter(bloom, addresses, topics) {
+	if h, ok := header.(*block.Header); !ok || bloomFilter(h.Bloom, addresses, topics) {
 		// Get the logs of the block
 		_, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
@@ -443,6 +482,7 @@ func (es *EventSystem) eventLoop() {
 		es.rmLogsSub.Unsubscribe()
 		es.pendingLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
+		es.dropsSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -452,15 +492,17 @@ func (es *EventSystem) eventLoop() {
 
 	for {
 		select {
-		case ev := <-es.txsCh:
+		case ev := <-es.txsSub.Chan():
 			es.handleTxsEvent(index, ev)
-		case ev := <-es.logsCh:
+		case ev := <-es.dropsSub.Chan():
+			es.handleDroppedTxsEvent(index, ev)
+		case ev := <-es.logsSub.Chan():
 			es.handleLogs(index, ev)
-		case ev := <-es.rmLogsCh:
+		case ev := <-es.rmLogsSub.Chan():
 			es.handleRemovedLogs(index, ev)
-		case ev := <-es.pendingLogsCh:
+		case ev := <-es.pendingLogsSub.Chan():
 			es.handlePendingLogs(index, ev)
-		case ev := <-es.chainCh:
+		case ev := <-es.chainSub.Chan():
 			if ev.Inserted {
 				es.handleChainEvent(index, ev)
 			}
@@ -0,0 +1,107 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+)
+
+// newPendingTx creates a legacy transfer with the given nonce
+func newPendingTx(nonce uint64) *transaction.Transaction {
+	from, to := types.Address{0x01}, types.Address{0xee}
+	return transaction.NewTx(&transaction.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: uint256.NewInt(1),
+		Gas:      21000,
+		To:       &to,
+		From:     &from,
+		Value:    uint256.NewInt(1),
+	})
+}
+
+func TestPendingTxsOptionsUnmarshal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  PendingTxsOptions
+	}{
+		{`true`, PendingTxsOptions{FullTx: true}},
+		{`false`, PendingTxsOptions{}},
+		{`{"includeDropped":true}`, PendingTxsOptions{IncludeDropped: true}},
+		{`{"fullTx":true,"includeDropped":true}`, PendingTxsOptions{FullTx: true, IncludeDropped: true}},
+	}
+	for _, test := range tests {
+		var have PendingTxsOptions
+		if err := json.Unmarshal([]byte(test.input), &have); err != nil {
+			t.Errorf("%s: %v", test.input, err)
+		} else if have != test.want {
+			t.Errorf("%s: have %+v, want %+v", test.input, have, test.want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`"full"`), new(PendingTxsOptions)); err == nil {
+		t.Error("string options accepted")
+	}
+}
+
+func TestDroppedTxEvent(t *testing.T) {
+	tx, replacement := newPendingTx(0), newPendingTx(1)
+
+	ev := newDroppedTxEvent(common.DroppedTx{Tx: tx, Reason: "underpriced"})
+	if ev.Type != PendingTxDropped || ev.Hash != tx.Hash() || ev.Reason != "underpriced" || ev.ReplacedBy != nil {
+		t.Errorf("unexpected drop %+v", ev)
+	}
+	ev = newDroppedTxEvent(common.DroppedTx{Tx: tx, Reason: "replaced", Replacement: replacement.Hash()})
+	if ev.Type != PendingTxReplaced || ev.ReplacedBy == nil || *ev.ReplacedBy != replacement.Hash() {
+		t.Errorf("unexpected replacement %+v", ev)
+	}
+}
+
+func TestHandlePendingTxsEvents(t *testing.T) {
+	var (
+		es       = new(EventSystem)
+		hashes   = make(chan []types.Hash, 1)
+		txs      = make(chan []*transaction.Transaction, 1)
+		drops    = make(chan []common.DroppedTx, 1)
+		index    = filterIndex{PendingTransactionsSubscription: {}, DroppedTransactionsSubscription: {}}
+		pending  = []*transaction.Transaction{newPendingTx(0), newPendingTx(1)}
+		hashSub  = &subscription{id: jsonrpc.NewID(), typ: PendingTransactionsSubscription, hashes: hashes}
+		fullSub  = &subscription{id: jsonrpc.NewID(), typ: PendingTransactionsSubscription, hashes: make(chan []types.Hash), txs: txs}
+		dropsSub = &subscription{id: jsonrpc.NewID(), typ: DroppedTransactionsSubscription, drops: drops}
+	)
+	index[PendingTransactionsSubscription][hashSub.id] = hashSub
+	index[PendingTransactionsSubscription][fullSub.id] = fullSub
+	index[DroppedTransactionsSubscription][dropsSub.id] = dropsSub
+
+	es.handleTxsEvent(index, common.NewTxsEvent{Txs: pending})
+	if have := <-hashes; len(have) != 2 || have[1] != pending[1].Hash() {
+		t.Errorf("unexpected hashes %v", have)
+	}
+	if have := <-txs; len(have) != 2 || have[0] != pending[0] {
+		t.Errorf("unexpected transactions %v", have)
+	}
+
+	es.handleDroppedTxsEvent(index, common.DroppedTxsEvent{Drops: []common.DroppedTx{{Tx: pending[0], Reason: "expired"}}})
+	if have := <-drops; len(have) != 1 || have[0].Tx != pending[0] || have[0].Reason != "expired" {
+		t.Errorf("unexpected drops %+v", have)
+	}
+}
@@ -26,7 +26,7 @@ import (
 	"github.com/n42blockchain/N42/conf"
 	types "github.com/n42blockchain/N42/common/avmtypes"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
 	"github.com/n42blockchain/N42/params"
 	"math/big"
@@ -94,27 +94,40 @@ func NewOracle(backend common2.IBlockChain, miner common2.IMiner, chainConfig *p
 		log.Warn("Sanitizing invalid gasprice oracle max block history", "provided", params.MaxBlockHistory, "updated", maxBlockHistory)
 	}
 
+	lastPrice := params.Default
+	if lastPrice == nil {
+		lastPrice = new(big.Int)
+		log.Warn("Sanitizing invalid gasprice oracle default price", "provided", params.Default, "updated", lastPrice)
+	}
+
 	cache, _ := lru.New(2048)
 
-	highestBlockCh := make(chan common2.ChainHighestBlock)
-	defer close(highestBlockCh)
-	highestSub := event.GlobalEvent.Subscribe(highestBlockCh)
-	defer highestSub.Unsubscribe()
+	// Purge the fee history cache whenever the head does not extend the previous
+	// one, since cached entries may then belong to blocks that are no longer canonical.
+	// The subscription lives as long as the oracle, i.e. for the lifetime of the node.
+	// A dropped head only causes a spurious purge.
+	highestSub := common2.ChainHeadTopic.Subscribe(16, eventv3.DropOldest)
 
 	go func() {
+		defer highestSub.Unsubscribe()
 		var lastHead types2.Hash
-		for ev := range highestBlockCh {
-			if ev.Block.ParentHash() != lastHead {
-				cache.Purge()
+		for {
+			select {
+			case ev := <-highestSub.Chan():
+				if ev.Block.ParentHash() != lastHead {
+					cache.Purge()
+				}
+				lastHead = ev.Block.Hash()
+			case <-highestSub.Err():
+				return
 			}
-			lastHead = ev.Block.Hash()
 		}
 	}()
 
 	return &Oracle{
 		backend:          backend,
 		miner:            miner,
-		lastPrice:        params.Default,
+		lastPrice:        lastPrice,
 		maxPrice:         maxPrice,
 		ignorePrice:      ignorePrice,
 		checkBlocks:      blocks,
@@ -61,6 +61,7 @@ type Router struct {
 	enablePersonal bool
 	enableMiner    bool
 	enableRPC      bool
+	enableStaking  bool
 }
 
 // RouterConfig holds configuration for the API router.
@@ -76,6 +77,7 @@ type RouterConfig struct {
 	EnablePersonal bool
 	EnableMiner    bool
 	EnableRPC      bool
+	EnableStaking  bool
 
 	// Metrics configuration
 	MetricsLogInterval time.Duration
@@ -94,6 +96,7 @@ func DefaultRouterConfig() *RouterConfig {
 		EnablePersonal:     false, // Disabled by default for security
 		EnableMiner:        true,
 		EnableRPC:          true,
+		EnableStaking:      true,
 		MetricsLogInterval: 60 * time.Second,
 	}
 }
@@ -117,6 +120,7 @@ func NewRouter(api *API, config *RouterConfig) *Router {
 		enablePersonal: config.EnablePersonal,
 		enableMiner:    config.EnableMiner,
 		enableRPC:      config.EnableRPC,
+		enableStaking:  config.EnableStaking,
 	}
 }
 
@@ -146,6 +150,11 @@ func (r *Router) APIs() []jsonrpc.API {
 				Namespace: "eth",
 				Service:   filters.NewFilterAPI(r.api, 5*time.Minute),
 			},
+			jsonrpc.API{
+				Namespace:     "eth",
+				Service:       NewBundleAPI(r.api),
+				Authenticated: true,
+			},
 		)
 	}
 
@@ -182,6 +191,10 @@ func (r *Router) APIs() []jsonrpc.API {
 		apis = append(apis, jsonrpc.API{
 			Namespace: "txpool",
 			Service:   NewTxsPoolAPI(r.api),
+		}, jsonrpc.API{
+			Namespace:     "txpool",
+			Service:       NewTxsPoolAdminAPI(r.api),
+			Authenticated: true,
 		})
 	}
 
@@ -197,7 +210,7 @@ func (r *Router) APIs() []jsonrpc.API {
 	if r.enablePersonal {
 		apis = append(apis, jsonrpc.API{
 			Namespace: "personal",
-			Service:   NewPersonalAPI(r.api),
+			Service:   NewPersonalAPI(r.api, nonceLock),
 		})
 	}
 
@@ -217,6 +230,14 @@ func (r *Router) APIs() []jsonrpc.API {
 		})
 	}
 
+	// staking namespace (validator set, deposits and rewards)
+	if r.enableStaking {
+		apis = append(apis, jsonrpc.API{
+			Namespace: "staking",
+			Service:   NewStakingAPI(r.api),
+		})
+	}
+
 	return apis
 }
 
@@ -24,17 +24,26 @@ package api
 // Only safe, read-only or simple methods are included.
 //
 // Namespaces covered:
-// - admin_*   : Node administration (read-only info)
+// - admin_*   : Node administration (read-only info, log level, reorg overrides)
 // - personal_*: Account management (limited)
 // - miner_*   : Mining control (PoA compatible)
 // - rpc_*     : RPC module info
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"runtime"
+	"time"
 
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/accounts/keystore"
+	avmcommon "github.com/n42blockchain/N42/common/avmutil"
+	"github.com/n42blockchain/N42/common/crypto"
 	"github.com/n42blockchain/N42/common/hexutil"
 	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
 	"github.com/n42blockchain/N42/params"
 )
 
@@ -110,6 +119,27 @@ func (admin *AdminAPI) Datadir() string {
 	return ""
 }
 
+// SetLogLevel changes the log level of the running node. It accepts trace,
+// debug, info, warn, error and fatal.
+func (admin *AdminAPI) SetLogLevel(level string) (bool, error) {
+	if err := log.SetLevel(level); err != nil {
+		return false, err
+	}
+	log.Info("Log level changed", "level", level)
+	return true, nil
+}
+
+// AllowReorg lets the chain follow the next reorg onto the fork containing
+// hash even when it is deeper than the configured reorg depth limit.
+func (admin *AdminAPI) AllowReorg(hash types.Hash) (bool, error) {
+	chain, ok := admin.api.BlockChain().(interface{ AllowReorg(types.Hash) })
+	if !ok {
+		return false, errors.New("chain does not support reorg overrides")
+	}
+	chain.AllowReorg(hash)
+	return true, nil
+}
+
 // AddPeer requests connecting to a remote node.
 // The enode is a URL like: enode://pubkey@ip:port
 func (admin *AdminAPI) AddPeer(url string) (bool, error) {
@@ -166,12 +196,14 @@ func (admin *AdminAPI) StopWS() (bool, error) {
 
 // PersonalAPI provides account management RPC methods.
 type PersonalAPI struct {
-	api *API
+	api       *API
+	nonceLock *AddrLocker
 }
 
-// NewPersonalAPI creates a new PersonalAPI instance.
-func NewPersonalAPI(api *API) *PersonalAPI {
-	return &PersonalAPI{api: api}
+// NewPersonalAPI creates a new PersonalAPI instance. The nonce lock is shared
+// with the eth transaction API so both assign nonces in order.
+func NewPersonalAPI(api *API, nonceLock *AddrLocker) *PersonalAPI {
+	return &PersonalAPI{api: api, nonceLock: nonceLock}
 }
 
 // ListAccounts returns the list of accounts managed by the node.
@@ -206,6 +238,213 @@ func (personal *PersonalAPI) ListWallets() []map[string]interface{} {
 	return result
 }
 
+var (
+	// errNoAccountManager is returned by the personal methods on a node without accounts.
+	errNoAccountManager = errors.New("account manager not available")
+	// errNoKeystore is returned when the node has no local keystore backend.
+	errNoKeystore = errors.New("local keystore not used")
+	// errExtUnlockForbidden is returned when unlocking over externally reachable
+	// RPC without --allow-insecure-unlock.
+	errExtUnlockForbidden = errors.New("account unlock with HTTP access is forbidden")
+)
+
+// defaultUnlockDuration is how long personal_unlockAccount keeps an account
+// unlocked when no duration is given.
+const defaultUnlockDuration = 300 * time.Second
+
+// keyStore returns the local keystore backend of the node.
+func (personal *PersonalAPI) keyStore() (*keystore.KeyStore, error) {
+	if personal.api == nil || personal.api.accountManager == nil {
+		return nil, errNoAccountManager
+	}
+	backends := personal.api.accountManager.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		return nil, errNoKeystore
+	}
+	return backends[0].(*keystore.KeyStore), nil
+}
+
+// NewAccount creates a new key in the local keystore, encrypted with the
+// given password, and returns its address.
+func (personal *PersonalAPI) NewAccount(password string) (types.Address, error) {
+	ks, err := personal.keyStore()
+	if err != nil {
+		return types.Address{}, err
+	}
+	acc, err := ks.NewAccount(password)
+	if err != nil {
+		return types.Address{}, err
+	}
+	log.Info("Your new key was generated", "address", acc.Address)
+	log.Warn("Please backup your key file!", "path", acc.URL.Path)
+	log.Warn("Please remember your password!")
+	return acc.Address, nil
+}
+
+// ImportRawKey stores the given hex encoded ECDSA key in the local keystore,
+// encrypted with the given password.
+func (personal *PersonalAPI) ImportRawKey(privkey string, password string) (types.Address, error) {
+	key, err := crypto.HexToECDSA(privkey)
+	if err != nil {
+		return types.Address{}, err
+	}
+	ks, err := personal.keyStore()
+	if err != nil {
+		return types.Address{}, err
+	}
+	acc, err := ks.ImportECDSA(key, password)
+	return acc.Address, err
+}
+
+// UnlockAccount unlocks the account with the given password for duration
+// seconds, 300 seconds when omitted and until the node stops when 0. Unlocking
+// is refused while the RPC endpoints are reachable from outside the node,
+// unless the node runs with --allow-insecure-unlock.
+func (personal *PersonalAPI) UnlockAccount(ctx context.Context, addr types.Address, password string, duration *uint64) (bool, error) {
+	ks, err := personal.keyStore()
+	if err != nil {
+		return false, err
+	}
+	if personal.api.extRPCEnabled && !personal.api.accountManager.Config().InsecureUnlockAllowed {
+		return false, errExtUnlockForbidden
+	}
+	const max = uint64(time.Duration(math.MaxInt64) / time.Second)
+	d := defaultUnlockDuration
+	if duration != nil {
+		if *duration > max {
+			return false, errors.New("unlock duration too large")
+		}
+		d = time.Duration(*duration) * time.Second
+	}
+	if err := ks.TimedUnlock(accounts.Account{Address: addr}, password, d); err != nil {
+		log.Warn("Failed account unlock attempt", "address", addr, "err", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// LockAccount removes the unlocked key of the account from memory.
+func (personal *PersonalAPI) LockAccount(addr types.Address) bool {
+	ks, err := personal.keyStore()
+	if err != nil {
+		return false
+	}
+	return ks.Lock(addr) == nil
+}
+
+// SendTransaction signs the transaction with the key of args.from, decrypted
+// with the given password for this call only, and submits it to the pool.
+func (personal *PersonalAPI) SendTransaction(ctx context.Context, args TransactionArgs, passwd string) (avmcommon.Hash, error) {
+	if personal.api == nil || personal.api.accountManager == nil {
+		return avmcommon.Hash{}, errNoAccountManager
+	}
+	account := accounts.Account{Address: args.from()}
+	wallet, err := personal.api.accountManager.Find(account)
+	if err != nil {
+		return avmcommon.Hash{}, err
+	}
+	if args.Nonce == nil && personal.nonceLock != nil {
+		personal.nonceLock.LockAddr(args.from())
+		defer personal.nonceLock.UnlockAddr(args.from())
+	}
+	if err := args.setDefaults(ctx, personal.api); err != nil {
+		return avmcommon.Hash{}, err
+	}
+	signed, err := wallet.SignTxWithPassphrase(account, passwd, args.toTransaction(), personal.api.GetChainConfig().ChainID)
+	if err != nil {
+		log.Warn("Failed transaction send attempt", "from", args.from(), "err", err)
+		return avmcommon.Hash{}, err
+	}
+	signed.SetFrom(args.from())
+	return SubmitTransaction(ctx, personal.api, signed)
+}
+
+// OpenWallet initiates a hardware wallet opening procedure, establishing a USB
+// connection and attempting to authenticate via the provided passphrase. Note,
+// the method may return an extra challenge requiring a second open (e.g. the
+// Trezor PIN matrix challenge).
+func (personal *PersonalAPI) OpenWallet(url string, passphrase *string) error {
+	if personal.api == nil || personal.api.accountManager == nil {
+		return errNoAccountManager
+	}
+	wallet, err := personal.api.accountManager.Wallet(url)
+	if err != nil {
+		return err
+	}
+	pass := ""
+	if passphrase != nil {
+		pass = *passphrase
+	}
+	return wallet.Open(pass)
+}
+
+// DeriveAccount requests an HD wallet to derive a new account, optionally
+// pinning it for later reuse.
+func (personal *PersonalAPI) DeriveAccount(url string, path string, pin *bool) (accounts.Account, error) {
+	if personal.api == nil || personal.api.accountManager == nil {
+		return accounts.Account{}, errNoAccountManager
+	}
+	wallet, err := personal.api.accountManager.Wallet(url)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	derivPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	if pin == nil {
+		pin = new(bool)
+	}
+	return wallet.Derive(derivPath, *pin)
+}
+
+// Sign calculates an Ethereum ECDSA signature for:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message))
+//
+// The key used to calculate the signature is decrypted with the given password,
+// hardware wallets ignore it and ask the user to confirm on the device instead.
+// The produced signature has V set to 27 or 28, as expected by ecrecover.
+func (personal *PersonalAPI) Sign(ctx context.Context, data hexutil.Bytes, addr types.Address, passwd string) (hexutil.Bytes, error) {
+	if personal.api == nil || personal.api.accountManager == nil {
+		return nil, errNoAccountManager
+	}
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr}
+
+	wallet, err := personal.api.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	// Assemble sign the data with the wallet
+	signature, err := wallet.SignTextWithPassphrase(account, passwd, data)
+	if err != nil {
+		log.Warn("Failed data sign attempt", "address", addr, "err", err)
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	return signature, nil
+}
+
+// EcRecover returns the address for the account that was used to create the
+// signature produced by personal_sign.
+func (personal *PersonalAPI) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (types.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return types.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+	if sig[crypto.RecoveryIDOffset] != 27 && sig[crypto.RecoveryIDOffset] != 28 {
+		return types.Address{}, errors.New("invalid Ethereum signature (V is not 27 or 28)")
+	}
+	// Transform yellow paper V from 27/28 to 0/1
+	sig = append(hexutil.Bytes{}, sig...)
+	sig[crypto.RecoveryIDOffset] -= 27
+
+	rpk, err := crypto.SigToPub(accounts.TextHash(data), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*rpk), nil
+}
+
 // =============================================================================
 // Miner API - Mining Control (PoA Compatible)
 // =============================================================================
@@ -236,8 +475,10 @@ func (miner *MinerAPI) Stop() {
 
 // Mining returns whether the node is currently mining.
 func (miner *MinerAPI) Mining() bool {
-	// TODO: Integrate with miner module
-	return false
+	if miner.api == nil || miner.api.Miner() == nil {
+		return false
+	}
+	return miner.api.Miner().Mining()
 }
 
 // SetEtherbase sets the etherbase (coinbase) address.
@@ -285,6 +526,7 @@ func (rpc *RPCAPI) Modules() map[string]string {
 		"personal": "1.0",
 		"miner":    "1.0",
 		"rpc":      "1.0",
+		"staking":  "1.0",
 	}
 }
 
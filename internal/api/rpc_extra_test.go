@@ -17,9 +17,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"runtime"
 	"testing"
+	"time"
+
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/accounts/keystore"
+	"github.com/n42blockchain/N42/params"
 )
 
 // =============================================================================
@@ -208,6 +214,91 @@ func TestListWallets(t *testing.T) {
 	t.Logf("✓ ListWallets returns %d wallets", len(wallets))
 }
 
+func TestPersonalUnlockAccount(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	am := accounts.NewManager(&accounts.Config{}, ks)
+	personal := NewPersonalAPI(&API{accountManager: am, extRPCEnabled: true}, new(AddrLocker))
+
+	addr, err := personal.NewAccount("secret")
+	if err != nil {
+		t.Fatalf("NewAccount failed: %v", err)
+	}
+	account := accounts.Account{Address: addr}
+
+	// 对外开放 RPC 且未允许不安全解锁时应拒绝
+	if ok, err := personal.UnlockAccount(context.Background(), addr, "secret", nil); ok || err != errExtUnlockForbidden {
+		t.Fatalf("UnlockAccount over external RPC = %v, %v; want %v", ok, err, errExtUnlockForbidden)
+	}
+
+	personal.api.extRPCEnabled = false
+	if ok, _ := personal.UnlockAccount(context.Background(), addr, "wrong", nil); ok {
+		t.Fatal("UnlockAccount succeeded with a wrong password")
+	}
+	if ok, err := personal.UnlockAccount(context.Background(), addr, "secret", nil); !ok || err != nil {
+		t.Fatalf("UnlockAccount failed: %v", err)
+	}
+	if _, err := ks.SignHash(account, make([]byte, 32)); err != nil {
+		t.Fatalf("SignHash after unlock failed: %v", err)
+	}
+
+	// 锁定后不能再签名
+	if !personal.LockAccount(addr) {
+		t.Fatal("LockAccount failed")
+	}
+	if _, err := ks.SignHash(account, make([]byte, 32)); err != keystore.ErrLocked {
+		t.Fatalf("SignHash after lock = %v; want %v", err, keystore.ErrLocked)
+	}
+
+	// 带时限的解锁到期后自动锁定
+	duration := uint64(1)
+	if ok, err := personal.UnlockAccount(context.Background(), addr, "secret", &duration); !ok || err != nil {
+		t.Fatalf("timed UnlockAccount failed: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := ks.SignHash(account, make([]byte, 32)); err != keystore.ErrLocked {
+		t.Fatalf("SignHash after unlock expiry = %v; want %v", err, keystore.ErrLocked)
+	}
+}
+
+func TestPersonalInsecureUnlock(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	am := accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks)
+	personal := NewPersonalAPI(&API{accountManager: am, extRPCEnabled: true}, new(AddrLocker))
+
+	addr, err := personal.ImportRawKey("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291", "secret")
+	if err != nil {
+		t.Fatalf("ImportRawKey failed: %v", err)
+	}
+	if ok, err := personal.UnlockAccount(context.Background(), addr, "secret", nil); !ok || err != nil {
+		t.Fatalf("UnlockAccount with --allow-insecure-unlock failed: %v", err)
+	}
+	personal.LockAccount(addr)
+}
+
+func TestApisPersonal(t *testing.T) {
+	hasPersonal := func(api *API) bool {
+		for _, a := range api.Apis() {
+			if a.Namespace == "personal" {
+				return true
+			}
+		}
+		return false
+	}
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	api := &API{accountManager: accounts.NewManager(&accounts.Config{}, ks), chainConfig: params.TestChainConfig}
+	if !hasPersonal(api) {
+		t.Fatal("personal namespace missing without external RPC")
+	}
+	api.extRPCEnabled = true
+	if hasPersonal(api) {
+		t.Fatal("personal namespace served over external RPC")
+	}
+	api.accountManager = accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks)
+	if !hasPersonal(api) {
+		t.Fatal("personal namespace missing with --allow-insecure-unlock")
+	}
+}
+
 // =============================================================================
 // RPCAPI 测试
 // =============================================================================
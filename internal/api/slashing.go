@@ -0,0 +1,127 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// doubleSigns remembers the signatures submitted for recent heights.
+var doubleSigns = newDoubleSignDetector(maxTrackedRounds)
+
+// signedRoot is a state root signed by a verifier
+type signedRoot struct {
+	root types.Hash
+	sig  types.Signature
+}
+
+// doubleSignDetector finds verifiers signing two different state roots for
+// the same block number. Only signatures which verify against the public key
+// of the verifier are kept, so a forged submission cannot frame anyone.
+type doubleSignDetector struct {
+	mu     sync.Mutex
+	limit  int
+	rounds map[uint64]map[types.Address]signedRoot
+}
+
+func newDoubleSignDetector(limit int) *doubleSignDetector {
+	return &doubleSignDetector{limit: limit, rounds: make(map[uint64]map[types.Address]signedRoot)}
+}
+
+// observe records the signature s and returns evidence if its signer already
+// signed another root at the same height.
+func (d *doubleSignDetector) observe(s *AggSign) *rawdb.DoubleSignEvidence {
+	d.mu.Lock()
+	prev, seen := d.rounds[s.Number][s.Address]
+	d.mu.Unlock()
+	if seen && prev.root == s.StateRoot {
+		return nil
+	}
+	if !s.verify() {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	round, ok := d.rounds[s.Number]
+	if !ok {
+		round = make(map[types.Address]signedRoot)
+		d.rounds[s.Number] = round
+		d.prune(s.Number)
+	}
+	prev, seen = round[s.Address]
+	if !seen {
+		round[s.Address] = signedRoot{root: s.StateRoot, sig: s.Sign}
+		return nil
+	}
+	if prev.root == s.StateRoot {
+		return nil
+	}
+	return &rawdb.DoubleSignEvidence{
+		Number:     s.Number,
+		Address:    s.Address,
+		PublicKey:  s.PublicKey,
+		Root:       prev.root,
+		Signature:  prev.sig,
+		Root2:      s.StateRoot,
+		Signature2: s.Sign,
+		Time:       uint64(time.Now().Unix()),
+	}
+}
+
+// prune drops the lowest height once more than limit heights are tracked.
+func (d *doubleSignDetector) prune(number uint64) {
+	if len(d.rounds) <= d.limit {
+		return
+	}
+	oldest := number
+	for n := range d.rounds {
+		if n < oldest {
+			oldest = n
+		}
+	}
+	delete(d.rounds, oldest)
+}
+
+// recordDoubleSign stores evidence against the signer of s if it signed
+// another root at the same height.
+func recordDoubleSign(db kv.RwDB, s *AggSign) {
+	ev := doubleSigns.observe(s)
+	if ev == nil {
+		return
+	}
+	var added bool
+	if err := db.Update(context.Background(), func(tx kv.RwTx) (err error) {
+		added, err = rawdb.WriteDoubleSignEvidence(tx, ev)
+		return err
+	}); err != nil {
+		log.Error("Failed to store double sign evidence", "number", ev.Number, "address", ev.Address, "err", err)
+		return
+	}
+	if added {
+		verifyDoubleSigns.Inc()
+		log.Warn("Verifier signed two state roots", "number", ev.Number, "address", ev.Address, "root", ev.Root, "root2", ev.Root2)
+	}
+}
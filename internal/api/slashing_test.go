@@ -0,0 +1,108 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// newAggSign signs root at number with secret
+func newAggSign(secret bls.SecretKey, addr types.Address, number uint64, root types.Hash) AggSign {
+	s := AggSign{Number: number, StateRoot: root, Address: addr}
+	copy(s.Sign[:], secret.Sign(root[:]).Marshal())
+	copy(s.PublicKey[:], secret.PublicKey().Marshal())
+	return s
+}
+
+func TestDoubleSignDetector(t *testing.T) {
+	secret, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := types.HexToAddress("0x1111111111111111111111111111111111111111")
+	rootA, rootB := types.Hash{0x0a}, types.Hash{0x0b}
+	d := newDoubleSignDetector(2)
+
+	first := newAggSign(secret, addr, 10, rootA)
+	if ev := d.observe(&first); ev != nil {
+		t.Fatalf("unexpected evidence %+v", ev)
+	}
+	if ev := d.observe(&first); ev != nil {
+		t.Fatalf("resubmission reported as double sign: %+v", ev)
+	}
+
+	// A forged signature over another root is not evidence
+	forged := newAggSign(secret, addr, 10, rootA)
+	forged.StateRoot = rootB
+	if ev := d.observe(&forged); ev != nil {
+		t.Fatalf("forged signature reported as double sign: %+v", ev)
+	}
+
+	second := newAggSign(secret, addr, 10, rootB)
+	ev := d.observe(&second)
+	if ev == nil {
+		t.Fatal("double sign not detected")
+	}
+	if ev.Number != 10 || ev.Address != addr || ev.Root != rootA || ev.Root2 != rootB || ev.Signature != first.Sign {
+		t.Errorf("unexpected evidence %+v", ev)
+	}
+
+	// Old heights are forgotten
+	for n := uint64(11); n <= 12; n++ {
+		s := newAggSign(secret, addr, n, rootA)
+		d.observe(&s)
+	}
+	if ev := d.observe(&second); ev != nil {
+		t.Errorf("pruned height still tracked: %+v", ev)
+	}
+}
+
+func TestStakingDoubleSigns(t *testing.T) {
+	s := newStakingAPI(t, nil)
+	secret, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doubleSigns = newDoubleSignDetector(maxTrackedRounds)
+
+	first := newAggSign(secret, stakingUser, 7, types.Hash{0x0a})
+	second := newAggSign(secret, stakingUser, 7, types.Hash{0x0b})
+	recordDoubleSign(s.api.db, &first)
+	recordDoubleSign(s.api.db, &second)
+	recordDoubleSign(s.api.db, &second)
+
+	ctx := context.Background()
+	evs, err := s.DoubleSigns(ctx, 0, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 1 || evs[0].Address != stakingUser || evs[0].Number != 7 || evs[0].Signature2 != second.Sign {
+		t.Fatalf("unexpected evidence %+v", evs)
+	}
+
+	other := types.HexToAddress("0x2222222222222222222222222222222222222222")
+	if evs, err := s.DoubleSigns(ctx, 0, 100, &other); err != nil || len(evs) != 0 {
+		t.Errorf("got %d evidences for another address, err %v", len(evs), err)
+	}
+	if evs, err := s.DoubleSigns(ctx, 8, 100, nil); err != nil || len(evs) != 0 {
+		t.Errorf("got %d evidences after the height, err %v", len(evs), err)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+// =============================================================================
+// Staking API - Validator Set, Deposits and Rewards
+// =============================================================================
+//
+// The staking_* methods expose what the node tracks about the verifiers of
+// the APos consensus, so dashboards don't have to read the deposit contracts
+// or the reward tables themselves:
+// - staking_validators        : the active validator set
+// - staking_getValidator      : deposit and reward info of one validator
+// - staking_pendingWithdrawals: withdraw calls waiting in the transaction pool
+// - staking_doubleSigns       : evidence of verifiers signing two state roots
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/contracts/deposit"
+	"github.com/n42blockchain/N42/contracts/deposit/bindings"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// withdrawSelector is the selector of withdraw(), shared by all deposit contracts
+var withdrawSelector = bindings.WithdrawMethodID[:]
+
+var errNotValidator = errors.New("address has no deposit")
+
+// StakingAPI provides the staking RPC methods.
+type StakingAPI struct {
+	api *API
+}
+
+// NewStakingAPI creates a new StakingAPI instance.
+func NewStakingAPI(api *API) *StakingAPI {
+	return &StakingAPI{api: api}
+}
+
+// Validator is the deposit and reward info of a validator.
+type Validator struct {
+	Address           types.Address   `json:"address"`
+	PublicKey         types.PublicKey `json:"publicKey"`
+	DepositAmount     *uint256.Int    `json:"depositAmount"`
+	RewardPerBlock    *uint256.Int    `json:"rewardPerBlock"`
+	MaxRewardPerEpoch *uint256.Int    `json:"maxRewardPerEpoch"`
+	RewardUnpaid      *uint256.Int    `json:"rewardUnpaid"`
+}
+
+// ValidatorSet is the active validator set at the head of the chain.
+type ValidatorSet struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         types.Hash     `json:"hash"`
+	TotalDeposit *uint256.Int   `json:"totalDeposit"`
+	Validators   []*Validator   `json:"validators"`
+}
+
+// PendingWithdrawal is a call to withdraw a deposit that has not been mined yet.
+type PendingWithdrawal struct {
+	Address       types.Address  `json:"address"`
+	Contract      types.Address  `json:"contract"`
+	Hash          types.Hash     `json:"hash"`
+	Nonce         hexutil.Uint64 `json:"nonce"`
+	Queued        bool           `json:"queued"`
+	DepositAmount *uint256.Int   `json:"depositAmount"`
+	RewardUnpaid  *uint256.Int   `json:"rewardUnpaid"`
+}
+
+// readValidator reads the deposit and reward info of addr, or returns nil
+// if addr has no deposit.
+func readValidator(tx kv.Tx, addr types.Address) (*Validator, error) {
+	info := deposit.GetDepositInfo(tx, addr)
+	if info == nil {
+		return nil, nil
+	}
+	unpaid, err := rawdb.GetAccountReward(tx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		Address:           addr,
+		PublicKey:         info.PublicKey,
+		DepositAmount:     info.DepositAmount,
+		RewardPerBlock:    info.RewardPerBlock,
+		MaxRewardPerEpoch: info.MaxRewardPerEpoch,
+		RewardUnpaid:      unpaid,
+	}, nil
+}
+
+// Validators returns the active validator set, the accounts with a deposit
+// at the head of the chain, ordered by address.
+func (s *StakingAPI) Validators(ctx context.Context) (*ValidatorSet, error) {
+	head := s.api.BlockChain().CurrentBlock()
+	set := &ValidatorSet{
+		Number:       hexutil.Uint64(head.Number64().Uint64()),
+		Hash:         head.Hash(),
+		TotalDeposit: uint256.NewInt(0),
+		Validators:   []*Validator{},
+	}
+	err := s.api.Database().View(ctx, func(tx kv.Tx) error {
+		addrs, err := rawdb.ReadDepositAddresses(tx)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			v, err := readValidator(tx, addr)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				continue
+			}
+			set.Validators = append(set.Validators, v)
+			set.TotalDeposit.Add(set.TotalDeposit, v.DepositAmount)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// GetValidator returns the deposit and reward info of a validator.
+func (s *StakingAPI) GetValidator(ctx context.Context, address types.Address) (*Validator, error) {
+	var v *Validator
+	err := s.api.Database().View(ctx, func(tx kv.Tx) (err error) {
+		v, err = readValidator(tx, address)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errNotValidator
+	}
+	return v, nil
+}
+
+// PendingWithdrawals returns the withdraw calls to the deposit contracts that
+// are waiting in the transaction pool, ordered by sender and nonce.
+func (s *StakingAPI) PendingWithdrawals(ctx context.Context) ([]*PendingWithdrawal, error) {
+	contracts := s.depositContracts()
+	withdrawals := []*PendingWithdrawal{}
+	if len(contracts) == 0 {
+		return withdrawals, nil
+	}
+
+	pending, queued := s.api.TxsPool().Content()
+	collect := func(txs map[types.Address][]*transaction.Transaction, isQueued bool) {
+		for from, list := range txs {
+			for _, tx := range list {
+				to := tx.To()
+				if to == nil || !contracts[*to] || !bytes.HasPrefix(tx.Data(), withdrawSelector) {
+					continue
+				}
+				withdrawals = append(withdrawals, &PendingWithdrawal{
+					Address:  from,
+					Contract: *to,
+					Hash:     tx.Hash(),
+					Nonce:    hexutil.Uint64(tx.Nonce()),
+					Queued:   isQueued,
+				})
+			}
+		}
+	}
+	collect(pending, false)
+	collect(queued, true)
+
+	err := s.api.Database().View(ctx, func(tx kv.Tx) error {
+		for _, w := range withdrawals {
+			if info := deposit.GetDepositInfo(tx, w.Address); info != nil {
+				w.DepositAmount = info.DepositAmount
+			}
+			unpaid, err := rawdb.GetAccountReward(tx, w.Address)
+			if err != nil {
+				return err
+			}
+			w.RewardUnpaid = unpaid
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(withdrawals, func(i, j int) bool {
+		if withdrawals[i].Address != withdrawals[j].Address {
+			return bytes.Compare(withdrawals[i].Address[:], withdrawals[j].Address[:]) < 0
+		}
+		return withdrawals[i].Nonce < withdrawals[j].Nonce
+	})
+	return withdrawals, nil
+}
+
+// depositContracts returns the deposit contracts configured for the chain
+func (s *StakingAPI) depositContracts() map[types.Address]bool {
+	contracts := make(map[types.Address]bool)
+	config := s.api.GetChainConfig()
+	if config == nil || config.Apos == nil {
+		return contracts
+	}
+	for _, hex := range []string{config.Apos.DepositContract, config.Apos.DepositNFTContract, config.Apos.DepositFUJIContract} {
+		if types.IsHexAddress(hex) {
+			contracts[types.HexToAddress(hex)] = true
+		}
+	}
+	return contracts
+}
+
+// DoubleSigns returns the evidence of verifiers that signed two different
+// state roots for a block number in [from, to], ordered by block number.
+// If address is given only the evidence against it is returned.
+func (s *StakingAPI) DoubleSigns(ctx context.Context, from, to hexutil.Uint64, address *types.Address) ([]*rawdb.DoubleSignEvidence, error) {
+	var evs []*rawdb.DoubleSignEvidence
+	err := s.api.Database().View(ctx, func(tx kv.Tx) (err error) {
+		evs, err = rawdb.ReadDoubleSignEvidences(tx, uint64(from), uint64(to))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return evs, nil
+	}
+	filtered := make([]*rawdb.DoubleSignEvidence, 0)
+	for _, ev := range evs {
+		if ev.Address == *address {
+			filtered = append(filtered, ev)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/params"
+)
+
+var (
+	stakingContract = types.HexToAddress("0x00000000000000000000000000000000000000d0")
+	stakingUser     = types.HexToAddress("0x1111111111111111111111111111111111111111")
+)
+
+// stakingTxsPool serves a fixed pool content
+type stakingTxsPool struct {
+	common.ITxsPool
+	pending, queued map[types.Address][]*transaction.Transaction
+}
+
+func (p *stakingTxsPool) Content() (map[types.Address][]*transaction.Transaction, map[types.Address][]*transaction.Transaction) {
+	return p.pending, p.queued
+}
+
+func newStakingAPI(t *testing.T, pool common.ITxsPool) *StakingAPI {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+
+	secret, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pub types.PublicKey
+	copy(pub[:], secret.PublicKey().Marshal())
+	amount := new(uint256.Int).Mul(uint256.NewInt(100), uint256.NewInt(params.N))
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		if err := rawdb.PutDeposit(tx, stakingUser, pub, *amount); err != nil {
+			return err
+		}
+		return rawdb.PutAccountReward(tx, stakingUser, uint256.NewInt(42))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &params.ChainConfig{Apos: &params.APosConfig{DepositContract: stakingContract.Hex()}}
+	return NewStakingAPI(NewAPI(nil, db, nil, pool, nil, config))
+}
+
+func TestStakingGetValidator(t *testing.T) {
+	s := newStakingAPI(t, nil)
+	ctx := context.Background()
+
+	v, err := s.GetValidator(ctx, stakingUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(uint256.Int).Mul(uint256.NewInt(100), uint256.NewInt(params.N)); !v.DepositAmount.Eq(want) {
+		t.Errorf("deposit amount = %v, want %v", v.DepositAmount, want)
+	}
+	if v.RewardPerBlock == nil || v.RewardPerBlock.IsZero() {
+		t.Error("missing reward per block")
+	}
+	if v.RewardUnpaid.Uint64() != 42 {
+		t.Errorf("unpaid reward = %v, want 42", v.RewardUnpaid)
+	}
+
+	other := types.HexToAddress("0x2222222222222222222222222222222222222222")
+	if _, err := s.GetValidator(ctx, other); !errors.Is(err, errNotValidator) {
+		t.Errorf("expected %v, got %v", errNotValidator, err)
+	}
+}
+
+func TestStakingPendingWithdrawals(t *testing.T) {
+	withdraw := transaction.NewTransaction(3, stakingUser, &stakingContract, uint256.NewInt(0), 50000, uint256.NewInt(1), withdrawSelector)
+	transfer := transaction.NewTransaction(4, stakingUser, &stakingContract, uint256.NewInt(1), 21000, uint256.NewInt(1), nil)
+	pool := &stakingTxsPool{
+		pending: map[types.Address][]*transaction.Transaction{stakingUser: {withdraw}},
+		queued:  map[types.Address][]*transaction.Transaction{stakingUser: {transfer}},
+	}
+	s := newStakingAPI(t, pool)
+
+	withdrawals, err := s.PendingWithdrawals(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdrawals) != 1 {
+		t.Fatalf("got %d withdrawals, want 1", len(withdrawals))
+	}
+	w := withdrawals[0]
+	if w.Address != stakingUser || w.Contract != stakingContract || w.Nonce != 3 || w.Queued {
+		t.Errorf("unexpected withdrawal %+v", w)
+	}
+	if w.DepositAmount == nil || w.RewardUnpaid.Uint64() != 42 {
+		t.Errorf("missing deposit or reward in %+v", w)
+	}
+}
@@ -54,6 +54,16 @@ type TransactionArgs struct {
 	ChainID    *hexutil.Big          `json:"chainId,omitempty"`
 }
 
+// RPCAuthorization is the RPC representation of an EIP-7702 authorization
+type RPCAuthorization struct {
+	ChainID hexutil.Uint64    `json:"chainId"`
+	Address avmcommon.Address `json:"address"`
+	Nonce   hexutil.Uint64    `json:"nonce"`
+	YParity hexutil.Uint64    `json:"yParity"`
+	R       *hexutil.Big      `json:"r"`
+	S       *hexutil.Big      `json:"s"`
+}
+
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
 	BlockHash        *avmcommon.Hash      `json:"blockHash"`
@@ -72,9 +82,13 @@ type RPCTransaction struct {
 	Type             hexutil.Uint64        `json:"type"`
 	Accesses         *avmtypes.AccessList `json:"accessList,omitempty"`
 	ChainID          *hexutil.Big          `json:"chainId,omitempty"`
+	BlobFeeCap       *hexutil.Big          `json:"maxFeePerBlobGas,omitempty"`
+	BlobHashes       []avmcommon.Hash      `json:"blobVersionedHashes,omitempty"`
+	AuthList         []RPCAuthorization    `json:"authorizationList,omitempty"`
 	V                *hexutil.Big          `json:"v"`
 	R                *hexutil.Big          `json:"r"`
 	S                *hexutil.Big          `json:"s"`
+	YParity          *hexutil.Uint64       `json:"yParity,omitempty"`
 }
 
 // from retrieves the transaction sender address.
@@ -132,7 +146,7 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, api *API) error {
 		}
 		pendingBlockNr := jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.PendingBlockNumber)
 		//todo gasCap
-		estimated, err := DoEstimateGas(ctx, api, callArgs, pendingBlockNr, 50000000)
+		estimated, err := DoEstimateGas(ctx, api, callArgs, pendingBlockNr, nil, 50000000)
 		if err != nil {
 			return err
 		}
@@ -315,8 +329,19 @@ func (args *TransactionArgs) toTransaction() *transaction.Transaction {
 // newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
 func newRPCPendingTransaction(tx *transaction.Transaction, current block.IHeader) *RPCTransaction {
 	blockNumber := uint64(0)
-	//todo baseFee
-	return newRPCTransaction(tx, types.Hash{}, blockNumber, 0, big.NewInt(baseFee))
+	return newRPCTransaction(tx, types.Hash{}, blockNumber, 0, current.BaseFee64().ToBig())
+}
+
+// rpcSender returns the sender of tx, recovering it from the signature when
+// the transaction does not carry one. The zero address is returned for
+// transactions whose sender cannot be recovered.
+func rpcSender(tx *transaction.Transaction) types.Address {
+	var chainID *big.Int
+	if id := tx.ChainId(); id != nil && !id.IsZero() {
+		chainID = id.ToBig()
+	}
+	from, _ := tx.Sender(transaction.LatestSignerForChainID(chainID))
+	return from
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -324,11 +349,11 @@ func newRPCPendingTransaction(tx *transaction.Transaction, current block.IHeader
 func newRPCTransaction(tx *transaction.Transaction, blockHash types.Hash, blockNumber uint64, index uint64, baseFee *big.Int) *RPCTransaction {
 
 	v, r, s := tx.RawSignatureValues()
-	from := tx.From()
+	from := rpcSender(tx)
 	hash := tx.Hash()
 	result := &RPCTransaction{
 		Type:     hexutil.Uint64(tx.Type()),
-		From:     *avmtypes.FromastAddress(from),
+		From:     *avmtypes.FromastAddress(&from),
 		Gas:      hexutil.Uint64(tx.Gas()),
 		GasPrice: (*hexutil.Big)(tx.GasPrice().ToBig()),
 		Hash:     avmtypes.FromastHash(hash),
@@ -346,6 +371,19 @@ func newRPCTransaction(tx *transaction.Transaction, blockHash types.Hash, blockN
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
 		result.TransactionIndex = (*hexutil.Uint64)(&index)
 	}
+	// Typed transactions always carry an access list, possibly empty, and
+	// report the signature parity as yParity next to v.
+	if tx.Type() != transaction.LegacyTxType {
+		al := avmtypes.FromastAccessList(tx.AccessList())
+		if al == nil {
+			al = avmtypes.AccessList{}
+		}
+		result.Accesses = &al
+		if v != nil {
+			yParity := hexutil.Uint64(v.Uint64())
+			result.YParity = &yParity
+		}
+	}
 	switch tx.Type() {
 	case transaction.LegacyTxType:
 		// if a legacy transaction has an EIP-155 chain id, include it explicitly
@@ -353,15 +391,28 @@ func newRPCTransaction(tx *transaction.Transaction, blockHash types.Hash, blockN
 			result.ChainID = (*hexutil.Big)(id.ToBig())
 		}
 	case transaction.AccessListTxType:
-		// todo copy al
-		//al := tx.AccessList()
-		//result.Accesses = &al
 		result.ChainID = (*hexutil.Big)(tx.ChainId().ToBig())
-	case transaction.DynamicFeeTxType:
-		// todo copy al
-		//al := tx.AccessList()
-		//result.Accesses = &al
+	case transaction.DynamicFeeTxType, transaction.BlobTxType, transaction.SetCodeTxType:
 		result.ChainID = (*hexutil.Big)(tx.ChainId().ToBig())
+		if tx.Type() == transaction.BlobTxType {
+			result.BlobFeeCap = (*hexutil.Big)(tx.BlobFeeCap().ToBig())
+			for _, h := range tx.BlobHashes() {
+				result.BlobHashes = append(result.BlobHashes, avmtypes.FromastHash(h))
+			}
+		}
+		for _, auth := range tx.AuthList() {
+			rpcAuth := RPCAuthorization{
+				ChainID: hexutil.Uint64(auth.ChainID),
+				Address: *avmtypes.FromastAddress(&auth.Address),
+				Nonce:   hexutil.Uint64(auth.Nonce),
+			}
+			if auth.V != nil && auth.R != nil && auth.S != nil {
+				rpcAuth.YParity = hexutil.Uint64(auth.V.Uint64())
+				rpcAuth.R = (*hexutil.Big)(auth.R.ToBig())
+				rpcAuth.S = (*hexutil.Big)(auth.S.ToBig())
+			}
+			result.AuthList = append(result.AuthList, rpcAuth)
+		}
 		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap().ToBig())
 		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap().ToBig())
 		// if the transaction has been mined, compute the effective gas price
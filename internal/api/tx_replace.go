@@ -0,0 +1,127 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common"
+	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
+	avmcommon "github.com/n42blockchain/N42/common/avmutil"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// resendPriceBump is the fee increase in percent Resend applies when no gas
+// price is given. It matches the default --txpool.pricebump.
+const resendPriceBump = 10
+
+// findPoolTx returns the pending or queued transaction of from with nonce, or
+// nil if the pool has none.
+func findPoolTx(pool common.ITxsPool, from types.Address, nonce uint64) *transaction.Transaction {
+	pending, queued := pool.Content()
+	for _, txs := range [][]*transaction.Transaction{pending[from], queued[from]} {
+		for _, tx := range txs {
+			if tx.Nonce() == nonce {
+				return tx
+			}
+		}
+	}
+	return nil
+}
+
+// resendArgs rebuilds the arguments of tx with its fees replaced by gasPrice,
+// or raised by resendPriceBump percent without one, and its gas limit replaced
+// by gasLimit if given.
+func resendArgs(tx *transaction.Transaction, chainID *big.Int, gasPrice *hexutil.Big, gasLimit *hexutil.Uint64) (*TransactionArgs, error) {
+	var (
+		data  = hexutil.Bytes(tx.Data())
+		nonce = hexutil.Uint64(tx.Nonce())
+		gas   = hexutil.Uint64(tx.Gas())
+	)
+	if gasLimit != nil && *gasLimit != 0 {
+		gas = *gasLimit
+	}
+	if gasPrice != nil && gasPrice.ToInt().Sign() == 0 {
+		gasPrice = nil
+	}
+	args := &TransactionArgs{
+		From:    avmtypes.FromastAddress(tx.From()),
+		To:      avmtypes.FromastAddress(tx.To()),
+		Gas:     &gas,
+		Value:   (*hexutil.Big)(tx.Value().ToBig()),
+		Nonce:   &nonce,
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() != transaction.LegacyTxType {
+		al := avmtypes.FromastAccessList(tx.AccessList())
+		args.AccessList = &al
+	}
+
+	switch tx.Type() {
+	case transaction.LegacyTxType, transaction.AccessListTxType:
+		price := bumpFee(tx.GasPrice())
+		if gasPrice != nil {
+			price = gasPrice.ToInt()
+		}
+		args.GasPrice = (*hexutil.Big)(price)
+	case transaction.DynamicFeeTxType:
+		feeCap, tip := bumpFee(tx.GasFeeCap()), bumpFee(tx.GasTipCap())
+		if gasPrice != nil {
+			feeCap = gasPrice.ToInt()
+			if tip.Cmp(feeCap) > 0 {
+				tip = feeCap
+			}
+		}
+		args.MaxFeePerGas, args.MaxPriorityFeePerGas = (*hexutil.Big)(feeCap), (*hexutil.Big)(tip)
+	default:
+		return nil, fmt.Errorf("resending transactions of type %d is not supported", tx.Type())
+	}
+	return args, nil
+}
+
+// bumpFee raises fee by resendPriceBump percent.
+func bumpFee(fee *uint256.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee.ToBig(), big.NewInt(100+resendPriceBump))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// TxsPoolAdminAPI offers the operator methods of the transaction pool. It is
+// only served behind authentication.
+type TxsPoolAdminAPI struct {
+	api *API
+}
+
+// NewTxsPoolAdminAPI creates a new transaction pool service for node operators.
+func NewTxsPoolAdminAPI(api *API) *TxsPoolAdminAPI {
+	return &TxsPoolAdminAPI{api}
+}
+
+// Drop evicts the local transaction hash from the pool, e.g. a transaction
+// stuck because of a too low price. Later transactions of the sender wait in
+// the queue until the nonce is used again.
+func (s *TxsPoolAdminAPI) Drop(hash avmcommon.Hash) (bool, error) {
+	if err := s.api.TxsPool().DropLocal(avmtypes.ToastHash(hash)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+func TestResendArgs(t *testing.T) {
+	from, to := types.Address{0x01}, types.Address{0x02}
+	chainID := big.NewInt(7)
+	legacy := transaction.NewTx(&transaction.LegacyTx{
+		Nonce:    3,
+		GasPrice: uint256.NewInt(1000),
+		Gas:      21000,
+		To:       &to,
+		From:     &from,
+		Value:    uint256.NewInt(5),
+		Data:     []byte{0xca, 0xfe},
+	})
+
+	// Without a gas price the fees are bumped
+	args, err := resendArgs(legacy, chainID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := args.toTransaction()
+	if tx.Type() != transaction.LegacyTxType || tx.Nonce() != 3 || tx.Gas() != 21000 || *tx.To() != to {
+		t.Fatalf("unexpected replacement %+v", args)
+	}
+	if tx.GasPrice().Uint64() != 1100 || tx.Value().Uint64() != 5 || string(tx.Data()) != "\xca\xfe" {
+		t.Errorf("got price %v, value %v and data %x", tx.GasPrice(), tx.Value(), tx.Data())
+	}
+
+	// Explicit values win, a zero gas price counts as unset
+	price, limit := hexutil.Big(*big.NewInt(5000)), hexutil.Uint64(30000)
+	if args, err = resendArgs(legacy, chainID, &price, &limit); err != nil {
+		t.Fatal(err)
+	}
+	if tx := args.toTransaction(); tx.GasPrice().Uint64() != 5000 || tx.Gas() != 30000 {
+		t.Errorf("got price %v and gas %d, want 5000 and 30000", tx.GasPrice(), tx.Gas())
+	}
+	zero := hexutil.Big{}
+	if args, err = resendArgs(legacy, chainID, &zero, nil); err != nil || args.GasPrice.ToInt().Uint64() != 1100 {
+		t.Errorf("zero gas price: got %v, %v", args.GasPrice, err)
+	}
+
+	dynamic := transaction.NewTx(&transaction.DynamicFeeTx{
+		ChainID:   uint256.NewInt(7),
+		Nonce:     4,
+		GasTipCap: uint256.NewInt(100),
+		GasFeeCap: uint256.NewInt(2000),
+		Gas:       50000,
+		To:        &to,
+		From:      &from,
+		Value:     uint256.NewInt(0),
+	})
+	if args, err = resendArgs(dynamic, chainID, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if tx := args.toTransaction(); tx.Type() != transaction.DynamicFeeTxType || tx.GasFeeCap().Uint64() != 2200 || tx.GasTipCap().Uint64() != 110 {
+		t.Errorf("got type %d, fee cap %v and tip %v", tx.Type(), tx.GasFeeCap(), tx.GasTipCap())
+	}
+
+	// The tip is capped by an explicit fee cap
+	price = hexutil.Big(*big.NewInt(50))
+	if args, err = resendArgs(dynamic, chainID, &price, nil); err != nil {
+		t.Fatal(err)
+	}
+	if tx := args.toTransaction(); tx.GasFeeCap().Uint64() != 50 || tx.GasTipCap().Uint64() != 50 {
+		t.Errorf("got fee cap %v and tip %v, want 50 and 50", tx.GasFeeCap(), tx.GasTipCap())
+	}
+}
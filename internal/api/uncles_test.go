@@ -0,0 +1,80 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common"
+	avmtypes "github.com/n42blockchain/N42/common/avmtypes"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+)
+
+// uncleChain knows a single block
+type uncleChain struct {
+	common.IBlockChain
+	blk block.IBlock
+}
+
+func (c *uncleChain) CurrentBlock() block.IBlock { return c.blk }
+func (c *uncleChain) GetBlockByHashContext(_ context.Context, h types.Hash) (block.IBlock, error) {
+	if h == c.blk.Hash() {
+		return c.blk, nil
+	}
+	return nil, errors.New("block does not exist")
+}
+func (c *uncleChain) GetBlockByNumberContext(_ context.Context, number *uint256.Int) (block.IBlock, error) {
+	if number.Eq(c.blk.Number64()) {
+		return c.blk, nil
+	}
+	return nil, nil
+}
+
+func TestUncleMethods(t *testing.T) {
+	blk := block.NewBlock(&block.Header{Number: uint256.NewInt(5), Difficulty: uint256.NewInt(1)}, nil)
+	s := NewBlockChainAPI(NewAPI(&uncleChain{blk: blk}, nil, nil, nil, nil, nil))
+	ctx := context.Background()
+	known, unknown := avmtypes.FromastHash(blk.Hash()), avmtypes.FromastHash(types.Hash{0x0a})
+
+	if n := s.GetUncleCountByBlockHash(ctx, known); n == nil || *n != 0 {
+		t.Errorf("count of a known block = %v, want 0", n)
+	}
+	if n := s.GetUncleCountByBlockHash(ctx, unknown); n != nil {
+		t.Errorf("count of an unknown block = %v, want null", *n)
+	}
+	for _, nr := range []jsonrpc.BlockNumber{5, jsonrpc.LatestBlockNumber} {
+		if n, err := s.GetUncleCountByBlockNumber(ctx, nr); err != nil || n == nil || *n != 0 {
+			t.Errorf("count of block %d = %v, %v, want 0", nr, n, err)
+		}
+	}
+	if n, err := s.GetUncleCountByBlockNumber(ctx, 6); err != nil || n != nil {
+		t.Errorf("count of a missing block = %v, %v, want null", n, err)
+	}
+
+	if u, err := s.GetUncleByBlockHashAndIndex(ctx, known, 0); err != nil || u != nil {
+		t.Errorf("uncle of a known block = %v, %v, want null", u, err)
+	}
+	if u, err := s.GetUncleByBlockNumberAndIndex(ctx, 5, 0); err != nil || u != nil {
+		t.Errorf("uncle by number = %v, %v, want null", u, err)
+	}
+}
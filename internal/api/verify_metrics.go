@@ -0,0 +1,103 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+)
+
+// Timings of the verification round: the mined block is broadcast to the
+// verifiers, which re-execute it and send back their signature over the
+// state root until the sealer has enough of them to aggregate.
+var (
+	verifyFirstSignTimer = prometheus.GetOrCreateHistogram("consensus_verify_first_sign_seconds")
+	verifySignTimer      = prometheus.GetOrCreateHistogram("consensus_verify_sign_seconds")
+	verifyQuorumTimer    = prometheus.GetOrCreateHistogram("consensus_verify_quorum_seconds")
+	signMergeTimer       = prometheus.GetOrCreateHistogram("consensus_sign_merge_seconds")
+
+	verifySignsAccepted  = prometheus.GetOrCreateCounter(`consensus_verify_signs_total{result="accepted"}`)
+	verifySignsStale     = prometheus.GetOrCreateCounter(`consensus_verify_signs_total{result="stale"}`)
+	verifySignsDuplicate = prometheus.GetOrCreateCounter(`consensus_verify_signs_total{result="duplicate"}`)
+	verifySignsInvalid   = prometheus.GetOrCreateCounter(`consensus_verify_signs_total{result="invalid"}`)
+	verifyDoubleSigns    = prometheus.GetOrCreateCounter(`consensus_verify_signs_total{result="double"}`)
+
+	verifyRoundsOK           = prometheus.GetOrCreateCounter(`consensus_verify_rounds_total{result="ok"}`)
+	verifyRoundsInsufficient = prometheus.GetOrCreateCounter(`consensus_verify_rounds_total{result="insufficient"}`)
+	verifyRoundSigns         = prometheus.GetOrCreateCounter("consensus_verify_round_signs", true)
+)
+
+// maxTrackedRounds bounds the broadcast times kept for rounds which never
+// reach the sealer, e.g. because the block lost the race.
+const maxTrackedRounds = 64
+
+var verifyRounds = newRoundTracker(maxTrackedRounds)
+
+// MarkVerifyBroadcast records that the block with the given number has been
+// handed to the verifiers. Signature timings are measured from this moment.
+func MarkVerifyBroadcast(number uint64) {
+	verifyRounds.start(number, time.Now())
+}
+
+// roundTracker remembers when each verification round started.
+type roundTracker struct {
+	mu     sync.Mutex
+	limit  int
+	starts map[uint64]time.Time
+}
+
+func newRoundTracker(limit int) *roundTracker {
+	return &roundTracker{limit: limit, starts: make(map[uint64]time.Time)}
+}
+
+func (r *roundTracker) start(number uint64, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.starts[number] = t
+	if len(r.starts) <= r.limit {
+		return
+	}
+	oldest := number
+	for n := range r.starts {
+		if n < oldest {
+			oldest = n
+		}
+	}
+	delete(r.starts, oldest)
+}
+
+// since returns the time elapsed since the round of number started.
+func (r *roundTracker) since(number uint64, now time.Time) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.starts[number]
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(t), true
+}
+
+// finish forgets the round of number.
+func (r *roundTracker) finish(number uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.starts, number)
+}
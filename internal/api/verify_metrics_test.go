@@ -0,0 +1,47 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundTracker(t *testing.T) {
+	r := newRoundTracker(2)
+	base := time.Unix(1000, 0)
+
+	r.start(1, base)
+	r.start(2, base.Add(time.Second))
+	if d, ok := r.since(1, base.Add(3*time.Second)); !ok || d != 3*time.Second {
+		t.Errorf("since(1) = %v, %v; want 3s, true", d, ok)
+	}
+
+	// A third round evicts the oldest one.
+	r.start(3, base.Add(2*time.Second))
+	if _, ok := r.since(1, base); ok {
+		t.Errorf("round 1 should have been evicted")
+	}
+	if _, ok := r.since(2, base); !ok {
+		t.Errorf("round 2 should still be tracked")
+	}
+
+	r.finish(2)
+	if _, ok := r.since(2, base); ok {
+		t.Errorf("round 2 should be forgotten after finish")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package backfill
+
+import (
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+const addressBloomName = "addressbloom"
+
+// addressBloomIndex records the addresses active in each block range, see
+// rawdb.AddressActivity.
+type addressBloomIndex struct{}
+
+func (addressBloomIndex) Name() string { return addressBloomName }
+
+func (addressBloomIndex) IndexBlock(tx kv.RwTx, blk *block.Block) error {
+	receipts := rawdb.ReadRawReceipts(tx, blk.Number64().Uint64())
+	return rawdb.WriteAddressActivity(tx, blk.Number64().Uint64(), rawdb.BlockAddresses(blk, receipts))
+}
@@ -0,0 +1,233 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package backfill builds database indexes for blocks that were written
+// before the index was enabled, in the background and resumably.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+const (
+	// batchSize is the number of blocks indexed per database transaction.
+	batchSize = 1000
+	// batchPause leaves room for block imports between two batches.
+	batchPause = 10 * time.Millisecond
+	// syncPollInterval is how often a paused backfill checks whether the
+	// initial sync has finished.
+	syncPollInterval = 10 * time.Second
+	logInterval      = 30 * time.Second
+)
+
+// Index is a per-block index that new blocks maintain on import and which
+// can be built for older blocks one block at a time.
+type Index interface {
+	// Name identifies the index and its stored progress.
+	Name() string
+	// IndexBlock writes the index entries of blk.
+	IndexBlock(tx kv.RwTx, blk *block.Block) error
+}
+
+// indexes holds the indexes that can be rebuilt on request, by name.
+var indexes = map[string]func() Index{
+	txLookupName:     func() Index { return txLookupIndex{} },
+	addressBloomName: func() Index { return addressBloomIndex{} },
+}
+
+// Lookup returns the named index, or an error listing the known ones.
+func Lookup(name string) (Index, error) {
+	newIndex, ok := indexes[name]
+	if !ok {
+		names := make([]string, 0, len(indexes))
+		for n := range indexes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown index %q, available: %v", name, names)
+	}
+	return newIndex(), nil
+}
+
+// Service runs the backfill of every registered index. Blocks imported while
+// it runs are indexed on import, so only blocks up to the head at the time
+// an index was enabled are backfilled.
+type Service struct {
+	db      kv.RwDB
+	head    func() uint64
+	synced  func() bool
+	indexes []Index
+
+	mu       sync.RWMutex
+	progress map[string]*rawdb.BackfillProgress
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService creates a backfill service. head returns the current block
+// number and synced reports whether the initial sync has finished; the
+// backfill waits for it so both do not compete for the database.
+func NewService(ctx context.Context, db kv.RwDB, head func() uint64, synced func() bool, indexes ...Index) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		db:       db,
+		head:     head,
+		synced:   synced,
+		indexes:  indexes,
+		progress: make(map[string]*rawdb.BackfillProgress),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start loads or creates the progress of every index and starts the
+// backfills that are not complete yet.
+func (s *Service) Start() error {
+	for _, index := range s.indexes {
+		p, err := s.loadProgress(index.Name())
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.progress[index.Name()] = p
+		s.mu.Unlock()
+		if p.Done() {
+			continue
+		}
+		log.Info("Backfilling index", "index", p.Name, "from", p.Next, "to", p.Target)
+		s.wg.Add(1)
+		go s.run(index)
+	}
+	return nil
+}
+
+// Stop interrupts the running backfills. Committed batches are kept and the
+// backfills resume from there on the next start.
+func (s *Service) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// Progress returns the progress of every index, ordered by name.
+func (s *Service) Progress() []rawdb.BackfillProgress {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	progress := make([]rawdb.BackfillProgress, 0, len(s.progress))
+	for _, p := range s.progress {
+		progress = append(progress, *p)
+	}
+	sort.Slice(progress, func(i, j int) bool { return progress[i].Name < progress[j].Name })
+	return progress
+}
+
+func (s *Service) loadProgress(name string) (*rawdb.BackfillProgress, error) {
+	var p *rawdb.BackfillProgress
+	err := s.db.Update(s.ctx, func(tx kv.RwTx) (err error) {
+		if p, err = rawdb.ReadBackfillProgress(tx, name); err != nil || p != nil {
+			return err
+		}
+		// The genesis block carries no index entries.
+		p = &rawdb.BackfillProgress{Name: name, Next: 1, Target: s.head(), Started: uint64(time.Now().Unix())}
+		return rawdb.WriteBackfillProgress(tx, p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s backfill progress: %w", name, err)
+	}
+	return p, nil
+}
+
+func (s *Service) run(index Index) {
+	defer s.wg.Done()
+
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+	for {
+		if !s.synced() {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(syncPollInterval):
+				continue
+			}
+		}
+		done, err := s.step(index)
+		if err != nil {
+			log.Error("Backfill failed", "index", index.Name(), "err", err)
+			return
+		}
+		p := s.current(index.Name())
+		if done {
+			log.Info("Backfill complete", "index", p.Name, "blocks", p.Target)
+			return
+		}
+		select {
+		case <-s.ctx.Done():
+			log.Info("Backfill interrupted", "index", p.Name, "next", p.Next, "target", p.Target)
+			return
+		case <-logEvery.C:
+			log.Info("Backfilling index", "index", p.Name, "next", p.Next, "target", p.Target)
+		case <-time.After(batchPause):
+		}
+	}
+}
+
+// step indexes the next batch of blocks and commits it together with the
+// new progress. It reports whether the backfill is complete.
+func (s *Service) step(index Index) (bool, error) {
+	p := s.current(index.Name())
+	last := min(p.Next+batchSize-1, p.Target)
+	if err := s.db.Update(s.ctx, func(tx kv.RwTx) error {
+		for number := p.Next; number <= last; number++ {
+			blk, err := rawdb.ReadBlockByNumber(tx, number)
+			if err != nil {
+				return err
+			}
+			if blk == nil {
+				return fmt.Errorf("missing canonical block %d", number)
+			}
+			if err := index.IndexBlock(tx, blk); err != nil {
+				return fmt.Errorf("block %d: %w", number, err)
+			}
+		}
+		p.Next = last + 1
+		return rawdb.WriteBackfillProgress(tx, &p)
+	}); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	s.progress[p.Name] = &p
+	s.mu.Unlock()
+	return p.Done(), nil
+}
+
+func (s *Service) current(name string) rawdb.BackfillProgress {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return *s.progress[name]
+}
@@ -0,0 +1,137 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package backfill
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+type recordingIndex struct {
+	mu     sync.Mutex
+	blocks []uint64
+}
+
+func (r *recordingIndex) Name() string { return "recording" }
+
+func (r *recordingIndex) IndexBlock(tx kv.RwTx, blk *block.Block) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blocks = append(r.blocks, blk.Number64().Uint64())
+	return nil
+}
+
+func newTestChain(t *testing.T, head uint64) kv.RwDB {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := uint64(0); i <= head; i++ {
+			blk := block.NewBlock(&block.Header{Number: uint256.NewInt(i), Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0)}, nil).(*block.Block)
+			if err := rawdb.WriteBlock(tx, blk); err != nil {
+				return err
+			}
+			if err := rawdb.WriteCanonicalHash(tx, blk.Hash(), i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func runBackfill(t *testing.T, db kv.RwDB, head uint64, index Index) rawdb.BackfillProgress {
+	s := NewService(context.Background(), db, func() uint64 { return head }, func() bool { return true }, index)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		p := s.Progress()[0]
+		if p.Done() {
+			return p
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backfill did not finish: %+v", p)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	const head = batchSize + 500
+	db := newTestChain(t, head)
+	index := &recordingIndex{}
+
+	p := runBackfill(t, db, head, index)
+	if p.Target != head || p.Next != head+1 {
+		t.Fatalf("progress %+v, want target %d", p, head)
+	}
+	if len(index.blocks) != head {
+		t.Fatalf("indexed %d blocks, want %d", len(index.blocks), head)
+	}
+	for i, number := range index.blocks {
+		if number != uint64(i+1) {
+			t.Fatalf("block %d indexed at position %d", number, i)
+		}
+	}
+
+	// A finished backfill is not repeated, even if the chain has grown.
+	index.blocks = nil
+	runBackfill(t, db, head+10, index)
+	if len(index.blocks) != 0 {
+		t.Fatalf("completed backfill indexed %d blocks again", len(index.blocks))
+	}
+}
+
+func TestBackfillResume(t *testing.T) {
+	const head = 100
+	db := newTestChain(t, head)
+	index := &recordingIndex{}
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.WriteBackfillProgress(tx, &rawdb.BackfillProgress{Name: index.Name(), Next: 60, Target: 80})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	runBackfill(t, db, head, index)
+	if len(index.blocks) != 21 || index.blocks[0] != 60 || index.blocks[20] != 80 {
+		t.Fatalf("resumed backfill indexed %v, want 60..80", index.blocks)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if _, err := Lookup(txLookupName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lookup("nope"); err == nil {
+		t.Fatal("expected an error for an unknown index")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package backfill
+
+import (
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+const txLookupName = "txlookup"
+
+// txLookupIndex maps transaction hashes to the number of their block.
+type txLookupIndex struct{}
+
+func (txLookupIndex) Name() string { return txLookupName }
+
+func (txLookupIndex) IndexBlock(tx kv.RwTx, blk *block.Block) error {
+	rawdb.WriteTxLookupEntries(tx, blk)
+	return nil
+}
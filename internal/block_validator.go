@@ -18,15 +18,29 @@ package internal
 
 import (
 	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/holiman/uint256"
 	"github.com/n42blockchain/N42/common/block"
-	"github.com/n42blockchain/N42/common/crypto/bls"
-	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/crypto/kzg"
+	prometheus "github.com/n42blockchain/N42/common/metrics"
 	"github.com/n42blockchain/N42/common/transaction"
-	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal/consensus"
-	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/internal/consensus/misc"
 	"github.com/n42blockchain/N42/modules/state"
 	"github.com/n42blockchain/N42/params"
+	"google.golang.org/protobuf/proto"
+)
+
+// Blocks rejected by ValidateState, by the header field that did not match
+// the execution.
+var (
+	invalidGasUsedCounter     = prometheus.GetOrCreateCounter(`chain_validation_failures_total{type="gas_used"}`)
+	invalidBaseFeeCounter     = prometheus.GetOrCreateCounter(`chain_validation_failures_total{type="base_fee"}`)
+	invalidBloomCounter       = prometheus.GetOrCreateCounter(`chain_validation_failures_total{type="bloom"}`)
+	invalidReceiptRootCounter = prometheus.GetOrCreateCounter(`chain_validation_failures_total{type="receipt_root"}`)
+	invalidStateRootCounter   = prometheus.GetOrCreateCounter(`chain_validation_failures_total{type="state_root"}`)
 )
 
 // BlockValidator is responsible for validating block headers, uncles and
@@ -49,43 +63,39 @@ func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain, engin
 	return validator
 }
 
-// ValidateBody validates the given block's uncles and verifies the block
-// header's transaction and uncle roots. The headers are assumed to be already
-// validated at this point.
-func (v *BlockValidator) ValidateBody(b block.IBlock) error {
-	// Check Signature valid
-	vfs := b.Body().Verifier()
-	addrs := make([]types.Address, len(vfs))
-	ss := make([]bls.PublicKey, len(vfs))
-	for i, p := range vfs {
-		addrs[i] = p.Address
-		blsP, err := bls.PublicKeyFromBytes(p.PublicKey[:])
-		if nil != err {
-			return err
-		}
-		ss[i] = blsP
+// ValidateSeals verifies the aggregated verifier signatures of a batch of
+// blocks. If the consensus engine supports it, the whole batch is verified
+// at once instead of block by block.
+func (v *BlockValidator) ValidateSeals(blocks []block.IBlock) []error {
+	if bv, ok := v.engine.(consensus.SealBatchVerifier); ok {
+		return bv.VerifySeals(v.bc, blocks)
 	}
-
-	if v.config.IsBeijing(b.Number64().Uint64()) {
-		header := b.Header().(*block.Header)
-		sig, err := bls.SignatureFromBytes(header.Signature[:])
-		if nil != err {
-			return err
-		}
-		if !sig.FastAggregateVerify(ss, header.Root) {
-			log.Warn("AggSignature verify falied", "blockNr", b.Number64().Uint64(), "Signature", hexutil.Encode(header.Signature[:]), "Root", hexutil.Encode(header.Root[:]))
-			for i, addr := range addrs {
-				log.Warn("", "address", addr.String(), "publicKey", hexutil.Encode(ss[i].Marshal()))
-			}
-			return fmt.Errorf("AggSignature verify falied")
+	errs := make([]error, len(blocks))
+	for i, b := range blocks {
+		if v.config.IsBeijing(b.Number64().Uint64()) {
+			errs[i] = consensus.VerifyAggSignature(b)
 		}
 	}
+	return errs
+}
 
+// ValidateBody validates the given block's uncles and verifies the block
+// header's transaction and uncle roots. The headers and the aggregated
+// signature (see ValidateSeals) are assumed to be already validated at this
+// point.
+func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 	// Check whether the block's known, and if not, that it's linkable
 	if v.bc.HasBlockAndState(b.Hash(), b.Number64().Uint64()) {
 		return ErrKnownBlock
 	}
 
+	if err := v.validateLimits(b); err != nil {
+		return err
+	}
+	if err := v.validateBlobs(b); err != nil {
+		return err
+	}
+
 	if hash := DeriveSha(transaction.Transactions(b.Transactions())); hash != b.TxHash() {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, b.TxHash())
 	}
@@ -99,6 +109,65 @@ func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 	return nil
 }
 
+// validateLimits checks the block and its transactions against the size and
+// gas limits of the chain config once the limits fork is active.
+func (v *BlockValidator) validateLimits(b block.IBlock) error {
+	if !v.config.IsLimits(b.Number64().Uint64()) {
+		return nil
+	}
+	if limit := v.config.BlockGasLimit(); b.GasLimit() > limit {
+		return fmt.Errorf("%w: gas limit %d, max %d", ErrBlockGasLimitExceeded, b.GasLimit(), limit)
+	}
+	if b.GasUsed() > b.GasLimit() {
+		return fmt.Errorf("%w: gas used %d, gas limit %d", ErrBlockGasLimitExceeded, b.GasUsed(), b.GasLimit())
+	}
+	if size, limit := uint64(proto.Size(b.ToProtoMessage())), v.config.BlockSizeLimit(); size > limit {
+		return fmt.Errorf("%w: size %d, limit %d", ErrOversizedBlock, size, limit)
+	}
+
+	var (
+		txSizeLimit   = v.config.TxSizeLimit()
+		initCodeLimit = v.config.InitCodeSizeLimit()
+		shanghai      = v.config.IsShanghai(b.Number64().Uint64())
+	)
+	for i, tx := range b.Transactions() {
+		if tx.Gas() > b.GasLimit() {
+			return fmt.Errorf("%w: tx %d (%x) gas %d, gas limit %d", ErrBlockGasLimitExceeded, i, tx.Hash(), tx.Gas(), b.GasLimit())
+		}
+		if size := tx.Size(); size > txSizeLimit {
+			return fmt.Errorf("%w: tx %d (%x) size %d, limit %d", ErrOversizedTx, i, tx.Hash(), size, txSizeLimit)
+		}
+		if shanghai && tx.To() == nil && uint64(len(tx.Data())) > initCodeLimit {
+			return fmt.Errorf("%w: tx %d (%x) code size %d, limit %d", ErrMaxInitCodeSizeExceeded, i, tx.Hash(), len(tx.Data()), initCodeLimit)
+		}
+	}
+	return nil
+}
+
+// validateBlobs checks the EIP-4844 blob accounting of the block once Cancun
+// is active: blocks carry no sidecars and declare the blob gas of their
+// transactions. Blob transactions themselves are refused while the kzg
+// package cannot prove their sidecars.
+func (v *BlockValidator) validateBlobs(b block.IBlock) error {
+	if !v.config.IsCancun(b.Number64().Uint64()) {
+		return nil
+	}
+	var blobGasUsed uint64
+	for i, tx := range b.Transactions() {
+		if tx.Type() == transaction.BlobTxType && !kzg.Implemented {
+			return fmt.Errorf("%w: tx %d (%x) is a blob transaction", ErrTxTypeNotSupported, i, tx.Hash())
+		}
+		if tx.BlobTxSidecar() != nil {
+			return fmt.Errorf("tx %d (%x) includes its blob sidecar", i, tx.Hash())
+		}
+		blobGasUsed += tx.BlobGas()
+	}
+	if header, ok := b.Header().(*block.Header); ok && header.BlobGasUsed != blobGasUsed {
+		return fmt.Errorf("%w: have %d, want %d", ErrBlobGasUsedMismatch, header.BlobGasUsed, blobGasUsed)
+	}
+	return nil
+}
+
 // ValidateState validates the various changes that happen after a state
 // transition, such as amount of used gas, the receipt roots and the state root
 // itself. ValidateState returns a database batch if the validation was a success
@@ -106,30 +175,99 @@ func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 func (v *BlockValidator) ValidateState(iBlock block.IBlock, statedb *state.IntraBlockState, receipts block.Receipts, usedGas uint64) error {
 	header := iBlock.Header().(*block.Header)
 	if header.GasUsed != usedGas {
+		invalidGasUsedCounter.Inc()
 		return fmt.Errorf("invalid gas used (remote: %d local: %d)", header.GasUsed, usedGas)
 	}
+	if err := v.validateBaseFee(header); err != nil {
+		invalidBaseFeeCounter.Inc()
+		return err
+	}
 
 	rbloom := block.CreateBloom(receipts)
 	if rbloom != header.Bloom {
-		return fmt.Errorf("invalid bloom (remote: %x  local: %x)", header.Bloom, rbloom)
+		invalidBloomCounter.Inc()
+		return fmt.Errorf("invalid bloom (remote: %x local: %x): %s", header.Bloom, rbloom, bloomDiff(header.Bloom, receipts))
 	}
 
+	// The receipts themselves are listed by the bad block report
 	receiptSha := DeriveSha(receipts)
 	if receiptSha != header.ReceiptHash {
-		for i, tx := range iBlock.Body().Transactions() {
-			log.Warn("tx", "index", i, "from", tx.From(), "GasUsed", receipts[i].GasUsed)
-			for index2, l := range receipts[i].Logs {
-				log.Warn("tx logs", "index", index2, "address", l.Address, "topic", l.Topics[0], "data", hexutil.Encode(l.Data))
-			}
-
-		}
-		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", header.ReceiptHash, receiptSha)
+		invalidReceiptRootCounter.Inc()
+		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x, %d receipts, %d logs)", header.ReceiptHash, receiptSha, len(receipts), countLogs(receipts))
 	}
 	// Validate the state root against the received state root and throw
 	// an error if they don't match.
 	// TODO 替换 emptyroot
 	if root := statedb.IntermediateRoot(); header.StateRoot() != root {
+		invalidStateRootCounter.Inc()
 		return fmt.Errorf("invalid merkle root (remote: %x local: %x)", header.Root, root)
 	}
 	return nil
 }
+
+// bloomDiff describes how the bloom of the local receipts differs from the
+// remote one: the receipts whose logs set bits the remote bloom lacks, and
+// the bits of the remote bloom no local log sets.
+func bloomDiff(remote block.Bloom, receipts block.Receipts) string {
+	var (
+		local   block.Bloom
+		outside []string
+	)
+	for i, receipt := range receipts {
+		b := block.BytesToBloom(block.LogsBloom(receipt.Logs))
+		covered := true
+		for j := range b {
+			local[j] |= b[j]
+			if b[j]&^remote[j] != 0 {
+				covered = false
+			}
+		}
+		if !covered {
+			outside = append(outside, fmt.Sprintf("%d (tx %v)", i, receipt.TxHash))
+		}
+	}
+	missing := 0
+	for j := range remote {
+		missing += bits.OnesCount8(remote[j] &^ local[j])
+	}
+
+	var diff []string
+	if len(outside) > 0 {
+		diff = append(diff, "logs of receipts "+strings.Join(outside, ", ")+" are not in the remote bloom")
+	}
+	if missing > 0 {
+		diff = append(diff, fmt.Sprintf("%d bits of the remote bloom are set by no local log", missing))
+	}
+	return strings.Join(diff, "; ")
+}
+
+func countLogs(receipts block.Receipts) (n int) {
+	for _, receipt := range receipts {
+		n += len(receipt.Logs)
+	}
+	return n
+}
+
+// validateBaseFee checks the base fee of a London header against its parent.
+// Before London the header must not carry a base fee, which reads as zero.
+func (v *BlockValidator) validateBaseFee(header *block.Header) error {
+	number := header.Number.Uint64()
+	if !v.config.IsLondon(number) {
+		if header.BaseFee != nil && !header.BaseFee.IsZero() {
+			return fmt.Errorf("invalid base fee before London (remote: %v)", header.BaseFee)
+		}
+		return nil
+	}
+	if number == 0 {
+		return nil
+	}
+	parent := v.bc.GetHeader(header.ParentHash, uint256.NewInt(number-1))
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	want := misc.CalcBaseFee(v.config, parent.(*block.Header))
+	if header.BaseFee == nil || header.BaseFee.ToBig().Cmp(want) != 0 {
+		return fmt.Errorf("invalid base fee (remote: %v local: %v)", header.BaseFee, want)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/params"
+)
+
+func TestValidateStateReceipts(t *testing.T) {
+	v := NewBlockValidator(&params.ChainConfig{ChainID: big.NewInt(1)}, nil, nil)
+	receipts := block.Receipts{
+		{Status: block.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: types.Hash{1}},
+		{Status: block.ReceiptStatusSuccessful, CumulativeGasUsed: 42000, TxHash: types.Hash{2}, Logs: []*block.Log{
+			{Address: types.Address{0xaa}, Topics: []types.Hash{{0x2a}}},
+		}},
+	}
+	for _, r := range receipts {
+		r.Bloom = block.BytesToBloom(block.LogsBloom(r.Logs))
+	}
+	header := &block.Header{
+		Number:      uint256.NewInt(1),
+		GasUsed:     42000,
+		Bloom:       block.CreateBloom(receipts),
+		ReceiptHash: DeriveSha(receipts),
+	}
+
+	// A log missing from the header bloom names its receipt
+	bad := *header
+	bad.Bloom = block.Bloom{}
+	err := v.ValidateState(block.NewBlock(&bad, nil), nil, receipts, 42000)
+	if err == nil || !strings.Contains(err.Error(), "logs of receipts 1 (tx ") {
+		t.Errorf("empty bloom: have %v", err)
+	}
+
+	// A header bit no log sets is counted
+	bad = *header
+	bad.Bloom[0] |= 0x80
+	err = v.ValidateState(block.NewBlock(&bad, nil), nil, receipts, 42000)
+	if err == nil || !strings.Contains(err.Error(), "1 bits of the remote bloom are set by no local log") {
+		t.Errorf("extra bloom bit: have %v", err)
+	}
+
+	bad = *header
+	bad.ReceiptHash = types.Hash{}
+	err = v.ValidateState(block.NewBlock(&bad, nil), nil, receipts, 42000)
+	if err == nil || !strings.Contains(err.Error(), "invalid receipt root hash") {
+		t.Errorf("receipt root: have %v", err)
+	}
+
+	if err := v.ValidateState(block.NewBlock(header, nil), nil, receipts, 21000); err == nil || !strings.Contains(err.Error(), "invalid gas used") {
+		t.Errorf("gas used: have %v", err)
+	}
+}
+
+func TestValidateBlobsWithoutLimits(t *testing.T) {
+	v := NewBlockValidator(&params.ChainConfig{ChainID: big.NewInt(1), CancunBlock: big.NewInt(0)}, nil, nil)
+	header := &block.Header{Number: uint256.NewInt(1), GasLimit: 30_000_000, BlobGasUsed: params.BlobTxBlobGasPerBlob}
+
+	// The limits fork is off, so only the blob accounting catches the header
+	if err := v.validateLimits(block.NewBlock(header, nil)); err != nil {
+		t.Fatalf("limits: have %v", err)
+	}
+	if err := v.validateBlobs(block.NewBlock(header, nil)); !errors.Is(err, ErrBlobGasUsedMismatch) {
+		t.Errorf("blob gas used: have %v", err)
+	}
+
+	header.BlobGasUsed = 0
+	if err := v.validateBlobs(block.NewBlock(header, nil)); err != nil {
+		t.Errorf("no blobs: have %v", err)
+	}
+
+	// Without a KZG backend blob transactions are not accepted at all
+	tx := transaction.NewTx(&transaction.BlobTx{
+		ChainID:    uint256.NewInt(1),
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(1),
+		Gas:        21000,
+		To:         types.Address{1},
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: []types.Hash{{0x01}},
+	})
+	header.BlobGasUsed = tx.BlobGas()
+	if err := v.validateBlobs(block.NewBlock(header, []*transaction.Transaction{tx})); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Errorf("blob tx: have %v", err)
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -42,7 +41,6 @@ import (
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
 	"github.com/n42blockchain/N42/modules/rawdb"
 )
 
@@ -59,10 +57,12 @@ var (
 )
 var (
 	headBlockGauge       = prometheus.GetOrCreateCounter("chain_head_block", true)
-	blockInsertTimer     = prometheus.GetOrCreateHistogram("chain_inserts")
-	blockValidationTimer = prometheus.GetOrCreateHistogram("chain_validation")
-	blockExecutionTimer  = prometheus.GetOrCreateHistogram("chain_execution")
-	blockWriteTimer      = prometheus.GetOrCreateHistogram("chain_write")
+	finalizedBlockGauge  = prometheus.GetOrCreateCounter("chain_finalized_block", true)
+	blockInsertTimer     = prometheus.GetOrCreateHistogram("chain_insert_seconds")
+	blockValidationTimer = prometheus.GetOrCreateHistogram("chain_validation_seconds")
+	blockExecutionTimer  = prometheus.GetOrCreateHistogram("chain_execution_seconds")
+	blockWriteTimer      = prometheus.GetOrCreateHistogram("chain_write_seconds")
+	blockCommitWaitTimer = prometheus.GetOrCreateHistogram("chain_commit_wait_seconds")
 )
 
 type WriteStatus byte
@@ -76,7 +76,8 @@ const (
 const (
 	//maxTimeFutureBlocks
 	blockCacheLimit     = 1024
-	receiptsCacheLimit  = 32
+	receiptsCacheLimit  = 256
+	receiptsReadAhead   = 32
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 5 * 60 // 5 min
 
@@ -90,8 +91,6 @@ type BlockChain struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	genesisBlock block.IBlock
-	blocks       []block.IBlock
-	headers      []block.IHeader
 	currentBlock atomic.Pointer[block.Block]
 	//state        *statedb.StateDB
 	ChainDB kv.RwDB
@@ -101,8 +100,6 @@ type BlockChain struct {
 	latestBlockCh chan block.IBlock
 	lock          sync.Mutex
 
-	peers map[peer.ID]bool
-
 	chBlocks chan block.IBlock
 
 	p2p p2p.P2P
@@ -114,7 +111,7 @@ type BlockChain struct {
 	wg sync.WaitGroup //
 
 	procInterrupt int32 // insert chain
-	futureBlocks  *lru.Cache[types.Hash, *block.Block]
+	futureBlocks  *futureBlocks
 	receiptCache  *lru.Cache[types.Hash, []*block.Receipt]
 	blockCache    *lru.Cache[types.Hash, *block.Block]
 
@@ -124,6 +121,16 @@ type BlockChain struct {
 
 	forker    *ForkChoice
 	validator Validator
+
+	freezer         *rawdb.Freezer
+	freezeThreshold uint64
+	readOnly        bool
+	loopWg          sync.WaitGroup // background loops Close waits for
+
+	commits     atomic.Pointer[commitQueue] // blocks of the running import awaiting their commit
+	commitEvery int                         // blocks per import transaction
+	commitSize  int                         // bytes of state changes per import transaction, 0 for no limit
+	reorgs      *reorgGuard                 // reorg depth limit and operator overrides
 }
 
 type insertStats struct {
@@ -147,7 +154,6 @@ func NewBlockChain(ctx context.Context, genesisBlock block.IBlock, engine consen
 	})
 
 	blockCache, _ := lru.New[types.Hash, *block.Block](blockCacheLimit)
-	futureBlocks, _ := lru.New[types.Hash, *block.Block](maxFutureBlocks)
 	receiptsCache, _ := lru.New[types.Hash, []*block.Receipt](receiptsCacheLimit)
 	tdCache, _ := lru.New[types.Hash, *uint256.Int](tdCacheLimit)
 	numberCache, _ := lru.New[types.Hash, uint64](numberCacheLimit)
@@ -155,13 +161,11 @@ func NewBlockChain(ctx context.Context, genesisBlock block.IBlock, engine consen
 	bc := &BlockChain{
 		chainConfig:  config, // Chain & network configuration
 		genesisBlock: genesisBlock,
-		blocks:       []block.IBlock{},
 		//currentBlock:  current,
 		ChainDB:       db,
 		ctx:           c,
 		cancel:        cancel,
 		insertLock:    make(chan struct{}, 1),
-		peers:         make(map[peer.ID]bool),
 		chBlocks:      make(chan block.IBlock, 100),
 		errorCh:       make(chan error),
 		p2p:           p2p,
@@ -169,7 +173,8 @@ func NewBlockChain(ctx context.Context, genesisBlock block.IBlock, engine consen
 		engine:        engine,
 		blockCache:    blockCache,
 		tdCache:       tdCache,
-		futureBlocks:  futureBlocks,
+		futureBlocks:  newFutureBlocks(maxFutureBlocks, futureBlockMaxAge),
+		reorgs:        newReorgGuard(0),
 		receiptCache:  receiptsCache,
 
 		numberCache: numberCache,
@@ -186,7 +191,7 @@ func NewBlockChain(ctx context.Context, genesisBlock block.IBlock, engine consen
 	return bc, nil
 }
 
-// Config, CurrentBlock, Blocks - see blockchain_reader.go
+// Config, CurrentBlock - see blockchain_reader.go
 
 func (bc *BlockChain) InsertHeader(headers []block.IHeader) (int, error) {
 	// TODO: Implement header-only insertion for light client support
@@ -199,24 +204,14 @@ func (bc *BlockChain) Start() error {
 	bc.wg.Add(3)
 	go bc.runLoop()
 	go bc.updateFutureBlocksLoop()
-	return nil
-}
-
-func (bc *BlockChain) AddPeer(hash string, remoteBlock uint64, peerID peer.ID) error {
-	if bc.genesisBlock.Hash().String() != hash {
-		return fmt.Errorf("failed to addPeer, err: genesis block different")
+	if bc.freezer != nil {
+		bc.loopWg.Add(1)
+		go bc.freezeLoop()
 	}
-	if _, ok := bc.peers[peerID]; ok {
-		return fmt.Errorf("failed to addPeer, err: the peer already exists")
+	if bc.readOnly {
+		bc.loopWg.Add(1)
+		go bc.followHeadLoop()
 	}
-
-	log.Debugf("local heigth:%d --> remote height: %d", bc.CurrentBlock().Number64(), remoteBlock)
-
-	bc.peers[peerID] = true
-	//if remoteBlock > bc.currentBlock.Number64().Uint64() {
-	//	bc.syncChain(remoteBlock, peerID)
-	//}
-
 	return nil
 }
 
@@ -263,57 +258,61 @@ func (bc *BlockChain) runLoop() {
 	}
 }
 
-// updateFutureBlocksLoop
+// updateFutureBlocksLoop inserts queued blocks as soon as their parent is
+// written or their timestamp is reached, and drops the ones that waited too
+// long.
 func (bc *BlockChain) updateFutureBlocksLoop() {
-	futureTimer := time.NewTicker(2 * time.Second)
-	defer futureTimer.Stop()
 	defer bc.wg.Done()
+	due := time.NewTimer(time.Hour)
+	defer due.Stop()
+	sweep := time.NewTicker(futureBlockSweep)
+	defer sweep.Stop()
 	for {
 		select {
-		case <-futureTimer.C:
-			if bc.futureBlocks.Len() > 0 {
-				blocks := make([]block.IBlock, 0, bc.futureBlocks.Len())
-				for _, key := range bc.futureBlocks.Keys() {
-					if value, ok := bc.futureBlocks.Get(key); ok {
-						blocks = append(blocks, value)
-					}
-				}
-				sort.Slice(blocks, func(i, j int) bool {
-					return blocks[i].Number64().Cmp(blocks[j].Number64()) < 0
-				})
-
-				if blocks[0].Number64().Uint64() > bc.CurrentBlock().Number64().Uint64()+1 {
-					continue
-				}
+		case <-bc.futureBlocks.wake:
+		case <-due.C:
+		case <-sweep.C:
+			bc.futureBlocks.expire(time.Now())
+		case <-bc.ctx.Done():
+			return
+		}
+		bc.procFutureBlocks()
 
-				if n, err := bc.InsertChain(blocks); nil != err {
-					log.Warn("insert future block failed", err)
-				} else {
-					for _, k := range bc.futureBlocks.Keys() {
-						bc.futureBlocks.Remove(k)
-					}
-					log.Infof("insert %d future block success, for %d to %d", n, blocks[0].Number64().Uint64(), blocks[n-1].Number64().Uint64())
-				}
+		next := time.Hour
+		if t, ok := bc.futureBlocks.nextDue(); ok {
+			next = max(time.Until(t), 0)
+		}
+		due.Reset(next)
+	}
+}
 
+// procFutureBlocks inserts the queued descendants of written parents and of
+// due blocks whose parent is known.
+func (bc *BlockChain) procFutureBlocks() {
+	for _, blk := range bc.futureBlocks.takeDue(time.Now()) {
+		if bc.HasBlock(blk.ParentHash(), blk.Number64().Uint64()-1) {
+			bc.futureBlocks.parentKnown(blk.ParentHash())
+		}
+	}
+	for _, parent := range bc.futureBlocks.takeReady() {
+		for _, chain := range bc.futureBlocks.take(parent) {
+			n, err := bc.InsertChain(chain)
+			futureInsertedMeter.Add(n)
+			if err != nil {
+				log.Warn("Failed to insert future blocks", "number", chain[0].Number64(), "hash", chain[0].Hash(), "inserted", n, "err", err)
+				continue
 			}
-		case <-bc.ctx.Done():
-			return
+			log.Debug("Inserted future blocks", "from", chain[0].Number64(), "to", chain[len(chain)-1].Number64(), "blocks", len(chain))
 		}
 	}
 }
 
 func (bc *BlockChain) runNewBlockMessage() {
-	newBlockCh := make(chan *msg_proto.NewBlockMessageData, 10)
-	sub := event.GlobalEvent.Subscribe(newBlockCh)
-	defer sub.Unsubscribe()
 	db := bc.ChainDB
 	for {
 		select {
 		case <-bc.ctx.Done():
 			return
-		case err := <-sub.Err():
-			log.Errorf("failed subscribe new block at blockchain err :%v", err)
-			return
 		case blk, ok := <-bc.chBlocks:
 			if ok {
 
@@ -327,18 +326,6 @@ func (bc *BlockChain) runNewBlockMessage() {
 					return nil
 				})
 			}
-		case msg, ok := <-newBlockCh:
-			if ok {
-				blk := block.Block{}
-				if err := blk.FromProtoMessage(msg.Block); err == nil {
-					_ = db.Update(bc.ctx, func(tx kv.RwTx) error {
-						rawdb.WriteBlock(tx, &blk)
-						rawdb.WriteHeadBlockHash(tx, blk.Hash())
-						_ = rawdb.ReadCurrentBlock(tx)
-						return nil
-					})
-				}
-			}
 		}
 	}
 }
@@ -412,6 +399,9 @@ func (bc *BlockChain) InsertChain(chain []block.IBlock) (int, error) {
 	if len(chain) == 0 {
 		return 0, nil
 	}
+	if bc.readOnly {
+		return 0, rawdb.ErrReadOnly
+	}
 	//
 	for i := 1; i < len(chain); i++ {
 		block, prev := chain[i], chain[i-1]
@@ -442,10 +432,11 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 		lastCanon block.IBlock
 	)
 
+	// Announce the head once the whole batch is in, unless a later import
+	// already moved it
 	defer func() {
 		if lastCanon != nil && bc.CurrentBlock().Hash() == lastCanon.Hash() {
-			// todo
-			// event.GlobalEvent.Send(&common.ChainHighestBlock{Block: lastCanon, Inserted: true})
+			bc.postChainEvent(lastCanon, CanonStatTy)
 		}
 	}()
 
@@ -460,8 +451,12 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 	abort, results := bc.engine.VerifyHeaders(bc, headers, seals)
 	defer close(abort)
 
+	// Verify the aggregated signatures of the whole batch at once while the
+	// headers are being verified
+	sealErrs := bc.validator.ValidateSeals(chain)
+
 	// Peek the error for the first block to decide the directing import logic
-	it := newInsertIterator(chain, results, bc.validator)
+	it := newInsertIterator(chain, results, sealErrs, bc.validator)
 	blk, err := it.next()
 	if bc.skipBlock(err) {
 		var (
@@ -513,7 +508,7 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 		return bc.insertSideChain(blk, it)
 
 	// First block is future, shove it (and all children) to the future queue (unknown ancestor)
-	case errors.Is(err, ErrFutureBlock) || (errors.Is(err, ErrUnknownAncestor) && bc.futureBlocks.Contains(it.first().ParentHash())):
+	case errors.Is(err, ErrFutureBlock) || (errors.Is(err, ErrUnknownAncestor) && bc.futureBlocks.contains(it.first().ParentHash())):
 		for blk != nil && (it.index == 0 || errors.Is(err, ErrUnknownAncestor)) {
 			log.Debug("Future block, postponing import", "number", blk.Number64(), "hash", blk.Hash())
 			if err := bc.AddFutureBlock(blk); err != nil {
@@ -531,20 +526,22 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 	// ErrKnownBlock is allowed here since some known blocks
 	// still need re-execution to generate snapshots that are missing
 	case err != nil && !errors.Is(err, ErrKnownBlock):
-		bc.futureBlocks.Remove(blk.Hash())
+		bc.futureBlocks.discard(blk.Hash())
 		stats.ignored += len(it.chain)
 		bc.reportBlock(blk, nil, err)
 		return it.index, err
 	}
 
-	evmRecord := func(ctx context.Context, db kv.RwDB, blockNr uint64, f func(tx kv.Tx, ibs *state.IntraBlockState, reader state.StateReader, writer state.WriterWithChangeSets) (map[types.Address]*uint256.Int, error)) (*state.IntraBlockState, map[types.Address]*uint256.Int, error) {
+	evmRecord := func(ctx context.Context, db kv.RwDB, blockNr uint64, staged []*state.StagedWriter, f func(tx kv.Tx, ibs *state.IntraBlockState, reader state.StateReader, writer state.WriterWithChangeSets) (map[types.Address]*uint256.Int, error)) (*state.IntraBlockState, map[types.Address]*uint256.Int, error) {
 		tx, err := db.BeginRo(ctx)
 		if nil != err {
 			return nil, nil, err
 		}
 		defer tx.Rollback()
 
-		stateReader := state.NewPlainStateReader(tx)
+		// Blocks still being committed are read from their staged writes
+		base := state.NewCachedPlainReader(state.NewPlainStateReader(tx), tx.ViewID())
+		stateReader := state.NewStagedReader(base, staged...)
 		ibs := state.New(stateReader)
 		stateWriter := state.NewNoopWriter()
 
@@ -557,12 +554,90 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 		return ibs, nopay, nil
 	}
 
+	// While a block is committed to the database, its child executes on top
+	// of the staged writes. Commits complete in order and are collected here.
+	queue := newCommitQueue(bc)
+	bc.commits.Store(queue)
+	defer func() {
+		queue.close()
+		bc.commits.Store(nil)
+	}()
+	collect := func(s *stagedBlock) {
+		// Report the import stats before returning the various results
+		stats.processed++
+		stats.usedGas += s.usedGas
+
+		blk := s.blk
+		switch s.status {
+		case CanonStatTy:
+			log.Trace("Inserted new block ", "number ", blk.Number64(), "hash", blk.Hash(),
+				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
+				"elapsed", time.Since(s.start).Seconds(),
+				"root", blk.StateRoot())
+
+			if len(s.logs) > 0 {
+				common.NewLogsTopic.Send(common.NewLogsEvent{Logs: s.logs})
+			}
+
+			lastCanon = blk
+
+		case SideStatTy:
+			log.Debug("Inserted forked block", "number", blk.Number64(), "hash", blk.Hash(),
+				"diff", blk.Difficulty(), "elapsed", time.Since(s.start).Seconds(),
+				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
+				"root", blk.StateRoot())
+			bc.postChainEvent(blk, SideStatTy)
+
+		default:
+			// This in theory is impossible, but lets be nice to our future selves and leave
+			// a log, instead of trying to track down blocks imports that don't emit logs.
+			log.Warn("Inserted block with unknown status", "number", blk.Number64(), "hash", blk.Hash(),
+				"diff", blk.Difficulty(), "elapsed", time.Since(s.start).Seconds(),
+				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
+				"root", blk.StateRoot())
+		}
+	}
+	// next collects the oldest queued block. After a failed commit the blocks
+	// behind it are dropped, as they cannot be written either.
+	next := func() (*stagedBlock, error) {
+		s := queue.wait()
+		if s != nil && s.err != nil {
+			for queue.wait() != nil {
+			}
+			return s, s.err
+		}
+		if s != nil {
+			collect(s)
+		}
+		return s, nil
+	}
+	// flush collects every queued block. A failed commit takes precedence
+	// over the error of the block at index, which comes after it.
+	flush := func(index int, err error) (int, error) {
+		for queue.len() > 0 {
+			if s, cerr := next(); cerr != nil {
+				return s.index, cerr
+			}
+		}
+		return index, err
+	}
+
 	for ; blk != nil && err == nil || errors.Is(err, ErrKnownBlock); blk, err = it.next() {
 		// If the chain is terminating, stop processing blocks
 		if bc.insertStopped() {
 			log.Debug("Abort during block processing")
 			break
 		}
+		if queue.needsBarrier(blk) {
+			if index, err := flush(it.index, nil); err != nil {
+				return index, err
+			}
+		}
+		for queue.len() >= queue.depth {
+			if s, err := next(); err != nil {
+				return s.index, err
+			}
+		}
 
 		log.Tracef("Current block: number=%v, hash=%v, difficult=%v | Insert block block: number=%v, hash=%v, difficult= %v",
 			bc.CurrentBlock().Number64(), bc.CurrentBlock().Hash(), bc.CurrentBlock().Difficulty(), blk.Number64(), blk.Hash(), blk.Difficulty())
@@ -572,8 +647,11 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 		var receipts block.Receipts
 		var logs []*block.Log
 		var usedGas uint64
-		ibs, nopay, err := evmRecord(bc.ctx, bc.ChainDB, blk.Number64().Uint64(), func(tx kv.Tx, ibs *state.IntraBlockState, reader state.StateReader, writer state.WriterWithChangeSets) (map[types.Address]*uint256.Int, error) {
+		ibs, nopay, err := evmRecord(bc.ctx, bc.ChainDB, blk.Number64().Uint64(), queue.writes(), func(tx kv.Tx, ibs *state.IntraBlockState, reader state.StateReader, writer state.WriterWithChangeSets) (map[types.Address]*uint256.Int, error) {
 			getHeader := func(hash types.Hash, number uint64) *block.Header {
+				if pending := bc.pendingBlock(hash); pending != nil {
+					return pending.Header().(*block.Header)
+				}
 				return rawdb.ReadHeader(tx, hash, number)
 			}
 			blockHashFunc := GetHashFn(blk.Header().(*block.Header), getHeader)
@@ -596,53 +674,32 @@ func (bc *BlockChain) insertChain(chain []block.IBlock) (int, error) {
 			}
 			vtime := time.Since(vstart)
 
-			blockExecutionTimer.Observe(float64(ptime)) // The time spent on EVM processing
-			blockValidationTimer.Observe(float64(vtime))
+			blockExecutionTimer.Observe(ptime.Seconds()) // The time spent on EVM processing
+			blockValidationTimer.Observe(vtime.Seconds())
+			common.SyncExecutionMeter.Observe(1, ptime+vtime)
 			return nopay, nil
 		})
 		if nil != err {
-			return it.index, err
+			return flush(it.index, err)
 		}
 
-		wstart := time.Now()
-		var status WriteStatus
-		status, err = bc.writeBlockWithState(blk, receipts, ibs, nopay)
-		if err != nil {
-			return it.index, err
-		}
-		blockWriteTimer.Observe(float64(time.Since(wstart)))
-		blockInsertTimer.Observe(float64(time.Since(start)))
-		// Report the import stats before returning the various results
-		stats.processed++
-		stats.usedGas += usedGas
-
-		switch status {
-		case CanonStatTy:
-			log.Trace("Inserted new block ", "number ", blk.Number64(), "hash", blk.Hash(),
-				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
-				"elapsed", time.Since(start).Seconds(),
-				"root", blk.StateRoot())
-
-			if len(logs) > 0 {
-				event.GlobalEvent.Send(common.NewLogsEvent{Logs: logs})
-			}
-
-			lastCanon = blk
-
-		case SideStatTy:
-			log.Debug("Inserted forked block", "number", blk.Number64(), "hash", blk.Hash(),
-				"diff", blk.Difficulty(), "elapsed", time.Since(start).Seconds(),
-				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
-				"root", blk.StateRoot())
-
-		default:
-			// This in theory is impossible, but lets be nice to our future selves and leave
-			// a log, instead of trying to track down blocks imports that don't emit logs.
-			log.Warn("Inserted block with unknown status", "number", blk.Number64(), "hash", blk.Hash(),
-				"diff", blk.Difficulty(), "elapsed", time.Since(start).Seconds(),
-				"txs", len(blk.Transactions()), "gas", blk.GasUsed(),
-				"root", blk.StateRoot())
+		writes := state.NewStagedWriter()
+		if err := ibs.CommitBlock(bc.chainConfig.Rules(blk.Number64().Uint64()), writes); err != nil {
+			return flush(it.index, err)
 		}
+		queue.push(&stagedBlock{
+			blk:      blk,
+			receipts: receipts,
+			logs:     logs,
+			usedGas:  usedGas,
+			writes:   writes,
+			nopay:    nopay,
+			index:    it.index,
+			start:    start,
+		})
+	}
+	if index, err := flush(it.index, nil); err != nil {
+		return index, err
 	}
 
 	// Any blocks remaining here? The only ones we care about are the future ones
@@ -830,67 +887,54 @@ func (bc *BlockChain) WriteBlockWithoutState(blk block.IBlock) (err error) {
 func (bc *BlockChain) WriteBlockWithState(blk block.IBlock, receipts []*block.Receipt, ibs interface{}, nopay map[types.Address]*uint256.Int) error {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
+	if bc.insertStopped() {
+		return errChainStopped
+	}
 	// Type assert to *state.IntraBlockState
 	stateDB, ok := ibs.(*state.IntraBlockState)
 	if !ok {
 		return fmt.Errorf("WriteBlockWithState: ibs must be *state.IntraBlockState")
 	}
-	_, err := bc.writeBlockWithState(blk, receipts, stateDB, nopay)
-	return err
+	status, err := bc.writeBlockWithState(blk, receipts, stateDB, nopay)
+	if err != nil {
+		return err
+	}
+	bc.postChainEvent(blk, status)
+	return nil
+}
+
+// postChainEvent announces a written block: a new canonical head on
+// ChainHeadTopic, a block that stayed off the canonical chain on
+// ChainSideTopic.
+func (bc *BlockChain) postChainEvent(blk block.IBlock, status WriteStatus) {
+	b, ok := blk.(*block.Block)
+	if !ok {
+		return
+	}
+	switch status {
+	case CanonStatTy:
+		common.ChainHeadTopic.Send(common.ChainHighestBlock{Block: *b, Inserted: true})
+	case SideStatTy:
+		common.ChainSideTopic.Send(common.ChainSideEvent{Block: *b})
+	}
 }
 
 // writeBlockWithState
 func (bc *BlockChain) writeBlockWithState(blk block.IBlock, receipts []*block.Receipt, ibs *state.IntraBlockState, nopay map[types.Address]*uint256.Int) (status WriteStatus, err error) {
+	return bc.writeBlockAndState(blk, receipts, func(w state.StateWriter) error {
+		return ibs.CommitBlock(bc.chainConfig.Rules(blk.Number64().Uint64()), w)
+	}, nopay)
+}
+
+// writeBlockAndState writes the block and the state changes commit applies
+// to the state writer, then updates the head.
+func (bc *BlockChain) writeBlockAndState(blk block.IBlock, receipts []*block.Receipt, commit func(state.StateWriter) error, nopay map[types.Address]*uint256.Int) (status WriteStatus, err error) {
 	// Calculate externTd outside transaction to update cache after commit
 	var externTd *uint256.Int
-	
-	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
-		//ptd := bc.GetTd(blk.ParentHash(), blk.Number64().Sub(uint256.NewInt(1)))
-		ptd, err := rawdb.ReadTd(tx, blk.ParentHash(), uint256.NewInt(0).Sub(blk.Number64(), uint256.NewInt(1)).Uint64())
-		if nil != err {
-			log.Errorf("ReadTd failed err: %v", err)
-		}
-		if ptd == nil {
-			return consensus.ErrUnknownAncestor
-		}
-
-		//if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
-		externTd = uint256.NewInt(0).Add(ptd, blk.Difficulty())
-		if err := rawdb.WriteTd(tx, blk.Hash(), blk.Number64().Uint64(), externTd); nil != err {
-			return err
-		}
-		log.Trace("writeTd:", "number", blk.Number64().Uint64(), "hash", blk.Hash(), "td", externTd.Uint64())
-		if len(receipts) > 0 {
-			//if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
-			if err := rawdb.AppendReceipts(tx, blk.Number64().Uint64(), receipts); nil != err {
-				log.Errorf("rawdb.AppendReceipts failed err= %v", err)
-				return err
-			}
-		}
-		if err := rawdb.WriteBlock(tx, blk.(*block.Block)); err != nil {
-			return err
-		}
-
-		stateWriter := state.NewPlainStateWriter(tx, tx, blk.Number64().Uint64())
-		if err := ibs.CommitBlock(bc.chainConfig.Rules(blk.Number64().Uint64()), stateWriter); nil != err {
-			return err
-		}
 
-		if err := stateWriter.WriteChangeSets(); err != nil {
-			return fmt.Errorf("writing changesets for block %d failed: %w", blk.Number64().Uint64(), err)
-		}
-
-		if err := stateWriter.WriteHistory(); err != nil {
-			return fmt.Errorf("writing history for block %d failed: %w", blk.Number64().Uint64(), err)
-		}
-
-		if nil != nopay {
-			for addr, v := range nopay {
-				rawdb.PutAccountReward(tx, addr, v)
-			}
-		}
-
-		return nil
+	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) (err error) {
+		externTd, err = bc.writeBlockData(tx, blk, receipts, commit, nopay)
+		return err
 	}); nil != err {
 		return NonStatTy, err
 	}
@@ -921,14 +965,69 @@ func (bc *BlockChain) writeBlockWithState(blk block.IBlock, receipts []*block.Re
 			log.Errorf("failed to save lates blocks, err: %v", err)
 			return NonStatTy, err
 		}
+		// Pre-warm the receipt cache so RPC readers following the head
+		// never have to go to the database for fresh blocks.
+		bc.receiptCache.Add(blk.Hash(), receipts)
 	}
-	//
-	if _, ok := bc.futureBlocks.Get(blk.Hash()); ok {
-		bc.futureBlocks.Remove(blk.Hash())
-	}
+	bc.futureBlocks.connected(blk.Hash())
 	return status, nil
 }
 
+// writeBlockData writes the block, its receipts and the state changes commit
+// applies to the state writer in tx, and returns the total difficulty of the
+// block.
+func (bc *BlockChain) writeBlockData(tx kv.RwTx, blk block.IBlock, receipts []*block.Receipt, commit func(state.StateWriter) error, nopay map[types.Address]*uint256.Int) (*uint256.Int, error) {
+	//ptd := bc.GetTd(blk.ParentHash(), blk.Number64().Sub(uint256.NewInt(1)))
+	ptd, err := rawdb.ReadTd(tx, blk.ParentHash(), uint256.NewInt(0).Sub(blk.Number64(), uint256.NewInt(1)).Uint64())
+	if nil != err {
+		log.Errorf("ReadTd failed err: %v", err)
+	}
+	if ptd == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+
+	//if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+	externTd := uint256.NewInt(0).Add(ptd, blk.Difficulty())
+	if err := rawdb.WriteTd(tx, blk.Hash(), blk.Number64().Uint64(), externTd); nil != err {
+		return nil, err
+	}
+	log.Trace("writeTd:", "number", blk.Number64().Uint64(), "hash", blk.Hash(), "td", externTd.Uint64())
+	if len(receipts) > 0 {
+		//if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+		if err := rawdb.AppendReceipts(tx, blk.Number64().Uint64(), receipts); nil != err {
+			log.Errorf("rawdb.AppendReceipts failed err= %v", err)
+			return nil, err
+		}
+	}
+	if err := rawdb.WriteBlock(tx, blk.(*block.Block)); err != nil {
+		return nil, err
+	}
+	if err := rawdb.WriteAddressActivity(tx, blk.Number64().Uint64(), rawdb.BlockAddresses(blk.(*block.Block), receipts)); err != nil {
+		return nil, err
+	}
+
+	stateWriter := state.NewPlainStateWriter(tx, tx, blk.Number64().Uint64())
+	if err := commit(stateWriter); nil != err {
+		return nil, err
+	}
+
+	if err := stateWriter.WriteChangeSets(); err != nil {
+		return nil, fmt.Errorf("writing changesets for block %d failed: %w", blk.Number64().Uint64(), err)
+	}
+
+	if err := stateWriter.WriteHistory(); err != nil {
+		return nil, fmt.Errorf("writing history for block %d failed: %w", blk.Number64().Uint64(), err)
+	}
+
+	if nil != nopay {
+		for addr, v := range nopay {
+			rawdb.PutAccountReward(tx, addr, v)
+		}
+	}
+
+	return externTd, nil
+}
+
 // writeHeadBlock head
 func (bc *BlockChain) writeHeadBlock(tx kv.RwTx, blk block.IBlock) error {
 	var err error
@@ -942,10 +1041,7 @@ func (bc *BlockChain) writeHeadBlock(tx kv.RwTx, blk block.IBlock) error {
 		notExternalTx = true
 	}
 
-	rawdb.WriteHeadBlockHash(tx, blk.Hash())
-	rawdb.WriteTxLookupEntries(tx, blk.(*block.Block))
-
-	if err = rawdb.WriteCanonicalHash(tx, blk.Hash(), blk.Number64().Uint64()); nil != err {
+	if err = bc.writeHead(tx, blk); nil != err {
 		return err
 	}
 
@@ -959,6 +1055,18 @@ func (bc *BlockChain) writeHeadBlock(tx kv.RwTx, blk block.IBlock) error {
 	return nil
 }
 
+// writeHead makes blk the canonical head in tx, leaving the in-memory head
+// to the caller.
+func (bc *BlockChain) writeHead(tx kv.RwTx, blk block.IBlock) error {
+	rawdb.WriteHeadBlockHash(tx, blk.Hash())
+	rawdb.WriteTxLookupEntries(tx, blk.(*block.Block))
+
+	if err := rawdb.WriteCanonicalHash(tx, blk.Hash(), blk.Number64().Uint64()); nil != err {
+		return err
+	}
+	return bc.updateFinality(tx, blk)
+}
+
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(blk block.IBlock, receipts []*block.Receipt, err error) {
 
@@ -966,7 +1074,7 @@ func (bc *BlockChain) reportBlock(blk block.IBlock, receipts []*block.Receipt, e
 	for i, receipt := range receipts {
 		receiptString += fmt.Sprintf("\t %d: cumulative: %v gas: %v contract: %v status: %v tx: %v logs: %v bloom: %x state: %x\n",
 			i, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.ContractAddress.String(),
-			receipt.Status, receipt.TxHash.String(), "Logs", receipt.Bloom, receipt.PostState)
+			receipt.Status, receipt.TxHash.String(), len(receipt.Logs), receipt.Bloom, receipt.PostState)
 	}
 	log.Error(fmt.Sprintf(`
 ########## BAD BLOCK #########
@@ -991,15 +1099,43 @@ func (bc *BlockChain) ReorgNeeded(current block.IBlock, header block.IBlock) boo
 	return true
 }
 
-// SetHead set new head
+// SetHead rewinds the chain to block head, unwinding the state and removing
+// all later blocks. See UnwindChain. The new head is announced like an
+// inserted block so the pool and the miner continue from it.
 func (bc *BlockChain) SetHead(head uint64) error {
-	newHeadBlock, err := bc.GetBlockByNumber(uint256.NewInt(head))
+	newHead, err := bc.setHead(head)
 	if err != nil {
-		return nil
+		return err
 	}
-	return bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
-		return rawdb.WriteHeadHeaderHash(tx, newHeadBlock.Hash())
-	})
+	if r, ok := bc.engine.(consensus.Rewinder); ok {
+		r.Rewind(head)
+	}
+	common.ChainHeadTopic.Send(common.ChainHighestBlock{Block: *newHead, Inserted: true})
+	return nil
+}
+
+func (bc *BlockChain) setHead(head uint64) (*block.Block, error) {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	var newHead *block.Block
+	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) (err error) {
+		newHead, err = UnwindChain(bc.ctx, tx, head)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	bc.blockCache.Purge()
+	bc.receiptCache.Purge()
+	bc.tdCache.Purge()
+	bc.numberCache.Purge()
+	bc.headerCache.Purge()
+	bc.futureBlocks.purge()
+	bc.currentBlock.Store(newHead)
+	headBlockGauge.Set(newHead.Number64().Uint64())
+	log.Info("Rewound chain head", "number", newHead.Number64(), "hash", newHead.Hash())
+	return newHead, nil
 }
 
 // AddFutureBlock checks if the block is within the max allowed window to get
@@ -1018,8 +1154,14 @@ func (bc *BlockChain) AddFutureBlock(blk block.IBlock) error {
 		return nil
 	}
 
+	if !bc.futureBlocks.add(blk.(*block.Block), time.Now()) {
+		return nil
+	}
 	log.Info("add future block", "hash", blk.Hash(), "number", blk.Number64().Uint64(), "stateRoot", blk.StateRoot(), "txs", len(blk.Body().Transactions()))
-	bc.futureBlocks.Add(blk.Hash(), blk.(*block.Block))
+	// The parent may have been written while blk was on its way
+	if blk.Time() <= uint64(time.Now().Unix()) && bc.HasBlock(blk.ParentHash(), blk.Number64().Uint64()-1) {
+		bc.futureBlocks.parentKnown(blk.ParentHash())
+	}
 	return nil
 }
 
@@ -1143,6 +1285,15 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block.IBlock) error {
 			return fmt.Errorf("invalid new chain")
 		}
 	}
+	// Finalized blocks are never reverted
+	if finalized := bc.finalizedNumber(tx); len(oldChain) > 0 && commonBlock.Number64().Uint64() < finalized {
+		return fmt.Errorf("%w: common ancestor %d, finalized %d", errReorgFinalized, commonBlock.Number64().Uint64(), finalized)
+	}
+	if len(oldChain) > 0 && len(newChain) > 0 {
+		if err := bc.checkReorgDepth(commonBlock, oldChain, newChain); err != nil {
+			return err
+		}
+	}
 
 	// Ensure the user sees large reorgs
 	if len(oldChain) > 0 && len(newChain) > 0 {
@@ -1206,8 +1357,17 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block.IBlock) error {
 
 	return nil
 }
+// Close stops accepting new blocks, waits for the block write in progress to
+// be committed and stops the background loops.
 func (bc *BlockChain) Close() error {
-	bc.Quit()
+	bc.StopInsert()
+	// Block writes hold bc.lock, taking it waits for the one in flight.
+	bc.lock.Lock()
+	bc.cancel()
+	bc.lock.Unlock()
+	bc.loopWg.Wait()
+	current := bc.CurrentBlock()
+	log.Info("Blockchain stopped", "number", current.Number64().Uint64(), "hash", current.Hash())
 	return nil
 }
 
@@ -75,6 +75,7 @@ type insertIterator struct {
 
 	results <-chan error // Verification result sink from the consensus engine
 	errors  []error      // Header verification errors for the blocks
+	seals   []error      // Seal verification errors for the blocks, verified as a batch
 
 	index     int       // Current offset of the iterator
 	validator Validator // Validator to run if verification succeeds
@@ -82,11 +83,12 @@ type insertIterator struct {
 
 // newInsertIterator creates a new iterator based on the given blocks, which are
 // assumed to be a contiguous chain.
-func newInsertIterator(chain []block.IBlock, results <-chan error, validator Validator) *insertIterator {
+func newInsertIterator(chain []block.IBlock, results <-chan error, seals []error, validator Validator) *insertIterator {
 	return &insertIterator{
 		chain:     chain,
 		results:   results,
 		errors:    make([]error, 0, len(chain)),
+		seals:     seals,
 		index:     -1,
 		validator: validator,
 	}
@@ -108,7 +110,10 @@ func (it *insertIterator) next() (block.IBlock, error) {
 	if it.errors[it.index] != nil {
 		return it.chain[it.index], it.errors[it.index]
 	}
-	// Block header valid, run body validation and return
+	if it.seals[it.index] != nil {
+		return it.chain[it.index], it.seals[it.index]
+	}
+	// Block header and seal valid, run body validation and return
 	return it.chain[it.index], it.validator.ValidateBody(it.chain[it.index])
 }
 
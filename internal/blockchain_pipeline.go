@@ -0,0 +1,336 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/consensus"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// commitQueueDepth is how many executed blocks may wait for their commit
+// while the import loop executes their descendants. Batched commits raise it
+// to the batch size.
+const commitQueueDepth = 2
+
+// stagedBlock is an executed and validated block waiting for its commit.
+type stagedBlock struct {
+	blk      block.IBlock
+	receipts block.Receipts
+	logs     []*block.Log
+	usedGas  uint64
+	writes   *state.StagedWriter
+	nopay    map[types.Address]*uint256.Int
+	index    int       // position in the imported batch
+	start    time.Time // when the execution began
+
+	status WriteStatus
+	err    error
+	done   chan struct{}
+}
+
+// commitQueue writes staged blocks to the database in import order on its
+// own goroutine. The blocks stay visible to the import loop until they are
+// collected, so their descendants can execute on top of them meanwhile.
+//
+// With batching enabled the committer writes up to every blocks, or size
+// bytes of state, in a single transaction. The head is stored in the same
+// transaction, so a crash leaves the database at the end of a batch.
+type commitQueue struct {
+	bc    *BlockChain
+	jobs  chan *stagedBlock
+	flush chan *stagedBlock // asks the committer to write the batch holding a block now
+	depth int
+	every int
+	size  int
+
+	mu      sync.RWMutex
+	pending []*stagedBlock // oldest first
+}
+
+func newCommitQueue(bc *BlockChain) *commitQueue {
+	every := bc.commitEvery
+	if every < 1 {
+		every = 1
+	}
+	// Room for the next blocks to execute while a full batch is written
+	depth := every + commitQueueDepth - 1
+	q := &commitQueue{
+		bc:    bc,
+		jobs:  make(chan *stagedBlock, depth),
+		flush: make(chan *stagedBlock),
+		depth: depth,
+		every: every,
+		size:  bc.commitSize,
+	}
+	go q.loop()
+	return q
+}
+
+func (q *commitQueue) loop() {
+	var (
+		batch  []*stagedBlock
+		size   int
+		failed error
+	)
+	commit := func() {
+		if len(batch) == 0 {
+			return
+		}
+		wstart := time.Now()
+		// Once a block failed, neither can its descendants be written
+		err := failed
+		if err == nil {
+			err = q.bc.writeBlocks(batch)
+		}
+		for _, s := range batch {
+			if s.status == NonStatTy {
+				s.err = err
+			} else {
+				blockWriteTimer.Observe(time.Since(wstart).Seconds())
+				blockInsertTimer.Observe(time.Since(s.start).Seconds())
+			}
+			close(s.done)
+		}
+		if err == nil {
+			common.SyncIndexingMeter.Observe(len(batch), time.Since(wstart))
+		}
+		failed = err
+		batch, size = nil, 0
+	}
+	add := func(s *stagedBlock) {
+		batch = append(batch, s)
+		size += s.writes.Size()
+		if len(batch) >= q.every || (q.size > 0 && size >= q.size) {
+			commit()
+		}
+	}
+	for {
+		select {
+		case s, ok := <-q.jobs:
+			if !ok {
+				commit()
+				return
+			}
+			add(s)
+		case s := <-q.flush:
+			// Everything pushed before the request is in the channel already
+			for drained := false; !drained; {
+				select {
+				case job, ok := <-q.jobs:
+					if !ok {
+						commit()
+						return
+					}
+					add(job)
+				default:
+					drained = true
+				}
+			}
+			select {
+			case <-s.done:
+				// Written meanwhile, the request is stale
+			default:
+				commit()
+			}
+		}
+	}
+}
+
+// push hands an executed block over to the committer.
+func (q *commitQueue) push(s *stagedBlock) {
+	s.done = make(chan struct{})
+	q.mu.Lock()
+	q.pending = append(q.pending, s)
+	q.mu.Unlock()
+	q.jobs <- s
+}
+
+// len returns the number of blocks not yet collected.
+func (q *commitQueue) len() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return len(q.pending)
+}
+
+// last returns the most recently staged block, or nil if there is none.
+func (q *commitQueue) last() *stagedBlock {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	return q.pending[len(q.pending)-1]
+}
+
+// wait blocks until the oldest staged block is committed and removes it from
+// the queue. It returns nil if the queue is empty.
+func (q *commitQueue) wait() *stagedBlock {
+	q.mu.RLock()
+	if len(q.pending) == 0 {
+		q.mu.RUnlock()
+		return nil
+	}
+	s := q.pending[0]
+	q.mu.RUnlock()
+
+	wstart := time.Now()
+	select {
+	case <-s.done:
+	case q.flush <- s:
+		<-s.done
+	}
+	blockCommitWaitTimer.Observe(time.Since(wstart).Seconds())
+
+	q.mu.Lock()
+	q.pending = q.pending[1:]
+	q.mu.Unlock()
+	return s
+}
+
+// close stops the committer. The queue must be empty.
+func (q *commitQueue) close() {
+	close(q.jobs)
+}
+
+// writes returns the staged writes of the queued blocks, oldest first. They
+// must be taken before opening the transaction they are laid over: a block
+// committed in between is then seen twice, with the same values, but never
+// missed.
+func (q *commitQueue) writes() []*state.StagedWriter {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	writes := make([]*state.StagedWriter, len(q.pending))
+	for i, s := range q.pending {
+		writes[i] = s.writes
+	}
+	return writes
+}
+
+// block returns the queued block with the given hash, if any.
+func (q *commitQueue) block(hash types.Hash) block.IBlock {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	for _, s := range q.pending {
+		if s.blk.Hash() == hash {
+			return s.blk
+		}
+	}
+	return nil
+}
+
+// needsBarrier reports whether blk can only be executed once every queued
+// block is committed: it does not extend the last staged block, or its
+// finalization reads chain data the overlay cannot provide.
+func (q *commitQueue) needsBarrier(blk block.IBlock) bool {
+	last := q.last()
+	if last == nil {
+		return false
+	}
+	if last.blk.Hash() != blk.ParentHash() || last.nopay != nil {
+		return true
+	}
+	if f, ok := q.bc.engine.(consensus.ChainReadingFinalizer); ok {
+		return f.FinalizeReadsChain(blk.Header())
+	}
+	return false
+}
+
+// writeBlocks writes a batch of consecutive blocks. A batch extending the head
+// goes into one transaction, anything else is written block by block.
+func (bc *BlockChain) writeBlocks(batch []*stagedBlock) error {
+	if len(batch) == 1 || !bc.extendsHead(batch) {
+		for _, s := range batch {
+			var err error
+			if s.status, err = bc.writeBlockAndState(s.blk, s.receipts, s.writes.Replay, s.nopay); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tds := make([]*uint256.Int, len(batch))
+	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) (err error) {
+		for i, s := range batch {
+			if tds[i], err = bc.writeBlockData(tx, s.blk, s.receipts, s.writes.Replay, s.nopay); err != nil {
+				return err
+			}
+			if err = bc.writeHead(tx, s.blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for i, s := range batch {
+		s.status = CanonStatTy
+		bc.tdCache.Add(s.blk.Hash(), tds[i])
+		bc.receiptCache.Add(s.blk.Hash(), s.receipts)
+		bc.futureBlocks.connected(s.blk.Hash())
+	}
+	head := batch[len(batch)-1].blk
+	bc.currentBlock.Store(head.(*block.Block))
+	headBlockGauge.Set(head.Number64().Uint64())
+	log.Debug("Committed block batch", "from", batch[0].blk.Number64(), "to", head.Number64(), "blocks", len(batch))
+	return nil
+}
+
+// extendsHead reports whether the batch is a chain on top of the current head
+// that the fork choice makes canonical block by block: every block adds
+// difficulty, so each one has a higher total difficulty than its parent.
+func (bc *BlockChain) extendsHead(batch []*stagedBlock) bool {
+	parent := bc.CurrentBlock().Hash()
+	for _, s := range batch {
+		if s.blk.ParentHash() != parent || s.blk.Difficulty().IsZero() {
+			return false
+		}
+		parent = s.blk.Hash()
+	}
+	return true
+}
+
+// SetCommitBatch makes block imports commit up to blocks blocks, or size
+// bytes of state changes, per database transaction. It must be called
+// before Start.
+func (bc *BlockChain) SetCommitBatch(blocks int, size int) error {
+	if blocks < 1 {
+		return errors.New("commit batch must hold at least one block")
+	}
+	bc.commitEvery = blocks
+	bc.commitSize = size
+	return nil
+}
+
+// pendingBlock returns the block with the given hash if the running import
+// executed it but may not have committed it yet.
+func (bc *BlockChain) pendingBlock(hash types.Hash) block.IBlock {
+	if q := bc.commits.Load(); q != nil {
+		return q.block(hash)
+	}
+	return nil
+}
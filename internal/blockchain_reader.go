@@ -31,8 +31,15 @@ package internal
 //   - State access: StateAt, HasState, HasBlockAndState
 //   - Deposit/Reward: GetDepositInfo, GetAccountRewardUnpaid
 //   - Lifecycle: Quit
+//
+// The *Context variants bind their database reads to the caller's context as
+// well as the chain's own, so an RPC request whose client has gone away stops
+// before opening further read transactions. The plain variants read with the
+// chain's context only.
 
 import (
+	"context"
+
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/n42blockchain/N42/common/block"
@@ -78,56 +85,77 @@ func (bc *BlockChain) GenesisBlock() block.IBlock {
 	return bc.genesisBlock
 }
 
-// Blocks returns all cached blocks.
-func (bc *BlockChain) Blocks() []block.IBlock {
-	return bc.blocks
-}
-
 // GetBlock retrieves a block from the database by hash and number.
 // Returns nil if the block is not found.
 func (bc *BlockChain) GetBlock(hash types.Hash, number uint64) block.IBlock {
+	blk, _ := bc.GetBlockContext(bc.ctx, hash, number)
+	return blk
+}
+
+// GetBlockContext is GetBlock bound to ctx. It returns a nil block and a nil
+// error if the block is not found.
+func (bc *BlockChain) GetBlockContext(ctx context.Context, hash types.Hash, number uint64) (block.IBlock, error) {
 	if hash == (types.Hash{}) {
-		return nil
+		return nil, nil
 	}
 
 	if blk, ok := bc.blockCache.Get(hash); ok {
-		return blk
+		return blk, nil
 	}
 
-	tx, err := bc.ChainDB.BeginRo(bc.ctx)
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	tx, err := bc.ChainDB.BeginRo(ctx)
 	if nil != err {
-		return nil
+		return nil, err
 	}
 	defer tx.Rollback()
 	blk := rawdb.ReadBlock(tx, hash, number)
 	if blk == nil {
-		return nil
+		return nil, nil
 	}
 	bc.blockCache.Add(hash, blk)
-	return blk
+	return blk, nil
 }
 
 // GetBlockByHash retrieves a block by its hash.
 func (bc *BlockChain) GetBlockByHash(h types.Hash) (block.IBlock, error) {
-	number := bc.GetBlockNumber(h)
+	return bc.GetBlockByHashContext(bc.ctx, h)
+}
+
+// GetBlockByHashContext is GetBlockByHash bound to ctx.
+func (bc *BlockChain) GetBlockByHashContext(ctx context.Context, h types.Hash) (block.IBlock, error) {
+	number, err := bc.getBlockNumber(ctx, h)
+	if err != nil {
+		return nil, err
+	}
 	if nil == number {
 		return nil, errBlockDoesNotExist
 	}
-	return bc.GetBlock(h, *number), nil
+	return bc.GetBlockContext(ctx, h, *number)
 }
 
 // GetBlockByNumber retrieves a block by its number.
 func (bc *BlockChain) GetBlockByNumber(number *uint256.Int) (block.IBlock, error) {
+	return bc.GetBlockByNumberContext(bc.ctx, number)
+}
+
+// GetBlockByNumberContext is GetBlockByNumber bound to ctx.
+func (bc *BlockChain) GetBlockByNumberContext(ctx context.Context, number *uint256.Int) (block.IBlock, error) {
+	rctx, cancel := bc.readContext(ctx)
+	defer cancel()
 	var hash types.Hash
-	bc.ChainDB.View(bc.ctx, func(tx kv.Tx) error {
+	if err := bc.ChainDB.View(rctx, func(tx kv.Tx) error {
 		hash, _ = rawdb.ReadCanonicalHash(tx, number.Uint64())
 		return nil
-	})
+	}); err != nil {
+		return nil, err
+	}
 
 	if hash == (types.Hash{}) {
 		return nil, nil
 	}
-	return bc.GetBlock(hash, number.Uint64()), nil
+	return bc.GetBlockContext(ctx, hash, number.Uint64())
 }
 
 // GetBlocksFromHash retrieves a number of blocks starting from a given hash,
@@ -181,62 +209,89 @@ func (bc *BlockChain) HasBlock(hash types.Hash, number uint64) bool {
 
 // GetHeader retrieves a block header by hash and number.
 func (bc *BlockChain) GetHeader(h types.Hash, number *uint256.Int) block.IHeader {
+	header, _ := bc.getHeader(bc.ctx, h, number.Uint64())
+	return header
+}
+
+func (bc *BlockChain) getHeader(ctx context.Context, h types.Hash, number uint64) (block.IHeader, error) {
 	// Short circuit if the header's already in the cache, retrieve otherwise
 	if header, ok := bc.headerCache.Get(h); ok {
-		return header
+		return header, nil
+	}
+	if pending := bc.pendingBlock(h); pending != nil {
+		return pending.Header(), nil
 	}
 
-	tx, err := bc.ChainDB.BeginRo(bc.ctx)
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	tx, err := bc.ChainDB.BeginRo(ctx)
 	if nil != err {
-		return nil
+		return nil, err
 	}
 	defer tx.Rollback()
-	header := rawdb.ReadHeader(tx, h, number.Uint64())
+	header := rawdb.ReadHeader(tx, h, number)
 	if nil == header {
-		return nil
+		return nil, nil
 	}
 
 	bc.headerCache.Add(h, header)
-	return header
+	return header, nil
 }
 
 // GetHeaderByNumber retrieves a block header by number.
 func (bc *BlockChain) GetHeaderByNumber(number *uint256.Int) block.IHeader {
-	tx, err := bc.ChainDB.BeginRo(bc.ctx)
-	if nil != err {
+	header, err := bc.GetHeaderByNumberContext(bc.ctx, number)
+	if err != nil {
 		log.Error("cannot open chain db", "err", err)
 		return nil
 	}
+	return header
+}
+
+// GetHeaderByNumberContext is GetHeaderByNumber bound to ctx. It returns a nil
+// header and a nil error if there is no canonical block at number.
+func (bc *BlockChain) GetHeaderByNumberContext(ctx context.Context, number *uint256.Int) (block.IHeader, error) {
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	tx, err := bc.ChainDB.BeginRo(ctx)
+	if nil != err {
+		return nil, err
+	}
 	defer tx.Rollback()
 
 	hash, err := rawdb.ReadCanonicalHash(tx, number.Uint64())
 	if nil != err {
-		log.Error("cannot open chain db", "err", err)
-		return nil
+		return nil, err
 	}
 	if hash == (types.Hash{}) {
-		return nil
+		return nil, nil
 	}
 
 	if header, ok := bc.headerCache.Get(hash); ok {
-		return header
+		return header, nil
 	}
 	header := rawdb.ReadHeader(tx, hash, number.Uint64())
 	if nil == header {
-		return nil
+		return nil, nil
 	}
 	bc.headerCache.Add(hash, header)
-	return header
+	return header, nil
 }
 
 // GetHeaderByHash retrieves a block header by hash.
 func (bc *BlockChain) GetHeaderByHash(h types.Hash) (block.IHeader, error) {
-	number := bc.GetBlockNumber(h)
-	if number == nil {
-		return nil, nil
-	}
+	header, _ := bc.GetHeaderByHashContext(bc.ctx, h)
+	return header, nil
+}
 
-	return bc.GetHeader(h, uint256.NewInt(*number)), nil
+// GetHeaderByHashContext is GetHeaderByHash bound to ctx. It returns a nil
+// header and a nil error if the header is not found.
+func (bc *BlockChain) GetHeaderByHashContext(ctx context.Context, h types.Hash) (block.IHeader, error) {
+	number, err := bc.getBlockNumber(ctx, h)
+	if err != nil || number == nil {
+		return nil, err
+	}
+	return bc.getHeader(ctx, h, *number)
 }
 
 // GetCanonicalHash returns the canonical hash for a given block number.
@@ -256,19 +311,26 @@ func (bc *BlockChain) GetCanonicalHash(number *uint256.Int) types.Hash {
 
 // GetBlockNumber retrieves the block number for a given hash.
 func (bc *BlockChain) GetBlockNumber(hash types.Hash) *uint64 {
+	number, _ := bc.getBlockNumber(bc.ctx, hash)
+	return number
+}
+
+func (bc *BlockChain) getBlockNumber(ctx context.Context, hash types.Hash) (*uint64, error) {
 	if cached, ok := bc.numberCache.Get(hash); ok {
-		return &cached
+		return &cached, nil
 	}
-	tx, err := bc.ChainDB.BeginRo(bc.ctx)
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	tx, err := bc.ChainDB.BeginRo(ctx)
 	if nil != err {
-		return nil
+		return nil, err
 	}
 	defer tx.Rollback()
 	number := rawdb.ReadHeaderNumber(tx, hash)
 	if number != nil {
 		bc.numberCache.Add(hash, *number)
 	}
-	return number
+	return number, nil
 }
 
 // GetTd retrieves the total difficulty for a block.
@@ -296,18 +358,79 @@ func (bc *BlockChain) GetTd(hash types.Hash, number *uint256.Int) *uint256.Int {
 // =============================================================================
 
 // GetReceipts retrieves receipts for a block by hash.
+// Canonical blocks are served from the receipt cache; on a miss the receipts of
+// the following receiptsReadAhead canonical blocks are loaded in one batch, so
+// sequential backfills (e.g. Blockscout) hit the cache instead of MDBX.
 func (bc *BlockChain) GetReceipts(blockHash types.Hash) (block.Receipts, error) {
-	rtx, err := bc.ChainDB.BeginRo(bc.ctx)
+	return bc.GetReceiptsContext(bc.ctx, blockHash)
+}
+
+// GetReceiptsContext is GetReceipts bound to ctx.
+func (bc *BlockChain) GetReceiptsContext(ctx context.Context, blockHash types.Hash) (block.Receipts, error) {
+	if receipts, ok := bc.receiptCache.Get(blockHash); ok {
+		return receipts, nil
+	}
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	rtx, err := bc.ChainDB.BeginRo(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer rtx.Rollback()
-	return rawdb.ReadReceiptsByHash(rtx, blockHash)
+
+	number := rawdb.ReadHeaderNumber(rtx, blockHash)
+	if number == nil {
+		return nil, nil
+	}
+	canonical, err := rawdb.ReadCanonicalHash(rtx, *number)
+	if err != nil {
+		return nil, err
+	}
+	if canonical != blockHash {
+		// Side chain blocks are rare enough to skip the cache.
+		return rawdb.ReadReceiptsByHash(rtx, blockHash)
+	}
+	// The read-ahead is the expensive part; skip it for a request that is gone.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := bc.warmReceipts(rtx, *number, receiptsReadAhead); err != nil {
+		return nil, err
+	}
+	if receipts, ok := bc.receiptCache.Get(blockHash); ok {
+		return receipts, nil
+	}
+	return nil, nil
+}
+
+// warmReceipts loads the receipts of count canonical blocks starting at from
+// into the receipt cache using a single batched read.
+func (bc *BlockChain) warmReceipts(tx kv.Tx, from uint64, count int) error {
+	batch, err := rawdb.ReadRawReceiptsRange(tx, from, count)
+	if err != nil {
+		return err
+	}
+	for number, receipts := range batch {
+		hash, err := rawdb.ReadCanonicalHash(tx, number)
+		if err != nil {
+			return err
+		}
+		if hash == (types.Hash{}) {
+			continue
+		}
+		bc.receiptCache.Add(hash, receipts)
+	}
+	return nil
 }
 
 // GetLogs retrieves all logs for a block by hash.
 func (bc *BlockChain) GetLogs(blockHash types.Hash) ([][]*block.Log, error) {
-	receipts, err := bc.GetReceipts(blockHash)
+	return bc.GetLogsContext(bc.ctx, blockHash)
+}
+
+// GetLogsContext is GetLogs bound to ctx.
+func (bc *BlockChain) GetLogsContext(ctx context.Context, blockHash types.Hash) ([][]*block.Log, error) {
+	receipts, err := bc.GetReceiptsContext(ctx, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -326,26 +449,39 @@ func (bc *BlockChain) GetLogs(blockHash types.Hash) ([][]*block.Log, error) {
 // StateAt returns a new state at the given block number.
 // Returns interface{} to avoid circular dependency.
 func (bc *BlockChain) StateAt(tx kv.Tx, blockNr uint64) interface{} {
-	reader := state.NewPlainState(tx, blockNr+1)
-	return state.New(reader)
+	return bc.StateAtContext(bc.ctx, tx, blockNr)
+}
+
+// StateAtContext is StateAt bound to ctx: the state stops reading once ctx
+// is done.
+func (bc *BlockChain) StateAtContext(ctx context.Context, tx kv.Tx, blockNr uint64) interface{} {
+	return state.New(state.NewContextReader(ctx, state.NewHistoricalStateReader(tx, blockNr)))
 }
 
 // HasState checks if the state for a block exists.
 func (bc *BlockChain) HasState(hash types.Hash) bool {
-	tx, err := bc.ChainDB.BeginRo(bc.ctx)
+	is, _ := bc.HasStateContext(bc.ctx, hash)
+	return is
+}
+
+// HasStateContext is HasState bound to ctx.
+func (bc *BlockChain) HasStateContext(ctx context.Context, hash types.Hash) (bool, error) {
+	ctx, cancel := bc.readContext(ctx)
+	defer cancel()
+	tx, err := bc.ChainDB.BeginRo(ctx)
 	if nil != err {
-		return false
+		return false, err
 	}
 	defer tx.Rollback()
-	is, err := rawdb.IsCanonicalHash(tx, hash)
-	if nil != err {
-		return false
-	}
-	return is
+	return rawdb.IsCanonicalHash(tx, hash)
 }
 
 // HasBlockAndState checks if a block and its state exist.
 func (bc *BlockChain) HasBlockAndState(hash types.Hash, number uint64) bool {
+	// Blocks of the running import are executed before they are committed
+	if bc.pendingBlock(hash) != nil {
+		return true
+	}
 	blk := bc.GetBlock(hash, number)
 	if blk == nil {
 		return false
@@ -385,6 +521,20 @@ func (bc *BlockChain) GetAccountRewardUnpaid(account types.Address) (*uint256.In
 // Lifecycle
 // =============================================================================
 
+// readContext returns a context that is done when either ctx or the chain's
+// own context is, so reads stop both on shutdown and when the caller gives up.
+func (bc *BlockChain) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil || ctx == bc.ctx || bc.ctx.Err() != nil {
+		return bc.ctx, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(bc.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 // Quit returns a channel that is closed when the blockchain is stopping.
 func (bc *BlockChain) Quit() <-chan struct{} {
 	return bc.ctx.Done()
@@ -0,0 +1,126 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+func newReaderTestChain(t *testing.T) (*BlockChain, *block.Block) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+
+	header := &block.Header{Number: uint256.NewInt(1), Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0)}
+	blk := block.NewBlockFromStorage(header.Hash(), header, &block.Body{})
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		if err := rawdb.WriteBlock(tx, blk); err != nil {
+			return err
+		}
+		return rawdb.WriteCanonicalHash(tx, blk.Hash(), 1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	bc := &BlockChain{ChainDB: db, ctx: ctx, cancel: cancel}
+	bc.blockCache, _ = lru.New[types.Hash, *block.Block](blockCacheLimit)
+	bc.headerCache, _ = lru.New[types.Hash, *block.Header](headerCacheLimit)
+	bc.numberCache, _ = lru.New[types.Hash, uint64](numberCacheLimit)
+	return bc, blk
+}
+
+func TestReadsHonourCallerContext(t *testing.T) {
+	bc, blk := newReaderTestChain(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bc.GetHeaderByNumberContext(cancelled, uint256.NewInt(1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("header read with a cancelled context: err %v, want %v", err, context.Canceled)
+	}
+	if _, err := bc.GetBlockByHashContext(cancelled, blk.Hash()); !errors.Is(err, context.Canceled) {
+		t.Errorf("block read with a cancelled context: err %v, want %v", err, context.Canceled)
+	}
+
+	header, err := bc.GetHeaderByNumberContext(context.Background(), uint256.NewInt(1))
+	if err != nil || header == nil || header.Hash() != blk.Hash() {
+		t.Fatalf("header read: have %v, %v, want %v", header, err, blk.Hash())
+	}
+	got, err := bc.GetBlockByHashContext(context.Background(), blk.Hash())
+	if err != nil || got == nil || got.Hash() != blk.Hash() {
+		t.Fatalf("block read: have %v, %v, want %v", got, err, blk.Hash())
+	}
+	// Cached entries are served without touching the database.
+	if got, err := bc.GetBlockByHashContext(cancelled, blk.Hash()); err != nil || got == nil {
+		t.Errorf("cached block read with a cancelled context: have %v, %v", got, err)
+	}
+}
+
+func TestReadContextFollowsChainShutdown(t *testing.T) {
+	bc, _ := newReaderTestChain(t)
+
+	ctx, cancel := bc.readContext(context.Background())
+	defer cancel()
+	bc.cancel()
+	<-ctx.Done()
+	if _, err := bc.GetHeaderByNumberContext(context.Background(), uint256.NewInt(1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("read after shutdown: err %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestStateReadsHonourCallerContext(t *testing.T) {
+	bc, blk := newReaderTestChain(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bc.HasStateContext(cancelled, blk.Hash()); !errors.Is(err, context.Canceled) {
+		t.Errorf("state check with a cancelled context: err %v, want %v", err, context.Canceled)
+	}
+	if ok, err := bc.HasStateContext(context.Background(), blk.Hash()); err != nil || !ok {
+		t.Errorf("state check: have %v, %v, want true", ok, err)
+	}
+
+	tx, err := bc.ChainDB.BeginRo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	ibs := bc.StateAtContext(cancelled, tx, 1).(*state.IntraBlockState)
+	ibs.GetBalance(types.Address{0x01})
+	if err := ibs.Error(); !errors.Is(err, context.Canceled) {
+		t.Errorf("state read with a cancelled context: err %v, want %v", err, context.Canceled)
+	}
+	ibs = bc.StateAtContext(context.Background(), tx, 1).(*state.IntraBlockState)
+	ibs.GetBalance(types.Address{0x01})
+	if err := ibs.Error(); err != nil {
+		t.Errorf("state read: err %v", err)
+	}
+}
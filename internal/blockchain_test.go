@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/types"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 )
 
 // =============================================================================
@@ -313,6 +315,48 @@ func TestDerivableListLen(t *testing.T) {
 	t.Logf("✓ DerivableList.Len() works correctly")
 }
 
+// =============================================================================
+// Chain Event Tests
+// =============================================================================
+
+func TestPostChainEvent(t *testing.T) {
+	heads := common.ChainHeadTopic.Subscribe(1, eventv3.DropOldest)
+	defer heads.Unsubscribe()
+	sides := common.ChainSideTopic.Subscribe(1, eventv3.DropOldest)
+	defer sides.Unsubscribe()
+
+	bc := &BlockChain{}
+	canon := newFutureTestBlock(nil, 1, 1, 0)
+	side := newFutureTestBlock(nil, 1, 1, 1)
+	bc.postChainEvent(canon, CanonStatTy)
+	bc.postChainEvent(side, SideStatTy)
+	bc.postChainEvent(side, NonStatTy)
+
+	select {
+	case ev := <-heads.Chan():
+		if ev.Block.Hash() != canon.Hash() || !ev.Inserted {
+			t.Errorf("head event mismatch: have %v inserted %v, want %v", ev.Block.Hash(), ev.Inserted, canon.Hash())
+		}
+	default:
+		t.Fatal("no head event")
+	}
+	select {
+	case ev := <-sides.Chan():
+		if ev.Block.Hash() != side.Hash() {
+			t.Errorf("side event mismatch: have %v, want %v", ev.Block.Hash(), side.Hash())
+		}
+	default:
+		t.Fatal("no side event")
+	}
+	select {
+	case <-heads.Chan():
+		t.Error("unexpected head event")
+	case <-sides.Chan():
+		t.Error("unexpected side event")
+	default:
+	}
+}
+
 // =============================================================================
 // Benchmark Tests
 // =============================================================================
@@ -0,0 +1,109 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/internal/commitment"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// UnwindChain rolls the chain stored in tx back to block target: the state is
+// reverted through the change sets, and every block above target is removed
+// together with its receipts, revert reasons, transaction lookups, total
+// difficulty, canonical markers, address blooms, state roots, change sets and
+// history. The code of contracts created after target is released, and
+// finality markers above target move to the new head. Blocks in the freezer
+// can not be unwound. It returns the new head.
+//
+// Account rewards and deposits are not versioned and are left untouched.
+func UnwindChain(ctx context.Context, tx kv.RwTx, target uint64) (*block.Block, error) {
+	current := rawdb.ReadCurrentBlock(tx)
+	if current == nil {
+		return nil, fmt.Errorf("database has no head block")
+	}
+	head := current.Number64().Uint64()
+	if target >= head {
+		return nil, fmt.Errorf("target %d is not below the head %d", target, head)
+	}
+	frozen, err := rawdb.FrozenBlocks(tx)
+	if err != nil {
+		return nil, err
+	}
+	if target+1 < frozen {
+		return nil, fmt.Errorf("cannot rewind to block %d below the %d frozen blocks", target, frozen)
+	}
+	newHead, err := rawdb.ReadBlockByNumber(tx, target)
+	if newHead == nil {
+		return nil, fmt.Errorf("canonical block %d is missing: %v", target, err)
+	}
+
+	log.Info("Unwinding chain", "from", head, "to", target)
+	for n := head; n > target; n-- {
+		blk, _ := rawdb.ReadBlockByNumber(tx, n)
+		if blk == nil {
+			continue
+		}
+		for _, t := range blk.Transactions() {
+			if err := rawdb.DeleteTxLookupEntry(tx, t.Hash()); err != nil {
+				return nil, err
+			}
+			if err := rawdb.DeleteRevertReason(tx, t.Hash()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := state.UnwindState(tx, target); err != nil {
+		return nil, fmt.Errorf("unwinding state: %w", err)
+	}
+	if err := commitment.Unwind(tx, target); err != nil {
+		return nil, fmt.Errorf("unwinding state commitment: %w", err)
+	}
+	if err := rawdb.TruncateReceipts(tx, target+1); err != nil {
+		return nil, err
+	}
+	if err := rawdb.TruncateAddressActivity(tx, target+1); err != nil {
+		return nil, err
+	}
+	// Reads the block numbers of the markers, before the blocks are gone.
+	if err := rawdb.TruncateFinality(tx, target, newHead.Hash()); err != nil {
+		return nil, err
+	}
+	if err := rawdb.TruncateCanonicalHash(tx, target+1, true); err != nil {
+		return nil, err
+	}
+	// Removes the non-canonical blocks above target too, so none of them is
+	// mistaken for a known block with state when it arrives again.
+	if err := rawdb.TruncateBlocks(ctx, tx, target+1); err != nil {
+		return nil, err
+	}
+	if err := rawdb.TruncateTd(tx, target+1); err != nil {
+		return nil, err
+	}
+
+	rawdb.WriteHeadBlockHash(tx, newHead.Hash())
+	if err := rawdb.WriteHeadHeaderHash(tx, newHead.Hash()); err != nil {
+		return nil, err
+	}
+	return newHead, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/consensus"
+	vm2 "github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// BundleTxResult is the outcome of one transaction of an applied bundle.
+type BundleTxResult struct {
+	Tx           *transaction.Transaction
+	Receipt      *block.Receipt
+	Return       []byte   // Return data, or the revert reason
+	CoinbaseDiff *big.Int // Change of the author balance, fees and direct payments
+}
+
+// ApplyBundle applies the transactions of bundle in order on top of ibs, the
+// first one at index txIndex of the block. Reverted transactions are part of
+// the results, it stops only at the first transaction which cannot be
+// included at all. The state cannot be reverted across transactions, so
+// callers apply bundles to a scratch state first.
+func ApplyBundle(config *params.ChainConfig, blockHashFunc func(n uint64) types.Hash, engine consensus.Engine, author *types.Address, gp *common.GasPool, ibs *state.IntraBlockState, header *block.Header, bundle *transaction.Bundle, txIndex int, usedGas *uint64, cfg vm2.Config) ([]*BundleTxResult, error) {
+	noop := state.NewNoopWriter()
+	results := make([]*BundleTxResult, 0, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		before := ibs.GetBalance(*author).ToBig()
+		ibs.Prepare(tx.Hash(), types.Hash{}, txIndex+i)
+		receipt, ret, err := ApplyTransaction(config, blockHashFunc, engine, author, gp, ibs, noop, header, tx, usedGas, cfg)
+		if err != nil {
+			return results, fmt.Errorf("bundle transaction %v: %w", tx.Hash(), err)
+		}
+		results = append(results, &BundleTxResult{
+			Tx:           tx,
+			Receipt:      receipt,
+			Return:       ret,
+			CoinbaseDiff: new(big.Int).Sub(ibs.GetBalance(*author).ToBig(), before),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+)
+
+// API exposes the state commitment over RPC.
+type API struct {
+	s *Service
+}
+
+// StateCommitment returns the last block whose state is committed, or nil
+// before the commitment is first built.
+func (api *API) StateCommitment() *Progress {
+	return api.s.Progress()
+}
+
+// APIs returns the RPC services of the commitment service.
+func (s *Service) APIs() []jsonrpc.API {
+	return []jsonrpc.API{{
+		Namespace: "admin",
+		Service:   &API{s},
+	}}
+}
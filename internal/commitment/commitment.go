@@ -0,0 +1,443 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package commitment maintains a Merkle commitment of the state in the
+// background. The trie is updated block by block from the plain state and
+// the change sets, and its root is recorded for every block. The scheme is
+// the hex patricia trie unless the chain config selects the experimental
+// Verkle tree.
+package commitment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/params"
+)
+
+const (
+	// batchSize is the number of blocks committed per database transaction.
+	batchSize = 100
+	// batchPause leaves room for block imports between two batches.
+	batchPause = 10 * time.Millisecond
+	// followInterval is how often the commitment checks for new blocks once
+	// it has caught up with the head.
+	followInterval = 2 * time.Second
+	// syncPollInterval is how often a paused commitment checks whether the
+	// initial sync has finished.
+	syncPollInterval = 10 * time.Second
+	logInterval      = 30 * time.Second
+)
+
+var (
+	progressKey  = []byte("Commitment")
+	trieStateKey = []byte("CommitmentTrie")
+)
+
+// Progress is the last block whose state is committed.
+type Progress struct {
+	Number uint64                  `json:"number"`
+	Hash   types.Hash              `json:"hash"`
+	Root   types.Hash              `json:"root"`
+	Scheme params.CommitmentScheme `json:"scheme"`
+}
+
+// ReadProgress retrieves the commitment progress, or nil if no commitment
+// was built yet.
+func ReadProgress(db kv.Getter) (*Progress, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, progressKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var p Progress
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid commitment progress JSON err: %v", err)
+	}
+	if p.Scheme == "" {
+		p.Scheme = params.HexPatriciaCommitment
+	}
+	return &p, nil
+}
+
+func writeProgress(db kv.Putter, p *Progress, trieState []byte) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := db.Put(modules.DatabaseInfo, trieStateKey, trieState); err != nil {
+		return err
+	}
+	return db.Put(modules.DatabaseInfo, progressKey, data)
+}
+
+// ReadRoot retrieves the committed state root of a block together with the
+// hash of the block it was computed for. ok is false if the block has no
+// committed root.
+func ReadRoot(db kv.Getter, number uint64) (hash, root types.Hash, ok bool, err error) {
+	data, err := db.GetOne(modules.CommitmentRoot, modules.EncodeBlockNumber(number))
+	if err != nil || len(data) != 2*types.HashLength {
+		return hash, root, false, err
+	}
+	return types.BytesToHash(data[:types.HashLength]), types.BytesToHash(data[types.HashLength:]), true, nil
+}
+
+func writeRoot(db kv.Putter, number uint64, hash, root types.Hash) error {
+	return db.Put(modules.CommitmentRoot, modules.EncodeBlockNumber(number), append(hash.Bytes(), root.Bytes()...))
+}
+
+// Service keeps the state commitment up to date with the canonical head.
+type Service struct {
+	db     kv.RwDB
+	synced func() bool
+
+	mu       sync.RWMutex
+	progress *Progress
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService creates a commitment service. synced reports whether the
+// initial sync has finished; the commitment waits for it so both do not
+// compete for the database.
+func NewService(ctx context.Context, db kv.RwDB, synced func() bool) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		db:     db,
+		synced: synced,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start loads the stored progress and starts following the head.
+func (s *Service) Start() error {
+	if err := s.loadProgress(); err != nil {
+		return err
+	}
+	if p := s.Progress(); p == nil {
+		log.Info("Building state commitment")
+	} else {
+		log.Info("Resuming state commitment", "number", p.Number, "root", p.Root)
+	}
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop interrupts the commitment. Committed batches are kept and the
+// commitment resumes from there on the next start.
+func (s *Service) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// Progress returns the last committed block, or nil before the first build.
+func (s *Service) Progress() *Progress {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.progress == nil {
+		return nil
+	}
+	p := *s.progress
+	return &p
+}
+
+func (s *Service) loadProgress() error {
+	var p *Progress
+	if err := s.db.View(s.ctx, func(tx kv.Tx) (err error) {
+		p, err = ReadProgress(tx)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to load commitment progress: %w", err)
+	}
+	s.setProgress(p)
+	return nil
+}
+
+func (s *Service) setProgress(p *Progress) {
+	s.mu.Lock()
+	s.progress = p
+	s.mu.Unlock()
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+	for {
+		wait := batchPause
+		if !s.synced() {
+			wait = syncPollInterval
+		} else {
+			caughtUp, err := s.step()
+			if err != nil {
+				log.Error("State commitment failed", "err", err)
+				return
+			}
+			if caughtUp {
+				wait = followInterval
+			}
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-logEvery.C:
+			if p := s.Progress(); p != nil {
+				log.Info("State commitment", "number", p.Number, "root", p.Root)
+			}
+		case <-time.After(wait):
+		}
+	}
+}
+
+// step commits the next batch of blocks. It reports whether the commitment
+// has caught up with the head.
+func (s *Service) step() (bool, error) {
+	p := s.Progress()
+	if p == nil {
+		return false, s.build()
+	}
+	var next *Progress
+	err := s.db.Update(s.ctx, func(tx kv.RwTx) error {
+		head, ok := readHead(tx)
+		if !ok {
+			next = p
+			return nil
+		}
+		if hash, err := rawdb.ReadCanonicalHash(tx, p.Number); err != nil {
+			return err
+		} else if hash != p.Hash {
+			return s.reset(tx, p)
+		}
+		scheme, err := readScheme(tx)
+		if err != nil {
+			return err
+		}
+		if scheme != p.Scheme {
+			log.Warn("State commitment scheme changed, rebuilding", "old", p.Scheme, "new", scheme)
+			return drop(tx)
+		}
+		if p.Number >= head {
+			next = p
+			return nil
+		}
+
+		enc, err := tx.GetOne(modules.DatabaseInfo, trieStateKey)
+		if err != nil {
+			return err
+		}
+		t, err := newStateTrie(scheme, dbBranches{tx})
+		if err != nil {
+			return err
+		}
+		if err := t.setState(enc); err != nil {
+			return fmt.Errorf("restore trie state: %w", err)
+		}
+		last := min(p.Number+batchSize, head)
+		cur := *p
+		for number := p.Number + 1; number <= last; number++ {
+			hash, err := rawdb.ReadCanonicalHash(tx, number)
+			if err != nil {
+				return err
+			}
+			keys, err := blockKeys(tx, number)
+			if err != nil {
+				return fmt.Errorf("block %d: %w", number, err)
+			}
+			root, err := t.update(tx, number+1, keys)
+			if err != nil {
+				return fmt.Errorf("block %d: %w", number, err)
+			}
+			if err := writeRoot(tx, number, hash, root); err != nil {
+				return err
+			}
+			cur = Progress{Number: number, Hash: hash, Root: root, Scheme: scheme}
+		}
+		if enc, err = t.encodeState(); err != nil {
+			return err
+		}
+		next = &cur
+		return writeProgress(tx, next, enc)
+	})
+	if err != nil {
+		return false, err
+	}
+	s.setProgress(next)
+	return next != nil && next == p, nil
+}
+
+// build computes the commitment of the current head from scratch. The trie
+// is built from a read transaction and only written once complete, so block
+// imports are not held up meanwhile.
+func (s *Service) build() error {
+	var (
+		p        *Progress
+		branches = make(memBranches)
+		enc      []byte
+	)
+	err := s.db.View(s.ctx, func(tx kv.Tx) error {
+		head, ok := readHead(tx)
+		if !ok {
+			return nil
+		}
+		hash, err := rawdb.ReadCanonicalHash(tx, head)
+		if err != nil {
+			return err
+		}
+		scheme, err := readScheme(tx)
+		if err != nil {
+			return err
+		}
+		t, err := newStateTrie(scheme, branches)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		keys, err := stateKeys(tx, head)
+		if err != nil {
+			return err
+		}
+		root, err := t.update(tx, head+1, keys)
+		if err != nil {
+			return err
+		}
+		if enc, err = t.encodeState(); err != nil {
+			return err
+		}
+		p = &Progress{Number: head, Hash: hash, Root: root, Scheme: scheme}
+		log.Info("Built state commitment", "number", head, "root", root, "scheme", scheme, "keys", len(keys), "elapsed", time.Since(start))
+		return nil
+	})
+	if err != nil || p == nil {
+		return err
+	}
+	err = s.db.Update(s.ctx, func(tx kv.RwTx) error {
+		if hash, err := rawdb.ReadCanonicalHash(tx, p.Number); err != nil {
+			return err
+		} else if hash != p.Hash {
+			// Reorged while building, start over.
+			p = nil
+			return nil
+		}
+		if err := tx.ClearBucket(modules.CommitmentBranch); err != nil {
+			return err
+		}
+		for prefix, data := range branches {
+			if err := tx.Put(modules.CommitmentBranch, []byte(prefix), data); err != nil {
+				return err
+			}
+		}
+		if err := writeRoot(tx, p.Number, p.Hash, p.Root); err != nil {
+			return err
+		}
+		return writeProgress(tx, p, enc)
+	})
+	if err != nil {
+		return err
+	}
+	s.setProgress(p)
+	return nil
+}
+
+// reset drops a commitment whose last block is no longer canonical. The
+// change sets of the abandoned blocks are gone, so the trie is rebuilt from
+// scratch; the roots of the blocks that are still canonical are kept.
+func (s *Service) reset(tx kv.RwTx, p *Progress) error {
+	number := p.Number
+	for ; ; number-- {
+		hash, root, ok, err := ReadRoot(tx, number)
+		if err != nil {
+			return err
+		}
+		if ok {
+			canonical, err := rawdb.ReadCanonicalHash(tx, number)
+			if err != nil {
+				return err
+			}
+			if canonical == hash {
+				break
+			}
+			if err := tx.Delete(modules.CommitmentRoot, modules.EncodeBlockNumber(number)); err != nil {
+				return err
+			}
+			log.Debug("Dropped non-canonical state root", "number", number, "hash", hash, "root", root)
+		}
+		if number == 0 {
+			break
+		}
+	}
+	log.Warn("State commitment reorged, rebuilding", "number", p.Number, "hash", p.Hash, "ancestor", number)
+	if err := tx.Delete(modules.DatabaseInfo, progressKey); err != nil {
+		return err
+	}
+	return tx.Delete(modules.DatabaseInfo, trieStateKey)
+}
+
+// drop deletes the whole commitment, including the roots of past blocks,
+// which were computed in another scheme.
+func drop(tx kv.RwTx) error {
+	if err := tx.ClearBucket(modules.CommitmentRoot); err != nil {
+		return err
+	}
+	if err := tx.Delete(modules.DatabaseInfo, progressKey); err != nil {
+		return err
+	}
+	return tx.Delete(modules.DatabaseInfo, trieStateKey)
+}
+
+// Unwind drops the roots of the blocks above target when the chain is rolled
+// back. A commitment that went past target can not be reverted without the
+// change sets of the removed blocks and is rebuilt from scratch.
+func Unwind(tx kv.RwTx, target uint64) error {
+	if err := tx.ForEach(modules.CommitmentRoot, modules.EncodeBlockNumber(target+1), func(k, _ []byte) error {
+		return tx.Delete(modules.CommitmentRoot, k)
+	}); err != nil {
+		return err
+	}
+	p, err := ReadProgress(tx)
+	if err != nil || p == nil || p.Number <= target {
+		return err
+	}
+	if err := tx.Delete(modules.DatabaseInfo, progressKey); err != nil {
+		return err
+	}
+	return tx.Delete(modules.DatabaseInfo, trieStateKey)
+}
+
+// readHead returns the number of the current head block.
+func readHead(tx kv.Tx) (uint64, bool) {
+	number := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadBlockHash(tx))
+	if number == nil {
+		return 0, false
+	}
+	return *number, true
+}
@@ -0,0 +1,382 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/commitment/verkle"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// testChain writes blocks whose only content is a set of state changes.
+type testChain struct {
+	t        *testing.T
+	db       kv.RwDB
+	accounts map[types.Address]*account.StateAccount
+	storage  map[types.Address]map[types.Hash]uint256.Int
+	head     uint64
+	scheme   params.CommitmentScheme
+}
+
+func newTestChain(t *testing.T, scheme params.CommitmentScheme) *testChain {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	return &testChain{
+		t:        t,
+		db:       memdb.NewTestDB(t),
+		scheme:   scheme,
+		accounts: make(map[types.Address]*account.StateAccount),
+		storage:  make(map[types.Address]map[types.Hash]uint256.Int),
+	}
+}
+
+// blockWriter applies the changes of one block.
+type blockWriter struct {
+	c *testChain
+	w *state.PlainStateWriter
+}
+
+func (b *blockWriter) setBalance(addr types.Address, balance uint64) {
+	original := &account.StateAccount{}
+	if acc, ok := b.c.accounts[addr]; ok {
+		original = acc.SelfCopy()
+	}
+	acc := original.SelfCopy()
+	acc.Initialised = true
+	acc.Balance.SetUint64(balance)
+	if err := b.w.UpdateAccountData(addr, original, acc); err != nil {
+		b.c.t.Fatal(err)
+	}
+	b.c.accounts[addr] = acc
+}
+
+func (b *blockWriter) createContract(addr types.Address, incarnation uint16) {
+	original := &account.StateAccount{}
+	if acc, ok := b.c.accounts[addr]; ok {
+		original = acc.SelfCopy()
+	}
+	acc := &account.StateAccount{Initialised: true, Nonce: 1, Incarnation: incarnation}
+	acc.CodeHash = crypto.Keccak256Hash([]byte{byte(incarnation)})
+	if err := b.w.UpdateAccountCode(addr, incarnation, acc.CodeHash, []byte{byte(incarnation)}); err != nil {
+		b.c.t.Fatal(err)
+	}
+	if err := b.w.UpdateAccountData(addr, original, acc); err != nil {
+		b.c.t.Fatal(err)
+	}
+	b.c.accounts[addr] = acc
+	b.c.storage[addr] = make(map[types.Hash]uint256.Int)
+}
+
+func (b *blockWriter) setStorage(addr types.Address, loc types.Hash, value uint64) {
+	acc := b.c.accounts[addr]
+	original := b.c.storage[addr][loc]
+	v := *uint256.NewInt(value)
+	if err := b.w.WriteAccountStorage(addr, acc.Incarnation, &loc, &original, &v); err != nil {
+		b.c.t.Fatal(err)
+	}
+	if value == 0 {
+		delete(b.c.storage[addr], loc)
+	} else {
+		b.c.storage[addr][loc] = v
+	}
+}
+
+func (b *blockWriter) destroy(addr types.Address) {
+	if err := b.w.DeleteAccount(addr, b.c.accounts[addr]); err != nil {
+		b.c.t.Fatal(err)
+	}
+	delete(b.c.accounts, addr)
+	delete(b.c.storage, addr)
+}
+
+// addBlock writes the next block, extra distinguishes forks.
+func (c *testChain) addBlock(number uint64, extra byte, changes func(b *blockWriter)) {
+	c.t.Helper()
+	if err := c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		b := &blockWriter{c: c, w: state.NewPlainStateWriter(tx, tx, number)}
+		changes(b)
+		if err := b.w.WriteChangeSets(); err != nil {
+			return err
+		}
+		if err := b.w.WriteHistory(); err != nil {
+			return err
+		}
+		header := &block.Header{Number: uint256.NewInt(number), Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0), Extra: []byte{extra}}
+		blk := block.NewBlock(header, nil).(*block.Block)
+		if err := rawdb.WriteBlock(tx, blk); err != nil {
+			return err
+		}
+		if err := rawdb.WriteCanonicalHash(tx, blk.Hash(), number); err != nil {
+			return err
+		}
+		if number == 0 {
+			if err := rawdb.WriteChainConfig(tx, blk.Hash(), &params.ChainConfig{StateCommitment: c.scheme}); err != nil {
+				return err
+			}
+		}
+		rawdb.WriteHeadBlockHash(tx, blk.Hash())
+		return nil
+	}); err != nil {
+		c.t.Fatal(err)
+	}
+	c.head = number
+}
+
+// checkRoots compares the committed root of every block from 'from' to the
+// head with a root computed from scratch.
+func (c *testChain) checkRoots(from uint64) {
+	c.t.Helper()
+	for number := from; number <= c.head; number++ {
+		c.checkRoot(number, true)
+	}
+}
+
+func (c *testChain) checkRoot(number uint64, committed bool) {
+	c.t.Helper()
+	if err := c.db.View(context.Background(), func(tx kv.Tx) error {
+		_, root, ok, err := ReadRoot(tx, number)
+		if err != nil {
+			return err
+		}
+		if ok != committed {
+			c.t.Fatalf("block %d: committed root present %v, want %v", number, ok, committed)
+		}
+		if !ok {
+			return nil
+		}
+		want, err := ComputeRoot(tx, number)
+		if err != nil {
+			return err
+		}
+		if root != want {
+			c.t.Errorf("block %d: committed root %x, recomputed %x", number, root, want)
+		}
+		return nil
+	}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func syncService(t *testing.T, s *Service) *Progress {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		caughtUp, err := s.step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if caughtUp {
+			return s.Progress()
+		}
+	}
+	t.Fatal("commitment did not catch up")
+	return nil
+}
+
+func loc(i int) types.Hash { return types.Hash{31: byte(i)} }
+
+func TestIncrementalCommitment(t *testing.T) {
+	t.Run("mpt", func(t *testing.T) { testIncrementalCommitment(t, params.HexPatriciaCommitment) })
+	t.Run("verkle", func(t *testing.T) { testIncrementalCommitment(t, params.VerkleCommitment) })
+}
+
+func testIncrementalCommitment(t *testing.T, scheme params.CommitmentScheme) {
+	c := newTestChain(t, scheme)
+	var (
+		alice    = types.HexToAddress("0x01")
+		bob      = types.HexToAddress("0x02")
+		contract = types.HexToAddress("0x03")
+	)
+	c.addBlock(0, 0, func(b *blockWriter) {
+		b.setBalance(alice, 100)
+		b.createContract(contract, 1)
+		for i := 1; i <= 20; i++ {
+			b.setStorage(contract, loc(i), uint64(i))
+		}
+	})
+	s := NewService(context.Background(), c.db, func() bool { return true })
+	if p := syncService(t, s); p.Number != 0 {
+		t.Fatalf("built at %d, want 0", p.Number)
+	}
+	c.checkRoots(0)
+
+	for n := uint64(1); n <= 30; n++ {
+		c.addBlock(n, 0, func(b *blockWriter) {
+			b.setBalance(alice, 100+n)
+			if n%3 == 0 {
+				b.setBalance(bob, n)
+			}
+			b.setStorage(contract, loc(int(n)), n*7)
+			if n%4 == 0 {
+				b.setStorage(contract, loc(int(n/2)), 0)
+			}
+		})
+	}
+	// Destroy the contract and recreate it with fresh storage.
+	c.addBlock(31, 0, func(b *blockWriter) { b.destroy(contract) })
+	c.addBlock(32, 0, func(b *blockWriter) {
+		b.createContract(contract, 2)
+		b.setStorage(contract, loc(1), 42)
+	})
+	// Destroy and recreate within one block: the block only records the
+	// incarnation change of the account.
+	c.addBlock(33, 0, func(b *blockWriter) {
+		b.setStorage(contract, loc(2), 5)
+		b.setStorage(contract, loc(3), 6)
+	})
+	c.addBlock(34, 0, func(b *blockWriter) {
+		b.createContract(contract, 3)
+		b.setStorage(contract, loc(3), 7)
+	})
+	if p := syncService(t, s); p.Number != c.head {
+		t.Fatalf("committed up to %d, want %d", p.Number, c.head)
+	}
+	c.checkRoots(0)
+
+	// Resume from the stored trie state.
+	s = NewService(context.Background(), c.db, func() bool { return true })
+	if err := s.loadProgress(); err != nil {
+		t.Fatal(err)
+	}
+	c.addBlock(35, 0, func(b *blockWriter) { b.setStorage(contract, loc(2), 3) })
+	syncService(t, s)
+	c.checkRoots(0)
+}
+
+func TestCommitmentReorg(t *testing.T) {
+	c := newTestChain(t, "")
+	alice := types.HexToAddress("0x01")
+	c.addBlock(0, 0, func(b *blockWriter) { b.setBalance(alice, 0) })
+	s := NewService(context.Background(), c.db, func() bool { return true })
+	syncService(t, s)
+	for n := uint64(1); n <= 5; n++ {
+		c.addBlock(n, 0, func(b *blockWriter) { b.setBalance(alice, n) })
+	}
+	syncService(t, s)
+
+	// Replace block 5 with a sibling and extend it.
+	if err := c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		return state.UnwindState(tx, 4)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c.accounts[alice].Balance.SetUint64(4)
+	for n := uint64(5); n <= 7; n++ {
+		c.addBlock(n, 1, func(b *blockWriter) { b.setBalance(alice, 1000+n) })
+	}
+	p := syncService(t, s)
+	if p.Number != 7 {
+		t.Fatalf("committed up to %d, want 7", p.Number)
+	}
+	// The roots of the abandoned blocks are dropped, the trie is rebuilt at
+	// the new head.
+	for n := uint64(0); n <= 4; n++ {
+		c.checkRoot(n, true)
+	}
+	c.checkRoot(5, false)
+	c.checkRoot(6, false)
+	c.checkRoot(7, true)
+}
+
+func TestCommitmentSchemeChange(t *testing.T) {
+	c := newTestChain(t, "")
+	alice := types.HexToAddress("0x01")
+	for n := uint64(0); n <= 2; n++ {
+		c.addBlock(n, 0, func(b *blockWriter) { b.setBalance(alice, n) })
+	}
+	s := NewService(context.Background(), c.db, func() bool { return true })
+	if p := syncService(t, s); p.Scheme != params.HexPatriciaCommitment {
+		t.Fatalf("built with scheme %q", p.Scheme)
+	}
+
+	c.scheme = params.VerkleCommitment
+	if err := c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		hash, err := rawdb.ReadCanonicalHash(tx, 0)
+		if err != nil {
+			return err
+		}
+		return rawdb.WriteChainConfig(tx, hash, &params.ChainConfig{StateCommitment: c.scheme})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c.addBlock(3, 0, func(b *blockWriter) { b.setBalance(alice, 3) })
+	if p := syncService(t, s); p.Scheme != params.VerkleCommitment || p.Number != 3 {
+		t.Fatalf("rebuilt with scheme %q at %d", p.Scheme, p.Number)
+	}
+	// The roots of the old scheme are gone.
+	for n := uint64(0); n <= 2; n++ {
+		c.checkRoot(n, false)
+	}
+	c.checkRoot(3, true)
+}
+
+func TestBuildWitness(t *testing.T) {
+	c := newTestChain(t, "")
+	var (
+		alice    = types.HexToAddress("0x01")
+		bob      = types.HexToAddress("0x02")
+		contract = types.HexToAddress("0x03")
+	)
+	c.addBlock(0, 0, func(b *blockWriter) {
+		b.setBalance(alice, 100)
+		b.createContract(contract, 1)
+		b.setStorage(contract, loc(1), 1)
+		b.setStorage(contract, loc(100), 2)
+	})
+	c.addBlock(1, 0, func(b *blockWriter) { b.setBalance(bob, 5) })
+
+	keys := [][]byte{alice.Bytes(), bob.Bytes(), append(contract.Bytes(), loc(100).Bytes()...), append(contract.Bytes(), loc(2).Bytes()...)}
+	if err := c.db.View(context.Background(), func(tx kv.Tx) error {
+		w, err := BuildWitness(tx, 1, keys)
+		if err != nil {
+			return err
+		}
+		if err := w.Verify(); err != nil {
+			return err
+		}
+		balance, err := w.Get(verkle.HeaderKey(alice, verkle.BalanceLeafKey))
+		if err != nil {
+			return err
+		}
+		if len(balance) == 0 || balance[0] != 100 {
+			t.Errorf("alice balance %x", balance)
+		}
+		for _, key := range [][]byte{verkle.HeaderKey(bob, verkle.BalanceLeafKey), verkle.StorageKey(contract, loc(2))} {
+			if v, err := w.Get(key); err != nil || v != nil {
+				t.Errorf("key %x: have %x, %v, want absent", key, v, err)
+			}
+		}
+		if v, err := w.Get(verkle.StorageKey(contract, loc(100))); err != nil || v[31] != 2 {
+			t.Errorf("storage: have %x, %v", v, err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	patricia "github.com/ledgerwatch/erigon-lib/commitment"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/changeset"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// branchStore holds the branch nodes of the trie, keyed by their compact
+// nibble path. Values keep the touch and after bitmaps written by the trie.
+type branchStore interface {
+	branch(prefix []byte) ([]byte, error)
+	putBranch(prefix, data []byte) error
+	deleteBranch(prefix []byte) error
+}
+
+// dbBranches keeps the branch nodes in the CommitmentBranch table.
+type dbBranches struct {
+	tx kv.RwTx
+}
+
+func (b dbBranches) branch(prefix []byte) ([]byte, error) {
+	return b.tx.GetOne(modules.CommitmentBranch, prefix)
+}
+
+func (b dbBranches) putBranch(prefix, data []byte) error {
+	return b.tx.Put(modules.CommitmentBranch, prefix, data)
+}
+
+func (b dbBranches) deleteBranch(prefix []byte) error {
+	return b.tx.Delete(modules.CommitmentBranch, prefix)
+}
+
+// memBranches keeps the branch nodes in memory, for one-off computations.
+type memBranches map[string][]byte
+
+func (b memBranches) branch(prefix []byte) ([]byte, error) {
+	return b[string(prefix)], nil
+}
+
+func (b memBranches) putBranch(prefix, data []byte) error {
+	b[string(prefix)] = data
+	return nil
+}
+
+func (b memBranches) deleteBranch(prefix []byte) error {
+	delete(b, string(prefix))
+	return nil
+}
+
+// stateTrie is a state commitment scheme.
+type stateTrie interface {
+	// setState restores the in-memory state saved by encodeState.
+	setState(enc []byte) error
+	encodeState() ([]byte, error)
+	// update applies the given account (20 bytes) and storage (address +
+	// location) keys as of the start of block asOf and returns the new root.
+	update(tx kv.Tx, asOf uint64, plainKeys [][]byte) (types.Hash, error)
+}
+
+// newStateTrie returns the trie of scheme kept in branches.
+func newStateTrie(scheme params.CommitmentScheme, branches branchStore) (stateTrie, error) {
+	switch scheme {
+	case params.HexPatriciaCommitment:
+		return newTrie(branches), nil
+	case params.VerkleCommitment:
+		return newVerkleTrie(branches), nil
+	}
+	return nil, fmt.Errorf("unknown state commitment scheme %q", scheme)
+}
+
+// readScheme returns the commitment scheme configured for the chain.
+func readScheme(tx kv.Getter) (params.CommitmentScheme, error) {
+	genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+	if err != nil {
+		return "", err
+	}
+	if genesisHash == (types.Hash{}) {
+		return params.HexPatriciaCommitment, nil
+	}
+	config, err := rawdb.ReadChainConfig(tx, genesisHash)
+	if err != nil {
+		return "", err
+	}
+	return config.CommitmentScheme(), nil
+}
+
+// trie computes the hex patricia root of the state. Only the keys passed to
+// update are re-hashed; the rest of the trie is loaded from the branch store.
+type trie struct {
+	hph      *patricia.HexPatriciaHashed
+	branches branchStore
+
+	// state and incarnations serve the account and storage values as of
+	// the block being committed.
+	state        *state.PlainState
+	incarnations map[types.Address]uint16
+}
+
+func newTrie(branches branchStore) *trie {
+	t := &trie{branches: branches}
+	t.hph = patricia.NewHexPatriciaHashed(length.Addr, t.branchFn, t.accountFn, t.storageFn)
+	return t
+}
+
+// setState restores the root of a trie encoded by encodeState.
+func (t *trie) setState(enc []byte) error {
+	if len(enc) == 0 {
+		return nil
+	}
+	return t.hph.SetState(enc)
+}
+
+func (t *trie) encodeState() ([]byte, error) {
+	return t.hph.EncodeCurrentState(nil)
+}
+
+// update re-hashes the given account (20 bytes) and storage (address +
+// location) keys against the state as of the start of block asOf, stores the
+// changed branch nodes and returns the new root.
+func (t *trie) update(tx kv.Tx, asOf uint64, plainKeys [][]byte) (types.Hash, error) {
+	t.state = state.NewPlainState(tx, asOf)
+	t.incarnations = make(map[types.Address]uint16)
+	defer func() { t.state, t.incarnations = nil, nil }()
+
+	hashedKeys := make([][]byte, len(plainKeys))
+	for i, key := range plainKeys {
+		hashedKeys[i] = hashKey(key)
+	}
+	sort.Sort(&keySorter{plain: plainKeys, hashed: hashedKeys})
+
+	root, updates, err := t.hph.ReviewKeys(plainKeys, hashedKeys)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	for prefix, update := range updates {
+		pre, err := t.branches.branch([]byte(prefix))
+		if err != nil {
+			return types.Hash{}, err
+		}
+		if len(pre) > 0 {
+			if update, err = patricia.BranchData(pre).MergeHexBranches(update, nil); err != nil {
+				return types.Hash{}, fmt.Errorf("merge branch %x: %w", prefix, err)
+			}
+		}
+		if err := t.branches.putBranch([]byte(prefix), update); err != nil {
+			return types.Hash{}, err
+		}
+	}
+	return types.BytesToHash(root), nil
+}
+
+func (t *trie) branchFn(prefix []byte) ([]byte, error) {
+	data, err := t.branches.branch(prefix)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	// Skip the touch map, the trie reads the after map as the bitmap.
+	return data[2:], nil
+}
+
+func (t *trie) accountFn(plainKey []byte, cell *patricia.Cell) error {
+	addr := types.BytesToAddress(plainKey)
+	acc, err := t.state.ReadAccountData(addr)
+	if err != nil {
+		return fmt.Errorf("read account %x: %w", plainKey, err)
+	}
+	if acc == nil {
+		cell.Delete = true
+		return nil
+	}
+	t.incarnations[addr] = acc.Incarnation
+	cell.Balance.Set(&acc.Balance)
+	cell.Nonce = acc.Nonce
+	if acc.IsEmptyCodeHash() {
+		copy(cell.CodeHash[:], patricia.EmptyCodeHash)
+	} else {
+		copy(cell.CodeHash[:], acc.CodeHash[:])
+	}
+	return nil
+}
+
+func (t *trie) storageFn(plainKey []byte, cell *patricia.Cell) error {
+	addr := types.BytesToAddress(plainKey[:length.Addr])
+	incarnation, ok := t.incarnations[addr]
+	if !ok {
+		acc, err := t.state.ReadAccountData(addr)
+		if err != nil {
+			return fmt.Errorf("read account %x: %w", addr, err)
+		}
+		if acc != nil {
+			incarnation = acc.Incarnation
+		}
+		t.incarnations[addr] = incarnation
+	}
+	var value []byte
+	if incarnation > 0 {
+		loc := types.BytesToHash(plainKey[length.Addr:])
+		v, err := t.state.ReadAccountStorage(addr, incarnation, &loc)
+		if err != nil {
+			return fmt.Errorf("read storage %x: %w", plainKey, err)
+		}
+		value = v
+	}
+	if len(value) == 0 {
+		cell.Delete = true
+		return nil
+	}
+	cell.StorageLen = copy(cell.Storage[:], value)
+	return nil
+}
+
+// hashKey returns the nibbles of the hashed account key, followed by those
+// of the hashed storage location for storage keys.
+func hashKey(plainKey []byte) []byte {
+	nibbles := make([]byte, 0, 4*length.Hash)
+	nibbles = appendNibbles(nibbles, crypto.Keccak256(plainKey[:length.Addr]))
+	if len(plainKey) > length.Addr {
+		nibbles = appendNibbles(nibbles, crypto.Keccak256(plainKey[length.Addr:]))
+	}
+	return nibbles
+}
+
+func appendNibbles(dst, hash []byte) []byte {
+	for _, b := range hash {
+		dst = append(dst, b>>4, b&0x0f)
+	}
+	return dst
+}
+
+// keySorter orders plain keys by their hashed keys, as the trie expects.
+type keySorter struct {
+	plain, hashed [][]byte
+}
+
+func (s *keySorter) Len() int { return len(s.hashed) }
+
+func (s *keySorter) Less(i, j int) bool { return bytes.Compare(s.hashed[i], s.hashed[j]) < 0 }
+
+func (s *keySorter) Swap(i, j int) {
+	s.plain[i], s.plain[j] = s.plain[j], s.plain[i]
+	s.hashed[i], s.hashed[j] = s.hashed[j], s.hashed[i]
+}
+
+// blockKeys returns the account and storage keys changed by block number.
+// Storage of accounts that were destroyed or recreated in the block is
+// included in full, as it is dropped together with the old incarnation.
+func blockKeys(tx kv.Tx, number uint64) ([][]byte, error) {
+	var (
+		keys    [][]byte
+		seen    = make(map[string]struct{})
+		changed = make(map[types.Address]uint16)
+	)
+	add := func(key []byte) {
+		if _, ok := seen[string(key)]; !ok {
+			seen[string(key)] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	if err := changeset.ForRange(tx, modules.AccountChangeSet, number, number+1, func(_ uint64, k, v []byte) error {
+		var original account.StateAccount
+		if err := original.DecodeForStorage(v); err != nil {
+			return fmt.Errorf("decode account change %x: %w", k, err)
+		}
+		add(types.CopyBytes(k))
+		changed[types.BytesToAddress(k)] = original.Incarnation
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := changeset.ForRange(tx, modules.StorageChangeSet, number, number+1, func(_ uint64, k, _ []byte) error {
+		key := make([]byte, 0, length.Addr+length.Hash)
+		key = append(append(key, k[:length.Addr]...), k[length.Addr+modules.Incarnation:]...)
+		add(key)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	after := state.NewPlainState(tx, number+1)
+	for addr, incarnation := range changed {
+		if incarnation == 0 {
+			continue
+		}
+		acc, err := after.ReadAccountData(addr)
+		if err != nil {
+			return nil, err
+		}
+		if acc != nil && acc.Incarnation == incarnation {
+			continue
+		}
+		if err := state.WalkAsOfStorage(tx, addr, incarnation, types.Hash{}, number, func(_, loc, _ []byte) (bool, error) {
+			add(append(addr.Bytes(), loc...))
+			return true, nil
+		}); err != nil {
+			return nil, fmt.Errorf("walk storage of %x: %w", addr, err)
+		}
+	}
+	return keys, nil
+}
+
+// stateKeys returns every account and storage key of the state after block
+// number.
+func stateKeys(tx kv.Tx, number uint64) ([][]byte, error) {
+	var keys [][]byte
+	asOf := number + 1
+	err := state.WalkAsOfAccounts(tx, types.Address{}, asOf, func(k, v []byte) (bool, error) {
+		if len(k) != length.Addr {
+			return true, nil
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(v); err != nil {
+			return false, fmt.Errorf("decode account %x: %w", k, err)
+		}
+		keys = append(keys, types.CopyBytes(k))
+		if acc.Incarnation == 0 {
+			return true, nil
+		}
+		addr := types.BytesToAddress(k)
+		return true, state.WalkAsOfStorage(tx, addr, acc.Incarnation, types.Hash{}, asOf, func(_, loc, v []byte) (bool, error) {
+			if len(v) > 0 {
+				keys = append(keys, append(addr.Bytes(), loc...))
+			}
+			return true, nil
+		})
+	})
+	return keys, err
+}
+
+// ComputeRoot recomputes the state root after block number from scratch,
+// in the scheme configured for the chain, without using the stored
+// commitment.
+func ComputeRoot(tx kv.Tx, number uint64) (types.Hash, error) {
+	scheme, err := readScheme(tx)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	t, err := newStateTrie(scheme, make(memBranches))
+	if err != nil {
+		return types.Hash{}, err
+	}
+	keys, err := stateKeys(tx, number)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return t.update(tx, number+1, keys)
+}
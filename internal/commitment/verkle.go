@@ -0,0 +1,182 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"fmt"
+
+	patricia "github.com/ledgerwatch/erigon-lib/commitment"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/commitment/verkle"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// verklePrefix keeps the Verkle nodes apart from patricia branches and gives
+// the root a non-empty key.
+const verklePrefix = 'v'
+
+// verkleNodes stores the Verkle tree nodes in a branch store.
+type verkleNodes struct {
+	branches branchStore
+}
+
+func (n verkleNodes) Node(path []byte) ([]byte, error) {
+	return n.branches.branch(verkleKey(path))
+}
+
+func (n verkleNodes) PutNode(path, data []byte) error {
+	return n.branches.putBranch(verkleKey(path), data)
+}
+
+func (n verkleNodes) DeleteNode(path []byte) error {
+	return n.branches.deleteBranch(verkleKey(path))
+}
+
+func verkleKey(path []byte) []byte {
+	return append([]byte{verklePrefix}, path...)
+}
+
+// verkleTrie commits to the state in a Verkle tree. All of its state lives
+// in the node store, so it has nothing to save in between batches.
+type verkleTrie struct {
+	tree *verkle.Tree
+}
+
+func newVerkleTrie(branches branchStore) *verkleTrie {
+	return &verkleTrie{tree: verkle.New(verkleNodes{branches})}
+}
+
+func (t *verkleTrie) setState([]byte) error { return nil }
+
+func (t *verkleTrie) encodeState() ([]byte, error) { return nil, nil }
+
+func (t *verkleTrie) update(tx kv.Tx, asOf uint64, plainKeys [][]byte) (types.Hash, error) {
+	reader := state.NewPlainState(tx, asOf)
+	incarnations := make(map[types.Address]uint16)
+	incarnation := func(addr types.Address) (uint16, error) {
+		if inc, ok := incarnations[addr]; ok {
+			return inc, nil
+		}
+		acc, err := reader.ReadAccountData(addr)
+		if err != nil || acc == nil {
+			return 0, err
+		}
+		incarnations[addr] = acc.Incarnation
+		return acc.Incarnation, nil
+	}
+
+	for _, key := range plainKeys {
+		addr := types.BytesToAddress(key[:length.Addr])
+		if len(key) == length.Addr {
+			acc, err := reader.ReadAccountData(addr)
+			if err != nil {
+				return types.Hash{}, fmt.Errorf("read account %x: %w", addr, err)
+			}
+			var values [][]byte
+			if acc != nil {
+				codeHash, codeSize := types.BytesToHash(patricia.EmptyCodeHash), 0
+				if !acc.IsEmptyCodeHash() {
+					codeHash = acc.CodeHash
+					if codeSize, err = reader.ReadAccountCodeSize(addr, acc.Incarnation, acc.CodeHash); err != nil {
+						return types.Hash{}, fmt.Errorf("read code of %x: %w", addr, err)
+					}
+				}
+				values = verkle.AccountValues(acc.Nonce, &acc.Balance, codeHash, codeSize)
+			}
+			for i, k := range verkleKeys(key) {
+				if values == nil {
+					err = t.tree.Delete(k)
+				} else {
+					err = t.tree.Insert(k, values[i])
+				}
+				if err != nil {
+					return types.Hash{}, err
+				}
+			}
+			continue
+		}
+
+		loc := types.BytesToHash(key[length.Addr:])
+		inc, err := incarnation(addr)
+		if err != nil {
+			return types.Hash{}, fmt.Errorf("read account %x: %w", addr, err)
+		}
+		var value []byte
+		if inc > 0 {
+			if value, err = reader.ReadAccountStorage(addr, inc, &loc); err != nil {
+				return types.Hash{}, fmt.Errorf("read storage %x: %w", key, err)
+			}
+		}
+		k := verkle.StorageKey(addr, loc)
+		if len(value) == 0 {
+			err = t.tree.Delete(k)
+		} else {
+			err = t.tree.Insert(k, verkle.StorageValue(value))
+		}
+		if err != nil {
+			return types.Hash{}, err
+		}
+	}
+	root, err := t.tree.Root()
+	return types.Hash(root), err
+}
+
+// verkleKeys returns the Verkle keys of a plain account or storage key. The
+// keys of an account are those of its header values, in suffix order.
+func verkleKeys(plainKey []byte) [][]byte {
+	addr := types.BytesToAddress(plainKey[:length.Addr])
+	if len(plainKey) > length.Addr {
+		return [][]byte{verkle.StorageKey(addr, types.BytesToHash(plainKey[length.Addr:]))}
+	}
+	stem := verkle.HeaderKey(addr, 0)[:verkle.StemSize]
+	keys := make([][]byte, 0, verkle.CodeSizeLeafKey+1)
+	for suffix := verkle.VersionLeafKey; suffix <= verkle.CodeSizeLeafKey; suffix++ {
+		keys = append(keys, append(append([]byte{}, stem...), byte(suffix)))
+	}
+	return keys
+}
+
+// BuildWitness builds the Verkle tree of the state at the start of block
+// number and returns the witness of the given plain keys, typically those
+// read by the block, and of the keys the block changed. The tree is built
+// from scratch in memory, so it works regardless of the configured
+// commitment scheme.
+func BuildWitness(tx kv.Tx, number uint64, plainKeys [][]byte) (*verkle.Witness, error) {
+	if number == 0 {
+		return nil, fmt.Errorf("genesis block has no pre-state")
+	}
+	changed, err := blockKeys(tx, number)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := stateKeys(tx, number-1)
+	if err != nil {
+		return nil, err
+	}
+	t := newVerkleTrie(make(memBranches))
+	if _, err := t.update(tx, number, keys); err != nil {
+		return nil, err
+	}
+	var witnessKeys [][]byte
+	for _, key := range append(plainKeys, changed...) {
+		witnessKeys = append(witnessKeys, verkleKeys(key)...)
+	}
+	return t.tree.Witness(witnessKeys)
+}
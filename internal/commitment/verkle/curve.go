@@ -0,0 +1,194 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Banderwagon is the prime order quotient group of the Bandersnatch twisted
+// Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2 over the scalar field of
+// BLS12-381, where (x, y) and (-x, -y) are the same element. Points use the
+// Montgomery field of field.go, encodings and curve checks use math/big.
+var (
+	fieldP = fromHex("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	// scalarR is the order of the group.
+	scalarR = fromHex("1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1")
+
+	curveA = new(big.Int).Sub(fieldP, big.NewInt(5))
+	curveD = fromHex("6389c12633c267cbc66e3bf86be3b6d8cb66677177e54f92b369f2f5188d58e7")
+
+	halfP = new(big.Int).Rsh(fieldP, 1)
+	one   = big.NewInt(1)
+
+	errNotOnCurve = errors.New("verkle: point is not on the curve")
+)
+
+func fromHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("verkle: invalid constant " + s)
+	}
+	return v
+}
+
+func fmul(a, b *big.Int) *big.Int {
+	v := new(big.Int).Mul(a, b)
+	return v.Mod(v, fieldP)
+}
+
+func fadd(a, b *big.Int) *big.Int {
+	v := new(big.Int).Add(a, b)
+	return v.Mod(v, fieldP)
+}
+
+func fsub(a, b *big.Int) *big.Int {
+	v := new(big.Int).Sub(a, b)
+	return v.Mod(v, fieldP)
+}
+
+// point is a group element in projective coordinates (X:Y:Z), representing
+// the affine point (X/Z, Y/Z).
+type point struct {
+	x, y, z fe
+}
+
+func identity() *point {
+	return &point{x: feZero, y: feOne, z: feOne}
+}
+
+func newPoint(x, y *big.Int) *point {
+	return &point{x: feFromBig(x), y: feFromBig(y), z: feOne}
+}
+
+// add returns p+q. The unified formula also doubles.
+func (p *point) add(q *point) *point {
+	a := feMul(p.z, q.z)
+	b := feMul(a, a)
+	c := feMul(p.x, q.x)
+	d := feMul(p.y, q.y)
+	e := feMul(feD, feMul(c, d))
+	f := feSub(b, e)
+	g := feAdd(b, e)
+	h := feSub(feSub(feMul(feAdd(p.x, p.y), feAdd(q.x, q.y)), c), d)
+	return &point{
+		x: feMul(feMul(a, f), h),
+		y: feMul(feMul(a, g), feSub(d, feMul(feA, c))),
+		z: feMul(f, g),
+	}
+}
+
+// mul returns k*p.
+func (p *point) mul(k *big.Int) *point {
+	k = new(big.Int).Mod(k, scalarR)
+	res := identity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		res = res.add(res)
+		if k.Bit(i) == 1 {
+			res = res.add(p)
+		}
+	}
+	return res
+}
+
+// affine returns the affine coordinates of p.
+func (p *point) affine() (x, y *big.Int) {
+	inv := new(big.Int).ModInverse(p.z.big(), fieldP)
+	return fmul(p.x.big(), inv), fmul(p.y.big(), inv)
+}
+
+// equal reports whether p and q are the same group element, taking the
+// (x, y) ~ (-x, -y) equivalence into account.
+func (p *point) equal(q *point) bool {
+	return feMul(p.x, q.y) == feMul(q.x, p.y)
+}
+
+// bytes serializes p as the x coordinate of the representative whose y is
+// lexicographically largest, big-endian.
+func (p *point) bytes() [32]byte {
+	x, y := p.affine()
+	if y.Cmp(halfP) <= 0 {
+		x = fsub(new(big.Int), x)
+	}
+	var out [32]byte
+	x.FillBytes(out[:])
+	return out
+}
+
+// field maps p to a scalar, x/y reduced modulo the group order. Parent
+// commitments commit to the field values of their children.
+func (p *point) field() *big.Int {
+	x, y := p.affine()
+	v := fmul(x, new(big.Int).ModInverse(y, fieldP))
+	return v.Mod(v, scalarR)
+}
+
+// encode stores p as the affine coordinates of the same representative
+// bytes picks, so that equal elements encode alike.
+func (p *point) encode() []byte {
+	x, y := p.affine()
+	if y.Cmp(halfP) <= 0 {
+		x, y = fsub(new(big.Int), x), fsub(new(big.Int), y)
+	}
+	out := make([]byte, 64)
+	x.FillBytes(out[:32])
+	y.FillBytes(out[32:])
+	return out
+}
+
+func decodePoint(b []byte) (*point, error) {
+	if len(b) != 64 {
+		return nil, errNotOnCurve
+	}
+	x, y := new(big.Int).SetBytes(b[:32]), new(big.Int).SetBytes(b[32:])
+	if x.Cmp(fieldP) >= 0 || y.Cmp(fieldP) >= 0 || !onCurve(x, y) {
+		return nil, errNotOnCurve
+	}
+	return newPoint(x, y), nil
+}
+
+func onCurve(x, y *big.Int) bool {
+	x2, y2 := fmul(x, x), fmul(y, y)
+	return fadd(fmul(curveA, x2), y2).Cmp(fadd(one, fmul(curveD, fmul(x2, y2)))) == 0
+}
+
+// pointFromX returns the group element serialized as x, or false if x is
+// not a valid serialization.
+func pointFromX(x *big.Int) (*point, bool) {
+	if x.Cmp(fieldP) >= 0 {
+		return nil, false
+	}
+	x2 := fmul(x, x)
+	num := fsub(one, fmul(curveA, x2))
+	// Only elements of the prime order subgroup have a square 1 - a*x^2.
+	if big.Jacobi(num, fieldP) != 1 {
+		return nil, false
+	}
+	den := fsub(one, fmul(curveD, x2))
+	if den.Sign() == 0 {
+		return nil, false
+	}
+	y := new(big.Int).ModSqrt(fmul(num, new(big.Int).ModInverse(den, fieldP)), fieldP)
+	if y == nil {
+		return nil, false
+	}
+	if y.Cmp(halfP) <= 0 {
+		y = fsub(new(big.Int), y)
+	}
+	return newPoint(x, y), true
+}
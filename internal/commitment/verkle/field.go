@@ -0,0 +1,158 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// fe is an element of the base field in Montgomery form, as little-endian
+// 64-bit limbs. It backs the point arithmetic; conversions to math/big are
+// only done at the edges.
+type fe [4]uint64
+
+var (
+	feModulus = limbs(fieldP)
+	// feInv is -p^-1 mod 2^64.
+	feInv = func() uint64 {
+		m := new(big.Int).Lsh(one, 64)
+		v := new(big.Int).ModInverse(new(big.Int).Mod(fieldP, m), m)
+		return new(big.Int).Sub(m, v).Uint64()
+	}()
+	// feR2 is R^2 mod p with R = 2^256, used to enter Montgomery form.
+	feR2 = limbs(new(big.Int).Mod(new(big.Int).Lsh(one, 512), fieldP))
+
+	feOne  = feFromBig(one)
+	feA    = feFromBig(curveA)
+	feD    = feFromBig(curveD)
+	feZero fe
+)
+
+// limbs splits v < 2^256 into little-endian limbs.
+func limbs(v *big.Int) fe {
+	var buf [32]byte
+	v.FillBytes(buf[:])
+	var z fe
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			z[i] |= uint64(buf[31-8*i-j]) << (8 * j)
+		}
+	}
+	return z
+}
+
+func feFromBig(v *big.Int) fe {
+	z := limbs(new(big.Int).Mod(v, fieldP))
+	return feMul(z, feR2)
+}
+
+func (z fe) big() *big.Int {
+	plain := feMul(z, fe{1})
+	var buf [32]byte
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			buf[31-8*i-j] = byte(plain[i] >> (8 * j))
+		}
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// feMul returns x*y/R mod p.
+func feMul(x, y fe) fe {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var carry uint64
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j], c = lo, hi
+		}
+		var carry uint64
+		t[4], carry = bits.Add64(t[4], c, 0)
+		t[5] = carry
+
+		m := t[0] * feInv
+		hi, lo := bits.Mul64(m, feModulus[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, feModulus[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1], c = lo, hi
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + carry
+	}
+	z := fe{t[0], t[1], t[2], t[3]}
+	if t[4] != 0 || !z.less(feModulus) {
+		z = z.subRaw(feModulus)
+	}
+	return z
+}
+
+func feAdd(x, y fe) fe {
+	var z fe
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	if carry != 0 || !z.less(feModulus) {
+		z = z.subRaw(feModulus)
+	}
+	return z
+}
+
+func feSub(x, y fe) fe {
+	var z fe
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	if borrow != 0 {
+		var carry uint64
+		for i := 0; i < 4; i++ {
+			z[i], carry = bits.Add64(z[i], feModulus[i], carry)
+		}
+	}
+	return z
+}
+
+// less reports whether z < y as plain integers.
+func (z fe) less(y fe) bool {
+	for i := 3; i >= 0; i-- {
+		if z[i] != y[i] {
+			return z[i] < y[i]
+		}
+	}
+	return false
+}
+
+func (z fe) subRaw(y fe) fe {
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		z[i], borrow = bits.Sub64(z[i], y[i], borrow)
+	}
+	return z
+}
@@ -0,0 +1,113 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// Suffixes of the account header values, which share a leaf with the first
+// storage slots. Code chunks (EIP-6800 suffixes 128-255 and the code tree
+// indices) are not committed to by this prototype.
+const (
+	VersionLeafKey = iota
+	BalanceLeafKey
+	NonceLeafKey
+	CodeHashLeafKey
+	CodeSizeLeafKey
+
+	headerStorageOffset = 64
+	codeOffset          = 128
+)
+
+// mainStorageOffset is where storage slots past the header ones start,
+// 256^31.
+var mainStorageOffset = new(big.Int).Lsh(one, 8*StemSize)
+
+// TreeKey returns the key of the value at subIndex of the stem derived from
+// the address and tree index, as in EIP-6800.
+func TreeKey(addr types.Address, treeIndex *big.Int, subIndex byte) []byte {
+	var input [64]byte
+	copy(input[32-types.AddressLength:32], addr[:])
+	var index [32]byte
+	treeIndex.FillBytes(index[:])
+	for i := range index {
+		input[32+i] = index[31-i]
+	}
+	values := make([]*big.Int, 5)
+	values[0] = big.NewInt(2 + 256*64)
+	for i := 0; i < 4; i++ {
+		values[i+1] = leScalar(input[16*i : 16*(i+1)])
+	}
+	field := commit(values).field().FillBytes(make([]byte, 32))
+	key := make([]byte, KeySize)
+	for i := 0; i < StemSize; i++ {
+		key[i] = field[31-i]
+	}
+	key[StemSize] = subIndex
+	return key
+}
+
+// HeaderKey returns the key of an account header value.
+func HeaderKey(addr types.Address, suffix byte) []byte {
+	return TreeKey(addr, zero, suffix)
+}
+
+// StorageKey returns the key of a storage slot.
+func StorageKey(addr types.Address, slot types.Hash) []byte {
+	pos := new(big.Int).SetBytes(slot[:])
+	if pos.Cmp(big.NewInt(codeOffset-headerStorageOffset)) < 0 {
+		pos.Add(pos, big.NewInt(headerStorageOffset))
+	} else {
+		pos.Add(pos, mainStorageOffset)
+	}
+	suffix := byte(new(big.Int).Mod(pos, big.NewInt(NodeWidth)).Uint64())
+	return TreeKey(addr, pos.Rsh(pos, 8), suffix)
+}
+
+// AccountValues returns the header values of an account, indexed by suffix.
+func AccountValues(nonce uint64, balance *uint256.Int, codeHash types.Hash, codeSize int) [][]byte {
+	values := make([][]byte, CodeSizeLeafKey+1)
+	values[VersionLeafKey] = make([]byte, ValueSize)
+	values[BalanceLeafKey] = littleEndian(balance.Bytes32())
+	values[NonceLeafKey] = make([]byte, ValueSize)
+	binary.LittleEndian.PutUint64(values[NonceLeafKey], nonce)
+	values[CodeHashLeafKey] = append([]byte(nil), codeHash[:]...)
+	values[CodeSizeLeafKey] = make([]byte, ValueSize)
+	binary.LittleEndian.PutUint64(values[CodeSizeLeafKey], uint64(codeSize))
+	return values
+}
+
+// StorageValue returns the leaf value of a storage slot value.
+func StorageValue(value []byte) []byte {
+	out := make([]byte, ValueSize)
+	copy(out[ValueSize-len(value):], value)
+	return out
+}
+
+func littleEndian(be [32]byte) []byte {
+	out := make([]byte, 32)
+	for i, b := range be {
+		out[31-i] = b
+	}
+	return out
+}
@@ -0,0 +1,250 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// StemSize is the length of the key prefix shared by the values of a
+	// leaf. The last key byte selects the value.
+	StemSize = 31
+	// KeySize is the length of a tree key.
+	KeySize = StemSize + 1
+	// ValueSize is the length of a leaf value.
+	ValueSize = 32
+
+	innerType = 1
+	leafType  = 2
+)
+
+var (
+	errInvalidNode = errors.New("verkle: invalid node encoding")
+
+	zero = new(big.Int)
+	// leafMarker distinguishes a stored zero value from an absent one.
+	leafMarker = new(big.Int).Lsh(one, 128)
+)
+
+// innerNode commits to the field values of up to NodeWidth children.
+type innerNode struct {
+	commitment *point
+	children   [NodeWidth]*big.Int // nil for an empty child
+}
+
+func newInner() *innerNode {
+	return &innerNode{commitment: identity()}
+}
+
+// setChild replaces the field value of a child, nil removes the child.
+func (n *innerNode) setChild(index byte, field *big.Int) {
+	old := n.children[index]
+	if old == nil {
+		old = zero
+	}
+	cur := field
+	if cur == nil {
+		cur = zero
+	}
+	n.commitment = shift(n.commitment, int(index), old, cur)
+	n.children[index] = field
+}
+
+// only returns the index of the single child of n, or false if n has none
+// or several.
+func (n *innerNode) only() (byte, bool) {
+	index, count := 0, 0
+	for i, c := range n.children {
+		if c != nil {
+			index = i
+			count++
+		}
+	}
+	return byte(index), count == 1
+}
+
+func (n *innerNode) empty() bool {
+	for _, c := range n.children {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// leafNode holds the values of the keys sharing a stem. Values 0-127 are
+// committed to in c1 and 128-255 in c2, each as two 128-bit halves.
+type leafNode struct {
+	stem       []byte
+	values     [NodeWidth][]byte
+	c1, c2     *point
+	commitment *point
+}
+
+func newLeaf(stem []byte) *leafNode {
+	n := &leafNode{stem: append([]byte(nil), stem...), c1: identity(), c2: identity()}
+	n.commitment = commit([]*big.Int{one, stemScalar(stem)})
+	return n
+}
+
+// setValue sets or, for a nil value, removes the value at suffix.
+func (n *leafNode) setValue(suffix byte, value []byte) {
+	oldLo, oldHi := valueScalars(n.values[suffix])
+	lo, hi := valueScalars(value)
+	n.values[suffix] = value
+
+	part, index := &n.c1, 2
+	if suffix >= NodeWidth/2 {
+		part, index = &n.c2, 3
+	}
+	before := (*part).field()
+	pos := 2 * (int(suffix) % (NodeWidth / 2))
+	*part = shift(shift(*part, pos, oldLo, lo), pos+1, oldHi, hi)
+	n.commitment = shift(n.commitment, index, before, (*part).field())
+}
+
+func (n *leafNode) empty() bool {
+	for _, v := range n.values {
+		if v != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// valueScalars splits a value into the two scalars it is committed as.
+func valueScalars(value []byte) (lo, hi *big.Int) {
+	if value == nil {
+		return zero, zero
+	}
+	lo = leScalar(value[:ValueSize/2])
+	lo.Or(lo, leafMarker)
+	return lo, leScalar(value[ValueSize/2:])
+}
+
+func stemScalar(stem []byte) *big.Int {
+	return leScalar(stem)
+}
+
+// leScalar interprets b as a little-endian integer.
+func leScalar(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i, c := range b {
+		be[len(b)-1-i] = c
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func encodeInner(n *innerNode) []byte {
+	out := append([]byte{innerType}, n.commitment.encode()...)
+	var bitmap [NodeWidth / 8]byte
+	for i, c := range n.children {
+		if c != nil {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	out = append(out, bitmap[:]...)
+	for _, c := range n.children {
+		if c != nil {
+			out = append(out, c.FillBytes(make([]byte, 32))...)
+		}
+	}
+	return out
+}
+
+func encodeLeaf(n *leafNode) []byte {
+	out := append([]byte{leafType}, n.stem...)
+	out = append(out, n.commitment.encode()...)
+	out = append(out, n.c1.encode()...)
+	out = append(out, n.c2.encode()...)
+	var bitmap [NodeWidth / 8]byte
+	for i, v := range n.values {
+		if v != nil {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	out = append(out, bitmap[:]...)
+	for _, v := range n.values {
+		if v != nil {
+			out = append(out, v...)
+		}
+	}
+	return out
+}
+
+// decodeNode returns an *innerNode or a *leafNode.
+func decodeNode(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, errInvalidNode
+	}
+	switch data[0] {
+	case innerType:
+		if len(data) < 1+64+NodeWidth/8 {
+			return nil, errInvalidNode
+		}
+		c, err := decodePoint(data[1:65])
+		if err != nil {
+			return nil, err
+		}
+		n := &innerNode{commitment: c}
+		bitmap, rest := data[65:65+NodeWidth/8], data[65+NodeWidth/8:]
+		for i := 0; i < NodeWidth; i++ {
+			if bitmap[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			if len(rest) < 32 {
+				return nil, errInvalidNode
+			}
+			n.children[i] = new(big.Int).SetBytes(rest[:32])
+			rest = rest[32:]
+		}
+		return n, nil
+	case leafType:
+		const header = 1 + StemSize + 3*64 + NodeWidth/8
+		if len(data) < header {
+			return nil, errInvalidNode
+		}
+		n := &leafNode{stem: append([]byte(nil), data[1:1+StemSize]...)}
+		var points [3]*point
+		for i := range points {
+			start := 1 + StemSize + 64*i
+			p, err := decodePoint(data[start : start+64])
+			if err != nil {
+				return nil, err
+			}
+			points[i] = p
+		}
+		n.commitment, n.c1, n.c2 = points[0], points[1], points[2]
+		bitmap, rest := data[header-NodeWidth/8:header], data[header:]
+		for i := 0; i < NodeWidth; i++ {
+			if bitmap[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			if len(rest) < ValueSize {
+				return nil, errInvalidNode
+			}
+			n.values[i] = append([]byte(nil), rest[:ValueSize]...)
+			rest = rest[ValueSize:]
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: type %d", errInvalidNode, data[0])
+	}
+}
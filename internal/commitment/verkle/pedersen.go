@@ -0,0 +1,101 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sync"
+)
+
+// NodeWidth is the number of children of an inner node and of values of a
+// leaf.
+const NodeWidth = 256
+
+// crsSeed seeds the derivation of the commitment basis.
+const crsSeed = "eth_verkle_oct_2021"
+
+var (
+	basisOnce sync.Once
+	basis     [NodeWidth]*point
+
+	// doublings holds 2^i*G for every generator G, built on first use.
+	doublings [NodeWidth]struct {
+		once   sync.Once
+		points []*point
+	}
+)
+
+// generators returns the Pedersen commitment basis: the first points whose
+// serialization is sha256(seed || counter), in counter order.
+func generators() *[NodeWidth]*point {
+	basisOnce.Do(func() {
+		var counter [8]byte
+		for i, n := 0, uint64(0); i < NodeWidth; n++ {
+			binary.BigEndian.PutUint64(counter[:], n)
+			hash := sha256.Sum256(append([]byte(crsSeed), counter[:]...))
+			x := new(big.Int).SetBytes(hash[:])
+			if p, ok := pointFromX(x); ok {
+				basis[i] = p
+				i++
+			}
+		}
+	})
+	return &basis
+}
+
+// commit returns the Pedersen commitment to values, which may be shorter
+// than NodeWidth. Nil values count as zero.
+func commit(values []*big.Int) *point {
+	res := identity()
+	for i, v := range values {
+		if v != nil && v.Sign() != 0 {
+			res = res.add(mulBasis(i, v))
+		}
+	}
+	return res
+}
+
+// mulBasis returns k times the generator at index.
+func mulBasis(index int, k *big.Int) *point {
+	d := &doublings[index]
+	d.once.Do(func() {
+		d.points = make([]*point, scalarR.BitLen())
+		d.points[0] = generators()[index]
+		for i := 1; i < len(d.points); i++ {
+			d.points[i] = d.points[i-1].add(d.points[i-1])
+		}
+	})
+	k = new(big.Int).Mod(k, scalarR)
+	res := identity()
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			res = res.add(d.points[i])
+		}
+	}
+	return res
+}
+
+// shift returns c updated for the value at index changing from old to cur.
+func shift(c *point, index int, old, cur *big.Int) *point {
+	delta := new(big.Int).Sub(cur, old)
+	if delta.Sign() == 0 {
+		return c
+	}
+	return c.add(mulBasis(index, delta))
+}
@@ -0,0 +1,220 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package verkle is an experimental Verkle tree state commitment: a 256-ary
+// tree of Pedersen vector commitments over the Banderwagon group, with keys
+// laid out as in EIP-6800. It lacks the IPA opening proofs; witnesses carry
+// whole nodes instead and are verified by recomputing the commitments.
+package verkle
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// NodeStore keeps the tree nodes, keyed by the stem prefix leading to them.
+// The root has the empty path.
+type NodeStore interface {
+	Node(path []byte) ([]byte, error)
+	PutNode(path, data []byte) error
+	DeleteNode(path []byte) error
+}
+
+// Tree is a Verkle tree whose nodes live in a NodeStore. Every update
+// refreshes the commitments on the path to the root right away.
+type Tree struct {
+	store NodeStore
+}
+
+// New returns the tree kept in store.
+func New(store NodeStore) *Tree {
+	return &Tree{store: store}
+}
+
+// Root returns the serialized root commitment.
+func (t *Tree) Root() ([32]byte, error) {
+	n, err := t.load(nil)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if n == nil {
+		return identity().bytes(), nil
+	}
+	return n.(*innerNode).commitment.bytes(), nil
+}
+
+// Get returns the value stored under key, or nil.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("verkle: invalid key length %d", len(key))
+	}
+	var path []byte
+	for depth := 0; depth < StemSize; depth++ {
+		n, err := t.load(path)
+		if err != nil {
+			return nil, err
+		}
+		switch n := n.(type) {
+		case nil:
+			return nil, nil
+		case *leafNode:
+			if !bytes.Equal(n.stem, key[:StemSize]) {
+				return nil, nil
+			}
+			return n.values[key[StemSize]], nil
+		}
+		path = append(path, key[depth])
+	}
+	return nil, nil
+}
+
+// Insert stores a 32-byte value under key.
+func (t *Tree) Insert(key, value []byte) error {
+	if len(value) != ValueSize {
+		return fmt.Errorf("verkle: invalid value length %d", len(value))
+	}
+	return t.update(key, append([]byte(nil), value...))
+}
+
+// Delete removes the value stored under key.
+func (t *Tree) Delete(key []byte) error {
+	return t.update(key, nil)
+}
+
+func (t *Tree) update(key, value []byte) error {
+	if len(key) != KeySize {
+		return fmt.Errorf("verkle: invalid key length %d", len(key))
+	}
+	root, err := t.load(nil)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		if value == nil {
+			return nil
+		}
+		if err := t.save(nil, newInner()); err != nil {
+			return err
+		}
+	}
+	_, _, _, err = t.set(nil, key[:StemSize], key[StemSize], value)
+	return err
+}
+
+// set updates the value of stem/suffix below the node at path. It returns
+// the field value of that node before and after, and whether the node still
+// exists.
+func (t *Tree) set(path, stem []byte, suffix byte, value []byte) (before, after *big.Int, exists bool, err error) {
+	n, err := t.load(path)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	switch n := n.(type) {
+	case nil:
+		if value == nil {
+			return zero, zero, false, nil
+		}
+		leaf := newLeaf(stem)
+		leaf.setValue(suffix, value)
+		return zero, leaf.commitment.field(), true, t.save(path, leaf)
+
+	case *leafNode:
+		before = n.commitment.field()
+		if bytes.Equal(n.stem, stem) {
+			n.setValue(suffix, value)
+			if n.empty() {
+				return before, zero, false, t.store.DeleteNode(path)
+			}
+			return before, n.commitment.field(), true, t.save(path, n)
+		}
+		if value == nil {
+			return before, before, true, nil
+		}
+		// Another stem shares the path: move the leaf one level down below
+		// a new inner node and insert into that one.
+		inner := newInner()
+		index := n.stem[len(path)]
+		inner.setChild(index, before)
+		if err := t.save(childPath(path, index), n); err != nil {
+			return nil, nil, false, err
+		}
+		if err := t.save(path, inner); err != nil {
+			return nil, nil, false, err
+		}
+		_, after, _, err = t.set(path, stem, suffix, value)
+		return before, after, true, err
+
+	case *innerNode:
+		before = n.commitment.field()
+		index := stem[len(path)]
+		_, cur, ok, err := t.set(childPath(path, index), stem, suffix, value)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !ok {
+			cur = nil
+		}
+		n.setChild(index, cur)
+		if len(path) > 0 {
+			if n.empty() {
+				return before, zero, false, t.store.DeleteNode(path)
+			}
+			// A lone leaf moves up to the shallowest depth its stem is
+			// unique at, so the tree shape depends only on its content.
+			if only, ok := n.only(); ok {
+				child, err := t.load(childPath(path, only))
+				if err != nil {
+					return nil, nil, false, err
+				}
+				if leaf, ok := child.(*leafNode); ok {
+					if err := t.store.DeleteNode(childPath(path, only)); err != nil {
+						return nil, nil, false, err
+					}
+					return before, leaf.commitment.field(), true, t.save(path, leaf)
+				}
+			}
+		}
+		return before, n.commitment.field(), true, t.save(path, n)
+	}
+	return nil, nil, false, errInvalidNode
+}
+
+func (t *Tree) load(path []byte) (interface{}, error) {
+	data, err := t.store.Node(path)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	n, err := decodeNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("node %x: %w", path, err)
+	}
+	return n, nil
+}
+
+func (t *Tree) save(path []byte, n interface{}) error {
+	switch n := n.(type) {
+	case *innerNode:
+		return t.store.PutNode(path, encodeInner(n))
+	case *leafNode:
+		return t.store.PutNode(path, encodeLeaf(n))
+	}
+	return errInvalidNode
+}
+
+func childPath(path []byte, index byte) []byte {
+	return append(append(make([]byte, 0, len(path)+1), path...), index)
+}
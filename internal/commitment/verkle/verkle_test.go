@@ -0,0 +1,230 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/types"
+)
+
+type memStore map[string][]byte
+
+func (s memStore) Node(path []byte) ([]byte, error) { return s[string(path)], nil }
+
+func (s memStore) PutNode(path, data []byte) error {
+	s[string(path)] = data
+	return nil
+}
+
+func (s memStore) DeleteNode(path []byte) error {
+	delete(s, string(path))
+	return nil
+}
+
+func testKey(prefix []byte, suffix byte) []byte {
+	key := make([]byte, KeySize)
+	copy(key, prefix)
+	key[StemSize] = suffix
+	return key
+}
+
+func testValue(b byte) []byte {
+	return bytes.Repeat([]byte{b}, ValueSize)
+}
+
+func TestField(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pm1 := new(big.Int).Sub(fieldP, one)
+	values := []*big.Int{new(big.Int), one, pm1, halfP}
+	for i := 0; i < 50; i++ {
+		values = append(values, new(big.Int).Rand(rnd, fieldP))
+	}
+	for i, a := range values {
+		b := values[(i+7)%len(values)]
+		x, y := feFromBig(a), feFromBig(b)
+		if x.big().Cmp(a) != 0 {
+			t.Fatalf("round trip of %x", a)
+		}
+		if got := feMul(x, y).big(); got.Cmp(fmul(a, b)) != 0 {
+			t.Fatalf("%x * %x = %x", a, b, got)
+		}
+		if got := feAdd(x, y).big(); got.Cmp(fadd(a, b)) != 0 {
+			t.Fatalf("%x + %x = %x", a, b, got)
+		}
+		if got := feSub(x, y).big(); got.Cmp(fsub(a, b)) != 0 {
+			t.Fatalf("%x - %x = %x", a, b, got)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	g := generators()
+	seen := make(map[[32]byte]bool)
+	for i, p := range g {
+		x, y := p.affine()
+		if !onCurve(x, y) {
+			t.Fatalf("generator %d not on curve", i)
+		}
+		b := p.bytes()
+		if seen[b] {
+			t.Fatalf("generator %d repeated", i)
+		}
+		seen[b] = true
+	}
+	if !g[0].mul(new(big.Int).Sub(scalarR, one)).add(g[0]).equal(identity()) {
+		t.Fatal("generator not in the prime order subgroup")
+	}
+	if dec, err := decodePoint(g[7].encode()); err != nil || !dec.equal(g[7]) {
+		t.Fatalf("point encoding round trip failed: %v", err)
+	}
+}
+
+func TestTreeInsertOrder(t *testing.T) {
+	keys := [][]byte{
+		testKey([]byte{1, 2, 3}, 0),
+		testKey([]byte{1, 2, 3}, 200),
+		testKey([]byte{1, 2, 4}, 5),
+		testKey([]byte{1, 9}, 5),
+		testKey([]byte{7}, 255),
+	}
+	forward, backward := New(make(memStore)), New(make(memStore))
+	for i := range keys {
+		if err := forward.Insert(keys[i], testValue(byte(i+1))); err != nil {
+			t.Fatal(err)
+		}
+		j := len(keys) - 1 - i
+		if err := backward.Insert(keys[j], testValue(byte(j+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r1, _ := forward.Root()
+	r2, _ := backward.Root()
+	if r1 != r2 || r1 == [32]byte{} {
+		t.Fatalf("roots differ: %x != %x", r1, r2)
+	}
+	for i, key := range keys {
+		v, err := forward.Get(key)
+		if err != nil || !bytes.Equal(v, testValue(byte(i+1))) {
+			t.Fatalf("key %d: have %x, %v", i, v, err)
+		}
+	}
+	if v, _ := forward.Get(testKey([]byte{1, 2, 5}, 0)); v != nil {
+		t.Fatalf("missing key returned %x", v)
+	}
+}
+
+func TestTreeDelete(t *testing.T) {
+	a, b, c := testKey([]byte{1, 2, 3}, 1), testKey([]byte{1, 2, 4}, 1), testKey([]byte{1, 2, 3}, 2)
+
+	store := make(memStore)
+	tree := New(store)
+	for _, key := range [][]byte{a, b, c} {
+		if err := tree.Insert(key, testValue(key[2])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Delete(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Delete(c); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := make(memStore)
+	if err := New(fresh).Insert(a, testValue(a[2])); err != nil {
+		t.Fatal(err)
+	}
+	if len(store) != len(fresh) {
+		t.Fatalf("have %d nodes after deletion, want %d", len(store), len(fresh))
+	}
+	for path, data := range fresh {
+		if !bytes.Equal(store[path], data) {
+			t.Fatalf("node %x differs from a fresh tree", path)
+		}
+	}
+
+	if err := tree.Delete(a); err != nil {
+		t.Fatal(err)
+	}
+	if root, _ := tree.Root(); root != [32]byte{} {
+		t.Fatalf("empty tree root %x", root)
+	}
+}
+
+func TestWitness(t *testing.T) {
+	tree := New(make(memStore))
+	present := [][]byte{testKey([]byte{1, 2, 3}, 0), testKey([]byte{1, 2, 4}, 9), testKey([]byte{8}, 3)}
+	for i, key := range present {
+		if err := tree.Insert(key, testValue(byte(i+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	absent := [][]byte{testKey([]byte{1, 2, 3}, 1), testKey([]byte{1, 2, 5}, 0), testKey([]byte{9}, 0)}
+	w, err := tree.Witness(append(append([][]byte{}, present[:2]...), absent...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range present[:2] {
+		if v, err := w.Get(key); err != nil || !bytes.Equal(v, testValue(byte(i+1))) {
+			t.Fatalf("key %d: have %x, %v", i, v, err)
+		}
+	}
+	for i, key := range absent {
+		if v, err := w.Get(key); err != nil || v != nil {
+			t.Fatalf("absent key %d: have %x, %v", i, v, err)
+		}
+	}
+	if _, err := w.Get(present[2]); err != errNotCovered {
+		t.Fatalf("uncovered key: have %v", err)
+	}
+
+	w.Leaves[0].Values[0].Value = testValue(0xff)
+	if err := w.Verify(); err == nil {
+		t.Fatal("tampered value accepted")
+	}
+	w.Leaves[0].Values[0].Value = testValue(1)
+	w.Inner[0].Children[0].Field = make([]byte, 32)
+	if err := w.Verify(); err == nil {
+		t.Fatal("tampered child accepted")
+	}
+}
+
+func TestTreeKey(t *testing.T) {
+	addr := types.Address{19: 1}
+	header := HeaderKey(addr, BalanceLeafKey)
+	if !bytes.Equal(header[:StemSize], HeaderKey(addr, NonceLeafKey)[:StemSize]) {
+		t.Fatal("header values do not share a stem")
+	}
+	slot := StorageKey(addr, types.Hash{31: 5})
+	if !bytes.Equal(slot[:StemSize], header[:StemSize]) || slot[StemSize] != headerStorageOffset+5 {
+		t.Fatalf("header storage slot key %x", slot)
+	}
+	main := StorageKey(addr, types.Hash{31: 200})
+	if bytes.Equal(main[:StemSize], header[:StemSize]) || main[StemSize] != 200 {
+		t.Fatalf("main storage slot key %x", main)
+	}
+	if bytes.Equal(header, HeaderKey(types.Address{19: 2}, BalanceLeafKey)) {
+		t.Fatal("addresses share a stem")
+	}
+}
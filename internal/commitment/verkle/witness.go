@@ -0,0 +1,246 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package verkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/n42blockchain/N42/common/hexutil"
+)
+
+// Witness holds the tree nodes on the paths to a set of keys, enough to
+// recompute the root commitment and to read or prove absent any of the keys
+// without the rest of the state.
+type Witness struct {
+	Root   hexutil.Bytes `json:"root"`
+	Inner  []InnerNode   `json:"inner"`
+	Leaves []LeafNode    `json:"leaves"`
+}
+
+// InnerNode is an inner node of a witness with the field values of all its
+// children.
+type InnerNode struct {
+	Path     hexutil.Bytes `json:"path"`
+	Children []ChildField  `json:"children"`
+}
+
+// ChildField is the field value of the child at Index.
+type ChildField struct {
+	Index uint8         `json:"index"`
+	Field hexutil.Bytes `json:"field"`
+}
+
+// LeafNode is a leaf of a witness with all its values.
+type LeafNode struct {
+	Path   hexutil.Bytes `json:"path"`
+	Stem   hexutil.Bytes `json:"stem"`
+	Values []LeafValue   `json:"values"`
+}
+
+// LeafValue is the value at Suffix of a leaf.
+type LeafValue struct {
+	Suffix uint8         `json:"suffix"`
+	Value  hexutil.Bytes `json:"value"`
+}
+
+var errNotCovered = errors.New("verkle: key not covered by witness")
+
+// Witness returns the nodes on the paths to keys.
+func (t *Tree) Witness(keys [][]byte) (*Witness, error) {
+	root, err := t.Root()
+	if err != nil {
+		return nil, err
+	}
+	w := &Witness{Root: root[:]}
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("verkle: invalid key length %d", len(key))
+		}
+		var path []byte
+		for depth := 0; depth < StemSize; depth++ {
+			n, err := t.load(path)
+			if err != nil {
+				return nil, err
+			}
+			if n == nil {
+				break
+			}
+			if _, ok := seen[string(path)]; !ok {
+				seen[string(path)] = struct{}{}
+				w.add(path, n)
+			}
+			if _, ok := n.(*leafNode); ok {
+				break
+			}
+			path = childPath(path, key[depth])
+		}
+	}
+	sort.Slice(w.Inner, func(i, j int) bool { return bytes.Compare(w.Inner[i].Path, w.Inner[j].Path) < 0 })
+	sort.Slice(w.Leaves, func(i, j int) bool { return bytes.Compare(w.Leaves[i].Path, w.Leaves[j].Path) < 0 })
+	return w, nil
+}
+
+func (w *Witness) add(path []byte, n interface{}) {
+	switch n := n.(type) {
+	case *innerNode:
+		node := InnerNode{Path: append([]byte{}, path...), Children: []ChildField{}}
+		for i, c := range n.children {
+			if c != nil {
+				node.Children = append(node.Children, ChildField{Index: uint8(i), Field: c.FillBytes(make([]byte, 32))})
+			}
+		}
+		w.Inner = append(w.Inner, node)
+	case *leafNode:
+		node := LeafNode{Path: append([]byte{}, path...), Stem: n.stem, Values: []LeafValue{}}
+		for i, v := range n.values {
+			if v != nil {
+				node.Values = append(node.Values, LeafValue{Suffix: uint8(i), Value: v})
+			}
+		}
+		w.Leaves = append(w.Leaves, node)
+	}
+}
+
+// Verify recomputes the commitments of the witness nodes and checks that
+// they add up to the root, and that every node hangs off the root.
+func (w *Witness) Verify() error {
+	fields := make(map[string]*big.Int)
+	for _, l := range w.Leaves {
+		if len(l.Stem) != StemSize || len(l.Path) == 0 || !bytes.HasPrefix(l.Stem, l.Path) {
+			return fmt.Errorf("verkle: invalid leaf at %x", []byte(l.Path))
+		}
+		leaf := newLeaf(l.Stem)
+		for _, v := range l.Values {
+			if len(v.Value) != ValueSize {
+				return fmt.Errorf("verkle: invalid value length at %x", []byte(l.Path))
+			}
+			leaf.setValue(v.Suffix, v.Value)
+		}
+		if err := setField(fields, l.Path, leaf.commitment.field()); err != nil {
+			return err
+		}
+	}
+
+	// Children come after their parents in path order, so walk backwards.
+	inner := append([]InnerNode(nil), w.Inner...)
+	sort.Slice(inner, func(i, j int) bool { return bytes.Compare(inner[i].Path, inner[j].Path) > 0 })
+	claimed := make(map[string]*big.Int)
+	var root *point
+	for _, n := range inner {
+		values := make([]*big.Int, NodeWidth)
+		for _, c := range n.Children {
+			if len(c.Field) != 32 || values[c.Index] != nil {
+				return fmt.Errorf("verkle: invalid child %d at %x", c.Index, []byte(n.Path))
+			}
+			values[c.Index] = new(big.Int).SetBytes(c.Field)
+			claimed[string(childPath(n.Path, c.Index))] = values[c.Index]
+		}
+		c := commit(values)
+		if len(n.Path) == 0 {
+			if root != nil {
+				return errors.New("verkle: duplicate root node")
+			}
+			root = c
+			continue
+		}
+		if err := setField(fields, n.Path, c.field()); err != nil {
+			return err
+		}
+	}
+	if root == nil {
+		if len(fields) > 0 || !bytes.Equal(w.Root, make([]byte, 32)) {
+			return errors.New("verkle: witness without root node")
+		}
+		return nil
+	}
+	for path, field := range fields {
+		parent, ok := claimed[path]
+		if !ok {
+			return fmt.Errorf("verkle: node %x not linked to the root", []byte(path))
+		}
+		if parent.Cmp(field) != 0 {
+			return fmt.Errorf("verkle: commitment mismatch at %x", []byte(path))
+		}
+	}
+	if got := root.bytes(); !bytes.Equal(got[:], w.Root) {
+		return fmt.Errorf("verkle: root mismatch: have %x, want %x", got, []byte(w.Root))
+	}
+	return nil
+}
+
+func setField(fields map[string]*big.Int, path []byte, field *big.Int) error {
+	if _, ok := fields[string(path)]; ok {
+		return fmt.Errorf("verkle: duplicate node at %x", path)
+	}
+	fields[string(path)] = field
+	return nil
+}
+
+// Get returns the value of key according to the witness, nil if the
+// witness proves it absent. The witness must have passed Verify.
+func (w *Witness) Get(key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("verkle: invalid key length %d", len(key))
+	}
+	inner := make(map[string]*InnerNode, len(w.Inner))
+	for i := range w.Inner {
+		inner[string(w.Inner[i].Path)] = &w.Inner[i]
+	}
+	leaves := make(map[string]*LeafNode, len(w.Leaves))
+	for i := range w.Leaves {
+		leaves[string(w.Leaves[i].Path)] = &w.Leaves[i]
+	}
+	if len(inner) == 0 && bytes.Equal(w.Root, make([]byte, 32)) {
+		return nil, nil
+	}
+
+	var path []byte
+	for depth := 0; depth < StemSize; depth++ {
+		if l, ok := leaves[string(path)]; ok {
+			if !bytes.Equal(l.Stem, key[:StemSize]) {
+				return nil, nil
+			}
+			for _, v := range l.Values {
+				if v.Suffix == key[StemSize] {
+					return v.Value, nil
+				}
+			}
+			return nil, nil
+		}
+		n, ok := inner[string(path)]
+		if !ok {
+			return nil, errNotCovered
+		}
+		present := false
+		for _, c := range n.Children {
+			if c.Index == key[depth] {
+				present = true
+				break
+			}
+		}
+		if !present {
+			return nil, nil
+		}
+		path = childPath(path, key[depth])
+	}
+	return nil, errNotCovered
+}
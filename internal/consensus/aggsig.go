@@ -0,0 +1,117 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/log"
+)
+
+// aggSeal is the aggregated verifier signature of a block and the public
+// keys of its verifiers
+type aggSeal struct {
+	sig     bls.Signature
+	pubKeys []bls.PublicKey
+	root    [32]byte
+}
+
+// decodeAggSeal reads the aggregated signature and verifier keys of b
+func decodeAggSeal(b block.IBlock) (*aggSeal, error) {
+	header := b.Header().(*block.Header)
+	vfs := b.Body().Verifier()
+	if len(vfs) == 0 {
+		return nil, fmt.Errorf("%w: no verifiers", ErrInvalidAggSignature)
+	}
+	pubKeys := make([]bls.PublicKey, len(vfs))
+	for i, p := range vfs {
+		pubKey, err := bls.PublicKeyFromBytes(p.PublicKey[:])
+		if err != nil {
+			return nil, err
+		}
+		pubKeys[i] = pubKey
+	}
+	sig, err := bls.SignatureFromBytes(header.Signature[:])
+	if err != nil {
+		return nil, err
+	}
+	return &aggSeal{sig: sig, pubKeys: pubKeys, root: header.Root}, nil
+}
+
+// VerifyAggSignature checks that the aggregated signature of b was made by
+// its verifiers over the state root.
+func VerifyAggSignature(b block.IBlock) error {
+	seal, err := decodeAggSeal(b)
+	if err != nil {
+		return err
+	}
+	if !seal.sig.FastAggregateVerify(seal.pubKeys, seal.root) {
+		header := b.Header().(*block.Header)
+		log.Warn("AggSignature verify falied", "blockNr", b.Number64().Uint64(), "Signature", hexutil.Encode(header.Signature[:]), "Root", hexutil.Encode(header.Root[:]))
+		for _, p := range b.Body().Verifier() {
+			log.Warn("", "address", p.Address.String(), "publicKey", hexutil.Encode(p.PublicKey[:]))
+		}
+		return ErrInvalidAggSignature
+	}
+	return nil
+}
+
+// VerifyAggSignatures verifies the aggregated signatures of a batch of blocks
+// with a single randomised multi-pairing check instead of one check per
+// block. If the batch doesn't verify, the blocks are checked one by one to
+// find the invalid ones. The returned slice holds the result of each block.
+func VerifyAggSignatures(blocks []block.IBlock) []error {
+	errs := make([]error, len(blocks))
+	if len(blocks) == 1 {
+		errs[0] = VerifyAggSignature(blocks[0])
+		return errs
+	}
+
+	var (
+		batch   = make([]int, 0, len(blocks))
+		sigs    = make([][]byte, 0, len(blocks))
+		pubKeys = make([]bls.PublicKey, 0, len(blocks))
+		msgs    = make([][32]byte, 0, len(blocks))
+	)
+	for i, b := range blocks {
+		seal, err := decodeAggSeal(b)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		batch = append(batch, i)
+		sigs = append(sigs, seal.sig.Marshal())
+		// All verifiers signed the same root, so their keys can be aggregated
+		pubKeys = append(pubKeys, bls.AggregateMultiplePubkeys(seal.pubKeys))
+		msgs = append(msgs, seal.root)
+	}
+	if len(batch) == 0 {
+		return errs
+	}
+	if ok, err := bls.VerifyMultipleSignatures(sigs, msgs, pubKeys); ok && err == nil {
+		return errs
+	}
+
+	log.Debug("Batch verification of aggregated signatures failed, verifying one by one", "blocks", len(batch))
+	for _, i := range batch {
+		errs[i] = VerifyAggSignature(blocks[i])
+	}
+	return errs
+}
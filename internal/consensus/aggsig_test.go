@@ -0,0 +1,109 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// newSignedBlock creates a block whose state root is signed by n verifiers
+func newSignedBlock(t testing.TB, number uint64, n int) *block.Block {
+	t.Helper()
+	header := &block.Header{Number: uint256.NewInt(number)}
+	header.Root[0], header.Root[1] = byte(number), 0x42
+	body := &block.Body{}
+	sigs := make([]bls.Signature, n)
+	for i := range sigs {
+		secret, err := bls.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i] = secret.Sign(header.Root[:])
+		v := &block.Verify{Address: types.BytesToAddress([]byte{byte(i + 1)})}
+		copy(v.PublicKey[:], secret.PublicKey().Marshal())
+		body.Verifiers = append(body.Verifiers, v)
+	}
+	copy(header.Signature[:], bls.AggregateSignatures(sigs).Marshal())
+	return block.NewBlockFromStorage(header.Hash(), header, body)
+}
+
+func TestVerifyAggSignatures(t *testing.T) {
+	blocks := make([]block.IBlock, 5)
+	for i := range blocks {
+		blocks[i] = newSignedBlock(t, uint64(i+1), 3)
+	}
+	for i, err := range VerifyAggSignatures(blocks) {
+		if err != nil {
+			t.Fatalf("block %d: unexpected error %v", i, err)
+		}
+	}
+	if err := VerifyAggSignature(blocks[0]); err != nil {
+		t.Fatalf("single block: unexpected error %v", err)
+	}
+}
+
+func TestVerifyAggSignaturesInvalid(t *testing.T) {
+	blocks := make([]block.IBlock, 4)
+	for i := range blocks {
+		blocks[i] = newSignedBlock(t, uint64(i+1), 3)
+	}
+	// Sign block 2 over another root and drop the verifiers of block 3
+	forged := newSignedBlock(t, 9, 3)
+	header := blocks[1].Header().(*block.Header)
+	header.Signature = forged.Header().(*block.Header).Signature
+	blocks[1] = block.NewBlockFromStorage(header.Hash(), header, forged.Body().(*block.Body))
+	blocks[2].Body().(*block.Body).Verifiers = nil
+
+	errs := VerifyAggSignatures(blocks)
+	for i, err := range errs {
+		switch i {
+		case 1, 2:
+			if !errors.Is(err, ErrInvalidAggSignature) {
+				t.Errorf("block %d: expected %v, got %v", i, ErrInvalidAggSignature, err)
+			}
+		default:
+			if err != nil {
+				t.Errorf("block %d: unexpected error %v", i, err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyAggSignatures(b *testing.B) {
+	blocks := make([]block.IBlock, 64)
+	for i := range blocks {
+		blocks[i] = newSignedBlock(b, uint64(i+1), 7)
+	}
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			VerifyAggSignatures(blocks)
+		}
+	})
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, blk := range blocks {
+				VerifyAggSignature(blk)
+			}
+		}
+	})
+}
@@ -181,6 +181,8 @@ type Apoa struct {
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
 
+	verifyWorkers, verifyAhead int // Parallelism of batch header verification
+
 	proposals map[types.Address]bool // Current list of proposals we are pushing
 
 	signer types.Address // Ethereum address of the signing key
@@ -227,28 +229,33 @@ func (c *Apoa) VerifyHeader(chain consensus.ChainHeaderReader, header block.IHea
 // method returns a quit channel to abort the operations and a results channel to
 // retrieve the async verifications (the order is that of the input slice).
 func (c *Apoa) VerifyHeaders(chain consensus.ChainHeaderReader, headers []block.IHeader, seals []bool) (chan<- struct{}, <-chan error) {
-	abort := make(chan struct{})
-	results := make(chan error, len(headers))
-
-	go func() {
-		for i, header := range headers {
-			err := c.verifyHeader(chain, header, headers[:i])
+	return consensus.VerifyHeadersPipelined(headers, c.verifyWorkers, c.verifyAhead, c.verifyStandalone,
+		func(header block.IHeader, parents []block.IHeader) error {
+			return c.verifyCascadingFields(chain, header, parents)
+		})
+}
 
-			select {
-			case <-abort:
-				return
-			case results <- err:
-			}
-		}
-	}()
-	return abort, results
+// SetVerifyParallelism implements consensus.ParallelVerifier.
+func (c *Apoa) SetVerifyParallelism(workers, ahead int) {
+	c.verifyWorkers, c.verifyAhead = workers, ahead
 }
 
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
 // a batch of new headers.
-func (c *Apoa) verifyHeader(chain consensus.ChainHeaderReader, iHeader block.IHeader, parents []block.IHeader) error {
+func (c *Apoa) verifyHeader(chain consensus.ChainHeaderReader, header block.IHeader, parents []block.IHeader) error {
+	if err := c.verifyStandalone(header); err != nil {
+		return err
+	}
+	return c.verifyCascadingFields(chain, header, parents)
+}
+
+// verifyStandalone checks the header fields that do not depend on previous
+// headers, so headers of a batch can be checked concurrently. It also
+// recovers the signer into the signature cache for the snapshot and seal
+// checks, which report a bad signature.
+func (c *Apoa) verifyStandalone(iHeader block.IHeader) error {
 	header := iHeader.(*block.Header)
 	if header.Number.IsZero() {
 		return errUnknownBlock
@@ -309,8 +316,8 @@ func (c *Apoa) verifyHeader(chain consensus.ChainHeaderReader, iHeader block.IHe
 	//if err := misc.VerifyForkHashes(chain.Config(), header, false); err != nil {
 	//	return err
 	//}
-	// All basic checks passed, verify cascading fields
-	return c.verifyCascadingFields(chain, header, parents)
+	ecrecover(header, c.signatures)
+	return nil
 }
 
 // verifyCascadingFields verifies all the header fields that are not standalone,
@@ -475,12 +482,11 @@ func (c *Apoa) snapshot(chain consensus.ChainHeaderReader, number uint64, hash t
 	return snap, err
 }
 
-// VerifyUncles implements consensus.Engine, always returning an error for any
-// uncles as this consensus mechanism doesn't permit uncles.
+// VerifyUncles implements consensus.Engine. Blocks are sealed by authorized
+// signers in turn, a competing block at the same height is resolved by the
+// fork choice and never referenced later. The block format has no uncle list,
+// so there is nothing to verify.
 func (c *Apoa) VerifyUncles(chain consensus.ConsensusChainReader, block block.IBlock) error {
-	//if len(block.Uncles()) > 0 {
-	//	return errors.New("uncles not allowed")
-	//}
 	return nil
 }
 
@@ -582,7 +588,7 @@ func (c *Apoa) Prepare(chain consensus.ChainHeaderReader, header block.IHeader)
 	rawHeader.MixDigest = types.Hash{}
 
 	// Ensure the timestamp has the correct delay
-	parent := chain.GetHeader(rawHeader.ParentHash, rawHeader.Number.Sub(rawHeader.Number, uint256.NewInt(1)))
+	parent := chain.GetHeader(rawHeader.ParentHash, uint256.NewInt(0).Sub(rawHeader.Number, uint256.NewInt(1)))
 	if parent == nil {
 		return errors.New("unknown ancestor")
 	}
@@ -38,6 +38,9 @@ import (
 
 const maxSearchBlock = 1000
 
+// maxRewardReportEpochs caps the epochs covered by one reward report request
+const maxRewardReportEpochs = 1000
+
 type MinedBlock struct {
 	BlockNumber *uint256.Int `json:"blockNumber"`
 	Timestamp   uint64       `json:"timestamp"`
@@ -85,6 +88,22 @@ func (api *API) GetSnapshotAtHash(hash types.Hash) (*Snapshot, error) {
 	return api.apos.snapshot(api.chain, header.Number64().Uint64(), header.Hash(), nil)
 }
 
+// GetProposers returns the signers allowed to seal the block after the
+// specified one (or the head), in the order they take their turn. The first
+// one is in turn unless it sealed too recently.
+func (api *API) GetProposers(number *jsonrpc.BlockNumber) ([]avmutil.Address, error) {
+	snap, err := api.GetSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+	proposers := snap.proposers(snap.Number + 1)
+	ethProposers := make([]avmutil.Address, len(proposers))
+	for i := range proposers {
+		ethProposers[i] = *avmtypes.FromastAddress(&proposers[i])
+	}
+	return ethProposers, nil
+}
+
 // GetSigners retrieves the list of authorized signers at the specified block.
 func (api *API) GetSigners(number *jsonrpc.BlockNumber) ([]avmutil.Address, error) {
 	// Retrieve the requested block number (or current if none requested)
@@ -491,3 +510,17 @@ func (api *API) GetAccountRewardUnpaid(address types.Address) (val *uint256.Int,
 	})
 	return
 }
+
+// GetRewardReport itemizes the rewards paid to accounts over the epochs
+// [fromEpoch, toEpoch] and their unpaid rewards. If addresses is empty the
+// rewards of all accounts are returned.
+func (api *API) GetRewardReport(fromEpoch uint64, toEpoch uint64, addresses []types.Address) (report *RewardReport, err error) {
+	if toEpoch >= fromEpoch && toEpoch-fromEpoch >= maxRewardReportEpochs {
+		return nil, fmt.Errorf("too many epochs requested, max %d", maxRewardReportEpochs)
+	}
+	err = api.apos.db.View(context.Background(), func(tx kv.Tx) error {
+		report, err = NewRewardReport(api.chain, tx, fromEpoch, toEpoch, addresses)
+		return err
+	})
+	return report, err
+}
@@ -191,12 +191,16 @@ type APos struct {
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
 
+	verifyWorkers, verifyAhead int // Parallelism of batch header verification
+
 	proposals map[types.Address]bool // Current list of proposals we are pushing
 
 	signer types.Address // Ethereum address of the signing key
 	signFn SignerFn      // Signer function to authorize hashes with
 	lock   sync.RWMutex  // Protects the signer and proposals fields
 
+	participation participation // Proposer metrics of the heights seen
+
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
 
@@ -244,28 +248,54 @@ func (c *APos) VerifyHeader(chain consensus.ChainHeaderReader, header block.IHea
 // method returns a quit channel to abort the operations and a results channel to
 // retrieve the async verifications (the order is that of the input slice).
 func (c *APos) VerifyHeaders(chain consensus.ChainHeaderReader, headers []block.IHeader, seals []bool) (chan<- struct{}, <-chan error) {
-	abort := make(chan struct{})
-	results := make(chan error, len(headers))
+	return consensus.VerifyHeadersPipelined(headers, c.verifyWorkers, c.verifyAhead, c.verifyStandalone,
+		func(header block.IHeader, parents []block.IHeader) error {
+			return c.verifyCascadingFields(chain, header, parents)
+		})
+}
 
-	go func() {
-		for i, header := range headers {
-			err := c.verifyHeader(chain, header, headers[:i])
+// SetVerifyParallelism implements consensus.ParallelVerifier.
+func (c *APos) SetVerifyParallelism(workers, ahead int) {
+	c.verifyWorkers, c.verifyAhead = workers, ahead
+}
 
-			select {
-			case <-abort:
-				return
-			case results <- err:
-			}
+// VerifySeals implements consensus.SealBatchVerifier, verifying the aggregated
+// verifier signatures of all blocks past the Beijing fork in one batch.
+func (c *APos) VerifySeals(chain consensus.ChainHeaderReader, blocks []block.IBlock) []error {
+	errs := make([]error, len(blocks))
+	signed := make([]block.IBlock, 0, len(blocks))
+	index := make([]int, 0, len(blocks))
+	for i, b := range blocks {
+		if c.chainConfig.IsBeijing(b.Number64().Uint64()) {
+			signed = append(signed, b)
+			index = append(index, i)
 		}
-	}()
-	return abort, results
+	}
+	if len(signed) == 0 {
+		return errs
+	}
+	for i, err := range consensus.VerifyAggSignatures(signed) {
+		errs[index[i]] = err
+	}
+	return errs
 }
 
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
 // a batch of new headers.
-func (c *APos) verifyHeader(chain consensus.ChainHeaderReader, iHeader block.IHeader, parents []block.IHeader) error {
+func (c *APos) verifyHeader(chain consensus.ChainHeaderReader, header block.IHeader, parents []block.IHeader) error {
+	if err := c.verifyStandalone(header); err != nil {
+		return err
+	}
+	return c.verifyCascadingFields(chain, header, parents)
+}
+
+// verifyStandalone checks the header fields that do not depend on previous
+// headers, so headers of a batch can be checked concurrently. It also
+// recovers the signer into the signature cache for the snapshot and seal
+// checks, which report a bad signature.
+func (c *APos) verifyStandalone(iHeader block.IHeader) error {
 	header := iHeader.(*block.Header)
 	if header.Number.IsZero() {
 		return errUnknownBlock
@@ -326,8 +356,8 @@ func (c *APos) verifyHeader(chain consensus.ChainHeaderReader, iHeader block.IHe
 	//if err := misc.VerifyForkHashes(chain.Config(), header, false); err != nil {
 	//	return err
 	//}
-	// All basic checks passed, verify cascading fields
-	return c.verifyCascadingFields(chain, header, parents)
+	ecrecover(header, c.signatures)
+	return nil
 }
 
 // verifyCascadingFields verifies all the header fields that are not standalone,
@@ -361,8 +391,9 @@ func (c *APos) verifyCascadingFields(chain consensus.ChainHeaderReader, iHeader
 		return fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, header.GasLimit)
 	}
 	if !chain.Config().IsLondon(header.Number.Uint64()) {
-		// Verify BaseFee not present before EIP-1559 fork.
-		if header.BaseFee != nil {
+		// Verify BaseFee not present before EIP-1559 fork. Headers always
+		// carry one on the wire, so an absent base fee reads as zero.
+		if header.BaseFee != nil && !header.BaseFee.IsZero() {
 			return fmt.Errorf("invalid baseFee before fork: have %d, want <nil>", header.BaseFee)
 		}
 		if err := misc.VerifyGaslimit(parent.(*block.Header).GasLimit, header.GasLimit); err != nil {
@@ -372,6 +403,10 @@ func (c *APos) verifyCascadingFields(chain consensus.ChainHeaderReader, iHeader
 		// Verify the header's EIP-1559 attributes.
 		return err
 	}
+	// Verify the header's EIP-4844 blob gas fields.
+	if err := misc.VerifyEip4844Header(chain.Config(), rawParent, header); err != nil {
+		return err
+	}
 	// Retrieve the snapshot needed to verify this header and cache it
 	snap, err := c.snapshot(chain, number-1, header.ParentHash, parents)
 	if err != nil {
@@ -492,12 +527,11 @@ func (c *APos) snapshot(chain consensus.ChainHeaderReader, number uint64, hash t
 	return snap, err
 }
 
-// VerifyUncles implements consensus.Engine, always returning an error for any
-// uncles as this consensus mechanism doesn't permit uncles.
+// VerifyUncles implements consensus.Engine. Blocks are sealed by authorized
+// signers in turn, a competing block at the same height is resolved by the
+// fork choice and never referenced later. The block format has no uncle list,
+// so there is nothing to verify.
 func (c *APos) VerifyUncles(chain consensus.ConsensusChainReader, block block.IBlock) error {
-	//if len(block.Uncles()) > 0 {
-	//	return errors.New("uncles not allowed")
-	//}
 	return nil
 }
 
@@ -539,6 +573,7 @@ func (c *APos) verifySeal(snap *Snapshot, h block.IHeader, parents []block.IHead
 			return errWrongDifficulty
 		}
 	}
+	c.participation.observe(snap, number, signer)
 	return nil
 }
 
@@ -648,6 +683,15 @@ func (c *APos) Rewards(tx kv.RwTx, header block.IHeader, state *state.IntraBlock
 	return rewards, nil
 }
 
+// FinalizeReadsChain implements consensus.ChainReadingFinalizer: the rewards
+// paid at the end of an epoch are computed from the blocks of the epoch.
+func (c *APos) FinalizeReadsChain(header block.IHeader) bool {
+	beijing, _ := uint256.FromBig(c.chainConfig.BeijingBlock)
+	number := header.Number64()
+	return c.chainConfig.IsBeijing(number.Uint64()) && new(uint256.Int).Mod(new(uint256.Int).Sub(number, beijing), uint256.NewInt(c.config.RewardEpoch)).
+		Cmp(uint256.NewInt(0)) == 0
+}
+
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
 // rewards given.
 func (c *APos) Finalize(chain consensus.ChainHeaderReader, header block.IHeader, state *state.IntraBlockState, txs []*transaction.Transaction, uncles []block.IHeader) ([]*block.Reward, map[types.Address]*uint256.Int, error) {
@@ -731,14 +775,12 @@ func (c *APos) Seal(chain consensus.ChainHeaderReader, b block.IBlock, results c
 		}
 	}
 
-	// Sweet, the protocol permits us to sign the block, wait for our time
-	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) // nolint: gosimple
+	// Sweet, the protocol permits us to sign the block, wait for our time. Backup
+	// proposers also wait for the ones scheduled before them to miss the slot.
+	backup := snap.backupDelay(number, signer)
+	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) + backup // nolint: gosimple
 	if header.Difficulty.Cmp(diffNoTurn) == 0 {
-		wiggle := time.Duration(len(snap.Signers)/2+1) * wiggleTime
-		delay += time.Duration(misc.SecureInt63n(int64(wiggle)))
-
-		log.Infof("wiggle %s , time %s, number %d", avmutil.PrettyDuration(wiggle), avmutil.PrettyDuration(delay), header.Number.Uint64())
-		log.Trace("Out-of-turn signing requested", "wiggle", avmutil.PrettyDuration(wiggle))
+		log.Debug("Out-of-turn signing requested", "number", number, "rank", snap.rank(number, signer), "backup", avmutil.PrettyDuration(backup))
 	}
 
 	if c.chainConfig.IsBeijing(header.Number.Uint64()) {
@@ -769,7 +811,7 @@ func (c *APos) Seal(chain consensus.ChainHeaderReader, b block.IBlock, results c
 		header.Signature = aggSign
 		body := b.Body().(*block.Body)
 		body.Verifiers = verifiers
-		delay = time.Unix(int64(header.Time), 0).Sub(time.Now())
+		delay = time.Unix(int64(header.Time), 0).Sub(time.Now()) + backup
 	}
 
 	// Sign all the things!
@@ -787,6 +829,7 @@ func (c *APos) Seal(chain consensus.ChainHeaderReader, b block.IBlock, results c
 			return
 		case <-time.After(delay):
 		}
+		c.participation.observe(snap, number, signer)
 
 		select {
 		case results <- b.WithSeal(header):
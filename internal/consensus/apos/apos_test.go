@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal/consensus"
 )
@@ -159,6 +160,29 @@ func TestFakerImplementsEngine(t *testing.T) {
 	t.Logf("✓ Faker implements consensus.Engine correctly")
 }
 
+func TestFakerRewind(t *testing.T) {
+	faker := NewDevFaker(0)
+	seal := func(number uint64) bool {
+		header := &block.Header{Number: uint256.NewInt(number)}
+		results := make(chan block.IBlock, 1)
+		if err := faker.Seal(nil, block.NewBlockFromStorage(header.Hash(), header, &block.Body{}), results, nil); err != nil {
+			t.Fatal(err)
+		}
+		return len(results) == 1
+	}
+
+	if !seal(1) {
+		t.Fatal("block 1 not sealed")
+	}
+	if seal(1) {
+		t.Fatal("height 1 sealed twice")
+	}
+	faker.(consensus.Rewinder).Rewind(0)
+	if !seal(1) {
+		t.Error("height 1 not sealed again after rewinding")
+	}
+}
+
 // =============================================================================
 // API Tests
 // =============================================================================
@@ -34,13 +34,20 @@ import (
 // Faker is a testing consensus engine that accepts all blocks as valid.
 // It is useful for testing purposes where consensus validation should be bypassed.
 type Faker struct {
-	sealMu        sync.Mutex // Prevent concurrent sealing
-	lastSealedNum uint64     // Last sealed block number (prevent duplicate blocks)
+	period        time.Duration // Delay before a block is sealed
+	sealMu        sync.Mutex    // Prevent concurrent sealing
+	lastSealedNum uint64        // Last sealed block number (prevent duplicate blocks)
 }
 
 // NewFaker creates a new Faker consensus engine.
 func NewFaker() consensus.Engine {
-	return &Faker{}
+	return &Faker{period: 2 * time.Second}
+}
+
+// NewDevFaker creates a Faker for the developer chain which seals blocks
+// period apart, or as soon as they are built if period is zero.
+func NewDevFaker(period time.Duration) consensus.Engine {
+	return &Faker{period: period}
 }
 
 func (f *Faker) Author(header block.IHeader) (types.Address, error) {
@@ -68,7 +75,7 @@ func (f *Faker) VerifyHeaders(chain consensus.ChainHeaderReader, headers []block
 }
 
 func (f *Faker) VerifyUncles(chain consensus.ConsensusChainReader, blk block.IBlock) error {
-	// Faker accepts all uncles as valid
+	// Blocks have no uncles, see APos.VerifyUncles
 	return nil
 }
 
@@ -110,8 +117,7 @@ func (f *Faker) Seal(chain consensus.ChainHeaderReader, blk block.IBlock, result
 		return nil
 	}
 
-	// 2 second block time for dev mode
-	delay := time.NewTimer(2 * time.Second)
+	delay := time.NewTimer(f.period)
 	defer delay.Stop()
 
 	select {
@@ -130,6 +136,16 @@ func (f *Faker) Seal(chain consensus.ChainHeaderReader, blk block.IBlock, result
 	return nil
 }
 
+// Rewind implements consensus.Rewinder, the heights above head may be sealed
+// again.
+func (f *Faker) Rewind(head uint64) {
+	f.sealMu.Lock()
+	defer f.sealMu.Unlock()
+	if f.lastSealedNum > head {
+		f.lastSealedNum = head
+	}
+}
+
 func (f *Faker) SealHash(header block.IHeader) types.Hash {
 	return header.Hash()
 }
@@ -0,0 +1,198 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/params"
+)
+
+var (
+	errRewardsDisabled  = errors.New("rewards are not enabled on this chain")
+	errInvalidEpochSpan = errors.New("from epoch is after to epoch")
+)
+
+// RewardChainReader is the part of the chain a reward report is built from.
+type RewardChainReader interface {
+	Config() *params.ChainConfig
+	CurrentBlock() block.IBlock
+	GetBlockByNumber(number *uint256.Int) (block.IBlock, error)
+}
+
+// RewardReportEntry is a reward paid to an account by an epoch block.
+type RewardReportEntry struct {
+	Epoch       hexutil.Uint64 `json:"epoch"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Timestamp   hexutil.Uint64 `json:"timestamp"`
+	Address     types.Address  `json:"address"`
+	Amount      *uint256.Int   `json:"amount"`
+}
+
+// RewardReportAccount sums the rewards of an account. Unpaid is the reward
+// accumulated at the head of the chain that is below the payout limit and
+// not paid yet.
+type RewardReportAccount struct {
+	Address types.Address `json:"address"`
+	Paid    *uint256.Int  `json:"paid"`
+	Unpaid  *uint256.Int  `json:"unpaid"`
+}
+
+// RewardReport itemizes the rewards paid over a range of epochs.
+type RewardReport struct {
+	FromEpoch hexutil.Uint64         `json:"fromEpoch"`
+	ToEpoch   hexutil.Uint64         `json:"toEpoch"`
+	Total     *uint256.Int           `json:"total"`
+	Entries   []*RewardReportEntry   `json:"entries"`
+	Accounts  []*RewardReportAccount `json:"accounts"`
+}
+
+// NewRewardReport builds the reward report of the epochs [fromEpoch, toEpoch].
+// toEpoch is capped to the last epoch of the chain. If addrs is not empty only
+// the rewards of those accounts are listed.
+func NewRewardReport(chain RewardChainReader, tx kv.Getter, fromEpoch, toEpoch uint64, addrs []types.Address) (*RewardReport, error) {
+	config := chain.Config()
+	if config.Apos == nil || config.BeijingBlock == nil || config.Apos.RewardEpoch == 0 {
+		return nil, errRewardsDisabled
+	}
+	r := newReward(config)
+	if last := r.number2epoch(chain.CurrentBlock().Number64()).Uint64(); toEpoch > last {
+		toEpoch = last
+	}
+	if fromEpoch > toEpoch {
+		return nil, errInvalidEpochSpan
+	}
+	return r.getRewardReport(tx, fromEpoch, toEpoch, addrs, chain.GetBlockByNumber)
+}
+
+func (r *Reward) getRewardReport(tx kv.Getter, fromEpoch, toEpoch uint64, addrs []types.Address, getBlockByNumber func(*uint256.Int) (block.IBlock, error)) (*RewardReport, error) {
+	report := &RewardReport{
+		FromEpoch: hexutil.Uint64(fromEpoch),
+		ToEpoch:   hexutil.Uint64(toEpoch),
+		Total:     uint256.NewInt(0),
+		Entries:   make([]*RewardReportEntry, 0),
+		Accounts:  make([]*RewardReportAccount, 0),
+	}
+
+	accounts := make(map[types.Address]*RewardReportAccount)
+	for _, addr := range addrs {
+		accounts[addr] = &RewardReportAccount{Address: addr, Paid: uint256.NewInt(0)}
+	}
+	filter := len(addrs) > 0
+
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		number := r.epoch2number(uint256.NewInt(epoch))
+		blk, err := getBlockByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		if blk == nil {
+			return nil, fmt.Errorf("epoch %d: block %d not found", epoch, number.Uint64())
+		}
+		for _, reward := range blk.Body().Reward() {
+			account, ok := accounts[reward.Address]
+			if !ok {
+				if filter {
+					continue
+				}
+				account = &RewardReportAccount{Address: reward.Address, Paid: uint256.NewInt(0)}
+				accounts[reward.Address] = account
+			}
+			report.Entries = append(report.Entries, &RewardReportEntry{
+				Epoch:       hexutil.Uint64(epoch),
+				BlockNumber: hexutil.Uint64(number.Uint64()),
+				Timestamp:   hexutil.Uint64(blk.Time()),
+				Address:     reward.Address,
+				Amount:      reward.Amount.Clone(),
+			})
+			account.Paid.Add(account.Paid, reward.Amount)
+			report.Total.Add(report.Total, reward.Amount)
+		}
+	}
+
+	for _, account := range accounts {
+		unpaid, err := r.getAccountRewardUnpaid(tx, account.Address)
+		if err != nil {
+			return nil, err
+		}
+		account.Unpaid = unpaid
+		report.Accounts = append(report.Accounts, account)
+	}
+	sort.Slice(report.Accounts, func(i, j int) bool {
+		return bytes.Compare(report.Accounts[i].Address[:], report.Accounts[j].Address[:]) < 0
+	})
+	// Order the entries by epoch, then by address
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Epoch != b.Epoch {
+			return a.Epoch < b.Epoch
+		}
+		return bytes.Compare(a.Address[:], b.Address[:]) < 0
+	})
+	return report, nil
+}
+
+// WriteCSV writes the report as CSV. Every paid reward is a "paid" row, and
+// every account gets an "unpaid" row with its reward not paid yet. Amounts
+// are given both in the smallest unit and in N42.
+func (report *RewardReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"type", "epoch", "block", "time", "address", "amount_wei", "amount"}); err != nil {
+		return err
+	}
+	for _, e := range report.Entries {
+		if err := cw.Write([]string{
+			"paid",
+			strconv.FormatUint(uint64(e.Epoch), 10),
+			strconv.FormatUint(uint64(e.BlockNumber), 10),
+			time.Unix(int64(e.Timestamp), 0).UTC().Format(time.RFC3339),
+			e.Address.Hex(),
+			e.Amount.Dec(),
+			formatN42(e.Amount),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, a := range report.Accounts {
+		if err := cw.Write([]string{"unpaid", "", "", "", a.Address.Hex(), a.Unpaid.Dec(), formatN42(a.Unpaid)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatN42 formats an amount in the smallest unit as an exact decimal N42 amount
+func formatN42(amount *uint256.Int) string {
+	unit := big.NewInt(params.N)
+	whole, frac := new(big.Int).QuoRem(amount.ToBig(), unit, new(big.Int))
+	return fmt.Sprintf("%d.%018d", whole, frac)
+}
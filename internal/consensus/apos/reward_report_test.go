@@ -0,0 +1,150 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/params"
+)
+
+var (
+	rewardAlice = types.HexToAddress("0x1111111111111111111111111111111111111111")
+	rewardBob   = types.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// rewardChain is a chain of epoch blocks paying fixed rewards
+type rewardChain struct {
+	config *params.ChainConfig
+	blocks map[uint64]block.IBlock
+	head   block.IBlock
+}
+
+func (c *rewardChain) Config() *params.ChainConfig { return c.config }
+func (c *rewardChain) CurrentBlock() block.IBlock  { return c.head }
+func (c *rewardChain) GetBlockByNumber(number *uint256.Int) (block.IBlock, error) {
+	return c.blocks[number.Uint64()], nil
+}
+
+// newRewardChain creates a chain with an epoch of 10 blocks from block 100 on,
+// where epoch e pays e to alice and 2e to bob
+func newRewardChain(head uint64) *rewardChain {
+	c := &rewardChain{
+		config: &params.ChainConfig{
+			BeijingBlock: big.NewInt(100),
+			Apos:         &params.APosConfig{RewardEpoch: 10, RewardLimit: big.NewInt(1000)},
+		},
+		blocks: make(map[uint64]block.IBlock),
+	}
+	for number := uint64(100); number <= head; number += 10 {
+		epoch := (number - 100) / 10
+		header := &block.Header{Number: uint256.NewInt(number), Time: 1700000000 + number}
+		body := &block.Body{Rewards: []*block.Reward{
+			{Address: rewardBob, Amount: uint256.NewInt(2 * epoch)},
+			{Address: rewardAlice, Amount: uint256.NewInt(epoch)},
+		}}
+		c.blocks[number] = block.NewBlockFromStorage(header.Hash(), header, body)
+	}
+	c.head = block.NewBlockFromStorage(types.Hash{}, &block.Header{Number: uint256.NewInt(head)}, &block.Body{})
+	return c
+}
+
+func TestRewardReport(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.PutAccountReward(tx, rewardAlice, uint256.NewInt(7))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.BeginRo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// The chain ends in epoch 4, so the range is capped there
+	chain := newRewardChain(145)
+	report, err := NewRewardReport(chain, tx, 2, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ToEpoch != 4 || len(report.Entries) != 6 {
+		t.Fatalf("got epochs %d-%d with %d entries, want 2-4 with 6", report.FromEpoch, report.ToEpoch, len(report.Entries))
+	}
+	if first := report.Entries[0]; first.Epoch != 2 || first.BlockNumber != 120 || first.Address != rewardAlice {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if report.Total.Uint64() != 27 {
+		t.Errorf("total = %v, want 27", report.Total)
+	}
+	if len(report.Accounts) != 2 {
+		t.Fatalf("got %d accounts, want 2", len(report.Accounts))
+	}
+	alice, bob := report.Accounts[0], report.Accounts[1]
+	if alice.Paid.Uint64() != 9 || alice.Unpaid.Uint64() != 7 || bob.Paid.Uint64() != 18 || !bob.Unpaid.IsZero() {
+		t.Errorf("unexpected accounts %+v %+v", alice, bob)
+	}
+
+	// Filtering keeps only the requested accounts, even without rewards
+	carol := types.HexToAddress("0x3333333333333333333333333333333333333333")
+	report, err = NewRewardReport(chain, tx, 0, 4, []types.Address{rewardBob, carol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Entries) != 5 || len(report.Accounts) != 2 || report.Total.Uint64() != 20 {
+		t.Errorf("got %d entries, %d accounts and total %v", len(report.Entries), len(report.Accounts), report.Total)
+	}
+
+	if _, err := NewRewardReport(chain, tx, 5, 6, nil); err != errInvalidEpochSpan {
+		t.Errorf("expected %v, got %v", errInvalidEpochSpan, err)
+	}
+}
+
+func TestRewardReportCSV(t *testing.T) {
+	report := &RewardReport{
+		Entries: []*RewardReportEntry{{Epoch: 3, BlockNumber: 130, Timestamp: 0, Address: rewardAlice, Amount: uint256.NewInt(1500000000000000000)}},
+		Accounts: []*RewardReportAccount{
+			{Address: rewardAlice, Paid: uint256.NewInt(1500000000000000000), Unpaid: uint256.NewInt(5)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"type,epoch,block,time,address,amount_wei,amount",
+		"paid,3,130,1970-01-01T00:00:00Z," + rewardAlice.Hex() + ",1500000000000000000,1.500000000000000000",
+		"unpaid,,,," + rewardAlice.Hex() + ",5,0.000000000000000005",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
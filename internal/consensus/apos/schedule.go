@@ -0,0 +1,134 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// The proposer schedule of a height is the rotation of the authorized signers
+// in ascending address order starting at number % len(signers), the signer
+// in turn. Signers which sealed one of the recent blocks are skipped as they
+// may not seal yet. When the first proposer misses its slot the next ones
+// take over in order, each waiting wiggleTime longer than the one before, so
+// the backup block is sealed by a predictable signer instead of whoever wins
+// a random delay.
+
+var (
+	proposerSlotsInTurn = prometheus.GetOrCreateCounter(`apos_proposer_slots_total{result="inturn"}`)
+	proposerSlotsBackup = prometheus.GetOrCreateCounter(`apos_proposer_slots_total{result="backup"}`)
+	proposerSlotsMissed = prometheus.GetOrCreateCounter(`apos_proposer_slots_total{result="missed"}`)
+)
+
+// signedRecently reports whether signer sealed a block too recently to seal
+// block number.
+func (s *Snapshot) signedRecently(number uint64, signer types.Address) bool {
+	limit := uint64(len(s.Signers)/2 + 1)
+	for seen, recent := range s.Recents {
+		if recent == signer && (number < limit || seen > number-limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// proposers returns the signers allowed to seal block number on top of the
+// snapshot, in the order they take their turn.
+func (s *Snapshot) proposers(number uint64) []types.Address {
+	signers := s.signers()
+	if len(signers) == 0 {
+		return nil
+	}
+	start := int(number % uint64(len(signers)))
+	proposers := make([]types.Address, 0, len(signers))
+	for i := range signers {
+		signer := signers[(start+i)%len(signers)]
+		if !s.signedRecently(number, signer) {
+			proposers = append(proposers, signer)
+		}
+	}
+	return proposers
+}
+
+// rank returns the position of signer in the proposer schedule of block
+// number, or -1 if it may not seal the block.
+func (s *Snapshot) rank(number uint64, signer types.Address) int {
+	for i, proposer := range s.proposers(number) {
+		if proposer == signer {
+			return i
+		}
+	}
+	return -1
+}
+
+// backupDelay returns how long signer waits past the block time before
+// sealing block number, giving the proposers ranked before it their turn.
+func (s *Snapshot) backupDelay(number uint64, signer types.Address) time.Duration {
+	rank := s.rank(number, signer)
+	if rank <= 0 {
+		return 0
+	}
+	return time.Duration(rank) * wiggleTime
+}
+
+// missedProposers returns the proposers which were scheduled before signer
+// for block number and so missed their slot.
+func (s *Snapshot) missedProposers(number uint64, signer types.Address) []types.Address {
+	proposers := s.proposers(number)
+	for i, proposer := range proposers {
+		if proposer == signer {
+			return proposers[:i]
+		}
+	}
+	return nil
+}
+
+// participation records the proposer metrics of every height once, for the
+// first block seen at it, sealed locally or imported.
+type participation struct {
+	mu   sync.Mutex
+	head uint64
+}
+
+// observe records that signer sealed block number on top of snap, the
+// snapshot of its parent.
+func (p *participation) observe(snap *Snapshot, number uint64, signer types.Address) {
+	p.mu.Lock()
+	if number <= p.head {
+		p.mu.Unlock()
+		return
+	}
+	p.head = number
+	p.mu.Unlock()
+
+	missed := snap.missedProposers(number, signer)
+	if snap.inturn(number, signer) {
+		proposerSlotsInTurn.Inc()
+	} else {
+		proposerSlotsBackup.Inc()
+	}
+	proposerSlotsMissed.Add(len(missed))
+	prometheus.GetOrCreateCounter(fmt.Sprintf(`apos_proposer_sealed_total{signer=%q}`, signer.Hex())).Inc()
+	for _, proposer := range missed {
+		prometheus.GetOrCreateCounter(fmt.Sprintf(`apos_proposer_missed_total{signer=%q}`, proposer.Hex())).Inc()
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/params"
+)
+
+func TestProposerSchedule(t *testing.T) {
+	a, b, c, d := types.Address{0x01}, types.Address{0x02}, types.Address{0x03}, types.Address{0x04}
+	snap := newSnapshot(&params.APosConfig{Epoch: 30000}, nil, 9, types.Hash{}, []types.Address{d, b, a, c})
+
+	// Block 10 starts the rotation at index 10 % 4 = 2
+	if have, want := snap.proposers(10), []types.Address{c, d, a, b}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("proposers = %v, want %v", have, want)
+	}
+	if !snap.inturn(10, c) || snap.rank(10, c) != 0 || snap.backupDelay(10, c) != 0 {
+		t.Error("first proposer is not in turn without delay")
+	}
+	if have, want := snap.backupDelay(10, a), 2*wiggleTime; have != want {
+		t.Errorf("backup delay = %v, want %v", have, want)
+	}
+	if have, want := snap.missedProposers(10, a), []types.Address{c, d}; !reflect.DeepEqual(have, want) {
+		t.Errorf("missed = %v, want %v", have, want)
+	}
+
+	// d sealed block 9 and may not seal again before two more blocks
+	snap.Recents[9] = d
+	if have, want := snap.proposers(10), []types.Address{c, a, b}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("proposers with a recent signer = %v, want %v", have, want)
+	}
+	if snap.rank(10, d) != -1 || snap.backupDelay(10, a) != wiggleTime {
+		t.Error("recent signer kept in the schedule")
+	}
+	if have, want := snap.proposers(12), []types.Address{a, b, c, d}; !reflect.DeepEqual(have, want) {
+		t.Errorf("proposers after the recent window = %v, want %v", have, want)
+	}
+}
@@ -18,6 +18,7 @@
 package consensus
 
 import (
+	"runtime"
 	"sync"
 
 	lru "github.com/hashicorp/golang-lru"
@@ -163,6 +164,83 @@ func VerifyHeadersAsync(
 	return abort, results
 }
 
+// DefaultVerifyAhead is how many headers the verification of a batch may run
+// ahead of its consumer, see VerifyHeadersPipelined.
+const DefaultVerifyAhead = 256
+
+// VerifyHeadersPipelined verifies a batch of headers in two stages. prepare
+// runs the checks a header needs no parents for on up to workers goroutines,
+// defaulting to one per CPU. verify runs the checks depending on the parents
+// in the order of the input slice, on headers prepare accepted. Preparation
+// runs at most ahead headers in front of verify, and verify at most ahead
+// results in front of the consumer.
+func VerifyHeadersPipelined(
+	headers []block.IHeader,
+	workers, ahead int,
+	prepare func(header block.IHeader) error,
+	verify func(header block.IHeader, parents []block.IHeader) error,
+) (chan<- struct{}, <-chan error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if ahead <= 0 {
+		ahead = DefaultVerifyAhead
+	}
+	var (
+		abort    = make(chan struct{})
+		results  = make(chan error, ahead)
+		jobs     = make(chan int)
+		window   = make(chan struct{}, ahead)
+		prepared = make([]chan error, len(headers))
+	)
+	for i := range prepared {
+		prepared[i] = make(chan error, 1)
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range headers {
+			select {
+			case window <- struct{}{}:
+			case <-abort:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-abort:
+				return
+			}
+		}
+	}()
+	for w := 0; w < workers && w < len(headers); w++ {
+		go func() {
+			for i := range jobs {
+				prepared[i] <- prepare(headers[i])
+			}
+		}()
+	}
+	go func() {
+		for i, header := range headers {
+			var err error
+			select {
+			case err = <-prepared[i]:
+			case <-abort:
+				return
+			}
+			<-window
+			if err == nil {
+				err = verify(header, headers[:i])
+			}
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
 // CalcDifficultyWithSnapshot calculates difficulty using a snapshot that implements Inturn.
 func CalcDifficultyWithSnapshot(snap misc.Inturn, blockNumber uint64, signer types.Address) *uint256.Int {
 	return misc.CalcDifficulty(snap, blockNumber, signer)
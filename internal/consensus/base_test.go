@@ -17,8 +17,13 @@
 package consensus
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
 
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/types"
@@ -229,6 +234,72 @@ func TestVerifyHeadersAsync(t *testing.T) {
 	})
 }
 
+func TestVerifyHeadersPipelined(t *testing.T) {
+	headers := make([]block.IHeader, 64)
+	for i := range headers {
+		headers[i] = &block.Header{Number: uint256.NewInt(uint64(i + 1))}
+	}
+	errOdd := errors.New("odd header")
+
+	t.Run("Order", func(t *testing.T) {
+		var verified []uint64
+		_, results := VerifyHeadersPipelined(headers, 8, 4, func(header block.IHeader) error {
+			if header.Number64().Uint64()%2 == 1 {
+				return errOdd
+			}
+			return nil
+		}, func(header block.IHeader, parents []block.IHeader) error {
+			if len(parents) != int(header.Number64().Uint64())-1 {
+				t.Errorf("header %d: %d parents", header.Number64().Uint64(), len(parents))
+			}
+			verified = append(verified, header.Number64().Uint64())
+			return nil
+		})
+		for i := range headers {
+			err := <-results
+			if want := i%2 == 0; (err == errOdd) != want {
+				t.Errorf("header %d: have %v", i+1, err)
+			}
+		}
+		// Only headers passing the first stage reach the second
+		if len(verified) != len(headers)/2 {
+			t.Errorf("verified %d headers, want %d", len(verified), len(headers)/2)
+		}
+		for i, n := range verified {
+			if n != uint64(2*i+2) {
+				t.Fatalf("verified out of order: %v", verified)
+			}
+		}
+	})
+
+	t.Run("Ahead", func(t *testing.T) {
+		const ahead = 4
+		var prepared atomic.Int32
+		abort, results := VerifyHeadersPipelined(headers, 8, ahead, func(header block.IHeader) error {
+			prepared.Add(1)
+			return nil
+		}, func(header block.IHeader, parents []block.IHeader) error {
+			return nil
+		})
+		defer close(abort)
+
+		// Without a consumer the pipeline stops once the result buffer and
+		// the preparation window are full
+		time.Sleep(50 * time.Millisecond)
+		if n := prepared.Load(); n > 2*ahead+1 {
+			t.Errorf("prepared %d headers without a consumer, want at most %d", n, 2*ahead+1)
+		}
+		for range headers {
+			if err := <-results; err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n := prepared.Load(); n != int32(len(headers)) {
+			t.Errorf("prepared %d headers, want %d", n, len(headers))
+		}
+	})
+}
+
 // =============================================================================
 // Mock Inturn Tests
 // =============================================================================
@@ -94,7 +94,8 @@ type Engine interface {
 	VerifyHeaders(chain ChainHeaderReader, headers []block.IHeader, seals []bool) (chan<- struct{}, <-chan error)
 
 	// VerifyUncles verifies that the given block's uncles conform to the consensus
-	// rules of a given engine.
+	// rules of a given engine. N42 blocks cannot carry uncles, the header has no
+	// uncle hash and the body no uncle list, so the engines have nothing to check.
 	VerifyUncles(chain ConsensusChainReader, block block.IBlock) error
 
 	// Prepare initializes the consensus fields of a block header according to the
@@ -138,6 +139,37 @@ type Engine interface {
 	Close() error
 }
 
+// SealBatchVerifier is implemented by engines that can verify the seals of a
+// batch of blocks at once, which is cheaper than verifying them one by one.
+type SealBatchVerifier interface {
+	// VerifySeals verifies the seals of a batch of blocks. The returned slice
+	// holds the result of each block, in the order of the input slice.
+	VerifySeals(chain ChainHeaderReader, blocks []block.IBlock) []error
+}
+
+// ParallelVerifier is implemented by engines that verify batches of headers
+// on several goroutines, see VerifyHeadersPipelined.
+type ParallelVerifier interface {
+	// SetVerifyParallelism sets the number of goroutines verifying a batch
+	// and how many headers they may run ahead. Zero selects the default.
+	SetVerifyParallelism(workers, ahead int)
+}
+
+// ChainReadingFinalizer is implemented by engines whose Finalize reads
+// ancestors or chain data other than the state, e.g. to pay epoch rewards.
+// Blocks it reports are only executed once all their ancestors are committed.
+type ChainReadingFinalizer interface {
+	// FinalizeReadsChain reports whether finalizing header reads the chain.
+	FinalizeReadsChain(header block.IHeader) bool
+}
+
+// Rewinder is implemented by engines that keep track of the sealed heights
+// and must be told when the chain is rewound below them.
+type Rewinder interface {
+	// Rewind forgets everything sealed above head.
+	Rewind(head uint64)
+}
+
 // EngineReader are read-only methods of the consensus engine
 // All of these methods should have thread-safe implementations
 type EngineReader interface {
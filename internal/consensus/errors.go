@@ -42,4 +42,8 @@ var (
 	ErrInvalidNumber = errors.New("invalid block number")
 	// ErrNotEnoughSign bls Sign
 	ErrNotEnoughSign = errors.New("not enough sign")
+
+	// ErrInvalidAggSignature is returned if the aggregated signature of a block
+	// doesn't match the state root and the public keys of its verifiers.
+	ErrInvalidAggSignature = errors.New("invalid aggregated signature")
 )
@@ -0,0 +1,87 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/params"
+)
+
+// londonConfig activates London at block 5
+func londonConfig() *params.ChainConfig {
+	return &params.ChainConfig{LondonBlock: big.NewInt(5)}
+}
+
+func TestCalcBaseFee(t *testing.T) {
+	tests := []struct {
+		parentBaseFee   uint64
+		parentGasLimit  uint64
+		parentGasUsed   uint64
+		expectedBaseFee uint64
+	}{
+		{params.InitialBaseFee, 20000000, 10000000, params.InitialBaseFee}, // usage == target
+		{params.InitialBaseFee, 20000000, 9000000, 987500000},              // usage below target
+		{params.InitialBaseFee, 20000000, 11000000, 1012500000},            // usage above target
+		{0, 20000000, 20000000, 1},                                         // a zero base fee still rises
+	}
+	for i, test := range tests {
+		parent := &block.Header{
+			Number:   uint256.NewInt(32),
+			GasLimit: test.parentGasLimit,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  uint256.NewInt(test.parentBaseFee),
+		}
+		if have, want := CalcBaseFee(londonConfig(), parent), new(big.Int).SetUint64(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d, want %d", i, have, want)
+		}
+	}
+
+	// The first London block starts from the initial base fee
+	parent := &block.Header{Number: uint256.NewInt(4), GasLimit: 10000000, BaseFee: uint256.NewInt(0)}
+	if have := CalcBaseFee(londonConfig(), parent); have.Uint64() != params.InitialBaseFee {
+		t.Errorf("fork block: have %d, want %d", have, params.InitialBaseFee)
+	}
+}
+
+func TestVerifyEip1559Header(t *testing.T) {
+	parent := &block.Header{
+		Number:   uint256.NewInt(10),
+		GasLimit: 20000000,
+		GasUsed:  11000000,
+		BaseFee:  uint256.NewInt(params.InitialBaseFee),
+	}
+	header := &block.Header{
+		Number:   uint256.NewInt(11),
+		GasLimit: 20000000,
+		BaseFee:  uint256.NewInt(1012500000),
+	}
+	if err := VerifyEip1559Header(londonConfig(), parent, header); err != nil {
+		t.Fatalf("valid header rejected: %v", err)
+	}
+	header.BaseFee = uint256.NewInt(params.InitialBaseFee)
+	if err := VerifyEip1559Header(londonConfig(), parent, header); err == nil {
+		t.Error("wrong base fee accepted")
+	}
+	header.BaseFee = nil
+	if err := VerifyEip1559Header(londonConfig(), parent, header); err == nil {
+		t.Error("missing base fee accepted")
+	}
+}
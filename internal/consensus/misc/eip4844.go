@@ -0,0 +1,56 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"fmt"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/params"
+)
+
+// VerifyEip4844Header verifies the blob gas fields of a header which were
+// added by EIP-4844.
+func VerifyEip4844Header(config *params.ChainConfig, parent, header *block.Header) error {
+	if !config.IsCancun(header.Number.Uint64()) {
+		if header.BlobGasUsed != 0 || header.ExcessBlobGas != 0 {
+			return fmt.Errorf("blob gas fields before cancun: blobGasUsed %d, excessBlobGas %d", header.BlobGasUsed, header.ExcessBlobGas)
+		}
+		return nil
+	}
+	if header.BlobGasUsed > transaction.MaxBlobGasPerBlock {
+		return fmt.Errorf("blob gas used %d exceeds maximum allowance %d", header.BlobGasUsed, transaction.MaxBlobGasPerBlock)
+	}
+	if header.BlobGasUsed%transaction.BlobTxBlobGasPerBlob != 0 {
+		return fmt.Errorf("blob gas used %d not a multiple of blob gas per blob %d", header.BlobGasUsed, transaction.BlobTxBlobGasPerBlob)
+	}
+	if expected := CalcExcessBlobGas(config, parent); header.ExcessBlobGas != expected {
+		return fmt.Errorf("invalid excessBlobGas: have %d, want %d, parentExcessBlobGas %d, parentBlobGasUsed %d",
+			header.ExcessBlobGas, expected, parent.ExcessBlobGas, parent.BlobGasUsed)
+	}
+	return nil
+}
+
+// CalcExcessBlobGas calculates the excess blob gas of the child of parent.
+// The first Cancun block starts with none.
+func CalcExcessBlobGas(config *params.ChainConfig, parent *block.Header) uint64 {
+	if !config.IsCancun(parent.Number.Uint64()) {
+		return 0
+	}
+	return transaction.CalcExcessBlobGas(parent.ExcessBlobGas, parent.BlobGasUsed)
+}
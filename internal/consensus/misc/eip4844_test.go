@@ -0,0 +1,67 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/params"
+)
+
+func TestVerifyEip4844Header(t *testing.T) {
+	config := &params.ChainConfig{CancunBlock: big.NewInt(5)}
+
+	// Blob gas fields are not allowed before Cancun
+	parent := &block.Header{Number: uint256.NewInt(3)}
+	header := &block.Header{Number: uint256.NewInt(4), ExcessBlobGas: 1}
+	if err := VerifyEip4844Header(config, parent, header); err == nil {
+		t.Error("excess blob gas accepted before cancun")
+	}
+
+	// The fork block starts without excess blob gas
+	parent, header = &block.Header{Number: uint256.NewInt(4)}, &block.Header{Number: uint256.NewInt(5)}
+	if err := VerifyEip4844Header(config, parent, header); err != nil {
+		t.Errorf("fork block rejected: %v", err)
+	}
+
+	parent = &block.Header{
+		Number:        uint256.NewInt(9),
+		BlobGasUsed:   transaction.MaxBlobGasPerBlock,
+		ExcessBlobGas: transaction.BlobTxTargetBlobGasPerBlock,
+	}
+	header = &block.Header{Number: uint256.NewInt(10), ExcessBlobGas: 2 * transaction.BlobTxTargetBlobGasPerBlock}
+	if err := VerifyEip4844Header(config, parent, header); err != nil {
+		t.Errorf("valid header rejected: %v", err)
+	}
+	header.ExcessBlobGas = 0
+	if err := VerifyEip4844Header(config, parent, header); err == nil {
+		t.Error("wrong excess blob gas accepted")
+	}
+	header.ExcessBlobGas = 2 * transaction.BlobTxTargetBlobGasPerBlock
+	header.BlobGasUsed = transaction.MaxBlobGasPerBlock + transaction.BlobTxBlobGasPerBlob
+	if err := VerifyEip4844Header(config, parent, header); err == nil {
+		t.Error("blob gas above the limit accepted")
+	}
+	header.BlobGasUsed = 1
+	if err := VerifyEip4844Header(config, parent, header); err == nil {
+		t.Error("partial blob accepted")
+	}
+}
@@ -32,7 +32,7 @@ import (
 	"github.com/n42blockchain/N42/api/protocol/types_pb"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"go.uber.org/zap"
 )
 
@@ -195,7 +195,7 @@ func (d *Downloader) Start() error {
 	if d.network.Bootstrapped() {
 		//todo
 		//log.Debugf("boot node")
-		event.GlobalEvent.Send(common.DownloaderFinishEvent{})
+		common.DownloaderFinishTopic.Send(common.DownloaderFinishEvent{})
 		return nil
 	}
 
@@ -299,9 +299,7 @@ func (d *Downloader) pubSubLoop() {
 	}()
 	defer d.cancel()
 
-	highestBlockCh := make(chan common.ChainHighestBlock)
-	defer close(highestBlockCh)
-	highestSub := event.GlobalEvent.Subscribe(highestBlockCh)
+	highestSub := common.ChainHeadTopic.Subscribe(1, eventv3.DropOldest)
 	defer highestSub.Unsubscribe()
 
 	for {
@@ -311,8 +309,8 @@ func (d *Downloader) pubSubLoop() {
 		case err := <-highestSub.Err():
 			log.Debugf("receive a err from highestSub %v", err)
 			return
-		case highestBlock, ok := <-highestBlockCh:
-			if ok && highestBlock.Block.Number64().Uint64() > d.highestNumber.Uint64() {
+		case highestBlock := <-highestSub.Chan():
+			if highestBlock.Block.Number64().Uint64() > d.highestNumber.Uint64() {
 				log.Debugf("receive a new highestBlock block number: %d", highestBlock.Block.Number64().Uint64())
 				d.highestNumber = *highestBlock.Block.Number64()
 				if highestBlock.Inserted {
@@ -333,8 +331,8 @@ func (d *Downloader) synchronise() {
 	//
 	d.waitAvailablePeer()
 	//
-	event.GlobalEvent.Send(common.DownloaderStartEvent{})
-	defer event.GlobalEvent.Send(common.DownloaderFinishEvent{})
+	common.DownloaderStartTopic.Send(common.DownloaderStartEvent{})
+	defer common.DownloaderFinishTopic.Send(common.DownloaderFinishEvent{})
 
 	defer d.cancel()
 	tick := time.NewTicker(syncTimeTick)
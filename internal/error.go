@@ -102,6 +102,53 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 
+	// ErrMaxInitCodeSizeExceeded is returned if creation transaction provides the init code bigger
+	// than init code size limit.
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+
+	// ErrOversizedTx is returned if the encoded transaction is larger than the
+	// transaction size limit of the chain.
+	ErrOversizedTx = errors.New("oversized transaction")
+
+	// ErrOversizedBlock is returned if the encoded block is larger than the
+	// block size limit of the chain.
+	ErrOversizedBlock = errors.New("oversized block")
+
+	// ErrBlockGasLimitExceeded is returned if a block declares or uses more gas
+	// than it is allowed to.
+	ErrBlockGasLimitExceeded = errors.New("block gas limit exceeded")
+
+	// ErrBlobFeeCapTooLow is returned if the transaction fee cap per blob gas
+	// is less than the blob base fee of the block.
+	ErrBlobFeeCapTooLow = errors.New("max fee per blob gas less than block blob base fee")
+
+	// ErrMissingBlobHashes is returned if a blob transaction carries no blobs.
+	ErrMissingBlobHashes = errors.New("blob transaction missing blob hashes")
+
+	// ErrBlobGasLimitExceeded is returned if a block uses more blob gas than
+	// it is allowed to.
+	ErrBlobGasLimitExceeded = errors.New("block blob gas limit exceeded")
+
+	// ErrBlobGasUsedMismatch is returned if the blob gas used declared by a
+	// header differs from the blob gas of its transactions.
+	ErrBlobGasUsedMismatch = errors.New("block blob gas used mismatch")
+
+	// ErrEmptyAuthList is returned if a set code transaction has no
+	// authorizations.
+	ErrEmptyAuthList = errors.New("set code transaction with empty auth list")
+
+	// ErrSetCodeTxCreate is returned if a set code transaction creates a
+	// contract.
+	ErrSetCodeTxCreate = errors.New("set code transaction cannot be used to create contract")
+
+	// The errors below only invalidate a single authorization of a set code
+	// transaction, which is skipped.
+	ErrAuthorizationWrongChainID       = errors.New("EIP-7702 authorization chain ID mismatch")
+	ErrAuthorizationNonceOverflow      = errors.New("EIP-7702 authorization nonce > 64 bit")
+	ErrAuthorizationInvalidSignature   = errors.New("EIP-7702 authorization has invalid signature")
+	ErrAuthorizationDestinationHasCode = errors.New("EIP-7702 authorization destination is a contract")
+	ErrAuthorizationNonceMismatch      = errors.New("EIP-7702 authorization nonce does not match current account nonce")
+
 	// ErrAlreadyDeposited already deposited
 	ErrAlreadyDeposited = errors.New("already deposited")
 )
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/holiman/uint256"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/internal/vm/evmtypes"
@@ -67,14 +68,18 @@ func NewEVMBlockContext(header *block.Header, blockHashFunc func(n uint64) types
 		BaseFee:     &baseFee,
 		GasLimit:    header.GasLimit,
 		PrevRanDao:  prevRandDao,
+
+		BlobBaseFee:   transaction.CalcBlobFee(header.ExcessBlobGas),
+		ExcessBlobGas: header.ExcessBlobGas,
 	}
 }
 
 // NewEVMTxContext creates a new transaction context for a single transaction.
 func NewEVMTxContext(msg Message) evmtypes.TxContext {
 	return evmtypes.TxContext{
-		Origin:   msg.From(),
-		GasPrice: msg.GasPrice(),
+		Origin:     msg.From(),
+		GasPrice:   msg.GasPrice(),
+		BlobHashes: msg.BlobHashes(),
 	}
 }
 
@@ -0,0 +1,89 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+var errReorgFinalized = errors.New("reorg below the finalized block")
+
+// justified reports whether the aggregated signature of blk was signed by more
+// than two thirds of the deposited verifiers. Blocks without verifier
+// signatures, such as all blocks before Beijing, are never justified.
+func justified(blk block.IBlock, depositors uint64) bool {
+	if depositors == 0 || blk.Body() == nil {
+		return false
+	}
+	signers := make(map[types.Address]struct{})
+	for _, v := range blk.Body().Verifier() {
+		signers[v.Address] = struct{}{}
+	}
+	return uint64(len(signers))*3 > depositors*2
+}
+
+// updateFinality moves the finality checkpoints after blk became the canonical
+// head. A justified head becomes the safe block, and its parent is finalized
+// once it is justified as well, so two consecutive justified blocks are needed
+// before anything is considered final. The finalized block never moves back.
+func (bc *BlockChain) updateFinality(tx kv.RwTx, blk block.IBlock) error {
+	depositors, err := rawdb.DepositNum(tx)
+	if err != nil {
+		return err
+	}
+	if !justified(blk, depositors) {
+		return nil
+	}
+	if err := rawdb.WriteSafeBlockHash(tx, blk.Hash()); err != nil {
+		return err
+	}
+
+	number := blk.Number64().Uint64()
+	if number == 0 || number-1 <= bc.finalizedNumber(tx) {
+		return nil
+	}
+	parent := rawdb.ReadBlock(tx, blk.ParentHash(), number-1)
+	if parent == nil || !justified(parent, depositors) {
+		return nil
+	}
+	if err := rawdb.WriteFinalizedBlockHash(tx, parent.Hash()); err != nil {
+		return err
+	}
+	finalizedBlockGauge.Set(number - 1)
+	log.Debug("Finalized block", "number", number-1, "hash", parent.Hash())
+	return nil
+}
+
+// finalizedNumber returns the number of the latest finalized block, or 0 if
+// nothing was finalized yet.
+func (bc *BlockChain) finalizedNumber(tx kv.Getter) uint64 {
+	hash := rawdb.ReadFinalizedBlockHash(tx)
+	if hash == (types.Hash{}) {
+		return 0
+	}
+	if number := rawdb.ReadHeaderNumber(tx, hash); number != nil {
+		return *number
+	}
+	return 0
+}
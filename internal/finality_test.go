@@ -0,0 +1,103 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/turbo/rpchelper"
+)
+
+func TestUpdateFinality(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+
+	verifiers := make([]*block.Verify, 4)
+	for i := range verifiers {
+		verifiers[i] = &block.Verify{Address: types.Address{byte(i + 1)}}
+	}
+	// Three of four verifiers justify a block, two do not
+	signers := []int{0, 3, 2, 3, 4}
+
+	bc := &BlockChain{}
+	err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for _, v := range verifiers {
+			if err := rawdb.PutDeposit(tx, v.Address, types.PublicKey{}, *uint256.NewInt(1)); err != nil {
+				return err
+			}
+		}
+		parent := types.Hash{}
+		for number, n := range signers {
+			header := &block.Header{Number: uint256.NewInt(uint64(number)), ParentHash: parent, Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0)}
+			blk := block.NewBlockFromStorage(header.Hash(), header, &block.Body{Verifiers: verifiers[:n]})
+			if err := rawdb.WriteBlock(tx, blk); err != nil {
+				return err
+			}
+			if err := rawdb.WriteCanonicalHash(tx, blk.Hash(), uint64(number)); err != nil {
+				return err
+			}
+			if err := bc.updateFinality(tx, blk); err != nil {
+				return err
+			}
+			parent = blk.Hash()
+
+			safe, _ := rpchelper.GetSafeBlockNumber(tx)
+			finalized, _ := rpchelper.GetFinalizedBlockNumber(tx)
+			var wantSafe, wantFinalized uint64
+			switch number {
+			case 1, 2:
+				wantSafe = 1
+			case 3:
+				wantSafe = 3
+			case 4:
+				wantSafe, wantFinalized = 4, 3
+			}
+			if safe.Uint64() != wantSafe || finalized.Uint64() != wantFinalized {
+				t.Errorf("block %d: safe %d finalized %d, want %d and %d", number, safe, finalized, wantSafe, wantFinalized)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJustified(t *testing.T) {
+	signers := []*block.Verify{{Address: types.Address{0x01}}, {Address: types.Address{0x02}}, {Address: types.Address{0x01}}}
+	blk := block.NewBlockFromStorage(types.Hash{}, &block.Header{Number: uint256.NewInt(1)}, &block.Body{Verifiers: signers})
+	if !justified(blk, 2) {
+		t.Error("block signed by all verifiers not justified")
+	}
+	// Duplicate signers are counted once
+	if justified(blk, 3) {
+		t.Error("block signed by two of three verifiers justified")
+	}
+	if justified(blk, 0) {
+		t.Error("block justified without verifiers")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/params"
+)
+
+// freezeInterval is how often the chain is checked for blocks to freeze.
+const freezeInterval = time.Minute
+
+// SetFreezer makes the chain move blocks into f once they are immutable and
+// more than threshold blocks below the head. It must be called before Start.
+func (bc *BlockChain) SetFreezer(f *rawdb.Freezer, threshold uint64) {
+	bc.freezer = f
+	bc.freezeThreshold = threshold
+}
+
+// freezeLimit returns the number of blocks from genesis that may be frozen: a
+// block must be finalized, or deeper than FullImmutabilityThreshold on chains
+// without finality, and at least freezeThreshold blocks below the head.
+func (bc *BlockChain) freezeLimit(tx kv.Getter) uint64 {
+	head := bc.CurrentBlock().Number64().Uint64()
+	if head < bc.freezeThreshold {
+		return 0
+	}
+	immutable := bc.finalizedNumber(tx)
+	if head > params.FullImmutabilityThreshold && head-params.FullImmutabilityThreshold > immutable {
+		immutable = head - params.FullImmutabilityThreshold
+	}
+	return min(immutable, head-bc.freezeThreshold) + 1
+}
+
+// freezeLoop periodically moves the immutable blocks into the freezer.
+func (bc *BlockChain) freezeLoop() {
+	defer bc.loopWg.Done()
+	ticker := time.NewTicker(freezeInterval)
+	defer ticker.Stop()
+	for {
+		var limit uint64
+		if err := bc.ChainDB.View(bc.ctx, func(tx kv.Tx) error {
+			limit = bc.freezeLimit(tx)
+			return nil
+		}); err != nil {
+			log.Error("Failed to compute freezer limit", "err", err)
+		} else if limit > bc.freezer.Ancients() {
+			start := time.Now()
+			moved, err := bc.freezer.Freeze(bc.ctx, bc.ChainDB, limit)
+			if err != nil && !errors.Is(err, bc.ctx.Err()) {
+				log.Error("Failed to freeze blocks", "err", err)
+			} else if moved > 0 {
+				log.Info("Moved blocks into the ancient store", "count", moved, "frozen", bc.freezer.Ancients(), "elapsed", time.Since(start))
+			}
+		}
+		select {
+		case <-bc.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/n42blockchain/N42/common/block"
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+const (
+	// futureBlockMaxAge is how long a block may wait for its parent or its
+	// timestamp before it is dropped.
+	futureBlockMaxAge = maxTimeFutureBlocks * time.Second
+	// futureBlockSweep is how often expired blocks are dropped.
+	futureBlockSweep = 30 * time.Second
+)
+
+var (
+	futureBlocksGauge    = prometheus.GetOrCreateCounter("chain_future_blocks", true)
+	futureQueuedMeter    = prometheus.GetOrCreateCounter(`chain_future_blocks_total{result="queued"}`)
+	futureInsertedMeter  = prometheus.GetOrCreateCounter(`chain_future_blocks_total{result="inserted"}`)
+	futureExpiredMeter   = prometheus.GetOrCreateCounter(`chain_future_blocks_total{result="expired"}`)
+	futureEvictedMeter   = prometheus.GetOrCreateCounter(`chain_future_blocks_total{result="evicted"}`)
+	futureDiscardedMeter = prometheus.GetOrCreateCounter(`chain_future_blocks_total{result="discarded"}`)
+)
+
+// futureBlock is a block waiting in the future queue.
+type futureBlock struct {
+	blk   *block.Block
+	added time.Time
+	index int // position in the due heap, -1 once due
+}
+
+// futureHeap orders waiting blocks by timestamp.
+type futureHeap []*futureBlock
+
+func (h futureHeap) Len() int           { return len(h) }
+func (h futureHeap) Less(i, j int) bool { return h[i].blk.Time() < h[j].blk.Time() }
+func (h futureHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *futureHeap) Push(x any) {
+	b := x.(*futureBlock)
+	b.index = len(*h)
+	*h = append(*h, b)
+}
+
+func (h *futureHeap) Pop() any {
+	old := *h
+	b := old[len(old)-1]
+	old[len(old)-1] = nil
+	b.index = -1
+	*h = old[:len(old)-1]
+	return b
+}
+
+// futureBlocks holds blocks that cannot be inserted yet, either because their
+// timestamp is ahead of the local clock or because their parent has not
+// arrived. Blocks are indexed by parent, so a parent being written schedules
+// its descendants at once, and by timestamp, so blocks from the future are
+// retried when they become due.
+type futureBlocks struct {
+	mu       sync.Mutex
+	blocks   map[types.Hash]*futureBlock
+	children map[types.Hash][]types.Hash // parent hash -> waiting children
+	due      futureHeap
+	ready    map[types.Hash]struct{} // written parents with waiting children
+	wake     chan struct{}
+	limit    int
+	maxAge   time.Duration
+}
+
+func newFutureBlocks(limit int, maxAge time.Duration) *futureBlocks {
+	return &futureBlocks{
+		blocks:   make(map[types.Hash]*futureBlock),
+		children: make(map[types.Hash][]types.Hash),
+		ready:    make(map[types.Hash]struct{}),
+		wake:     make(chan struct{}, 1),
+		limit:    limit,
+		maxAge:   maxAge,
+	}
+}
+
+// add queues blk and reports whether it was not queued already. When the
+// queue is full, expired blocks and then the block furthest ahead are
+// dropped to make room.
+func (f *futureBlocks) add(blk *block.Block, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	hash := blk.Hash()
+	if _, ok := f.blocks[hash]; ok {
+		return false
+	}
+	if len(f.blocks) >= f.limit {
+		f.expireLocked(now)
+	}
+	if len(f.blocks) >= f.limit {
+		var furthest *futureBlock
+		for _, b := range f.blocks {
+			if furthest == nil || b.blk.Number64().Cmp(furthest.blk.Number64()) > 0 {
+				furthest = b
+			}
+		}
+		if furthest.blk.Number64().Cmp(blk.Number64()) <= 0 {
+			futureEvictedMeter.Inc()
+			return false
+		}
+		f.removeLocked(furthest.blk.Hash())
+		futureEvictedMeter.Inc()
+	}
+	b := &futureBlock{blk: blk, added: now}
+	f.blocks[hash] = b
+	f.children[blk.ParentHash()] = append(f.children[blk.ParentHash()], hash)
+	heap.Push(&f.due, b)
+	futureQueuedMeter.Inc()
+	futureBlocksGauge.Set(uint64(len(f.blocks)))
+	return true
+}
+
+func (f *futureBlocks) contains(hash types.Hash) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.blocks[hash]
+	return ok
+}
+
+func (f *futureBlocks) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.blocks)
+}
+
+// connected drops the written block hash from the queue and schedules the
+// blocks waiting for it.
+func (f *futureBlocks) connected(hash types.Hash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removeLocked(hash)
+	if len(f.children[hash]) > 0 {
+		f.readyLocked(hash)
+	}
+}
+
+// parentKnown schedules the blocks waiting for a parent that is already in
+// the chain.
+func (f *futureBlocks) parentKnown(hash types.Hash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.children[hash]) > 0 {
+		f.readyLocked(hash)
+	}
+}
+
+func (f *futureBlocks) readyLocked(parent types.Hash) {
+	f.ready[parent] = struct{}{}
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// discard drops hash, which failed to import, together with its waiting
+// descendants.
+func (f *futureBlocks) discard(hash types.Hash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.blocks)
+	f.removeLocked(hash)
+	f.takeLocked(hash)
+	if dropped := n - len(f.blocks); dropped > 0 {
+		futureDiscardedMeter.Add(dropped)
+	}
+}
+
+// takeReady returns the written parents whose waiting blocks were not taken
+// yet.
+func (f *futureBlocks) takeReady() []types.Hash {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parents := make([]types.Hash, 0, len(f.ready))
+	for hash := range f.ready {
+		parents = append(parents, hash)
+	}
+	clear(f.ready)
+	return parents
+}
+
+// takeDue returns the blocks whose timestamp has been reached, oldest first.
+// They stay queued until their descendants are taken.
+func (f *futureBlocks) takeDue(now time.Time) []*block.Block {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var due []*block.Block
+	for f.due.Len() > 0 && f.due[0].blk.Time() <= uint64(now.Unix()) {
+		due = append(due, heap.Pop(&f.due).(*futureBlock).blk)
+	}
+	return due
+}
+
+// nextDue returns the earliest timestamp among the waiting blocks.
+func (f *futureBlocks) nextDue() (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.due.Len() == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f.due[0].blk.Time()), 0), true
+}
+
+// take removes the blocks descending from parent and returns them as
+// linked chains, each starting at a child of parent or at a fork point
+// of an earlier chain, parents always before children.
+func (f *futureBlocks) take(parent types.Hash) [][]block.IBlock {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.takeLocked(parent)
+}
+
+func (f *futureBlocks) takeLocked(parent types.Hash) [][]block.IBlock {
+	var chains [][]block.IBlock
+	roots := f.childrenLocked(parent)
+	for len(roots) > 0 {
+		b := roots[0]
+		roots = roots[1:]
+		var chain []block.IBlock
+		for b != nil {
+			hash := b.blk.Hash()
+			chain = append(chain, b.blk)
+			f.removeLocked(hash)
+			next := f.childrenLocked(hash)
+			b = nil
+			if len(next) > 0 {
+				b, roots = next[0], append(roots, next[1:]...)
+			}
+		}
+		chains = append(chains, chain)
+	}
+	futureBlocksGauge.Set(uint64(len(f.blocks)))
+	return chains
+}
+
+// childrenLocked returns the waiting children of parent by number and hash,
+// so the first fork taken is deterministic.
+func (f *futureBlocks) childrenLocked(parent types.Hash) []*futureBlock {
+	hashes := f.children[parent]
+	children := make([]*futureBlock, 0, len(hashes))
+	for _, hash := range hashes {
+		children = append(children, f.blocks[hash])
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if c := children[i].blk.Number64().Cmp(children[j].blk.Number64()); c != 0 {
+			return c < 0
+		}
+		return children[i].blk.Hash().String() < children[j].blk.Hash().String()
+	})
+	return children
+}
+
+// expire drops the blocks that waited longer than the maximum age.
+func (f *futureBlocks) expire(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expireLocked(now)
+}
+
+func (f *futureBlocks) expireLocked(now time.Time) {
+	for hash, b := range f.blocks {
+		if now.Sub(b.added) > f.maxAge {
+			f.removeLocked(hash)
+			futureExpiredMeter.Inc()
+		}
+	}
+}
+
+func (f *futureBlocks) purge() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.blocks)
+	clear(f.children)
+	clear(f.ready)
+	f.due = f.due[:0]
+	futureBlocksGauge.Set(0)
+}
+
+// removeLocked drops hash from the queue. Its waiting children stay indexed
+// under it.
+func (f *futureBlocks) removeLocked(hash types.Hash) {
+	b, ok := f.blocks[hash]
+	if !ok {
+		return
+	}
+	delete(f.blocks, hash)
+	parent := b.blk.ParentHash()
+	siblings := f.children[parent]
+	for i, h := range siblings {
+		if h == hash {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+	if len(siblings) == 0 {
+		delete(f.children, parent)
+	} else {
+		f.children[parent] = siblings
+	}
+	if b.index >= 0 {
+		heap.Remove(&f.due, b.index)
+	}
+	futureBlocksGauge.Set(uint64(len(f.blocks)))
+}
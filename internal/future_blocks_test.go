@@ -0,0 +1,124 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+func newFutureTestBlock(parent block.IBlock, number, ts uint64, extra byte) *block.Block {
+	header := &block.Header{Number: uint256.NewInt(number), Time: ts, Extra: []byte{extra}}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+	}
+	return block.NewBlock(header, nil).(*block.Block)
+}
+
+// TestFutureBlocksParentTrigger checks that writing a parent schedules its
+// waiting descendants as linked chains, forks after their fork point.
+func TestFutureBlocksParentTrigger(t *testing.T) {
+	now := time.Now()
+	f := newFutureBlocks(16, time.Minute)
+
+	root := newFutureTestBlock(nil, 1, 1, 0)
+	a2 := newFutureTestBlock(root, 2, 2, 0)
+	a3 := newFutureTestBlock(a2, 3, 3, 0)
+	b3 := newFutureTestBlock(a2, 3, 3, 1)
+	for _, blk := range []*block.Block{a3, b3, a2} {
+		if !f.add(blk, now) {
+			t.Fatalf("block %d not queued", blk.Number64().Uint64())
+		}
+	}
+	if f.add(a2, now) {
+		t.Fatal("block queued twice")
+	}
+
+	f.connected(types.Hash{0x01})
+	select {
+	case <-f.wake:
+		t.Fatal("woken for an unrelated block")
+	default:
+	}
+	f.connected(root.Hash())
+	select {
+	case <-f.wake:
+	default:
+		t.Fatal("not woken for a waiting parent")
+	}
+	parents := f.takeReady()
+	if len(parents) != 1 || parents[0] != root.Hash() {
+		t.Fatalf("ready parents %v, want %v", parents, root.Hash())
+	}
+	chains := f.take(root.Hash())
+	if len(chains) != 2 || len(chains[0]) != 2 || len(chains[1]) != 1 {
+		t.Fatalf("chains %v, want a linked pair and a fork", chains)
+	}
+	if chains[0][0].Hash() != a2.Hash() || chains[0][1].ParentHash() != a2.Hash() || chains[1][0].ParentHash() != a2.Hash() {
+		t.Fatal("chains not linked to their parents")
+	}
+	if f.len() != 0 {
+		t.Fatalf("%d blocks left queued", f.len())
+	}
+}
+
+func TestFutureBlocksDueAndLimits(t *testing.T) {
+	now := time.Now()
+	f := newFutureBlocks(2, time.Minute)
+
+	root := newFutureTestBlock(nil, 1, 1, 0)
+	later := newFutureTestBlock(root, 2, uint64(now.Unix())+60, 0)
+	due := newFutureTestBlock(root, 2, uint64(now.Unix()), 1)
+	f.add(later, now)
+	f.add(due, now)
+	if next, ok := f.nextDue(); !ok || next.Unix() != now.Unix() {
+		t.Fatalf("next due %v, want %v", next, now)
+	}
+	if blocks := f.takeDue(now); len(blocks) != 1 || blocks[0].Hash() != due.Hash() {
+		t.Fatalf("due blocks %v, want %v", blocks, due.Hash())
+	}
+	if !f.contains(due.Hash()) {
+		t.Fatal("due block no longer waits for its parent")
+	}
+
+	// A full queue drops the block furthest ahead
+	far := newFutureTestBlock(later, 3, 3, 0)
+	if f.add(far, now) {
+		t.Fatal("block beyond a full queue queued")
+	}
+	near := newFutureTestBlock(nil, 1, 1, 2)
+	if !f.add(near, now) || f.len() != 2 {
+		t.Fatal("nearer block did not replace the furthest one")
+	}
+
+	// Expired blocks go, and a discarded block takes its descendants along
+	f.expire(now.Add(2 * time.Minute))
+	if f.len() != 0 {
+		t.Fatalf("%d blocks left after expiry", f.len())
+	}
+	f.add(later, now)
+	f.add(far, now)
+	f.discard(later.Hash())
+	if f.len() != 0 {
+		t.Fatalf("%d blocks left after discard", f.len())
+	}
+}
@@ -257,6 +257,20 @@ func GenesisByChainName(chain string) *conf.Genesis {
 	}
 }
 
+// DevGenesisBlock returns the genesis of the --dev chain, funding every
+// faucet with 10000 coins.
+func DevGenesisBlock(faucets []types.Address) *conf.Genesis {
+	alloc := make(conf.GenesisAlloc, len(faucets))
+	for _, faucet := range faucets {
+		alloc[faucet] = conf.GenesisAccount{Balance: "10000000000000000000000"}
+	}
+	return &conf.Genesis{
+		Config:   params.DevChainConfig,
+		GasLimit: 30000000,
+		Alloc:    alloc,
+	}
+}
+
 // DefaultGenesisBlock returns the Ethereum main net genesis block.
 func mainnetGenesisBlock() *conf.Genesis {
 	return &conf.Genesis{
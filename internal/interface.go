@@ -28,6 +28,10 @@ type Validator interface {
 	// ValidateBody validates the given block's content.
 	ValidateBody(block block.IBlock) error
 
+	// ValidateSeals verifies the aggregated verifier signatures of a batch of
+	// blocks, returning the result of each block in the order of the input.
+	ValidateSeals(blocks []block.IBlock) []error
+
 	// ValidateState validates the given statedb and optionally the receipts and
 	// gas used.
 	ValidateState(block block.IBlock, state *state.IntraBlockState, receipts block.Receipts, usedGas uint64) error
@@ -0,0 +1,189 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	vm2 "github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+const (
+	maxBundlesPerBlock   = 128 // Bundles kept for a single target block
+	maxBundleBlocksAhead = 64  // How far beyond the head a bundle may target
+)
+
+var (
+	errEmptyBundle    = errors.New("bundle has no transactions")
+	errBundleTooLarge = fmt.Errorf("bundle has more than %d transactions", transaction.MaxBundleTxs)
+	errBundleBlobTx   = errors.New("bundle contains a blob transaction")
+	errBundleBlock    = errors.New("bundle targets a past or too distant block")
+	errBundlesFull    = errors.New("too many bundles for the block")
+	errBundleKnown    = errors.New("bundle already known")
+	errBundleReverted = errors.New("bundle transaction reverted")
+)
+
+// bundlePool keeps the bundles submitted for the next blocks.
+type bundlePool struct {
+	mu      sync.Mutex
+	bundles map[uint64][]*transaction.Bundle // By target block number
+}
+
+func newBundlePool() *bundlePool {
+	return &bundlePool{bundles: make(map[uint64][]*transaction.Bundle)}
+}
+
+// add stores bundle unless it cannot be included on top of the head block.
+func (p *bundlePool) add(bundle *transaction.Bundle, head uint64) error {
+	switch {
+	case len(bundle.Txs) == 0:
+		return errEmptyBundle
+	case len(bundle.Txs) > transaction.MaxBundleTxs:
+		return errBundleTooLarge
+	case bundle.BlockNumber <= head || bundle.BlockNumber > head+maxBundleBlocksAhead:
+		return errBundleBlock
+	}
+	for _, tx := range bundle.Txs {
+		if tx.BlobGas() > 0 {
+			return errBundleBlobTx
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(head)
+	list := p.bundles[bundle.BlockNumber]
+	if len(list) >= maxBundlesPerBlock {
+		return errBundlesFull
+	}
+	hash := bundle.Hash()
+	for _, b := range list {
+		if b.Hash() == hash {
+			return errBundleKnown
+		}
+	}
+	p.bundles[bundle.BlockNumber] = append(list, bundle)
+	return nil
+}
+
+// eligible returns the bundles which may be included in the block with the
+// given number and timestamp, in the order they were submitted.
+func (p *bundlePool) eligible(number, timestamp uint64) []*transaction.Bundle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(number - 1)
+	var bundles []*transaction.Bundle
+	for _, b := range p.bundles[number] {
+		if b.Eligible(number, timestamp) {
+			bundles = append(bundles, b)
+		}
+	}
+	return bundles
+}
+
+// prune drops the bundles for blocks up to head.
+func (p *bundlePool) prune(head uint64) {
+	for n := range p.bundles {
+		if n <= head {
+			delete(p.bundles, n)
+		}
+	}
+}
+
+// bundleSize returns the encoded size of the transactions of bundle.
+func bundleSize(bundle *transaction.Bundle) (size uint64) {
+	for _, tx := range bundle.Txs {
+		size += tx.Size()
+	}
+	return size
+}
+
+// commitBundles includes the bundles at the top of the block of env, each one
+// completely or not at all. The state cannot be reverted across transactions,
+// so every bundle is first applied to a scratch state following the block
+// and only committed with commitTx if none of its transactions fails or
+// reverts without being allowed to. It returns the block size left.
+func (w *worker) commitBundles(bundles []*transaction.Bundle, env *environment, reader state.StateReader, hashFn func(n uint64) types.Hash, sizeLeft uint64, commitTx func(tx *transaction.Transaction) error) uint64 {
+	var scratch *state.IntraBlockState
+	for _, bundle := range bundles {
+		size := bundleSize(bundle)
+		if size > sizeLeft {
+			log.Trace("Skipping bundle exceeding block size", "hash", bundle.Hash(), "size", size, "left", sizeLeft)
+			continue
+		}
+		// Rebuild the scratch state after a failed bundle.
+		if scratch == nil {
+			scratch = w.replayState(env, reader, hashFn)
+		}
+		gp := new(common.GasPool).AddGas(env.gasPool.Gas())
+		usedGas := env.header.GasUsed
+		results, err := internal.ApplyBundle(w.chainConfig, hashFn, w.engine, &env.coinbase, gp, scratch, env.header, bundle, len(env.txs), &usedGas, vm2.Config{})
+		if err == nil {
+			err = checkBundleResults(bundle, results)
+		}
+		if err != nil {
+			log.Debug("Skipping failed bundle", "hash", bundle.Hash(), "err", err)
+			scratch = nil
+			continue
+		}
+		for _, tx := range bundle.Txs {
+			if err := commitTx(tx); err != nil {
+				// The scratch state followed the block, so this is a bug.
+				log.Error("Failed to commit simulated bundle", "hash", bundle.Hash(), "tx", tx.Hash(), "err", err)
+				scratch = nil
+				break
+			}
+		}
+		sizeLeft -= size
+	}
+	return sizeLeft
+}
+
+// replayState returns a new state with the transactions of env applied.
+func (w *worker) replayState(env *environment, reader state.StateReader, hashFn func(n uint64) types.Hash) *state.IntraBlockState {
+	ibs := state.New(reader)
+	gp := new(common.GasPool).AddGas(env.header.GasLimit)
+	var usedGas uint64
+	for i, tx := range env.txs {
+		ibs.Prepare(tx.Hash(), types.Hash{}, i)
+		if _, _, err := internal.ApplyTransaction(w.chainConfig, hashFn, w.engine, &env.coinbase, gp, ibs, state.NewNoopWriter(), env.header, tx, &usedGas, vm2.Config{}); err != nil {
+			log.Error("Failed to replay block transaction", "hash", tx.Hash(), "err", err)
+		}
+	}
+	return ibs
+}
+
+// checkBundleResults fails if a transaction of bundle reverted without being
+// listed as allowed to.
+func checkBundleResults(bundle *transaction.Bundle, results []*internal.BundleTxResult) error {
+	for _, res := range results {
+		if res.Receipt.Status == block.ReceiptStatusFailed && !bundle.CanRevert(res.Tx.Hash()) {
+			return fmt.Errorf("%w: %v", errBundleReverted, res.Tx.Hash())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/internal"
+)
+
+func TestBundlePool(t *testing.T) {
+	p := newBundlePool()
+	first := &transaction.Bundle{Txs: []*transaction.Transaction{newOrderingTx(orderAlice, 0, 1)}, BlockNumber: 11}
+	late := &transaction.Bundle{Txs: []*transaction.Transaction{newOrderingTx(orderBob, 0, 2)}, BlockNumber: 11, MinTimestamp: 200}
+	next := &transaction.Bundle{Txs: []*transaction.Transaction{newOrderingTx(orderCarol, 0, 3)}, BlockNumber: 12}
+
+	for _, b := range []*transaction.Bundle{first, late, next} {
+		if err := p.add(b, 10); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	if err := p.add(first, 10); !errors.Is(err, errBundleKnown) {
+		t.Errorf("duplicate bundle: got %v, want %v", err, errBundleKnown)
+	}
+	if err := p.add(&transaction.Bundle{BlockNumber: 11}, 10); !errors.Is(err, errEmptyBundle) {
+		t.Errorf("empty bundle: got %v, want %v", err, errEmptyBundle)
+	}
+	for _, number := range []uint64{10, 11 + maxBundleBlocksAhead} {
+		stale := &transaction.Bundle{Txs: first.Txs, BlockNumber: number}
+		if err := p.add(stale, 10); !errors.Is(err, errBundleBlock) {
+			t.Errorf("bundle for block %d: got %v, want %v", number, err, errBundleBlock)
+		}
+	}
+
+	if bundles := p.eligible(11, 100); len(bundles) != 1 || bundles[0] != first {
+		t.Errorf("got %d bundles at time 100, want the first one", len(bundles))
+	}
+	if bundles := p.eligible(11, 200); len(bundles) != 2 || bundles[0] != first || bundles[1] != late {
+		t.Errorf("got %d bundles at time 200, want both in submission order", len(bundles))
+	}
+	// Building block 12 forgets the bundles of block 11
+	if bundles := p.eligible(12, 300); len(bundles) != 1 || bundles[0] != next {
+		t.Errorf("got %d bundles for block 12, want one", len(bundles))
+	}
+	if _, ok := p.bundles[11]; ok {
+		t.Error("bundles of a past block were kept")
+	}
+}
+
+func TestCheckBundleResults(t *testing.T) {
+	ok, reverted := newOrderingTx(orderAlice, 0, 1), newOrderingTx(orderAlice, 1, 2)
+	bundle := &transaction.Bundle{Txs: []*transaction.Transaction{ok, reverted}}
+	results := []*internal.BundleTxResult{
+		{Tx: ok, Receipt: &block.Receipt{Status: block.ReceiptStatusSuccessful}},
+		{Tx: reverted, Receipt: &block.Receipt{Status: block.ReceiptStatusFailed}},
+	}
+	if err := checkBundleResults(bundle, results); !errors.Is(err, errBundleReverted) {
+		t.Errorf("got %v, want %v", err, errBundleReverted)
+	}
+	bundle.RevertingTxHashes = append(bundle.RevertingTxHashes, reverted.Hash())
+	if err := checkBundleResults(bundle, results); err != nil {
+		t.Errorf("allowed revert rejected: %v", err)
+	}
+}
@@ -20,11 +20,12 @@ import (
 	"context"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/conf"
 	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"golang.org/x/sync/errgroup"
 	"time"
 )
@@ -70,10 +71,9 @@ func (m *Miner) Start() {
 
 func (m *Miner) runLoop() error {
 	defer m.cancel()
-	startCh := make(chan common.DownloaderFinishEvent)
-	doneCh := make(chan common.DownloaderStartEvent)
-	start := event.GlobalEvent.Subscribe(startCh)
-	done := event.GlobalEvent.Subscribe(doneCh)
+	start := common.DownloaderFinishTopic.Subscribe(1, eventv3.Block)
+	done := common.DownloaderStartTopic.Subscribe(1, eventv3.Block)
+	startCh, doneCh := start.Chan(), done.Chan()
 
 	defer func() {
 		start.Unsubscribe()
@@ -145,6 +145,40 @@ func (m *Miner) SetCoinbase(addr types.Address) {
 	m.worker.setCoinbase(addr)
 }
 
+// Coinbase returns the address block rewards are paid to.
+func (m *Miner) Coinbase() types.Address {
+	return m.worker.etherbase()
+}
+
+// Hashrate returns the local block production rate as the number of blocks
+// sealed during the last hour. The PoA and PoS engines have no hash rate.
+func (m *Miner) Hashrate() uint64 {
+	return m.worker.sealed.count(time.Now())
+}
+
 func (m *Miner) PendingBlockAndReceipts() (block.IBlock, block.Receipts) {
 	return m.worker.pendingBlockAndReceipts()
 }
+
+// Mine seals a block on top of the current head as soon as possible, even
+// when there are no transactions to include.
+func (m *Miner) Mine() {
+	m.worker.mine()
+}
+
+// TimeOffset returns the number of seconds added to the wall clock when
+// stamping new blocks.
+func (m *Miner) TimeOffset() int64 {
+	return m.worker.clockOffset.Load()
+}
+
+// SetTimeOffset moves the clock used to stamp new blocks by offset seconds.
+// Block times never go backwards, a block is at least a second after its parent.
+func (m *Miner) SetTimeOffset(offset int64) {
+	m.worker.clockOffset.Store(offset)
+}
+
+// AddBundle queues bundle for inclusion at the top of its target block.
+func (m *Miner) AddBundle(bundle *transaction.Bundle) error {
+	return m.worker.bundles.add(bundle, m.worker.chain.CurrentBlock().Number64().Uint64())
+}
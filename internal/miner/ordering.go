@@ -0,0 +1,190 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// TxOrdering decides in which order the miner tries to include the executable
+// transactions of the pool in a block. Implementations must keep the nonce
+// order of the transactions of every account, otherwise the later ones fail.
+type TxOrdering interface {
+	// Order flattens pending, the nonce sorted transactions of every account,
+	// into the list of transactions to try for the block of header.
+	Order(pending map[types.Address][]*transaction.Transaction, header *block.Header) []*transaction.Transaction
+}
+
+// Built-in transaction orderings, selected by --miner.ordering.
+const (
+	OrderingPrice = "price" // highest effective tip first
+	OrderingFIFO  = "fifo"  // first seen first
+)
+
+var (
+	errOrderingName       = errors.New("ordering name must not be empty")
+	errOrderingFunc       = errors.New("ordering needs a constructor")
+	errOrderingRegistered = errors.New("ordering already registered")
+	errOrderingUnknown    = errors.New("unknown transaction ordering")
+)
+
+var (
+	orderingsMu sync.RWMutex
+	orderings   = map[string]func() TxOrdering{
+		OrderingPrice: func() TxOrdering { return priceOrdering{} },
+		OrderingFIFO:  func() TxOrdering { return fifoOrdering{} },
+	}
+)
+
+// RegisterTxOrdering makes the ordering created by newOrdering selectable by
+// name, e.g. one placing searcher bundles before the other transactions. It
+// must be called before the miner is created.
+func RegisterTxOrdering(name string, newOrdering func() TxOrdering) error {
+	if name == "" {
+		return errOrderingName
+	}
+	if newOrdering == nil {
+		return errOrderingFunc
+	}
+	orderingsMu.Lock()
+	defer orderingsMu.Unlock()
+	if _, ok := orderings[name]; ok {
+		return fmt.Errorf("%w: %s", errOrderingRegistered, name)
+	}
+	orderings[name] = newOrdering
+	return nil
+}
+
+// NewTxOrdering creates the ordering registered under name. An empty name
+// selects the price ordering.
+func NewTxOrdering(name string) (TxOrdering, error) {
+	if name == "" {
+		name = OrderingPrice
+	}
+	orderingsMu.RLock()
+	newOrdering, ok := orderings[name]
+	orderingsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q, have %v", errOrderingUnknown, name, TxOrderings())
+	}
+	return newOrdering(), nil
+}
+
+// TxOrderings returns the sorted names of the registered orderings.
+func TxOrderings() []string {
+	orderingsMu.RLock()
+	defer orderingsMu.RUnlock()
+	names := make([]string, 0, len(orderings))
+	for name := range orderings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// priceOrdering takes the transaction with the highest effective tip among the
+// next transactions of all accounts, preferring the earlier seen on a tie.
+type priceOrdering struct{}
+
+func (priceOrdering) Order(pending map[types.Address][]*transaction.Transaction, header *block.Header) []*transaction.Transaction {
+	var baseFee *uint256.Int
+	if header != nil {
+		baseFee = header.BaseFee
+	}
+	return mergeByNonce(pending, func(a, b *transaction.Transaction) bool {
+		if c := a.EffectiveGasTipValue(baseFee).Cmp(b.EffectiveGasTipValue(baseFee)); c != 0 {
+			return c > 0
+		}
+		return a.Time().Before(b.Time())
+	})
+}
+
+// fifoOrdering takes the earliest seen transaction among the next transactions
+// of all accounts, regardless of its price.
+type fifoOrdering struct{}
+
+func (fifoOrdering) Order(pending map[types.Address][]*transaction.Transaction, _ *block.Header) []*transaction.Transaction {
+	return mergeByNonce(pending, func(a, b *transaction.Transaction) bool {
+		return a.Time().Before(b.Time())
+	})
+}
+
+// accountTxs are the remaining transactions of an account.
+type accountTxs []*transaction.Transaction
+
+// txHeads is a heap of the next transactions of every account.
+type txHeads struct {
+	accounts []accountTxs
+	less     func(a, b *transaction.Transaction) bool
+}
+
+func (h *txHeads) Len() int           { return len(h.accounts) }
+func (h *txHeads) Less(i, j int) bool { return h.less(h.accounts[i][0], h.accounts[j][0]) }
+func (h *txHeads) Swap(i, j int)      { h.accounts[i], h.accounts[j] = h.accounts[j], h.accounts[i] }
+
+func (h *txHeads) Push(x interface{}) {
+	h.accounts = append(h.accounts, x.(accountTxs))
+}
+
+func (h *txHeads) Pop() interface{} {
+	old := h.accounts
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	h.accounts = old[0 : n-1]
+	return x
+}
+
+// mergeByNonce flattens the nonce sorted transactions of every account into a
+// single list, taking the first transaction according to less among the next
+// transactions of all accounts at every step.
+func mergeByNonce(pending map[types.Address][]*transaction.Transaction, less func(a, b *transaction.Transaction) bool) []*transaction.Transaction {
+	var (
+		heads = &txHeads{accounts: make([]accountTxs, 0, len(pending)), less: less}
+		total int
+	)
+	for _, txs := range pending {
+		if len(txs) > 0 {
+			heads.accounts = append(heads.accounts, txs)
+			total += len(txs)
+		}
+	}
+	heap.Init(heads)
+
+	sorted := make([]*transaction.Transaction, 0, total)
+	for heads.Len() > 0 {
+		txs := heads.accounts[0]
+		sorted = append(sorted, txs[0])
+		if len(txs) > 1 {
+			heads.accounts[0] = txs[1:]
+			heap.Fix(heads, 0)
+		} else {
+			heap.Pop(heads)
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,140 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// newOrderingTx creates a legacy transfer of from with the given nonce
+func newOrderingTx(from types.Address, nonce, gasPrice uint64) *transaction.Transaction {
+	to := types.Address{0xee}
+	return transaction.NewTx(&transaction.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: uint256.NewInt(gasPrice),
+		Gas:      21000,
+		To:       &to,
+		From:     &from,
+		Value:    uint256.NewInt(1),
+	})
+}
+
+type orderedTx struct {
+	from  types.Address
+	nonce uint64
+}
+
+func checkOrder(t *testing.T, name string, have []*transaction.Transaction, want []orderedTx) {
+	t.Helper()
+	if len(have) != len(want) {
+		t.Fatalf("%s: got %d transactions, want %d", name, len(have), len(want))
+	}
+	for i, w := range want {
+		if *have[i].From() != w.from || have[i].Nonce() != w.nonce {
+			t.Errorf("%s: position %d: got %v nonce %d, want %v nonce %d", name, i, have[i].From(), have[i].Nonce(), w.from, w.nonce)
+		}
+	}
+}
+
+var (
+	orderAlice = types.Address{0x01}
+	orderBob   = types.Address{0x02}
+	orderCarol = types.Address{0x03}
+)
+
+// newOrderingPending creates pending transactions seen in the order alice 0,
+// bob 3, carol 7, alice 1, bob 4
+func newOrderingPending() map[types.Address][]*transaction.Transaction {
+	var (
+		prices = map[orderedTx]uint64{{orderAlice, 0}: 1, {orderAlice, 1}: 9, {orderBob, 3}: 5, {orderBob, 4}: 2, {orderCarol, 7}: 3}
+		txs    []*transaction.Transaction
+	)
+	for _, tx := range []orderedTx{{orderAlice, 0}, {orderBob, 3}, {orderCarol, 7}, {orderAlice, 1}, {orderBob, 4}} {
+		txs = append(txs, newOrderingTx(tx.from, tx.nonce, prices[tx]))
+		time.Sleep(time.Millisecond)
+	}
+	return map[types.Address][]*transaction.Transaction{
+		orderAlice: {txs[0], txs[3]},
+		orderBob:   {txs[1], txs[4]},
+		orderCarol: {txs[2]},
+	}
+}
+
+func TestTxOrderings(t *testing.T) {
+	pending := newOrderingPending()
+	header := &block.Header{BaseFee: uint256.NewInt(0)}
+
+	price, err := NewTxOrdering("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, "price", price.Order(pending, header), []orderedTx{{orderBob, 3}, {orderCarol, 7}, {orderBob, 4}, {orderAlice, 0}, {orderAlice, 1}})
+
+	fifo, err := NewTxOrdering(OrderingFIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, "fifo", fifo.Order(pending, header), []orderedTx{{orderAlice, 0}, {orderBob, 3}, {orderCarol, 7}, {orderAlice, 1}, {orderBob, 4}})
+
+	// The input is left untouched
+	if len(pending[orderAlice]) != 2 || len(pending[orderBob]) != 2 || len(pending[orderCarol]) != 1 {
+		t.Errorf("pending transactions modified")
+	}
+
+	if _, err := NewTxOrdering("lottery"); !errors.Is(err, errOrderingUnknown) {
+		t.Errorf("unknown ordering: got %v", err)
+	}
+}
+
+// bundleOrdering includes the transactions of carol first
+type bundleOrdering struct{}
+
+func (bundleOrdering) Order(pending map[types.Address][]*transaction.Transaction, header *block.Header) []*transaction.Transaction {
+	rest := make(map[types.Address][]*transaction.Transaction, len(pending))
+	for from, txs := range pending {
+		if from != orderCarol {
+			rest[from] = txs
+		}
+	}
+	return append(pending[orderCarol], priceOrdering{}.Order(rest, header)...)
+}
+
+func TestRegisterTxOrdering(t *testing.T) {
+	if err := RegisterTxOrdering("test-bundle", func() TxOrdering { return bundleOrdering{} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTxOrdering("test-bundle", func() TxOrdering { return bundleOrdering{} }); !errors.Is(err, errOrderingRegistered) {
+		t.Errorf("duplicate registration: got %v", err)
+	}
+	if err := RegisterTxOrdering("", func() TxOrdering { return bundleOrdering{} }); err != errOrderingName {
+		t.Errorf("empty name: got %v", err)
+	}
+
+	ordering, err := NewTxOrdering("test-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, "bundle", ordering.Order(newOrderingPending(), nil), []orderedTx{{orderCarol, 7}, {orderBob, 3}, {orderBob, 4}, {orderAlice, 0}, {orderAlice, 1}})
+}
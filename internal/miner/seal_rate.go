@@ -0,0 +1,57 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"sync"
+	"time"
+)
+
+// sealRateWindow is the period over which the block production rate is
+// reported.
+const sealRateWindow = time.Hour
+
+// sealRate tracks the times of locally sealed blocks within sealRateWindow.
+type sealRate struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+// mark records a block sealed at now.
+func (r *sealRate) mark(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(now)
+	r.times = append(r.times, now)
+}
+
+// count returns the number of blocks sealed during the window ending at now.
+func (r *sealRate) count(now time.Time) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(now)
+	return uint64(len(r.times))
+}
+
+func (r *sealRate) prune(now time.Time) {
+	cutoff := now.Add(-sealRateWindow)
+	i := 0
+	for i < len(r.times) && !r.times[i].After(cutoff) {
+		i++
+	}
+	r.times = r.times[i:]
+}
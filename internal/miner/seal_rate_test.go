@@ -0,0 +1,39 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSealRate(t *testing.T) {
+	var r sealRate
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		r.mark(start.Add(time.Duration(i) * time.Minute))
+	}
+	if n := r.count(start.Add(30 * time.Minute)); n != 3 {
+		t.Fatalf("count = %d, want 3", n)
+	}
+	if n := r.count(start.Add(sealRateWindow + time.Minute)); n != 1 {
+		t.Fatalf("count after window = %d, want 1", n)
+	}
+	if n := r.count(start.Add(2 * sealRateWindow)); n != 0 {
+		t.Fatalf("count after expiry = %d, want 0", n)
+	}
+}
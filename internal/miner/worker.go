@@ -44,7 +44,7 @@ import (
 
 	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 	"github.com/n42blockchain/N42/params"
 
 	mapset "github.com/deckarep/golang-set"
@@ -52,7 +52,9 @@ import (
 )
 
 var (
-	blockSignGauge = prometheus.GetOrCreateCounter("block_sign_counter", true)
+	blockSignGauge     = prometheus.GetOrCreateCounter("block_sign_counter", true)
+	minerRunningGauge  = prometheus.GetOrCreateCounter("miner_running", true)
+	sealedBlockCounter = prometheus.GetOrCreateCounter("miner_sealed_blocks")
 )
 
 type task struct {
@@ -123,6 +125,9 @@ const (
 	staleThreshold         = 7
 	resubmitAdjustChanSize = 10
 
+	// txChanSize is the size of channel listening to NewTxsEvent.
+	txChanSize = 4096
+
 	// maxRecommitInterval is the maximum time interval to recreate the sealing block with
 	// any newly arrived transactions.
 	maxRecommitInterval = 12 * time.Second
@@ -130,6 +135,10 @@ const (
 	intervalAdjustRatio = 0.1
 
 	intervalAdjustBias = 200 * 1000.0 * 1000.0
+
+	// blockSizeReserve is the part of the block size limit kept free for the
+	// header, verifiers and rewards when packing transactions.
+	blockSizeReserve = 16 * 1024
 )
 
 var (
@@ -149,6 +158,8 @@ type worker struct {
 	engine    consensus.Engine
 	chain     common.IBlockChain
 	txsPool   common.ITxsPool
+	ordering  TxOrdering
+	bundles   *bundlePool
 
 	coinbase    types.Address
 	chainConfig *params.ChainConfig
@@ -160,6 +171,7 @@ type worker struct {
 	mu sync.RWMutex
 
 	startCh   chan struct{}
+	mineCh    chan struct{}
 	newWorkCh chan *newWorkReq
 	resultCh  chan block.IBlock
 	taskCh    chan *task
@@ -168,6 +180,9 @@ type worker struct {
 
 	running int32
 	newTxs  int32
+	sealed  sealRate
+
+	clockOffset atomic.Int64 // seconds added to the wall clock when stamping blocks
 
 	group  *errgroup.Group
 	ctx    context.Context
@@ -189,6 +204,7 @@ func newWorker(ctx context.Context, group *errgroup.Group, chainConfig *params.C
 		chainConfig:      chainConfig,
 		mu:               sync.RWMutex{},
 		startCh:          make(chan struct{}, 1),
+		mineCh:           make(chan struct{}, 1),
 		group:            group,
 		isLocalBlock:     isLocalBlock,
 		ctx:              c,
@@ -199,7 +215,15 @@ func newWorker(ctx context.Context, group *errgroup.Group, chainConfig *params.C
 		pendingTasks:     make(map[types.Hash]*task),
 		minerConf:        minerConf,
 		resubmitAdjustCh: make(chan *intervalAdjust, resubmitAdjustChanSize),
+		bundles:          newBundlePool(),
 	}
+	ordering, err := NewTxOrdering(minerConf.Ordering)
+	if err != nil {
+		log.Warn("Falling back to price transaction ordering", "err", err)
+		ordering, _ = NewTxOrdering(OrderingPrice)
+	}
+	worker.ordering = ordering
+
 	recommit := worker.minerConf.Recommit
 	if recommit < minPeriodInterval {
 		recommit = minPeriodInterval
@@ -234,12 +258,18 @@ func newWorker(ctx context.Context, group *errgroup.Group, chainConfig *params.C
 }
 
 func (w *worker) start() {
-	atomic.StoreInt32(&w.running, 1)
+	if atomic.CompareAndSwapInt32(&w.running, 0, 1) {
+		minerRunningGauge.Set(1)
+		common.MinerStartedTopic.Send(common.MinerStartedEvent{Coinbase: w.etherbase()})
+	}
 	w.startCh <- struct{}{}
 }
 
 func (w *worker) stop() {
-	atomic.StoreInt32(&w.running, 0)
+	if atomic.CompareAndSwapInt32(&w.running, 1, 0) {
+		minerRunningGauge.Set(0)
+		common.MinerStoppedTopic.Send(common.MinerStoppedEvent{})
+	}
 }
 
 func (w *worker) close() {
@@ -255,6 +285,25 @@ func (w *worker) setCoinbase(addr types.Address) {
 	w.coinbase = addr
 }
 
+// now returns the timestamp for a block built at this moment.
+func (w *worker) now() int64 {
+	return time.Now().Unix() + w.clockOffset.Load()
+}
+
+// mine requests a block on top of the current head, even an empty one.
+func (w *worker) mine() {
+	select {
+	case w.mineCh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *worker) etherbase() types.Address {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.coinbase
+}
+
 func (w *worker) runLoop() error {
 	defer w.cancel()
 	defer w.stop()
@@ -337,9 +386,11 @@ func (w *worker) resultLoop() error {
 				continue
 			}
 			blockSignGauge.Set(uint64(len(blk.Body().Verifier())))
+			sealedBlockCounter.Inc()
+			w.sealed.mark(time.Now())
 
 			if len(logs) > 0 {
-				event.GlobalEvent.Send(common.NewLogsEvent{Logs: logs})
+				common.NewLogsTopic.Send(common.NewLogsEvent{Logs: logs})
 			}
 
 			log.Info("🔨 Successfully sealed new block",
@@ -358,7 +409,6 @@ func (w *worker) resultLoop() error {
 				log.Error("Failed Broadcast block to p2p network", "err", err)
 				continue
 			}
-			event.GlobalEvent.Send(common.ChainHighestBlock{Block: *blk.(*block.Block), Inserted: true})
 		}
 	}
 }
@@ -453,8 +503,11 @@ func (w *worker) commitWork(interrupt *atomic.Int32, noempty bool, timestamp int
 		return h
 	}
 
-	err = w.fillTransactions(interrupt, current, ibs, getHeader)
+	err = w.fillTransactions(interrupt, current, ibs, stateReader, getHeader)
 	switch {
+	case errors.Is(err, errBlockInterruptedByNewHead):
+		// The block is stale, a build on the new head follows.
+		return nil
 	case err == nil:
 		w.resubmitAdjustCh <- &intervalAdjust{inc: false}
 	case errors.Is(err, errBlockInterruptedByRecommit):
@@ -469,6 +522,11 @@ func (w *worker) commitWork(interrupt *atomic.Int32, noempty bool, timestamp int
 		}
 	}
 
+	// A rebuild without transactions would only replace the block being sealed.
+	if noempty && len(current.txs) == 0 {
+		return nil
+	}
+
 	//var rewards []*block.Reward
 	//if w.chainConfig.IsBeijing(current.header.Number.Uint64()) {
 	//	rewards, err = w.engine.Rewards(tx, block.CopyHeader(current.header), ibs, false)
@@ -516,12 +574,14 @@ func (w *worker) workLoop(recommit time.Duration) error {
 		timestamp   int64      // timestamp for each round of sealing.
 	)
 
-	newBlockCh := make(chan common.ChainHighestBlock)
-	defer close(newBlockCh)
-
-	newBlockSub := event.GlobalEvent.Subscribe(newBlockCh)
+	// Sealing restarts on the latest head only, and pending transactions are
+	// picked up by the next round anyway
+	newBlockSub := common.ChainHeadTopic.Subscribe(1, eventv3.DropOldest)
 	defer newBlockSub.Unsubscribe()
 
+	txsSub := common.NewTxsTopic.Subscribe(txChanSize, eventv3.DropNewest)
+	defer txsSub.Unsubscribe()
+
 	timer := time.NewTimer(0)
 	defer timer.Stop()
 	<-timer.C // discard the initial tick
@@ -531,13 +591,18 @@ func (w *worker) workLoop(recommit time.Duration) error {
 			interrupt.Store(s)
 		}
 		interrupt = new(atomic.Int32)
+		// Seal what has been packed so far if building takes longer than the interval.
+		deadline := interrupt
+		time.AfterFunc(recommit, func() {
+			deadline.CompareAndSwap(commitInterruptNone, commitInterruptTimeout)
+		})
 		select {
 		case w.newWorkCh <- &newWorkReq{interrupt: interrupt, noempty: noempty, timestamp: timestamp}:
 		case <-w.ctx.Done():
 			return
 		}
 		timer.Reset(recommit)
-		//atomic.StoreInt32(&w.newTxs, 0)
+		atomic.StoreInt32(&w.newTxs, 0)
 	}
 
 	clearPending := func(number *uint256.Int) {
@@ -556,23 +621,39 @@ func (w *worker) workLoop(recommit time.Duration) error {
 			return w.ctx.Err()
 		case <-w.startCh:
 			clearPending(w.chain.CurrentBlock().Number64())
-			timestamp = time.Now().Unix()
-			commit(false, commitInterruptNewHead)
+			timestamp = w.now()
+			commit(w.minerConf.Instant, commitInterruptNewHead)
 
-		case blockEvent := <-newBlockCh:
+		case blockEvent := <-newBlockSub.Chan():
 			clearPending(blockEvent.Block.Number64())
-			timestamp = time.Now().Unix()
+			timestamp = w.now()
+			commit(w.minerConf.Instant, commitInterruptNewHead)
+		case <-w.mineCh:
+			timestamp = w.now()
 			commit(false, commitInterruptNewHead)
 		case err := <-newBlockSub.Err():
 			return err
 
+		case ev := <-txsSub.Chan():
+			atomic.AddInt32(&w.newTxs, int32(len(ev.Txs)))
+			// Instant sealing builds a block for every batch of arrivals.
+			if w.minerConf.Instant && w.isRunning() {
+				timestamp = w.now()
+				commit(true, commitInterruptResubmit)
+			}
+		case err := <-txsSub.Err():
+			return err
+
 		case <-timer.C:
-			// If sealing is running resubmit a new work cycle periodically to pull in
-			// higher priced transactions. Disable this overhead for pending blocks.
-			//if w.isRunning() && (w.chainConfig.Apos == nil && w.chainConfig.Clique == nil) {
-			//	continue
-			//	commit(false, commitInterruptResubmit)
-			//}
+			// If sealing is running rebuild the block periodically to pull in the
+			// transactions which arrived since the last build. Blocks checked by the
+			// verifiers are handed to them when built, a rebuild would make them
+			// sign two state roots for the same height.
+			if w.isRunning() && atomic.LoadInt32(&w.newTxs) > 0 && !w.chainConfig.IsBeijing(w.chain.CurrentBlock().Number64().Uint64()+1) {
+				commit(true, commitInterruptResubmit)
+				continue
+			}
+			timer.Reset(recommit)
 		case adjust := <-w.resubmitAdjustCh:
 			// Adjust resubmit interval by feedback.
 			if adjust.inc {
@@ -589,16 +670,11 @@ func (w *worker) workLoop(recommit time.Duration) error {
 	}
 }
 
-func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment, ibs *state.IntraBlockState, getHeader func(hash types.Hash, number uint64) *block.Header) error {
-	// todo fillTx
+func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment, ibs *state.IntraBlockState, reader state.StateReader, getHeader func(hash types.Hash, number uint64) *block.Header) error {
 	env.txs = []*transaction.Transaction{}
-	txs, err := w.txsPool.GetTransaction()
-	if err != nil {
-		log.Warn("get transaction error", "err", err)
-		return err
-	}
-
 	header := env.header
+	txs := w.ordering.Order(w.txsPool.Pending(true), header)
+
 	noop := state.NewNoopWriter()
 	var miningCommitTx = func(txn *transaction.Transaction, coinbase types.Address, vmConfig *vm2.Config, chainConfig *params.ChainConfig, ibs *state.IntraBlockState, current *environment) ([]*block.Log, error) {
 		ibs.Prepare(txn.Hash(), types.Hash{}, env.tcount)
@@ -612,11 +688,28 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment, ibs
 			return nil, err
 		}
 
+		header.BlobGasUsed += txn.BlobGas()
 		current.txs = append(current.txs, txn)
 		current.receipts = append(current.receipts, receipt)
 		return receipt.Logs, nil
 	}
 
+	var sizeLeft uint64
+	if limit := w.chainConfig.BlockSizeLimit(); limit > blockSizeReserve {
+		sizeLeft = limit - blockSizeReserve
+	}
+
+	// Bundles go first, pool transactions they include then fail as known nonces.
+	if bundles := w.bundles.eligible(header.Number64().Uint64(), header.Time); len(bundles) > 0 {
+		sizeLeft = w.commitBundles(bundles, env, reader, internal.GetHashFn(header, getHeader), sizeLeft, func(tx *transaction.Transaction) error {
+			_, err := miningCommitTx(tx, env.coinbase, &vm2.Config{}, w.chainConfig, ibs, env)
+			if err == nil {
+				env.tcount++
+			}
+			return err
+		})
+	}
+
 	log.Tracef("fillTransactions txs len:%d", len(txs))
 	for _, tx := range txs {
 		// Check interruption signal and abort building if it's fired.
@@ -629,6 +722,18 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment, ibs
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
 			break
 		}
+		// Blocks carry blob transactions without their sidecars.
+		tx = tx.WithoutBlobTxSidecar()
+		// Skip blob transactions that would exceed the blob gas of the block.
+		if blobGas := tx.BlobGas(); blobGas > 0 && header.BlobGasUsed+blobGas > transaction.MaxBlobGasPerBlock {
+			log.Trace("Skipping blob transaction exceeding blob gas limit", "hash", tx.Hash(), "blobGas", blobGas, "used", header.BlobGasUsed)
+			continue
+		}
+		// Skip transactions that would push the block over its size limit.
+		if tx.Size() > sizeLeft {
+			log.Trace("Skipping transaction exceeding block size", "hash", tx.Hash(), "size", tx.Size(), "left", sizeLeft)
+			continue
+		}
 		// Start executing the transaction
 		_, err := miningCommitTx(tx, env.coinbase, &vm2.Config{}, w.chainConfig, ibs, env)
 
@@ -641,6 +746,7 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment, ibs
 			continue
 		case errors.Is(err, nil):
 			env.tcount++
+			sizeLeft -= tx.Size()
 			continue
 		default:
 			log.Error("miningCommitTx failed ", "error", err)
@@ -674,8 +780,9 @@ func (w *worker) prepareWork(param *generateParams) (*environment, error) {
 		ParentHash: parent.Hash(),
 		Coinbase:   param.coinbase,
 		Number:     uint256.NewInt(0).Add(parent.Number64(), uint256.NewInt(1)),
-		GasLimit:   CalcGasLimit(parent.GasLimit, w.minerConf.GasCeil),
+		GasLimit:   CalcGasLimit(parent.GasLimit, min(w.minerConf.GasCeil, w.chainConfig.BlockGasLimit())),
 		Time:       uint64(timestamp),
+		Extra:      []byte(w.minerConf.ExtraData),
 		Difficulty: uint256.NewInt(0),
 		// just for now
 		BaseFee: uint256.NewInt(0),
@@ -690,6 +797,10 @@ func (w *worker) prepareWork(param *generateParams) (*environment, error) {
 		}
 	}
 
+	if w.chainConfig.IsCancun(header.Number.Uint64()) {
+		header.ExcessBlobGas = misc.CalcExcessBlobGas(w.chainConfig, parent)
+	}
+
 	if err := w.engine.Prepare(w.chain, header); err != nil {
 		return nil, err
 	}
@@ -751,7 +862,8 @@ func (w *worker) commit(env *environment, writer state.WriterWithChangeSets, ibs
 			}
 			sort.Sort(hs)
 
-			event.GlobalEvent.Send(common.MinedEntireEvent{Entire: state.EntireCode{Codes: hs, Headers: needHeaders, Entire: entri, Rewards: rewards, CoinBase: env.coinbase}})
+			api.MarkVerifyBroadcast(env.header.Number.Uint64())
+			common.MinedEntireTopic.Send(common.MinedEntireEvent{Entire: state.EntireCode{Codes: hs, Headers: needHeaders, Entire: entri, Rewards: rewards, CoinBase: env.coinbase}})
 		}
 
 		//
@@ -0,0 +1,251 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	leakybucket "github.com/n42blockchain/N42/internal/p2p/leaky-bucket"
+)
+
+const (
+	// apiKeyHeader carries the API key of a request. Clients that cannot set
+	// headers may append the key to the URL path instead, e.g. /<key>.
+	apiKeyHeader = "X-API-Key"
+	// apiKeyUsagePath serves the per-key usage counters as JSON.
+	apiKeyUsagePath = "/apikeys/usage"
+)
+
+// apiKey holds the usage counters of a single key.
+type apiKey struct {
+	name     string
+	requests prometheus.Counter
+	limited  prometheus.Counter
+	lastSeen atomic.Int64
+}
+
+// apiKeyStore authenticates requests against a fixed set of keys, rate limits
+// them per key and counts their usage.
+type apiKeyStore struct {
+	keys    map[string]*apiKey
+	admin   string                 // key reporting the usage of all keys, empty if none
+	limiter *leakybucket.Collector // nil when requests are not rate limited
+}
+
+// APIKeyUsage is the usage of a single API key reported by the usage endpoint.
+type APIKeyUsage struct {
+	Name        string `json:"name"`
+	Requests    uint64 `json:"requests"`
+	RateLimited uint64 `json:"rate_limited"`
+	LastSeen    int64  `json:"last_seen,omitempty"`
+}
+
+// loadAPIKeys reads the key file. Every non-empty line holds a key optionally
+// followed by a name used in metrics and usage reports; lines starting with #
+// are ignored. A positive rate limits each key to rate requests per second
+// with bursts of up to burst requests.
+func loadAPIKeys(path string, rate float64, burst int64) (*apiKeyStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &apiKeyStore{keys: make(map[string]*apiKey)}
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("%s:%d: expected <key> [name]", path, line)
+		}
+		key, name := fields[0], maskAPIKey(fields[0])
+		if len(fields) == 2 {
+			name = fields[1]
+		}
+		if _, ok := s.keys[key]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate key", path, line)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("%s:%d: duplicate name %q", path, line, name)
+		}
+		names[name] = true
+		s.keys[key] = &apiKey{
+			name:     name,
+			requests: prometheus.GetOrCreateCounter(fmt.Sprintf(`rpc_apikey_requests_total{key=%q}`, name)),
+			limited:  prometheus.GetOrCreateCounter(fmt.Sprintf(`rpc_apikey_rate_limited_total{key=%q}`, name)),
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.keys) == 0 {
+		return nil, fmt.Errorf("no API keys in %s", path)
+	}
+	if rate > 0 {
+		if burst <= 0 {
+			burst = int64(math.Max(1, math.Ceil(rate)))
+		}
+		s.limiter = leakybucket.NewCollector(rate, burst, time.Second, false)
+	}
+	return s, nil
+}
+
+// maskAPIKey shortens a key so it can be reported without revealing it.
+func maskAPIKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + "…" + key[len(key)-4:]
+}
+
+// extractPathToken moves a key given as the last path segment after prefix
+// into the API key header, so the request matches the RPC path again.
+func (s *apiKeyStore) extractPathToken(r *http.Request, prefix string) {
+	if r.Header.Get(apiKeyHeader) != "" {
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/"))
+	token := strings.Trim(rest, "/")
+	if token == "" || strings.Contains(token, "/") {
+		return
+	}
+	if _, ok := s.keys[token]; !ok {
+		return
+	}
+	r.Header.Set(apiKeyHeader, token)
+	u := *r.URL
+	u.Path = strings.TrimSuffix(u.Path, rest)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawPath = ""
+	r.URL = &u
+}
+
+// usage returns the counters of every key, ordered by name.
+func (s *apiKeyStore) usage() []APIKeyUsage {
+	usage := make([]APIKeyUsage, 0, len(s.keys))
+	for _, k := range s.keys {
+		usage = append(usage, k.usage())
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Name < usage[j].Name })
+	return usage
+}
+
+func (k *apiKey) usage() APIKeyUsage {
+	return APIKeyUsage{
+		Name:        k.name,
+		Requests:    k.requests.Get(),
+		RateLimited: k.limited.Get(),
+		LastSeen:    k.lastSeen.Load(),
+	}
+}
+
+type apiKeyHandler struct {
+	store *apiKeyStore
+	next  http.Handler
+}
+
+// newAPIKeyHandler creates a http.Handler which rejects requests without a
+// known API key and accounts the others to their key.
+func newAPIKeyHandler(store *apiKeyStore, next http.Handler) http.Handler {
+	return &apiKeyHandler{store: store, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *apiKeyHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		http.Error(out, "missing API key", http.StatusUnauthorized)
+		return
+	}
+	entry, ok := handler.store.keys[key]
+	if !ok {
+		http.Error(out, "invalid API key", http.StatusForbidden)
+		return
+	}
+	if !handler.store.allow(out, key, entry) {
+		return
+	}
+	entry.requests.Inc()
+	entry.lastSeen.Store(time.Now().Unix())
+	handler.next.ServeHTTP(out, r)
+}
+
+// allow takes a request of key from its rate limit. If the key is over the
+// limit, the request is answered and counted to entry, if not nil.
+func (s *apiKeyStore) allow(out http.ResponseWriter, key string, entry *apiKey) bool {
+	if s.limiter == nil || s.limiter.Add(key, 1) != 0 {
+		return true
+	}
+	if entry != nil {
+		entry.limited.Inc()
+	}
+	out.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(1/s.limiter.Rate()))))
+	http.Error(out, "rate limit exceeded", http.StatusTooManyRequests)
+	return false
+}
+
+// usageHandler reports the usage of the key the request is made with, or of
+// all keys when it is made with the admin key. Its requests are rate limited
+// like the RPC requests of the key.
+func (s *apiKeyStore) usageHandler() http.Handler {
+	return http.HandlerFunc(func(out http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(out, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			http.Error(out, "missing API key", http.StatusUnauthorized)
+			return
+		}
+		var result interface{}
+		if s.admin != "" && subtle.ConstantTimeCompare([]byte(key), []byte(s.admin)) == 1 {
+			if !s.allow(out, key, nil) {
+				return
+			}
+			result = s.usage()
+		} else if entry, ok := s.keys[key]; ok {
+			if !s.allow(out, key, entry) {
+				return
+			}
+			result = entry.usage()
+		} else {
+			http.Error(out, "invalid API key", http.StatusForbidden)
+			return
+		}
+		out.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(out).Encode(result)
+	})
+}
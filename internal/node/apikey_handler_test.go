@@ -0,0 +1,202 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	store, err := loadAPIKeys(writeKeyFile(t, "# keys\nsecret-key-alice alice\nsecret-key-bob\n"), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newAPIKeyHandler(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	// Counters are process wide metrics, compare against the starting values.
+	before := store.usage()
+	serve := func(path, key string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if key != "" {
+			req.Header.Set(apiKeyHeader, key)
+		}
+		store.extractPathToken(req, "")
+		if !checkPath(req, "") {
+			return http.StatusNotFound
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("/", ""); code != http.StatusUnauthorized {
+		t.Errorf("missing key: status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := serve("/", "nope"); code != http.StatusForbidden {
+		t.Errorf("unknown key: status %d, want %d", code, http.StatusForbidden)
+	}
+	if code := serve("/", "secret-key-alice"); code != http.StatusOK {
+		t.Errorf("header key: status %d, want %d", code, http.StatusOK)
+	}
+	if code := serve("/secret-key-alice", ""); code != http.StatusOK {
+		t.Errorf("path key: status %d, want %d", code, http.StatusOK)
+	}
+	if code := serve("/secret-key-alice", ""); code != http.StatusTooManyRequests {
+		t.Errorf("burst exceeded: status %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if code := serve("/secret-key-bob", ""); code != http.StatusOK {
+		t.Errorf("other key: status %d, want %d", code, http.StatusOK)
+	}
+
+	store.admin = "secret-key-admin"
+	req := httptest.NewRequest(http.MethodGet, apiKeyUsagePath, nil)
+	req.Header.Set(apiKeyHeader, store.admin)
+	rec := httptest.NewRecorder()
+	store.usageHandler().ServeHTTP(rec, req)
+	var usage []APIKeyUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("decode usage: %v", err)
+	}
+	if len(usage) != 2 || usage[0].Name != "alice" || usage[1].Name != "secr…-bob" {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if usage[0].Requests-before[0].Requests != 2 || usage[0].RateLimited-before[0].RateLimited != 1 {
+		t.Errorf("alice usage %+v, started at %+v", usage[0], before[0])
+	}
+	if usage[1].Requests-before[1].Requests != 1 {
+		t.Errorf("unnamed key usage %+v, started at %+v", usage[1], before[1])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, apiKeyUsagePath, nil)
+	req.Header.Set(apiKeyHeader, "secret-key-bob")
+	rec = httptest.NewRecorder()
+	store.usageHandler().ServeHTTP(rec, req)
+	var own APIKeyUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &own); err != nil {
+		t.Fatalf("decode own usage: %v", err)
+	}
+	if own.Name != "secr…-bob" {
+		t.Errorf("usage with a key reported %+v", own)
+	}
+
+	// The usage endpoint counts against the rate limit of the key
+	req = httptest.NewRequest(http.MethodGet, apiKeyUsagePath, nil)
+	req.Header.Set(apiKeyHeader, "secret-key-alice")
+	rec = httptest.NewRecorder()
+	store.usageHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("usage over the limit: status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// The local host is no exception, all keys need the admin key.
+	req = httptest.NewRequest(http.MethodGet, apiKeyUsagePath, nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	rec = httptest.NewRecorder()
+	store.usageHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("usage without key: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIKeyCorsPreflight(t *testing.T) {
+	store, err := loadAPIKeys(writeKeyFile(t, "secret-key-alice alice\n"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newHTTPServer()
+	if err := h.enableRPC(nil, httpConfig{CorsAllowedOrigins: []string{"*"}, Vhosts: []string{"*"}, apiKeys: store}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Browsers send the preflight without the key.
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", apiKeyHeader)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code >= 300 || rec.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Errorf("preflight: status %d, headers %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("request without key: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIKeyWebsocket(t *testing.T) {
+	store, err := loadAPIKeys(writeKeyFile(t, "secret-key-alice alice\n"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newHTTPServer()
+	if err := h.enableWS(nil, wsConfig{Origins: []string{"*"}, apiKeys: store}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("upgrade without key: %v, response %v", err, resp)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/secret-key-alice", nil)
+	if err != nil {
+		t.Fatalf("upgrade with path key: %v", err)
+	}
+	conn.Close()
+	conn, _, err = websocket.DefaultDialer.Dial(url, http.Header{apiKeyHeader: {"secret-key-alice"}})
+	if err != nil {
+		t.Fatalf("upgrade with header key: %v", err)
+	}
+	conn.Close()
+}
+
+func TestLoadAPIKeysErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":          "# nothing\n",
+		"duplicate key":  "k1 a\nk1 b\n",
+		"duplicate name": "k1 a\nk2 a\n",
+		"extra fields":   "k1 a b\n",
+	} {
+		if _, err := loadAPIKeys(writeKeyFile(t, content), 0, 0); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
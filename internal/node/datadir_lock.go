@@ -0,0 +1,100 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gofrs/flock"
+
+	"github.com/n42blockchain/N42/log"
+)
+
+const (
+	datadirLockName = "LOCK"     // file locked by the owning process
+	datadirPIDName  = "LOCK.pid" // PID of the owning process
+)
+
+// DatadirLock guards a datadir against concurrent writable use by several
+// processes. MDBX itself only reports such sharing as corruption.
+type DatadirLock struct {
+	lock    *flock.Flock
+	pidPath string
+}
+
+// LockDatadir takes the lock of datadir, creating the directory if needed.
+// When another live process owns it the returned error wraps ErrDatadirUsed
+// and names that process.
+func LockDatadir(datadir string) (*DatadirLock, error) {
+	if err := os.MkdirAll(datadir, 0700); err != nil {
+		return nil, err
+	}
+	lock := flock.New(filepath.Join(datadir, datadirLockName))
+	pidPath := filepath.Join(datadir, datadirPIDName)
+
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		if pid := readLockPID(pidPath); pid > 0 {
+			return nil, fmt.Errorf("%w: %s is held by pid %d", ErrDatadirUsed, datadir, pid)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrDatadirUsed, datadir)
+	}
+
+	// The lock is released by the OS when its owner dies, so a PID file left
+	// behind only tells that the previous owner did not shut down cleanly.
+	// A live process under that PID has merely reused it.
+	if pid := readLockPID(pidPath); pid > 0 && pid != os.Getpid() {
+		log.Warn("Removing stale datadir lock, previous owner did not shut down cleanly",
+			"datadir", datadir, "pid", pid, "pidReused", processAlive(pid))
+	}
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return &DatadirLock{lock: lock, pidPath: pidPath}, nil
+}
+
+// Unlock removes the PID file and releases the lock.
+func (l *DatadirLock) Unlock() {
+	if l == nil || !l.lock.Locked() {
+		return
+	}
+	if err := os.Remove(l.pidPath); err != nil && !os.IsNotExist(err) {
+		log.Warn("Failed to remove datadir PID file", "path", l.pidPath, "err", err)
+	}
+	l.lock.Unlock()
+}
+
+// readLockPID returns the PID recorded in the PID file, or 0.
+func readLockPID(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
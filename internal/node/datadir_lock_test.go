@@ -0,0 +1,60 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLockDatadir(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := LockDatadir(dir)
+	if err != nil {
+		t.Fatalf("first lock failed: %v", err)
+	}
+	_, err = LockDatadir(dir)
+	if !errors.Is(err, ErrDatadirUsed) {
+		t.Fatalf("second lock: have %v, want %v", err, ErrDatadirUsed)
+	}
+	if !strings.Contains(err.Error(), "pid "+strconv.Itoa(os.Getpid())) {
+		t.Errorf("error %q does not name the owning process", err)
+	}
+
+	lock.Unlock()
+	if _, err := os.Stat(filepath.Join(dir, datadirPIDName)); !os.IsNotExist(err) {
+		t.Errorf("PID file left behind after unlock: %v", err)
+	}
+
+	// A PID file without a held lock is stale and gets replaced.
+	if err := os.WriteFile(filepath.Join(dir, datadirPIDName), []byte("999999999"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = LockDatadir(dir)
+	if err != nil {
+		t.Fatalf("lock over stale PID file failed: %v", err)
+	}
+	defer lock.Unlock()
+	if pid := readLockPID(filepath.Join(dir, datadirPIDName)); pid != os.Getpid() {
+		t.Errorf("PID file holds %d, want %d", pid, os.Getpid())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/accounts/keystore"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+)
+
+// devKeys are the private keys of the prefunded --dev accounts. They are the
+// well-known keys of the Hardhat and Anvil test mnemonic, so wallets and
+// scripts written against those work unchanged. Never use them elsewhere.
+var devKeys = []string{
+	"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+	"7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6",
+	"47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a",
+	"8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba",
+	"92db14e403b83dfe3df233f83dfa3a0d7096f21ca9b0d6d6b8d88b2b4ec1564e",
+	"4bbbf85ce3377467afe5d46f804f221813b2bb87f24d81f60f1fcdbf7cbf4356",
+	"dbda1821b80551c9d65939329250298aa3472ba22feea921c0cf5d620ea67b97",
+	"2a871d0798f97d79848a013d4936a73bf4cc922c825d33c1cf7073dff6d409c6",
+}
+
+// devAccounts returns the addresses of the --dev accounts.
+func devAccounts() []types.Address {
+	addrs := make([]types.Address, len(devKeys))
+	for i, hex := range devKeys {
+		key, err := crypto.HexToECDSA(hex)
+		if err != nil {
+			panic(err)
+		}
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return addrs
+}
+
+// setupDevAccounts imports the --dev accounts into the keystore and unlocks
+// them, so eth_sendTransaction can sign for them. The first account becomes
+// the etherbase unless one is configured.
+func (n *Node) setupDevAccounts() error {
+	backends := n.accman.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		return errors.New("dev mode needs a keystore")
+	}
+	ks := backends[0].(*keystore.KeyStore)
+	for i, hex := range devKeys {
+		key, err := crypto.HexToECDSA(hex)
+		if err != nil {
+			return err
+		}
+		account, err := ks.ImportECDSA(key, "")
+		if err != nil && !errors.Is(err, keystore.ErrAccountAlreadyExists) {
+			return fmt.Errorf("failed to import dev account %d: %w", i, err)
+		}
+		if err := ks.Unlock(accounts.Account{Address: account.Address}, ""); err != nil {
+			return fmt.Errorf("failed to unlock dev account %s: %w", account.Address, err)
+		}
+		log.Info("Dev account", "index", i, "address", account.Address, "key", "0x"+hex)
+	}
+	if n.etherbase == (types.Address{}) {
+		n.etherbase = devAccounts()[0]
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// jwtAuth authenticates gRPC calls with the same tokens as the
+// authenticated RPC endpoint.
+type jwtAuth struct {
+	secret []byte
+}
+
+func (a jwtAuth) check(ctx context.Context) error {
+	var strToken string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if auth := md.Get("authorization"); len(auth) > 0 && strings.HasPrefix(auth[0], "Bearer ") {
+			strToken = strings.TrimPrefix(auth[0], "Bearer ")
+		}
+	}
+	err := checkJWT(strToken, func(*jwt.Token) (interface{}, error) { return a.secret, nil })
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// serverOptions returns the interceptors refusing unauthenticated calls.
+func (a jwtAuth) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := a.check(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := a.check(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials, signing a
+// fresh token for every call.
+func (a jwtAuth) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	})
+	s, err := token.SignedString(a.secret)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + s}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. The
+// tokens are short-lived and do not reveal the secret.
+func (a jwtAuth) RequireTransportSecurity() bool { return false }
+
+var _ credentials.PerRPCCredentials = jwtAuth{}
@@ -0,0 +1,48 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWTAuthGRPC(t *testing.T) {
+	server := jwtAuth{secret: []byte("0123456789abcdef0123456789abcdef")}
+	incoming := func(client jwtAuth) context.Context {
+		md, err := client.GetRequestMetadata(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return metadata.NewIncomingContext(context.Background(), metadata.New(md))
+	}
+
+	if err := server.check(incoming(server)); err != nil {
+		t.Fatalf("same secret: have %v", err)
+	}
+	other := jwtAuth{secret: []byte("fedcba9876543210fedcba9876543210")}
+	if err := server.check(incoming(other)); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("other secret: have %v, want %v", err, codes.Unauthenticated)
+	}
+	if err := server.check(context.Background()); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("no token: have %v, want %v", err, codes.Unauthenticated)
+	}
+}
@@ -17,6 +17,7 @@
 package node
 
 import (
+	"errors"
 	"github.com/golang-jwt/jwt/v4"
 	"net/http"
 	"strings"
@@ -42,38 +43,43 @@ func newJWTHandler(secret []byte, next http.Handler) http.Handler {
 
 // ServeHTTP implements http.Handler
 func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
-	var (
-		strToken string
-		claims   jwt.RegisteredClaims
-	)
+	var strToken string
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
 		strToken = strings.TrimPrefix(auth, "Bearer ")
 	}
-	if len(strToken) == 0 {
-		http.Error(out, "missing token", http.StatusForbidden)
+	if err := checkJWT(strToken, handler.keyFunc); err != nil {
+		http.Error(out, err.Error(), http.StatusForbidden)
 		return
 	}
+	handler.next.ServeHTTP(out, r)
+}
+
+// checkJWT validates a bearer token signed with the key returned by keyFunc.
+func checkJWT(strToken string, keyFunc jwt.Keyfunc) error {
+	if len(strToken) == 0 {
+		return errors.New("missing token")
+	}
+	var claims jwt.RegisteredClaims
 	// We explicitly set only HS256 allowed, and also disables the
 	// claim-check: the RegisteredClaims internally requires 'iat' to
 	// be no later than 'now', but we allow for a bit of drift.
-	token, err := jwt.ParseWithClaims(strToken, &claims, handler.keyFunc,
+	token, err := jwt.ParseWithClaims(strToken, &claims, keyFunc,
 		jwt.WithValidMethods([]string{"HS256"}),
 		jwt.WithoutClaimsValidation())
 
 	switch {
 	case err != nil:
-		http.Error(out, err.Error(), http.StatusForbidden)
+		return err
 	case !token.Valid:
-		http.Error(out, "invalid token", http.StatusForbidden)
+		return errors.New("invalid token")
 	case !claims.VerifyExpiresAt(time.Now(), false): // optional
-		http.Error(out, "token is expired", http.StatusForbidden)
+		return errors.New("token is expired")
 	case claims.IssuedAt == nil:
-		http.Error(out, "missing issued-at", http.StatusForbidden)
+		return errors.New("missing issued-at")
 	case time.Since(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "stale token", http.StatusForbidden)
+		return errors.New("stale token")
 	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "future token", http.StatusForbidden)
-	default:
-		handler.next.ServeHTTP(out, r)
+		return errors.New("future token")
 	}
+	return nil
 }
@@ -0,0 +1,175 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/ethdb/dbstat"
+)
+
+// dbStatInterval bounds how often a scrape may walk the table statistics.
+const dbStatInterval = 15 * time.Second
+
+// dbStatCache serves database gauges from a summary refreshed at most once
+// per dbStatInterval, so that scrapes do not each open a read transaction.
+type dbStatCache struct {
+	n *Node
+
+	mu      sync.Mutex
+	stat    *dbstat.Stat
+	updated time.Time
+}
+
+func (c *dbStatCache) get() *dbstat.Stat {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stat != nil && time.Since(c.updated) < dbStatInterval {
+		return c.stat
+	}
+	stat, err := dbstat.Summary(context.Background(), c.n.db)
+	if err != nil {
+		log.Debug("Failed to collect database statistics", "err", err)
+		if c.stat == nil {
+			c.stat = &dbstat.Stat{}
+		}
+		return c.stat
+	}
+	c.stat, c.updated = stat, time.Now()
+	return c.stat
+}
+
+func (c *dbStatCache) gauge(f func(*dbstat.Stat) uint64) func() float64 {
+	return func() float64 { return float64(f(c.get())) }
+}
+
+// registerNodeMetrics exports chain, p2p and database gauges which are
+// evaluated on every scrape of the metrics endpoint.
+func (n *Node) registerNodeMetrics() {
+	prometheus.GetOrCreateGaugeFunc("chain_head_td", func() float64 {
+		current := n.blockChain.CurrentBlock()
+		td := n.blockChain.GetTd(current.Hash(), current.Number64())
+		if td == nil {
+			return 0
+		}
+		return td.Float64()
+	})
+	prometheus.GetOrCreateGaugeFunc("chain_head_timestamp", func() float64 {
+		return float64(n.blockChain.CurrentBlock().Time())
+	})
+	if n.p2p == nil {
+		// A read-only node has no peers.
+		n.registerDBMetrics()
+		return
+	}
+	prometheus.GetOrCreateGaugeFunc("chain_sync_lag", func() float64 {
+		head := n.blockChain.CurrentBlock().Number64().Uint64()
+		highest := n.p2p.Peers().HighestBlockNumber().Uint64()
+		if highest <= head {
+			return 0
+		}
+		return float64(highest - head)
+	})
+	prometheus.GetOrCreateGaugeFunc("p2p_peers_inbound", func() float64 {
+		return float64(len(n.p2p.Peers().InboundConnected()))
+	})
+	prometheus.GetOrCreateGaugeFunc("p2p_peers_outbound", func() float64 {
+		return float64(len(n.p2p.Peers().OutboundConnected()))
+	})
+	n.registerDBMetrics()
+}
+
+// registerDBMetrics exports the database gauges.
+func (n *Node) registerDBMetrics() {
+	db := &dbStatCache{n: n}
+	prometheus.GetOrCreateGaugeFunc("db_used_bytes", db.gauge(func(s *dbstat.Stat) uint64 { return s.UsedSize }))
+	prometheus.GetOrCreateGaugeFunc("db_map_size_bytes", db.gauge(func(s *dbstat.Stat) uint64 { return s.MapSize }))
+	prometheus.GetOrCreateGaugeFunc("db_tables_bytes", db.gauge(func(s *dbstat.Stat) uint64 { return s.TablesSize }))
+	prometheus.GetOrCreateGaugeFunc("db_readers", db.gauge(func(s *dbstat.Stat) uint64 { return uint64(s.Readers) }))
+	for _, kind := range []string{"branch", "leaf", "overflow"} {
+		kind := kind
+		prometheus.GetOrCreateGaugeFunc(`db_pages{type="`+kind+`"}`, db.gauge(func(s *dbstat.Stat) (pages uint64) {
+			for _, t := range s.Tables {
+				switch kind {
+				case "branch":
+					pages += t.BranchPages
+				case "leaf":
+					pages += t.LeafPages
+				case "overflow":
+					pages += t.OverflowPages
+				}
+			}
+			return pages
+		}))
+	}
+}
+
+// startMetricsPush starts the configured InfluxDB and pushgateway reporters.
+func (n *Node) startMetricsPush(config conf.MetricsConfig) {
+	var reporters []prometheus.Reporter
+	if config.EnableInfluxDB || config.EnableInfluxDBV2 {
+		tags := make(map[string]string)
+		for _, kv := range strings.Split(config.InfluxDBTags, ",") {
+			if kv = strings.TrimSpace(kv); kv == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				log.Warn("Ignoring malformed InfluxDB tag", "tag", kv)
+				continue
+			}
+			tags[k] = v
+		}
+		if config.EnableInfluxDBV2 {
+			log.Info("Enabling metrics export to InfluxDB (v2)", "endpoint", config.InfluxDBEndpoint, "bucket", config.InfluxDBBucket)
+			reporters = append(reporters, prometheus.NewInfluxDBV2(config.InfluxDBEndpoint, config.InfluxDBToken, config.InfluxDBOrg, config.InfluxDBBucket, tags))
+		} else {
+			log.Info("Enabling metrics export to InfluxDB", "endpoint", config.InfluxDBEndpoint, "database", config.InfluxDBDatabase)
+			reporters = append(reporters, prometheus.NewInfluxDB(config.InfluxDBEndpoint, config.InfluxDBDatabase, config.InfluxDBUsername, config.InfluxDBPassword, tags))
+		}
+	}
+	if config.PushGateway != "" {
+		instance, _ := os.Hostname()
+		log.Info("Enabling metrics export to pushgateway", "endpoint", config.PushGateway, "job", config.PushGatewayJob, "instance", instance)
+		reporters = append(reporters, prometheus.NewPushGateway(config.PushGateway, config.PushGatewayJob, instance))
+	}
+	if len(reporters) == 0 {
+		return
+	}
+	interval := config.PushInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		prometheus.Push(ctx, prometheus.DefaultRegistry, interval, reporters...)
+	}()
+	n.stopMetricsPush = func() {
+		cancel()
+		<-done
+	}
+}
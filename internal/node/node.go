@@ -26,7 +26,6 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/gofrs/flock"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/common/cmp"
 	"github.com/n42blockchain/N42/common/hexutil"
@@ -35,18 +34,23 @@ import (
 	n42deposit "github.com/n42blockchain/N42/contracts/deposit/AMT"
 	fujideposit "github.com/n42blockchain/N42/contracts/deposit/FUJI"
 	nftdeposit "github.com/n42blockchain/N42/contracts/deposit/NFT"
+	"github.com/n42blockchain/N42/internal/backfill"
+	"github.com/n42blockchain/N42/internal/commitment"
 	"github.com/n42blockchain/N42/internal/debug"
 	"github.com/n42blockchain/N42/internal/p2p"
 	n42sync "github.com/n42blockchain/N42/internal/sync"
 	initialsync "github.com/n42blockchain/N42/internal/sync/initial-sync"
 	"github.com/n42blockchain/N42/internal/tracers"
+	"github.com/n42blockchain/N42/internal/vm"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 	"github.com/urfave/cli/v2"
 
 	"github.com/n42blockchain/N42/internal"
 	"github.com/n42blockchain/N42/internal/api"
 
 	"github.com/c2h5oh/datasize"
+	libmdbx "github.com/erigontech/mdbx-go/mdbx"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
@@ -61,9 +65,11 @@ import (
 	"github.com/n42blockchain/N42/log"
 
 	"sync"
+	"sync/atomic"
 
 	"github.com/n42blockchain/N42/accounts"
 	"github.com/n42blockchain/N42/accounts/keystore"
+	"github.com/n42blockchain/N42/accounts/usbwallet"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/transaction"
@@ -76,6 +82,8 @@ import (
 	"github.com/n42blockchain/N42/internal/miner"
 	"github.com/n42blockchain/N42/internal/txgen"
 	"github.com/n42blockchain/N42/internal/txspool"
+	"github.com/n42blockchain/N42/internal/witness"
+	"github.com/n42blockchain/N42/modules/ethdb/remotedb"
 	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
 	"github.com/n42blockchain/N42/params"
@@ -97,18 +105,21 @@ type Node struct {
 	startStopLock sync.Mutex    // Start/Stop are protected by an additional lock
 	state         int           // Tracks state of node lifecycle
 	shutDown      chan struct{} // Channel to wait for termination notifications
-	dirLock       *flock.Flock  // prevents concurrent use of instance directory
+	dirLock       *DatadirLock  // prevents concurrent use of instance directory
 
 	// s
 	miner           *miner.Miner
 	blockChain      common.IBlockChain
 	engine          consensus.Engine
 	db              kv.RwDB
+	ancients        *rawdb.Freezer
 	txspool         common.ITxsPool
 	depositContract *deposit.Deposit
 	p2p             p2p.P2P
 	sync            *n42sync.Service
 	is              *initialsync.Service
+	backfill        *backfill.Service
+	commit          *commitment.Service
 	accman          *accounts.Manager
 
 	api     *api.API
@@ -119,6 +130,8 @@ type Node struct {
 	ws            *httpServer
 	httpAuth      *httpServer //
 	wsAuth        *httpServer //
+	kv            *grpc.Server // Serves the database to remote RPC nodes
+	kvServer      *remotedb.Server
 	inprocHandler *jsonrpc.Server
 
 	keyDir     string // key store directory
@@ -126,6 +139,9 @@ type Node struct {
 
 	// Development tools
 	txGenerator *txgen.Generator // Transaction generator for testing
+
+	shutdownPhase   atomic.Value // Name of the shutdown step in progress
+	stopMetricsPush func()       // Sends the last metrics report, nil unless pushing
 }
 
 const (
@@ -156,11 +172,45 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		err             error
 	)
 
+	// The database of another node cannot be written either.
+	if cfg.NodeCfg.RemoteKV != "" {
+		cfg.NodeCfg.ReadOnly = true
+	}
+	if err := checkReadOnly(cfg); err != nil {
+		return nil, err
+	}
+
+	// Acquire the instance directory lock before touching the database.
+	dirLock, err := openDataDir(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if !success {
+			dirLock.Unlock()
+		}
+	}()
+
 	//
 	chainKv, err = OpenDatabase(cfg, nil, kv.ChainDB.String())
 	if nil != err {
 		return nil, err
 	}
+	ancients, err := OpenAncients(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if !success && ancients != nil {
+			rawdb.SetAncients(nil)
+			ancients.Close()
+		}
+	}()
+	if pending, err := rawdb.RunMigrations(ctx, chainKv, rawdb.Migrations, cfg.NodeCfg.ReadOnly); err != nil {
+		return nil, err
+	} else if cfg.NodeCfg.ReadOnly && len(pending) > 0 {
+		log.Warn("Database has pending migrations, they are applied by the node owning the data directory", "count", len(pending))
+	}
 
 	if err := chainKv.View(ctx, func(tx kv.Tx) error {
 		//
@@ -189,10 +239,18 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		return nil, err
 	}
 
+	if genesisHash == (types.Hash{}) && cfg.NodeCfg.ReadOnly {
+		return nil, fmt.Errorf("read-only node needs an initialised chain in %s", cfg.NodeCfg.DataDir)
+	}
 	if genesisHash == (types.Hash{}) {
-		genesisHash = *params.GenesisHashByChainName(cfg.NodeCfg.Chain)
-		genesisConfig = internal.GenesisByChainName(cfg.NodeCfg.Chain)
-		chainConfig = params.ChainConfigByChainName(cfg.NodeCfg.Chain)
+		if cfg.DevCfg.Enabled {
+			genesisConfig = internal.DevGenesisBlock(devAccounts())
+			chainConfig = genesisConfig.Config
+		} else {
+			genesisHash = *params.GenesisHashByChainName(cfg.NodeCfg.Chain)
+			genesisConfig = internal.GenesisByChainName(cfg.NodeCfg.Chain)
+			chainConfig = params.ChainConfigByChainName(cfg.NodeCfg.Chain)
+		}
 		if err := chainKv.Update(ctx, func(tx kv.RwTx) error {
 			var genesisErr error
 			genesisBlock, genesisErr = WriteGenesisBlock(tx, genesisConfig)
@@ -206,7 +264,7 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 	}
 
 	// update ChainConfig everytime
-	if cfg.NodeCfg.Chain != "private" {
+	if cfg.NodeCfg.Chain != "private" && !cfg.NodeCfg.ReadOnly {
 		if err := chainKv.Update(ctx, func(tx kv.RwTx) error {
 			genesisHash = *params.GenesisHashByChainName(cfg.NodeCfg.Chain)
 			genesisConfig = internal.GenesisByChainName(cfg.NodeCfg.Chain)
@@ -219,30 +277,53 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		}
 	}
 
-	// Acquire the instance directory lock.
-	if err := node.openDataDir(cfg); err != nil {
-		return nil, err
+	if len(cfg.Overrides) > 0 {
+		if chainConfig, err = overrideChainConfig(ctx, chainKv, chainConfig, cfg.Overrides); err != nil {
+			return nil, err
+		}
 	}
 
 	cfg.ChainCfg = chainConfig
-
-	p2p, err := p2p.NewService(ctx, genesisBlock.Hash(), cfg.P2PCfg, cfg.NodeCfg)
-	if err != nil {
+	if err := vm.ValidateCustomPrecompiles(chainConfig); err != nil {
+		return nil, err
+	}
+	if _, err := miner.NewTxOrdering(cfg.Miner.Ordering); err != nil {
 		return nil, err
 	}
+	if len(cfg.Miner.ExtraData) > int(params.MaximumExtraDataSize) {
+		return nil, fmt.Errorf("miner extra-data exceeds %d bytes", params.MaximumExtraDataSize)
+	}
 
-	switch cfg.ChainCfg.Consensus {
-	case params.CliqueConsensus:
-		engine = apoa.New(cfg.ChainCfg.Clique, chainKv)
-	case params.AposConsensu:
-		engine = apos.New(cfg.ChainCfg.Apos, chainKv, cfg.ChainCfg)
-	case params.Faker:
-		engine = apos.NewFaker()
-	default:
-		return nil, fmt.Errorf("invalid engine name %s", cfg.ChainCfg.Consensus)
+	// A read-only node neither syncs nor serves blocks to peers.
+	var network p2p.P2P
+	if !cfg.NodeCfg.ReadOnly {
+		if network, err = p2p.NewService(ctx, genesisBlock.Hash(), cfg.P2PCfg, cfg.NodeCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.DevCfg.Enabled && cfg.ChainCfg.Consensus == params.Faker {
+		engine = apos.NewDevFaker(cfg.DevCfg.Period)
+		cfg.Miner.Instant = cfg.DevCfg.Period == 0
+	} else if engine, err = CreateConsensusEngine(cfg.ChainCfg, chainKv); err != nil {
+		return nil, err
+	}
+	if pv, ok := engine.(consensus.ParallelVerifier); ok {
+		pv.SetVerifyParallelism(cfg.NodeCfg.VerifyWorkers, cfg.NodeCfg.VerifyAhead)
 	}
 
-	bc, _ := internal.NewBlockChain(ctx, genesisBlock, engine, chainKv, p2p, cfg.ChainCfg)
+	bc, _ := internal.NewBlockChain(ctx, genesisBlock, engine, chainKv, network, cfg.ChainCfg)
+	if ancients != nil && cfg.DatabaseCfg.FreezeThreshold > 0 {
+		bc.(*internal.BlockChain).SetFreezer(ancients, cfg.DatabaseCfg.FreezeThreshold)
+	}
+	bc.(*internal.BlockChain).SetMaxReorgDepth(cfg.NodeCfg.MaxReorgDepth)
+	if cfg.NodeCfg.ReadOnly {
+		bc.(*internal.BlockChain).SetReadOnly()
+	} else if cfg.NodeCfg.CommitEvery > 1 {
+		if err := bc.(*internal.BlockChain).SetCommitBatch(cfg.NodeCfg.CommitEvery, cfg.NodeCfg.CommitSize*1024*1024); err != nil {
+			return nil, err
+		}
+	}
 
 	if cfg.ChainCfg.Apos != nil {
 		depositContracts := make(map[types.Address]deposit.DepositContract, 0)
@@ -271,19 +352,27 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		depositContract = deposit.NewDeposit(ctx, bc, chainKv, depositContracts)
 	}
 
-	pool, _ := txspool.NewTxsPool(ctx, bc, depositContract)
+	poolConfig, err := txsPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pool, _ := txspool.NewTxsPoolWithConfig(ctx, poolConfig, bc, depositContract)
 
 	is := initialsync.NewService(ctx, &initialsync.Config{
 		Chain: bc,
-		P2P:   p2p,
+		P2P:   network,
 	})
 
-	syncServer := n42sync.NewService(
-		ctx,
-		n42sync.WithP2P(p2p),
-		n42sync.WithChainService(bc),
-		n42sync.WithInitialSync(is),
-	)
+	var syncServer *n42sync.Service
+	if !cfg.NodeCfg.ReadOnly {
+		syncServer = n42sync.NewService(
+			ctx,
+			n42sync.WithP2P(network),
+			n42sync.WithChainService(bc),
+			n42sync.WithInitialSync(is),
+			n42sync.WithWatchdog(cfg.WatchdogCfg),
+		)
+	}
 
 	//todo
 	var txs []*transaction.Transaction
@@ -302,6 +391,22 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 
 	miner := miner.NewMiner(ctx, cfg, bc, engine, pool, nil)
 
+	var indexes []backfill.Index
+	for _, name := range cfg.NodeCfg.Backfill {
+		index, err := backfill.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	backfiller := backfill.NewService(ctx, chainKv, func() uint64 {
+		return bc.CurrentBlock().Number64().Uint64()
+	}, is.Synced, indexes...)
+	var committer *commitment.Service
+	if cfg.NodeCfg.StateCommitment {
+		committer = commitment.NewService(ctx, chainKv, is.Synced)
+	}
+
 	keyDir, isEphem, err := getKeyStoreDir(&cfg.NodeCfg)
 	if err != nil {
 		return nil, err
@@ -321,10 +426,12 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		genesisBlock:    genesisBlock,
 		blockChain:      bc,
 		db:              chainKv,
+		ancients:        ancients,
 		shutDown:        make(chan struct{}),
 		txspool:         pool,
 		engine:          engine,
 		depositContract: depositContract,
+		dirLock:         dirLock,
 
 		inprocHandler: jsonrpc.NewServer(),
 		http:          newHTTPServer(),
@@ -338,9 +445,11 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 		keyDir:     keyDir,
 		keyDirTemp: isEphem,
 
-		p2p:  p2p,
-		sync: syncServer,
-		is:   is,
+		p2p:      network,
+		sync:     syncServer,
+		is:       is,
+		backfill: backfiller,
+		commit:   committer,
 	}
 
 	// Apply flags.
@@ -349,6 +458,11 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 	if err = setAccountManagerBackends(&node, &cfg.NodeCfg); err != nil {
 		log.Errorf("Failed to set account manager backends: %v", err)
 	}
+	if cfg.DevCfg.Enabled {
+		if err := node.setupDevAccounts(); err != nil {
+			return nil, err
+		}
+	}
 
 	gpoParams := cfg.GPO
 	if gpoParams.Default == nil {
@@ -369,6 +483,10 @@ func NewNode(cliCtx *cli.Context, cfg *conf.Config) (*Node, error) {
 
 	node.api = api.NewAPI(bc, chainKv, engine, pool, node.AccountManager(), cfg.ChainCfg)
 	node.api.SetGpo(api.NewOracle(bc, miner, cfg.ChainCfg, gpoParams))
+	node.api.SetMiner(miner)
+	node.api.SetSyncer(is)
+	node.api.SetExtRPCEnabled(cfg.NodeCfg.ExtRPCEnabled())
+	node.api.SetReadOnly(cfg.NodeCfg.ReadOnly)
 	success = true
 	return &node, nil
 }
@@ -394,7 +512,7 @@ func (n *Node) Start() error {
 		return err
 	}
 
-	if n.config.NodeCfg.Miner {
+	if n.config.NodeCfg.Miner && !n.config.NodeCfg.ReadOnly {
 
 		// Configure the local mining address
 		eb, err := n.Etherbase()
@@ -430,25 +548,47 @@ func (n *Node) Start() error {
 	n.rpcAPIs = append(n.rpcAPIs, n.engine.APIs(n.blockChain)...)
 	n.rpcAPIs = append(n.rpcAPIs, n.api.Apis()...)
 	n.rpcAPIs = append(n.rpcAPIs, tracers.APIs(n.api)...)
+	n.rpcAPIs = append(n.rpcAPIs, witness.APIs(n.api)...)
 	n.rpcAPIs = append(n.rpcAPIs, debug.APIs()...)
+	n.rpcAPIs = append(n.rpcAPIs, n.backfill.APIs()...)
+	if n.commit != nil {
+		n.rpcAPIs = append(n.rpcAPIs, n.commit.APIs()...)
+	}
+	if n.config.DevCfg.Enabled {
+		n.rpcAPIs = append(n.rpcAPIs, n.api.DevApis()...)
+	}
 
 	if err := n.startRPC(); err != nil {
 		log.Error("failed start jsonrpc service", zap.Error(err))
 		return err
 	}
 
+	n.SetupMetrics(n.config.MetricsCfg)
+	if n.config.NodeCfg.ReadOnly {
+		log.Info("Serving the chain read-only", "datadir", n.config.NodeCfg.DataDir, "remote", n.config.NodeCfg.RemoteKV)
+		return nil
+	}
+
 	//n.p2p.AddConnectionHandler()
 	n.p2p.Start()
 	n.sync.Start()
 
-	n.SetupMetrics(n.config.MetricsCfg)
-
 	if n.depositContract != nil {
 		n.depositContract.Start()
 	}
 
+	n.logSyncCheckpoint()
 	go n.is.Start()
 
+	if err := n.backfill.Start(); err != nil {
+		return err
+	}
+	if n.commit != nil {
+		if err := n.commit.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Start transaction generator if enabled
 	if n.config.DevCfg.TxGenEnabled {
 		n.startTxGenerator()
@@ -506,29 +646,44 @@ func (n *Node) stopInProc() {
 	n.inprocHandler.Stop()
 }
 
-func (n *Node) openDataDir(cfg *conf.Config) error {
+func openDataDir(cfg *conf.Config) (*DatadirLock, error) {
 	if cfg.NodeCfg.DataDir == "" {
-		return nil // ephemeral
+		return nil, nil // ephemeral
 	}
-
-	if err := os.MkdirAll(cfg.NodeCfg.DataDir, 0700); err != nil {
-		return err
+	if cfg.NodeCfg.ReadOnly {
+		return nil, nil // shares the directory with the node holding the lock
 	}
 	// Lock the instance directory to prevent concurrent use by another instance as well as
 	// accidental use of the instance directory as a database.
-	n.dirLock = flock.New(filepath.Join(cfg.NodeCfg.DataDir, "LOCK"))
+	return LockDatadir(cfg.NodeCfg.DataDir)
+}
 
-	if locked, err := n.dirLock.TryLock(); err != nil {
-		return err
-	} else if !locked {
-		return ErrDatadirUsed
+// checkReadOnly rejects the options that need to write to the data directory
+// when the node is read-only.
+func checkReadOnly(cfg *conf.Config) error {
+	if !cfg.NodeCfg.ReadOnly {
+		return nil
+	}
+	switch {
+	case cfg.NodeCfg.DataDir == "" && cfg.NodeCfg.RemoteKV == "":
+		return errors.New("read-only node needs a data directory")
+	case cfg.NodeCfg.Miner:
+		return errors.New("read-only node cannot mine")
+	case len(cfg.NodeCfg.Backfill) > 0:
+		return errors.New("read-only node cannot backfill indexes")
+	case cfg.NodeCfg.StateCommitment:
+		return errors.New("read-only node cannot maintain the state commitment")
+	case cfg.DatabaseCfg.FreezeThreshold > 0:
+		return errors.New("read-only node cannot freeze blocks")
+	case cfg.DevCfg.Enabled:
+		return errors.New("read-only node cannot run in dev mode")
 	}
 	return nil
 }
 
 func (n *Node) closeDataDir() {
 	// Release instance directory lock.
-	if n.dirLock != nil && n.dirLock.Locked() {
+	if n.dirLock != nil {
 		n.dirLock.Unlock()
 		n.dirLock = nil
 	}
@@ -570,6 +725,10 @@ func (n *Node) obtainJWTSecret(cliParam string) ([]byte, error) {
 func (n *Node) startRPC() error {
 
 	openAPIs, allAPIs := n.getAPIs()
+	// The developer chain has nothing to protect, serve everything openly.
+	if n.config.DevCfg.Enabled {
+		openAPIs = allAPIs
+	}
 
 	if err := n.startInProc(); err != nil {
 		return err
@@ -580,6 +739,23 @@ func (n *Node) startRPC() error {
 		//	return err
 		//}
 	}
+	// The API keys guard both the HTTP and the WebSocket endpoint, and share
+	// their rate limits.
+	var apiKeys *apiKeyStore
+	if keyFile := n.config.NodeCfg.HTTPAPIKeys; keyFile != "" && (n.config.NodeCfg.HTTP || n.config.NodeCfg.WS) {
+		keys, err := loadAPIKeys(keyFile, n.config.NodeCfg.HTTPAPIKeyRate, n.config.NodeCfg.HTTPAPIKeyBurst)
+		if err != nil {
+			return fmt.Errorf("failed to load API keys: %w", err)
+		}
+		if admin := n.config.NodeCfg.HTTPAPIAdminKey; admin != "" {
+			if _, ok := keys.keys[admin]; ok {
+				return errors.New("the admin API key must not be an RPC key")
+			}
+			keys.admin = admin
+		}
+		log.Info("RPC requires API keys", "keys", len(keys.keys), "rate", n.config.NodeCfg.HTTPAPIKeyRate)
+		apiKeys = keys
+	}
 	if n.config.NodeCfg.HTTP {
 		//todo []string{"eth", "web3", "debug", "net", "apoa", "txpool", "apos"}
 		config := httpConfig{
@@ -587,12 +763,13 @@ func (n *Node) startRPC() error {
 			Vhosts:             []string{"*"},
 			Modules:            utils.SplitAndTrim(n.config.NodeCfg.HTTPApi),
 			prefix:             "",
+			apiKeys:            apiKeys,
 		}
 		port, _ := strconv.Atoi(n.config.NodeCfg.HTTPPort)
 		if err := n.http.setListenAddr(n.config.NodeCfg.HTTPHost, port); err != nil {
 			return err
 		}
-		if err := n.http.enableRPC(n.rpcAPIs, config); err != nil {
+		if err := n.http.enableRPC(openAPIs, config); err != nil {
 			return err
 		}
 		if err := n.http.start(); err != nil {
@@ -612,8 +789,9 @@ func (n *Node) startRPC() error {
 			Origins:   utils.SplitAndTrim(n.config.NodeCfg.WSOrigins),
 			prefix:    "",
 			jwtSecret: []byte{},
+			apiKeys:   apiKeys,
 		}
-		if err := n.ws.enableWS(n.rpcAPIs, config); err != nil {
+		if err := n.ws.enableWS(openAPIs, config); err != nil {
 			return err
 		}
 		if err := n.ws.start(); err != nil {
@@ -630,7 +808,7 @@ func (n *Node) startRPC() error {
 		config := httpConfig{
 			CorsAllowedOrigins: utils.SplitAndTrim(n.config.NodeCfg.HTTPCors),
 			Vhosts:             []string{"*"},
-			Modules:            []string{"admin", "apos"},
+			Modules:            []string{"admin", "apos", "eth", "txpool"},
 			prefix:             "",
 			jwtSecret:          jwtSecret,
 		}
@@ -646,6 +824,36 @@ func (n *Node) startRPC() error {
 		}
 	}
 
+	if n.config.NodeCfg.KVRPC {
+		if err := n.startKV(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startKV serves the chain database to RPC nodes started with --kv.remote
+// over gRPC. Calls are authenticated with the JWT secret of the
+// authenticated RPC endpoint, which the RPC nodes must share.
+func (n *Node) startKV() error {
+	jwtSecret, err := n.obtainJWTSecret(n.config.NodeCfg.JWTSecret)
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", net.JoinHostPort(n.config.NodeCfg.KVAddr, strconv.Itoa(n.config.NodeCfg.KVPort)))
+	if err != nil {
+		return err
+	}
+	var ancients rawdb.AncientReader
+	if n.ancients != nil {
+		ancients = n.ancients
+	}
+	n.kvServer = remotedb.NewServer(n.db, ancients, log2.New())
+	n.kv = grpc.NewServer(jwtAuth{secret: jwtSecret}.serverOptions()...)
+	n.kvServer.Register(n.kv)
+	go n.kv.Serve(lis)
+	log.Info("KV server started", "endpoint", lis.Addr())
 	return nil
 }
 
@@ -653,6 +861,12 @@ func (n *Node) stopRPC() {
 	n.http.stop()
 	n.ws.stop()
 	n.ipc.stop()
+	if n.kv != nil {
+		n.kv.Stop()
+	}
+	if n.kvServer != nil {
+		n.kvServer.Close()
+	}
 	n.stopInProc()
 }
 
@@ -687,90 +901,81 @@ func (n *Node) Close() error {
 }
 
 // stopServices terminates running services, RPC and p2p networking.
-// It is the inverse of Start.
+// It is the inverse of Start. Networking goes first so no new blocks arrive,
+// then the chain commits the block in flight and the sync checkpoint is
+// written before the database is closed by doClose.
 func (n *Node) stopServices() []error {
-	var errs []error
-	total := 9 // Total number of services to stop
-	current := 0
-	
-	logProgress := func(service string) {
-		current++
-		log.Info(fmt.Sprintf("  [%d/%d] Stopping %s...", current, total, service))
-	}
-	
-	logDone := func(service string, err error) {
-		if err != nil {
-			log.Warn(fmt.Sprintf("  [%d/%d] %s stopped with error", current, total, service), "err", err)
-		}
-	}
-	
-	// 1. Stop RPC services
-	logProgress("RPC services")
-	n.stopRPC()
-	
-	// 2. Stop transaction generator if running
-	logProgress("Transaction generator")
-	if n.txGenerator != nil {
-		n.txGenerator.Stop()
-	}
-	
-	// 3. Stop miner
-	logProgress("Miner")
-	n.miner.Close()
-	
-	// 4. Stop blockchain (this may take time to flush data)
-	logProgress("Blockchain (flushing data)")
-	if err := n.blockChain.Close(); err != nil {
-		errs = append(errs, err)
-		logDone("Blockchain", err)
-	}
-	
-	// 5. Stop consensus engine
-	logProgress("Consensus engine")
-	if err := n.engine.Close(); err != nil {
-		errs = append(errs, err)
-		logDone("Consensus engine", err)
-	}
-	
-	// 6. Stop transaction pool
-	logProgress("Transaction pool")
-	if err := n.txspool.Stop(); err != nil {
-		errs = append(errs, err)
-		logDone("Transaction pool", err)
+	phases := []struct {
+		name string
+		stop func() error
+	}{
+		{"Stopping RPC services", func() error { n.stopRPC(); return nil }},
+		{"Stopping sync service", func() error {
+			if n.sync != nil {
+				return n.sync.Stop()
+			}
+			return nil
+		}},
+		{"Stopping P2P network", func() error {
+			if n.p2p != nil {
+				return n.p2p.Stop()
+			}
+			return nil
+		}},
+		{"Stopping initial sync", n.is.Stop},
+		{"Stopping index backfill", n.backfill.Stop},
+		{"Stopping state commitment", func() error {
+			if n.commit != nil {
+				return n.commit.Stop()
+			}
+			return nil
+		}},
+		{"Stopping transaction generator", func() error {
+			if n.txGenerator != nil {
+				n.txGenerator.Stop()
+			}
+			return nil
+		}},
+		{"Stopping miner", func() error { n.miner.Close(); return nil }},
+		{"Stopping blockchain (flushing in-flight blocks)", n.blockChain.Close},
+		{"Saving sync checkpoint", n.writeSyncCheckpoint},
+		{"Stopping consensus engine", n.engine.Close},
+		{"Stopping transaction pool", n.txspool.Stop},
+		{"Stopping deposit contract", func() error {
+			if n.depositContract != nil {
+				return n.depositContract.Stop()
+			}
+			return nil
+		}},
+		{"Pushing final metrics", func() error {
+			if n.stopMetricsPush != nil {
+				n.stopMetricsPush()
+			}
+			return nil
+		}},
 	}
-	
-	// 7. Stop deposit contract
-	logProgress("Deposit contract")
-	if n.depositContract != nil {
-		if err := n.depositContract.Stop(); err != nil {
+
+	var errs []error
+	for i, phase := range phases {
+		n.shutdownPhase.Store(phase.name)
+		log.Info(fmt.Sprintf("  [%d/%d] %s...", i+1, len(phases), phase.name))
+		if err := phase.stop(); err != nil {
+			log.Warn(fmt.Sprintf("  [%d/%d] %s failed", i+1, len(phases), phase.name), "err", err)
 			errs = append(errs, err)
-			logDone("Deposit contract", err)
 		}
 	}
-	
-	// 8. Stop initial sync
-	logProgress("Initial sync")
-	if err := n.is.Stop(); err != nil {
-		errs = append(errs, err)
-		logDone("Initial sync", err)
-	}
-	
-	// 9. Stop P2P networking
-	logProgress("P2P network")
-	if err := n.p2p.Stop(); err != nil {
-		errs = append(errs, err)
-		logDone("P2P network", err)
-	}
-	
-	// Stop sync service (doesn't count towards total as it's auxiliary)
-	if err := n.sync.Stop(); err != nil {
-		errs = append(errs, err)
-	}
-	
+	n.shutdownPhase.Store("Closing database")
 	log.Info("All services stopped")
 	return errs
 }
 
+// ShutdownPhase returns the name of the shutdown step in progress, or an
+// empty string if the node is not shutting down.
+func (n *Node) ShutdownPhase() string {
+	phase, _ := n.shutdownPhase.Load().(string)
+	return phase
+}
+
 // doClose releases resources acquired by New(), collecting errors.
 func (n *Node) doClose(errs []error) error {
 	// Close databases. This needs the lock because it needs to
@@ -779,6 +984,13 @@ func (n *Node) doClose(errs []error) error {
 	n.state = closedState
 	n.db.Close()
 	n.lock.Unlock()
+	log.Info("Database closed")
+	if n.ancients != nil {
+		rawdb.SetAncients(nil)
+		if err := n.ancients.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	if err := n.accman.Close(); err != nil {
 		errs = append(errs, err)
@@ -864,6 +1076,19 @@ func setAccountManagerBackends(stack *Node, conf *conf.NodeConfig) error {
 	// accounts in both externally and locally, plus very racey.
 	am.AddBackend(keystore.NewKeyStore(keydir, scryptN, scryptP))
 
+	if conf.USB {
+		// Hardware wallets are optional, a missing transport must not stop the node
+		if hub, err := usbwallet.NewLedgerHub(); err != nil {
+			log.Warn("Failed to start Ledger hub, disabling", "err", err)
+		} else {
+			am.AddBackend(hub)
+		}
+		if hub, err := usbwallet.NewTrezorHub(); err != nil {
+			log.Warn("Failed to start Trezor hub, disabling", "err", err)
+		} else {
+			am.AddBackend(hub)
+		}
+	}
 	return nil
 }
 
@@ -874,6 +1099,7 @@ func (n *Node) KeyStoreDir() string {
 
 func (n *Node) SetupMetrics(config conf.MetricsConfig) {
 	if config.Enable {
+		n.registerNodeMetrics()
 		if config.HTTP != "" {
 			address := net.JoinHostPort(config.HTTP, fmt.Sprintf("%d", config.Port))
 			log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
@@ -881,6 +1107,7 @@ func (n *Node) SetupMetrics(config conf.MetricsConfig) {
 		} else if config.Port != 0 {
 			log.Warn(fmt.Sprintf("--%s specified without --%s, metrics server will not start.", "metrics.port", "metrics.addr"))
 		}
+		n.startMetricsPush(config)
 	}
 
 }
@@ -908,7 +1135,25 @@ func (s *Node) Etherbase() (eb types.Address, err error) {
 	return types.Address{}, fmt.Errorf("etherbase must be explicitly specified")
 }
 
+// CreateConsensusEngine creates the consensus engine selected by the chain
+// config.
+func CreateConsensusEngine(chainConfig *params.ChainConfig, db kv.RwDB) (consensus.Engine, error) {
+	switch chainConfig.Consensus {
+	case params.CliqueConsensus:
+		return apoa.New(chainConfig.Clique, db), nil
+	case params.AposConsensu:
+		return apos.New(chainConfig.Apos, db, chainConfig), nil
+	case params.Faker:
+		return apos.NewFaker(), nil
+	default:
+		return nil, fmt.Errorf("invalid engine name %s", chainConfig.Consensus)
+	}
+}
+
 func OpenDatabase(cfg *conf.Config, logger log2.Logger, name string) (kv.RwDB, error) {
+	if cfg.NodeCfg.RemoteKV != "" {
+		return openRemoteDatabase(cfg.NodeCfg.RemoteKV, cfg.NodeCfg.JWTSecret)
+	}
 	var chainKv kv.RwDB
 	if cfg.NodeCfg.DataDir == "" {
 		chainKv = memdb.New("")
@@ -931,17 +1176,28 @@ func OpenDatabase(cfg *conf.Config, logger log2.Logger, name string) (kv.RwDB, e
 		if exclusive {
 			opts = opts.Exclusive()
 		}
+		if cfg.NodeCfg.ReadOnly {
+			// Accede to the geometry of the environment opened by the
+			// writing node.
+			opts = opts.Flags(func(flags uint) uint { return flags | libmdbx.Readonly | libmdbx.Accede })
+		}
 
 		modules.N42Init()
 		kv.ChaindataTablesCfg = modules.N42TableCfg
 
 		opts = opts.MapSize(8 * datasize.TB)
+		if cfg.ResourceCfg.Cache > 0 {
+			opts = opts.DirtySpace(cfg.ResourceCfg.Cache * uint64(datasize.MB))
+		}
 		return opts.Open()
 	}
 	chainKv, err = openFunc(false)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.NodeCfg.ReadOnly {
+		return rawdb.ReadOnlyDB(chainKv), nil
+	}
 
 	if err = chainKv.Update(context.Background(), func(tx kv.RwTx) (err error) {
 		return params.SetN42Version(tx, params.VersionKeyCreated)
@@ -951,6 +1207,61 @@ func OpenDatabase(cfg *conf.Config, logger log2.Logger, name string) (kv.RwDB, e
 	return chainKv, nil
 }
 
+// openRemoteDatabase connects to the database served by another node at
+// addr, authenticating with the JWT secret read from secretFile. Its frozen
+// blocks are read remotely as well.
+func openRemoteDatabase(addr, secretFile string) (kv.RwDB, error) {
+	if secretFile == "" {
+		return nil, errors.New("a remote database requires the JWT secret of the serving node (--authrpc.jwtsecret)")
+	}
+	data, err := os.ReadFile(secretFile)
+	if err != nil {
+		return nil, err
+	}
+	jwtSecret, err := hexutil.Decode(strings.TrimSpace(string(data)))
+	if err != nil || len(jwtSecret) != 32 {
+		return nil, fmt.Errorf("invalid JWT secret in %s", secretFile)
+	}
+
+	log.Info("Opening remote database", "addr", addr)
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	db, err := remotedb.Open(context.Background(), addr, grpc.WithPerRPCCredentials(jwtAuth{secret: jwtSecret}))
+	if err != nil {
+		return nil, err
+	}
+	rawdb.SetAncients(db)
+	return db, nil
+}
+
+// OpenAncients opens the freezer of the data directory and makes the chain
+// readers use it. It returns nil if freezing is disabled and nothing was
+// frozen before.
+func OpenAncients(cfg *conf.Config) (*rawdb.Freezer, error) {
+	if cfg.NodeCfg.DataDir == "" || cfg.NodeCfg.RemoteKV != "" {
+		return nil, nil
+	}
+	dir := cfg.DatabaseCfg.AncientDir
+	if dir == "" {
+		dir = "ancient"
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cfg.NodeCfg.DataDir, dir)
+	}
+	if cfg.DatabaseCfg.FreezeThreshold == 0 || cfg.NodeCfg.ReadOnly {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	f, err := rawdb.NewFreezer(dir, cfg.NodeCfg.ReadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("open ancient store: %w", err)
+	}
+	rawdb.SetAncients(f)
+	return f, nil
+}
+
 func WriteGenesisBlock(db kv.RwTx, genesis *conf.Genesis) (*block.Block, error) {
 	if genesis == nil {
 		return nil, internal.ErrGenesisNoConfig
@@ -978,6 +1289,78 @@ func WriteChainConfig(db kv.RwTx, genesisHash types.Hash, genesis *conf.Genesis)
 	return nil
 }
 
+// overrideChainConfig applies the fork overrides of the command line to the
+// chain config. They are validated against the current head but not stored,
+// so restarting without them returns to the stored config.
+func overrideChainConfig(ctx context.Context, db kv.RoDB, chainConfig *params.ChainConfig, overrides map[string]uint64) (*params.ChainConfig, error) {
+	var head, headTime uint64
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		if current := rawdb.ReadCurrentBlock(tx); current != nil {
+			head, headTime = current.Number64().Uint64(), current.Time()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	overridden, err := chainConfig.WithOverrides(overrides, head, headTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain config override: %w", err)
+	}
+	for name, at := range overrides {
+		log.Warn("Overriding fork activation", "fork", name, "at", at)
+	}
+	return overridden, nil
+}
+
+// txsPoolConfig returns the transaction pool configuration of cfg. Unset
+// limits keep their defaults and the journal is kept in the data directory.
+func txsPoolConfig(cfg *conf.Config) (txspool.TxsPoolConfig, error) {
+	c, pc := txspool.DefaultTxPoolConfig, cfg.TxPoolCfg
+	for _, s := range pc.Locals {
+		s = strings.TrimSpace(s)
+		if !types.IsHexAddress(s) {
+			return c, fmt.Errorf("invalid txpool local account %q", s)
+		}
+		c.Locals = append(c.Locals, types.HexToAddress(s))
+	}
+	c.NoLocals = pc.NoLocals
+	if pc.Journal != "" {
+		c.Journal = pc.Journal
+		if !filepath.IsAbs(c.Journal) {
+			c.Journal = filepath.Join(cfg.NodeCfg.DataDir, c.Journal)
+		}
+	}
+	if cfg.NodeCfg.ReadOnly {
+		// The journal belongs to the node owning the data directory.
+		c.Journal = ""
+	}
+	if pc.Rejournal != 0 {
+		c.Rejournal = pc.Rejournal
+	}
+	if pc.PriceLimit != 0 {
+		c.PriceLimit = pc.PriceLimit
+	}
+	if pc.PriceBump != 0 {
+		c.PriceBump = pc.PriceBump
+	}
+	if pc.AccountSlots != 0 {
+		c.AccountSlots = pc.AccountSlots
+	}
+	if pc.GlobalSlots != 0 {
+		c.GlobalSlots = pc.GlobalSlots
+	}
+	if pc.AccountQueue != 0 {
+		c.AccountQueue = pc.AccountQueue
+	}
+	if pc.GlobalQueue != 0 {
+		c.GlobalQueue = pc.GlobalQueue
+	}
+	if pc.Lifetime != 0 {
+		c.Lifetime = pc.Lifetime
+	}
+	return c, nil
+}
+
 func SplitTagsFlag(tagsFlag string) map[string]string {
 	tags := strings.Split(tagsFlag, ",")
 	tagsMap := map[string]string{}
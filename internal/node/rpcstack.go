@@ -41,15 +41,17 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string
-	jwtSecret          []byte // optional JWT secret
+	jwtSecret          []byte       // optional JWT secret
+	apiKeys            *apiKeyStore // optional API keys
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
 	Origins   []string
 	Modules   []string
-	prefix    string // path prefix on which to mount ws handler
-	jwtSecret []byte // optional JWT secret
+	prefix    string       // path prefix on which to mount ws handler
+	jwtSecret []byte       // optional JWT secret
+	apiKeys   *apiKeyStore // optional API keys
 }
 
 type rpcHandler struct {
@@ -170,6 +172,9 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil && isWebsocket(r) {
+		if h.wsConfig.apiKeys != nil {
+			h.wsConfig.apiKeys.extractPathToken(r, h.wsConfig.prefix)
+		}
 		if checkPath(r, h.wsConfig.prefix) {
 			ws.ServeHTTP(w, r)
 		}
@@ -185,6 +190,9 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if h.httpConfig.apiKeys != nil {
+			h.httpConfig.apiKeys.extractPathToken(r, h.httpConfig.prefix)
+		}
 		if checkPath(r, h.httpConfig.prefix) {
 			rpc.ServeHTTP(w, r)
 			return
@@ -207,8 +215,13 @@ func (h *httpServer) enableWS(apis []jsonrpc.API, config wsConfig) error {
 		return err
 	}
 	h.wsConfig = config
+	ws := srv.WebsocketHandler(config.Origins)
+	if config.apiKeys != nil {
+		// The key is checked, and counted, when the connection is upgraded
+		ws = newAPIKeyHandler(config.apiKeys, ws)
+	}
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
+		Handler: NewWSHandlerStack(ws, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -302,8 +315,19 @@ func (h *httpServer) enableRPC(apis []jsonrpc.API, config httpConfig) error {
 		return err
 	}
 	h.httpConfig = config
+	var rpc http.Handler = srv
+	if config.apiKeys != nil {
+		// The key is checked behind the CORS handler, which answers the
+		// preflight requests browsers send without it.
+		rpc = newAPIKeyHandler(config.apiKeys, srv)
+		usagePath := strings.TrimSuffix(config.prefix, "/") + apiKeyUsagePath
+		if _, ok := h.handlerNames[usagePath]; !ok {
+			h.mux.Handle(usagePath, config.apiKeys.usageHandler())
+			h.handlerNames[usagePath] = "API key usage"
+		}
+	}
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(rpc, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
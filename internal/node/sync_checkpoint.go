@@ -0,0 +1,79 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// writeSyncCheckpoint records the committed head and the sync target so the
+// next start can report where syncing resumes from.
+func (n *Node) writeSyncCheckpoint() error {
+	current := n.blockChain.CurrentBlock()
+	if current == nil || n.config.NodeCfg.ReadOnly {
+		return nil
+	}
+	cp := &rawdb.SyncCheckpoint{
+		Number: current.Number64().Uint64(),
+		Hash:   current.Hash(),
+		Target: n.is.Target(),
+		Time:   uint64(time.Now().Unix()),
+	}
+	// The node context may already be cancelled during shutdown.
+	if err := n.db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.WriteSyncCheckpoint(tx, cp)
+	}); err != nil {
+		return err
+	}
+	log.Info("Saved sync checkpoint", "number", cp.Number, "hash", cp.Hash, "target", cp.Target)
+	return nil
+}
+
+// logSyncCheckpoint reports the checkpoint written by the previous shutdown
+// and removes it, so a missing checkpoint on the next start means the node
+// did not shut down cleanly.
+func (n *Node) logSyncCheckpoint() {
+	var cp *rawdb.SyncCheckpoint
+	if err := n.db.Update(n.ctx, func(tx kv.RwTx) (err error) {
+		if cp, err = rawdb.ReadSyncCheckpoint(tx); err != nil || cp == nil {
+			return err
+		}
+		return rawdb.DeleteSyncCheckpoint(tx)
+	}); err != nil {
+		log.Warn("Failed to read sync checkpoint", "err", err)
+		return
+	}
+	current := n.blockChain.CurrentBlock()
+	if cp == nil {
+		if current.Number64().Uint64() > 0 {
+			log.Warn("No sync checkpoint found, the previous shutdown was not clean", "head", current.Number64().Uint64())
+		}
+		return
+	}
+	if current.Number64().Uint64() != cp.Number || current.Hash() != cp.Hash {
+		log.Warn("Chain head differs from the last sync checkpoint", "checkpoint", cp.Number, "checkpointHash", cp.Hash,
+			"head", current.Number64().Uint64(), "headHash", current.Hash())
+	}
+	log.Info("Resuming sync from checkpoint", "number", current.Number64().Uint64(), "target", cp.Target,
+		"saved", time.Unix(int64(cp.Time), 0).Format(time.RFC3339))
+}
@@ -28,6 +28,11 @@ func (s *Service) Broadcast(ctx context.Context, msg proto.Message) error {
 	ctx, span := trace.StartSpan(ctx, "p2p.Broadcast")
 	defer span.End()
 
+	// A node without peers, such as a dev chain, has nobody to gossip to
+	if s.cfg.MaxPeers == 0 {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, maxBroadcastTime)
 	defer cancel()
 
@@ -0,0 +1,35 @@
+package encoder
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	encodedRawBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_encoder_raw_bytes_total",
+		Help: "The number of uncompressed payload bytes passed to the network encoder, by encoding.",
+	},
+		[]string{"encoding"})
+	encodedCompressedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_encoder_compressed_bytes_total",
+		Help: "The number of compressed payload bytes produced by the network encoder, by encoding.",
+	},
+		[]string{"encoding"})
+	compressionRatio = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "p2p_encoder_compression_ratio",
+		Help:    "Ratio of uncompressed to compressed size for each encoded message, by encoding.",
+		Buckets: []float64{0.5, 1, 1.25, 1.5, 2, 2.5, 3, 4, 6, 8},
+	},
+		[]string{"encoding"})
+)
+
+// observeCompression records the raw and compressed sizes of one encoded message.
+func observeCompression(encoding string, raw, compressed int) {
+	if raw == 0 || compressed == 0 {
+		return
+	}
+	encodedRawBytes.WithLabelValues(encoding).Add(float64(raw))
+	encodedCompressedBytes.WithLabelValues(encoding).Add(float64(compressed))
+	compressionRatio.WithLabelValues(encoding).Observe(float64(raw) / float64(compressed))
+}
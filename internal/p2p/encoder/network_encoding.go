@@ -2,6 +2,7 @@ package encoder
 
 import (
 	"io"
+	"strings"
 
 	ssz "github.com/prysmaticlabs/fastssz"
 )
@@ -21,3 +22,13 @@ type NetworkEncoding interface {
 	// ProtocolSuffix returns the last part of the protocol ID to indicate the encoding scheme.
 	ProtocolSuffix() string
 }
+
+// ForProtocol returns the encoding identified by the suffix of a negotiated protocol ID.
+// Protocol IDs without a known compression suffix fall back to ssz_snappy, which every
+// peer supports.
+func ForProtocol(protocolID string) NetworkEncoding {
+	if strings.HasSuffix(protocolID, "/"+ProtocolSuffixSSZZstd) {
+		return &SszZstdNetworkEncoder{}
+	}
+	return &SszNetworkEncoder{}
+}
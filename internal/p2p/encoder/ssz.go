@@ -43,8 +43,9 @@ func (_ SszNetworkEncoder) EncodeGossip(w io.Writer, msg fastssz.Marshaler) (int
 	if uint64(len(b)) > MaxGossipSize {
 		return 0, errors.Errorf("gossip message exceeds max gossip size: %d bytes > %d bytes", len(b), MaxGossipSize)
 	}
-	b = snappy.Encode(nil /*dst*/, b)
-	return w.Write(b)
+	compressed := snappy.Encode(nil /*dst*/, b)
+	observeCompression(ProtocolSuffixSSZSnappy, len(b), len(compressed))
+	return w.Write(compressed)
 }
 
 // EncodeWithMaxLength the proto message to the io.Writer. This encoding prefixes the byte slice with a protobuf varint
@@ -69,7 +70,12 @@ func (_ SszNetworkEncoder) EncodeWithMaxLength(w io.Writer, msg fastssz.Marshale
 	if err != nil {
 		return 0, err
 	}
-	return writeSnappyBuffer(w, b)
+	cw := &countingWriter{w: w}
+	n, err := writeSnappyBuffer(cw, b)
+	if err == nil {
+		observeCompression(ProtocolSuffixSSZSnappy, len(b), cw.n)
+	}
+	return n, err
 }
 
 func doDecode(b []byte, to fastssz.Unmarshaler) error {
@@ -154,6 +160,18 @@ func (_ SszNetworkEncoder) MaxLength(length uint64) (int, error) {
 	return maxLen, nil
 }
 
+// countingWriter tracks the number of bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
 // Writes a bytes value through a snappy buffered writer.
 func writeSnappyBuffer(w io.Writer, b []byte) (int, error) {
 	bufWriter := newBufferedWriter(w)
@@ -0,0 +1,153 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	fastssz "github.com/prysmaticlabs/fastssz"
+)
+
+var _ NetworkEncoding = (*SszZstdNetworkEncoder)(nil)
+
+// ProtocolSuffixSSZZstd is the last part of the protocol ID for zstd compressed SimpleSerialize payloads.
+const ProtocolSuffixSSZZstd = "ssz_zstd"
+
+// The zstd encoder and decoder are safe for concurrent use through EncodeAll/DecodeAll,
+// so a single shared instance of each is kept for the process.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxChunkSize))
+)
+
+// SszZstdNetworkEncoder supports p2p networking encoding using SimpleSerialize
+// with zstd compression. It trades CPU for a noticeably better ratio than snappy
+// on block bodies and is offered on block-carrying req/resp protocols only.
+//
+// Framing on streams is <varint raw length> | <varint compressed length> | <zstd frame>,
+// so the reader never consumes bytes belonging to the next response chunk.
+type SszZstdNetworkEncoder struct{}
+
+// EncodeGossip the proto gossip message to the io.Writer.
+func (_ SszZstdNetworkEncoder) EncodeGossip(w io.Writer, msg fastssz.Marshaler) (int, error) {
+	if msg == nil {
+		return 0, nil
+	}
+	b, err := msg.MarshalSSZ()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(len(b)) > MaxGossipSize {
+		return 0, errors.Errorf("gossip message exceeds max gossip size: %d bytes > %d bytes", len(b), MaxGossipSize)
+	}
+	compressed := zstdEncoder.EncodeAll(b, nil)
+	observeCompression(ProtocolSuffixSSZZstd, len(b), len(compressed))
+	return w.Write(compressed)
+}
+
+// EncodeWithMaxLength the proto message to the io.Writer. This encoding prefixes the compressed frame with
+// the raw and compressed sizes as varints. This checks that the encoded message isn't larger than the provided max limit.
+func (_ SszZstdNetworkEncoder) EncodeWithMaxLength(w io.Writer, msg fastssz.Marshaler) (int, error) {
+	if msg == nil {
+		return 0, nil
+	}
+	b, err := msg.MarshalSSZ()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(len(b)) > MaxChunkSize {
+		return 0, fmt.Errorf(
+			"size of encoded message is %d which is larger than the provided max limit of %d",
+			len(b),
+			MaxChunkSize,
+		)
+	}
+	compressed := zstdEncoder.EncodeAll(b, nil)
+	observeCompression(ProtocolSuffixSSZZstd, len(b), len(compressed))
+
+	header := append(EncodeVarint(uint64(len(b))), EncodeVarint(uint64(len(compressed)))...)
+	if _, err := w.Write(header); err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(compressed); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// DecodeGossip decodes the bytes to the protobuf gossip message provided.
+func (_ SszZstdNetworkEncoder) DecodeGossip(b []byte, to fastssz.Unmarshaler) error {
+	b, err := DecodeZstd(b, MaxGossipSize)
+	if err != nil {
+		return err
+	}
+	return doDecode(b, to)
+}
+
+// DecodeZstd decodes a zstd compressed message.
+func DecodeZstd(msg []byte, maxSize uint64) ([]byte, error) {
+	out, err := zstdDecoder.DecodeAll(msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(out)) > maxSize {
+		return nil, errors.Errorf("zstd message exceeds max size: %d bytes > %d bytes", len(out), maxSize)
+	}
+	return out, nil
+}
+
+// DecodeWithMaxLength the bytes from io.Reader to the protobuf message provided.
+// This checks that the decoded message isn't larger than the provided max limit.
+func (e SszZstdNetworkEncoder) DecodeWithMaxLength(r io.Reader, to fastssz.Unmarshaler) error {
+	msgLen, err := readVarint(r)
+	if err != nil {
+		return err
+	}
+	if msgLen > MaxChunkSize {
+		return fmt.Errorf(
+			"remaining bytes %d goes over the provided max limit of %d",
+			msgLen,
+			MaxChunkSize,
+		)
+	}
+	compressedLen, err := readVarint(r)
+	if err != nil {
+		return err
+	}
+	msgMax, err := e.MaxLength(msgLen)
+	if err != nil {
+		return err
+	}
+	if compressedLen > uint64(msgMax) {
+		return fmt.Errorf("compressed length %d exceeds the bound %d for a %d byte message", compressedLen, msgMax, msgLen)
+	}
+
+	compressed := make([]byte, compressedLen)
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return err
+	}
+	buf, err := zstdDecoder.DecodeAll(compressed, make([]byte, 0, msgLen))
+	if err != nil {
+		return err
+	}
+	if uint64(len(buf)) != msgLen {
+		return fmt.Errorf("decompressed length %d does not match the announced length %d", len(buf), msgLen)
+	}
+	return doDecode(buf, to)
+}
+
+// ProtocolSuffix returns the appropriate suffix for protocol IDs.
+func (_ SszZstdNetworkEncoder) ProtocolSuffix() string {
+	return "/" + ProtocolSuffixSSZZstd
+}
+
+// MaxLength specifies the maximum possible length of an encoded
+// chunk of data.
+func (_ SszZstdNetworkEncoder) MaxLength(length uint64) (int, error) {
+	maxLen := zstdEncoder.MaxEncodedSize(int(length))
+	if maxLen < 0 {
+		return 0, errors.Errorf("max encoded length is negative: %d", maxLen)
+	}
+	return maxLen, nil
+}
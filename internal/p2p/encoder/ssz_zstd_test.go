@@ -0,0 +1,88 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package encoder
+
+import (
+	"bytes"
+	"testing"
+
+	ssztype "github.com/n42blockchain/N42/common/types/ssz"
+)
+
+func TestZstdEncodeWithMaxLengthRoundTrip(t *testing.T) {
+	e := SszZstdNetworkEncoder{}
+	buf := new(bytes.Buffer)
+
+	// Two chunks back to back must decode independently.
+	first, second := ssztype.SSZUint64(42), ssztype.SSZUint64(1<<40)
+	if _, err := e.EncodeWithMaxLength(buf, &first); err != nil {
+		t.Fatalf("encode first: %v", err)
+	}
+	if _, err := e.EncodeWithMaxLength(buf, &second); err != nil {
+		t.Fatalf("encode second: %v", err)
+	}
+
+	var got ssztype.SSZUint64
+	if err := e.DecodeWithMaxLength(buf, &got); err != nil {
+		t.Fatalf("decode first: %v", err)
+	}
+	if got != first {
+		t.Errorf("first = %d, want %d", got, first)
+	}
+	if err := e.DecodeWithMaxLength(buf, &got); err != nil {
+		t.Fatalf("decode second: %v", err)
+	}
+	if got != second {
+		t.Errorf("second = %d, want %d", got, second)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d trailing bytes left in stream", buf.Len())
+	}
+}
+
+func TestZstdGossipRoundTrip(t *testing.T) {
+	e := SszZstdNetworkEncoder{}
+	buf := new(bytes.Buffer)
+	msg := ssztype.SSZUint64(7)
+	if _, err := e.EncodeGossip(buf, &msg); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var got ssztype.SSZUint64
+	if err := e.DecodeGossip(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got != msg {
+		t.Errorf("got %d, want %d", got, msg)
+	}
+}
+
+func TestForProtocol(t *testing.T) {
+	tests := []struct {
+		protocol string
+		want     string
+	}{
+		{"/rpc/bodies_by_range/1/ssz_zstd", "/" + ProtocolSuffixSSZZstd},
+		{"/rpc/bodies_by_range/1/ssz_snappy", "/" + ProtocolSuffixSSZSnappy},
+		{"/rpc/status/1/ssz_snappy", "/" + ProtocolSuffixSSZSnappy},
+		{"", "/" + ProtocolSuffixSSZSnappy},
+	}
+	for _, tt := range tests {
+		if got := ForProtocol(tt.protocol).ProtocolSuffix(); got != tt.want {
+			t.Errorf("ForProtocol(%q) = %s, want %s", tt.protocol, got, tt.want)
+		}
+	}
+}
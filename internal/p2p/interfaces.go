@@ -92,6 +92,7 @@ type SenderEncoder interface {
 // EncodingProvider provides p2p network encoding.
 type EncodingProvider interface {
 	Encoding() encoder.NetworkEncoding
+	RPCEncodings(baseTopic string) []encoder.NetworkEncoding
 }
 
 // PubSubProvider provides the p2p pubsub protocol.
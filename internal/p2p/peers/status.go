@@ -699,7 +699,7 @@ func (p *Status) PeersToPrune() []peer.ID {
 func (p *Status) HighestBlockNumber() *uint256.Int {
 	p.store.RLock()
 	defer p.store.RUnlock()
-	var highestSlot *uint256.Int
+	highestSlot := new(uint256.Int)
 	for _, peerData := range p.store.Peers() {
 		if peerData != nil && peerData.ChainState != nil && peerData.CurrentHeight().Cmp(highestSlot) == 1 {
 			highestSlot = peerData.CurrentHeight()
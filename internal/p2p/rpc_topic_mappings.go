@@ -45,6 +45,13 @@ const (
 	RPCHeadersDataTopicV1 = protocolPrefix + HeadersByRangeMessageName + SchemaVersionV1
 )
 
+// compressibleRPCTopics lists the req/resp protocols carrying blocks and bodies. For these,
+// zstd is negotiated in addition to the baseline ssz_snappy encoding.
+var compressibleRPCTopics = map[string]bool{
+	RPCBodiesDataTopicV1:  true,
+	RPCHeadersDataTopicV1: true,
+}
+
 // RPC errors for topic parsing.
 const (
 	invalidRPCMessageType = "provided message type doesn't have a registered mapping"
@@ -55,6 +62,8 @@ var RPCTopicMappings = map[string]interface{}{
 	// RPC Status Message
 	RPCStatusTopicV1:     new(sync_pb.Status),
 	RPCBodiesDataTopicV1: new(sync_pb.BodiesByRangeRequest),
+	// Sparse header skeletons for the downloader
+	RPCHeadersDataTopicV1: new(sync_pb.HeadersByRangeRequest),
 
 	RPCPingTopicV1:    new(ssztype.SSZUint64),
 	RPCGoodByeTopicV1: new(ssztype.SSZUint64),
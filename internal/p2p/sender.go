@@ -8,6 +8,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/n42blockchain/N42/internal/p2p/encoder"
 	"github.com/pkg/errors"
 	ssz "github.com/prysmaticlabs/fastssz"
 	"go.opencensus.io/trace"
@@ -23,7 +24,12 @@ func (s *Service) Send(ctx context.Context, message interface{}, baseTopic strin
 	if err := VerifyTopicMapping(baseTopic, message); err != nil {
 		return nil, err
 	}
-	topic := baseTopic + s.Encoding().ProtocolSuffix()
+	encodings := s.RPCEncodings(baseTopic)
+	protocols := make([]protocol.ID, 0, len(encodings))
+	for _, e := range encodings {
+		protocols = append(protocols, protocol.ID(baseTopic+e.ProtocolSuffix()))
+	}
+	topic := string(protocols[0])
 	span.AddAttributes(trace.StringAttribute("topic", topic))
 
 	log.Trace(fmt.Sprintf("Sending RPC request to peer %s", pid.String()), "topic", topic, "request", pretty.Sprint(message))
@@ -32,16 +38,19 @@ func (s *Service) Send(ctx context.Context, message interface{}, baseTopic strin
 	ctx, cancel := context.WithTimeout(ctx, maxDialTimeout)
 	defer cancel()
 
-	stream, err := s.host.NewStream(ctx, pid, protocol.ID(topic))
+	// The remote picks the first protocol it supports, which also fixes the
+	// encoding used for the request and every response chunk on this stream.
+	stream, err := s.host.NewStream(ctx, pid, protocols...)
 	if err != nil {
 		//tracing.AnnotateError(span, err)
 		return nil, err
 	}
+	encoding := StreamEncoding(stream)
 	castedMsg, ok := message.(ssz.Marshaler)
 	if !ok {
 		return nil, errors.Errorf("%T does not support the ssz marshaller interface", message)
 	}
-	if _, err := s.Encoding().EncodeWithMaxLength(stream, castedMsg); err != nil {
+	if _, err := encoding.EncodeWithMaxLength(stream, castedMsg); err != nil {
 		//tracing.AnnotateError(span, err)
 		_err := stream.Reset()
 		_ = _err
@@ -58,3 +67,8 @@ func (s *Service) Send(ctx context.Context, message interface{}, baseTopic strin
 
 	return stream, nil
 }
+
+// StreamEncoding returns the encoding negotiated for the given stream.
+func StreamEncoding(stream network.Stream) encoder.NetworkEncoding {
+	return encoder.ForProtocol(string(stream.Protocol()))
+}
@@ -355,6 +355,16 @@ func (_ *Service) Encoding() encoder.NetworkEncoding {
 	return &encoder.SszNetworkEncoder{}
 }
 
+// RPCEncodings returns the encodings supported for the given base rpc topic, in order of
+// preference. Block-carrying topics offer zstd ahead of ssz_snappy unless compression has been
+// configured as "snappy"; every other topic uses the baseline encoding only.
+func (s *Service) RPCEncodings(baseTopic string) []encoder.NetworkEncoding {
+	if !compressibleRPCTopics[baseTopic] || s.cfg.Compression == conf.CompressionSnappy {
+		return []encoder.NetworkEncoding{s.Encoding()}
+	}
+	return []encoder.NetworkEncoding{&encoder.SszZstdNetworkEncoder{}, s.Encoding()}
+}
+
 // PubSub returns the p2p pubsub framework.
 func (s *Service) PubSub() *pubsub.PubSub {
 	return s.pubsub
@@ -0,0 +1,77 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// headPollInterval is how often a read-only chain looks for a new head.
+const headPollInterval = time.Second
+
+// SetReadOnly makes the chain follow the head written to the database by the
+// node owning it, instead of inserting blocks itself. It must be called
+// before Start.
+func (bc *BlockChain) SetReadOnly() {
+	bc.readOnly = true
+}
+
+// followHeadLoop picks up the head block written by the owning node and
+// announces it like a newly inserted block.
+func (bc *BlockChain) followHeadLoop() {
+	defer bc.loopWg.Done()
+	ticker := time.NewTicker(headPollInterval)
+	defer ticker.Stop()
+	// The stored head hash is tracked rather than compared with the hash of
+	// the current block, which is recomputed from the decoded header.
+	var headHash types.Hash
+	for {
+		select {
+		case <-bc.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var head *block.Block
+		if err := bc.ChainDB.View(bc.ctx, func(tx kv.Tx) error {
+			hash := rawdb.ReadHeadBlockHash(tx)
+			if hash == (types.Hash{}) || hash == headHash {
+				return nil
+			}
+			if head = rawdb.ReadCurrentBlock(tx); head != nil {
+				headHash = hash
+			}
+			return nil
+		}); err != nil {
+			log.Warn("Failed to read the head block", "err", err)
+			continue
+		}
+		if head == nil {
+			continue
+		}
+		bc.currentBlock.Store(head)
+		headBlockGauge.Set(head.Number64().Uint64())
+		common.ChainHeadTopic.Send(common.ChainHighestBlock{Block: *head, Inserted: true})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n42blockchain/N42/common/types"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Client talks to a remote signer. Every signature it returns has been
+// checked against the public key the signer announced for the address.
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+
+	mu   sync.Mutex
+	keys map[types.Address]types.PublicKey
+}
+
+// NewClient creates a client for the signer at url. An https url is
+// verified with the system roots.
+func NewClient(url, token string) *Client {
+	return &Client{
+		url:   strings.TrimRight(url, "/"),
+		token: token,
+		http:  &http.Client{Timeout: defaultTimeout},
+		keys:  make(map[types.Address]types.PublicKey),
+	}
+}
+
+// Keys fetches the keys of the signer
+func (c *Client) Keys(ctx context.Context) ([]Key, error) {
+	var keys []Key
+	if err := c.do(ctx, http.MethodGet, KeysPath, nil, &keys); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = make(map[types.Address]types.PublicKey, len(keys))
+	for _, key := range keys {
+		c.keys[key.Address] = key.PublicKey
+	}
+	return keys, nil
+}
+
+// Sign requests the signature of req.StateRoot by req.Address
+func (c *Client) Sign(ctx context.Context, req *SignRequest) (types.Signature, error) {
+	c.mu.Lock()
+	pub, ok := c.keys[req.Address]
+	c.mu.Unlock()
+	if !ok {
+		return types.Signature{}, fmt.Errorf("%w: %s", ErrUnknownAddress, req.Address)
+	}
+
+	res := new(SignResponse)
+	if err := c.do(ctx, http.MethodPost, SignPath, req, res); err != nil {
+		return types.Signature{}, err
+	}
+	if err := VerifySignature(pub, req.StateRoot, res.Signature); err != nil {
+		return types.Signature{}, fmt.Errorf("signer returned a bad signature for %s: %w", req.Address, err)
+	}
+	return res.Signature, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		e := new(errorResponse)
+		json.NewDecoder(resp.Body).Decode(e)
+		return statusError(resp.StatusCode, e.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
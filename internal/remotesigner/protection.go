@@ -0,0 +1,124 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotesigner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// ErrSlashable is returned for a request that conflicts with a state root the
+// address signed before. The chain records two roots signed by one address
+// at the same number as equivocation.
+var ErrSlashable = errors.New("refusing slashable signature")
+
+// signedRoot is the last state root an address signed
+type signedRoot struct {
+	Number    uint64     `json:"number"`
+	StateRoot types.Hash `json:"stateRoot"`
+}
+
+// Protection records the last state root signed by every address in a file,
+// so a signer never signs a second root at the same number or goes back to a
+// lower number, not even after a restart.
+type Protection struct {
+	path string
+
+	mu   sync.Mutex
+	last map[types.Address]signedRoot
+}
+
+// OpenProtection loads the signed roots recorded in path. The file is
+// created on the first signature.
+func OpenProtection(path string) (*Protection, error) {
+	p := &Protection{path: path, last: make(map[types.Address]signedRoot)}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return p, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &p.last); err != nil {
+		return nil, fmt.Errorf("failed to read signed roots: %w", err)
+	}
+	return p, nil
+}
+
+// Check reports whether req may be signed. An allowed request is recorded
+// before Check returns, so a crash while signing never forgets it. Asking
+// again for the root last signed is allowed.
+func (p *Protection) Check(req *SignRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last, ok := p.last[req.Address]
+	switch {
+	case !ok || req.Number > last.Number:
+	case req.Number < last.Number:
+		return fmt.Errorf("%w: %s signed block %d, asked for %d", ErrSlashable, req.Address, last.Number, req.Number)
+	case req.StateRoot != last.StateRoot:
+		return fmt.Errorf("%w: %s signed root %s at block %d, asked for %s", ErrSlashable, req.Address, last.StateRoot, last.Number, req.StateRoot)
+	default:
+		return nil
+	}
+
+	p.last[req.Address] = signedRoot{Number: req.Number, StateRoot: req.StateRoot}
+	if err := p.save(); err != nil {
+		if ok {
+			p.last[req.Address] = last
+		} else {
+			delete(p.last, req.Address)
+		}
+		return fmt.Errorf("failed to record signed root: %w", err)
+	}
+	return nil
+}
+
+// save writes the signed roots to a temporary file and renames it, so a
+// crash never leaves a truncated record behind
+func (p *Protection) save() error {
+	data, err := json.Marshal(p.last)
+	if err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
@@ -0,0 +1,188 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package remotesigner implements the protocol between a block verifier and
+// a signer holding the validators' BLS keys. The verifier only sends the
+// state roots it computed, so the keys can live on a separate hardened host
+// or behind an HSM.
+//
+// The protocol is JSON over HTTP:
+//
+//	GET  /keys  lists the addresses and BLS public keys the signer holds
+//	POST /sign  signs a state root with the key of an address
+//
+// Requests carry an optional bearer token. The protocol itself is not
+// encrypted: a signer that is not on the same host must be served over TLS,
+// which the client verifies against the system roots. Signatures are plain
+// BLS signatures over the state root, so the node can aggregate them.
+//
+// A signer refuses requests its Protection marks as slashable, so even a
+// compromised verifier cannot make it sign conflicting roots.
+package remotesigner
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+const (
+	KeysPath = "/keys"
+	SignPath = "/sign"
+
+	// maxRequestSize bounds the body of a sign request
+	maxRequestSize = 4096
+)
+
+var (
+	ErrUnknownAddress = errors.New("unknown signer address")
+	ErrUnauthorized   = errors.New("unauthorized")
+)
+
+// Key is a validator key held by a signer
+type Key struct {
+	Address   types.Address   `json:"address"`
+	PublicKey types.PublicKey `json:"publicKey"`
+}
+
+// SignRequest asks for the signature of a verified state root
+type SignRequest struct {
+	Address   types.Address `json:"address"`
+	Number    uint64        `json:"number"`
+	StateRoot types.Hash    `json:"stateRoot"`
+}
+
+// SignResponse carries the BLS signature of the requested state root
+type SignResponse struct {
+	Signature types.Signature `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Signer signs verified state roots with the BLS keys of validators
+type Signer interface {
+	// Keys returns the keys the signer can sign with
+	Keys(ctx context.Context) ([]Key, error)
+	// Sign returns the signature of req.StateRoot by req.Address
+	Sign(ctx context.Context, req *SignRequest) (types.Signature, error)
+}
+
+// VerifySignature checks that sig is the signature of root by pub
+func VerifySignature(pub types.PublicKey, root types.Hash, sig types.Signature) error {
+	pk, err := bls.PublicKeyFromBytes(pub[:])
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	s, err := bls.SignatureFromBytes(sig[:])
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !s.Verify(pk, root[:]) {
+		return errors.New("signature does not match the state root")
+	}
+	return nil
+}
+
+// NewHandler serves signer over the remote signer protocol. Sign requests
+// are checked against protection first. If token is not empty, requests must
+// present it as a bearer token.
+func NewHandler(signer Signer, protection *Protection, token string) http.Handler {
+	authorized := func(r *http.Request) bool {
+		return token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(KeysPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		if !authorized(r) {
+			writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		keys, err := signer.Keys(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, keys)
+	})
+	mux.HandleFunc(SignPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		if !authorized(r) {
+			writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		req := new(SignRequest)
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		err := protection.Check(req)
+		var sig types.Signature
+		if err == nil {
+			sig, err = signer.Sign(r.Context(), req)
+		}
+		switch {
+		case errors.Is(err, ErrUnknownAddress):
+			writeError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrSlashable):
+			writeError(w, http.StatusConflict, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, &SignResponse{Signature: sig})
+		}
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
+
+// statusError maps an error response back to the error the signer returned
+func statusError(status int, msg string) error {
+	switch status {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrUnknownAddress, strings.TrimPrefix(msg, ErrUnknownAddress.Error()+": "))
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %s", ErrSlashable, strings.TrimPrefix(msg, ErrSlashable.Error()+": "))
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	default:
+		return fmt.Errorf("signer returned %d: %s", status, msg)
+	}
+}
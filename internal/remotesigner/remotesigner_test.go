@@ -0,0 +1,180 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotesigner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/crypto/bls"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// testSigner signs with a single BLS key, or with a fixed signature if bad is set
+type testSigner struct {
+	addr   types.Address
+	secret bls.SecretKey
+	bad    bool
+}
+
+func newTestProtection(t *testing.T) *Protection {
+	p, err := OpenProtection(filepath.Join(t.TempDir(), "signed.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	secret, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testSigner{addr: types.HexToAddress("0x1111111111111111111111111111111111111111"), secret: secret}
+}
+
+func (s *testSigner) Keys(ctx context.Context) ([]Key, error) {
+	key := Key{Address: s.addr}
+	copy(key.PublicKey[:], s.secret.PublicKey().Marshal())
+	return []Key{key}, nil
+}
+
+func (s *testSigner) Sign(ctx context.Context, req *SignRequest) (types.Signature, error) {
+	if req.Address != s.addr {
+		return types.Signature{}, fmt.Errorf("%w: %s", ErrUnknownAddress, req.Address)
+	}
+	msg := req.StateRoot
+	if s.bad {
+		msg[0] ^= 0xff
+	}
+	var sig types.Signature
+	copy(sig[:], s.secret.Sign(msg[:]).Marshal())
+	return sig, nil
+}
+
+func TestClientSign(t *testing.T) {
+	signer := newTestSigner(t)
+	srv := httptest.NewServer(NewHandler(signer, newTestProtection(t), "secret"))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := NewClient(srv.URL, "secret")
+	keys, err := client.Keys(ctx)
+	if err != nil {
+		t.Fatalf("keys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Address != signer.addr {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	root := types.HexToHash("0xabcdef")
+	sig, err := client.Sign(ctx, &SignRequest{Address: signer.addr, Number: 7, StateRoot: root})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := VerifySignature(keys[0].PublicKey, root, sig); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	// Aggregating the returned signature must work like a local signature
+	agg, err := bls.SignatureFromBytes(sig[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.AggregateSignatures([]bls.Signature{agg}).FastAggregateVerify([]bls.PublicKey{signer.secret.PublicKey()}, root) {
+		t.Fatal("aggregated signature does not verify")
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	signer := newTestSigner(t)
+	srv := httptest.NewServer(NewHandler(signer, newTestProtection(t), "secret"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if _, err := NewClient(srv.URL, "wrong").Keys(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected %v, got %v", ErrUnauthorized, err)
+	}
+
+	client := NewClient(srv.URL, "secret")
+	if _, err := client.Keys(ctx); err != nil {
+		t.Fatal(err)
+	}
+	other := types.HexToAddress("0x2222222222222222222222222222222222222222")
+	if _, err := client.Sign(ctx, &SignRequest{Address: other}); !errors.Is(err, ErrUnknownAddress) {
+		t.Fatalf("expected %v, got %v", ErrUnknownAddress, err)
+	}
+
+	// A signature that does not match the announced key is rejected
+	signer.bad = true
+	if _, err := client.Sign(ctx, &SignRequest{Address: signer.addr, StateRoot: types.HexToHash("0x01")}); err == nil {
+		t.Fatal("expected a bad signature to be rejected")
+	}
+}
+
+func TestClientSlashable(t *testing.T) {
+	signer := newTestSigner(t)
+	srv := httptest.NewServer(NewHandler(signer, newTestProtection(t), "secret"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := NewClient(srv.URL, "secret")
+	if _, err := client.Keys(ctx); err != nil {
+		t.Fatal(err)
+	}
+	req := &SignRequest{Address: signer.addr, Number: 7, StateRoot: types.Hash{0x01}}
+	if _, err := client.Sign(ctx, req); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := client.Sign(ctx, req); err != nil {
+		t.Fatalf("sign again: %v", err)
+	}
+	if _, err := client.Sign(ctx, &SignRequest{Address: signer.addr, Number: 7, StateRoot: types.Hash{0x02}}); !errors.Is(err, ErrSlashable) {
+		t.Fatalf("second root: expected %v, got %v", ErrSlashable, err)
+	}
+	if _, err := client.Sign(ctx, &SignRequest{Address: signer.addr, Number: 6, StateRoot: types.Hash{0x01}}); !errors.Is(err, ErrSlashable) {
+		t.Fatalf("lower number: expected %v, got %v", ErrSlashable, err)
+	}
+}
+
+func TestProtectionPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signed.json")
+	p, err := OpenProtection(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := types.HexToAddress("0x1111111111111111111111111111111111111111")
+	if err := p.Check(&SignRequest{Address: addr, Number: 7, StateRoot: types.Hash{0x01}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The record survives a restart
+	p, err = OpenProtection(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Check(&SignRequest{Address: addr, Number: 7, StateRoot: types.Hash{0x02}}); !errors.Is(err, ErrSlashable) {
+		t.Fatalf("expected %v, got %v", ErrSlashable, err)
+	}
+	if err := p.Check(&SignRequest{Address: addr, Number: 8, StateRoot: types.Hash{0x02}}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+)
+
+var errReorgTooDeep = errors.New("reorg deeper than the allowed depth")
+
+var (
+	deepReorgRejectedMeter   = prometheus.GetOrCreateCounter(`chain_deep_reorgs_total{result="rejected"}`)
+	deepReorgOverriddenMeter = prometheus.GetOrCreateCounter(`chain_deep_reorgs_total{result="overridden"}`)
+)
+
+// reorgGuard refuses reorgs dropping more canonical blocks than a limit. An
+// aggregated signature over an old fork is cheap to replay once its signers
+// exited, so a long fork is only followed after the operator checked it and
+// allowed one of its blocks.
+type reorgGuard struct {
+	mu      sync.Mutex
+	limit   uint64                  // deepest reorg followed, 0 for no limit
+	allowed map[types.Hash]struct{} // fork blocks the operator accepted
+}
+
+func newReorgGuard(limit uint64) *reorgGuard {
+	return &reorgGuard{limit: limit, allowed: make(map[types.Hash]struct{})}
+}
+
+// check reports whether a reorg dropping depth blocks onto newChain may go
+// ahead, and whether that needed an override. An override is used up by the
+// reorg it allowed.
+func (g *reorgGuard) check(depth uint64, newChain []block.IBlock) (overridden bool, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.limit == 0 || depth <= g.limit {
+		return false, nil
+	}
+	for _, blk := range newChain {
+		if _, ok := g.allowed[blk.Hash()]; ok {
+			delete(g.allowed, blk.Hash())
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("%w: dropping %d blocks, limit %d", errReorgTooDeep, depth, g.limit)
+}
+
+// SetMaxReorgDepth makes the chain refuse reorgs dropping more than depth
+// canonical blocks unless the operator allowed them with AllowReorg. Zero
+// disables the limit.
+func (bc *BlockChain) SetMaxReorgDepth(depth uint64) {
+	bc.reorgs.mu.Lock()
+	defer bc.reorgs.mu.Unlock()
+	bc.reorgs.limit = depth
+}
+
+// AllowReorg lets the next reorg onto a fork containing hash go ahead however
+// deep it is.
+func (bc *BlockChain) AllowReorg(hash types.Hash) {
+	bc.reorgs.mu.Lock()
+	defer bc.reorgs.mu.Unlock()
+	bc.reorgs.allowed[hash] = struct{}{}
+	log.Warn("Deep reorg override armed", "hash", hash)
+}
+
+// checkReorgDepth applies the reorg depth limit to a reorg from oldChain to
+// newChain above commonBlock, raising an alert for every reorg beyond it.
+func (bc *BlockChain) checkReorgDepth(commonBlock block.IBlock, oldChain, newChain []block.IBlock) error {
+	depth := uint64(len(oldChain))
+	overridden, err := bc.reorgs.check(depth, newChain)
+	if err == nil && !overridden {
+		return nil
+	}
+	ev := common.DeepReorgEvent{
+		CommonBlock: commonBlock.Hash(),
+		Number:      commonBlock.Number64().Uint64(),
+		OldHead:     oldChain[0].Hash(),
+		NewHead:     newChain[0].Hash(),
+		Depth:       depth,
+		Overridden:  overridden,
+	}
+	if overridden {
+		deepReorgOverriddenMeter.Inc()
+		log.Warn("Following deep reorg allowed by operator", "common", ev.Number, "drop", depth, "newhead", ev.NewHead)
+	} else {
+		deepReorgRejectedMeter.Inc()
+		log.Error("Refused deep chain reorg, allow it with admin_allowReorg if the fork is legitimate",
+			"common", ev.Number, "hash", ev.CommonBlock, "drop", depth, "oldhead", ev.OldHead, "newhead", ev.NewHead)
+	}
+	common.DeepReorgTopic.Send(ev)
+	return err
+}
@@ -0,0 +1,55 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/block"
+)
+
+// TestReorgGuard checks that reorgs beyond the limit are refused unless a
+// block of the new chain was allowed, and that an override is used once.
+func TestReorgGuard(t *testing.T) {
+	g := newReorgGuard(2)
+
+	root := newFutureTestBlock(nil, 1, 1, 0)
+	fork := newFutureTestBlock(root, 2, 2, 1)
+	head := newFutureTestBlock(fork, 3, 3, 1)
+	newChain := []block.IBlock{head, fork}
+
+	if overridden, err := g.check(2, newChain); err != nil || overridden {
+		t.Fatalf("reorg at the limit: overridden %v, err %v", overridden, err)
+	}
+	if _, err := g.check(3, newChain); !errors.Is(err, errReorgTooDeep) {
+		t.Fatalf("deep reorg: have %v, want %v", err, errReorgTooDeep)
+	}
+
+	g.allowed[fork.Hash()] = struct{}{}
+	if overridden, err := g.check(3, newChain); err != nil || !overridden {
+		t.Fatalf("allowed reorg: overridden %v, err %v", overridden, err)
+	}
+	if _, err := g.check(3, newChain); !errors.Is(err, errReorgTooDeep) {
+		t.Fatalf("override reused: have %v, want %v", err, errReorgTooDeep)
+	}
+
+	g.limit = 0
+	if _, err := g.check(1000, newChain); err != nil {
+		t.Fatalf("unlimited guard refused reorg: %v", err)
+	}
+}
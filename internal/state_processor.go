@@ -182,6 +182,7 @@ func applyTransaction(config *params.ChainConfig, engine consensus.Engine, gp *c
 		receipt = &block.Receipt{Type: tx.Type(), CumulativeGasUsed: *usedGas}
 		if result.Failed() {
 			receipt.Status = block.ReceiptStatusFailed
+			receipt.RevertReason = result.Revert()
 		} else {
 			receipt.Status = block.ReceiptStatusSuccessful
 		}
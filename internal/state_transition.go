@@ -93,6 +93,12 @@ type Message interface {
 	AccessList() transaction.AccessList
 
 	IsFree() bool
+
+	BlobHashes() []types.Hash
+	BlobGas() uint64
+	BlobGasFeeCap() *uint256.Int
+
+	AuthList() transaction.AuthorizationList
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -131,7 +137,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, accessList transaction.AccessList, isContractCreation bool, isHomestead, isEIP2028 bool, isEIP3860 bool) (uint64, error) {
+func IntrinsicGas(data []byte, accessList transaction.AccessList, authList transaction.AuthorizationList, isContractCreation bool, isHomestead, isEIP2028 bool, isEIP3860 bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if isContractCreation && isHomestead {
@@ -177,6 +183,9 @@ func IntrinsicGas(data []byte, accessList transaction.AccessList, isContractCrea
 		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
 		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
 	}
+	if authList != nil {
+		gas += uint64(len(authList)) * params.PerEmptyAccountCost
+	}
 	return gas, nil
 }
 
@@ -244,6 +253,27 @@ func (st *StateTransition) buyGas(gasBailout bool) error {
 			return fmt.Errorf("%w: address %v", ErrInsufficientFunds, st.msg.From().Hex())
 		}
 	}
+	// Blob gas is bought up front at the blob base fee and never refunded.
+	if blobGas := st.msg.BlobGas(); blobGas > 0 {
+		if blobBaseFee := st.evm.Context().BlobBaseFee; blobBaseFee != nil {
+			blobFee, overflow := new(uint256.Int).MulOverflow(uint256.NewInt(blobGas), blobBaseFee)
+			if overflow {
+				return fmt.Errorf("%w: address %v", ErrInsufficientFunds, st.msg.From().Hex())
+			}
+			if mgval, overflow = mgval.AddOverflow(mgval, blobFee); overflow {
+				return fmt.Errorf("%w: address %v", ErrInsufficientFunds, st.msg.From().Hex())
+			}
+		}
+		if blobFeeCap := st.msg.BlobGasFeeCap(); st.gasFeeCap != nil && blobFeeCap != nil {
+			maxBlobFee, overflow := new(uint256.Int).MulOverflow(uint256.NewInt(blobGas), blobFeeCap)
+			if overflow {
+				return fmt.Errorf("%w: address %v", ErrInsufficientFunds, st.msg.From().Hex())
+			}
+			if balanceCheck, overflow = balanceCheck.AddOverflow(balanceCheck, maxBlobFee); overflow {
+				return fmt.Errorf("%w: address %v", ErrInsufficientFunds, st.msg.From().Hex())
+			}
+		}
+	}
 	var subBalance = false
 	if have, want := st.state.GetBalance(st.msg.From()), balanceCheck; have.Cmp(want) < 0 {
 		if !gasBailout {
@@ -313,9 +343,100 @@ func (st *StateTransition) preCheck(gasBailout bool) error {
 			}
 		}
 	}
+
+	// Check the blob fields of EIP-4844 transactions.
+	if blobHashes := st.msg.BlobHashes(); blobHashes != nil || st.msg.BlobGasFeeCap() != nil {
+		if !st.evm.ChainRules().IsCancun {
+			return fmt.Errorf("%w: address %v, blob transaction before cancun", ErrTxTypeNotSupported, st.msg.From().Hex())
+		}
+		if st.msg.To() == nil {
+			return fmt.Errorf("%w: address %v", transaction.ErrBlobTxCreate, st.msg.From().Hex())
+		}
+		if len(blobHashes) == 0 {
+			return fmt.Errorf("%w: address %v", ErrMissingBlobHashes, st.msg.From().Hex())
+		}
+		for i, h := range blobHashes {
+			if !transaction.IsValidVersionedHash(h) {
+				return fmt.Errorf("blob %d has invalid hash version: address %v", i, st.msg.From().Hex())
+			}
+		}
+		blobFeeCap, blobBaseFee := st.msg.BlobGasFeeCap(), st.evm.Context().BlobBaseFee
+		if !st.evm.Config().NoBaseFee || (blobFeeCap != nil && !blobFeeCap.IsZero()) {
+			if blobFeeCap == nil || (blobBaseFee != nil && blobFeeCap.Lt(blobBaseFee)) {
+				return fmt.Errorf("%w: address %v, maxFeePerBlobGas: %v blobBaseFee: %v", ErrBlobFeeCapTooLow,
+					st.msg.From().Hex(), blobFeeCap, blobBaseFee)
+			}
+		}
+	}
+
+	// Check that EIP-7702 authorization list signatures are well formed.
+	if authList := st.msg.AuthList(); authList != nil {
+		if !st.evm.ChainRules().IsPectra {
+			return fmt.Errorf("%w: address %v, set code transaction before pectra", ErrTxTypeNotSupported, st.msg.From().Hex())
+		}
+		if st.msg.To() == nil {
+			return fmt.Errorf("%w (sender %v)", ErrSetCodeTxCreate, st.msg.From().Hex())
+		}
+		if len(authList) == 0 {
+			return fmt.Errorf("%w (sender %v)", ErrEmptyAuthList, st.msg.From().Hex())
+		}
+	}
 	return st.buyGas(gasBailout)
 }
 
+// validateAuthorization checks an EIP-7702 authorization against the state and
+// returns its signer.
+func (st *StateTransition) validateAuthorization(auth *transaction.Authorization) (types.Address, error) {
+	// Verify chain ID is zero or the current chain.
+	if auth.ChainID != 0 && auth.ChainID != st.evm.ChainConfig().ChainID.Uint64() {
+		return types.Address{}, ErrAuthorizationWrongChainID
+	}
+	// Limit nonce to 2^64-1 per EIP-2681.
+	if auth.Nonce+1 < auth.Nonce {
+		return types.Address{}, ErrAuthorizationNonceOverflow
+	}
+	if auth.V == nil || auth.R == nil || auth.S == nil || auth.V.Uint64() > 1 ||
+		!crypto.ValidateSignatureValues(byte(auth.V.Uint64()), auth.R, auth.S, true) {
+		return types.Address{}, ErrAuthorizationInvalidSignature
+	}
+	authority, err := auth.RecoverSigner()
+	if err != nil {
+		return types.Address{}, fmt.Errorf("%w: %v", ErrAuthorizationInvalidSignature, err)
+	}
+	// The authority must be an EOA or already delegated.
+	st.state.AddAddressToAccessList(authority)
+	if code := st.state.GetCode(authority); len(code) != 0 {
+		if _, ok := transaction.ParseDelegation(code); !ok {
+			return authority, ErrAuthorizationDestinationHasCode
+		}
+	}
+	if have := st.state.GetNonce(authority); have != auth.Nonce {
+		return authority, ErrAuthorizationNonceMismatch
+	}
+	return authority, nil
+}
+
+// applyAuthorization delegates the code of the signer of auth to its address,
+// or removes the delegation if the address is zero.
+func (st *StateTransition) applyAuthorization(auth *transaction.Authorization) error {
+	authority, err := st.validateAuthorization(auth)
+	if err != nil {
+		return err
+	}
+	// Refund the empty account cost which was charged up front if the
+	// authority already exists.
+	if st.state.Exist(authority) {
+		st.state.AddRefund(params.PerEmptyAccountCost - params.PerAuthBaseCost)
+	}
+	st.state.SetNonce(authority, auth.Nonce+1)
+	if auth.Address == (types.Address{}) {
+		st.state.SetCode(authority, nil)
+		return nil
+	}
+	st.state.SetCode(authority, transaction.AddressToDelegation(auth.Address))
+	return nil
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the evm execution result with following fields.
 //
@@ -381,7 +502,7 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	//}
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(st.data, st.msg.AccessList(), contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	gas, err := IntrinsicGas(st.data, st.msg.AccessList(), st.msg.AuthList(), contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
 	if err != nil {
 		return nil, err
 	}
@@ -390,6 +511,13 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	}
 	st.gas -= gas
 
+	// Check whether the init code size has been exceeded.
+	if rules.IsShanghai && rules.IsLimits && contractCreation {
+		if limit := st.evm.ChainConfig().InitCodeSizeLimit(); uint64(len(st.data)) > limit {
+			return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(st.data), limit)
+		}
+	}
+
 	var bailout bool
 	// Gas bailout (for trace_call) should only be applied if there is not sufficient balance to perform value transfer
 	if gasBailout {
@@ -420,6 +548,19 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+
+		// Apply EIP-7702 authorizations. Invalid ones are skipped.
+		if authList := msg.AuthList(); authList != nil {
+			for _, auth := range authList {
+				st.applyAuthorization(auth)
+			}
+		}
+		// Delegated code of the callee is warm (EIP-7702).
+		if rules.IsPectra {
+			if target, ok := transaction.ParseDelegation(st.state.GetCode(st.to())); ok {
+				st.state.AddAddressToAccessList(target)
+			}
+		}
 		ret, st.gas, vmerr = st.evm.Call(sender, st.to(), st.data, st.gas, st.value, bailout)
 	}
 	if refunds {
@@ -18,7 +18,7 @@ var responseCodeInvalidRequest = byte(0x01)
 var responseCodeServerError = byte(0x02)
 
 func (s *Service) generateErrorResponse(code byte, reason string) ([]byte, error) {
-	return createErrorResponse(code, reason, s.cfg.p2p)
+	return createErrorResponse(code, reason, s.cfg.p2p.Encoding())
 }
 
 // ReadStatusCode response from a RPC stream.
@@ -48,8 +48,9 @@ func ReadStatusCode(stream network.Stream, encoding encoder.NetworkEncoding) (ui
 	return b[0], string(*msg), nil
 }
 
-func writeErrorResponseToStream(responseCode byte, reason string, stream libp2pcore.Stream, encoder p2p.EncodingProvider) {
-	resp, err := createErrorResponse(responseCode, reason, encoder)
+// writeErrorResponseToStream writes the error using the encoding negotiated for the stream.
+func writeErrorResponseToStream(responseCode byte, reason string, stream libp2pcore.Stream) {
+	resp, err := createErrorResponse(responseCode, reason, p2p.StreamEncoding(stream))
 	if err != nil {
 		log.Debug("Could not generate a response error", "err", err)
 	} else if _, err := stream.Write(resp); err != nil {
@@ -60,10 +61,10 @@ func writeErrorResponseToStream(responseCode byte, reason string, stream libp2pc
 	}
 }
 
-func createErrorResponse(code byte, reason string, encoder p2p.EncodingProvider) ([]byte, error) {
+func createErrorResponse(code byte, reason string, encoding encoder.NetworkEncoding) ([]byte, error) {
 	buf := bytes.NewBuffer([]byte{code})
 	errMsg := p2ptypes.ErrorMessage(reason)
-	if _, err := encoder.Encoding().EncodeWithMaxLength(buf, &errMsg); err != nil {
+	if _, err := encoding.EncodeWithMaxLength(buf, &errMsg); err != nil {
 		return nil, err
 	}
 
@@ -330,7 +330,12 @@ func (f *blocksFetcher) requestBlocks(ctx context.Context, req *sync_pb.BodiesBy
 	}
 	f.rateLimiter.Add(pid.String(), int64(req.Count))
 	l.Unlock()
-	return n42sync.SendBodiesByRangeRequest(ctx, f.chain, f.p2p, pid, req, nil)
+	start := time.Now()
+	blocks, err := n42sync.SendBodiesByRangeRequest(ctx, f.chain, f.p2p, pid, req, nil)
+	if err == nil {
+		common.SyncBodiesMeter.Observe(len(blocks), time.Since(start))
+	}
+	return blocks, err
 }
 
 // waitForBandwidth blocks up until peer's bandwidth is restored.
@@ -32,6 +32,9 @@ func (s *Service) roundRobinSync(highestExpectedBlockNr *uint256.Int) error {
 
 	s.counter = ratecounter.NewRateCounter(counterSeconds * time.Second)
 	s.highestExpectedBlockNr = highestExpectedBlockNr.Clone()
+	s.target.Store(highestExpectedBlockNr.Uint64())
+	s.startRound()
+	defer s.endRound()
 	// Step 1 - Sync to end of finalized BlockNr.
 	if err := s.syncToFinalizedBlockNr(ctx, highestExpectedBlockNr); err != nil {
 		return err
@@ -47,6 +50,16 @@ func (s *Service) syncToFinalizedBlockNr(ctx context.Context, highestExpectedBlo
 		log.Debug("Already synced to finalized block number")
 		return nil
 	}
+
+	// Fill a header skeleton from all peers at once, falling back to plain
+	// range requests against peers that do not serve headers.
+	err := s.skeletonSync(ctx, highestExpectedBlockNr.Uint64())
+	if err == nil || ctx.Err() != nil {
+		log.Info("Synced to finalized block number", "syncedBlockNr", s.cfg.Chain.CurrentBlock().Number64().Uint64(), "highestExpectedBlockNr", highestExpectedBlockNr.Uint64())
+		return err
+	}
+	log.Warn("Skeleton sync failed, falling back to range sync", "err", err)
+
 	queue := newBlocksQueue(ctx, &blocksQueueConfig{
 		p2p:                    s.cfg.P2P,
 		chain:                  s.cfg.Chain,
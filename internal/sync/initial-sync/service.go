@@ -10,8 +10,8 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/internal/p2p"
-	event "github.com/n42blockchain/N42/modules/event/v2"
 	"github.com/paulbellamy/ratecounter"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -33,11 +33,17 @@ type Service struct {
 	syncing                atomic.Bool
 	counter                *ratecounter.RateCounter
 	highestExpectedBlockNr *uint256.Int
+	target                 atomic.Uint64
 	// Log throttling
 	lastLogTime            time.Time
 	lastLogBlock           uint64
 	syncStartTime          time.Time
 	syncStartBlock         uint64
+	// Progress of the running sync round
+	roundMu     sync.Mutex
+	roundActive bool
+	roundStart  uint64
+	roundStages common.SyncStages // stage totals when the round started
 }
 
 // NewService configures the initial sync service responsible for bringing the node up to the
@@ -57,8 +63,8 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 // Start the initial sync service.
 func (s *Service) Start() {
 
-	event.GlobalEvent.Send(common.DownloaderStartEvent{})
-	defer event.GlobalEvent.Send(common.DownloaderFinishEvent{})
+	common.DownloaderStartTopic.Send(common.DownloaderStartEvent{})
+	defer common.DownloaderFinishTopic.Send(common.DownloaderFinishEvent{})
 
 	log.Info("Starting initial chain sync...")
 	highestExpectedBlockNr := s.waitForMinimumPeers()
@@ -92,6 +98,42 @@ func (s *Service) Syncing() bool {
 	return s.syncing.Load()
 }
 
+// Target returns the block number the latest sync round was heading to.
+func (s *Service) Target() uint64 {
+	return s.target.Load()
+}
+
+// Progress returns the progress of the running sync round and false when no
+// round is running. Stages only cover the time spent since the round started.
+func (s *Service) Progress() (common.SyncProgress, bool) {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+	if !s.roundActive {
+		return common.SyncProgress{}, false
+	}
+	return common.SyncProgress{
+		StartingBlock: s.roundStart,
+		CurrentBlock:  s.cfg.Chain.CurrentBlock().Number64().Uint64(),
+		HighestBlock:  s.target.Load(),
+		Stages:        common.ReadSyncStages().Sub(s.roundStages),
+	}, true
+}
+
+// startRound records the head and stage totals a sync round starts from.
+func (s *Service) startRound() {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+	s.roundActive = true
+	s.roundStart = s.cfg.Chain.CurrentBlock().Number64().Uint64()
+	s.roundStages = common.ReadSyncStages()
+}
+
+func (s *Service) endRound() {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+	s.roundActive = false
+}
+
 // Synced returns true if initial sync has been completed.
 func (s *Service) Synced() bool {
 	return s.synced.Load()
@@ -102,10 +144,10 @@ func (s *Service) Synced() bool {
 func (s *Service) Resync() error {
 	// Set it to false since we are syncing again.
 	s.markSyncing()
-	event.GlobalEvent.Send(common.DownloaderStartEvent{})
+	common.DownloaderStartTopic.Send(common.DownloaderStartEvent{})
 	defer func() {
 		s.markSynced()
-		event.GlobalEvent.Send(common.DownloaderFinishEvent{})
+		common.DownloaderFinishTopic.Send(common.DownloaderFinishEvent{})
 	}() // Reset it at the end of the method.
 	//
 	beforeBlockNr := s.cfg.Chain.CurrentBlock().Number64()
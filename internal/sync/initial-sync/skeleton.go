@@ -0,0 +1,322 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package initialsync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/n42blockchain/N42/api/protocol/sync_pb"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/p2p"
+	n42sync "github.com/n42blockchain/N42/internal/sync"
+	"github.com/n42blockchain/N42/utils"
+)
+
+const (
+	// skeletonSegments is how many segments one header skeleton spans. Each
+	// segment is a block batch filled from a single peer.
+	skeletonSegments = 64
+	// skeletonRounds is how many skeleton rounds in a row may fail to move the
+	// head before the downloader gives up.
+	skeletonRounds = 3
+)
+
+var (
+	errNoSkeleton         = errors.New("no peer served a header skeleton")
+	errSegmentWithheld    = errors.New("peer withheld blocks of a skeleton segment")
+	errSegmentMismatch    = errors.New("segment does not link to the skeleton")
+	errSkeletonNoProgress = errors.New("skeleton sync made no progress")
+)
+
+// segment is the run of blocks ending at a skeleton header.
+type segment struct {
+	start, count uint64
+	parent       types.Hash // hash of the block before start, zero if not checked
+	anchor       types.Hash // hash of the last block from the skeleton, zero for the tail
+	skeleton     peer.ID    // peer that served the anchor
+
+	raw    []*types_pb.Block
+	blocks []block.IBlock
+	pid    peer.ID // peer that filled the segment
+	err    error
+	done   chan struct{}
+}
+
+// verify checks that raw holds every block of the segment, linked to each
+// other and to the skeleton, and keeps them.
+func (s *segment) verify(raw []*types_pb.Block) error {
+	if uint64(len(raw)) < s.count {
+		return errSegmentWithheld
+	}
+	blocks := make([]block.IBlock, len(raw))
+	parent := s.parent
+	for i, pb := range raw {
+		blk := new(block.Block)
+		if err := blk.FromProtoMessage(pb); err != nil {
+			return err
+		}
+		if blk.Number64().Uint64() != s.start+uint64(i) {
+			return errSegmentWithheld
+		}
+		if (i > 0 || parent != (types.Hash{})) && blk.ParentHash() != parent {
+			return errSegmentMismatch
+		}
+		parent = blk.Hash()
+		blocks[i] = blk
+	}
+	if s.anchor != (types.Hash{}) && parent != s.anchor {
+		return errSegmentMismatch
+	}
+	s.raw, s.blocks = raw, blocks
+	return nil
+}
+
+// skeletonDownloader syncs by fetching a sparse header skeleton from one peer
+// and filling the segments between its headers from all peers in parallel,
+// striping the segments across them. Every segment must link up with the
+// skeleton, so a peer cannot withhold or swap blocks unnoticed. Segments are
+// inserted in order as soon as they are filled.
+type skeletonDownloader struct {
+	chain   common.IBlockChain
+	p2p     p2p.P2P
+	fetcher *blocksFetcher // peer ordering and per-peer rate limits
+	size    uint64         // blocks per segment
+	insert  func(*segment) error
+}
+
+// skeletonSync syncs the chain up to target with a skeletonDownloader using
+// the block batch limit as segment size.
+func (s *Service) skeletonSync(ctx context.Context, target uint64) error {
+	fetcher := newBlocksFetcher(ctx, &blocksFetcherConfig{
+		chain:                    s.cfg.Chain,
+		p2p:                      s.cfg.P2P,
+		peerFilterCapacityWeight: peerFilterCapacityWeight,
+		mode:                     modeStopOnFinalizedEpoch,
+	})
+	if err := fetcher.start(); err != nil {
+		return err
+	}
+	defer fetcher.stop()
+
+	d := &skeletonDownloader{
+		chain:   s.cfg.Chain,
+		p2p:     s.cfg.P2P,
+		fetcher: fetcher,
+		size:    uint64(max(s.cfg.P2P.GetConfig().P2PLimit.BlockBatchLimit, 1)),
+		insert:  s.insertSegment,
+	}
+	return d.run(ctx, target)
+}
+
+// insertSegment inserts the blocks of a filled segment the chain does not
+// have yet.
+func (s *Service) insertSegment(seg *segment) error {
+	head := s.cfg.Chain.CurrentBlock().Number64()
+	defer s.updatePeerScorerStats(seg.pid, head)
+
+	blocks, raw := seg.blocks, seg.raw
+	for len(blocks) > 0 && blocks[0].Number64().Uint64() <= head.Uint64() {
+		blocks, raw = blocks[1:], raw[1:]
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	s.logBatchSyncStatus(raw)
+	_, err := s.cfg.Chain.InsertChain(blocks)
+	return err
+}
+
+func (d *skeletonDownloader) run(ctx context.Context, target uint64) error {
+	stalled := 0
+	for {
+		head := d.chain.CurrentBlock().Number64().Uint64()
+		if head >= target {
+			return nil
+		}
+		_, peers := d.p2p.Peers().BestPeers(d.p2p.GetConfig().MinSyncPeers, d.chain.CurrentBlock().Number64())
+		peers = d.fetcher.filterPeers(ctx, peers, 1)
+		if len(peers) == 0 {
+			return errNoPeersAvailable
+		}
+		segments, err := d.skeleton(ctx, head, target, peers)
+		if err != nil {
+			return err
+		}
+		err = d.fill(ctx, segments, peers)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.chain.CurrentBlock().Number64().Uint64() > head {
+			stalled = 0
+			continue
+		}
+		if err == nil {
+			err = errSkeletonNoProgress
+		}
+		log.Warn("Skeleton round failed", "head", head, "target", target, "err", err)
+		if stalled++; stalled >= skeletonRounds {
+			return err
+		}
+	}
+}
+
+// skeleton requests the headers ending each segment after head from the
+// first peer serving them and lays out the segments. A tail shorter than a
+// segment is only anchored to the block before it.
+func (d *skeletonDownloader) skeleton(ctx context.Context, head, target uint64, peers []peer.ID) ([]*segment, error) {
+	from := head + 1
+	var (
+		anchors []*types_pb.Header
+		source  peer.ID
+	)
+	if from+d.size-1 <= target {
+		req := &sync_pb.HeadersByRangeRequest{
+			StartBlockNumber: utils.ConvertUint256IntToH256(uint256.NewInt(from + d.size - 1)),
+			Count:            min((target-from+1)/d.size, skeletonSegments),
+			Step:             d.size,
+		}
+		for _, pid := range peers {
+			start := time.Now()
+			headers, err := n42sync.SendHeadersByRangeRequest(ctx, d.p2p, pid, req)
+			if err != nil {
+				log.Debug("Could not fetch header skeleton", "peer", pid, "err", err)
+				continue
+			}
+			common.SyncHeadersMeter.Observe(len(headers), time.Since(start))
+			if len(headers) > 0 {
+				anchors, source = headers, pid
+				break
+			}
+		}
+		if anchors == nil {
+			return nil, errNoSkeleton
+		}
+	}
+
+	segments := make([]*segment, 0, len(anchors)+1)
+	var parent types.Hash // the first segment may fork off below the head
+	for i, h := range anchors {
+		header := new(block.Header)
+		if err := header.FromProtoMessage(h); err != nil {
+			d.p2p.Peers().Scorers().BadResponsesScorer().Increment(source)
+			return nil, err
+		}
+		segments = append(segments, &segment{
+			start:    from + uint64(i)*d.size,
+			count:    d.size,
+			parent:   parent,
+			anchor:   header.Hash(),
+			skeleton: source,
+			done:     make(chan struct{}),
+		})
+		parent = header.Hash()
+	}
+	if next := from + uint64(len(anchors))*d.size; next <= target && next+d.size-1 > target {
+		segments = append(segments, &segment{
+			start:  next,
+			count:  target - next + 1,
+			parent: parent,
+			done:   make(chan struct{}),
+		})
+	}
+	return segments, nil
+}
+
+// fill fetches the segments from the peers in parallel and inserts them in
+// order. It returns at the first segment that cannot be filled or inserted.
+func (d *skeletonDownloader) fill(ctx context.Context, segments []*segment, peers []peer.ID) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Two requests per peer keep every peer busy while a response is decoded
+	slots := make(chan struct{}, 2*len(peers))
+	go func() {
+		for i, seg := range segments {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer func() { <-slots }()
+				d.fetchSegment(ctx, seg, i, peers)
+				close(seg.done)
+			}()
+		}
+	}()
+
+	for _, seg := range segments {
+		select {
+		case <-seg.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if seg.err != nil {
+			return seg.err
+		}
+		if err := d.insert(seg); err != nil {
+			d.p2p.Peers().Scorers().BadResponsesScorer().Increment(seg.pid)
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchSegment requests the i-th segment from the i-th peer, moving on to the
+// next peer whenever one fails or serves blocks the skeleton rejects.
+func (d *skeletonDownloader) fetchSegment(ctx context.Context, seg *segment, i int, peers []peer.ID) {
+	req := &sync_pb.BodiesByRangeRequest{
+		StartBlockNumber: utils.ConvertUint256IntToH256(uint256.NewInt(seg.start)),
+		Count:            seg.count,
+		Step:             1,
+	}
+	mismatches := 0
+	for n := range peers {
+		pid := peers[(i+n)%len(peers)]
+		raw, err := d.fetcher.requestBlocks(ctx, req, pid)
+		if err == nil {
+			err = seg.verify(raw)
+		}
+		if err == nil {
+			seg.pid, seg.err = pid, nil
+			d.p2p.Peers().Scorers().BlockProviderScorer().Touch(pid)
+			return
+		}
+		seg.err = err
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errSegmentWithheld) || errors.Is(err, errSegmentMismatch) || errors.Is(err, n42sync.ErrInvalidFetchedData) {
+			d.p2p.Peers().Scorers().BadResponsesScorer().Increment(pid)
+		}
+		if errors.Is(err, errSegmentMismatch) {
+			mismatches++
+		}
+		log.Debug("Rejected skeleton segment", "peer", pid, "start", seg.start, "count", seg.count, "err", err)
+	}
+	// Every peer disagreeing points at the skeleton itself
+	if mismatches == len(peers) && seg.skeleton != "" {
+		d.p2p.Peers().Scorers().BadResponsesScorer().Increment(seg.skeleton)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package initialsync
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// testSegmentChain returns n linked blocks from number 1 as sent over the wire.
+func testSegmentChain(n int, extra byte) ([]*types_pb.Block, []block.IBlock) {
+	var (
+		raw    []*types_pb.Block
+		blocks []block.IBlock
+		parent types.Hash
+	)
+	for i := 1; i <= n; i++ {
+		header := &block.Header{
+			Number:     uint256.NewInt(uint64(i)),
+			ParentHash: parent,
+			Difficulty: uint256.NewInt(1),
+			BaseFee:    uint256.NewInt(0),
+			Extra:      []byte{extra},
+		}
+		blk := block.NewBlock(header, nil)
+		raw = append(raw, blk.ToProtoMessage().(*types_pb.Block))
+		blocks = append(blocks, blk)
+		parent = blk.Hash()
+	}
+	return raw, blocks
+}
+
+func TestSegmentVerify(t *testing.T) {
+	raw, blocks := testSegmentChain(4, 0)
+	forged, _ := testSegmentChain(4, 1)
+	newSegment := func() *segment {
+		return &segment{start: 3, count: 2, parent: blocks[1].Hash(), anchor: blocks[3].Hash()}
+	}
+
+	seg := newSegment()
+	if err := seg.verify(raw[2:]); err != nil {
+		t.Fatalf("valid segment rejected: %v", err)
+	}
+	if len(seg.blocks) != 2 || seg.blocks[1].Hash() != blocks[3].Hash() {
+		t.Fatal("verified blocks not kept")
+	}
+
+	tests := []struct {
+		name string
+		raw  []*types_pb.Block
+		want error
+	}{
+		{"short", raw[2:3], errSegmentWithheld},
+		{"gap", []*types_pb.Block{raw[2], raw[2]}, errSegmentWithheld},
+		{"forged", forged[2:], errSegmentMismatch},
+		{"off skeleton", []*types_pb.Block{raw[2], forged[3]}, errSegmentMismatch},
+	}
+	for _, tt := range tests {
+		seg := newSegment()
+		if err := seg.verify(tt.raw); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err %v, want %v", tt.name, err, tt.want)
+		}
+		if seg.blocks != nil {
+			t.Errorf("%s: rejected blocks kept", tt.name)
+		}
+	}
+
+	// The tail is only linked to the block before it
+	tail := &segment{start: 3, count: 2, parent: blocks[1].Hash()}
+	if err := tail.verify(raw[2:]); err != nil {
+		t.Fatalf("valid tail rejected: %v", err)
+	}
+}
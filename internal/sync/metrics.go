@@ -57,6 +57,30 @@ var (
 			Help: "Count the number of times a node resyncs.",
 		},
 	)
+	chainHeadAgeGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "chain_head_age_seconds",
+			Help: "Seconds since the local chain head last advanced.",
+		},
+	)
+	chainHeadStalledGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "chain_head_stalled",
+			Help: "1 while the chain head is stalled and peers report higher blocks.",
+		},
+	)
+	chainHeadStallsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "chain_head_stalls_total",
+			Help: "Count the number of chain head stalls detected by the watchdog.",
+		},
+	)
+	chainHeadStallResyncsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "chain_head_stall_resyncs_total",
+			Help: "Count the number of resyncs triggered by the watchdog.",
+		},
+	)
 	duplicatesRemovedCounter = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "number_of_duplicates_removed",
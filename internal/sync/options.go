@@ -2,6 +2,7 @@ package sync
 
 import (
 	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/conf"
 	"github.com/n42blockchain/N42/internal/p2p"
 )
 
@@ -27,3 +28,10 @@ func WithInitialSync(initialSync Checker) Option {
 		return nil
 	}
 }
+
+func WithWatchdog(cfg conf.WatchdogConfig) Option {
+	return func(s *Service) error {
+		s.cfg.watchdog = cfg
+		return nil
+	}
+}
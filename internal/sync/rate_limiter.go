@@ -35,6 +35,13 @@ func newRateLimiter(p2pProvider p2p.P2P) *limiter {
 	addEncoding := func(topic string) string {
 		return topic + p2pProvider.Encoding().ProtocolSuffix()
 	}
+	// share one collector across every encoding negotiated for a topic, so a peer
+	// cannot double its budget by alternating compression schemes.
+	setCollector := func(topicMap map[string]*leakybucket.Collector, topic string, collector *leakybucket.Collector) {
+		for _, encoding := range p2pProvider.RPCEncodings(topic) {
+			topicMap[topic+encoding.ProtocolSuffix()] = collector
+		}
+	}
 
 	// Initialize block limits.
 	allowedBlocksPerSecond := float64(p2pProvider.GetConfig().P2PLimit.BlockBatchLimit)
@@ -52,10 +59,10 @@ func newRateLimiter(p2pProvider p2p.P2P) *limiter {
 	topicMap[addEncoding(p2p.RPCStatusTopicV1)] = leakybucket.NewCollector(1, defaultBurstLimit, leakyBucketPeriod, false /* deleteEmptyBuckets */)
 
 	// Bodies Message
-	topicMap[addEncoding(p2p.RPCBodiesDataTopicV1)] = leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockLimiterPeriod, false /* deleteEmptyBuckets */)
+	setCollector(topicMap, p2p.RPCBodiesDataTopicV1, leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockLimiterPeriod, false /* deleteEmptyBuckets */))
 
 	// Headers Message
-	topicMap[addEncoding(p2p.RPCHeadersDataTopicV1)] = leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockLimiterPeriod, false /* deleteEmptyBuckets */)
+	setCollector(topicMap, p2p.RPCHeadersDataTopicV1, leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, blockLimiterPeriod, false /* deleteEmptyBuckets */))
 
 	// General topic for all rpc requests.
 	topicMap[rpcLimiterTopic] = leakybucket.NewCollector(5, defaultBurstLimit*2, leakyBucketPeriod, false /* deleteEmptyBuckets */)
@@ -96,7 +103,7 @@ func (l *limiter) validateRequest(stream network.Stream, amt uint64) error {
 		)
 
 		l.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
-		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream, l.p2p)
+		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream)
 		return p2ptypes.ErrRateLimited
 	}
 	return nil
@@ -119,7 +126,7 @@ func (l *limiter) validateRawRpcRequest(stream network.Stream) error {
 	amt := int64(1)
 	if amt > remaining {
 		l.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
-		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream, l.p2p)
+		writeErrorResponseToStream(responseCodeInvalidRequest, p2ptypes.ErrRateLimited.Error(), stream)
 		return p2ptypes.ErrRateLimited
 	}
 	return nil
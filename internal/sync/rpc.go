@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"github.com/n42blockchain/N42/internal/p2p"
+	"github.com/n42blockchain/N42/internal/p2p/encoder"
 	p2ptypes "github.com/n42blockchain/N42/internal/p2p/types"
 	"github.com/n42blockchain/N42/log"
 	"reflect"
@@ -47,24 +48,37 @@ func (s *Service) registerRPCHandlers() {
 		p2p.RPCBodiesDataTopicV1,
 		s.bodiesByRangeRPCHandler,
 	)
+	s.registerRPC(
+		p2p.RPCHeadersDataTopicV1,
+		s.headersByRangeRPCHandler,
+	)
 }
 
 // Remove all Stream handlers
 func (s *Service) unregisterHandlers() {
-	fullBodiesRangeTopic := p2p.RPCBodiesDataTopicV1 + s.cfg.p2p.Encoding().ProtocolSuffix()
-	fullStatusTopic := p2p.RPCStatusTopicV1 + s.cfg.p2p.Encoding().ProtocolSuffix()
-	fullGoodByeTopic := p2p.RPCGoodByeTopicV1 + s.cfg.p2p.Encoding().ProtocolSuffix()
-	fullPingTopic := p2p.RPCPingTopicV1 + s.cfg.p2p.Encoding().ProtocolSuffix()
-
-	s.cfg.p2p.Host().RemoveStreamHandler(protocol.ID(fullBodiesRangeTopic))
-	s.cfg.p2p.Host().RemoveStreamHandler(protocol.ID(fullStatusTopic))
-	s.cfg.p2p.Host().RemoveStreamHandler(protocol.ID(fullGoodByeTopic))
-	s.cfg.p2p.Host().RemoveStreamHandler(protocol.ID(fullPingTopic))
+	for _, baseTopic := range []string{
+		p2p.RPCBodiesDataTopicV1,
+		p2p.RPCHeadersDataTopicV1,
+		p2p.RPCStatusTopicV1,
+		p2p.RPCGoodByeTopicV1,
+		p2p.RPCPingTopicV1,
+	} {
+		for _, encoding := range s.cfg.p2p.RPCEncodings(baseTopic) {
+			s.cfg.p2p.Host().RemoveStreamHandler(protocol.ID(baseTopic + encoding.ProtocolSuffix()))
+		}
+	}
 }
 
-// registerRPC for a given topic with an expected protobuf message type.
+// registerRPC for a given topic with an expected protobuf message type. A handler is
+// registered for every encoding supported on the topic so peers can negotiate compression.
 func (s *Service) registerRPC(baseTopic string, handle rpcHandler) {
-	topic := baseTopic + s.cfg.p2p.Encoding().ProtocolSuffix()
+	for _, encoding := range s.cfg.p2p.RPCEncodings(baseTopic) {
+		s.registerRPCWithEncoding(baseTopic, encoding, handle)
+	}
+}
+
+func (s *Service) registerRPCWithEncoding(baseTopic string, encoding encoder.NetworkEncoding, handle rpcHandler) {
+	topic := baseTopic + encoding.ProtocolSuffix()
 	s.cfg.p2p.SetStreamHandler(topic, func(stream network.Stream) {
 		defer func() {
 			if r := recover(); r != nil {
@@ -131,7 +145,7 @@ func (s *Service) registerRPC(baseTopic string, handle rpcHandler) {
 				log.Errorf("message of %T does not support marshaller interface", msg)
 				return
 			}
-			if err := s.cfg.p2p.Encoding().DecodeWithMaxLength(stream, msg); err != nil {
+			if err := encoding.DecodeWithMaxLength(stream, msg); err != nil {
 				log.Debug("Could not decode stream message", "topic", topic, "err", err)
 				//tracing.AnnotateError(span, err)
 				s.cfg.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
@@ -151,7 +165,7 @@ func (s *Service) registerRPC(baseTopic string, handle rpcHandler) {
 				log.Errorf("message of %T does not support marshaller interface", msg)
 				return
 			}
-			if err := s.cfg.p2p.Encoding().DecodeWithMaxLength(stream, msg); err != nil {
+			if err := encoding.DecodeWithMaxLength(stream, msg); err != nil {
 				log.Debug("Could not decode stream message", "topic", topic, "err", err)
 				//tracing.AnnotateError(span, err)
 				s.cfg.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
@@ -17,7 +17,7 @@ import (
 // response_chunk  ::= <result> | <context-bytes> | <encoding-dependent-header> | <encoded-payload>
 func (s *Service) chunkBlockWriter(stream libp2pcore.Stream, blk types.IBlock) error {
 	SetStreamWriteDeadline(stream, defaultWriteDuration)
-	return WriteBlockChunk(stream, s.cfg.chain, s.cfg.p2p.Encoding(), blk)
+	return WriteBlockChunk(stream, s.cfg.chain, p2p.StreamEncoding(stream), blk)
 }
 
 // WriteBlockChunk writes block chunk object to stream.
@@ -40,21 +40,77 @@ func WriteBlockChunk(stream libp2pcore.Stream, chain common.IBlockChain, encodin
 	return err
 }
 
+// chunkHeaderWriter writes the given header as a chunked response to the given
+// network stream.
+func (s *Service) chunkHeaderWriter(stream libp2pcore.Stream, header types.IHeader) error {
+	SetStreamWriteDeadline(stream, defaultWriteDuration)
+	return WriteHeaderChunk(stream, s.cfg.chain, p2p.StreamEncoding(stream), header)
+}
+
+// WriteHeaderChunk writes header chunk object to stream.
+func WriteHeaderChunk(stream libp2pcore.Stream, chain common.IBlockChain, encoding encoder.NetworkEncoding, header types.IHeader) error {
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		return err
+	}
+
+	digest, err := utils.CreateForkDigest(header.Number64(), chain.GenesisBlock().Hash())
+	if err != nil {
+		return err
+	}
+
+	if err = writeContextToStream(digest[:], stream, chain); err != nil {
+		return err
+	}
+	_, err = encoding.EncodeWithMaxLength(stream, header.ToProtoMessage().(*types_pb.Header))
+	return err
+}
+
+// ReadChunkedHeader handles each response chunk of a headers by range request.
+func ReadChunkedHeader(stream libp2pcore.Stream, isFirstChunk bool) (*types_pb.Header, error) {
+	encoding := p2p.StreamEncoding(stream)
+	if isFirstChunk {
+		code, errMsg, err := ReadStatusCode(stream, encoding)
+		if err != nil {
+			return nil, err
+		}
+		if code != 0 {
+			return nil, fmt.Errorf("%s", errMsg)
+		}
+	} else {
+		SetStreamReadDeadline(stream, respTimeout)
+		code, errMsg, err := readStatusCodeNoDeadline(stream, encoding)
+		if err != nil {
+			return nil, err
+		}
+		if code != 0 {
+			return nil, errors.New(errMsg)
+		}
+	}
+	if _, err := readContextFromStream(stream); err != nil {
+		return nil, err
+	}
+	header := &types_pb.Header{}
+	err := encoding.DecodeWithMaxLength(stream, header)
+	return header, err
+}
+
 // ReadChunkedBlock handles each response chunk that is sent by the
-// peer and converts it into a beacon block.
-func ReadChunkedBlock(stream libp2pcore.Stream, p2p p2p.EncodingProvider, isFirstChunk bool) (*types_pb.Block, error) {
+// peer and converts it into a beacon block. Chunks are decoded with the
+// encoding negotiated for the stream.
+func ReadChunkedBlock(stream libp2pcore.Stream, isFirstChunk bool) (*types_pb.Block, error) {
+	encoding := p2p.StreamEncoding(stream)
 	// Handle deadlines differently for first chunk
 	if isFirstChunk {
-		return readFirstChunkedBlock(stream, p2p)
+		return readFirstChunkedBlock(stream, encoding)
 	}
 
-	return readResponseChunk(stream, p2p)
+	return readResponseChunk(stream, encoding)
 }
 
 // readFirstChunkedBlock reads the first chunked block and applies the appropriate deadlines to
 // it.
-func readFirstChunkedBlock(stream libp2pcore.Stream, p2p p2p.EncodingProvider) (*types_pb.Block, error) {
-	code, errMsg, err := ReadStatusCode(stream, p2p.Encoding())
+func readFirstChunkedBlock(stream libp2pcore.Stream, encoding encoder.NetworkEncoding) (*types_pb.Block, error) {
+	code, errMsg, err := ReadStatusCode(stream, encoding)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +122,15 @@ func readFirstChunkedBlock(stream libp2pcore.Stream, p2p p2p.EncodingProvider) (
 		return nil, err
 	}
 	blk := &types_pb.Block{}
-	err = p2p.Encoding().DecodeWithMaxLength(stream, blk)
+	err = encoding.DecodeWithMaxLength(stream, blk)
 	return blk, err
 }
 
 // readResponseChunk reads the response from the stream and decodes it into the
 // provided message type.
-func readResponseChunk(stream libp2pcore.Stream, p2p p2p.EncodingProvider) (*types_pb.Block, error) {
+func readResponseChunk(stream libp2pcore.Stream, encoding encoder.NetworkEncoding) (*types_pb.Block, error) {
 	SetStreamReadDeadline(stream, respTimeout)
-	code, errMsg, err := readStatusCodeNoDeadline(stream, p2p.Encoding())
+	code, errMsg, err := readStatusCodeNoDeadline(stream, encoding)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +144,6 @@ func readResponseChunk(stream libp2pcore.Stream, p2p p2p.EncodingProvider) (*typ
 	}
 
 	blk := &types_pb.Block{}
-	err = p2p.Encoding().DecodeWithMaxLength(stream, blk)
+	err = encoding.DecodeWithMaxLength(stream, blk)
 	return blk, err
 }
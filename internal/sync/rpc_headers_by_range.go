@@ -0,0 +1,94 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"context"
+
+	"github.com/holiman/uint256"
+	libp2pcore "github.com/libp2p/go-libp2p/core"
+	"github.com/n42blockchain/N42/api/protocol/sync_pb"
+	p2ptypes "github.com/n42blockchain/N42/internal/p2p/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/utils"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// headersByRangeRPCHandler serves canonical headers from a start block at a
+// fixed step, so a syncing peer can fetch a sparse skeleton of the chain and
+// fill the gaps from other peers. The response stops early at the local head.
+func (s *Service) headersByRangeRPCHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	ctx, span := trace.StartSpan(ctx, "sync.HeadersByRangeHandler")
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, respTimeout)
+	defer cancel()
+	SetRPCStreamDeadlines(stream)
+
+	m, ok := msg.(*sync_pb.HeadersByRangeRequest)
+	if !ok {
+		return errors.New("message is not type *sync_pb.HeadersByRangeRequest")
+	}
+	if err := s.validateHeadersRequest(m); err != nil {
+		s.writeErrorResponseToStream(responseCodeInvalidRequest, err.Error(), stream)
+		s.cfg.p2p.Peers().Scorers().BadResponsesScorer().Increment(stream.Conn().RemotePeer())
+		return err
+	}
+	if err := s.rateLimiter.validateRequest(stream, m.Count); err != nil {
+		return err
+	}
+	s.rateLimiter.add(stream, int64(m.Count))
+
+	start := utils.ConvertH256ToUint256Int(m.StartBlockNumber).Uint64()
+	span.AddAttributes(
+		trace.Int64Attribute("start", int64(start)), // lint:ignore uintcast -- This conversion is OK for tracing.
+		trace.Int64Attribute("step", int64(m.Step)),
+		trace.Int64Attribute("count", int64(m.Count)),
+		trace.StringAttribute("peer", stream.Conn().RemotePeer().String()),
+	)
+	for i := uint64(0); i < m.Count; i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		header := s.cfg.chain.GetHeaderByNumber(uint256.NewInt(start + i*m.Step))
+		if header == nil {
+			break
+		}
+		if err := s.chunkHeaderWriter(stream, header); err != nil {
+			log.Debug("Could not send a chunked response", "err", err)
+			s.writeErrorResponseToStream(responseCodeServerError, p2ptypes.ErrGeneric.Error(), stream)
+			return err
+		}
+	}
+	closeStream(stream)
+	return nil
+}
+
+func (s *Service) validateHeadersRequest(r *sync_pb.HeadersByRangeRequest) error {
+	if r.StartBlockNumber == nil || r.Count == 0 || r.Count > maxRequestBlocks {
+		return p2ptypes.ErrInvalidRequest
+	}
+	if r.Step == 0 || r.Step > rangeLimit {
+		return p2ptypes.ErrInvalidRequest
+	}
+	start := utils.ConvertH256ToUint256Int(r.StartBlockNumber)
+	highest := new(uint256.Int).AddUint64(s.cfg.chain.CurrentBlock().Number64(), rangeLimit*2)
+	if start.Cmp(highest) > 0 {
+		return p2ptypes.ErrInvalidRequest
+	}
+	return nil
+}
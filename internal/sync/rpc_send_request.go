@@ -52,7 +52,7 @@ func SendBodiesByRangeRequest(ctx context.Context, chain common.IBlockChain, p2p
 	blockStart := utils.ConvertH256ToUint256Int(req.StartBlockNumber)
 	for i := uint64(0); ; i++ {
 		isFirstChunk := i == 0
-		blk, err := ReadChunkedBlock(stream, p2pProvider, isFirstChunk)
+		blk, err := ReadChunkedBlock(stream, isFirstChunk)
 		if errors.Is(err, io.EOF) {
 			break
 		}
@@ -88,3 +88,38 @@ func SendBodiesByRangeRequest(ctx context.Context, chain common.IBlockChain, p2p
 
 	return blocks, nil
 }
+
+// SendHeadersByRangeRequest sends HeadersByRange and returns the fetched
+// headers. Peers answer with fewer headers past their head, but every header
+// returned must sit exactly at its step.
+func SendHeadersByRangeRequest(ctx context.Context, p2pProvider p2p.SenderEncoder, pid peer.ID, req *sync_pb.HeadersByRangeRequest) ([]*types_pb.Header, error) {
+	topic, err := p2p.TopicFromMessage(p2p.HeadersByRangeMessageName)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := p2pProvider.Send(ctx, req, topic, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStream(stream)
+
+	start := utils.ConvertH256ToUint256Int(req.StartBlockNumber).Uint64()
+	headers := make([]*types_pb.Header, 0, req.Count)
+	for i := uint64(0); ; i++ {
+		header, err := ReadChunkedHeader(stream, i == 0)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i >= req.Count || i >= maxRequestBlocks || header.Number == nil {
+			return nil, ErrInvalidFetchedData
+		}
+		if utils.ConvertH256ToUint256Int(header.Number).Uint64() != start+i*req.Step {
+			return nil, ErrInvalidFetchedData
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
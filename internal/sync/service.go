@@ -10,6 +10,7 @@ import (
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
 	"github.com/n42blockchain/N42/internal/p2p"
 	"github.com/n42blockchain/N42/utils"
 	"sync"
@@ -54,6 +55,7 @@ type config struct {
 	p2p         p2p.P2P
 	chain       common.IBlockChain
 	initialSync Checker
+	watchdog    conf.WatchdogConfig
 }
 
 // This defines the interface for interacting with block chain service
@@ -128,6 +130,7 @@ func (s *Service) Start() {
 	s.cfg.p2p.AddPingMethod(s.sendPingRequest)
 	s.maintainPeerStatuses()
 	s.resyncIfBehind()
+	s.startWatchdog()
 
 	// Update sync metrics.
 	utils.RunEvery(s.ctx, syncMetricsInterval, s.updateMetrics)
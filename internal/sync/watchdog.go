@@ -0,0 +1,171 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/utils"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// headWatchdog tracks how long the local head has been unchanged and raises
+// an alert when it stays stuck while peers report higher blocks.
+type headWatchdog struct {
+	cfg conf.WatchdogConfig
+
+	head     func() (uint64, types.Hash)
+	peerHead func() uint64
+	resync   func() error // nil when resync is unavailable
+	notify   func(watchdogEvent)
+
+	number     uint64
+	lastChange time.Time
+	stalled    bool
+	lastResync time.Time
+}
+
+// watchdogEvent is the webhook payload.
+type watchdogEvent struct {
+	Event       string     `json:"event"`
+	Number      uint64     `json:"number"`
+	Hash        types.Hash `json:"hash"`
+	PeerHead    uint64     `json:"peer_head"`
+	StalledFor  string     `json:"stalled_for"`
+	LastAdvance time.Time  `json:"last_advance"`
+}
+
+// startWatchdog runs the head watchdog until the service stops.
+func (s *Service) startWatchdog() {
+	cfg := s.cfg.watchdog
+	if cfg.StallTimeout <= 0 {
+		return
+	}
+	w := &headWatchdog{
+		cfg: cfg,
+		head: func() (uint64, types.Hash) {
+			current := s.cfg.chain.CurrentBlock()
+			return current.Number64().Uint64(), current.Hash()
+		},
+		peerHead: func() uint64 {
+			return s.cfg.p2p.Peers().HighestBlockNumber().Uint64()
+		},
+		notify: func(ev watchdogEvent) {
+			if cfg.Webhook != "" {
+				go postWebhook(s.ctx, cfg.Webhook, ev)
+			}
+		},
+	}
+	if cfg.Resync && s.cfg.initialSync != nil {
+		w.resync = func() error {
+			if s.cfg.initialSync.Syncing() {
+				return nil
+			}
+			return s.cfg.initialSync.Resync()
+		}
+	}
+	log.Info("Chain head watchdog enabled", "stall", cfg.StallTimeout, "resync", w.resync != nil, "webhook", cfg.Webhook != "")
+	utils.RunEvery(s.ctx, min(syncMetricsInterval, cfg.StallTimeout/2+time.Second), func() {
+		w.check(time.Now())
+	})
+}
+
+func (w *headWatchdog) check(now time.Time) {
+	number, hash := w.head()
+	if w.lastChange.IsZero() || number != w.number {
+		if w.stalled {
+			log.Info("Chain head advancing again", "number", number, "hash", hash, "stalledFor", now.Sub(w.lastChange).Round(time.Second))
+			chainHeadStalledGauge.Set(0)
+			w.notify(w.event("head_recovered", number, hash, 0, now))
+		}
+		w.number, w.lastChange, w.stalled = number, now, false
+		chainHeadAgeGauge.Set(0)
+		return
+	}
+
+	age := now.Sub(w.lastChange)
+	chainHeadAgeGauge.Set(age.Seconds())
+	if age < w.cfg.StallTimeout {
+		return
+	}
+	// A quiet network is not a stall: only alert if someone is ahead of us.
+	peerHead := w.peerHead()
+	if peerHead <= number {
+		return
+	}
+
+	if !w.stalled {
+		w.stalled = true
+		chainHeadStalledGauge.Set(1)
+		chainHeadStallsCounter.Inc()
+		log.Warn("Chain head stalled while peers are ahead", "number", number, "hash", hash, "peerHead", peerHead, "stalledFor", age.Round(time.Second))
+		w.notify(w.event("head_stalled", number, hash, peerHead, now))
+	}
+	if w.resync != nil && now.Sub(w.lastResync) >= w.cfg.StallTimeout {
+		w.lastResync = now
+		chainHeadStallResyncsCounter.Inc()
+		log.Warn("Resyncing after chain head stall", "number", number, "peerHead", peerHead)
+		if err := w.resync(); err != nil {
+			log.Error("Resync after chain head stall failed", "err", err)
+		}
+	}
+}
+
+func (w *headWatchdog) event(name string, number uint64, hash types.Hash, peerHead uint64, now time.Time) watchdogEvent {
+	return watchdogEvent{
+		Event:       name,
+		Number:      number,
+		Hash:        hash,
+		PeerHead:    peerHead,
+		StalledFor:  now.Sub(w.lastChange).Round(time.Second).String(),
+		LastAdvance: w.lastChange,
+	}
+}
+
+func postWebhook(ctx context.Context, url string, ev watchdogEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		log.Warn("Invalid watchdog webhook", "url", url, "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			err = fmt.Errorf("status %s", resp.Status)
+		}
+	}
+	if err != nil {
+		log.Warn("Watchdog webhook failed", "url", url, "event", ev.Event, "err", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+)
+
+func TestHeadWatchdog(t *testing.T) {
+	var (
+		head     uint64 = 10
+		peerHead uint64 = 10
+		events   []string
+		resyncs  int
+	)
+	w := &headWatchdog{
+		cfg:      conf.WatchdogConfig{StallTimeout: time.Minute},
+		head:     func() (uint64, types.Hash) { return head, types.Hash{} },
+		peerHead: func() uint64 { return peerHead },
+		resync:   func() error { resyncs++; return nil },
+		notify:   func(ev watchdogEvent) { events = append(events, ev.Event) },
+	}
+	now := time.Unix(1700000000, 0)
+	w.check(now)
+
+	// Stuck head, but nobody is ahead: not a stall.
+	w.check(now.Add(2 * time.Minute))
+	if w.stalled || len(events) != 0 {
+		t.Fatalf("quiet network reported as stall: %v", events)
+	}
+
+	// Peers move on while the head stays put.
+	peerHead = 20
+	w.check(now.Add(3 * time.Minute))
+	if !w.stalled || len(events) != 1 || events[0] != "head_stalled" {
+		t.Fatalf("stall not reported: %v", events)
+	}
+	if resyncs != 1 {
+		t.Fatalf("resyncs = %d, want 1", resyncs)
+	}
+
+	// No repeated alert, and resync is throttled to once per stall timeout.
+	w.check(now.Add(3*time.Minute + 30*time.Second))
+	if len(events) != 1 || resyncs != 1 {
+		t.Fatalf("events %v, resyncs %d after repeated check", events, resyncs)
+	}
+	w.check(now.Add(4 * time.Minute))
+	if resyncs != 2 {
+		t.Fatalf("resyncs = %d, want 2", resyncs)
+	}
+
+	head = 11
+	w.check(now.Add(5 * time.Minute))
+	if w.stalled || len(events) != 2 || events[1] != "head_recovered" {
+		t.Fatalf("recovery not reported: %v", events)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package txspool
+
+import (
+	"fmt"
+
+	"github.com/n42blockchain/N42/common/crypto/kzg"
+	"github.com/n42blockchain/N42/common/transaction"
+)
+
+// validateBlobTx checks the blobs of an EIP-4844 transaction. The pool only
+// accepts blob transactions together with their sidecar, so that the blobs
+// can be gossiped to peers and proven against the versioned hashes.
+func validateBlobTx(tx *transaction.Transaction) error {
+	hashes := tx.BlobHashes()
+	if len(hashes) == 0 {
+		return transaction.ErrNoBlobs
+	}
+	if len(hashes) > transaction.MaxBlobsPerBlock {
+		return fmt.Errorf("%w: have %d, max %d", transaction.ErrTooManyBlobs, len(hashes), transaction.MaxBlobsPerBlock)
+	}
+	if tx.To() == nil {
+		return transaction.ErrBlobTxCreate
+	}
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil {
+		return transaction.ErrBlobSidecarMissing
+	}
+	if err := kzg.ValidateBlobSidecar(sidecar, hashes); err != nil {
+		return fmt.Errorf("%w: %v", transaction.ErrInvalidBlobProof, err)
+	}
+	return nil
+}
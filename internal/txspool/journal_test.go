@@ -0,0 +1,111 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package txspool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// newJournalTx creates a legacy transfer of from with the given nonce
+func newJournalTx(from types.Address, nonce, gasPrice uint64) *transaction.Transaction {
+	to := types.Address{0xee}
+	return transaction.NewTx(&transaction.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: uint256.NewInt(gasPrice),
+		Gas:      21000,
+		To:       &to,
+		From:     &from,
+		Value:    uint256.NewInt(1),
+	})
+}
+
+func TestTxJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.journal")
+	alice, bob := types.Address{0x01}, types.Address{0x02}
+
+	journal := newTxJournal(path)
+	if err := journal.insert(newJournalTx(alice, 0, 1)); err != errNoActiveJournal {
+		t.Fatalf("insert without journal: got %v", err)
+	}
+	if err := journal.rotate(map[types.Address][]*transaction.Transaction{
+		alice: {newJournalTx(alice, 0, 1), newJournalTx(alice, 1, 1)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.insert(newJournalTx(bob, 5, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded []*transaction.Transaction
+	add := func(txs []*transaction.Transaction) []error {
+		loaded = append(loaded, txs...)
+		return make([]error, len(txs))
+	}
+	if err := newTxJournal(path).load(add); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("loaded %d transactions, want 3", len(loaded))
+	}
+	if last := loaded[2]; *last.From() != bob || last.Nonce() != 5 || last.GasPrice().Uint64() != 2 {
+		t.Errorf("unexpected transaction from %v, nonce %d", last.From(), last.Nonce())
+	}
+
+	// A truncated entry stops the load after the intact ones
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-3], 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded = nil
+	if err := newTxJournal(path).load(add); err == nil {
+		t.Error("truncated journal loaded without error")
+	}
+	if len(loaded) != 2 {
+		t.Errorf("loaded %d transactions from truncated journal, want 2", len(loaded))
+	}
+
+	// A missing journal is not an error
+	if err := newTxJournal(filepath.Join(t.TempDir(), "missing")).load(add); err != nil {
+		t.Errorf("missing journal: %v", err)
+	}
+}
+
+func TestTxsPoolConfigSanitize(t *testing.T) {
+	config := TxsPoolConfig{Rejournal: 0, PriceBump: 25, Lifetime: 0}
+	sanitized := config.sanitize()
+	if sanitized.PriceBump != 25 {
+		t.Errorf("price bump changed to %d", sanitized.PriceBump)
+	}
+	if sanitized.PriceLimit != DefaultTxPoolConfig.PriceLimit || sanitized.GlobalSlots != DefaultTxPoolConfig.GlobalSlots {
+		t.Errorf("unset limits not defaulted: %+v", sanitized)
+	}
+	if sanitized.Lifetime != DefaultTxPoolConfig.Lifetime || sanitized.Rejournal <= 0 {
+		t.Errorf("invalid durations kept: %+v", sanitized)
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
 )
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -285,8 +284,7 @@ func (t *txLookup) RemotesBelowTip(threshold uint256.Int) []*transaction.Transac
 
 // numSlots calculates the number of slots needed for a single transaction.
 func numSlots(tx *transaction.Transaction) int {
-	//todo
-	return int((int(unsafe.Sizeof(tx)) + txSlotSize - 1) / txSlotSize)
+	return int((tx.Size() + txSlotSize - 1) / txSlotSize)
 }
 
 // TxByNonce implements the sort interface to allow sorting a list of transactions by their nonces.
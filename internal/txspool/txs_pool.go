@@ -31,15 +31,15 @@ import (
 	"sort"
 	"sync"
 	"time"
-	"unsafe"
 
 	"github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto/kzg"
 	"github.com/n42blockchain/N42/common/prque"
 	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/log"
-	event "github.com/n42blockchain/N42/modules/event/v2"
+	eventv3 "github.com/n42blockchain/N42/modules/event/v3"
 )
 
 const (
@@ -49,11 +49,10 @@ const (
 	// that validating a new transaction remains a constant operation (in reality
 	// O(maxslots), where max slots are 4 currently).
 	txSlotSize = 32 * 1024
-	// txMaxSize is the maximum size a single transaction can have. This field has
-	// non-trivial consequences: larger transactions are significantly harder and
-	// more expensive to propagate; larger transactions also take more resources
-	// to validate whether they fit into the pool or not.
-	txMaxSize = 4 * txSlotSize // 128KB
+
+	// evictionInterval is the time between checks for queued transactions
+	// exceeding their lifetime.
+	evictionInterval = time.Minute
 )
 
 var (
@@ -73,6 +72,9 @@ var (
 
 	ErrInsufficientFunds = fmt.Errorf("insufficient funds for gas * price + value")
 
+	ErrTxNotFound = fmt.Errorf("transaction not found")
+	ErrTxNotLocal = fmt.Errorf("transaction is not local")
+
 	// ErrTipAboveFeeCap is a sanity error to ensure no one is able to specify a
 	// transaction with a tip higher than the total fee cap.
 	ErrTipAboveFeeCap = fmt.Errorf("max priority fee per gas higher than max fee per gas")
@@ -82,13 +84,26 @@ var (
 	localGauge   = prometheus.GetOrCreateCounter("txpool_local", true)
 )
 
+// Reasons of transactions leaving the pool, reported by DroppedTxsEvent.
+const (
+	DropReplaced    = "replaced"    // replaced by a transaction with the same nonce and a higher price
+	DropUnderpriced = "underpriced" // discarded to make room for better paying transactions
+	DropUnpayable   = "unpayable"   // the sender can no longer pay for it or it exceeds the block gas limit
+	DropOverflow    = "overflow"    // the account or the pool exceeded its transaction limit
+	DropExpired     = "expired"     // queued for longer than the configured lifetime
+	DropEvicted     = "evicted"     // removed on request of the node operator
+)
+
 type txspoolResetRequest struct {
 	oldBlock, newBlock block.IBlock
 }
 
 type TxsPoolConfig struct {
-	Locals   []types.Address
-	NoLocals bool
+	Locals   []types.Address // Addresses that should be treated by default as local
+	NoLocals bool            // Whether local transaction handling should be disabled
+
+	Journal   string        // Journal of local transactions to survive node restarts
+	Rejournal time.Duration // Time interval to regenerate the local transaction journal
 
 	PriceLimit uint64
 	PriceBump  uint64
@@ -103,6 +118,7 @@ type TxsPoolConfig struct {
 
 // DefaultTxPoolConfig default blockchain
 var DefaultTxPoolConfig = TxsPoolConfig{
+	Rejournal: time.Hour,
 
 	PriceLimit: 1,
 	PriceBump:  10,
@@ -115,6 +131,45 @@ var DefaultTxPoolConfig = TxsPoolConfig{
 	Lifetime: 3 * time.Hour,
 }
 
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *TxsPoolConfig) sanitize() TxsPoolConfig {
+	conf := *config
+	if conf.Rejournal < time.Second {
+		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
+		conf.Rejournal = time.Second
+	}
+	if conf.PriceLimit < 1 {
+		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
+		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
+	}
+	if conf.PriceBump < 1 {
+		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
+		conf.PriceBump = DefaultTxPoolConfig.PriceBump
+	}
+	if conf.AccountSlots < 1 {
+		log.Warn("Sanitizing invalid txpool account slots", "provided", conf.AccountSlots, "updated", DefaultTxPoolConfig.AccountSlots)
+		conf.AccountSlots = DefaultTxPoolConfig.AccountSlots
+	}
+	if conf.GlobalSlots < 1 {
+		log.Warn("Sanitizing invalid txpool global slots", "provided", conf.GlobalSlots, "updated", DefaultTxPoolConfig.GlobalSlots)
+		conf.GlobalSlots = DefaultTxPoolConfig.GlobalSlots
+	}
+	if conf.AccountQueue < 1 {
+		log.Warn("Sanitizing invalid txpool account queue", "provided", conf.AccountQueue, "updated", DefaultTxPoolConfig.AccountQueue)
+		conf.AccountQueue = DefaultTxPoolConfig.AccountQueue
+	}
+	if conf.GlobalQueue < 1 {
+		log.Warn("Sanitizing invalid txpool global queue", "provided", conf.GlobalQueue, "updated", DefaultTxPoolConfig.GlobalQueue)
+		conf.GlobalQueue = DefaultTxPoolConfig.GlobalQueue
+	}
+	if conf.Lifetime < 1 {
+		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
+		conf.Lifetime = DefaultTxPoolConfig.Lifetime
+	}
+	return conf
+}
+
 type TxsPool struct {
 	config      TxsPoolConfig
 	chainconfig *params.ChainConfig
@@ -135,8 +190,11 @@ type TxsPool struct {
 	eip2718  bool // Fork indicator whether we are using EIP-2718 type transactions.
 	eip1559  bool // Fork indicator whether we are using EIP-1559 type transactions.
 	shanghai bool // Fork indicator whether we are in the Shanghai stage.
+	cancun   bool // Fork indicator whether we are using EIP-4844 blob transactions.
+	pectra   bool // Fork indicator whether we are using EIP-7702 set code transactions.
 
 	locals   *accountSet
+	journal  *txJournal // Journal of local transaction to back up to disk
 	pending  map[types.Address]*txsList
 	queue    map[types.Address]*txsList
 	beats    map[types.Address]time.Time
@@ -153,19 +211,29 @@ type TxsPool struct {
 
 	changesSinceReorg int
 
+	dropped []common.DroppedTx // Dropped transactions not yet announced
+
 	isRun uint32
 
 	deposit *deposit.Deposit
 }
 
+// NewTxsPool creates a transaction pool with the default configuration.
 func NewTxsPool(ctx context.Context, bc common.IBlockChain, depositContract *deposit.Deposit) (common.ITxsPool, error) {
+	return NewTxsPoolWithConfig(ctx, DefaultTxPoolConfig, bc, depositContract)
+}
+
+// NewTxsPoolWithConfig creates a transaction pool with the given limits. Local
+// transactions of a previous run are restored from the journal, if any.
+func NewTxsPoolWithConfig(ctx context.Context, config TxsPoolConfig, bc common.IBlockChain, depositContract *deposit.Deposit) (common.ITxsPool, error) {
+	config = (&config).sanitize()
 
 	c, cancel := context.WithCancel(ctx)
 	// for test
 	//log.Init(nil)
 	pool := &TxsPool{
 		chainconfig: bc.Config(),
-		config:      DefaultTxPoolConfig,
+		config:      config,
 		ctx:         c,
 		cancel:      cancel,
 
@@ -187,7 +255,11 @@ func NewTxsPool(ctx context.Context, bc common.IBlockChain, depositContract *dep
 		queueTxEventCh:  make(chan *transaction.Transaction),
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
-		gasPrice:        uint256.NewInt(DefaultTxPoolConfig.PriceLimit),
+		gasPrice:        uint256.NewInt(config.PriceLimit),
+	}
+	for _, addr := range config.Locals {
+		log.Info("Setting new local account", "address", addr)
+		pool.locals.add(addr)
 	}
 
 	//
@@ -196,6 +268,7 @@ func NewTxsPool(ctx context.Context, bc common.IBlockChain, depositContract *dep
 
 	pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, bc.CurrentBlock())
+	pool.updateBaseFee(bc.CurrentBlock())
 
 	pool.wg.Add(1)
 	go pool.scheduleLoop()
@@ -203,6 +276,23 @@ func NewTxsPool(ctx context.Context, bc common.IBlockChain, depositContract *dep
 	pool.wg.Add(1)
 	go pool.blockChangeLoop()
 
+	// If local transactions and journaling is enabled, load from disk
+	if !config.NoLocals && config.Journal != "" {
+		pool.journal = newTxJournal(config.Journal)
+
+		if err := pool.journal.load(pool.AddLocals); err != nil {
+			log.Warn("Failed to load transaction journal", "err", err)
+		}
+		pool.mu.Lock()
+		if err := pool.journal.rotate(pool.local()); err != nil {
+			log.Warn("Failed to rotate transaction journal", "err", err)
+		}
+		pool.mu.Unlock()
+	}
+
+	pool.wg.Add(1)
+	go pool.maintenanceLoop()
+
 	//todo for test
 	//pool.wg.Add(1)
 	//go pool.ethFetchTxPoolLoop()
@@ -232,6 +322,7 @@ func (pool *TxsPool) promoteTx(addr types.Address, hash types.Hash, tx *transact
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.markDropped(DropUnderpriced, nil, tx)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -239,6 +330,7 @@ func (pool *TxsPool) promoteTx(addr types.Address, hash types.Hash, tx *transact
 		hash := old.Hash()
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.markDropped(DropReplaced, tx, old)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc()
@@ -297,6 +389,7 @@ func (pool *TxsPool) addTxs(txs []*transaction.Transaction, local, sync bool) []
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
 	pool.mu.Unlock()
+	pool.announceDropped()
 
 	var nilSlot = 0
 	for _, err := range newErrs {
@@ -314,7 +407,7 @@ func (pool *TxsPool) addTxs(txs []*transaction.Transaction, local, sync bool) []
 			}
 		}
 		//log.Infof("event new local txs : %v", localTxs)
-		event.GlobalEvent.Send(common.NewLocalTxsEvent{Txs: localTxs})
+		common.NewLocalTxsTopic.Send(common.NewLocalTxsEvent{Txs: localTxs})
 	}
 	// Reorg the pool internals if needed and return
 	done := pool.requestPromoteExecutables(dirtyAddrs)
@@ -451,6 +544,7 @@ func (pool *TxsPool) add(tx *transaction.Transaction, local bool) (replaced bool
 			hash := tx.Hash()
 			pool.removeTx(hash, false)
 		}
+		pool.markDropped(DropUnderpriced, nil, drop...)
 	}
 	// Try to replace an existing transaction in the pending pool
 	from := *tx.From() //
@@ -465,6 +559,7 @@ func (pool *TxsPool) add(tx *transaction.Transaction, local bool) (replaced bool
 			hash := old.Hash()
 			pool.all.Remove(hash)
 			pool.priced.Removed(1)
+			pool.markDropped(DropReplaced, tx, old)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
@@ -489,11 +584,68 @@ func (pool *TxsPool) add(tx *transaction.Transaction, local bool) (replaced bool
 	if isLocal {
 		localGauge.Inc()
 	}
+	pool.journalTx(from, tx)
 
 	//log.Debug("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To)
 	return replaced, nil
 }
 
+// markDropped records txs as dropped for reason, to be announced once the
+// pool lock is released. replacement is the transaction replacing them, if any.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxsPool) markDropped(reason string, replacement *transaction.Transaction, txs ...*transaction.Transaction) {
+	var by types.Hash
+	if replacement != nil {
+		by = replacement.Hash()
+	}
+	for _, tx := range txs {
+		pool.dropped = append(pool.dropped, common.DroppedTx{Tx: tx, Reason: reason, Replacement: by})
+	}
+}
+
+// announceDropped posts a DroppedTxsEvent for the transactions dropped since
+// the last announcement.
+func (pool *TxsPool) announceDropped() {
+	pool.mu.Lock()
+	dropped := pool.dropped
+	pool.dropped = nil
+	pool.mu.Unlock()
+
+	if len(dropped) > 0 {
+		common.DroppedTxsTopic.Send(common.DroppedTxsEvent{Drops: dropped})
+	}
+}
+
+// journalTx adds the specified transaction to the local disk journal if it is
+// deemed to have been sent from a local account.
+func (pool *TxsPool) journalTx(from types.Address, tx *transaction.Transaction) {
+	// Only journal if it's enabled and the transaction is local
+	if pool.journal == nil || !pool.locals.contains(from) {
+		return
+	}
+	if err := pool.journal.insert(tx); err != nil {
+		log.Warn("Failed to journal local transaction", "err", err)
+	}
+}
+
+// local retrieves all currently known local transactions, grouped by origin
+// account and sorted by nonce.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxsPool) local() map[types.Address][]*transaction.Transaction {
+	txs := make(map[types.Address][]*transaction.Transaction)
+	for addr := range pool.locals.accounts {
+		if pending := pool.pending[addr]; pending != nil {
+			txs[addr] = append(txs[addr], pending.Flatten()...)
+		}
+		if queued := pool.queue[addr]; queued != nil {
+			txs[addr] = append(txs[addr], queued.Flatten()...)
+		}
+	}
+	return txs
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -513,6 +665,7 @@ func (pool *TxsPool) enqueueTx(hash types.Hash, tx *transaction.Transaction, loc
 		hash := old.Hash()
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.markDropped(DropReplaced, tx, old)
 	} else {
 		queuedGauge.Inc()
 	}
@@ -545,17 +698,43 @@ func (pool *TxsPool) validateTx(tx *transaction.Transaction, local bool) error {
 	if !pool.eip1559 && tx.Type() == transaction.DynamicFeeTxType {
 		return internal.ErrTxTypeNotSupported
 	}
-	// Reject transactions over defined size to prevent DOS attacks
-	//if tx.Size() > txMaxSize {
-	//	return ErrOversizedData
-	//}
+	// Reject blob transactions until EIP-4844 activates and their sidecars
+	// can be proven.
+	if (!pool.cancun || !kzg.Implemented) && tx.Type() == transaction.BlobTxType {
+		return internal.ErrTxTypeNotSupported
+	}
+	// Reject set code transactions until EIP-7702 activates.
+	if tx.Type() == transaction.SetCodeTxType {
+		if !pool.pectra {
+			return internal.ErrTxTypeNotSupported
+		}
+		if tx.To() == nil {
+			return internal.ErrSetCodeTxCreate
+		}
+		if len(tx.AuthList()) == 0 {
+			return internal.ErrEmptyAuthList
+		}
+	}
+	// Reject transactions over defined size to prevent DOS attacks. Blob
+	// sidecars are not part of blocks and are checked separately.
+	if size, limit := tx.WithoutBlobTxSidecar().Size(), pool.chainconfig.TxSizeLimit(); size > limit {
+		return fmt.Errorf("%w: size %d, limit %d", ErrOversizedData, size, limit)
+	}
+	if tx.Type() == transaction.BlobTxType {
+		if err := validateBlobTx(tx); err != nil {
+			return err
+		}
+	}
+	// Check whether the init code size has been exceeded.
+	if pool.shanghai && tx.To() == nil {
+		if limit := pool.chainconfig.InitCodeSizeLimit(); uint64(len(tx.Data())) > limit {
+			return fmt.Errorf("%w: code size %d, limit %d", internal.ErrMaxInitCodeSizeExceeded, len(tx.Data()), limit)
+		}
+	}
 
 	gasPrice := tx.GasPrice()
 	addr := *tx.From()
 
-	if uint64(unsafe.Sizeof(tx)) > txMaxSize {
-		return ErrOversizedData
-	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -564,7 +743,7 @@ func (pool *TxsPool) validateTx(tx *transaction.Transaction, local bool) error {
 
 	// Ensure the transaction doesn't exceed the current block limit gas.
 	if pool.currentMaxGas < tx.Gas() {
-		return ErrGasLimit
+		return fmt.Errorf("%w: gas %d, block gas limit %d", ErrGasLimit, tx.Gas(), pool.currentMaxGas)
 	}
 
 	// Sanity check for extremely large numbers
@@ -595,7 +774,7 @@ func (pool *TxsPool) validateTx(tx *transaction.Transaction, local bool) error {
 	}
 
 	// Ensure the transaction has more gas than the basic tx fee.
-	intrGas, err := internal.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.istanbul, pool.shanghai)
+	intrGas, err := internal.IntrinsicGas(tx.Data(), tx.AccessList(), tx.AuthList(), tx.To() == nil, true, pool.istanbul, pool.shanghai)
 	if err != nil {
 		return err
 	}
@@ -619,10 +798,28 @@ func (pool *TxsPool) validateTx(tx *transaction.Transaction, local bool) error {
 	return nil
 }
 
-// validateSender verify todo
+// validateSender checks the sender a signed transaction carries against the
+// one recovered from its signature. The recovered sender is cached by hash,
+// so executing or serving the transaction later does not recover it again.
+// Unsigned transactions are accepted as carried.
 func (pool *TxsPool) validateSender(tx *transaction.Transaction) bool {
-
-	return true
+	_, r, s := tx.RawSignatureValues()
+	if (r == nil || r.IsZero()) && (s == nil || s.IsZero()) {
+		return tx.From() != nil
+	}
+	var chainID *big.Int
+	if pool.chainconfig != nil {
+		chainID = pool.chainconfig.ChainID
+	}
+	from, err := transaction.Sender(transaction.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return false
+	}
+	if tx.From() == nil {
+		tx.SetFrom(from)
+		return true
+	}
+	return *tx.From() == from
 }
 
 // requestReset requests a pool reset to the new head block.
@@ -673,8 +870,8 @@ func (pool *TxsPool) reset(oldBlock, newBlock block.IBlock) {
 			// Reorg seems shallow enough to pull in all transactions into memorynewHash
 			var discarded, included []*transaction.Transaction
 			var (
-				rem = oldBlock
-				add = newBlock
+				rem, _ = pool.bc.GetBlockByHash(oldBlock.Hash())
+				add    = newBlock
 			)
 			if rem == nil {
 				// This can happen if a setHead is performed, where we simply discard the old
@@ -749,6 +946,18 @@ func (pool *TxsPool) reset(oldBlock, newBlock block.IBlock) {
 	pool.istanbul = pool.chainconfig.IsIstanbul(next.Uint64())
 	pool.eip2718 = pool.chainconfig.IsBerlin(next.Uint64())
 	pool.eip1559 = pool.chainconfig.IsLondon(next.Uint64())
+	pool.cancun = pool.chainconfig.IsCancun(next.Uint64())
+	pool.pectra = pool.chainconfig.IsPectra(next.Uint64())
+}
+
+// updateBaseFee prices the pool with the base fee of the block after head,
+// once London is active.
+func (pool *TxsPool) updateBaseFee(head block.IBlock) {
+	if !pool.chainconfig.IsLondon(head.Number64().Uint64() + 1) {
+		return
+	}
+	pendingBaseFee, _ := uint256.FromBig(misc.CalcBaseFee(pool.chainconfig, head.Header().(*block.Header)))
+	pool.priced.SetBaseFee(pendingBaseFee)
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -778,6 +987,7 @@ func (pool *TxsPool) promoteExecutables(accounts []types.Address) []*transaction
 			hash := tx.Hash()
 			pool.all.Remove(hash)
 		}
+		pool.markDropped(DropUnpayable, nil, drops...)
 		//log.Debug("Removed unpayable queued transactions", "count", len(drops))
 
 		// Gather all executable transactions and promote them
@@ -800,6 +1010,7 @@ func (pool *TxsPool) promoteExecutables(accounts []types.Address) []*transaction
 				pool.all.Remove(hash)
 				//log.Debug("Removed cap-exceeding queued transaction", "hash", hash)
 			}
+			pool.markDropped(DropOverflow, nil, caps...)
 		}
 		// Mark all the items dropped as removed
 		//todo pool.priced.Removed(len(forwards) + len(drops) + len(caps))
@@ -865,6 +1076,7 @@ func (pool *TxsPool) truncatePending() {
 						log.Debug("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
 					pool.priced.Removed(len(caps))
+					pool.markDropped(DropOverflow, nil, caps...)
 					pendingGauge.Add(-len(caps))
 					if pool.locals.contains(offenders[i]) {
 						localGauge.Add(-(len(caps)))
@@ -892,6 +1104,7 @@ func (pool *TxsPool) truncatePending() {
 					log.Debug("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
 				pool.priced.Removed(len(caps))
+				pool.markDropped(DropOverflow, nil, caps...)
 				pendingGauge.Add(-len(caps))
 				if pool.locals.contains(addr) {
 					localGauge.Add(-len(caps))
@@ -930,10 +1143,12 @@ func (pool *TxsPool) truncateQueue() {
 
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
-			for _, tx := range list.Flatten() {
+			txs := list.Flatten()
+			for _, tx := range txs {
 				hash := tx.Hash()
 				pool.removeTx(hash, true)
 			}
+			pool.markDropped(DropOverflow, nil, txs...)
 			drop -= size
 			continue
 		}
@@ -942,6 +1157,7 @@ func (pool *TxsPool) truncateQueue() {
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			hash := txs[i].Hash()
 			pool.removeTx(hash, true)
+			pool.markDropped(DropOverflow, nil, txs[i])
 			drop--
 		}
 	}
@@ -973,6 +1189,7 @@ func (pool *TxsPool) demoteUnexecutables() {
 			//log.Debug("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
 		}
+		pool.markDropped(DropUnpayable, nil, drops...)
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
@@ -1044,9 +1261,8 @@ func (pool *TxsPool) runReorg(done chan struct{}, reset *txspoolResetRequest, di
 	if reset != nil {
 		pool.demoteUnexecutables()
 
-		if reset.newBlock != nil && pool.chainconfig.IsLondon(reset.newBlock.Number64().Uint64()+1) {
-			pendingBaseFee, _ := uint256.FromBig(misc.CalcBaseFee(pool.chainconfig, reset.newBlock.Header().(*block.Header)))
-			pool.priced.SetBaseFee(pendingBaseFee)
+		if reset.newBlock != nil {
+			pool.updateBaseFee(reset.newBlock)
 		}
 		// Update all accounts to the latest known pending nonce
 		nonces := make(map[types.Address]uint64, len(pool.pending))
@@ -1062,6 +1278,7 @@ func (pool *TxsPool) runReorg(done chan struct{}, reset *txspoolResetRequest, di
 
 	pool.changesSinceReorg = 0 // Reset change counter
 	pool.mu.Unlock()
+	pool.announceDropped()
 
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
@@ -1077,7 +1294,7 @@ func (pool *TxsPool) runReorg(done chan struct{}, reset *txspoolResetRequest, di
 		for _, set := range events {
 			txs = append(txs, set.Flatten()...)
 		}
-		event.GlobalEvent.Send(common.NewTxsEvent{Txs: txs})
+		common.NewTxsTopic.Send(common.NewTxsEvent{Txs: txs})
 	}
 }
 
@@ -1163,9 +1380,8 @@ func (pool *TxsPool) scheduleLoop() {
 func (pool *TxsPool) blockChangeLoop() {
 	defer pool.wg.Done()
 
-	highestBlockCh := make(chan common.ChainHighestBlock)
-	defer close(highestBlockCh)
-	highestSub := event.GlobalEvent.Subscribe(highestBlockCh)
+	// Resets always move to the current head, so only the latest event counts
+	highestSub := common.ChainHeadTopic.Subscribe(1, eventv3.DropOldest)
 	defer highestSub.Unsubscribe()
 
 	oldBlock := pool.bc.CurrentBlock()
@@ -1176,8 +1392,8 @@ func (pool *TxsPool) blockChangeLoop() {
 			return
 		case <-pool.ctx.Done():
 			return
-		case highestBlock, ok := <-highestBlockCh:
-			if ok && highestBlock.Inserted {
+		case highestBlock := <-highestSub.Chan():
+			if highestBlock.Inserted {
 				pool.requestReset(oldBlock, pool.bc.CurrentBlock())
 				oldBlock = pool.bc.CurrentBlock()
 			}
@@ -1185,10 +1401,65 @@ func (pool *TxsPool) blockChangeLoop() {
 	}
 }
 
+// maintenanceLoop evicts queued transactions which exceeded their lifetime and
+// regenerates the journal of local transactions.
+func (pool *TxsPool) maintenanceLoop() {
+	defer pool.wg.Done()
+
+	var (
+		evict   = time.NewTicker(evictionInterval)
+		journal = time.NewTicker(pool.config.Rejournal)
+	)
+	defer evict.Stop()
+	defer journal.Stop()
+
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+
+		// Handle inactive account transaction eviction
+		case <-evict.C:
+			pool.mu.Lock()
+			for addr := range pool.queue {
+				// Skip local transactions from the eviction mechanism
+				if pool.locals.contains(addr) {
+					continue
+				}
+				// Any non-locals old enough should be removed
+				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
+					list := pool.queue[addr].Flatten()
+					for _, tx := range list {
+						pool.removeTx(tx.Hash(), true)
+					}
+					pool.markDropped(DropExpired, nil, list...)
+					log.Debug("Evicted stale queued transactions", "account", addr, "count", len(list))
+				}
+			}
+			pool.mu.Unlock()
+			pool.announceDropped()
+
+		// Handle local transaction journal rotation
+		case <-journal.C:
+			if pool.journal != nil {
+				pool.mu.Lock()
+				if err := pool.journal.rotate(pool.local()); err != nil {
+					log.Warn("Failed to rotate local tx journal", "err", err)
+				}
+				pool.mu.Unlock()
+			}
+		}
+	}
+}
+
 // Stop terminates the transaction pool.
 func (pool *TxsPool) Stop() error {
 	pool.cancel()
 	pool.wg.Wait()
+
+	if pool.journal != nil {
+		pool.journal.close()
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -1249,16 +1520,45 @@ func (pool *TxsPool) Has(hash types.Hash) bool {
 	return pool.all.Get(hash) != nil
 }
 
-// GetTransaction
+// GetTransaction returns the executable transactions paying at least the price
+// limit of the pool (local ones regardless of their price), grouped by account
+// in nonce order. The order across accounts is left to the miner.
 func (pool *TxsPool) GetTransaction() (txs []*transaction.Transaction, err error) {
-	//
-	pending := pool.Pending(false)
-	heads := make([]*transaction.Transaction, 0, len(pending))
-	for _, accTxs := range pending {
-		//heads = append(heads, accTxs[0])
-		heads = append(heads, accTxs...)
+	for _, list := range pool.Pending(true) {
+		txs = append(txs, list...)
 	}
-	return heads, nil
+	return txs, nil
+}
+
+// DropLocal removes the local transaction hash from the pool, e.g. to get rid
+// of a stuck one. Later transactions of the sender become non-executable until
+// the nonce gap is filled again.
+func (pool *TxsPool) DropLocal(hash types.Hash) error {
+	pool.mu.Lock()
+	tx := pool.all.Get(hash)
+	if tx == nil {
+		pool.mu.Unlock()
+		return ErrTxNotFound
+	}
+	if !pool.locals.contains(*tx.From()) {
+		pool.mu.Unlock()
+		return ErrTxNotLocal
+	}
+	pool.removeTx(hash, true)
+	pool.markDropped(DropEvicted, nil, tx)
+
+	// Rewrite the journal right away, so the transaction is not resurrected
+	// by a restart
+	if pool.journal != nil {
+		if err := pool.journal.rotate(pool.local()); err != nil {
+			log.Warn("Failed to rotate local tx journal", "err", err)
+		}
+	}
+	pool.mu.Unlock()
+	pool.announceDropped()
+
+	log.Info("Dropped local transaction", "hash", hash, "from", tx.From(), "nonce", tx.Nonce())
+	return nil
 }
 
 // GetTx
@@ -1282,11 +1582,21 @@ func (pool *TxsPool) Content() (map[types.Address][]*transaction.Transaction, ma
 	return pending, queued
 }
 
+// Nonce returns the next nonce of an account, with all transactions executable
+// by the pool already applied on top of the current state. Queued transactions
+// that continue the pending sequence but have not been promoted yet are counted
+// too, so that callers issuing back-to-back transactions don't reuse a nonce.
 func (pool *TxsPool) Nonce(addr types.Address) uint64 {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
-	return pool.pendingNonces.get(addr)
+	nonce := pool.pendingNonces.get(addr)
+	if list := pool.queue[addr]; list != nil {
+		for list.txs.Get(nonce) != nil {
+			nonce++
+		}
+	}
+	return nonce
 }
 
 // StatsPrint
@@ -1301,6 +1611,9 @@ func (pool *TxsPool) StatsPrint() {
 }
 
 func (pool *TxsPool) Stats() (int, int, int, int) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
 	pendingTxs := 0
 	pendingAddresses := len(pool.pending)
 	for _, list := range pool.pending {
@@ -21,6 +21,7 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 )
 
@@ -203,6 +204,44 @@ func TestReadStateInterface(t *testing.T) {
 	t.Logf("✓ ReadState interface is correctly defined")
 }
 
+// =============================================================================
+// Pool Nonce Tests
+// =============================================================================
+
+func TestPoolNonceIncludesQueued(t *testing.T) {
+	db := newMockReadState()
+	addr := types.Address{0x01}
+	db.setNonce(addr, 5)
+
+	pool := &TxsPool{
+		pendingNonces: newTxNoncer(db),
+		queue:         make(map[types.Address]*txsList),
+	}
+	if nonce := pool.Nonce(addr); nonce != 5 {
+		t.Fatalf("Nonce() = %d, want 5 without transactions", nonce)
+	}
+
+	// Queue 5, 6 and 8: the gap at 7 must stop the sequence
+	list := newTxsList(false)
+	for _, n := range []uint64{5, 6, 8} {
+		list.Add(transaction.NewTx(&transaction.LegacyTx{Nonce: n, Value: uint256.NewInt(0), Gas: 21000, GasPrice: uint256.NewInt(1)}), 10)
+	}
+	pool.queue[addr] = list
+	if nonce := pool.Nonce(addr); nonce != 7 {
+		t.Errorf("Nonce() = %d, want 7 with queued 5, 6 and 8", nonce)
+	}
+
+	// Promoted transactions move the pending nonce, queued ones extend it
+	pool.pendingNonces.set(addr, 7)
+	if nonce := pool.Nonce(addr); nonce != 7 {
+		t.Errorf("Nonce() = %d, want 7 after promotion", nonce)
+	}
+	pool.pendingNonces.set(addr, 8)
+	if nonce := pool.Nonce(addr); nonce != 9 {
+		t.Errorf("Nonce() = %d, want 9 with queued 8", nonce)
+	}
+}
+
 // =============================================================================
 // Benchmark Tests
 // =============================================================================
@@ -154,13 +154,23 @@ func (c *Contract) AsDelegate() *Contract {
 
 // GetOp returns the n'th element in the contract's byte array
 func (c *Contract) GetOp(n uint64) OpCode {
-	if n < uint64(len(c.Code)) {
-		return OpCode(c.Code[n])
+	code := c.sectionCode()
+	if n < uint64(len(code)) {
+		return OpCode(code[n])
 	}
 
 	return STOP
 }
 
+// sectionCode returns the code being executed, which is the current code
+// section for EOF contracts.
+func (c *Contract) sectionCode() []byte {
+	if c.EOFContainer != nil {
+		return c.EOFContainer.Code[c.CodeSection]
+	}
+	return c.Code
+}
+
 // Caller returns the caller of the contract.
 //
 // Caller will recursively call caller when the contract is a delegate
@@ -189,6 +189,11 @@ func init() {
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules *params.Rules) []types.Address {
+	return withCustomPrecompiles(activeBuiltinPrecompiles(rules), rules)
+}
+
+// activeBuiltinPrecompiles returns the Ethereum precompiles enabled by rules.
+func activeBuiltinPrecompiles(rules *params.Rules) []types.Address {
 	switch {
 	case rules.IsMoran:
 		return PrecompiledAddressesMoran
@@ -76,6 +76,9 @@ func (c *pointEvaluationPrecompile) RequiredGas(input []byte) uint64 {
 //   - FIELD_ELEMENTS_PER_BLOB: 32 bytes (big-endian)
 //   - BLS_MODULUS: 32 bytes (big-endian)
 func (c *pointEvaluationPrecompile) Run(input []byte) ([]byte, error) {
+	if !kzg.Implemented {
+		return nil, errBlobVerifyUnavailable
+	}
 	if len(input) != pointEvaluationInputLength {
 		return nil, errBlobVerifyInputLength
 	}
@@ -143,6 +146,7 @@ var (
 	errBlobVerifyVersionHash = errors.New("invalid versioned hash version")
 	errBlobVerifyMismatch    = errors.New("versioned hash mismatch")
 	errBlobVerifyKZGProof    = errors.New("kzg proof verification failed")
+	errBlobVerifyUnavailable = errors.New("kzg proof verification unavailable")
 )
 
 // =============================================================================
@@ -0,0 +1,160 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/params"
+)
+
+// =============================================================================
+// Custom precompiled contracts
+//
+// App-chains built on N42 can add domain specific precompiles without forking
+// the VM. The implementation is registered under a name at start up, usually
+// from an init function of the package providing it:
+//
+//	vm.RegisterPrecompile("poseidon", poseidonGas, poseidonHash)
+//
+// and enabled at an address by the chain config:
+//
+//	"customPrecompiles": [{"name": "poseidon", "address": "0x...", "block": 1000}]
+//
+// Addresses whose first 18 bytes are zero are reserved for the precompiles of
+// Ethereum and cannot be used.
+// =============================================================================
+
+// PrecompileGasFunc returns the gas charged for running a precompile on input.
+type PrecompileGasFunc func(input []byte) uint64
+
+// PrecompileRunFunc runs a precompile on input.
+type PrecompileRunFunc func(input []byte) ([]byte, error)
+
+var (
+	errPrecompileName       = errors.New("precompile name must not be empty")
+	errPrecompileFuncs      = errors.New("precompile needs a gas and a run function")
+	errPrecompileRegistered = errors.New("precompile already registered")
+)
+
+// customPrecompile adapts a gas and a run function to PrecompiledContract.
+type customPrecompile struct {
+	gas PrecompileGasFunc
+	run PrecompileRunFunc
+}
+
+func (c *customPrecompile) RequiredGas(input []byte) uint64  { return c.gas(input) }
+func (c *customPrecompile) Run(input []byte) ([]byte, error) { return c.run(input) }
+
+var (
+	customPrecompilesMu sync.RWMutex
+	customPrecompiles   = make(map[string]PrecompiledContract)
+)
+
+// RegisterPrecompile registers a precompiled contract under name. It must be
+// called before blocks are processed; the chain config decides at which
+// address and block the contract is enabled.
+func RegisterPrecompile(name string, gas PrecompileGasFunc, run PrecompileRunFunc) error {
+	if gas == nil || run == nil {
+		return errPrecompileFuncs
+	}
+	return RegisterPrecompiledContract(name, &customPrecompile{gas: gas, run: run})
+}
+
+// RegisterPrecompiledContract registers an implementation of
+// PrecompiledContract under name, see RegisterPrecompile.
+func RegisterPrecompiledContract(name string, p PrecompiledContract) error {
+	if name == "" {
+		return errPrecompileName
+	}
+	if p == nil {
+		return errPrecompileFuncs
+	}
+	customPrecompilesMu.Lock()
+	defer customPrecompilesMu.Unlock()
+	if _, ok := customPrecompiles[name]; ok {
+		return fmt.Errorf("%w: %s", errPrecompileRegistered, name)
+	}
+	customPrecompiles[name] = p
+	return nil
+}
+
+// CustomPrecompile returns the precompiled contract registered under name.
+func CustomPrecompile(name string) (PrecompiledContract, bool) {
+	customPrecompilesMu.RLock()
+	defer customPrecompilesMu.RUnlock()
+	p, ok := customPrecompiles[name]
+	return p, ok
+}
+
+// isReservedPrecompileAddress reports whether addr lies in the range of the
+// Ethereum precompiles.
+func isReservedPrecompileAddress(addr types.Address) bool {
+	for _, b := range addr[:types.AddressLength-2] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCustomPrecompiles checks that every custom precompile of config is
+// registered and enabled at its own address outside the reserved range.
+func ValidateCustomPrecompiles(config *params.ChainConfig) error {
+	seen := make(map[types.Address]string, len(config.CustomPrecompiles))
+	for _, p := range config.CustomPrecompiles {
+		if _, ok := CustomPrecompile(p.Name); !ok {
+			return fmt.Errorf("custom precompile %q is not registered", p.Name)
+		}
+		if isReservedPrecompileAddress(p.Address) {
+			return fmt.Errorf("custom precompile %q uses reserved address %s", p.Name, p.Address)
+		}
+		if other, ok := seen[p.Address]; ok {
+			return fmt.Errorf("custom precompiles %q and %q share address %s", other, p.Name, p.Address)
+		}
+		seen[p.Address] = p.Name
+	}
+	return nil
+}
+
+// customPrecompileAt returns the custom precompile enabled at addr by rules.
+func customPrecompileAt(rules *params.Rules, addr types.Address) (PrecompiledContract, bool) {
+	name, ok := rules.CustomPrecompiles[addr]
+	if !ok {
+		return nil, false
+	}
+	return CustomPrecompile(name)
+}
+
+// withCustomPrecompiles appends the addresses of the custom precompiles
+// enabled by rules to addrs, without modifying addrs.
+func withCustomPrecompiles(addrs []types.Address, rules *params.Rules) []types.Address {
+	if len(rules.CustomPrecompiles) == 0 {
+		return addrs
+	}
+	custom := make([]types.Address, 0, len(rules.CustomPrecompiles))
+	for addr := range rules.CustomPrecompiles {
+		custom = append(custom, addr)
+	}
+	sort.Slice(custom, func(i, j int) bool { return bytes.Compare(custom[i][:], custom[j][:]) < 0 })
+	return append(append(make([]types.Address, 0, len(addrs)+len(custom)), addrs...), custom...)
+}
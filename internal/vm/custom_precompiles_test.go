@@ -0,0 +1,99 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/vm/evmtypes"
+	"github.com/n42blockchain/N42/params"
+)
+
+func TestCustomPrecompile(t *testing.T) {
+	gas := func(input []byte) uint64 { return uint64(len(input)) * 10 }
+	run := func(input []byte) ([]byte, error) { return append(input, input...), nil }
+	if err := RegisterPrecompile("test-double", gas, run); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPrecompile("test-double", gas, run); !errors.Is(err, errPrecompileRegistered) {
+		t.Errorf("duplicate registration: got %v", err)
+	}
+	if err := RegisterPrecompile("test-nil", nil, run); err != errPrecompileFuncs {
+		t.Errorf("missing gas function: got %v", err)
+	}
+
+	addr := types.HexToAddress("0x0000000000000000000000000000000000424242")
+	config := &params.ChainConfig{
+		ByzantiumBlock:    big.NewInt(0),
+		CustomPrecompiles: []params.CustomPrecompileConfig{{Name: "test-double", Address: addr, Block: big.NewInt(10)}},
+	}
+	if err := ValidateCustomPrecompiles(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// The precompile is enabled from block 10 on
+	before := NewEVM(evmtypes.BlockContext{BlockNumber: 9}, evmtypes.TxContext{}, nil, config, Config{})
+	if _, ok := before.precompile(addr); ok {
+		t.Error("precompile enabled before its block")
+	}
+	if n := len(ActivePrecompiles(before.chainRules)); n != len(PrecompiledAddressesByzantium) {
+		t.Errorf("got %d active precompiles before the block, want %d", n, len(PrecompiledAddressesByzantium))
+	}
+	after := NewEVM(evmtypes.BlockContext{BlockNumber: 10}, evmtypes.TxContext{}, nil, config, Config{})
+	p, ok := after.precompile(addr)
+	if !ok {
+		t.Fatal("precompile not enabled at its block")
+	}
+	out, left, err := RunPrecompiledContract(p, []byte{1, 2}, 100)
+	if err != nil || left != 80 || !bytes.Equal(out, []byte{1, 2, 1, 2}) {
+		t.Errorf("got %x, %d gas left, err %v", out, left, err)
+	}
+	active := ActivePrecompiles(after.chainRules)
+	if len(active) != len(PrecompiledAddressesByzantium)+1 || active[len(active)-1] != addr {
+		t.Errorf("unexpected active precompiles %v", active)
+	}
+	if len(PrecompiledAddressesByzantium) != 8 {
+		t.Error("builtin address list modified")
+	}
+}
+
+func TestValidateCustomPrecompiles(t *testing.T) {
+	if err := RegisterPrecompiledContract("test-sha256", &sha256hash{}); err != nil {
+		t.Fatal(err)
+	}
+	addr := types.HexToAddress("0x00000000000000000000000000000000000a0000")
+	tests := []struct {
+		name        string
+		precompiles []params.CustomPrecompileConfig
+		valid       bool
+	}{
+		{"valid", []params.CustomPrecompileConfig{{Name: "test-sha256", Address: addr}}, true},
+		{"unregistered", []params.CustomPrecompileConfig{{Name: "unknown", Address: addr}}, false},
+		{"reserved address", []params.CustomPrecompileConfig{{Name: "test-sha256", Address: types.BytesToAddress([]byte{0x01, 0x00})}}, false},
+		{"shared address", []params.CustomPrecompileConfig{{Name: "test-sha256", Address: addr}, {Name: "test-sha256", Address: addr}}, false},
+	}
+	for _, test := range tests {
+		err := ValidateCustomPrecompiles(&params.ChainConfig{CustomPrecompiles: test.precompiles})
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v", test.name, err)
+		}
+	}
+}
@@ -37,23 +37,23 @@ import (
 
 const (
 	// Gas costs for EOF instructions
-	GasRJUMP  = 2   // RJUMP gas cost
-	GasRJUMPI = 4   // RJUMPI gas cost
-	GasRJUMPV = 4   // RJUMPV base gas cost
-	GasCALLF  = 5   // CALLF gas cost
-	GasRETF   = 3   // RETF gas cost
-	GasJUMPF  = 5   // JUMPF gas cost
+	GasRJUMP  = 2 // RJUMP gas cost
+	GasRJUMPI = 4 // RJUMPI gas cost
+	GasRJUMPV = 4 // RJUMPV base gas cost
+	GasCALLF  = 5 // CALLF gas cost
+	GasRETF   = 3 // RETF gas cost
+	GasJUMPF  = 5 // JUMPF gas cost
 
 	// Data section access
-	GasDataLoad  = 4   // DATALOAD gas cost
-	GasDataLoadN = 3   // DATALOADN gas cost
-	GasDataSize  = 2   // DATASIZE gas cost
-	GasDataCopy  = 3   // DATACOPY base gas cost
+	GasDataLoad  = 4 // DATALOAD gas cost
+	GasDataLoadN = 3 // DATALOADN gas cost
+	GasDataSize  = 2 // DATASIZE gas cost
+	GasDataCopy  = 3 // DATACOPY base gas cost
 
 	// Stack manipulation
-	GasDUPN     = 3   // DUPN gas cost
-	GasSWAPN    = 3   // SWAPN gas cost
-	GasEXCHANGE = 3   // EXCHANGE gas cost
+	GasDUPN     = 3 // DUPN gas cost
+	GasSWAPN    = 3 // SWAPN gas cost
+	GasEXCHANGE = 3 // EXCHANGE gas cost
 
 	// Contract creation
 	GasEOFCREATE      = 32000 // EOFCREATE gas cost (same as CREATE)
@@ -67,7 +67,7 @@ const (
 
 // opRJUMP implements RJUMP (0xE0) - unconditional relative jump
 func opRJUMP(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	offset := int16(binary.BigEndian.Uint16(code[*pc+1:]))
 	*pc = uint64(int64(*pc) + 3 + int64(offset) - 1) // -1 because pc is incremented after
 	return nil, nil
@@ -77,7 +77,7 @@ func opRJUMP(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]by
 func opRJUMPI(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	condition := scope.Stack.Pop()
 	if !condition.IsZero() {
-		code := scope.Contract.Code
+		code := scope.Contract.sectionCode()
 		offset := int16(binary.BigEndian.Uint16(code[*pc+1:]))
 		*pc = uint64(int64(*pc) + 3 + int64(offset) - 1)
 	} else {
@@ -88,7 +88,7 @@ func opRJUMPI(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]b
 
 // opRJUMPV implements RJUMPV (0xE2) - jump table (switch)
 func opRJUMPV(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	count := int(code[*pc+1])
 	caseIndex := scope.Stack.Pop()
 
@@ -105,25 +105,28 @@ func opRJUMPV(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]b
 	return nil, nil
 }
 
-// opCALLF implements CALLF (0xE3) - call function
+// opCALLF implements CALLF (0xE3) - call function. The return stack keeps
+// the code section in the upper and the return pc in the lower 16 bits.
 func opCALLF(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	funcIdx := binary.BigEndian.Uint16(code[*pc+1:])
 
-	// Push return address to return stack
-	returnAddr := uint32(*pc + 3)
-	scope.ReturnStack.Push(returnAddr)
-
 	// Get target code section
 	container := scope.Contract.EOFContainer
-	if container == nil || int(funcIdx) >= container.NumCodeSections() {
+	if container == nil || scope.ReturnStack == nil || int(funcIdx) >= container.NumCodeSections() {
 		return nil, ErrEOFInvalidCallF
 	}
+	if len(scope.ReturnStack.Data()) >= eofMaxReturnStackLen {
+		return nil, ErrEOFReturnStackOverflow
+	}
+
+	// Push return address to return stack
+	scope.ReturnStack.Push(uint32(scope.Contract.CodeSection)<<16 | uint32(*pc+3))
 
 	// Update scope to new code section
 	scope.Contract.CodeSection = int(funcIdx)
 	*pc = 0 // Start at beginning of new section
-	*pc-- // Will be incremented by interpreter loop
+	*pc--   // Will be incremented by interpreter loop
 
 	return nil, nil
 }
@@ -135,20 +138,17 @@ func opRETF(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byt
 		return nil, ErrEOFInvalidRetF
 	}
 
-	// Pop return address from return stack
+	// Restore the calling section and position
 	returnAddr := scope.ReturnStack.Pop()
-
-	// Restore to previous code section
-	// The return address encodes both section and position
-	scope.Contract.CodeSection = 0 // Main section
-	*pc = uint64(returnAddr) - 1   // Will be incremented
+	scope.Contract.CodeSection = int(returnAddr >> 16)
+	*pc = uint64(returnAddr&0xffff) - 1 // Will be incremented
 
 	return nil, nil
 }
 
 // opJUMPF implements JUMPF (0xE5) - tail call to function
 func opJUMPF(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	funcIdx := binary.BigEndian.Uint16(code[*pc+1:])
 
 	// Get target code section
@@ -190,8 +190,9 @@ func opDATALOAD(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([
 
 // opDATALOADN implements DATALOADN (0xD1) - load 32 bytes with immediate offset
 func opDATALOADN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	offset := binary.BigEndian.Uint16(code[*pc+1:])
+	*pc += 2 // Skip immediate
 
 	container := scope.Contract.EOFContainer
 	if container == nil {
@@ -207,8 +208,6 @@ func opDATALOADN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) (
 
 	value := new(uint256.Int).SetBytes32(data[offset : offset+32])
 	scope.Stack.Push(value)
-
-	*pc += 2 // Skip immediate
 	return nil, nil
 }
 
@@ -255,7 +254,7 @@ func opDATACOPY(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([
 
 // opDUPN implements DUPN (0xE6) - DUP with immediate operand
 func opDUPN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	n := int(code[*pc+1]) + 1 // n is 1-indexed in the opcode
 
 	value := scope.Stack.Back(n - 1)
@@ -267,7 +266,7 @@ func opDUPN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byt
 
 // opSWAPN implements SWAPN (0xE7) - SWAP with immediate operand
 func opSWAPN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	n := int(code[*pc+1]) + 1 // n is 1-indexed
 
 	scope.Stack.Swap(n)
@@ -278,7 +277,7 @@ func opSWAPN(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]by
 
 // opEXCHANGE implements EXCHANGE (0xE8) - exchange two stack items
 func opEXCHANGE(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	imm := code[*pc+1]
 	n := int((imm >> 4) + 1)
 	m := int((imm & 0x0f) + 1)
@@ -299,7 +298,7 @@ func opEXCHANGE(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([
 func opEOFCREATE(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	// This is a simplified implementation
 	// Full implementation requires contract creation logic
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	_ = code[*pc+1] // Container index
 
 	// Push failure (0) for now - full implementation needed
@@ -311,7 +310,7 @@ func opEOFCREATE(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) (
 
 // opRETURNCONTRACT implements RETURNCONTRACT (0xEE) - return new contract from initcode
 func opRETURNCONTRACT(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
-	code := scope.Contract.Code
+	code := scope.Contract.sectionCode()
 	_ = code[*pc+1] // Container index
 
 	// Implementation requires integration with contract creation
@@ -496,5 +495,16 @@ func newOsakaInstructionSet() JumpTable {
 func init() {
 	// Register EOF enabler
 	activators[3540] = enableEOF // EIP-3540: EOF
-}
 
+	// Name the EOF opcodes for tracers and EOF validation
+	for op, name := range map[OpCode]string{
+		RJUMP: "RJUMP", RJUMPI: "RJUMPI", RJUMPV: "RJUMPV",
+		CALLF: "CALLF", RETF: "RETF", JUMPF: "JUMPF",
+		DATALOAD: "DATALOAD", DATALOADN: "DATALOADN", DATASIZE: "DATASIZE", DATACOPY: "DATACOPY",
+		EOFCREATE: "EOFCREATE", RETURNCONTRACT: "RETURNCONTRACT", RETURNDATALOAD: "RETURNDATALOAD",
+		DUPN: "DUPN", SWAPN: "SWAPN", EXCHANGE: "EXCHANGE",
+	} {
+		opCodeToString[op] = name
+		stringToOp[name] = op
+	}
+}
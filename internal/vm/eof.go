@@ -51,12 +51,22 @@ const (
 	EOFFormatByte = 0xEF
 )
 
-// Section type identifiers
+// Section kinds, in the order they appear in the header
 const (
-	EOFSectionTypeCode    = 0x01 // Code section
-	EOFSectionContainer   = 0x02 // Container section (for EOFCREATE)
-	EOFSectionData        = 0x03 // Data section
-	EOFSectionTerminator  = 0x00 // Section header terminator
+	EOFSectionTerminator = 0x00 // Section header terminator
+	EOFSectionType       = 0x01 // Type section
+	EOFSectionCode       = 0x02 // Code sections
+	EOFSectionContainer  = 0x03 // Container sections (for EOFCREATE)
+	EOFSectionData       = 0x04 // Data section
+)
+
+// EOF limits
+const (
+	eofMaxCodeSections   = 1024
+	eofMaxContainers     = 256
+	eofMaxIO             = 0x7F
+	eofMaxStackHeight    = 1023
+	eofMaxReturnStackLen = 1024
 )
 
 // =============================================================================
@@ -65,18 +75,18 @@ const (
 
 // EOFHeader represents the header of an EOF container
 type EOFHeader struct {
-	Version       uint8            // EOF version
-	TypeSize      uint16           // Size of type section
-	CodeSizes     []uint16         // Sizes of code sections
-	ContainerSizes []uint16        // Sizes of container sections
-	DataSize      uint16           // Size of data section
-	Types         []EOFTypeSection // Type information for each code section
+	Version        uint8            // EOF version
+	TypeSize       uint16           // Size of type section
+	CodeSizes      []uint16         // Sizes of code sections
+	ContainerSizes []uint16         // Sizes of container sections
+	DataSize       uint16           // Size of data section
+	Types          []EOFTypeSection // Type information for each code section
 }
 
 // EOFTypeSection represents type information for a code section
 type EOFTypeSection struct {
-	Inputs      uint8  // Number of stack inputs
-	Outputs     uint8  // Number of stack outputs (0x80 = non-returning)
+	Inputs         uint8  // Number of stack inputs
+	Outputs        uint8  // Number of stack outputs (0x80 = non-returning)
 	MaxStackHeight uint16 // Maximum stack height during execution
 }
 
@@ -117,8 +127,8 @@ const (
 	RETURNDATALOAD OpCode = 0xF7 // Load 32 bytes from return data
 
 	// EIP-663: Unlimited SWAP and DUP
-	DUPN  OpCode = 0xE6 // DUP with immediate operand
-	SWAPN OpCode = 0xE7 // SWAP with immediate operand
+	DUPN     OpCode = 0xE6 // DUP with immediate operand
+	SWAPN    OpCode = 0xE7 // SWAP with immediate operand
 	EXCHANGE OpCode = 0xE8 // Exchange two stack items
 )
 
@@ -131,30 +141,36 @@ const NonReturningFunction = 0x80
 
 // EOF validation errors
 var (
-	ErrEOFInvalidMagic           = errors.New("invalid EOF magic")
-	ErrEOFInvalidVersion         = errors.New("invalid EOF version")
-	ErrEOFInvalidSectionKind     = errors.New("invalid section kind")
-	ErrEOFMissingTypeSection     = errors.New("missing type section")
-	ErrEOFMissingCodeSection     = errors.New("missing code section")
-	ErrEOFMissingDataSection     = errors.New("missing data section")
-	ErrEOFMissingTerminator      = errors.New("missing header terminator")
-	ErrEOFZeroSectionSize        = errors.New("zero section size")
-	ErrEOFInvalidTypeSize        = errors.New("invalid type section size")
-	ErrEOFTooManyInputs          = errors.New("too many inputs")
-	ErrEOFTooManyOutputs         = errors.New("too many outputs")
-	ErrEOFInvalidMaxStackHeight  = errors.New("invalid max stack height")
-	ErrEOFInvalidFirstCode       = errors.New("first code section must have 0 inputs and non-zero outputs")
-	ErrEOFUndefinedInstruction   = errors.New("undefined instruction")
-	ErrEOFTruncatedInstruction   = errors.New("truncated instruction")
-	ErrEOFInvalidJumpDest        = errors.New("invalid jump destination")
-	ErrEOFInvalidRJUMPVCount     = errors.New("invalid RJUMPV count")
-	ErrEOFStackUnderflow         = errors.New("stack underflow")
-	ErrEOFStackOverflow          = errors.New("stack overflow")
-	ErrEOFInvalidCallF           = errors.New("invalid CALLF target")
-	ErrEOFInvalidRetF            = errors.New("invalid RETF in non-returning function")
-	ErrEOFUnreachableCode        = errors.New("unreachable code")
-	ErrEOFInvalidContainer       = errors.New("invalid container")
-	ErrEOFInvalidDataOffset      = errors.New("invalid data offset")
+	ErrEOFInvalidMagic          = errors.New("invalid EOF magic")
+	ErrEOFInvalidVersion        = errors.New("invalid EOF version")
+	ErrEOFInvalidSectionKind    = errors.New("invalid section kind")
+	ErrEOFMissingTypeSection    = errors.New("missing type section")
+	ErrEOFMissingCodeSection    = errors.New("missing code section")
+	ErrEOFMissingDataSection    = errors.New("missing data section")
+	ErrEOFMissingTerminator     = errors.New("missing header terminator")
+	ErrEOFZeroSectionSize       = errors.New("zero section size")
+	ErrEOFInvalidTypeSize       = errors.New("invalid type section size")
+	ErrEOFTooManyInputs         = errors.New("too many inputs")
+	ErrEOFTooManyOutputs        = errors.New("too many outputs")
+	ErrEOFInvalidMaxStackHeight = errors.New("invalid max stack height")
+	ErrEOFInvalidFirstCode      = errors.New("first code section must have 0 inputs and non-zero outputs")
+	ErrEOFUndefinedInstruction  = errors.New("undefined instruction")
+	ErrEOFTruncatedInstruction  = errors.New("truncated instruction")
+	ErrEOFInvalidJumpDest       = errors.New("invalid jump destination")
+	ErrEOFInvalidRJUMPVCount    = errors.New("invalid RJUMPV count")
+	ErrEOFStackUnderflow        = errors.New("stack underflow")
+	ErrEOFStackOverflow         = errors.New("stack overflow")
+	ErrEOFInvalidCallF          = errors.New("invalid CALLF target")
+	ErrEOFInvalidRetF           = errors.New("invalid RETF in non-returning function")
+	ErrEOFUnreachableCode       = errors.New("unreachable code")
+	ErrEOFInvalidContainer      = errors.New("invalid container")
+	ErrEOFInvalidDataOffset     = errors.New("invalid data offset")
+	ErrEOFInvalidSectionSize    = errors.New("invalid section size")
+	ErrEOFTooManySections       = errors.New("too many sections")
+	ErrEOFTrailingBytes         = errors.New("trailing bytes after data section")
+	ErrEOFMissingTerminating    = errors.New("code section does not end with a terminating instruction")
+	ErrEOFInvalidContainerIndex = errors.New("invalid container index")
+	ErrEOFReturnStackOverflow   = errors.New("return stack overflow")
 )
 
 // IsEOF checks if the code starts with EOF magic bytes
@@ -172,145 +188,133 @@ func HasEOFMagic(code []byte) bool {
 	return len(code) >= 2 && binary.BigEndian.Uint16(code[:2]) == EOFMagic
 }
 
-// ParseEOF parses an EOF container and validates its structure
+// ParseEOF parses an EOF container and validates its structure. The header
+// lists the type, code, optional container and data sections in this order.
 func ParseEOF(code []byte) (*EOFContainer, error) {
-	if len(code) < 7 {
+	if len(code) < 3 {
 		return nil, ErrEOFInvalidMagic
 	}
-
-	// Check magic
-	if code[0] != EOFFormatByte || code[1] != 0x00 {
+	if !HasEOFMagic(code) {
 		return nil, ErrEOFInvalidMagic
 	}
-
-	// Check version
 	if code[2] != EOFVersion1 {
 		return nil, ErrEOFInvalidVersion
 	}
+	header := &EOFHeader{Version: code[2]}
+	pos := 3
 
-	container := &EOFContainer{
-		Header: &EOFHeader{
-			Version: code[2],
-		},
+	// Type section
+	typeSize, pos, err := parseSectionSize(code, pos, EOFSectionType, ErrEOFMissingTypeSection)
+	if err != nil {
+		return nil, err
 	}
+	header.TypeSize = typeSize
 
-	pos := 3
+	// Code sections
+	if header.CodeSizes, pos, err = parseSectionList(code, pos, EOFSectionCode, eofMaxCodeSections, ErrEOFMissingCodeSection); err != nil {
+		return nil, err
+	}
 
-	// Parse sections
-	for pos < len(code) {
-		if pos >= len(code) {
-			return nil, ErrEOFMissingTerminator
+	// Optional container sections
+	if pos < len(code) && code[pos] == EOFSectionContainer {
+		if header.ContainerSizes, pos, err = parseSectionList(code, pos, EOFSectionContainer, eofMaxContainers, ErrEOFInvalidContainer); err != nil {
+			return nil, err
 		}
+	}
 
-		sectionKind := code[pos]
-		pos++
-
-		switch sectionKind {
-		case EOFSectionTerminator:
-			// End of header
-			return parseEOFBody(code, pos, container)
-
-		case EOFSectionTypeCode:
-			// Type section - just read size for now
-			if pos+2 > len(code) {
-				return nil, ErrEOFMissingTypeSection
-			}
-			container.Header.TypeSize = binary.BigEndian.Uint16(code[pos:])
-			pos += 2
-
-			// Code sections follow
-			if pos >= len(code) || code[pos] != EOFSectionTypeCode {
-				return nil, ErrEOFMissingCodeSection
-			}
-			pos++
-
-			// Read number of code sections
-			if pos+2 > len(code) {
-				return nil, ErrEOFMissingCodeSection
-			}
-			numCode := binary.BigEndian.Uint16(code[pos:])
-			pos += 2
-
-			// Read code section sizes
-			container.Header.CodeSizes = make([]uint16, numCode)
-			for i := uint16(0); i < numCode; i++ {
-				if pos+2 > len(code) {
-					return nil, ErrEOFMissingCodeSection
-				}
-				container.Header.CodeSizes[i] = binary.BigEndian.Uint16(code[pos:])
-				pos += 2
-				if container.Header.CodeSizes[i] == 0 {
-					return nil, ErrEOFZeroSectionSize
-				}
-			}
+	// Data section, which may be empty
+	if pos >= len(code) || code[pos] != EOFSectionData {
+		return nil, ErrEOFMissingDataSection
+	}
+	if pos+3 > len(code) {
+		return nil, ErrEOFMissingDataSection
+	}
+	header.DataSize = binary.BigEndian.Uint16(code[pos+1:])
+	pos += 3
 
-		case EOFSectionContainer:
-			// Container section (for EOFCREATE)
-			if pos+2 > len(code) {
-				return nil, ErrEOFInvalidContainer
-			}
-			numContainers := binary.BigEndian.Uint16(code[pos:])
-			pos += 2
-
-			container.Header.ContainerSizes = make([]uint16, numContainers)
-			for i := uint16(0); i < numContainers; i++ {
-				if pos+2 > len(code) {
-					return nil, ErrEOFInvalidContainer
-				}
-				container.Header.ContainerSizes[i] = binary.BigEndian.Uint16(code[pos:])
-				pos += 2
-			}
+	if pos >= len(code) || code[pos] != EOFSectionTerminator {
+		return nil, ErrEOFMissingTerminator
+	}
+	return parseEOFBody(code, pos+1, &EOFContainer{Header: header})
+}
 
-		case EOFSectionData:
-			// Data section
-			if pos+2 > len(code) {
-				return nil, ErrEOFMissingDataSection
-			}
-			container.Header.DataSize = binary.BigEndian.Uint16(code[pos:])
-			pos += 2
+// parseSectionSize reads the header entry of a section with a single size.
+func parseSectionSize(code []byte, pos int, kind byte, missing error) (uint16, int, error) {
+	if pos+3 > len(code) || code[pos] != kind {
+		return 0, pos, missing
+	}
+	size := binary.BigEndian.Uint16(code[pos+1:])
+	if size == 0 {
+		return 0, pos, ErrEOFZeroSectionSize
+	}
+	return size, pos + 3, nil
+}
 
-		default:
-			return nil, ErrEOFInvalidSectionKind
+// parseSectionList reads the header entry of a section kind with a list of
+// non-zero sizes.
+func parseSectionList(code []byte, pos int, kind byte, limit int, missing error) ([]uint16, int, error) {
+	if pos+3 > len(code) || code[pos] != kind {
+		return nil, pos, missing
+	}
+	num := int(binary.BigEndian.Uint16(code[pos+1:]))
+	pos += 3
+	if num == 0 {
+		return nil, pos, ErrEOFZeroSectionSize
+	}
+	if num > limit {
+		return nil, pos, ErrEOFTooManySections
+	}
+	if pos+2*num > len(code) {
+		return nil, pos, missing
+	}
+	sizes := make([]uint16, num)
+	for i := range sizes {
+		sizes[i] = binary.BigEndian.Uint16(code[pos:])
+		if sizes[i] == 0 {
+			return nil, pos, ErrEOFZeroSectionSize
 		}
+		pos += 2
 	}
-
-	return nil, ErrEOFMissingTerminator
+	return sizes, pos, nil
 }
 
 // parseEOFBody parses the body sections of an EOF container
 func parseEOFBody(code []byte, pos int, container *EOFContainer) (*EOFContainer, error) {
 	// Parse type section
 	typeSize := int(container.Header.TypeSize)
+	numCodes := len(container.Header.CodeSizes)
+	if typeSize != numCodes*4 {
+		return nil, ErrEOFInvalidTypeSize
+	}
 	if pos+typeSize > len(code) {
 		return nil, ErrEOFMissingTypeSection
 	}
 	container.TypesData = code[pos : pos+typeSize]
 	pos += typeSize
 
-	// Parse type section into structures
-	numCodes := len(container.Header.CodeSizes)
-	if typeSize != numCodes*4 {
-		return nil, ErrEOFInvalidTypeSize
-	}
-
 	container.Header.Types = make([]EOFTypeSection, numCodes)
 	for i := 0; i < numCodes; i++ {
 		offset := i * 4
-		container.Header.Types[i] = EOFTypeSection{
+		typ := EOFTypeSection{
 			Inputs:         container.TypesData[offset],
 			Outputs:        container.TypesData[offset+1],
 			MaxStackHeight: binary.BigEndian.Uint16(container.TypesData[offset+2:]),
 		}
-	}
-
-	// Validate first code section
-	if numCodes > 0 {
-		if container.Header.Types[0].Inputs != 0 {
-			return nil, ErrEOFInvalidFirstCode
+		if typ.Inputs > eofMaxIO {
+			return nil, ErrEOFTooManyInputs
+		}
+		if typ.Outputs > eofMaxIO && typ.Outputs != NonReturningFunction {
+			return nil, ErrEOFTooManyOutputs
 		}
-		if container.Header.Types[0].Outputs == NonReturningFunction {
-			return nil, ErrEOFInvalidFirstCode
+		if typ.MaxStackHeight > eofMaxStackHeight {
+			return nil, ErrEOFInvalidMaxStackHeight
 		}
+		container.Header.Types[i] = typ
+	}
+
+	// The first code section takes no inputs and does not return
+	if first := container.Header.Types[0]; first.Inputs != 0 || first.Outputs != NonReturningFunction {
+		return nil, ErrEOFInvalidFirstCode
 	}
 
 	// Parse code sections
@@ -335,12 +339,13 @@ func parseEOFBody(code []byte, pos int, container *EOFContainer) (*EOFContainer,
 
 	// Parse data section
 	dataSize := int(container.Header.DataSize)
-	if pos+dataSize > len(code) {
-		// Data section can be truncated for deploy containers
-		container.Data = code[pos:]
-	} else {
-		container.Data = code[pos : pos+dataSize]
+	switch {
+	case pos+dataSize > len(code):
+		return nil, ErrEOFInvalidSectionSize
+	case pos+dataSize < len(code):
+		return nil, ErrEOFTrailingBytes
 	}
+	container.Data = code[pos : pos+dataSize]
 
 	return container, nil
 }
@@ -370,16 +375,24 @@ func ValidateEOF(code []byte) error {
 	return nil
 }
 
-// validateCodeSection validates a single code section
+// validateCodeSection validates the instructions of a single code section.
+// Stack heights (EIP-5450) are not verified; the interpreter still checks
+// them at run time.
 func validateCodeSection(code []byte, typeInfo EOFTypeSection, container *EOFContainer) error {
 	if len(code) == 0 {
 		return ErrEOFZeroSectionSize
 	}
 
-	// Validate each instruction
+	var (
+		starts  = make([]bool, len(code)) // instruction boundaries
+		targets []int                     // relative jump destinations
+		last    OpCode
+	)
 	pos := 0
 	for pos < len(code) {
 		op := OpCode(code[pos])
+		starts[pos] = true
+		last = op
 
 		// Check for undefined instructions
 		if !isValidEOFOpcode(op) {
@@ -396,51 +409,63 @@ func validateCodeSection(code []byte, typeInfo EOFTypeSection, container *EOFCon
 		switch op {
 		case RJUMP, RJUMPI:
 			offset := int16(binary.BigEndian.Uint16(code[pos+1:]))
-			target := pos + 3 + int(offset)
-			if target < 0 || target >= len(code) {
-				return ErrEOFInvalidJumpDest
-			}
+			targets = append(targets, pos+3+int(offset))
 
 		case RJUMPV:
-			if pos+1 >= len(code) {
-				return ErrEOFTruncatedInstruction
-			}
 			count := int(code[pos+1])
 			if count == 0 {
 				return ErrEOFInvalidRJUMPVCount
 			}
+			for i := 0; i < count; i++ {
+				offset := int16(binary.BigEndian.Uint16(code[pos+2+2*i:]))
+				targets = append(targets, pos+size+int(offset))
+			}
 
 		case CALLF:
-			if pos+2 >= len(code) {
-				return ErrEOFTruncatedInstruction
-			}
-			funcIdx := binary.BigEndian.Uint16(code[pos+1:])
-			if int(funcIdx) >= len(container.Header.Types) {
+			funcIdx := int(binary.BigEndian.Uint16(code[pos+1:]))
+			if funcIdx >= len(container.Header.Types) || container.Header.Types[funcIdx].Outputs == NonReturningFunction {
 				return ErrEOFInvalidCallF
 			}
 
 		case JUMPF:
-			if pos+2 >= len(code) {
-				return ErrEOFTruncatedInstruction
-			}
 			funcIdx := binary.BigEndian.Uint16(code[pos+1:])
 			if int(funcIdx) >= len(container.Header.Types) {
 				return ErrEOFInvalidCallF
 			}
 
-		case DATALOADN:
-			if pos+2 >= len(code) {
-				return ErrEOFTruncatedInstruction
+		case RETF:
+			if typeInfo.Outputs == NonReturningFunction {
+				return ErrEOFInvalidRetF
 			}
+
+		case DATALOADN:
 			offset := binary.BigEndian.Uint16(code[pos+1:])
 			if int(offset)+32 > int(container.Header.DataSize) {
 				return ErrEOFInvalidDataOffset
 			}
+
+		case EOFCREATE, RETURNCONTRACT:
+			if int(code[pos+1]) >= container.NumContainers() {
+				return ErrEOFInvalidContainerIndex
+			}
 		}
 
 		pos += size
 	}
 
+	// Relative jumps must land on an instruction, not on an immediate
+	for _, target := range targets {
+		if target < 0 || target >= len(code) || !starts[target] {
+			return ErrEOFInvalidJumpDest
+		}
+	}
+
+	// Execution must not run off the end of the section
+	switch last {
+	case STOP, RETURN, REVERT, INVALID, RETF, JUMPF, RJUMP, RETURNCONTRACT:
+	default:
+		return ErrEOFMissingTerminating
+	}
 	return nil
 }
 
@@ -459,7 +484,7 @@ func isValidEOFOpcode(op OpCode) bool {
 	case GAS:
 		return false // GAS opcode disabled in EOF
 	}
-	return true
+	return opCodeToString[op] != ""
 }
 
 // eofOpcodeSize returns the total size of an instruction including operands
@@ -598,4 +623,3 @@ func (c *EOFContainer) NumCodeSections() int {
 func (c *EOFContainer) NumContainers() int {
 	return len(c.Containers)
 }
-
@@ -17,7 +17,11 @@
 package vm
 
 import (
+	"encoding/hex"
+	"errors"
 	"testing"
+
+	"github.com/n42blockchain/N42/internal/vm/stack"
 )
 
 // =============================================================================
@@ -182,14 +186,17 @@ func TestIsValidEOFOpcode(t *testing.T) {
 // =============================================================================
 
 func TestEOFSectionTypes(t *testing.T) {
-	if EOFSectionTypeCode != 0x01 {
-		t.Errorf("EOFSectionTypeCode = 0x%02x, want 0x01", EOFSectionTypeCode)
+	if EOFSectionType != 0x01 {
+		t.Errorf("EOFSectionType = 0x%02x, want 0x01", EOFSectionType)
+	}
+	if EOFSectionCode != 0x02 {
+		t.Errorf("EOFSectionCode = 0x%02x, want 0x02", EOFSectionCode)
 	}
-	if EOFSectionContainer != 0x02 {
-		t.Errorf("EOFSectionContainer = 0x%02x, want 0x02", EOFSectionContainer)
+	if EOFSectionContainer != 0x03 {
+		t.Errorf("EOFSectionContainer = 0x%02x, want 0x03", EOFSectionContainer)
 	}
-	if EOFSectionData != 0x03 {
-		t.Errorf("EOFSectionData = 0x%02x, want 0x03", EOFSectionData)
+	if EOFSectionData != 0x04 {
+		t.Errorf("EOFSectionData = 0x%02x, want 0x04", EOFSectionData)
 	}
 	if EOFSectionTerminator != 0x00 {
 		t.Errorf("EOFSectionTerminator = 0x%02x, want 0x00", EOFSectionTerminator)
@@ -317,6 +324,106 @@ func TestNonReturningFunction(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// EOF Parsing and Validation Tests
+// =============================================================================
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestValidateEOF(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		err  error
+	}{
+		{"invalid only", "ef000101000402000100010400000000800000fe", nil},
+		{"loop", "ef000101000402000100030400000000800000e0fffd", nil},
+		{"data", "ef000101000402000100040400200000800001d1000000" + "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff", nil},
+		{"function", "ef0001" + "010008" + "0200020004" + "0001" + "040000" + "00" + "00800001" + "00000000" + "e3000100" + "e4", nil},
+		{"wrong magic", "ef010101000402000100010400000000800000fe", ErrEOFInvalidMagic},
+		{"wrong version", "ef000201000402000100010400000000800000fe", ErrEOFInvalidVersion},
+		{"no code section", "ef000101000404000000", ErrEOFMissingCodeSection},
+		{"no terminator", "ef000101000402000100010400000100800000fe", ErrEOFMissingTerminator},
+		{"returning first section", "ef000101000402000100010400000000000000fe", ErrEOFInvalidFirstCode},
+		{"truncated data", "ef000101000402000100010400020000800000fe00", ErrEOFInvalidSectionSize},
+		{"trailing bytes", "ef000101000402000100010400000000800000fe00", ErrEOFTrailingBytes},
+		{"legacy jump", "ef00010100040200010001040000000080000056", ErrEOFUndefinedInstruction},
+		{"no terminating instruction", "ef00010100040200010001040000000080000001", ErrEOFMissingTerminating},
+		{"jump into immediate", "ef000101000402000100040400000000800000e0fffe00", ErrEOFInvalidJumpDest},
+		{"data offset out of range", "ef000101000402000100040400000000800001d1000000", ErrEOFInvalidDataOffset},
+		{"retf in main section", "ef000101000402000100010400000000800000e4", ErrEOFInvalidRetF},
+		{"call to non-returning", "ef0001" + "010008" + "0200020004" + "0001" + "040000" + "00" + "00800001" + "00800000" + "e3000100" + "e4", ErrEOFInvalidCallF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEOF(mustDecodeHex(t, tt.code))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ValidateEOF() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseEOFSections(t *testing.T) {
+	code := mustDecodeHex(t, "ef0001"+"010008"+"0200020004"+"0001"+"030001000a"+"040002"+"00"+
+		"00800001"+"00000000"+"e3000100"+"e4"+"ef000101000402000100"+"cdef")
+	container, err := ParseEOF(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if container.NumCodeSections() != 2 || container.NumContainers() != 1 || container.DataSize() != 2 {
+		t.Fatalf("got %d code sections, %d containers and %d bytes of data",
+			container.NumCodeSections(), container.NumContainers(), container.DataSize())
+	}
+	if info := container.GetTypeInfo(0); info.Outputs != NonReturningFunction || info.MaxStackHeight != 1 {
+		t.Errorf("unexpected type of section 0: %+v", info)
+	}
+	if got := hex.EncodeToString(container.GetCodeSection(1)); got != "e4" {
+		t.Errorf("code section 1 = %s, want e4", got)
+	}
+	if got := hex.EncodeToString(container.GetData()); got != "cdef" {
+		t.Errorf("data = %s, want cdef", got)
+	}
+}
+
+func TestEOFCallFRetF(t *testing.T) {
+	code := mustDecodeHex(t, "ef0001"+"010008"+"0200020004"+"0001"+"040000"+"00"+"00800001"+"00000000"+"e3000100"+"e4")
+	container, err := ParseEOF(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := &Contract{Code: code, EOFContainer: container}
+	scope := &ScopeContext{Stack: stack.New(), Memory: NewMemory(), Contract: contract, ReturnStack: stack.NewReturnStack()}
+	defer stack.ReturnRStack(scope.ReturnStack)
+
+	// CALLF 1 at the start of section 0
+	pc := uint64(0)
+	if _, err := opCALLF(&pc, nil, scope); err != nil {
+		t.Fatal(err)
+	}
+	pc++
+	if contract.CodeSection != 1 || pc != 0 || contract.GetOp(pc) != RETF {
+		t.Fatalf("after CALLF: section %d pc %d op %v", contract.CodeSection, pc, contract.GetOp(pc))
+	}
+	if _, err := opRETF(&pc, nil, scope); err != nil {
+		t.Fatal(err)
+	}
+	pc++
+	if contract.CodeSection != 0 || pc != 3 || contract.GetOp(pc) != STOP {
+		t.Errorf("after RETF: section %d pc %d, want section 0 pc 3", contract.CodeSection, pc)
+	}
+	if _, err := opRETF(&pc, nil, scope); err != ErrEOFInvalidRetF {
+		t.Errorf("RETF on empty return stack = %v, want %v", err, ErrEOFInvalidRetF)
+	}
+}
+
 // =============================================================================
 // Benchmarks
 // =============================================================================
@@ -351,4 +458,3 @@ func BenchmarkIsValidEOFOpcode(b *testing.B) {
 		isValidEOFOpcode(ADD)
 	}
 }
-
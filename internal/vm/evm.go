@@ -80,8 +80,10 @@ func (evm *EVM) precompileLegacy(addr types.Address) (PrecompiledContract, bool)
 	default:
 		precompiles = PrecompiledContractsHomestead
 	}
-	p, ok := precompiles[addr]
-	return p, ok
+	if p, ok := precompiles[addr]; ok {
+		return p, true
+	}
+	return customPrecompileAt(evm.chainRules, addr)
 }
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
@@ -223,8 +225,16 @@ func (evm *EVM) call(typ OpCode, caller ContractRef, addr types.Address, input [
 	}
 	p, isPrecompile := evm.precompile(addr)
 	var code []byte
+	codeAddr := addr
 	if !isPrecompile {
 		code = evm.intraBlockState.GetCode(addr)
+		// EIP-7702: a delegated account runs the code of its target
+		if evm.chainRules.IsPectra {
+			if target, ok := ParseDelegation(code); ok {
+				codeAddr = target
+				code = evm.intraBlockState.GetCode(target)
+			}
+		}
 	}
 
 	snapshot := evm.intraBlockState.Snapshot()
@@ -290,7 +300,7 @@ func (evm *EVM) call(typ OpCode, caller ContractRef, addr types.Address, input [
 		addrCopy := addr
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
-		codeHash := evm.intraBlockState.GetCodeHash(addrCopy)
+		codeHash := evm.intraBlockState.GetCodeHash(codeAddr)
 		var contract *Contract
 		if typ == CALLCODE {
 			contract = NewContract(caller, AccountRef(caller.Address()), value, gas, evm.config.SkipAnalysis)
@@ -437,7 +447,15 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, address, gas, nil
 	}
 
-	ret, err = run(evm, contract, nil, false)
+	// Once EOF is active, EOF initcode must be a valid container
+	if evm.chainRules.IsOsaka && HasEOFMagic(codeAndHash.code) {
+		if ValidateEOF(codeAndHash.code) != nil {
+			err = ErrInvalidCode
+		}
+	}
+	if err == nil {
+		ret, err = run(evm, contract, nil, false)
+	}
 
 	// EIP-170: Contract code size limit
 	if err == nil && evm.chainRules.IsSpuriousDragon && len(ret) > params.MaxCodeSize {
@@ -448,9 +466,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		}
 	}
 
-	// Reject code starting with 0xEF if EIP-3541 is enabled.
+	// Reject code starting with 0xEF if EIP-3541 is enabled, unless it is a
+	// valid EOF container and EOF is active.
 	if err == nil && evm.chainRules.IsLondon && len(ret) >= 1 && ret[0] == 0xEF {
-		err = ErrInvalidCode
+		if !evm.chainRules.IsOsaka || !HasEOFMagic(ret) || ValidateEOF(ret) != nil {
+			err = ErrInvalidCode
+		}
 	}
 	// if the contract creation ran successfully and no errors were returned
 	// calculate the gas required to store the code. If the code could not
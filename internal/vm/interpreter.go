@@ -90,6 +90,7 @@ type keccakState interface {
 type EVMInterpreter struct {
 	*VM
 	jt    *JumpTable // EVM instruction table
+	eofJt *JumpTable // instruction table for EOF contracts, nil before Osaka
 	depth int
 }
 
@@ -120,10 +121,11 @@ func copyJumpTable(jt *JumpTable) *JumpTable {
 
 // NewEVMInterpreter returns a new instance of the Interpreter.
 func NewEVMInterpreter(evm VMInterpreter, cfg Config) *EVMInterpreter {
-	var jt *JumpTable
+	var jt, eofJt *JumpTable
 	switch {
 	case evm.ChainRules().IsOsaka:
-		jt = &osakaInstructionSet
+		// EOF opcodes are only valid inside EOF containers
+		jt, eofJt = &pectraInstructionSet, &osakaInstructionSet
 	case evm.ChainRules().IsPectra:
 		jt = &pectraInstructionSet
 	case evm.ChainRules().IsPrague:
@@ -167,7 +169,8 @@ func NewEVMInterpreter(evm VMInterpreter, cfg Config) *EVMInterpreter {
 			evm: evm,
 			cfg: cfg,
 		},
-		jt: jt,
+		jt:    jt,
+		eofJt: eofJt,
 	}
 }
 
@@ -200,6 +203,18 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	// as every returning call will return new data anyway.
 	in.returnData = nil
 
+	jt := in.jt
+	if in.eofJt != nil && HasEOFMagic(contract.Code) {
+		if contract.EOFContainer == nil {
+			container, err := ParseEOF(contract.Code)
+			if err != nil {
+				return nil, ErrInvalidCode
+			}
+			contract.EOFContainer = container
+		}
+		jt = in.eofJt
+	}
+
 	var (
 		op          OpCode // current opcode
 		mem         = pool.Get().(*Memory)
@@ -227,6 +242,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	mem.Reset()
 	defer pool.Put(mem)
 	defer stack.ReturnNormalStack(locStack)
+	if contract.EOFContainer != nil {
+		callContext.ReturnStack = stack.NewReturnStack()
+		defer stack.ReturnRStack(callContext.ReturnStack)
+	}
 	contract.Input = input
 
 	if in.cfg.Debug {
@@ -257,7 +276,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(_pc)
-		operation := in.jt[op]
+		operation := jt[op]
 		cost = operation.constantGas // For tracing
 		// Validate stack
 		if sLen := locStack.Len(); sLen < operation.numPop {
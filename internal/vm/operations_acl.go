@@ -179,21 +179,43 @@ func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
 				return 0, ErrOutOfGas
 			}
 		}
+		// EIP-7702: the code of a delegated callee is loaded from the target,
+		// which is charged like any other account access
+		var delegationCost uint64
+		if evm.ChainRules().IsPectra {
+			if target, ok := ParseDelegation(evm.IntraBlockState().GetCode(addr)); ok {
+				delegationCost = params.WarmStorageReadCostEIP2929
+				if !evm.IntraBlockState().AddressInAccessList(target) {
+					evm.IntraBlockState().AddAddressToAccessList(target)
+					delegationCost = params.ColdAccountAccessCostEIP2929
+				}
+				if !contract.UseGas(delegationCost) {
+					return 0, ErrOutOfGas
+				}
+			}
+		}
 		// Now call the old calculator, which takes into account
 		// - create new account
 		// - transfer value
 		// - memory expansion
 		// - 63/64ths rule
 		gas, err := oldCalculator(evm, contract, stack, mem, memorySize)
-		if warmAccess || err != nil {
+		if err != nil {
 			return gas, err
 		}
+		if !warmAccess {
+			delegationCost += coldCost
+		}
 		// In case of a cold access, we temporarily add the cold charge back, and also
 		// add it to the returned gas. By adding it to the return, it will be charged
 		// outside of this function, as part of the dynamic gas, and that will make it
 		// also become correctly reported to tracers.
-		contract.Gas += coldCost
-		return gas + coldCost, nil
+		contract.Gas += delegationCost
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, delegationCost); overflow {
+			return 0, ErrGasUintOverflow
+		}
+		return gas, nil
 	}
 }
 
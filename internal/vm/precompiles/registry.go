@@ -123,6 +123,13 @@ func (r *Registry) registerForRules(rules *params.Rules) {
 		r.registerAt(p256Addr, NewP256Verify())
 	}
 
+	// Custom precompiles of the chain config (see vm.RegisterPrecompile)
+	for addr, name := range rules.CustomPrecompiles {
+		if p, ok := vm.CustomPrecompile(name); ok {
+			r.registerAt(addr, p)
+		}
+	}
+
 	// Build sorted address list
 	r.addresses = make([]types.Address, 0, len(r.contracts))
 	for addr := range r.contracts {
@@ -0,0 +1,86 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/n42blockchain/N42/params"
+)
+
+// fork enables one hard fork in a chain config
+type fork struct {
+	name    string
+	aliases []string // names used for the fork by the Ethereum state tests
+	enable  func(c *params.ChainConfig)
+}
+
+// forks lists the hard forks ForkConfig knows, oldest first.
+var forks = []fork{
+	{name: "Frontier", enable: func(c *params.ChainConfig) {}},
+	{name: "Homestead", enable: func(c *params.ChainConfig) { c.HomesteadBlock = new(big.Int) }},
+	{name: "TangerineWhistle", aliases: []string{"EIP150"}, enable: func(c *params.ChainConfig) { c.TangerineWhistleBlock = new(big.Int) }},
+	{name: "SpuriousDragon", aliases: []string{"EIP158"}, enable: func(c *params.ChainConfig) { c.SpuriousDragonBlock = new(big.Int) }},
+	{name: "Byzantium", enable: func(c *params.ChainConfig) { c.ByzantiumBlock = new(big.Int) }},
+	{name: "Constantinople", enable: func(c *params.ChainConfig) { c.ConstantinopleBlock = new(big.Int) }},
+	{name: "Petersburg", aliases: []string{"ConstantinopleFix"}, enable: func(c *params.ChainConfig) { c.PetersburgBlock = new(big.Int) }},
+	{name: "Istanbul", enable: func(c *params.ChainConfig) {
+		c.IstanbulBlock = new(big.Int)
+		c.MuirGlacierBlock = new(big.Int)
+	}},
+	{name: "Berlin", enable: func(c *params.ChainConfig) { c.BerlinBlock = new(big.Int) }},
+	{name: "London", aliases: []string{"Merge", "Paris"}, enable: func(c *params.ChainConfig) {
+		c.LondonBlock = new(big.Int)
+		c.ArrowGlacierBlock = new(big.Int)
+		c.GrayGlacierBlock = new(big.Int)
+	}},
+	{name: "Shanghai", enable: func(c *params.ChainConfig) { c.ShanghaiBlock = new(big.Int) }},
+	{name: "Cancun", enable: func(c *params.ChainConfig) { c.CancunBlock = new(big.Int) }},
+	{name: "Prague", enable: func(c *params.ChainConfig) { c.PragueTime = new(big.Int) }},
+	{name: "Pectra", enable: func(c *params.ChainConfig) { c.PectraTime = new(big.Int) }},
+	{name: "Osaka", enable: func(c *params.ChainConfig) { c.OsakaTime = new(big.Int) }},
+}
+
+// Forks returns the fork names accepted by ForkConfig, oldest first.
+func Forks() []string {
+	names := make([]string, len(forks))
+	for i, f := range forks {
+		names[i] = f.name
+	}
+	return names
+}
+
+// ForkConfig returns a chain config with chain id 1 in which every fork up
+// to and including the named one is active from genesis. Names are case
+// insensitive and the aliases of the Ethereum state tests are accepted.
+func ForkConfig(name string) (*params.ChainConfig, error) {
+	config := &params.ChainConfig{ChainID: big.NewInt(1)}
+	for _, f := range forks {
+		f.enable(config)
+		if strings.EqualFold(f.name, name) {
+			return config, nil
+		}
+		for _, alias := range f.aliases {
+			if strings.EqualFold(alias, name) {
+				return config, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown fork %q (want one of %s)", name, strings.Join(Forks(), ", "))
+}
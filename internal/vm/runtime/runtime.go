@@ -92,6 +92,9 @@ func setDefaults(cfg *Config) {
 	if cfg.BlockNumber == nil {
 		cfg.BlockNumber = new(big.Int)
 	}
+	if cfg.BaseFee == nil {
+		cfg.BaseFee = uint256.NewInt(params.InitialBaseFee)
+	}
 	if cfg.GetHashFn == nil {
 		cfg.GetHashFn = func(n uint64) types.Hash {
 			return types.BytesToHash(crypto.Keccak256([]byte(new(big.Int).SetUint64(n).String())))
@@ -0,0 +1,348 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/hash"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/math"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/commitment"
+	"github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// StateTest is a state test fixture in the format of the Ethereum tests
+// repository. A fixture file maps test names to state tests.
+//
+// The post state root of a subtest is computed with the hex patricia trie of
+// the state commitment, which hashes the state as Ethereum does.
+type StateTest struct {
+	Env  stEnv                       `json:"env"`
+	Pre  map[types.Address]stAccount `json:"pre"`
+	Tx   stTransaction               `json:"transaction"`
+	Post map[string][]stPostState    `json:"post"`
+}
+
+type stEnv struct {
+	Coinbase   types.Address         `json:"currentCoinbase"`
+	Difficulty *math.HexOrDecimal256 `json:"currentDifficulty"`
+	GasLimit   math.HexOrDecimal64   `json:"currentGasLimit"`
+	Number     math.HexOrDecimal64   `json:"currentNumber"`
+	Timestamp  math.HexOrDecimal64   `json:"currentTimestamp"`
+	BaseFee    *math.HexOrDecimal256 `json:"currentBaseFee"`
+}
+
+type stAccount struct {
+	Balance *math.HexOrDecimal256 `json:"balance"`
+	Nonce   math.HexOrDecimal64   `json:"nonce"`
+	Code    hexutil.Bytes         `json:"code"`
+	Storage map[string]string     `json:"storage"`
+}
+
+type stTransaction struct {
+	Data                 []string                 `json:"data"`
+	GasLimit             []math.HexOrDecimal64    `json:"gasLimit"`
+	Value                []string                 `json:"value"`
+	AccessLists          []transaction.AccessList `json:"accessLists"`
+	GasPrice             *math.HexOrDecimal256    `json:"gasPrice"`
+	MaxFeePerGas         *math.HexOrDecimal256    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *math.HexOrDecimal256    `json:"maxPriorityFeePerGas"`
+	Nonce                math.HexOrDecimal64      `json:"nonce"`
+	To                   string                   `json:"to"` // empty for contract creation
+	Sender               *types.Address           `json:"sender"`
+	SecretKey            hexutil.Bytes            `json:"secretKey"`
+}
+
+type stPostState struct {
+	Root            types.Hash `json:"hash"`
+	Logs            types.Hash `json:"logs"`
+	ExpectException string     `json:"expectException"`
+	Indexes         struct {
+		Data  int `json:"data"`
+		Gas   int `json:"gas"`
+		Value int `json:"value"`
+	} `json:"indexes"`
+}
+
+// StateSubtest selects one post state of a state test.
+type StateSubtest struct {
+	Fork  string
+	Index int
+}
+
+// Subtests returns the post states of the test for the forks ForkConfig
+// knows, oldest fork first.
+func (t *StateTest) Subtests() []StateSubtest {
+	var subtests []StateSubtest
+	for _, f := range forks {
+		names := append([]string{f.name}, f.aliases...)
+		for _, name := range names {
+			for i := range t.Post[name] {
+				subtests = append(subtests, StateSubtest{Fork: name, Index: i})
+			}
+		}
+	}
+	return subtests
+}
+
+// Run executes the transaction of subtest against the pre state of the test.
+// It returns the execution result, or nil if the transaction was rejected as
+// the fixture expects. An error is returned if the transaction is rejected
+// although it should be accepted or vice versa, or if the post state root
+// or the hash of the logs differ from the fixture.
+func (t *StateTest) Run(subtest StateSubtest, vmconfig vm.Config) (*internal.ExecutionResult, error) {
+	config, err := ForkConfig(subtest.Fork)
+	if err != nil {
+		return nil, err
+	}
+	if subtest.Index >= len(t.Post[subtest.Fork]) {
+		return nil, fmt.Errorf("no post state %d for fork %s", subtest.Index, subtest.Fork)
+	}
+	post := t.Post[subtest.Fork][subtest.Index]
+
+	db := newDB()
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	number := uint64(t.Env.Number)
+	rules := config.Rules(number)
+	pre := state.New(state.NewPlainStateReader(tx))
+	if err := t.writePre(pre); err != nil {
+		return nil, err
+	}
+	if err := pre.CommitBlock(rules, state.NewPlainStateWriterNoHistory(tx)); err != nil {
+		return nil, err
+	}
+	ibs := state.New(state.NewPlainStateReader(tx))
+
+	msg, err := t.Tx.toMessage(post, t.Env.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{
+		ChainConfig: config,
+		Coinbase:    t.Env.Coinbase,
+		BlockNumber: new(big.Int).SetUint64(number),
+		Time:        new(big.Int).SetUint64(uint64(t.Env.Timestamp)),
+		GasLimit:    uint64(t.Env.GasLimit),
+		Origin:      msg.From(),
+		GasPrice:    msg.GasPrice(),
+		EVMConfig:   vmconfig,
+		State:       ibs,
+	}
+	if t.Env.Difficulty != nil {
+		cfg.Difficulty = (*big.Int)(t.Env.Difficulty)
+	}
+	if t.Env.BaseFee != nil {
+		cfg.BaseFee, _ = uint256.FromBig((*big.Int)(t.Env.BaseFee))
+	}
+	setDefaults(cfg)
+
+	snapshot := ibs.Snapshot()
+	gp := new(common.GasPool).AddGas(cfg.GasLimit)
+	result, err := internal.ApplyMessage(NewEnv(cfg), msg, gp, true, false)
+	switch {
+	case err != nil && post.ExpectException == "":
+		return nil, fmt.Errorf("transaction rejected: %w", err)
+	case err != nil:
+		ibs.RevertToSnapshot(snapshot)
+		result = nil
+	case post.ExpectException != "":
+		return result, fmt.Errorf("transaction accepted, expected exception %s", post.ExpectException)
+	}
+
+	// Touch the coinbase as the fixtures do, so that it is removed if it
+	// ends up empty.
+	ibs.AddBalance(t.Env.Coinbase, new(uint256.Int))
+	if err := ibs.CommitBlock(rules, state.NewPlainStateWriterNoHistory(tx)); err != nil {
+		return nil, err
+	}
+	if post.Root != (types.Hash{}) {
+		root, err := commitment.ComputeRoot(tx, number)
+		if err != nil {
+			return nil, err
+		}
+		if root != post.Root {
+			return result, fmt.Errorf("post state root mismatch: have %v, want %v", root, post.Root)
+		}
+	}
+	if post.Logs != (types.Hash{}) {
+		if logs := logsHash(ibs.Logs()); logs != post.Logs {
+			return result, fmt.Errorf("logs hash mismatch: have %v, want %v", logs, post.Logs)
+		}
+	}
+	return result, nil
+}
+
+// logsHash returns the hash of the consensus fields of logs, as recorded in
+// the fixtures.
+func logsHash(logs []*block.Log) types.Hash {
+	type rlpLog struct {
+		Address types.Address
+		Topics  []types.Hash
+		Data    []byte
+	}
+	enc := make([]rlpLog, len(logs))
+	for i, l := range logs {
+		enc[i] = rlpLog{Address: l.Address, Topics: l.Topics, Data: l.Data}
+	}
+	return hash.RlpHash(enc)
+}
+
+// writePre writes the pre state accounts to ibs
+func (t *StateTest) writePre(ibs *state.IntraBlockState) error {
+	for addr, acc := range t.Pre {
+		ibs.CreateAccount(addr, len(acc.Code) > 0)
+		if acc.Balance != nil {
+			balance, overflow := uint256.FromBig((*big.Int)(acc.Balance))
+			if overflow {
+				return fmt.Errorf("balance of %v overflows", addr)
+			}
+			ibs.SetBalance(addr, balance)
+		}
+		ibs.SetNonce(addr, uint64(acc.Nonce))
+		if len(acc.Code) > 0 {
+			ibs.SetCode(addr, acc.Code)
+		}
+		for k, v := range acc.Storage {
+			key, ok := math.ParseBig256(k)
+			if !ok {
+				return fmt.Errorf("invalid storage key %q of %v", k, addr)
+			}
+			value, ok := math.ParseBig256(v)
+			if !ok {
+				return fmt.Errorf("invalid storage value %q of %v", v, addr)
+			}
+			slot := types.BigToHash(key)
+			val, _ := uint256.FromBig(value)
+			ibs.SetState(addr, &slot, *val)
+		}
+	}
+	return nil
+}
+
+// toMessage builds the message selected by the indexes of post
+func (tx *stTransaction) toMessage(post stPostState, baseFee *math.HexOrDecimal256) (transaction.Message, error) {
+	idx := post.Indexes
+	if idx.Data >= len(tx.Data) || idx.Gas >= len(tx.GasLimit) || idx.Value >= len(tx.Value) {
+		return transaction.Message{}, errors.New("transaction index out of range")
+	}
+
+	var from types.Address
+	switch {
+	case tx.Sender != nil:
+		from = *tx.Sender
+	case len(tx.SecretKey) > 0:
+		key, err := crypto.ToECDSA(tx.SecretKey)
+		if err != nil {
+			return transaction.Message{}, fmt.Errorf("invalid secret key: %w", err)
+		}
+		from = crypto.PubkeyToAddress(key.PublicKey)
+	default:
+		return transaction.Message{}, errors.New("transaction has neither sender nor secret key")
+	}
+
+	var to *types.Address
+	if tx.To != "" {
+		addr := types.HexToAddress(tx.To)
+		to = &addr
+	}
+	data, err := hexutil.Decode(strings.TrimPrefix(tx.Data[idx.Data], ":raw "))
+	if err != nil {
+		return transaction.Message{}, fmt.Errorf("invalid data: %w", err)
+	}
+	value, ok := math.ParseBig256(tx.Value[idx.Value])
+	if !ok {
+		return transaction.Message{}, fmt.Errorf("invalid value %q", tx.Value[idx.Value])
+	}
+	var accessList transaction.AccessList
+	if idx.Data < len(tx.AccessLists) {
+		accessList = tx.AccessLists[idx.Data]
+	}
+
+	// Dynamic fee transactions pay the base fee plus the tip, capped by the
+	// fee cap
+	gasPrice := (*big.Int)(tx.GasPrice)
+	feeCap, tip := gasPrice, gasPrice
+	if tx.MaxFeePerGas != nil {
+		feeCap, tip = (*big.Int)(tx.MaxFeePerGas), (*big.Int)(tx.MaxPriorityFeePerGas)
+		if tip == nil {
+			tip = new(big.Int)
+		}
+		gasPrice = new(big.Int).Set(feeCap)
+		if baseFee != nil {
+			if price := new(big.Int).Add(tip, (*big.Int)(baseFee)); price.Cmp(feeCap) < 0 {
+				gasPrice = price
+			}
+		}
+	}
+	if gasPrice == nil {
+		return transaction.Message{}, errors.New("transaction has no gas price")
+	}
+
+	return transaction.NewMessage(from, to, uint64(tx.Nonce), toUint256(value), uint64(tx.GasLimit[idx.Gas]),
+		toUint256(gasPrice), toUint256(feeCap), toUint256(tip), data, accessList, true, false), nil
+}
+
+func toUint256(b *big.Int) *uint256.Int {
+	v, _ := uint256.FromBig(b)
+	return v
+}
+
+// NewState returns an empty state backed by an in-memory database, along
+// with a function releasing the database.
+func NewState() (*state.IntraBlockState, func(), error) {
+	db := newDB()
+	tx, err := db.BeginRo(context.Background())
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	release := func() {
+		tx.Rollback()
+		db.Close()
+	}
+	return state.New(state.NewPlainStateReader(tx)), release, nil
+}
+
+// newDB returns an empty in-memory chain database
+func newDB() kv.RwDB {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	return memdb.New("")
+}
@@ -0,0 +1,101 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/n42blockchain/N42/internal/vm"
+)
+
+const storeTest = `{
+	"env": {
+		"currentCoinbase": "0x2adc25665018aa1fe0e6bc666dac8fc2697ff9ba",
+		"currentDifficulty": "0x020000",
+		"currentGasLimit": "0x05f5e100",
+		"currentNumber": "0x01",
+		"currentTimestamp": "0x03e8",
+		"currentBaseFee": "0x0a"
+	},
+	"pre": {
+		"0x1000000000000000000000000000000000000000": {
+			"balance": "0x00", "nonce": "0x00", "code": "0x600160005500", "storage": {}
+		},
+		"0xa94f5374fce5edbac8e2a8e5d1c8a56b4b0a1d8f": {
+			"balance": "0x0de0b6b3a7640000", "nonce": "0x00", "code": "0x", "storage": {}
+		}
+	},
+	"transaction": {
+		"data": ["0x"],
+		"gasLimit": ["0x0186a0"],
+		"value": ["0x00"],
+		"gasPrice": "0x0a",
+		"nonce": "0x00",
+		"to": "0x1000000000000000000000000000000000000000",
+		"sender": "0xa94f5374fce5edbac8e2a8e5d1c8a56b4b0a1d8f"
+	},
+	"post": {
+		"Cancun": [
+			{"indexes": {"data": 0, "gas": 0, "value": 0}}
+		],
+		"Berlin": [
+			{"indexes": {"data": 0, "gas": 0, "value": 0}, "expectException": "TR_NoFunds"}
+		]
+	}
+}`
+
+func TestForkConfig(t *testing.T) {
+	config, err := ForkConfig("eip158")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.IsSpuriousDragon(0) || config.IsByzantium(0) {
+		t.Errorf("EIP158 config enables the wrong forks: %v", config)
+	}
+	if config, _ = ForkConfig("Osaka"); !config.IsCancun(0) || !config.IsOsaka(0) {
+		t.Error("Osaka config does not enable all forks")
+	}
+	if _, err := ForkConfig("Atlantis"); err == nil {
+		t.Error("unknown fork accepted")
+	}
+}
+
+func TestStateTestRun(t *testing.T) {
+	var test StateTest
+	if err := json.Unmarshal([]byte(storeTest), &test); err != nil {
+		t.Fatal(err)
+	}
+	subtests := test.Subtests()
+	if len(subtests) != 2 || subtests[0].Fork != "Berlin" || subtests[1].Fork != "Cancun" {
+		t.Fatalf("unexpected subtests %v", subtests)
+	}
+
+	// 21000 intrinsic, two pushes and a cold SSTORE of a new slot
+	result, err := test.Run(subtests[1], vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed() || result.UsedGas != 43106 {
+		t.Errorf("got gas %d, err %v; want 43106", result.UsedGas, result.Err)
+	}
+
+	// The transaction succeeds, so the expected exception is a mismatch
+	if _, err := test.Run(subtests[0], vm.Config{}); err == nil {
+		t.Error("missing exception not reported")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package witness
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/api"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// API serves execution witnesses over RPC.
+type API struct {
+	backend *api.API
+}
+
+// ExecutionWitness re-executes a block and returns its execution witness,
+// in the format verifiers receive for mined blocks.
+func (w *API) ExecutionWitness(ctx context.Context, blockNrOrHash jsonrpc.BlockNumberOrHash) (*state.EntireCode, error) {
+	blk, err := w.backend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, errors.New("block not found")
+	}
+	bc, ok := w.backend.BlockChain().(*internal.BlockChain)
+	if !ok {
+		return nil, errors.New("execution witnesses need a local blockchain")
+	}
+	processor := internal.NewStateProcessor(w.backend.GetChainConfig(), bc, w.backend.Engine())
+
+	var ec *state.EntireCode
+	err = w.backend.Database().View(ctx, func(tx kv.Tx) error {
+		ec, err = Generate(tx, processor, w.backend.Engine(), blk)
+		return err
+	})
+	return ec, err
+}
+
+// APIs returns the RPC services of the witness package.
+func APIs(backend *api.API) []jsonrpc.API {
+	return []jsonrpc.API{{
+		Namespace: "debug",
+		Service:   &API{backend},
+	}}
+}
@@ -14,26 +14,101 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
 
-package main
+// Package witness produces and verifies execution witnesses: the accounts,
+// storage slots, code and headers a block reads, recorded while executing
+// it, which suffice to re-execute the block without the state database.
+// A witness is the same state.EntireCode the miner pushes to verifiers.
+package witness
 
 import (
 	"context"
 	"fmt"
+	"sort"
+	"unsafe"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
 	common2 "github.com/n42blockchain/N42/common"
 	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/transaction"
 	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/consensus"
 	"github.com/n42blockchain/N42/internal/consensus/apos"
 	"github.com/n42blockchain/N42/internal/consensus/misc"
 	"github.com/n42blockchain/N42/internal/vm"
 	"github.com/n42blockchain/N42/modules/ethdb/olddb"
+	"github.com/n42blockchain/N42/modules/rawdb"
 	"github.com/n42blockchain/N42/modules/state"
 	"github.com/n42blockchain/N42/params"
-	"unsafe"
 )
 
-func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
+// Generate executes blk on the state of its parent, as read from tx, and
+// returns the witness recorded during the execution.
+func Generate(tx kv.Tx, processor *internal.StateProcessor, engine consensus.Engine, blk *block.Block) (*state.EntireCode, error) {
+	number := blk.Number64().Uint64()
+	if number == 0 {
+		return nil, fmt.Errorf("the genesis block has no witness")
+	}
+	// Finalize fills in the header, keep the stored one intact.
+	header := block.CopyHeader(blk.Header().(*block.Header))
+	body := blk.Body().(*block.Body)
+	exec := block.NewBlockFromStorage(blk.Hash(), block.CopyHeader(header), body)
+
+	ibs := state.New(state.NewPlainState(tx, number))
+	ibs.BeginWriteSnapshot()
+	ibs.BeginWriteCodes()
+	var (
+		headers []*block.Header
+		seen    = make(map[types.Hash]struct{})
+	)
+	getHeader := func(hash types.Hash, number uint64) *block.Header {
+		h := rawdb.ReadHeader(tx, hash, number)
+		if h != nil {
+			if _, ok := seen[hash]; !ok {
+				seen[hash] = struct{}{}
+				headers = append(headers, h)
+			}
+		}
+		return h
+	}
+	if _, _, _, _, err := processor.Process(exec, ibs, ibs.GetStateReader(), state.NewNoopWriter(), internal.GetHashFn(header, getHeader)); err != nil {
+		return nil, fmt.Errorf("execute block %d: %w", number, err)
+	}
+
+	txs := make([][]byte, len(body.Txs))
+	for i, t := range body.Txs {
+		data, err := t.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		txs[i] = data
+	}
+	codes := make(state.HashCodes, 0)
+	for hash, code := range ibs.CodeHashes() {
+		codes = append(codes, &state.HashCode{Hash: hash, Code: code})
+	}
+	sort.Sort(codes)
+	snap := ibs.Snap()
+	sort.Sort(snap.Items)
+	coinbase, err := engine.Author(header)
+	if err != nil {
+		return nil, err
+	}
+	return &state.EntireCode{
+		CoinBase: coinbase,
+		Entire:   state.Entire{Header: header, Transactions: txs, Snap: snap},
+		Codes:    codes,
+		Headers:  headers,
+		Rewards:  body.Rewards,
+	}, nil
+}
+
+// Verify re-executes the block of a witness from the witness alone and
+// returns the resulting state root. State missing from the witness reads as
+// empty, so an incomplete witness shows as a root differing from the one in
+// the header rather than as an error.
+func Verify(ctx context.Context, chainConfig *params.ChainConfig, msg *state.EntireCode) (types.Hash, error) {
 	codeMap := make(map[types.Hash][]byte)
 	for _, pair := range msg.Codes {
 		codeMap[pair.Hash] = pair.Code
@@ -61,7 +136,7 @@ func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
 	for _, tByte := range msg.Entire.Transactions {
 		tmp := &transaction.Transaction{}
 		if err := tmp.Unmarshal(tByte); nil != err {
-			panic(err)
+			return types.Hash{}, err
 		}
 		txs = append(txs, tmp)
 	}
@@ -84,16 +159,11 @@ func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
 	ibs.SetHeight(blk.Number64().Uint64())
 	ibs.SetGetOneFun(batch.GetOne)
 
-	root, err := checkBlock(getNumberHash, blk, ibs, msg.CoinBase, msg.Rewards)
-	if nil != err {
-		panic(err)
-	}
-	return root
+	return checkBlock(chainConfig, getNumberHash, blk, ibs, msg.CoinBase, msg.Rewards)
 }
 
-func checkBlock(getHashF func(n uint64) types.Hash, blk *block.Block, ibs *state.IntraBlockState, coinbase types.Address, rewards []*block.Reward) (types.Hash, error) {
+func checkBlock(chainConfig *params.ChainConfig, getHashF func(n uint64) types.Hash, blk *block.Block, ibs *state.IntraBlockState, coinbase types.Address, rewards []*block.Reward) (types.Hash, error) {
 	header := blk.Header().(*block.Header)
-	chainConfig := params.MainnetChainConfig
 	if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(blk.Number64().ToBig()) == 0 {
 		misc.ApplyDAOHardFork(ibs)
 	}
@@ -103,14 +173,12 @@ func checkBlock(getHashF func(n uint64) types.Hash, blk *block.Block, ibs *state
 	gp := new(common2.GasPool)
 	gp.AddGas(blk.GasLimit())
 	cfg := vm.Config{}
-	//cfg := vm.Config{Debug: true, Tracer: logger.NewMarkdownLogger(nil, os.Stdout)}
 
 	engine := apos.NewFaker()
 	for i, tx := range blk.Transactions() {
 		ibs.Prepare(tx.Hash(), blk.Hash(), i)
 		_, _, err := internal.ApplyTransaction(chainConfig, getHashF, engine, &coinbase, gp, ibs, noop, header, tx, usedGas, cfg)
 		if err != nil {
-
 			return types.Hash{}, err
 		}
 	}
@@ -43,6 +43,9 @@ var (
 
 	// logManager 管理日志清理
 	logManager *LogManager
+
+	// fileWriter 当前的日志文件输出，重新加载时关闭
+	fileWriter *lumberjack.Logger
 )
 
 type Lvl int
@@ -203,6 +206,7 @@ func Init(nodeConfig conf.NodeConfig, config conf.LoggerConfig) {
 		terminal.SetFormatter(formatter)
 		terminal.SetLevel(lvl)
 		terminal.SetOutput(os.Stdout)
+		swapFileWriter(nil)
 		return
 	}
 
@@ -251,6 +255,7 @@ func Init(nodeConfig conf.NodeConfig, config conf.LoggerConfig) {
 		// 仅输出到文件
 		terminal.SetOutput(lj)
 	}
+	swapFileWriter(lj)
 
 	// 启动日志管理器（如果设置了总大小限制）
 	if config.TotalSizeCap > 0 {
@@ -277,6 +282,40 @@ func Close() {
 	}
 }
 
+// Reload 按新的日志配置重新初始化日志系统，无需重启节点
+func Reload(nodeConfig conf.NodeConfig, config conf.LoggerConfig) error {
+	if _, err := logrus.ParseLevel(config.Level); err != nil {
+		return err
+	}
+	Close()
+	logManager = nil
+	Init(nodeConfig, config)
+	return nil
+}
+
+// SetLevel 在运行时修改日志级别
+func SetLevel(level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	terminal.SetLevel(lvl)
+	return nil
+}
+
+// GetLevel 返回当前日志级别
+func GetLevel() string {
+	return terminal.GetLevel().String()
+}
+
+// swapFileWriter 记录新的日志文件输出并关闭旧的
+func swapFileWriter(lj *lumberjack.Logger) {
+	if fileWriter != nil && fileWriter != lj {
+		fileWriter.Close()
+	}
+	fileWriter = lj
+}
+
 func InitMobileLogger(filepath string, isDebug bool) {
 	if !isDebug {
 		return
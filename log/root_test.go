@@ -140,6 +140,45 @@ func TestInitWithFile(t *testing.T) {
 	t.Log("✓ File logging works")
 }
 
+// TestSetLevel 测试运行时修改日志级别
+func TestSetLevel(t *testing.T) {
+	defer SetLevel(GetLevel())
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	if lvl := GetLevel(); lvl != "debug" {
+		t.Errorf("Expected level debug, got %s", lvl)
+	}
+	if err := SetLevel("loud"); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+	if lvl := GetLevel(); lvl != "debug" {
+		t.Errorf("Invalid level should not change the level, got %s", lvl)
+	}
+}
+
+// TestReload 测试重新加载日志配置
+func TestReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	nodeConfig := conf.NodeConfig{DataDir: tmpDir}
+
+	Init(nodeConfig, conf.LoggerConfig{LogFile: "reload.log", Level: "info", MaxSize: 10})
+	if err := Reload(nodeConfig, conf.LoggerConfig{Level: "loud"}); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+	if err := Reload(nodeConfig, conf.LoggerConfig{Level: "warn", Console: true}); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if lvl := GetLevel(); lvl != "warning" {
+		t.Errorf("Expected level warning, got %s", lvl)
+	}
+	if fileWriter != nil {
+		t.Error("File output should be closed after switching to console only")
+	}
+	Close()
+}
+
 // TestLogOutput 测试各级别日志输出
 func TestLogOutput(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -0,0 +1,258 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dbstat collects table and free-list statistics from an MDBX
+// database through the kv API, without relying on the on-disk file size.
+package dbstat
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/erigontech/mdbx-go/mdbx"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	mdbx2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	log2 "github.com/ledgerwatch/log/v3"
+
+	"github.com/n42blockchain/N42/modules"
+)
+
+// gcDBI is the MDBX garbage collection table which holds the free-list.
+const gcDBI = mdbx.DBI(0)
+
+// TableStat describes the B-tree of a single table.
+type TableStat struct {
+	Name          string `json:"name"`
+	Entries       uint64 `json:"entries"`
+	Depth         uint   `json:"depth"`
+	BranchPages   uint64 `json:"branch_pages"`
+	LeafPages     uint64 `json:"leaf_pages"`
+	OverflowPages uint64 `json:"overflow_pages"`
+	Size          uint64 `json:"size"`
+}
+
+// FreeList summarizes the MDBX garbage collection table.
+type FreeList struct {
+	Records    uint64 `json:"records"`      // GC records, one per retiring transaction
+	FreePages  uint64 `json:"free_pages"`   // Pages available for reuse
+	FreeSize   uint64 `json:"free_size"`    // Bytes available for reuse
+	TreeSize   uint64 `json:"tree_size"`    // Size of the GC B-tree itself
+	Malformed  uint64 `json:"malformed"`    // Records whose page list does not match its length prefix
+	OutOfRange uint64 `json:"out_of_range"` // Free pages beyond the last allocated page
+}
+
+// Stat is a snapshot of an MDBX environment.
+type Stat struct {
+	PageSize   uint64      `json:"page_size"`
+	FileSize   uint64      `json:"file_size"`  // Current size of the data file
+	UsedSize   uint64      `json:"used_size"`  // Allocated pages, including free ones
+	MapSize    uint64      `json:"map_size"`   // Upper bound the data file may grow to
+	TablesSize uint64      `json:"table_size"` // Pages referenced by tables
+	LastTxnID  uint64      `json:"last_txn_id"`
+	Readers    uint        `json:"readers"`
+	Tables     []TableStat `json:"tables"`
+	FreeList   FreeList    `json:"free_list"`
+}
+
+type bucketStater interface {
+	kv.BucketMigratorRO
+	ExistsBucket(string) (bool, error)
+	BucketStat(string) (*mdbx.Stat, error)
+}
+
+// OpenReadonly opens the chain database below datadir without taking the
+// write lock, so it can be inspected next to a running node.
+func OpenReadonly(datadir string) (kv.RoDB, error) {
+	path := filepath.Join(datadir, kv.ChainDB.String())
+	if _, err := os.Stat(filepath.Join(path, "mdbx.dat")); err != nil {
+		return nil, fmt.Errorf("no chain database at %s: %w", path, err)
+	}
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	return mdbx2.NewMDBX(log2.New()).
+		Path(path).
+		Label(kv.ChainDB).
+		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.TablesCfgByLabel(kv.ChainDB) }).
+		Flags(func(flags uint) uint { return flags | mdbx.Readonly | mdbx.Accede }).
+		Open()
+}
+
+// Tables returns the statistics of every table in the database, largest
+// first.
+func Tables(tx kv.Tx) ([]TableStat, error) {
+	stater, ok := tx.(bucketStater)
+	if !ok {
+		return nil, fmt.Errorf("table statistics are not supported by %T", tx)
+	}
+	names, err := stater.ListBuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]TableStat, 0, len(names))
+	for _, name := range names {
+		if exists, err := stater.ExistsBucket(name); err != nil || !exists {
+			continue
+		}
+		st, err := stater.BucketStat(name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, TableStat{
+			Name:          name,
+			Entries:       st.Entries,
+			Depth:         st.Depth,
+			BranchPages:   st.BranchPages,
+			LeafPages:     st.LeafPages,
+			OverflowPages: st.OverflowPages,
+			Size:          (st.BranchPages + st.LeafPages + st.OverflowPages) * uint64(st.PSize),
+		})
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Size != tables[j].Size {
+			return tables[i].Size > tables[j].Size
+		}
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
+
+// Collect gathers environment, table and free-list statistics of db.
+func Collect(ctx context.Context, db kv.RoDB) (*Stat, error) {
+	stat, err := Summary(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	env := db.(*mdbx2.MdbxKV).Env()
+	if stat.FreeList, err = freeList(env, stat.UsedSize/stat.PageSize-1); err != nil {
+		return nil, err
+	}
+	return stat, nil
+}
+
+// Summary gathers environment and table statistics of db. Unlike Collect it
+// does not walk the free-list, so it is cheap enough to be sampled regularly.
+func Summary(ctx context.Context, db kv.RoDB) (*Stat, error) {
+	mdb, ok := db.(*mdbx2.MdbxKV)
+	if !ok {
+		return nil, fmt.Errorf("database statistics are not supported by %T", db)
+	}
+
+	stat := &Stat{}
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		stat.Tables, err = Tables(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	for _, t := range stat.Tables {
+		stat.TablesSize += t.Size
+	}
+
+	env := mdb.Env()
+	info, err := env.Info(nil)
+	if err != nil {
+		return nil, err
+	}
+	stat.PageSize = uint64(info.PageSize)
+	stat.FileSize = info.Geo.Current
+	stat.MapSize = info.Geo.Upper
+	stat.UsedSize = uint64(info.LastPNO+1) * stat.PageSize
+	stat.LastTxnID = uint64(info.LastTxnID)
+	stat.Readers = info.NumReaders
+	return stat, nil
+}
+
+// freeList walks the GC table. Every record is a page number list whose first
+// element holds the number of pages that follow.
+func freeList(env *mdbx.Env, lastPage uint64) (FreeList, error) {
+	// Read transactions are bound to the OS thread unless MDBX_NOTLS is set.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var fl FreeList
+	txn, err := env.BeginTxn(nil, mdbx.Readonly)
+	if err != nil {
+		return fl, err
+	}
+	defer txn.Abort()
+
+	st, err := txn.StatDBI(gcDBI)
+	if err != nil {
+		return fl, err
+	}
+	fl.TreeSize = (st.BranchPages + st.LeafPages + st.OverflowPages) * uint64(st.PSize)
+
+	cursor, err := txn.OpenCursor(gcDBI)
+	if err != nil {
+		return fl, err
+	}
+	defer cursor.Close()
+
+	for _, v, err := cursor.Get(nil, nil, mdbx.First); ; _, v, err = cursor.Get(nil, nil, mdbx.Next) {
+		if err != nil {
+			if mdbx.IsNotFound(err) {
+				break
+			}
+			return fl, err
+		}
+		fl.Records++
+		if len(v) < 4 || len(v)%4 != 0 {
+			fl.Malformed++
+			continue
+		}
+		count := uint64(binary.LittleEndian.Uint32(v))
+		if count != uint64(len(v)/4-1) {
+			fl.Malformed++
+			continue
+		}
+		fl.FreePages += count
+		for i := 1; i <= int(count); i++ {
+			if uint64(binary.LittleEndian.Uint32(v[i*4:])) > lastPage {
+				fl.OutOfRange++
+			}
+		}
+	}
+	fl.FreeSize = fl.FreePages * uint64(st.PSize)
+	return fl, nil
+}
+
+// Verify reports inconsistencies in the free-list, returning nil when it is
+// healthy.
+func (s *Stat) Verify() error {
+	var errs []error
+	if s.FreeList.Malformed > 0 {
+		errs = append(errs, fmt.Errorf("%d malformed free-list records", s.FreeList.Malformed))
+	}
+	if s.FreeList.OutOfRange > 0 {
+		errs = append(errs, fmt.Errorf("%d free pages beyond the last allocated page", s.FreeList.OutOfRange))
+	}
+	if s.PageSize > 0 && s.FreeList.FreePages > s.UsedSize/s.PageSize {
+		errs = append(errs, fmt.Errorf("free-list holds %d pages but only %d are allocated", s.FreeList.FreePages, s.UsedSize/s.PageSize))
+	}
+	if s.TablesSize+s.FreeList.FreeSize+s.FreeList.TreeSize > s.UsedSize {
+		errs = append(errs, fmt.Errorf("tables and free-list account for %d bytes but only %d are allocated",
+			s.TablesSize+s.FreeList.FreeSize+s.FreeList.TreeSize, s.UsedSize))
+	}
+	return errors.Join(errs...)
+}
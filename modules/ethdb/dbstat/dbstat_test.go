@@ -0,0 +1,96 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package dbstat
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+func TestCollect(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+
+	const entries = 1000
+	value := make([]byte, 256)
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		for i := uint64(0); i < entries; i++ {
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, i)
+			if err := tx.Put(kv.Headers, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Rewriting the table retires its old pages into the free-list.
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.ClearBucket(kv.Headers)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.Headers, []byte{0x01}, value)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err := Collect(ctx, db)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	var headers *TableStat
+	for i := range stat.Tables {
+		if stat.Tables[i].Name == kv.Headers {
+			headers = &stat.Tables[i]
+		}
+	}
+	if headers == nil {
+		t.Fatalf("table %s missing from %d tables", kv.Headers, len(stat.Tables))
+	}
+	if headers.Entries != 1 {
+		t.Errorf("entries = %d, want 1", headers.Entries)
+	}
+	if stat.PageSize == 0 || stat.UsedSize == 0 {
+		t.Errorf("page size %d, used size %d should be set", stat.PageSize, stat.UsedSize)
+	}
+	if stat.FreeList.FreePages == 0 {
+		t.Errorf("free-list should hold the pages released by the cleared table")
+	}
+	if err := stat.Verify(); err != nil {
+		t.Errorf("Verify failed on a healthy database: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	stat := &Stat{PageSize: 4096, UsedSize: 10 * 4096, FreeList: FreeList{FreePages: 2, FreeSize: 2 * 4096}}
+	if err := stat.Verify(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	stat.FreeList.Malformed = 1
+	stat.FreeList.OutOfRange = 3
+	if err := stat.Verify(); err == nil {
+		t.Errorf("Verify should report malformed and out of range records")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// The ancients service serves the frozen blocks, which live outside the
+// database and so are not reachable through the kv service. It reuses the
+// messages of the kv protocol: an item is requested with the kind as the
+// table and the block number as the timestamp of a DomainGetReq.
+const (
+	ancientsService = "n42.remotedb.Ancients"
+	ancientsCount   = "/" + ancientsService + "/Count"
+	ancientsGet     = "/" + ancientsService + "/Get"
+)
+
+var errNoAncients = errors.New("no frozen blocks")
+
+// ancientsHandler is implemented by the server of the ancients service
+type ancientsHandler interface {
+	Count(context.Context, *emptypb.Empty) (*wrapperspb.UInt64Value, error)
+	Get(context.Context, *remote.DomainGetReq) (*remote.DomainGetReply, error)
+}
+
+var ancientsServiceDesc = grpc.ServiceDesc{
+	ServiceName: ancientsService,
+	HandlerType: (*ancientsHandler)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Count", Handler: ancientsCountHandler},
+		{MethodName: "Get", Handler: ancientsGetHandler},
+	},
+}
+
+func ancientsCountHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ancientsHandler).Count(ctx, req.(*emptypb.Empty))
+	}
+	if interceptor == nil {
+		return handler(ctx, in)
+	}
+	return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: ancientsCount}, handler)
+}
+
+func ancientsGetHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remote.DomainGetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ancientsHandler).Get(ctx, req.(*remote.DomainGetReq))
+	}
+	if interceptor == nil {
+		return handler(ctx, in)
+	}
+	return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: ancientsGet}, handler)
+}
+
+// ancientsServer serves the frozen blocks of a freezer
+type ancientsServer struct {
+	ancients rawdb.AncientReader // nil if nothing is frozen
+}
+
+func (s *ancientsServer) Count(context.Context, *emptypb.Empty) (*wrapperspb.UInt64Value, error) {
+	if s.ancients == nil {
+		return wrapperspb.UInt64(0), nil
+	}
+	return wrapperspb.UInt64(s.ancients.Ancients()), nil
+}
+
+func (s *ancientsServer) Get(_ context.Context, req *remote.DomainGetReq) (*remote.DomainGetReply, error) {
+	if s.ancients == nil {
+		return nil, errNoAncients
+	}
+	data, err := s.ancients.Ancient(req.Table, req.Ts)
+	if err != nil {
+		return nil, err
+	}
+	return &remote.DomainGetReply{V: data, Ok: true}, nil
+}
+
+// ancientsClient calls the ancients service
+type ancientsClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func (c *ancientsClient) Count(ctx context.Context) (uint64, error) {
+	out := new(wrapperspb.UInt64Value)
+	if err := c.conn.Invoke(ctx, ancientsCount, new(emptypb.Empty), out); err != nil {
+		return 0, err
+	}
+	return out.Value, nil
+}
+
+func (c *ancientsClient) Get(ctx context.Context, kind string, number uint64) ([]byte, error) {
+	out := new(remote.DomainGetReply)
+	if err := c.conn.Invoke(ctx, ancientsGet, &remote.DomainGetReq{Table: kind, Ts: number}, out); err != nil {
+		return nil, err
+	}
+	return out.V, nil
+}
@@ -0,0 +1,410 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// ancientsRefresh is how long the client trusts the number of frozen blocks
+// it last read from the server.
+const ancientsRefresh = time.Second
+
+var errNotSupported = errors.New("not supported by the remote database")
+
+// DB is a chain database served by a Server. It is read-only: the write
+// methods return rawdb.ErrReadOnly.
+type DB struct {
+	conn     *grpc.ClientConn
+	kv       remote.KVClient
+	ancients *ancientsClient
+	tables   kv.TableCfg
+
+	ancientsCount   atomic.Uint64
+	ancientsChecked atomic.Int64 // Unix nanoseconds
+}
+
+var (
+	_ kv.RwDB             = (*DB)(nil)
+	_ rawdb.AncientReader = (*DB)(nil)
+)
+
+// Open connects to the kv service at addr, e.g. 10.0.0.1:8548. The options
+// are added to the dial options, e.g. to authenticate the client.
+func Open(ctx context.Context, addr string, opts ...grpc.DialOption) (*DB, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newDB(ctx, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("remote database %s: %w", addr, err)
+	}
+	return db, nil
+}
+
+func newDB(ctx context.Context, conn *grpc.ClientConn) (*DB, error) {
+	client := remote.NewKVClient(conn)
+	version, err := client.Version(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	want := remotedbserver.KvServiceAPIVersion.Major
+	if kv.DBSchemaVersion.Major > want {
+		want = kv.DBSchemaVersion.Major
+	}
+	if version.Major != want {
+		return nil, fmt.Errorf("server speaks version %d, want %d", version.Major, want)
+	}
+	return &DB{
+		conn:     conn,
+		kv:       client,
+		ancients: &ancientsClient{conn: conn},
+		tables:   kv.ChaindataTablesCfg,
+	}, nil
+}
+
+func (db *DB) Close()                 { db.conn.Close() }
+func (db *DB) ReadOnly() bool         { return true }
+func (db *DB) AllTables() kv.TableCfg { return db.tables }
+func (db *DB) PageSize() uint64       { return 0 }
+
+// BeginRo opens a transaction on the server. It stays open until it is
+// rolled back or ctx is done.
+func (db *DB) BeginRo(ctx context.Context) (kv.Tx, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := db.kv.Tx(streamCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &tx{
+		db:      db,
+		ctx:     ctx,
+		stream:  stream,
+		cancel:  cancel,
+		id:      msg.TxId,
+		viewID:  msg.ViewId,
+		cursors: make(map[string]*cursor),
+	}, nil
+}
+
+func (db *DB) View(ctx context.Context, f func(tx kv.Tx) error) error {
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return f(tx)
+}
+
+func (db *DB) Update(context.Context, func(tx kv.RwTx) error) error {
+	return rawdb.ErrReadOnly
+}
+
+func (db *DB) UpdateNosync(context.Context, func(tx kv.RwTx) error) error {
+	return rawdb.ErrReadOnly
+}
+
+func (db *DB) BeginRw(context.Context) (kv.RwTx, error) {
+	return nil, rawdb.ErrReadOnly
+}
+
+func (db *DB) BeginRwNosync(context.Context) (kv.RwTx, error) {
+	return nil, rawdb.ErrReadOnly
+}
+
+// Ancients returns the number of blocks frozen by the server. The number is
+// cached for ancientsRefresh, so blocks frozen in the meantime may briefly
+// be missing.
+func (db *DB) Ancients() uint64 {
+	if time.Since(time.Unix(0, db.ancientsChecked.Load())) < ancientsRefresh {
+		return db.ancientsCount.Load()
+	}
+	n, err := db.ancients.Count(context.Background())
+	if err != nil {
+		return db.ancientsCount.Load()
+	}
+	db.ancientsCount.Store(n)
+	db.ancientsChecked.Store(time.Now().UnixNano())
+	return n
+}
+
+// Ancient returns the item of the given kind for frozen block number.
+func (db *DB) Ancient(kind string, number uint64) ([]byte, error) {
+	return db.ancients.Get(context.Background(), kind, number)
+}
+
+// tx is a read transaction held open by the server for the lifetime of its
+// stream. Cursor moves are exchanged on the stream, ranges are fetched in
+// pages by separate calls.
+type tx struct {
+	db     *DB
+	ctx    context.Context
+	stream remote.KV_TxClient
+	cancel context.CancelFunc
+	id     uint64
+	viewID uint64
+
+	mu      sync.Mutex // Serializes the exchanges on the stream
+	cursors map[string]*cursor
+	closed  bool
+}
+
+// exchange sends a cursor request and returns the answer of the server
+func (tx *tx) exchange(req *remote.Cursor) (*remote.Pair, error) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return nil, errors.New("transaction is closed")
+	}
+	if err := tx.stream.Send(req); err != nil {
+		return nil, err
+	}
+	return tx.stream.Recv()
+}
+
+func (tx *tx) ViewID() uint64 { return tx.viewID }
+
+func (tx *tx) Commit() error {
+	tx.Rollback()
+	return nil
+}
+
+// Rollback ends the stream, the server rolls the transaction back with it.
+func (tx *tx) Rollback() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return
+	}
+	tx.closed = true
+	tx.stream.CloseSend()
+	tx.cancel()
+}
+
+// statelessCursor returns the cursor shared by the point reads of table
+func (tx *tx) statelessCursor(table string) (*cursor, error) {
+	if c, ok := tx.cursors[table]; ok {
+		return c, nil
+	}
+	c, err := tx.cursor(table, false)
+	if err != nil {
+		return nil, err
+	}
+	tx.cursors[table] = c
+	return c, nil
+}
+
+func (tx *tx) GetOne(table string, key []byte) ([]byte, error) {
+	c, err := tx.statelessCursor(table)
+	if err != nil {
+		return nil, err
+	}
+	_, v, err := c.SeekExact(key)
+	return v, err
+}
+
+func (tx *tx) Has(table string, key []byte) (bool, error) {
+	c, err := tx.statelessCursor(table)
+	if err != nil {
+		return false, err
+	}
+	k, _, err := c.SeekExact(key)
+	return k != nil, err
+}
+
+func (tx *tx) ReadSequence(table string) (uint64, error) {
+	v, err := tx.GetOne(kv.Sequence, []byte(table))
+	if err != nil || len(v) == 0 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (tx *tx) ListBuckets() ([]string, error)    { return nil, errNotSupported }
+func (tx *tx) BucketSize(string) (uint64, error) { return 0, errNotSupported }
+func (tx *tx) DBSize() (uint64, error)           { return 0, errNotSupported }
+
+func (tx *tx) cursor(table string, dupSort bool) (*cursor, error) {
+	op := remote.Op_OPEN
+	if dupSort {
+		op = remote.Op_OPEN_DUP_SORT
+	}
+	pair, err := tx.exchange(&remote.Cursor{Op: op, BucketName: table})
+	if err != nil {
+		return nil, err
+	}
+	return &cursor{tx: tx, id: pair.CursorId}, nil
+}
+
+func (tx *tx) Cursor(table string) (kv.Cursor, error) {
+	return tx.cursor(table, false)
+}
+
+func (tx *tx) CursorDupSort(table string) (kv.CursorDupSort, error) {
+	return tx.cursor(table, true)
+}
+
+func (tx *tx) rangePages(table string, from, to []byte, asc bool, limit int) iter.KV {
+	return iter.PaginateKV(func(token string) ([][]byte, [][]byte, string, error) {
+		page, err := tx.db.kv.Range(tx.ctx, &remote.RangeReq{
+			TxId:        tx.id,
+			Table:       table,
+			FromPrefix:  from,
+			ToPrefix:    to,
+			OrderAscend: asc,
+			Limit:       int64(limit),
+			PageToken:   token,
+		})
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return page.Keys, page.Values, page.NextPageToken, nil
+	})
+}
+
+func (tx *tx) Range(table string, fromPrefix, toPrefix []byte) (iter.KV, error) {
+	return tx.rangePages(table, fromPrefix, toPrefix, true, -1), nil
+}
+
+func (tx *tx) RangeAscend(table string, fromPrefix, toPrefix []byte, limit int) (iter.KV, error) {
+	return tx.rangePages(table, fromPrefix, toPrefix, true, limit), nil
+}
+
+func (tx *tx) RangeDescend(table string, fromPrefix, toPrefix []byte, limit int) (iter.KV, error) {
+	return tx.rangePages(table, fromPrefix, toPrefix, false, limit), nil
+}
+
+func (tx *tx) Prefix(table string, prefix []byte) (iter.KV, error) {
+	to, ok := kv.NextSubtree(prefix)
+	if !ok {
+		to = nil
+	}
+	return tx.rangePages(table, prefix, to, true, -1), nil
+}
+
+func (tx *tx) RangeDupSort(string, []byte, []byte, []byte, order.By, int) (iter.KV, error) {
+	return nil, errNotSupported
+}
+
+func (tx *tx) walk(it iter.KV, walker func(k, v []byte) error) error {
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err := walker(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *tx) ForEach(table string, fromPrefix []byte, walker func(k, v []byte) error) error {
+	return tx.walk(tx.rangePages(table, fromPrefix, nil, true, -1), walker)
+}
+
+func (tx *tx) ForPrefix(table string, prefix []byte, walker func(k, v []byte) error) error {
+	it, _ := tx.Prefix(table, prefix)
+	return tx.walk(it, walker)
+}
+
+func (tx *tx) ForAmount(table string, prefix []byte, amount uint32, walker func(k, v []byte) error) error {
+	return tx.walk(tx.rangePages(table, prefix, nil, true, int(amount)), walker)
+}
+
+// cursor is a cursor held open by the server, every move is a round trip.
+type cursor struct {
+	tx *tx
+	id uint32
+}
+
+func (c *cursor) op(op remote.Op, k, v []byte) ([]byte, []byte, error) {
+	pair, err := c.tx.exchange(&remote.Cursor{Op: op, Cursor: c.id, K: k, V: v})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair.K, pair.V, nil
+}
+
+func (c *cursor) value(op remote.Op, k, v []byte) ([]byte, error) {
+	_, val, err := c.op(op, k, v)
+	return val, err
+}
+
+func (c *cursor) First() ([]byte, []byte, error)        { return c.op(remote.Op_FIRST, nil, nil) }
+func (c *cursor) Seek(k []byte) ([]byte, []byte, error) { return c.op(remote.Op_SEEK, k, nil) }
+func (c *cursor) SeekExact(k []byte) ([]byte, []byte, error) {
+	return c.op(remote.Op_SEEK_EXACT, k, nil)
+}
+func (c *cursor) Next() ([]byte, []byte, error)    { return c.op(remote.Op_NEXT, nil, nil) }
+func (c *cursor) Prev() ([]byte, []byte, error)    { return c.op(remote.Op_PREV, nil, nil) }
+func (c *cursor) Last() ([]byte, []byte, error)    { return c.op(remote.Op_LAST, nil, nil) }
+func (c *cursor) Current() ([]byte, []byte, error) { return c.op(remote.Op_CURRENT, nil, nil) }
+
+func (c *cursor) SeekBothExact(k, v []byte) ([]byte, []byte, error) {
+	return c.op(remote.Op_SEEK_BOTH_EXACT, k, v)
+}
+func (c *cursor) SeekBothRange(k, v []byte) ([]byte, error) {
+	return c.value(remote.Op_SEEK_BOTH, k, v)
+}
+func (c *cursor) FirstDup() ([]byte, error)          { return c.value(remote.Op_FIRST_DUP, nil, nil) }
+func (c *cursor) NextDup() ([]byte, []byte, error)   { return c.op(remote.Op_NEXT_DUP, nil, nil) }
+func (c *cursor) NextNoDup() ([]byte, []byte, error) { return c.op(remote.Op_NEXT_NO_DUP, nil, nil) }
+func (c *cursor) LastDup() ([]byte, error)           { return c.value(remote.Op_LAST_DUP, nil, nil) }
+
+// The kv service does not move backwards through duplicates or count them
+func (c *cursor) PrevDup() ([]byte, []byte, error)   { return nil, nil, errNotSupported }
+func (c *cursor) PrevNoDup() ([]byte, []byte, error) { return nil, nil, errNotSupported }
+func (c *cursor) CountDuplicates() (uint64, error)   { return 0, errNotSupported }
+
+func (c *cursor) Count() (uint64, error) {
+	_, v, err := c.op(remote.Op_COUNT, nil, nil)
+	if err != nil || len(v) < 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (c *cursor) Close() {
+	c.tx.exchange(&remote.Cursor{Op: remote.Op_CLOSE, Cursor: c.id})
+}
@@ -0,0 +1,238 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	log2 "github.com/ledgerwatch/log/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+func key(i uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, i)
+}
+
+// testAncients holds ten frozen blocks
+type testAncients struct{}
+
+func (testAncients) Ancients() uint64 { return 10 }
+
+func (testAncients) Ancient(kind string, number uint64) ([]byte, error) {
+	if number >= 10 {
+		return nil, fmt.Errorf("block %d is not frozen", number)
+	}
+	return append([]byte(kind), key(number)...), nil
+}
+
+// newTestDB serves a database holding 1000 plain keys and two keys with
+// three duplicates each, and connects to it in process.
+func newTestDB(t *testing.T, ancients rawdb.AncientReader) *DB {
+	t.Helper()
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.New("")
+	t.Cleanup(db.Close)
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := uint64(0); i < 1000; i++ {
+			if err := tx.Put(modules.HeaderNumber, key(i), key(i*2)); err != nil {
+				return err
+			}
+		}
+		for _, k := range []uint64{1, 2} {
+			for v := byte(1); v <= 3; v++ {
+				if err := tx.Put(modules.AccountChangeSet, key(k), []byte{v}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(db, ancients, log2.New())
+	t.Cleanup(server.Close)
+	g := grpc.NewServer()
+	server.Register(g)
+	lis := bufconn.Listen(1 << 20)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	remote, err := Open(context.Background(), "passthrough:///bufconn", grpc.WithContextDialer(dialer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(remote.Close)
+	return remote
+}
+
+func TestRemoteGet(t *testing.T) {
+	db := newTestDB(t, nil)
+	err := db.View(context.Background(), func(tx kv.Tx) error {
+		v, err := tx.GetOne(modules.HeaderNumber, key(7))
+		if err != nil || !bytes.Equal(v, key(14)) {
+			t.Fatalf("get: have %x, %v", v, err)
+		}
+		if v, err := tx.GetOne(modules.HeaderNumber, key(1000)); err != nil || v != nil {
+			t.Fatalf("get missing key: have %x, %v", v, err)
+		}
+		if ok, err := tx.Has(modules.HeaderNumber, key(999)); err != nil || !ok {
+			t.Fatalf("has: have %v, %v", ok, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(context.Background(), func(kv.RwTx) error { return nil }); !errors.Is(err, rawdb.ErrReadOnly) {
+		t.Fatalf("update: have %v, want %v", err, rawdb.ErrReadOnly)
+	}
+	if n := db.Ancients(); n != 0 {
+		t.Fatalf("ancients without a freezer: have %d", n)
+	}
+}
+
+func TestRemoteCursor(t *testing.T) {
+	db := newTestDB(t, nil)
+	err := db.View(context.Background(), func(tx kv.Tx) error {
+		c, err := tx.Cursor(modules.HeaderNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		if k, _, err := c.Seek(key(500)); err != nil || !bytes.Equal(k, key(500)) {
+			t.Fatalf("seek: have %x, %v", k, err)
+		}
+		if k, v, err := c.Next(); err != nil || !bytes.Equal(k, key(501)) || !bytes.Equal(v, key(1002)) {
+			t.Fatalf("next: have %x %x, %v", k, v, err)
+		}
+		if k, _, err := c.Last(); err != nil || !bytes.Equal(k, key(999)) {
+			t.Fatalf("last: have %x, %v", k, err)
+		}
+		if k, _, err := c.Next(); err != nil || k != nil {
+			t.Fatalf("next past the end: have %x, %v", k, err)
+		}
+
+		dc, err := tx.CursorDupSort(modules.AccountChangeSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dc.Close()
+		if v, err := dc.SeekBothRange(key(2), []byte{2}); err != nil || !bytes.Equal(v, []byte{2}) {
+			t.Fatalf("seek both range: have %x, %v", v, err)
+		}
+		if _, err := dc.CountDuplicates(); !errors.Is(err, errNotSupported) {
+			t.Fatalf("count duplicates: have %v, want %v", err, errNotSupported)
+		}
+		if k, v, err := dc.NextDup(); err != nil || !bytes.Equal(k, key(2)) || !bytes.Equal(v, []byte{3}) {
+			t.Fatalf("next dup: have %x %x, %v", k, v, err)
+		}
+		if k, _, err := dc.NextDup(); err != nil || k != nil {
+			t.Fatalf("next dup past the last value: have %x, %v", k, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteRange(t *testing.T) {
+	db := newTestDB(t, nil)
+	err := db.View(context.Background(), func(tx kv.Tx) error {
+		// Spans several pages
+		it, err := tx.Range(modules.HeaderNumber, key(100), key(900))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys, _, err := iter.ToKVArray(it)
+		if err != nil || len(keys) != 800 || !bytes.Equal(keys[0], key(100)) || !bytes.Equal(keys[799], key(899)) {
+			t.Fatalf("range: have %d keys, %v", len(keys), err)
+		}
+
+		it, _ = tx.RangeDescend(modules.HeaderNumber, key(10), key(5), -1)
+		if keys, _, err = iter.ToKVArray(it); err != nil || len(keys) != 5 || !bytes.Equal(keys[0], key(10)) {
+			t.Fatalf("range descend: have %d keys, %v", len(keys), err)
+		}
+
+		var n int
+		if err := tx.ForAmount(modules.HeaderNumber, key(998), 10, func(k, v []byte) error {
+			n++
+			return nil
+		}); err != nil || n != 2 {
+			t.Fatalf("for amount: have %d pairs, %v", n, err)
+		}
+
+		// Duplicates are returned as separate pairs
+		n = 0
+		if err := tx.ForEach(modules.AccountChangeSet, nil, func(k, v []byte) error {
+			n++
+			return nil
+		}); err != nil || n != 6 {
+			t.Fatalf("for each on dupsort table: have %d pairs, %v", n, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteAncients(t *testing.T) {
+	db := newTestDB(t, testAncients{})
+	if n := db.Ancients(); n != 10 {
+		t.Fatalf("ancients: have %d, want 10", n)
+	}
+	if v, err := db.Ancient("headers", 3); err != nil || !bytes.Equal(v, append([]byte("headers"), key(3)...)) {
+		t.Fatalf("ancient: have %x, %v", v, err)
+	}
+	if _, err := db.Ancient("headers", 10); err == nil {
+		t.Fatal("ancient past the freezer: have no error")
+	}
+}
+
+func TestRemoteRollback(t *testing.T) {
+	db := newTestDB(t, nil)
+	tx, err := db.BeginRo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.GetOne(modules.HeaderNumber, key(1)); err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+	if _, err := tx.GetOne(modules.HeaderNumber, key(1)); err == nil {
+		t.Fatal("read from rolled back transaction: have no error")
+	}
+}
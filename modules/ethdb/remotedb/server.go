@@ -0,0 +1,63 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package remotedb serves a chain database over the erigon-lib kv gRPC
+// protocol and opens it from another process, so that stateless RPC
+// front-ends can run on separate hosts from the node that syncs the chain.
+package remotedb
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
+	log2 "github.com/ledgerwatch/log/v3"
+	"google.golang.org/grpc"
+
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// Server serves read transactions on a database with the kv gRPC service,
+// and the frozen blocks with the ancients service.
+type Server struct {
+	kv       *remotedbserver.KvServer
+	ancients *ancientsServer
+	cancel   context.CancelFunc
+}
+
+// NewServer creates a server for db. Frozen blocks are served from ancients,
+// which may be nil.
+func NewServer(db kv.RoDB, ancients rawdb.AncientReader, logger log2.Logger) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		kv:       remotedbserver.NewKvServer(ctx, db, nil, nil, logger),
+		ancients: &ancientsServer{ancients: ancients},
+		cancel:   cancel,
+	}
+}
+
+// Register adds the services of the server to g.
+func (s *Server) Register(g *grpc.Server) {
+	remote.RegisterKVServer(g, s.kv)
+	g.RegisterService(&ancientsServiceDesc, s.ancients)
+}
+
+// Close stops the server. Transactions end with the streams of the gRPC
+// server.
+func (s *Server) Close() {
+	s.cancel()
+}
@@ -22,6 +22,11 @@ import (
 	"sync"
 )
 
+// GlobalEvent carries the node wide events that have no typed topic. Chain,
+// log, transaction, sync and mining events are published on the topics of
+// common/topics.go. Only the wallet and peer events stay here: the accounts
+// manager hands out v2 subscriptions to wallet events as part of its API, and
+// the peer events are left to the network layer, which has no v3 topics.
 var GlobalEvent Event
 
 type Event struct {
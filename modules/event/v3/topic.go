@@ -0,0 +1,233 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package v3 is a typed event bus. Every kind of event has its own Topic, and
+// every subscriber owns a bounded buffer together with a policy deciding what
+// happens when the buffer is full: the producer waits, or an event is dropped
+// and counted. Unlike the v2 feeds, a slow subscriber can only stall the
+// producer when it asked for that.
+package v3
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+)
+
+// Policy decides what Send does when a subscriber's buffer is full.
+type Policy int
+
+const (
+	// Block makes Send wait until the subscriber has room, slowing the
+	// producer down to the subscriber's pace. Use it for events that must
+	// not be lost.
+	Block Policy = iota
+	// DropNewest discards the event being sent.
+	DropNewest
+	// DropOldest discards the oldest buffered event to make room, so the
+	// subscriber always sees the latest state.
+	DropOldest
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	default:
+		return fmt.Sprintf("policy(%d)", int(p))
+	}
+}
+
+// Topic delivers events of type T to its subscribers.
+type Topic[T any] struct {
+	name string
+
+	mu   sync.RWMutex
+	subs map[*Subscription[T]]struct{}
+
+	sentMeter        prometheus.Counter
+	droppedMeter     prometheus.Counter
+	subscribersGauge prometheus.Counter
+}
+
+// NewTopic creates a topic whose metrics are labelled with name.
+func NewTopic[T any](name string) *Topic[T] {
+	return &Topic[T]{
+		name:             name,
+		subs:             make(map[*Subscription[T]]struct{}),
+		sentMeter:        prometheus.GetOrCreateCounter(fmt.Sprintf(`event_sent_total{topic=%q}`, name)),
+		droppedMeter:     prometheus.GetOrCreateCounter(fmt.Sprintf(`event_dropped_total{topic=%q}`, name)),
+		subscribersGauge: prometheus.GetOrCreateCounter(fmt.Sprintf(`event_subscribers{topic=%q}`, name), true),
+	}
+}
+
+// Name returns the name of the topic.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Subscribe registers a subscriber buffering up to buffer events. Dropping
+// policies need room for at least one event, so their buffer is raised to one.
+func (t *Topic[T]) Subscribe(buffer int, policy Policy) *Subscription[T] {
+	if buffer < 1 && policy != Block {
+		buffer = 1
+	}
+	sub := &Subscription[T]{
+		topic:  t,
+		policy: policy,
+		ch:     make(chan T, buffer),
+		quit:   make(chan struct{}),
+		err:    make(chan error),
+	}
+	t.mu.Lock()
+	t.subs[sub] = struct{}{}
+	t.subscribersGauge.Set(uint64(len(t.subs)))
+	t.mu.Unlock()
+	return sub
+}
+
+// Send delivers ev to all subscribers and returns how many received it.
+// Subscribers that drop events are served first, so a blocking subscriber
+// does not delay them.
+func (t *Topic[T]) Send(ev T) int {
+	t.mu.RLock()
+	var subs, blocking []*Subscription[T]
+	for sub := range t.subs {
+		if sub.policy == Block {
+			blocking = append(blocking, sub)
+		} else {
+			subs = append(subs, sub)
+		}
+	}
+	t.mu.RUnlock()
+	subs = append(subs, blocking...)
+
+	t.sentMeter.Inc()
+	delivered := 0
+	for _, sub := range subs {
+		ok, dropped := sub.deliver(ev)
+		if ok {
+			delivered++
+		}
+		if dropped > 0 {
+			sub.dropped.Add(uint64(dropped))
+			t.droppedMeter.Add(dropped)
+		}
+	}
+	return delivered
+}
+
+// Subscribers returns the number of live subscriptions.
+func (t *Topic[T]) Subscribers() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.subs)
+}
+
+func (t *Topic[T]) remove(sub *Subscription[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.subs, sub)
+	t.subscribersGauge.Set(uint64(len(t.subs)))
+}
+
+// Subscription receives the events of a topic on its own buffered channel.
+// It satisfies the v2 Subscription interface, so Err is closed once
+// Unsubscribe was called.
+type Subscription[T any] struct {
+	topic  *Topic[T]
+	policy Policy
+	ch     chan T
+
+	mu      sync.Mutex // serialises evictions of concurrent senders
+	quit    chan struct{}
+	err     chan error
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// Chan returns the channel events are delivered on. It is never closed.
+func (s *Subscription[T]) Chan() <-chan T {
+	return s.ch
+}
+
+// Err returns a channel that is closed when the subscription ends.
+func (s *Subscription[T]) Err() <-chan error {
+	return s.err
+}
+
+// Dropped returns the number of events this subscriber lost to its policy.
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops the delivery of events and releases senders blocked on
+// the subscription. It can be called any number of times.
+func (s *Subscription[T]) Unsubscribe() {
+	s.once.Do(func() {
+		s.topic.remove(s)
+		close(s.quit)
+		close(s.err)
+	})
+}
+
+// deliver hands ev to the subscriber according to its policy. It reports
+// whether ev was buffered and how many events were dropped for it.
+func (s *Subscription[T]) deliver(ev T) (ok bool, dropped int) {
+	select {
+	case <-s.quit:
+		return false, 0
+	default:
+	}
+	switch s.policy {
+	case DropNewest:
+		select {
+		case s.ch <- ev:
+			return true, 0
+		default:
+			return false, 1
+		}
+	case DropOldest:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for {
+			select {
+			case s.ch <- ev:
+				return true, dropped
+			default:
+			}
+			// The subscriber may have drained the buffer in the meantime
+			select {
+			case <-s.ch:
+				dropped++
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- ev:
+			return true, 0
+		case <-s.quit:
+			return false, 0
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package v3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopicPolicies(t *testing.T) {
+	topic := NewTopic[int]("test_policies")
+	newest := topic.Subscribe(2, DropNewest)
+	oldest := topic.Subscribe(2, DropOldest)
+	defer newest.Unsubscribe()
+	defer oldest.Unsubscribe()
+
+	for i := 1; i <= 4; i++ {
+		topic.Send(i)
+	}
+	if got := []int{<-newest.Chan(), <-newest.Chan()}; got[0] != 1 || got[1] != 2 {
+		t.Errorf("drop-newest kept %v, want [1 2]", got)
+	}
+	if got := []int{<-oldest.Chan(), <-oldest.Chan()}; got[0] != 3 || got[1] != 4 {
+		t.Errorf("drop-oldest kept %v, want [3 4]", got)
+	}
+	if newest.Dropped() != 2 || oldest.Dropped() != 2 {
+		t.Errorf("dropped mismatch: newest %d, oldest %d, want 2 each", newest.Dropped(), oldest.Dropped())
+	}
+	if n := topic.droppedMeter.Get(); n != 4 {
+		t.Errorf("topic dropped meter %d, want 4", n)
+	}
+}
+
+func TestTopicBlock(t *testing.T) {
+	topic := NewTopic[int]("test_block")
+	sub := topic.Subscribe(0, Block)
+
+	sent := make(chan int)
+	go func() { sent <- topic.Send(1) }()
+	select {
+	case <-sent:
+		t.Fatal("send did not wait for the blocking subscriber")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if ev := <-sub.Chan(); ev != 1 {
+		t.Fatalf("received %d, want 1", ev)
+	}
+	if n := <-sent; n != 1 {
+		t.Fatalf("delivered to %d subscribers, want 1", n)
+	}
+
+	// Unsubscribing releases a blocked sender
+	go func() { sent <- topic.Send(2) }()
+	time.Sleep(10 * time.Millisecond)
+	sub.Unsubscribe()
+	select {
+	case n := <-sent:
+		if n != 0 {
+			t.Fatalf("delivered to %d subscribers after unsubscribe, want 0", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("send still blocked after unsubscribe")
+	}
+	if _, ok := <-sub.Err(); ok {
+		t.Fatal("error channel not closed")
+	}
+	if topic.Subscribers() != 0 {
+		t.Fatalf("%d subscribers left, want 0", topic.Subscribers())
+	}
+}
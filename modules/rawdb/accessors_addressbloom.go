@@ -0,0 +1,177 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+const (
+	// AddressBloomRange is the number of blocks summarized by one bloom.
+	AddressBloomRange = 4096
+	// addressBloomBits is the size of a range bloom in bits.
+	addressBloomBits = 1 << 16
+	// addressBloomHashes is the number of bits set per address.
+	addressBloomHashes = 3
+
+	// An address bloom entry holds a bitmap of the blocks of the range that
+	// were indexed, followed by the bloom itself.
+	addressBloomIndexedSize = AddressBloomRange / 8
+	addressBloomSize        = addressBloomIndexedSize + addressBloomBits/8
+)
+
+// BlockAddresses returns the addresses a block touches: transaction senders
+// and recipients, created contracts and log emitters.
+func BlockAddresses(blk *block.Block, receipts block.Receipts) []types.Address {
+	seen := make(map[types.Address]struct{})
+	for _, tx := range blk.Transactions() {
+		if from := tx.From(); from != nil {
+			seen[*from] = struct{}{}
+		}
+		if to := tx.To(); to != nil {
+			seen[*to] = struct{}{}
+		}
+	}
+	for _, r := range receipts {
+		if r == nil {
+			continue
+		}
+		if !r.ContractAddress.IsNull() {
+			seen[r.ContractAddress] = struct{}{}
+		}
+		for _, l := range r.Logs {
+			seen[l.Address] = struct{}{}
+		}
+	}
+	addrs := make([]types.Address, 0, len(seen))
+	for addr := range seen {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// WriteAddressActivity adds the addresses active in block number to the bloom
+// of its range and marks the block as indexed.
+func WriteAddressActivity(db kv.RwTx, number uint64, addrs []types.Address) error {
+	key := modules.EncodeBlockNumber(number / AddressBloomRange)
+	entry := make([]byte, addressBloomSize)
+	data, err := db.GetOne(modules.AddressBloom, key)
+	if err != nil {
+		return err
+	}
+	if len(data) == addressBloomSize {
+		copy(entry, data)
+	}
+
+	offset := number % AddressBloomRange
+	entry[offset/8] |= 1 << (offset % 8)
+	bloom := entry[addressBloomIndexedSize:]
+	for _, addr := range addrs {
+		for _, bit := range addressBloomPositions(addr) {
+			bloom[bit/8] |= 1 << (bit % 8)
+		}
+	}
+	if err := db.Put(modules.AddressBloom, key, entry); err != nil {
+		return fmt.Errorf("writing address bloom of block %d: %w", number, err)
+	}
+	return nil
+}
+
+// AddressActivity reports whether addr may have been active in the blocks
+// [from, to]. It returns true unless every block of the span is indexed and
+// the blooms rule the address out, so a false result is definite.
+func AddressActivity(db kv.Getter, addr types.Address, from, to uint64) (bool, error) {
+	positions := addressBloomPositions(addr)
+	for r := from / AddressBloomRange; r <= to/AddressBloomRange; r++ {
+		data, err := db.GetOne(modules.AddressBloom, modules.EncodeBlockNumber(r))
+		if err != nil {
+			return false, err
+		}
+		if len(data) != addressBloomSize {
+			return true, nil
+		}
+		first, last := max(from, r*AddressBloomRange), min(to, (r+1)*AddressBloomRange-1)
+		for n := first; n <= last; n++ {
+			// The genesis block has no transactions and is never indexed.
+			if offset := n % AddressBloomRange; n != 0 && data[offset/8]&(1<<(offset%8)) == 0 {
+				return true, nil
+			}
+		}
+		bloom := data[addressBloomIndexedSize:]
+		hit := true
+		for _, bit := range positions {
+			if bloom[bit/8]&(1<<(bit%8)) == 0 {
+				hit = false
+				break
+			}
+		}
+		if hit {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TruncateAddressActivity drops the blocks from number on from the address
+// blooms. The bits set for their addresses stay in the bloom of the range
+// number falls in, which only causes false positives.
+func TruncateAddressActivity(db kv.RwTx, number uint64) error {
+	first := number / AddressBloomRange
+	if number%AddressBloomRange != 0 {
+		key := modules.EncodeBlockNumber(first)
+		data, err := db.GetOne(modules.AddressBloom, key)
+		if err != nil {
+			return err
+		}
+		if len(data) == addressBloomSize {
+			entry := bytes.Clone(data)
+			for offset := number % AddressBloomRange; offset < AddressBloomRange; offset++ {
+				entry[offset/8] &^= 1 << (offset % 8)
+			}
+			if err := db.Put(modules.AddressBloom, key, entry); err != nil {
+				return err
+			}
+		}
+		first++
+	}
+	if err := db.ForEach(modules.AddressBloom, modules.EncodeBlockNumber(first), func(k, _ []byte) error {
+		return db.Delete(modules.AddressBloom, k)
+	}); err != nil {
+		return fmt.Errorf("TruncateAddressActivity: %w", err)
+	}
+	return nil
+}
+
+// addressBloomPositions derives the bloom bits of an address from its hash,
+// so that vanity addresses with shared prefixes do not collide.
+func addressBloomPositions(addr types.Address) [addressBloomHashes]uint32 {
+	h := crypto.Keccak256(addr[:])
+	var positions [addressBloomHashes]uint32
+	for i := range positions {
+		positions[i] = uint32(binary.BigEndian.Uint16(h[2*i:])) % addressBloomBits
+	}
+	return positions
+}
@@ -0,0 +1,106 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func TestAddressActivity(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	_, tx := memdb.NewTestTx(t)
+
+	active, idle := types.Address{0x01}, types.Address{0x02}
+	// Index the first range completely, with active appearing in block 10,
+	// and only the first half of the second range.
+	for n := uint64(1); n < AddressBloomRange+AddressBloomRange/2; n++ {
+		var addrs []types.Address
+		if n == 10 {
+			addrs = []types.Address{active}
+		}
+		if err := WriteAddressActivity(tx, n, addrs); err != nil {
+			t.Fatalf("WriteAddressActivity(%d) failed: %v", n, err)
+		}
+	}
+
+	tests := []struct {
+		addr     types.Address
+		from, to uint64
+		want     bool
+	}{
+		{active, 0, AddressBloomRange - 1, true},
+		{idle, 0, AddressBloomRange - 1, false},
+		{active, AddressBloomRange, AddressBloomRange + 100, false},
+		{idle, AddressBloomRange, AddressBloomRange + AddressBloomRange/2 - 1, false},
+		// Blocks that were never indexed may hold anything.
+		{idle, AddressBloomRange, 2*AddressBloomRange - 1, true},
+		{idle, 3 * AddressBloomRange, 3*AddressBloomRange + 1, true},
+	}
+	for _, tt := range tests {
+		have, err := AddressActivity(tx, tt.addr, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("AddressActivity failed: %v", err)
+		}
+		if have != tt.want {
+			t.Errorf("AddressActivity(%x, %d, %d) = %v, want %v", tt.addr[:1], tt.from, tt.to, have, tt.want)
+		}
+	}
+}
+
+func TestTruncateAddressActivity(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	_, tx := memdb.NewTestTx(t)
+
+	idle := types.Address{0x02}
+	for n := uint64(1); n < 2*AddressBloomRange; n++ {
+		if err := WriteAddressActivity(tx, n, nil); err != nil {
+			t.Fatalf("WriteAddressActivity(%d) failed: %v", n, err)
+		}
+	}
+	if err := TruncateAddressActivity(tx, 100); err != nil {
+		t.Fatalf("TruncateAddressActivity failed: %v", err)
+	}
+
+	tests := []struct {
+		from, to uint64
+		want     bool
+	}{
+		{1, 99, false},
+		{1, 100, true},
+		{AddressBloomRange, AddressBloomRange + 1, true},
+	}
+	for _, tt := range tests {
+		have, err := AddressActivity(tx, idle, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("AddressActivity failed: %v", err)
+		}
+		if have != tt.want {
+			t.Errorf("AddressActivity(%d, %d) = %v, want %v", tt.from, tt.to, have, tt.want)
+		}
+	}
+	if v, _ := tx.GetOne(modules.AddressBloom, modules.EncodeBlockNumber(1)); len(v) != 0 {
+		t.Errorf("bloom of a range above the target survived")
+	}
+}
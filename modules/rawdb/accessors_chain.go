@@ -117,6 +117,9 @@ func DeleteHeaderNumber(db kv.Deleter, hash types.Hash) {
 
 // ReadHeaderRAW retrieves a block header in its raw database encoding.
 func ReadHeaderRAW(db kv.Getter, hash types.Hash, number uint64) []byte {
+	if data, ok := readAncientHeader(hash, number); ok {
+		return data
+	}
 	data, err := db.GetOne(modules.Headers, modules.HeaderKey(number, hash))
 	if err != nil {
 		log.Error("ReadHeaderRAW failed", "err", err)
@@ -126,6 +129,9 @@ func ReadHeaderRAW(db kv.Getter, hash types.Hash, number uint64) []byte {
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db kv.Has, hash types.Hash, number uint64) bool {
+	if _, ok := readAncientHeader(hash, number); ok {
+		return true
+	}
 	if has, err := db.Has(modules.Headers, modules.HeaderKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -190,6 +196,18 @@ func ReadHeader(db kv.Getter, hash types.Hash, number uint64) *block.Header {
 //}
 
 func ReadHeadersByNumber(db kv.Tx, number uint64) ([]*block.Header, error) {
+	if isFrozen(number) {
+		// Only the canonical header of a frozen height is kept.
+		hash, ok := readAncient(AncientHashes, number)
+		if !ok {
+			return nil, fmt.Errorf("missing ancient hash of block %d", number)
+		}
+		header := ReadHeader(db, types.BytesToHash(hash), number)
+		if header == nil {
+			return nil, fmt.Errorf("missing ancient header of block %d", number)
+		}
+		return []*block.Header{header}, nil
+	}
 	var res []*block.Header
 	c, err := db.Cursor(modules.Headers)
 	if err != nil {
@@ -291,6 +309,9 @@ func CanonicalTxnByID(db kv.Getter, id uint64) (*transaction.Transaction, error)
 	if err != nil {
 		return nil, err
 	}
+	if v, err = decompressValue(v); err != nil {
+		return nil, err
+	}
 
 	tx := new(transaction.Transaction)
 	if err := tx.Unmarshal(v); nil != err {
@@ -310,7 +331,10 @@ func CanonicalTransactions(db kv.Getter, baseTxId uint64, amount uint32) ([]*tra
 	i := uint32(0)
 
 	if err := db.ForAmount(modules.BlockTx, txIdKey, amount, func(k, v []byte) error {
-		var decodeErr error
+		v, decodeErr := decompressValue(v)
+		if decodeErr != nil {
+			return decodeErr
+		}
 		tx := new(transaction.Transaction)
 		if decodeErr = tx.Unmarshal(v); nil != decodeErr {
 			return decodeErr
@@ -343,7 +367,7 @@ func WriteTransactions(db kv.RwTx, txs []*transaction.Transaction, baseTxId uint
 		//}
 
 		// If next Append returns KeyExists error - it means you need to open transaction in App code before calling this func. Batch is also fine.
-		if err := db.Append(modules.BlockTx, txIdKey, types.CopyBytes(data)); err != nil {
+		if err := db.Append(modules.BlockTx, txIdKey, compressValue(modules.BlockTx, types.CopyBytes(data))); err != nil {
 			return err
 		}
 	}
@@ -356,7 +380,7 @@ func WriteRawTransactions(tx kv.RwTx, txs [][]byte, baseTxId uint64) error {
 		txIdKey := make([]byte, 8)
 		binary.BigEndian.PutUint64(txIdKey, txId)
 		// If next Append returns KeyExists error - it means you need to open transaction in App code before calling this func. Batch is also fine.
-		if err := tx.Append(modules.BlockTx, txIdKey, txn); err != nil {
+		if err := tx.Append(modules.BlockTx, txIdKey, compressValue(modules.BlockTx, txn)); err != nil {
 			return fmt.Errorf("txId=%d, baseTxId=%d, %w", txId, baseTxId, err)
 		}
 		txId++
@@ -400,7 +424,11 @@ func ReadCanonicalBodyWithTransactions(db kv.Getter, hash types.Hash, number uin
 		return nil
 	}
 	var err error
-	body.Txs, err = CanonicalTransactions(db, baseTxId, txAmount)
+	if raw, ok, aerr := readAncientTransactions(hash, number); !ok {
+		body.Txs, err = CanonicalTransactions(db, baseTxId, txAmount)
+	} else if err = aerr; err == nil {
+		body.Txs, err = decodeAncientTransactions(raw)
+	}
 	if err != nil {
 		log.Error("failed ReadTransactionByHash", "hash", hash, "block", number, "err", err)
 		return nil
@@ -445,11 +473,25 @@ func RawTransactionsRange(db kv.Getter, from, to uint64) (res [][]byte, err erro
 			continue
 		}
 
+		if txs, ok, err := readAncientTransactions(types.BytesToHash(hash), i); ok {
+			if err != nil {
+				return nil, err
+			}
+			for _, txn := range txs {
+				res = append(res, types.CopyBytes(txn))
+			}
+			continue
+		}
+
 		baseTxId := binary.BigEndian.Uint64(bodyRaw[:8])
 		txAmount := binary.BigEndian.Uint32(bodyRaw[8:])
 
 		binary.BigEndian.PutUint64(encNum, baseTxId)
 		if err = db.ForAmount(modules.BlockTx, encNum, txAmount, func(k, v []byte) error {
+			v, err := decompressValue(v)
+			if err != nil {
+				return err
+			}
 			res = append(res, v)
 			return nil
 		}); err != nil {
@@ -674,6 +716,9 @@ func TruncateTd(tx kv.RwTx, blockFrom uint64) error {
 // HasReceipts verifies the existence of all the transaction receipts belonging
 // to a block.
 func HasReceipts(db kv.Has, number uint64) bool {
+	if isFrozen(number) {
+		return true
+	}
 	if has, err := db.Has(modules.Receipts, modules.EncodeBlockNumber(number)); !has || err != nil {
 		return false
 	}
@@ -685,9 +730,16 @@ func HasReceipts(db kv.Has, number uint64) bool {
 // should not be used. Use ReadReceipts instead if the metadata is needed.
 func ReadRawReceipts(db kv.Tx, blockNum uint64) block.Receipts {
 	// Retrieve the flattened receipt slice
-	data, err := db.GetOne(modules.Receipts, modules.EncodeBlockNumber(blockNum))
-	if err != nil {
-		log.Error("ReadRawReceipts failed", "err", err)
+	data, ok := readAncient(AncientReceipts, blockNum)
+	if !ok {
+		var err error
+		if data, err = db.GetOne(modules.Receipts, modules.EncodeBlockNumber(blockNum)); err != nil {
+			log.Error("ReadRawReceipts failed", "err", err)
+		}
+		if data, err = decompressValue(data); err != nil {
+			log.Error("ReadRawReceipts failed", "err", err)
+			return nil
+		}
 	}
 	if len(data) == 0 {
 		return nil
@@ -717,6 +769,65 @@ func ReadRawReceipts(db kv.Tx, blockNum uint64) block.Receipts {
 	return receipts
 }
 
+// ReadRawReceiptsRange retrieves the receipts of up to count consecutive blocks
+// starting at from with a single cursor walk, instead of one point lookup per
+// block. Blocks without stored receipts are absent from the returned map.
+func ReadRawReceiptsRange(db kv.Tx, from uint64, count int) (map[uint64]block.Receipts, error) {
+	result := make(map[uint64]block.Receipts, count)
+	if count <= 0 {
+		return result, nil
+	}
+	end := from + uint64(count)
+	for ; from < end && isFrozen(from); from++ {
+		data, ok := readAncient(AncientReceipts, from)
+		if !ok {
+			return nil, fmt.Errorf("missing ancient receipts of block %d", from)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		var receipts block.Receipts
+		if err := receipts.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("decode receipts for block %d: %w", from, err)
+		}
+		result[from] = receipts
+	}
+	if from == end {
+		return result, nil
+	}
+
+	c, err := db.Cursor(modules.Receipts)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	for k, v, err := c.Seek(modules.EncodeBlockNumber(from)); ; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if k == nil {
+			break
+		}
+		number := binary.BigEndian.Uint64(k)
+		if number >= end {
+			break
+		}
+		if len(v) == 0 {
+			continue
+		}
+		if v, err = decompressValue(v); err != nil {
+			return nil, fmt.Errorf("decode receipts for block %d: %w", number, err)
+		}
+		var receipts block.Receipts
+		if err := receipts.Unmarshal(v); err != nil {
+			return nil, fmt.Errorf("decode receipts for block %d: %w", number, err)
+		}
+		result[number] = receipts
+	}
+	return result, nil
+}
+
 // ReadReceipts retrieves all the transaction receipts belonging to a block, including
 // its corresponding metadata fields. If it is unable to populate these metadata
 // fields then nil is returned.
@@ -789,10 +900,10 @@ func WriteReceipts(tx kv.Putter, number uint64, receipts block.Receipts) error {
 		return fmt.Errorf("encode block receipts for block %d: %w", number, err)
 	}
 
-	if err = tx.Put(modules.Receipts, modules.EncodeBlockNumber(number), v); err != nil {
+	if err = tx.Put(modules.Receipts, modules.EncodeBlockNumber(number), compressValue(modules.Receipts, v)); err != nil {
 		return fmt.Errorf("writing receipts for block %d: %w", number, err)
 	}
-	return nil
+	return writeRevertReasons(tx, receipts)
 }
 
 // AppendReceipts stores all the transaction receipts belonging to a block.
@@ -819,12 +930,47 @@ func AppendReceipts(tx kv.StatelessWriteTx, blockNumber uint64, receipts block.R
 		return fmt.Errorf("encode block receipts for block %d: %w", blockNumber, err)
 	}
 
-	if err = tx.Append(modules.Receipts, modules.EncodeBlockNumber(blockNumber), rv); err != nil {
+	if err = tx.Append(modules.Receipts, modules.EncodeBlockNumber(blockNumber), compressValue(modules.Receipts, rv)); err != nil {
 		return fmt.Errorf("writing receipts for block %d: %w", blockNumber, err)
 	}
+	return writeRevertReasons(tx, receipts)
+}
+
+// MaxRevertReasonSize bounds the revert data kept per transaction. Longer
+// return data is not stored and has to be recovered by re-execution.
+const MaxRevertReasonSize = 1024
+
+// writeRevertReasons stores the revert data captured for failed receipts.
+func writeRevertReasons(tx kv.Putter, receipts block.Receipts) error {
+	for _, r := range receipts {
+		if r.Status != block.ReceiptStatusFailed || len(r.RevertReason) == 0 || len(r.RevertReason) > MaxRevertReasonSize {
+			continue
+		}
+		if err := tx.Put(modules.RevertReasons, r.TxHash.Bytes(), r.RevertReason); err != nil {
+			return fmt.Errorf("writing revert reason of tx %x: %w", r.TxHash, err)
+		}
+	}
 	return nil
 }
 
+// ReadRevertReason retrieves the revert data of a failed transaction, or nil
+// if none was captured when the transaction was executed.
+func ReadRevertReason(db kv.Getter, txHash types.Hash) ([]byte, error) {
+	data, err := db.GetOne(modules.RevertReasons, txHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return common2.Copy(data), nil
+}
+
+// DeleteRevertReason removes the revert data of a transaction.
+func DeleteRevertReason(db kv.Deleter, txHash types.Hash) error {
+	return db.Delete(modules.RevertReasons, txHash.Bytes())
+}
+
 // TruncateReceipts removes all receipt for given block number or newer
 func TruncateReceipts(db kv.RwTx, number uint64) error {
 	if err := db.ForEach(modules.Receipts, modules.EncodeBlockNumber(number), func(k, _ []byte) error {
@@ -844,6 +990,9 @@ func TruncateReceipts(db kv.RwTx, number uint64) error {
 }
 
 func ReceiptsAvailableFrom(tx kv.Tx) (uint64, error) {
+	if isFrozen(0) {
+		return 0, nil
+	}
 	c, err := tx.Cursor(modules.Receipts)
 	if err != nil {
 		return math.MaxUint64, err
@@ -880,6 +1029,9 @@ func ReadBlock(tx kv.Getter, hash types.Hash, number uint64) *block.Block {
 // HasBlock - is more efficient than ReadBlock because doesn't read transactions.
 // It's is not equivalent of HasHeader because headers and bodies written by different stages
 func HasBlock(db kv.Getter, hash types.Hash, number uint64) bool {
+	if _, ok := readAncientHeader(hash, number); ok {
+		return true
+	}
 	body := ReadStorageBodyRAW(db, hash, number)
 	return len(body) > 0
 }
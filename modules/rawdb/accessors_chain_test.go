@@ -17,9 +17,13 @@
 package rawdb
 
 import (
+	"bytes"
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/n42blockchain/N42/common/block"
 	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
 	"testing"
 )
 
@@ -86,3 +90,69 @@ func TestTdStorage(t *testing.T) {
 		t.Fatal("ReadTd returned nil")
 	}
 }
+
+// Tests that a batched receipt read returns exactly the stored blocks in range.
+func TestReadRawReceiptsRange(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	for _, number := range []uint64{1, 2, 4} {
+		receipts := block.Receipts{{
+			Status:            block.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000 * number,
+			GasUsed:           21000,
+			BlockNumber:       uint256.NewInt(number),
+		}}
+		if err := WriteReceipts(tx, number, receipts); err != nil {
+			t.Fatalf("WriteReceipts(%d) failed: %v", number, err)
+		}
+	}
+
+	batch, err := ReadRawReceiptsRange(tx, 1, 3)
+	if err != nil {
+		t.Fatalf("ReadRawReceiptsRange failed: %v", err)
+	}
+	if len(batch) != 2 || batch[1] == nil || batch[2] == nil {
+		t.Fatalf("unexpected blocks in range [1,4): %v", batch)
+	}
+	if have, want := batch[2][0].CumulativeGasUsed, uint64(42000); have != want {
+		t.Fatalf("cumulative gas mismatch: have %d, want %d", have, want)
+	}
+
+	batch, err = ReadRawReceiptsRange(tx, 3, 10)
+	if err != nil {
+		t.Fatalf("ReadRawReceiptsRange failed: %v", err)
+	}
+	if len(batch) != 1 || batch[4] == nil {
+		t.Fatalf("unexpected blocks in range [3,13): %v", batch)
+	}
+}
+
+// Tests that revert data of failed receipts is kept next to the receipts.
+func TestRevertReasons(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	_, tx := memdb.NewTestTx(t)
+
+	reverted, oversized, succeeded := types.Hash{0x01}, types.Hash{0x02}, types.Hash{0x03}
+	receipts := block.Receipts{
+		{Status: block.ReceiptStatusFailed, TxHash: reverted, RevertReason: []byte{0x08, 0xc3, 0x79, 0xa0}, BlockNumber: uint256.NewInt(1)},
+		{Status: block.ReceiptStatusFailed, TxHash: oversized, RevertReason: make([]byte, MaxRevertReasonSize+1), BlockNumber: uint256.NewInt(1)},
+		{Status: block.ReceiptStatusSuccessful, TxHash: succeeded, RevertReason: []byte{0x01}, BlockNumber: uint256.NewInt(1)},
+	}
+	if err := WriteReceipts(tx, 1, receipts); err != nil {
+		t.Fatalf("WriteReceipts failed: %v", err)
+	}
+
+	reason, err := ReadRevertReason(tx, reverted)
+	if err != nil {
+		t.Fatalf("ReadRevertReason failed: %v", err)
+	}
+	if !bytes.Equal(reason, receipts[0].RevertReason) {
+		t.Fatalf("revert reason mismatch: have %x, want %x", reason, receipts[0].RevertReason)
+	}
+	for _, hash := range []types.Hash{oversized, succeeded} {
+		if reason, err := ReadRevertReason(tx, hash); err != nil || reason != nil {
+			t.Fatalf("unexpected revert reason for %x: %x, %v", hash, reason, err)
+		}
+	}
+}
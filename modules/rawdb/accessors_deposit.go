@@ -83,3 +83,20 @@ func DepositNum(tx kv.Tx) (uint64, error) {
 	defer cur.Close()
 	return cur.Count()
 }
+
+// ReadDepositAddresses returns the addresses of all deposit accounts, in key order
+func ReadDepositAddresses(tx kv.Tx) ([]types.Address, error) {
+	var addrs []types.Address
+	if err := tx.ForEach(modules.Deposit, nil, func(k, _ []byte) error {
+		if len(k) != types.AddressLength {
+			return nil
+		}
+		var addr types.Address
+		copy(addr[:], k)
+		addrs = append(addrs, addr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+// DoubleSignEvidence proves that a verifier signed two different state roots
+// for the same block number. Both signatures verify against its public key.
+type DoubleSignEvidence struct {
+	Number     uint64          `json:"number"`
+	Address    types.Address   `json:"address"`
+	PublicKey  types.PublicKey `json:"publicKey"`
+	Root       types.Hash      `json:"root"`
+	Signature  types.Signature `json:"signature"`
+	Root2      types.Hash      `json:"root2"`
+	Signature2 types.Signature `json:"signature2"`
+	Time       uint64          `json:"time"` // unix time of the detection
+}
+
+func evidenceKey(number uint64, addr types.Address) []byte {
+	return append(modules.EncodeBlockNumber(number), addr[:]...)
+}
+
+// WriteDoubleSignEvidence stores the evidence unless evidence for the same
+// verifier and block number is already known. It reports whether it was new.
+func WriteDoubleSignEvidence(db kv.RwTx, ev *DoubleSignEvidence) (bool, error) {
+	key := evidenceKey(ev.Number, ev.Address)
+	if has, err := db.Has(modules.Evidence, key); err != nil || has {
+		return false, err
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return false, err
+	}
+	if err := db.Put(modules.Evidence, key, data); err != nil {
+		return false, fmt.Errorf("failed to store double sign evidence: %w", err)
+	}
+	return true, nil
+}
+
+// ReadDoubleSignEvidence returns the evidence against addr at block number, or
+// nil if there is none.
+func ReadDoubleSignEvidence(db kv.Getter, number uint64, addr types.Address) (*DoubleSignEvidence, error) {
+	data, err := db.GetOne(modules.Evidence, evidenceKey(number, addr))
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	ev := new(DoubleSignEvidence)
+	if err := json.Unmarshal(data, ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// ReadDoubleSignEvidences returns the evidence recorded for the block numbers
+// [from, to], ordered by block number and address.
+func ReadDoubleSignEvidences(tx kv.Tx, from, to uint64) ([]*DoubleSignEvidence, error) {
+	c, err := tx.Cursor(modules.Evidence)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	evs := make([]*DoubleSignEvidence, 0)
+	for k, v, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint64(k) > to {
+			break
+		}
+		ev := new(DoubleSignEvidence)
+		if err := json.Unmarshal(v, ev); err != nil {
+			return nil, err
+		}
+		evs = append(evs, ev)
+	}
+	return evs, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+)
+
+var (
+	finalizedBlockKey = []byte("finalized")
+	safeBlockKey      = []byte("safe")
+)
+
+func readFinalityHash(db kv.Getter, key []byte) types.Hash {
+	data, err := db.GetOne(modules.Finality, key)
+	if err != nil {
+		log.Error("ReadFinalityHash failed", "key", string(key), "err", err)
+	}
+	if len(data) == 0 {
+		return types.Hash{}
+	}
+	return types.BytesToHash(data)
+}
+
+// ReadFinalizedBlockHash retrieves the hash of the latest finalized block, or
+// the zero hash if no block was finalized yet.
+func ReadFinalizedBlockHash(db kv.Getter) types.Hash {
+	return readFinalityHash(db, finalizedBlockKey)
+}
+
+// WriteFinalizedBlockHash stores the hash of the latest finalized block.
+func WriteFinalizedBlockHash(db kv.Putter, hash types.Hash) error {
+	return db.Put(modules.Finality, finalizedBlockKey, hash.Bytes())
+}
+
+// ReadSafeBlockHash retrieves the hash of the latest justified block, or the
+// zero hash if no block was justified yet.
+func ReadSafeBlockHash(db kv.Getter) types.Hash {
+	return readFinalityHash(db, safeBlockKey)
+}
+
+// WriteSafeBlockHash stores the hash of the latest justified block.
+func WriteSafeBlockHash(db kv.Putter, hash types.Hash) error {
+	return db.Put(modules.Finality, safeBlockKey, hash.Bytes())
+}
+
+// TruncateFinality moves the finality markers that point above block number
+// to hash, the canonical block at number. Ancestors of a finalized or safe
+// block are final and safe as well. It has to run before the blocks above
+// number are deleted.
+func TruncateFinality(db kv.RwTx, number uint64, hash types.Hash) error {
+	for _, key := range [][]byte{finalizedBlockKey, safeBlockKey} {
+		marker := readFinalityHash(db, key)
+		if marker == (types.Hash{}) {
+			continue
+		}
+		if n := ReadHeaderNumber(db, marker); n != nil && *n <= number {
+			continue
+		}
+		if err := db.Put(modules.Finality, key, hash.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -59,3 +59,98 @@ func WriteChainConfig(db kv.RwTx, hash types.Hash, cfg *params.ChainConfig) erro
 	}
 	return nil
 }
+
+// syncCheckpointKey is the DatabaseInfo key holding the last sync checkpoint.
+var syncCheckpointKey = []byte("SyncCheckpoint")
+
+// SyncCheckpoint records how far the chain was synced when the node last
+// shut down, and the head it was syncing towards.
+type SyncCheckpoint struct {
+	Number uint64     `json:"number"`
+	Hash   types.Hash `json:"hash"`
+	Target uint64     `json:"target"`
+	Time   uint64     `json:"time"`
+}
+
+// ReadSyncCheckpoint retrieves the last sync checkpoint, or nil if none was
+// written.
+func ReadSyncCheckpoint(db kv.Getter) (*SyncCheckpoint, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, syncCheckpointKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var cp SyncCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid sync checkpoint JSON err: %v", err)
+	}
+	return &cp, nil
+}
+
+// WriteSyncCheckpoint stores the sync checkpoint.
+func WriteSyncCheckpoint(db kv.Putter, cp *SyncCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return db.Put(modules.DatabaseInfo, syncCheckpointKey, data)
+}
+
+// DeleteSyncCheckpoint removes the sync checkpoint.
+func DeleteSyncCheckpoint(db kv.Deleter) error {
+	return db.Delete(modules.DatabaseInfo, syncCheckpointKey)
+}
+
+// backfillKey returns the DatabaseInfo key holding the progress of an index
+// backfill.
+func backfillKey(name string) []byte {
+	return []byte("Backfill/" + name)
+}
+
+// BackfillProgress records how far an index has been built for the blocks
+// written before it was enabled. Blocks from Next to Target are still missing.
+type BackfillProgress struct {
+	Name    string `json:"name"`
+	Next    uint64 `json:"next"`
+	Target  uint64 `json:"target"`
+	Started uint64 `json:"started"`
+}
+
+// Done reports whether the backfill has covered every block up to Target.
+func (p *BackfillProgress) Done() bool {
+	return p.Next > p.Target
+}
+
+// ReadBackfillProgress retrieves the backfill progress of the named index, or
+// nil if the backfill never started.
+func ReadBackfillProgress(db kv.Getter, name string) (*BackfillProgress, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, backfillKey(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var p BackfillProgress
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid backfill progress JSON err: %v", err)
+	}
+	return &p, nil
+}
+
+// WriteBackfillProgress stores the backfill progress of an index.
+func WriteBackfillProgress(db kv.Putter, p *BackfillProgress) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return db.Put(modules.DatabaseInfo, backfillKey(p.Name), data)
+}
+
+// DeleteBackfillProgress removes the backfill progress of the named index so
+// the index is rebuilt from scratch.
+func DeleteBackfillProgress(db kv.Deleter, name string) error {
+	return db.Delete(modules.DatabaseInfo, backfillKey(name))
+}
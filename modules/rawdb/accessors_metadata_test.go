@@ -0,0 +1,55 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+func TestSyncCheckpoint(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if cp, err := ReadSyncCheckpoint(tx); err != nil || cp != nil {
+		t.Fatalf("empty database: checkpoint %v, err %v", cp, err)
+	}
+	want := &SyncCheckpoint{Number: 42, Hash: types.HexToHash("0x01"), Target: 100, Time: 1700000000}
+	if err := WriteSyncCheckpoint(tx, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSyncCheckpoint(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *want {
+		t.Fatalf("checkpoint = %+v, want %+v", got, want)
+	}
+	if err := DeleteSyncCheckpoint(tx); err != nil {
+		t.Fatal(err)
+	}
+	if cp, err := ReadSyncCheckpoint(tx); err != nil || cp != nil {
+		t.Fatalf("deleted checkpoint: checkpoint %v, err %v", cp, err)
+	}
+}
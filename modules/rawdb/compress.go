@@ -0,0 +1,199 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+)
+
+// Values of the compressed tables are stored as compressedPrefix followed by a
+// zstd frame. The tables hold protobuf messages, which never start with a zero
+// byte since field number 0 is invalid, so values written uncompressed by older
+// versions stay readable and are told apart by their first byte.
+const compressedPrefix = 0x00
+
+// CompressedTables lists the tables whose values are zstd compressed, with the
+// encoder level used for each.
+var CompressedTables = map[string]zstd.EncoderLevel{
+	modules.BlockTx:  zstd.SpeedDefault,
+	modules.Receipts: zstd.SpeedDefault,
+}
+
+// The encoders and decoder are safe for concurrent use through
+// EncodeAll/DecodeAll, so a single instance of each is kept for the process.
+var (
+	valueEncoders = make(map[zstd.EncoderLevel]*zstd.Encoder)
+	valueDecoder  *zstd.Decoder
+)
+
+func init() {
+	levels := []zstd.EncoderLevel{zstd.SpeedDefault}
+	for _, level := range CompressedTables {
+		levels = append(levels, level)
+	}
+	for _, level := range levels {
+		if _, ok := valueEncoders[level]; ok {
+			continue
+		}
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		valueEncoders[level] = enc
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		panic(err)
+	}
+	valueDecoder = dec
+}
+
+// compressValue returns the value to store for table. Values that do not
+// shrink are stored as they are.
+func compressValue(table string, v []byte) []byte {
+	level, ok := CompressedTables[table]
+	if !ok {
+		return v
+	}
+	return compressWith(level, v)
+}
+
+// compressItem compresses an item of a compressed freezer table, which uses
+// the same encoding as the table values.
+func compressItem(v []byte) []byte {
+	return compressWith(zstd.SpeedDefault, v)
+}
+
+func compressWith(level zstd.EncoderLevel, v []byte) []byte {
+	if len(v) == 0 {
+		return v
+	}
+	dst := make([]byte, 1, len(v)/2+16)
+	dst[0] = compressedPrefix
+	dst = valueEncoders[level].EncodeAll(v, dst)
+	if len(dst) >= len(v) {
+		return v
+	}
+	return dst
+}
+
+// decompressValue returns the plain value of a stored value, which may or may
+// not be compressed.
+func decompressValue(v []byte) ([]byte, error) {
+	if len(v) == 0 || v[0] != compressedPrefix {
+		return v, nil
+	}
+	out, err := valueDecoder.DecodeAll(v[1:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decompress value: %w", err)
+	}
+	return out, nil
+}
+
+// isCompressed reports whether a stored value is compressed.
+func isCompressed(v []byte) bool {
+	return len(v) > 0 && v[0] == compressedPrefix
+}
+
+// CompressStats summarises a CompressTable run.
+type CompressStats struct {
+	Entries    uint64 // values visited
+	Compressed uint64 // values rewritten compressed
+	Before     uint64 // bytes of the rewritten values before compression
+	After      uint64 // bytes of the rewritten values after compression
+}
+
+// compressBatch bounds the number of values rewritten per write transaction.
+const compressBatch = 10_000
+
+// CompressTable rewrites the values of a compressed table that are still
+// stored uncompressed, committing every compressBatch values so it can run on
+// large databases. Running it again only visits the remaining values. The
+// freed pages are reused by MDBX but the data file only shrinks after a
+// compaction.
+func CompressTable(ctx context.Context, db kv.RwDB, table string) (CompressStats, error) {
+	var stats CompressStats
+	if _, ok := CompressedTables[table]; !ok {
+		return stats, fmt.Errorf("table %s is not compressed", table)
+	}
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+
+	var from []byte
+	for done := false; !done; {
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			type entry struct{ k, v []byte }
+			var batch []entry
+
+			c, err := tx.Cursor(table)
+			if err != nil {
+				return err
+			}
+			k, v, err := c.Seek(from)
+			for ; k != nil && err == nil && len(batch) < compressBatch; k, v, err = c.Next() {
+				stats.Entries++
+				if isCompressed(v) {
+					continue
+				}
+				compressed := compressValue(table, v)
+				if len(compressed) == len(v) {
+					continue
+				}
+				batch = append(batch, entry{k: bytes.Clone(k), v: compressed})
+				stats.Before += uint64(len(v))
+				stats.After += uint64(len(compressed))
+			}
+			c.Close()
+			if err != nil {
+				return err
+			}
+			// The cursor stops on the first key of the next batch.
+			if k == nil {
+				done = true
+			} else {
+				from = bytes.Clone(k)
+			}
+			for _, e := range batch {
+				if err := tx.Put(table, e.k, e.v); err != nil {
+					return err
+				}
+			}
+			stats.Compressed += uint64(len(batch))
+			return nil
+		}); err != nil {
+			return stats, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return stats, ctx.Err()
+		case <-logEvery.C:
+			log.Info("Compressing table", "table", table, "entries", stats.Entries, "compressed", stats.Compressed)
+		default:
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func testReceipts(number uint64) block.Receipts {
+	var receipts block.Receipts
+	for i := 0; i < 20; i++ {
+		receipts = append(receipts, &block.Receipt{
+			Status:            block.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000 * uint64(i+1),
+			GasUsed:           21000,
+			TxHash:            types.Hash{byte(i)},
+			BlockNumber:       uint256.NewInt(number),
+			Logs: []*block.Log{{
+				Address:     types.Address{0xaa},
+				Topics:      []types.Hash{{0x01}, {0x02}},
+				Data:        make([]byte, 64),
+				BlockNumber: uint256.NewInt(number),
+			}},
+		})
+	}
+	return receipts
+}
+
+// Tests that receipts are stored compressed and that values written before
+// compression was enabled are still read.
+func TestCompressedReceipts(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	_, tx := memdb.NewTestTx(t)
+
+	receipts := testReceipts(1)
+	if err := WriteReceipts(tx, 1, receipts); err != nil {
+		t.Fatalf("WriteReceipts failed: %v", err)
+	}
+	plain, err := receipts.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := tx.GetOne(modules.Receipts, modules.EncodeBlockNumber(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCompressed(stored) || len(stored) >= len(plain) {
+		t.Fatalf("receipts not compressed: stored %d bytes, plain %d bytes", len(stored), len(plain))
+	}
+
+	// Uncompressed value as written by older versions
+	if err := tx.Put(modules.Receipts, modules.EncodeBlockNumber(2), plain); err != nil {
+		t.Fatal(err)
+	}
+	for _, number := range []uint64{1, 2} {
+		have := ReadRawReceipts(tx, number)
+		if len(have) != len(receipts) || have[19].CumulativeGasUsed != receipts[19].CumulativeGasUsed {
+			t.Fatalf("block %d: receipts mismatch", number)
+		}
+	}
+	batch, err := ReadRawReceiptsRange(tx, 1, 2)
+	if err != nil {
+		t.Fatalf("ReadRawReceiptsRange failed: %v", err)
+	}
+	if len(batch) != 2 || len(batch[1]) != 20 || len(batch[2]) != 20 {
+		t.Fatalf("unexpected receipts in range: %v", batch)
+	}
+}
+
+func TestCompressValue(t *testing.T) {
+	// Values that do not shrink are stored as they are
+	small := []byte{0x0a, 0x01}
+	if have := compressValue(modules.BlockTx, small); !bytes.Equal(have, small) {
+		t.Fatalf("small value rewritten: %x", have)
+	}
+	// Tables without compression are left alone
+	large := bytes.Repeat([]byte{0x0a, 0x20}, 512)
+	if have := compressValue(modules.Headers, large); !bytes.Equal(have, large) {
+		t.Fatal("value of an uncompressed table rewritten")
+	}
+	compressed := compressValue(modules.BlockTx, large)
+	if !isCompressed(compressed) {
+		t.Fatal("large value not compressed")
+	}
+	if have, err := decompressValue(compressed); err != nil || !bytes.Equal(have, large) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if _, err := decompressValue([]byte{compressedPrefix, 0x01, 0x02}); err == nil {
+		t.Fatal("corrupt value decoded")
+	}
+}
+
+// Tests that CompressTable rewrites old values across several batches and
+// that a second run has nothing left to do.
+func TestCompressTable(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+
+	const count = compressBatch + 500
+	value := func(i uint64) []byte {
+		return append([]byte{0x0a}, bytes.Repeat(modules.EncodeBlockNumber(i), 16)...)
+	}
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		for i := uint64(0); i < count; i++ {
+			if err := tx.Put(modules.BlockTx, modules.EncodeBlockNumber(i), value(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := CompressTable(ctx, db, modules.BlockTx)
+	if err != nil {
+		t.Fatalf("CompressTable failed: %v", err)
+	}
+	if stats.Entries != count || stats.Compressed != count || stats.After >= stats.Before {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		return tx.ForEach(modules.BlockTx, nil, func(k, v []byte) error {
+			i := binary.BigEndian.Uint64(k)
+			plain, err := decompressValue(v)
+			if err != nil {
+				return err
+			}
+			if !isCompressed(v) || !bytes.Equal(plain, value(i)) {
+				t.Fatalf("value %d not rewritten correctly", i)
+			}
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = CompressTable(ctx, db, modules.BlockTx)
+	if err != nil {
+		t.Fatalf("second CompressTable failed: %v", err)
+	}
+	if stats.Entries != count || stats.Compressed != 0 {
+		t.Fatalf("unexpected stats on second run: %+v", stats)
+	}
+	if _, err := CompressTable(ctx, db, modules.Headers); err == nil {
+		t.Fatal("compressing an uncompressed table succeeded")
+	}
+}
@@ -0,0 +1,485 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+)
+
+// The kinds of data kept in the freezer, one table each. Item n of every table
+// belongs to the canonical block n.
+const (
+	// AncientHashes holds the canonical block hashes.
+	AncientHashes = "hashes"
+	// AncientHeaders holds the encoded headers.
+	AncientHeaders = "headers"
+	// AncientBodies holds every transaction of a block, system transactions
+	// included, each prefixed with its uvarint encoded length.
+	AncientBodies = "bodies"
+	// AncientReceipts holds the encoded receipts.
+	AncientReceipts = "receipts"
+)
+
+// freezerTables lists the freezer tables and whether their items are zstd
+// compressed.
+var freezerTables = map[string]bool{
+	AncientHashes:   false,
+	AncientHeaders:  false,
+	AncientBodies:   true,
+	AncientReceipts: true,
+}
+
+// freezeBatch bounds the number of blocks moved per write transaction.
+const freezeBatch = 1000
+
+// ancientPrunedKey is the DatabaseInfo key holding the number of frozen blocks
+// already removed from the database.
+var ancientPrunedKey = []byte("AncientPruned")
+
+// Freezer keeps the immutable part of the chain in append-only flat files
+// next to the database. Blocks are moved once they are final and old enough,
+// which keeps their headers, transactions and receipts out of the database
+// write path and lets the files be backed up by copying them.
+type Freezer struct {
+	dir      string
+	readonly bool
+	tables   map[string]*freezerTable
+	frozen   atomic.Uint64
+}
+
+// NewFreezer opens the freezer in dir, creating it if needed. Tables left
+// uneven by an unclean shutdown are truncated to the shortest one. A
+// read-only freezer serves the blocks frozen by the process owning dir,
+// including those frozen after it was opened.
+func NewFreezer(dir string, readonly bool) (*Freezer, error) {
+	if !readonly {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	f := &Freezer{dir: dir, readonly: readonly, tables: make(map[string]*freezerTable, len(freezerTables))}
+	for name, compress := range freezerTables {
+		table, err := openFreezerTable(dir, name, compress, readonly)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+	if readonly {
+		log.Info("Opened ancient store read-only", "dir", dir, "blocks", f.Ancients())
+		return f, nil
+	}
+	frozen := uint64(0)
+	first := true
+	for _, table := range f.tables {
+		if items := table.Items(); first || items < frozen {
+			frozen, first = items, false
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.TruncateHead(frozen); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	f.frozen.Store(frozen)
+	log.Info("Opened ancient store", "dir", dir, "blocks", frozen)
+	return f, nil
+}
+
+// Ancients returns the number of frozen blocks. Blocks below it are read
+// from the freezer.
+func (f *Freezer) Ancients() uint64 {
+	if f.readonly {
+		// Receipts are appended last, a block with receipts is complete.
+		return f.tables[AncientReceipts].Items()
+	}
+	return f.frozen.Load()
+}
+
+// Ancient returns the item of the given kind for the frozen block number.
+func (f *Freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table, ok := f.tables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown ancient kind %q", kind)
+	}
+	if number >= f.Ancients() {
+		return nil, fmt.Errorf("%s item %d: %w", kind, number, errOutOfBounds)
+	}
+	return table.Retrieve(number)
+}
+
+// Close closes the freezer files.
+func (f *Freezer) Close() error {
+	var errs []error
+	for _, table := range f.tables {
+		errs = append(errs, table.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// frozenBlock is a canonical block read from the database to be frozen.
+type frozenBlock struct {
+	hash     types.Hash
+	header   []byte
+	body     []byte
+	receipts []byte
+}
+
+// Freeze moves the canonical blocks below limit that are not frozen yet from
+// the database into the freezer, and returns the number of blocks moved.
+// Blocks are first appended and synced to the freezer files and only then
+// removed from the database, so an interrupted run loses nothing and the
+// next run completes the removal.
+func (f *Freezer) Freeze(ctx context.Context, db kv.RwDB, limit uint64) (uint64, error) {
+	if f.readonly {
+		return 0, ErrReadOnly
+	}
+	if err := f.prune(ctx, db); err != nil {
+		return 0, err
+	}
+	var moved uint64
+	for f.Ancients() < limit {
+		from := f.Ancients()
+		to := from + freezeBatch
+		if to > limit {
+			to = limit
+		}
+		blocks := make([]frozenBlock, 0, to-from)
+		if err := db.View(ctx, func(tx kv.Tx) error {
+			for number := from; number < to; number++ {
+				b, err := readFrozenBlock(tx, number)
+				if err != nil {
+					return err
+				}
+				blocks = append(blocks, b)
+			}
+			return nil
+		}); err != nil {
+			return moved, err
+		}
+		if err := f.append(from, blocks); err != nil {
+			return moved, err
+		}
+		moved += uint64(len(blocks))
+		if err := f.prune(ctx, db); err != nil {
+			return moved, err
+		}
+		select {
+		case <-ctx.Done():
+			return moved, ctx.Err()
+		default:
+		}
+	}
+	return moved, nil
+}
+
+// readFrozenBlock reads the data of canonical block number that goes into the
+// freezer.
+func readFrozenBlock(tx kv.Tx, number uint64) (frozenBlock, error) {
+	hash, err := ReadCanonicalHash(tx, number)
+	if err != nil {
+		return frozenBlock{}, err
+	}
+	if hash == (types.Hash{}) {
+		return frozenBlock{}, fmt.Errorf("canonical hash of block %d missing", number)
+	}
+	b := frozenBlock{hash: hash}
+	if b.header, err = tx.GetOne(modules.Headers, modules.HeaderKey(number, hash)); err != nil {
+		return frozenBlock{}, err
+	}
+	if len(b.header) == 0 {
+		return frozenBlock{}, fmt.Errorf("header of block %d missing", number)
+	}
+	stored, err := ReadStorageBody(tx, hash, number)
+	if err != nil {
+		return frozenBlock{}, fmt.Errorf("body of block %d: %w", number, err)
+	}
+	var body []byte
+	if err := tx.ForAmount(modules.BlockTx, modules.EncodeBlockNumber(stored.BaseTxId), stored.TxAmount, func(k, v []byte) error {
+		v, err := decompressValue(v)
+		if err != nil {
+			return err
+		}
+		body = binary.AppendUvarint(body, uint64(len(v)))
+		body = append(body, v...)
+		return nil
+	}); err != nil {
+		return frozenBlock{}, err
+	}
+	b.body = body
+	receipts, err := tx.GetOne(modules.Receipts, modules.EncodeBlockNumber(number))
+	if err != nil {
+		return frozenBlock{}, err
+	}
+	if receipts, err = decompressValue(receipts); err != nil {
+		return frozenBlock{}, err
+	}
+	// The values are only valid within the transaction.
+	b.header = bytes.Clone(b.header)
+	b.receipts = bytes.Clone(receipts)
+	return b, nil
+}
+
+// append adds the blocks from number on to the freezer and syncs the files.
+func (f *Freezer) append(number uint64, blocks []frozenBlock) error {
+	for i, b := range blocks {
+		n := number + uint64(i)
+		for _, item := range []struct {
+			kind string
+			data []byte
+		}{
+			{AncientHashes, b.hash[:]},
+			{AncientHeaders, b.header},
+			{AncientBodies, b.body},
+			{AncientReceipts, b.receipts},
+		} {
+			if err := f.tables[item.kind].Append(n, item.data); err != nil {
+				return err
+			}
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			return err
+		}
+	}
+	f.frozen.Add(uint64(len(blocks)))
+	return nil
+}
+
+// prune removes the frozen blocks from the database that are still stored
+// there, in batches of freezeBatch blocks.
+func (f *Freezer) prune(ctx context.Context, db kv.RwDB) error {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for {
+		var done bool
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			from, err := readAncientPruned(tx)
+			if err != nil {
+				return err
+			}
+			to := f.Ancients()
+			if from > to {
+				return fmt.Errorf("ancient store holds %d blocks, but %d were removed from the database", to, from)
+			}
+			if from == to {
+				done = true
+				return nil
+			}
+			if to > from+freezeBatch {
+				to = from + freezeBatch
+			}
+			for number := from; number < to; number++ {
+				if err := deleteFrozenBlock(tx, number); err != nil {
+					return err
+				}
+			}
+			select {
+			case <-logEvery.C:
+				log.Info("Removing frozen blocks from database", "number", to)
+			default:
+			}
+			return tx.Put(modules.DatabaseInfo, ancientPrunedKey, modules.EncodeBlockNumber(to))
+		}); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// readAncientPruned returns the number of frozen blocks already removed from
+// the database.
+func readAncientPruned(db kv.Getter) (uint64, error) {
+	v, err := db.GetOne(modules.DatabaseInfo, ancientPrunedKey)
+	if err != nil || len(v) != 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// FrozenBlocks returns the number of blocks moved to the freezer. They can no
+// longer change, so the chain can not be unwound below them.
+func FrozenBlocks(db kv.Getter) (uint64, error) {
+	frozen, err := readAncientPruned(db)
+	if err != nil {
+		return 0, err
+	}
+	if f := ancients.Load(); f != nil {
+		frozen = max(frozen, f.Ancients())
+	}
+	return frozen, nil
+}
+
+// deleteFrozenBlock removes the headers, transactions and receipts of block
+// number from the database. Side chains at that height can no longer become
+// canonical and are removed altogether. The canonical body index, hash
+// mappings, senders and logs stay in the database.
+func deleteFrozenBlock(tx kv.RwTx, number uint64) error {
+	canonical, err := ReadCanonicalHash(tx, number)
+	if err != nil {
+		return err
+	}
+	prefix := modules.EncodeBlockNumber(number)
+	var keys [][]byte
+	if err := tx.ForPrefix(modules.Headers, prefix, func(k, _ []byte) error {
+		keys = append(keys, bytes.Clone(k))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		stored, err := ReadBodyForStorageByKey(tx, k)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			for id := stored.BaseTxId; id < stored.BaseTxId+uint64(stored.TxAmount); id++ {
+				if err := tx.Delete(modules.BlockTx, modules.EncodeBlockNumber(id)); err != nil {
+					return err
+				}
+			}
+		}
+		if !bytes.Equal(k[modules.NumberLength:], canonical[:]) {
+			if err := tx.Delete(modules.BlockBody, k); err != nil {
+				return err
+			}
+			if err := tx.Delete(modules.HeaderNumber, k[modules.NumberLength:]); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(modules.Headers, k); err != nil {
+			return err
+		}
+	}
+	return tx.Delete(modules.Receipts, prefix)
+}
+
+// AncientReader gives access to frozen blocks, either from a local Freezer or
+// from a remote one.
+type AncientReader interface {
+	// Ancients returns the number of frozen blocks.
+	Ancients() uint64
+	// Ancient returns the item of the given kind for frozen block number.
+	Ancient(kind string, number uint64) ([]byte, error)
+}
+
+type ancientHolder struct {
+	AncientReader
+}
+
+// ancients is the freezer the readers fall back to, nil if the chain is not
+// frozen.
+var ancients atomic.Pointer[ancientHolder]
+
+// SetAncients makes the readers of this package serve frozen blocks from f.
+// Passing nil detaches the freezer.
+func SetAncients(f AncientReader) {
+	if f == nil {
+		ancients.Store(nil)
+		return
+	}
+	ancients.Store(&ancientHolder{f})
+}
+
+// readAncient returns the item of the given kind for block number if the
+// block is frozen.
+func readAncient(kind string, number uint64) ([]byte, bool) {
+	f := ancients.Load()
+	if f == nil || number >= f.Ancients() {
+		return nil, false
+	}
+	data, err := f.Ancient(kind, number)
+	if err != nil {
+		log.Error("Failed to read ancient item", "kind", kind, "number", number, "err", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// isFrozen reports whether block number is in the freezer.
+func isFrozen(number uint64) bool {
+	f := ancients.Load()
+	return f != nil && number < f.Ancients()
+}
+
+// readAncientHeader returns the encoded header of block number if it is frozen
+// and has the given hash.
+func readAncientHeader(hash types.Hash, number uint64) ([]byte, bool) {
+	h, ok := readAncient(AncientHashes, number)
+	if !ok || !bytes.Equal(h, hash[:]) {
+		return nil, false
+	}
+	return readAncient(AncientHeaders, number)
+}
+
+// readAncientTransactions returns the encoded transactions of block number,
+// system transactions included, if it is frozen and has the given hash.
+func readAncientTransactions(hash types.Hash, number uint64) ([][]byte, bool, error) {
+	if h, ok := readAncient(AncientHashes, number); !ok || !bytes.Equal(h, hash[:]) {
+		return nil, false, nil
+	}
+	data, ok := readAncient(AncientBodies, number)
+	if !ok {
+		return nil, false, nil
+	}
+	var txs [][]byte
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return nil, true, fmt.Errorf("invalid ancient body of block %d", number)
+		}
+		txs = append(txs, data[n:n+int(size)])
+		data = data[n+int(size):]
+	}
+	return txs, true, nil
+}
+
+// decodeAncientTransactions decodes the user transactions of a frozen body,
+// leaving out the system transactions at its start and end like ReadBody.
+func decodeAncientTransactions(raw [][]byte) ([]*transaction.Transaction, error) {
+	if len(raw) < 2 {
+		return nil, fmt.Errorf("ancient body has too few transactions: %d", len(raw))
+	}
+	raw = raw[1 : len(raw)-1]
+	txs := make([]*transaction.Transaction, len(raw))
+	for i, data := range raw {
+		txs[i] = new(transaction.Transaction)
+		if err := txs[i].Unmarshal(data); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// indexEntrySize is the size of an index entry: the big endian offset in the
+// data file at which the item ends.
+const indexEntrySize = 8
+
+var (
+	errOutOfBounds   = errors.New("out of bounds")
+	errClosed        = errors.New("freezer closed")
+	errNotSequential = errors.New("append not sequential")
+)
+
+// freezerTable is an append-only store of numbered items. Item data is
+// appended to <name>.dat and, for every item, <name>.idx holds the offset at
+// which it ends in the data file, so item i spans the bytes between the end
+// offsets of items i-1 and i.
+//
+// A read-only table follows the items appended by the process writing it.
+type freezerTable struct {
+	mu       sync.RWMutex
+	name     string
+	compress bool
+	readonly bool
+	data     *os.File
+	index    *os.File
+	items    uint64 // number of items stored
+	dataSize uint64 // end offset of the last item
+}
+
+// openFreezerTable opens or creates the table files in dir. Entries written
+// partially by an unclean shutdown are dropped. A read-only table must exist
+// and is left as it is.
+func openFreezerTable(dir, name string, compress, readonly bool) (*freezerTable, error) {
+	flag := os.O_RDWR | os.O_CREATE
+	if readonly {
+		flag = os.O_RDONLY
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), flag, 0644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	t := &freezerTable{name: name, compress: compress, readonly: readonly, data: data, index: index}
+	if readonly {
+		return t, nil
+	}
+	if err := t.repair(); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair truncates the index to whole entries whose data is present, and the
+// data file to the end of the last indexed item.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(stat.Size()) / indexEntrySize
+	if stat, err = t.data.Stat(); err != nil {
+		return err
+	}
+	dataSize := uint64(stat.Size())
+
+	var end uint64
+	for items > 0 {
+		if end, err = t.offset(items - 1); err != nil {
+			return err
+		}
+		if end <= dataSize {
+			break
+		}
+		items--
+	}
+	if items == 0 {
+		end = 0
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+	t.items, t.dataSize = items, end
+	return nil
+}
+
+// offset reads the end offset of item n from the index.
+func (t *freezerTable) offset(n uint64) (uint64, error) {
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64(n*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// Items returns the number of items stored.
+func (t *freezerTable) Items() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.count()
+}
+
+// count returns the number of items stored. A read-only table counts the
+// complete index entries, so it sees the items appended by the writer.
+func (t *freezerTable) count() uint64 {
+	if !t.readonly || t.index == nil {
+		return t.items
+	}
+	stat, err := t.index.Stat()
+	if err != nil {
+		return 0
+	}
+	return uint64(stat.Size()) / indexEntrySize
+}
+
+// Append stores item n, which must be the next item of the table.
+func (t *freezerTable) Append(n uint64, item []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.data == nil {
+		return errClosed
+	}
+	if t.readonly {
+		return ErrReadOnly
+	}
+	if n != t.items {
+		return fmt.Errorf("%s: %w: have %d, want %d", t.name, errNotSequential, n, t.items)
+	}
+	if t.compress {
+		item = compressItem(item)
+	}
+	if _, err := t.data.WriteAt(item, int64(t.dataSize)); err != nil {
+		return err
+	}
+	end := t.dataSize + uint64(len(item))
+	var buf [indexEntrySize]byte
+	binary.BigEndian.PutUint64(buf[:], end)
+	if _, err := t.index.WriteAt(buf[:], int64(t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.items++
+	t.dataSize = end
+	return nil
+}
+
+// Retrieve returns item n.
+func (t *freezerTable) Retrieve(n uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.data == nil {
+		return nil, errClosed
+	}
+	if n >= t.count() {
+		return nil, fmt.Errorf("%s item %d: %w", t.name, n, errOutOfBounds)
+	}
+	var start uint64
+	if n > 0 {
+		var err error
+		if start, err = t.offset(n - 1); err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.offset(n)
+	if err != nil {
+		return nil, err
+	}
+	item := make([]byte, end-start)
+	if _, err := t.data.ReadAt(item, int64(start)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if t.compress {
+		return decompressValue(item)
+	}
+	return item, nil
+}
+
+// TruncateHead drops the items from n on.
+func (t *freezerTable) TruncateHead(n uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.data == nil {
+		return errClosed
+	}
+	if t.readonly {
+		return ErrReadOnly
+	}
+	if n >= t.items {
+		return nil
+	}
+	var end uint64
+	if n > 0 {
+		var err error
+		if end, err = t.offset(n - 1); err != nil {
+			return err
+		}
+	}
+	if err := t.index.Truncate(int64(n * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+	t.items, t.dataSize = n, end
+	return nil
+}
+
+// Sync flushes the data file before the index, so that an indexed item is
+// always present on disk.
+func (t *freezerTable) Sync() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.data == nil {
+		return errClosed
+	}
+	if t.readonly {
+		return nil
+	}
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the table files.
+func (t *freezerTable) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.data == nil {
+		return nil
+	}
+	err := errors.Join(t.data.Close(), t.index.Close())
+	t.data, t.index = nil, nil
+	return err
+}
@@ -0,0 +1,296 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func TestFreezerTable(t *testing.T) {
+	dir := t.TempDir()
+	table, err := openFreezerTable(dir, "test", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := func(i uint64) []byte {
+		return bytes.Repeat([]byte{byte(i)}, int(i)*100)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if err := table.Append(i, item(i)); err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+	if err := table.Append(11, item(11)); !errors.Is(err, errNotSequential) {
+		t.Fatalf("out of order append: have %v, want %v", err, errNotSequential)
+	}
+	if _, err := table.Retrieve(10); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("retrieve past the end: have %v, want %v", err, errOutOfBounds)
+	}
+	if err := table.TruncateHead(8); err != nil {
+		t.Fatal(err)
+	}
+	table.Close()
+
+	// Simulate a torn write of the last item
+	stat, err := os.Stat(filepath.Join(dir, "test.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(filepath.Join(dir, "test.dat"), stat.Size()-1); err != nil {
+		t.Fatal(err)
+	}
+	if table, err = openFreezerTable(dir, "test", true, false); err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	if table.Items() != 7 {
+		t.Fatalf("items after repair: have %d, want 7", table.Items())
+	}
+	for i := uint64(0); i < 7; i++ {
+		have, err := table.Retrieve(i)
+		if err != nil || !bytes.Equal(have, item(i)) {
+			t.Fatalf("item %d mismatch: %v", i, err)
+		}
+	}
+	if err := table.Append(7, item(7)); err != nil {
+		t.Fatalf("append after repair failed: %v", err)
+	}
+}
+
+// writeTestChain writes count canonical blocks with raw transactions and
+// receipts, plus a side chain header and body at height 1.
+func writeTestChain(t *testing.T, db kv.RwDB, count uint64) []types.Hash {
+	t.Helper()
+	var hashes []types.Hash
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		var parent types.Hash
+		for number := uint64(0); number < count; number++ {
+			header := &block.Header{Number: uint256.NewInt(number), ParentHash: parent, Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0)}
+			WriteHeader(tx, header)
+			hash := header.Hash()
+			if err := WriteCanonicalHash(tx, hash, number); err != nil {
+				return err
+			}
+			if err := writeTestBody(tx, hash, number, 4); err != nil {
+				return err
+			}
+			if err := WriteReceipts(tx, number, testReceipts(number)); err != nil {
+				return err
+			}
+			hashes = append(hashes, hash)
+			parent = hash
+		}
+		side := &block.Header{Number: uint256.NewInt(1), ParentHash: hashes[0], Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0)}
+		WriteHeader(tx, side)
+		return writeTestBody(tx, side.Hash(), 1, 2)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return hashes
+}
+
+func writeTestBody(tx kv.RwTx, hash types.Hash, number uint64, amount int) error {
+	baseTxId, err := tx.IncrementSequence(modules.BlockTx, uint64(amount))
+	if err != nil {
+		return err
+	}
+	var txs [][]byte
+	for i := 0; i < amount; i++ {
+		txs = append(txs, append([]byte{0x0a}, bytes.Repeat([]byte{byte(number), byte(i)}, 50)...))
+	}
+	if err := WriteRawTransactions(tx, txs, baseTxId); err != nil {
+		return err
+	}
+	return WriteBodyForStorage(tx, hash, number, &block.BodyForStorage{BaseTxId: baseTxId, TxAmount: uint32(amount)})
+}
+
+// Tests that frozen blocks are removed from the database and still served by
+// the readers from the freezer.
+func TestFreeze(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+	hashes := writeTestChain(t, db, 10)
+
+	// Expected data read before freezing
+	var (
+		wantHeaders [][]byte
+		wantTxs     [][]byte
+	)
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		for number, hash := range hashes {
+			wantHeaders = append(wantHeaders, bytes.Clone(ReadHeaderRAW(tx, hash, uint64(number))))
+		}
+		var err error
+		wantTxs, err = RawTransactionsRange(tx, 0, 9)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	f, err := NewFreezer(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetAncients(f)
+	defer SetAncients(nil)
+
+	moved, err := f.Freeze(ctx, db, 6)
+	if err != nil {
+		t.Fatalf("Freeze failed: %v", err)
+	}
+	if moved != 6 || f.Ancients() != 6 {
+		t.Fatalf("frozen blocks: moved %d, ancients %d, want 6", moved, f.Ancients())
+	}
+
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		for number, hash := range hashes {
+			n := uint64(number)
+			stored, err := tx.GetOne(modules.Headers, modules.HeaderKey(n, hash))
+			if err != nil {
+				return err
+			}
+			if frozen := n < 6; frozen == (len(stored) > 0) {
+				t.Errorf("block %d: header in database %v, frozen %v", n, len(stored) > 0, frozen)
+			}
+			if have := ReadHeaderRAW(tx, hash, n); !bytes.Equal(have, wantHeaders[n]) {
+				t.Errorf("block %d: header mismatch", n)
+			}
+			if !HasBlock(tx, hash, n) || !HasReceipts(tx, n) {
+				t.Errorf("block %d: missing after freezing", n)
+			}
+			if receipts := ReadRawReceipts(tx, n); len(receipts) != 20 || receipts[19].BlockNumber.Uint64() != n {
+				t.Errorf("block %d: receipts mismatch", n)
+			}
+			if headers, err := ReadHeadersByNumber(tx, n); err != nil || len(headers) == 0 || headers[0].Hash() != hash {
+				t.Errorf("block %d: headers by number mismatch: %v", n, err)
+			}
+		}
+		if ReadHeaderRAW(tx, hashes[2], 1) != nil {
+			t.Error("header with wrong hash served from freezer")
+		}
+		if headers, err := ReadHeadersByNumber(tx, 1); err != nil || len(headers) != 1 {
+			t.Errorf("side chain at frozen height not removed: %d headers, %v", len(headers), err)
+		}
+		txs, err := RawTransactionsRange(tx, 0, 9)
+		if err != nil {
+			return err
+		}
+		if len(txs) != len(wantTxs) {
+			t.Fatalf("transactions: have %d, want %d", len(txs), len(wantTxs))
+		}
+		for i := range txs {
+			if !bytes.Equal(txs[i], wantTxs[i]) {
+				t.Errorf("transaction %d mismatch", i)
+			}
+		}
+		receipts, err := ReadRawReceiptsRange(tx, 4, 4)
+		if err != nil {
+			return err
+		}
+		if len(receipts) != 4 || len(receipts[5]) != 20 || len(receipts[6]) != 20 {
+			t.Errorf("receipts range across the freezer boundary mismatch")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening keeps the frozen blocks
+	f.Close()
+	if f, err = NewFreezer(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	SetAncients(f)
+	if f.Ancients() != 6 {
+		t.Fatalf("ancients after reopen: have %d, want 6", f.Ancients())
+	}
+	if moved, err = f.Freeze(ctx, db, 8); err != nil || moved != 2 {
+		t.Fatalf("second Freeze: moved %d, err %v", moved, err)
+	}
+}
+
+func TestFreezerTableReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := openFreezerTable(dir, "test", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	if err := writer.Append(0, []byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := openFreezerTable(dir, "test", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if err := reader.Append(1, []byte{4}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("append to read-only table: have %v, want %v", err, ErrReadOnly)
+	}
+	if err := reader.TruncateHead(0); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("truncate read-only table: have %v, want %v", err, ErrReadOnly)
+	}
+
+	// Items appended by the writer become visible without reopening
+	if err := writer.Append(1, []byte{4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if reader.Items() != 2 {
+		t.Fatalf("items seen by reader: have %d, want 2", reader.Items())
+	}
+	if have, err := reader.Retrieve(1); err != nil || !bytes.Equal(have, []byte{4, 5}) {
+		t.Fatalf("retrieve from reader: have %x, %v", have, err)
+	}
+}
+
+func TestReadOnlyDB(t *testing.T) {
+	db := ReadOnlyDB(memdb.New(""))
+	defer db.Close()
+	if err := db.Update(context.Background(), func(kv.RwTx) error { return nil }); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("update: have %v, want %v", err, ErrReadOnly)
+	}
+	if _, err := db.BeginRw(context.Background()); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("begin rw: have %v, want %v", err, ErrReadOnly)
+	}
+	if err := db.View(context.Background(), func(kv.Tx) error { return nil }); err != nil {
+		t.Fatalf("view: %v", err)
+	}
+}
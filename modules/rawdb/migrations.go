@@ -0,0 +1,209 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/log"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// Migration changes the layout of existing data. Migrations run in list order
+// and each one runs once per database. Up may commit several transactions,
+// so it must be safe to run again after an interruption: the migration is
+// only recorded as applied once Up returns.
+type Migration struct {
+	Name        string
+	Description string
+	Up          func(ctx context.Context, db kv.RwDB) error
+}
+
+// Migrations lists the schema migrations in the order they are applied. New
+// migrations are appended, existing ones are never reordered or removed.
+var Migrations = []Migration{
+	{
+		Name:        "compress_chain_tables",
+		Description: "zstd compress the stored transactions and receipts",
+		Up: func(ctx context.Context, db kv.RwDB) error {
+			for _, table := range []string{modules.BlockTx, modules.Receipts} {
+				stats, err := CompressTable(ctx, db, table)
+				if err != nil {
+					return fmt.Errorf("compress %s: %w", table, err)
+				}
+				log.Info("Table compressed", "table", table, "entries", stats.Entries, "compressed", stats.Compressed)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "count_code_refs",
+		Description: "count the references of contracts to their code",
+		Up: func(ctx context.Context, db kv.RwDB) error {
+			stats, err := state.CountCodeRefs(ctx, db)
+			if err != nil {
+				return err
+			}
+			log.Info("Contract code references counted", "contracts", stats.Contracts, "codes", stats.Codes,
+				"shared", types.StorageSize(stats.Shared))
+			return nil
+		},
+	},
+}
+
+// MigrationRecord is stored for every applied migration.
+type MigrationRecord struct {
+	Name    string `json:"name"`
+	Applied uint64 `json:"applied"` // unix time
+	Version string `json:"version"` // node version that applied it
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// ReadMigrations returns the records of the applied migrations by name.
+func ReadMigrations(db kv.Tx) (map[string]*MigrationRecord, error) {
+	applied := make(map[string]*MigrationRecord)
+	if err := db.ForEach(modules.Migrations, nil, func(k, v []byte) error {
+		var rec MigrationRecord
+		if err := json.Unmarshal(v, &rec); err != nil {
+			return fmt.Errorf("invalid migration record %q: %w", k, err)
+		}
+		applied[string(k)] = &rec
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// writeMigration records m, the migration at position i of the list, as
+// applied and raises the schema version of db accordingly.
+func writeMigration(db kv.RwTx, i int, m Migration, skipped bool) error {
+	data, err := json.Marshal(&MigrationRecord{
+		Name:    m.Name,
+		Applied: uint64(time.Now().Unix()),
+		Version: params.VersionWithCommit(params.GitCommit, ""),
+		Skipped: skipped,
+	})
+	if err != nil {
+		return err
+	}
+	if err := db.Put(modules.Migrations, []byte(m.Name), data); err != nil {
+		return err
+	}
+	current, err := ReadSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if version := uint64(SchemaVersion + i + 1); version > current {
+		return db.Put(modules.DatabaseInfo, []byte(SchemaVersionKey), modules.EncodeBlockNumber(version))
+	}
+	return nil
+}
+
+// ReadSchemaVersion returns the schema version of db: SchemaVersion plus the
+// number of migrations applied to it, or 0 if it predates version tracking.
+func ReadSchemaVersion(db kv.Getter) (uint64, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, []byte(SchemaVersionKey))
+	if err != nil || len(data) != 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// PendingMigrations returns the migrations of the list not yet applied to db.
+func PendingMigrations(db kv.Tx, migrations []Migration) ([]Migration, error) {
+	applied, err := ReadMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if applied[m.Name] == nil {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// migrationIndex returns the position of the named migration in the list.
+func migrationIndex(migrations []Migration, name string) int {
+	for i, m := range migrations {
+		if m.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// RunMigrations applies the pending migrations of the list in order and
+// returns them. A database without a genesis block has no data to migrate,
+// so the migrations are only recorded. With dryRun nothing is changed and
+// the migrations that would run are returned.
+func RunMigrations(ctx context.Context, db kv.RwDB, migrations []Migration, dryRun bool) ([]Migration, error) {
+	var (
+		pending []Migration
+		fresh   bool
+	)
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		genesis, err := ReadCanonicalHash(tx, 0)
+		if err != nil {
+			return err
+		}
+		fresh = genesis == (types.Hash{})
+		pending, err = PendingMigrations(tx, migrations)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 || dryRun {
+		return pending, nil
+	}
+
+	if fresh {
+		return pending, db.Update(ctx, func(tx kv.RwTx) error {
+			for _, m := range pending {
+				if err := writeMigration(tx, migrationIndex(migrations, m.Name), m, true); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	for i, m := range pending {
+		log.Info(fmt.Sprintf("[%d/%d] Applying database migration", i+1, len(pending)), "name", m.Name, "description", m.Description)
+		start := time.Now()
+		if err := m.Up(ctx, db); err != nil {
+			return pending[:i], fmt.Errorf("migration %s: %w", m.Name, err)
+		}
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			return writeMigration(tx, migrationIndex(migrations, m.Name), m, false)
+		}); err != nil {
+			return pending[:i], err
+		}
+		log.Info(fmt.Sprintf("[%d/%d] Applied database migration", i+1, len(pending)), "name", m.Name, "elapsed", time.Since(start))
+	}
+	return pending, nil
+}
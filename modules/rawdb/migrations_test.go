@@ -0,0 +1,134 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func TestRunMigrations(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		return WriteCanonicalHash(tx, types.Hash{0x01}, 0)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var runs []string
+	failing := errors.New("interrupted")
+	fail := true
+	migrations := []Migration{
+		{Name: "first", Up: func(context.Context, kv.RwDB) error {
+			runs = append(runs, "first")
+			return nil
+		}},
+		{Name: "second", Up: func(context.Context, kv.RwDB) error {
+			runs = append(runs, "second")
+			if fail {
+				return failing
+			}
+			return nil
+		}},
+	}
+
+	pending, err := RunMigrations(ctx, db, migrations, true)
+	if err != nil || len(pending) != 2 || len(runs) != 0 {
+		t.Fatalf("dry run: pending %d, runs %v, err %v", len(pending), runs, err)
+	}
+
+	// An interrupted migration stays pending
+	if applied, err := RunMigrations(ctx, db, migrations, false); !errors.Is(err, failing) || len(applied) != 1 {
+		t.Fatalf("failing run: applied %d, err %v", len(applied), err)
+	}
+	checkSchema(t, db, migrations, 1, SchemaVersion+1)
+
+	fail = false
+	if applied, err := RunMigrations(ctx, db, migrations, false); err != nil || len(applied) != 1 || applied[0].Name != "second" {
+		t.Fatalf("resumed run: applied %v, err %v", applied, err)
+	}
+	checkSchema(t, db, migrations, 2, SchemaVersion+2)
+	if want := []string{"first", "second", "second"}; len(runs) != len(want) {
+		t.Fatalf("runs: have %v, want %v", runs, want)
+	}
+
+	if applied, err := RunMigrations(ctx, db, migrations, false); err != nil || len(applied) != 0 {
+		t.Fatalf("up to date run: applied %d, err %v", len(applied), err)
+	}
+}
+
+// Tests that the migrations of a database without chain data are recorded
+// without running them.
+func TestRunMigrationsFreshDatabase(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+
+	migrations := []Migration{{Name: "never", Up: func(context.Context, kv.RwDB) error {
+		t.Fatal("migration ran on a fresh database")
+		return nil
+	}}}
+	if _, err := RunMigrations(ctx, db, migrations, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		applied, err := ReadMigrations(tx)
+		if err != nil {
+			return err
+		}
+		if rec := applied["never"]; rec == nil || !rec.Skipped {
+			t.Fatalf("migration not recorded as skipped: %+v", rec)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkSchema(t *testing.T, db kv.RoDB, migrations []Migration, applied int, version uint64) {
+	t.Helper()
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		pending, err := PendingMigrations(tx, migrations)
+		if err != nil {
+			return err
+		}
+		if len(pending) != len(migrations)-applied {
+			t.Errorf("pending migrations: have %d, want %d", len(pending), len(migrations)-applied)
+		}
+		have, err := ReadSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		if have != version {
+			t.Errorf("schema version: have %d, want %d", have, version)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
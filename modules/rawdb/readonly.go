@@ -0,0 +1,56 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// ErrReadOnly is returned by the write paths of a database or freezer opened
+// read-only.
+var ErrReadOnly = errors.New("database is opened read-only")
+
+// readOnlyDB refuses write transactions with ErrReadOnly, rather than with
+// the storage engine error, so callers can tell them apart.
+type readOnlyDB struct {
+	kv.RwDB
+}
+
+// ReadOnlyDB wraps a database opened read-only so that every write
+// transaction fails with ErrReadOnly.
+func ReadOnlyDB(db kv.RwDB) kv.RwDB {
+	return readOnlyDB{db}
+}
+
+func (readOnlyDB) Update(context.Context, func(tx kv.RwTx) error) error {
+	return ErrReadOnly
+}
+
+func (readOnlyDB) UpdateNosync(context.Context, func(tx kv.RwTx) error) error {
+	return ErrReadOnly
+}
+
+func (readOnlyDB) BeginRw(context.Context) (kv.RwTx, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyDB) BeginRwNosync(context.Context) (kv.RwTx, error) {
+	return nil, ErrReadOnly
+}
@@ -38,6 +38,11 @@
 //	StorageChangeSet : block_num(8) + address(20) + incarnation(2) -> key(32) + value(32)
 //	StorageHistory   : address(20) + key(32) + shard_id(8) -> roaring_bitmap
 //
+// ## 2a. Commitment Buckets (internal/commitment/ access only)
+//
+//	CommitmentBranch : compact_nibble_path -> branch_node
+//	CommitmentRoot   : block_num(8) -> hash(32) + state_trie_root(32)
+//
 // ## 3. Chain Buckets (modules/rawdb/ access)
 //
 //	Headers          : block_num(8) + hash(32) -> header_proto
@@ -78,6 +83,7 @@
 //
 //	DatabaseInfo     : key -> value
 //	ChainConfig      : "config" -> chain_config_json
+//	Migrations       : migration_name -> applied_record_json
 //	Sequence         : table_name -> sequence(8)
 //
 // ## 9. Application Buckets
@@ -108,10 +114,9 @@
 // # Migration Notes
 //
 // When modifying the schema:
-// 1. Increment DatabaseInfo version
-// 2. Add migration logic in database initialization
-// 3. Update this documentation
-// 4. Test backward compatibility
+// 1. Add a migration to the end of the Migrations list
+// 2. Update this documentation
+// 3. Test backward compatibility
 package rawdb
 
 import (
@@ -166,10 +171,11 @@ func ReceiptKey(number uint64) []byte {
 // =============================================================================
 
 const (
-	// SchemaVersion is the current database schema version
+	// SchemaVersion is the version of the database schema before any
+	// migration. Each applied migration raises the stored version by one.
 	SchemaVersion = 1
 
-	// SchemaVersionKey is the key for storing schema version
+	// SchemaVersionKey is the DatabaseInfo key storing the schema version
 	SchemaVersionKey = "schema_version"
 )
 
@@ -183,6 +189,7 @@ var StateBuckets = []string{
 	modules.Storage,
 	modules.Code,
 	modules.PlainContractCode,
+	modules.CodeRefs,
 	modules.IncarnationMap,
 	modules.AccountChangeSet,
 	modules.AccountsHistory,
@@ -218,6 +225,7 @@ var ConsensusBuckets = []string{
 var MetadataBuckets = []string{
 	modules.DatabaseInfo,
 	modules.ChainConfig,
+	modules.Migrations,
 	modules.Sequence,
 }
 
@@ -0,0 +1,208 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"github.com/n42blockchain/N42/common/account"
+	prometheus "github.com/n42blockchain/N42/common/metrics"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+const (
+	// DefaultCodeCacheSize is the default size of the shared code cache in bytes
+	DefaultCodeCacheSize = 64 * 1024 * 1024
+	// DefaultAccountCacheSize is the default number of accounts in the shared cache
+	DefaultAccountCacheSize = 100_000
+
+	// plainStateBlock keys the accounts read from the plain state rather than
+	// from the history as of a block.
+	plainStateBlock = ^uint64(0)
+)
+
+var (
+	codeCacheHits      = prometheus.GetOrCreateCounter(`state_cache_hits_total{cache="code"}`)
+	codeCacheMisses    = prometheus.GetOrCreateCounter(`state_cache_misses_total{cache="code"}`)
+	accountCacheHits   = prometheus.GetOrCreateCounter(`state_cache_hits_total{cache="account"}`)
+	accountCacheMisses = prometheus.GetOrCreateCounter(`state_cache_misses_total{cache="account"}`)
+)
+
+// sharedCache is used by block execution and the RPC state readers alike,
+// so a burst of calls to a hot contract reads its code from the database
+// only once.
+var sharedCache = newStateCache(DefaultCodeCacheSize, DefaultAccountCacheSize)
+
+// SetCacheSizes resizes the shared state cache: codeSize bounds the cached
+// code in bytes, accounts the number of cached accounts. Zero disables the
+// respective cache.
+func SetCacheSizes(codeSize uint64, accounts int) {
+	sharedCache = newStateCache(codeSize, accounts)
+}
+
+// stateCache holds contract code by hash, which never changes, and accounts
+// by the database view they were read in: a read-only transaction sees the
+// same data as every other one opened before the next commit.
+type stateCache struct {
+	codeMu   sync.Mutex
+	code     *simplelru.LRU[types.Hash, []byte]
+	codeSize uint64 // bytes held by code
+	codeMax  uint64
+
+	accounts *lru.Cache[accountKey, *account.StateAccount] // nil marks a missing account
+}
+
+type accountKey struct {
+	view, block uint64
+	addr        types.Address
+}
+
+func newStateCache(codeSize uint64, accounts int) *stateCache {
+	c := &stateCache{codeMax: codeSize}
+	if codeSize > 0 {
+		// Bounded by size, not by the number of entries
+		c.code, _ = simplelru.NewLRU[types.Hash, []byte](1<<30, func(_ types.Hash, code []byte) {
+			c.codeSize -= uint64(len(code))
+		})
+	}
+	if accounts > 0 {
+		c.accounts, _ = lru.New[accountKey, *account.StateAccount](accounts)
+	}
+	return c
+}
+
+func (c *stateCache) getCode(hash types.Hash) ([]byte, bool) {
+	if c.code == nil {
+		return nil, false
+	}
+	c.codeMu.Lock()
+	code, ok := c.code.Get(hash)
+	c.codeMu.Unlock()
+	if ok {
+		codeCacheHits.Inc()
+	} else {
+		codeCacheMisses.Inc()
+	}
+	return code, ok
+}
+
+func (c *stateCache) addCode(hash types.Hash, code []byte) {
+	if c.code == nil || uint64(len(code)) > c.codeMax {
+		return
+	}
+	c.codeMu.Lock()
+	defer c.codeMu.Unlock()
+	if c.code.Contains(hash) {
+		return
+	}
+	c.code.Add(hash, code)
+	c.codeSize += uint64(len(code))
+	for c.codeSize > c.codeMax {
+		c.code.RemoveOldest()
+	}
+}
+
+func (c *stateCache) getAccount(key accountKey) (*account.StateAccount, bool) {
+	if c.accounts == nil {
+		return nil, false
+	}
+	acc, ok := c.accounts.Get(key)
+	if !ok {
+		accountCacheMisses.Inc()
+		return nil, false
+	}
+	accountCacheHits.Inc()
+	if acc == nil {
+		return nil, true
+	}
+	return acc.SelfCopy(), true
+}
+
+func (c *stateCache) addAccount(key accountKey, acc *account.StateAccount) {
+	if c.accounts == nil {
+		return
+	}
+	if acc != nil {
+		acc = acc.SelfCopy()
+	}
+	c.accounts.Add(key, acc)
+}
+
+// CachedReader serves contract code and accounts from the process-wide state
+// cache before reading them from the wrapped reader. The wrapped reader must
+// read from a read-only transaction with the given view id.
+type CachedReader struct {
+	inner       StateReader
+	cache       *stateCache
+	view, block uint64
+}
+
+// NewCachedReader wraps a reader of the state at the beginning of block, as
+// returned by NewPlainState.
+func NewCachedReader(inner StateReader, view, block uint64) *CachedReader {
+	return &CachedReader{inner: inner, cache: sharedCache, view: view, block: block}
+}
+
+// NewCachedPlainReader wraps a reader of the plain state.
+func NewCachedPlainReader(inner StateReader, view uint64) *CachedReader {
+	return NewCachedReader(inner, view, plainStateBlock)
+}
+
+func (r *CachedReader) ReadAccountData(address types.Address) (*account.StateAccount, error) {
+	key := accountKey{view: r.view, block: r.block, addr: address}
+	if acc, ok := r.cache.getAccount(key); ok {
+		return acc, nil
+	}
+	acc, err := r.inner.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.addAccount(key, acc)
+	return acc, nil
+}
+
+func (r *CachedReader) ReadAccountStorage(address types.Address, incarnation uint16, key *types.Hash) ([]byte, error) {
+	return r.inner.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *CachedReader) ReadAccountCode(address types.Address, incarnation uint16, codeHash types.Hash) ([]byte, error) {
+	if codeHash == (types.Hash{}) || codeHash == emptyCodeHashH {
+		return r.inner.ReadAccountCode(address, incarnation, codeHash)
+	}
+	if code, ok := r.cache.getCode(codeHash); ok {
+		return code, nil
+	}
+	code, err := r.inner.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		r.cache.addCode(codeHash, code)
+	}
+	return code, nil
+}
+
+func (r *CachedReader) ReadAccountCodeSize(address types.Address, incarnation uint16, codeHash types.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (r *CachedReader) ReadAccountIncarnation(address types.Address) (uint16, error) {
+	return r.inner.ReadAccountIncarnation(address)
+}
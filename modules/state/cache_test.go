@@ -0,0 +1,112 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// countingReader serves fixed accounts and code and counts the reads that
+// reach it.
+type countingReader struct {
+	StateReader
+	accounts map[types.Address]*account.StateAccount
+	code     map[types.Hash][]byte
+	reads    int
+}
+
+func (r *countingReader) ReadAccountData(address types.Address) (*account.StateAccount, error) {
+	r.reads++
+	if acc, ok := r.accounts[address]; ok {
+		return acc.SelfCopy(), nil
+	}
+	return nil, nil
+}
+
+func (r *countingReader) ReadAccountCode(_ types.Address, _ uint16, codeHash types.Hash) ([]byte, error) {
+	r.reads++
+	return r.code[codeHash], nil
+}
+
+func TestCachedReader(t *testing.T) {
+	var (
+		addr    = types.HexToAddress("0x01")
+		missing = types.HexToAddress("0x02")
+		small   = []byte{0x60, 0x00}
+		large   = []byte{0x60, 0x01, 0x60, 0x02}
+		smallH  = crypto.Keccak256Hash(small)
+		largeH  = crypto.Keccak256Hash(large)
+	)
+	inner := &countingReader{
+		accounts: map[types.Address]*account.StateAccount{addr: newTestAccount(7)},
+		code:     map[types.Hash][]byte{smallH: small, largeH: large},
+	}
+	cache := newStateCache(uint64(len(small)+len(large)-1), 16)
+	reader := func(view, block uint64) *CachedReader {
+		return &CachedReader{inner: inner, cache: cache, view: view, block: block}
+	}
+
+	// Accounts, present or not, are read once per view and block
+	r := reader(1, 10)
+	for i := 0; i < 2; i++ {
+		acc, err := r.ReadAccountData(addr)
+		if err != nil || acc == nil || acc.Balance.Uint64() != 7 {
+			t.Fatalf("read %d: account %v, err %v", i, acc, err)
+		}
+		acc.Balance.SetUint64(0) // must not leak into the cache
+		if acc, err := r.ReadAccountData(missing); err != nil || acc != nil {
+			t.Fatalf("read %d: missing account %v, err %v", i, acc, err)
+		}
+	}
+	if inner.reads != 2 {
+		t.Fatalf("inner reads %d, want 2", inner.reads)
+	}
+	if _, err := reader(2, 10).ReadAccountData(addr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader(1, 11).ReadAccountData(addr); err != nil {
+		t.Fatal(err)
+	}
+	if inner.reads != 4 {
+		t.Fatalf("inner reads %d after other views, want 4", inner.reads)
+	}
+
+	// Code is shared across views and bounded by size
+	inner.reads = 0
+	if size, err := reader(1, 10).ReadAccountCodeSize(addr, 1, smallH); err != nil || size != len(small) {
+		t.Fatalf("code size %d, err %v", size, err)
+	}
+	if code, err := reader(2, 10).ReadAccountCode(addr, 1, smallH); err != nil || len(code) != len(small) {
+		t.Fatalf("code %x, err %v", code, err)
+	}
+	if inner.reads != 1 {
+		t.Fatalf("inner code reads %d, want 1", inner.reads)
+	}
+	if _, err := r.ReadAccountCode(addr, 1, largeH); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.getCode(smallH); ok {
+		t.Fatal("small code not evicted")
+	}
+	if cache.codeSize != uint64(len(large)) {
+		t.Fatalf("cached code size %d, want %d", cache.codeSize, len(large))
+	}
+}
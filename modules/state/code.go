@@ -0,0 +1,229 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/changeset"
+)
+
+// Contract code is stored once per code hash in the Code table, however many
+// accounts run it: the PlainCodeHash entry of a contract incarnation only
+// holds the hash. CodeRefs counts those entries per hash. Code is not deleted
+// when its count drops to zero, because the account history keeps the hashes
+// of replaced code for archive reads and unwinds; only DedupCode deletes
+// code, after checking the history.
+
+type codeStore interface {
+	kv.Getter
+	kv.Putter
+	kv.Deleter
+}
+
+func readCodeRefs(db kv.Getter, codeHash []byte) (uint64, error) {
+	v, err := db.GetOne(modules.CodeRefs, codeHash)
+	if err != nil || len(v) != 8 {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func writeCodeRefs(db codeStore, codeHash []byte, refs uint64) error {
+	if refs == 0 {
+		return db.Delete(modules.CodeRefs, codeHash)
+	}
+	return db.Put(modules.CodeRefs, codeHash, modules.EncodeBlockNumber(refs))
+}
+
+// setContractCode points the contract incarnation at prefix to codeHash,
+// storing the code if it is new and moving the reference from the previous
+// code of the incarnation.
+func setContractCode(db codeStore, prefix []byte, codeHash types.Hash, code []byte) error {
+	prev, err := db.GetOne(modules.PlainContractCode, prefix)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(prev, codeHash[:]) {
+		return nil
+	}
+	refs, err := readCodeRefs(db, codeHash[:])
+	if err != nil {
+		return err
+	}
+	if refs == 0 {
+		if err := db.Put(modules.Code, codeHash[:], code); err != nil {
+			return err
+		}
+	}
+	if err := writeCodeRefs(db, codeHash[:], refs+1); err != nil {
+		return err
+	}
+	if len(prev) > 0 {
+		if err := releaseCode(db, prev); err != nil {
+			return err
+		}
+	}
+	return db.Put(modules.PlainContractCode, prefix, codeHash[:])
+}
+
+// releaseCode drops a reference to the code. The code itself is kept, the
+// account history may still point to it.
+func releaseCode(db codeStore, codeHash []byte) error {
+	refs, err := readCodeRefs(db, codeHash)
+	if err != nil || refs == 0 {
+		return err
+	}
+	return writeCodeRefs(db, codeHash, refs-1)
+}
+
+// CodeStats summarises a CountCodeRefs or DedupCode run.
+type CodeStats struct {
+	Contracts uint64 // contract incarnations
+	Codes     uint64 // distinct codes they reference
+	Size      uint64 // bytes of the stored codes
+	Shared    uint64 // bytes a copy of the code per contract would take in addition
+	History   uint64 // codes only accounts or the account history reference, kept
+	Orphans   uint64 // codes nothing references, deleted
+	Freed     uint64 // bytes of the deleted codes
+}
+
+// CountCodeRefs recounts the references of the contracts to every stored
+// code. Databases written before the codes were counted need it once. It
+// deletes nothing, so running it again is harmless.
+func CountCodeRefs(ctx context.Context, db kv.RwDB) (CodeStats, error) {
+	var stats CodeStats
+	err := db.Update(ctx, func(tx kv.RwTx) error {
+		stats = CodeStats{}
+		_, err := countCodeRefs(tx, &stats)
+		return err
+	})
+	return stats, err
+}
+
+// DedupCode recounts the code references like CountCodeRefs and deletes the
+// codes that no contract, account or account history entry references.
+func DedupCode(ctx context.Context, db kv.RwDB) (CodeStats, error) {
+	var stats CodeStats
+	err := db.Update(ctx, func(tx kv.RwTx) error {
+		stats = CodeStats{}
+		refs, err := countCodeRefs(tx, &stats)
+		if err != nil {
+			return err
+		}
+		accounts, err := accountCodes(tx)
+		if err != nil {
+			return err
+		}
+
+		var orphans [][]byte
+		if err := tx.ForEach(modules.Code, nil, func(k, v []byte) error {
+			hash := types.BytesToHash(k)
+			if refs[hash] > 0 {
+				return nil
+			}
+			if _, ok := accounts[hash]; ok {
+				stats.History++
+				return nil
+			}
+			stats.Orphans++
+			stats.Freed += uint64(len(v))
+			orphans = append(orphans, bytes.Clone(k))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range orphans {
+			if err := tx.Delete(modules.Code, k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// countCodeRefs rewrites CodeRefs from the PlainCodeHash entries and returns
+// the counts.
+func countCodeRefs(tx kv.RwTx, stats *CodeStats) (map[types.Hash]uint64, error) {
+	refs := make(map[types.Hash]uint64)
+	if err := tx.ForEach(modules.PlainContractCode, nil, func(k, v []byte) error {
+		stats.Contracts++
+		refs[types.BytesToHash(v)]++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.ClearBucket(modules.CodeRefs); err != nil {
+		return nil, err
+	}
+	if err := tx.ForEach(modules.Code, nil, func(k, v []byte) error {
+		if n := refs[types.BytesToHash(k)]; n > 0 {
+			stats.Codes++
+			stats.Size += uint64(len(v))
+			stats.Shared += (n - 1) * uint64(len(v))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for hash, n := range refs {
+		if err := writeCodeRefs(tx, hash[:], n); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// accountCodes returns the code hashes of the current accounts and of the
+// previous account values kept in the account history.
+func accountCodes(tx kv.Tx) (map[types.Hash]struct{}, error) {
+	codes := make(map[types.Hash]struct{})
+	add := func(enc []byte) error {
+		if len(enc) == 0 {
+			return nil
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(enc); err != nil {
+			return err
+		}
+		if !acc.IsEmptyCodeHash() {
+			codes[acc.CodeHash] = struct{}{}
+		}
+		return nil
+	}
+	if err := tx.ForEach(modules.Account, nil, func(k, v []byte) error {
+		if len(k) != types.AddressLength {
+			return nil
+		}
+		return add(v)
+	}); err != nil {
+		return nil, err
+	}
+	if err := changeset.ForEach(tx, modules.AccountChangeSet, nil, func(_ uint64, _, v []byte) error {
+		return add(v)
+	}); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
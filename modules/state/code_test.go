@@ -0,0 +1,184 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func TestContractCodeRefs(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		clone1, clone2 = types.HexToAddress("0x01"), types.HexToAddress("0x02")
+		proxy, other   = []byte{0x60, 0x01}, []byte{0x60, 0x02}
+		proxyHash      = crypto.Keccak256Hash(proxy)
+		otherHash      = crypto.Keccak256Hash(other)
+	)
+	expect := func(hash types.Hash, refs uint64, stored bool) {
+		t.Helper()
+		n, err := readCodeRefs(tx, hash[:])
+		if err != nil || n != refs {
+			t.Errorf("refs of %x = %d, %v, want %d", hash[:4], n, err, refs)
+		}
+		code, err := tx.GetOne(modules.Code, hash[:])
+		if err != nil || (len(code) > 0) != stored {
+			t.Errorf("code %x stored = %v, %v, want %v", hash[:4], len(code) > 0, err, stored)
+		}
+	}
+
+	w := NewPlainStateWriter(tx, tx, 1)
+	for _, addr := range []types.Address{clone1, clone2} {
+		if err := w.UpdateAccountCode(addr, 1, proxyHash, proxy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Writing the same code again does not add a reference
+	if err := w.UpdateAccountCode(clone1, 1, proxyHash, proxy); err != nil {
+		t.Fatal(err)
+	}
+	expect(proxyHash, 2, true)
+	if n := countCodes(t, tx); n != 1 {
+		t.Errorf("stored codes = %d, want 1", n)
+	}
+
+	// Moving both clones to other code releases the proxy
+	if err := w.UpdateAccountCode(clone1, 1, otherHash, other); err != nil {
+		t.Fatal(err)
+	}
+	expect(proxyHash, 1, true)
+	expect(otherHash, 1, true)
+	if err := w.UpdateAccountCode(clone2, 1, otherHash, other); err != nil {
+		t.Fatal(err)
+	}
+	// The history may still point to the proxy, its code stays
+	expect(proxyHash, 0, true)
+	expect(otherHash, 2, true)
+}
+
+func TestDedupCode(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+
+	var (
+		code     = []byte{0x60, 0x01, 0x60, 0x02}
+		orphan   = []byte{0x60, 0x03}
+		replaced = []byte{0x60, 0x04} // only in the account history
+		current  = []byte{0x60, 0x05} // only in an account
+		hash     = crypto.Keccak256Hash(code)
+	)
+	encodeAccount := func(code []byte) []byte {
+		acc := account.NewAccount()
+		acc.CodeHash = crypto.Keccak256Hash(code)
+		enc := make([]byte, acc.EncodingLengthForStorage())
+		acc.EncodeForStorage(enc)
+		return enc
+	}
+	// A database written before the references were counted
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for _, addr := range []types.Address{types.HexToAddress("0x01"), types.HexToAddress("0x02"), types.HexToAddress("0x03")} {
+			if err := tx.Put(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(addr[:], 1), hash[:]); err != nil {
+				return err
+			}
+		}
+		for _, c := range [][]byte{code, orphan, replaced, current} {
+			if err := tx.Put(modules.Code, crypto.Keccak256(c), c); err != nil {
+				return err
+			}
+		}
+		addr := types.HexToAddress("0x04")
+		if err := tx.Put(modules.AccountChangeSet, modules.EncodeBlockNumber(1), append(addr.Bytes(), encodeAccount(replaced)...)); err != nil {
+			return err
+		}
+		return tx.Put(modules.Account, addr[:], encodeAccount(current))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Counting the references deletes nothing
+	stats, err := CountCodeRefs(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (CodeStats{Contracts: 3, Codes: 1, Size: 4, Shared: 8}); stats != want {
+		t.Errorf("count: stats = %+v, want %+v", stats, want)
+	}
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		if n := countCodes(t, tx); n != 4 {
+			t.Errorf("stored codes after count = %d, want 4", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		stats, err := DedupCode(context.Background(), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := CodeStats{Contracts: 3, Codes: 1, Size: 4, Shared: 8, History: 2}
+		if i == 0 {
+			want.Orphans, want.Freed = 1, 2
+		}
+		if stats != want {
+			t.Errorf("run %d: stats = %+v, want %+v", i, stats, want)
+		}
+	}
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		if n, err := readCodeRefs(tx, hash[:]); err != nil || n != 3 {
+			t.Errorf("refs = %d, %v, want 3", n, err)
+		}
+		if n := countCodes(t, tx); n != 3 {
+			t.Errorf("stored codes = %d, want 3", n)
+		}
+		if v, _ := tx.GetOne(modules.Code, crypto.Keccak256(orphan)); v != nil {
+			t.Error("orphaned code not deleted")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countCodes(t *testing.T, tx kv.Tx) int {
+	t.Helper()
+	var n int
+	if err := tx.ForEach(modules.Code, nil, func(k, v []byte) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// ContextReader stops reading the wrapped state once ctx is done. The
+// database only checks the context of a read transaction when it is opened,
+// so this is what aborts a long call or trace whose client went away.
+type ContextReader struct {
+	ctx   context.Context
+	inner StateReader
+}
+
+// NewContextReader wraps inner so that every read fails with the context's
+// error once ctx is done.
+func NewContextReader(ctx context.Context, inner StateReader) *ContextReader {
+	return &ContextReader{ctx: ctx, inner: inner}
+}
+
+func (r *ContextReader) ReadAccountData(address types.Address) (*account.StateAccount, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.inner.ReadAccountData(address)
+}
+
+func (r *ContextReader) ReadAccountStorage(address types.Address, incarnation uint16, key *types.Hash) ([]byte, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.inner.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *ContextReader) ReadAccountCode(address types.Address, incarnation uint16, codeHash types.Hash) ([]byte, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.inner.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *ContextReader) ReadAccountCodeSize(address types.Address, incarnation uint16, codeHash types.Hash) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.inner.ReadAccountCodeSize(address, incarnation, codeHash)
+}
+
+func (r *ContextReader) ReadAccountIncarnation(address types.Address) (uint16, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.inner.ReadAccountIncarnation(address)
+}
@@ -207,17 +207,20 @@ func WalkAsOfStorage(tx kv.Tx, address types.Address, incarnation uint16, startL
 				copy(csKey, modules.EncodeBlockNumber(changeSetBlock))
 				copy(csKey[8:], address[:]) // address + incarnation
 				binary.BigEndian.PutUint16(csKey[8+types.AddressLength:], incarnation)
-				kData := csKey
 				data, err3 := csCursor.SeekBothRange(csKey, hLoc)
 				if err3 != nil {
 					return err3
 				}
-				if !bytes.Equal(kData, csKey) || !bytes.HasPrefix(data, hLoc) {
-					return fmt.Errorf("inconsistent storage changeset and history kData %x, csKey %x, data %x, hLoc %x", kData, csKey, data, hLoc)
-				}
-				data = data[types.HashLength:]
-				if len(data) > 0 { // Skip deleted entries
-					goOn, err = walker(hAddr, hLoc, data)
+				if bytes.HasPrefix(data, hLoc) {
+					data = data[types.HashLength:]
+					if len(data) > 0 { // Skip deleted entries
+						goOn, err = walker(hAddr, hLoc, data)
+					}
+				} else if cmp == 0 {
+					// The history is not split by incarnation: the change
+					// belongs to a later incarnation of the account, so this
+					// one kept its value in the plain state, as in GetAsOf.
+					goOn, err = walker(addr, loc, v)
 				}
 			} else if cmp == 0 {
 				goOn, err = walker(addr, loc, v)
@@ -941,12 +941,14 @@ func (sdb *IntraBlockState) Print() {
 }
 
 // Prepare sets the current transaction hash and index and block hash which is
-// used when the EVM emits new state logs.
+// used when the EVM emits new state logs. It also clears the access list and
+// the transient storage (EIP-1153), which only live for one transaction.
 func (sdb *IntraBlockState) Prepare(thash, bhash types.Hash, ti int) {
 	sdb.thash = thash
 	sdb.bhash = bhash
 	sdb.txIndex = ti
 	sdb.accessList = newAccessList()
+	sdb.transientStorage = newTransientStorage()
 }
 
 // no not lock
@@ -49,6 +49,13 @@ type PlainState struct {
 	trace                        bool
 }
 
+// NewHistoricalStateReader returns a reader of the state after block blockNr.
+// History is indexed by the block that changed a value, so this is the plain
+// state as of blockNr+1.
+func NewHistoricalStateReader(tx kv.Tx, blockNr uint64) *PlainState {
+	return NewPlainState(tx, blockNr+1)
+}
+
 func NewPlainState(tx kv.Tx, blockNr uint64) *PlainState {
 	c1, _ := tx.Cursor(modules.AccountsHistory)
 	c2, _ := tx.Cursor(modules.StorageHistory)
@@ -175,6 +182,11 @@ func (s *PlainState) ReadAccountData(address types.Address) (*account.StateAccou
 			return nil, err1
 		}
 	}
+	// change sets store Keccak256(nil) as the storage root, the plain state
+	// none, and the state root of a block covers the field
+	if a.Root == emptyCodeHashH {
+		a.Root = types.Hash{}
+	}
 	if s.trace {
 		fmt.Printf("ReadAccountData [%x] => [nonce: %d, balance: %d, codeHash: %x]\n", address, a.Nonce, &a.Balance, a.CodeHash)
 	}
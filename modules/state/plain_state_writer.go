@@ -82,10 +82,14 @@ func (w *PlainStateWriter) UpdateAccountCode(address types.Address, incarnation
 	//if w.accumulator != nil {
 	//	w.accumulator.ChangeCode(address, incarnation, code)
 	//}
+	prefix := modules.PlainGenerateStoragePrefix(address[:], incarnation)
+	if db, ok := w.db.(codeStore); ok {
+		return setContractCode(db, prefix, codeHash, code)
+	}
 	if err := w.db.Put(modules.Code, codeHash[:], code); err != nil {
 		return err
 	}
-	return w.db.Put(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(address[:], incarnation), codeHash[:])
+	return w.db.Put(modules.PlainContractCode, prefix, codeHash[:])
 }
 
 func (w *PlainStateWriter) DeleteAccount(address types.Address, original *account.StateAccount) error {
@@ -0,0 +1,85 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+)
+
+// RecordingReader wraps a StateReader and records the accounts and storage
+// slots read through it, e.g. to collect the witness of a block execution.
+type RecordingReader struct {
+	inner    StateReader
+	accounts map[types.Address]struct{}
+	storage  map[types.Address]map[types.Hash]struct{}
+}
+
+// NewRecordingReader creates a recording wrapper around inner.
+func NewRecordingReader(inner StateReader) *RecordingReader {
+	return &RecordingReader{
+		inner:    inner,
+		accounts: make(map[types.Address]struct{}),
+		storage:  make(map[types.Address]map[types.Hash]struct{}),
+	}
+}
+
+func (r *RecordingReader) ReadAccountData(address types.Address) (*account.StateAccount, error) {
+	r.accounts[address] = struct{}{}
+	return r.inner.ReadAccountData(address)
+}
+
+func (r *RecordingReader) ReadAccountStorage(address types.Address, incarnation uint16, key *types.Hash) ([]byte, error) {
+	r.accounts[address] = struct{}{}
+	slots, ok := r.storage[address]
+	if !ok {
+		slots = make(map[types.Hash]struct{})
+		r.storage[address] = slots
+	}
+	slots[*key] = struct{}{}
+	return r.inner.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *RecordingReader) ReadAccountCode(address types.Address, incarnation uint16, codeHash types.Hash) ([]byte, error) {
+	r.accounts[address] = struct{}{}
+	return r.inner.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *RecordingReader) ReadAccountCodeSize(address types.Address, incarnation uint16, codeHash types.Hash) (int, error) {
+	r.accounts[address] = struct{}{}
+	return r.inner.ReadAccountCodeSize(address, incarnation, codeHash)
+}
+
+func (r *RecordingReader) ReadAccountIncarnation(address types.Address) (uint16, error) {
+	r.accounts[address] = struct{}{}
+	return r.inner.ReadAccountIncarnation(address)
+}
+
+// Keys returns the recorded reads as plain state keys: 20-byte addresses
+// and address + location for storage slots.
+func (r *RecordingReader) Keys() [][]byte {
+	keys := make([][]byte, 0, len(r.accounts))
+	for addr := range r.accounts {
+		keys = append(keys, addr.Bytes())
+	}
+	for addr, slots := range r.storage {
+		for loc := range slots {
+			keys = append(keys, append(addr.Bytes(), loc.Bytes()...))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,196 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/holiman/uint256"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"google.golang.org/protobuf/proto"
+)
+
+// StagedWriter buffers the writes of a block in memory so the block can be
+// committed to the database later. Until then it serves the buffered values
+// as an overlay of the plain state, which lets the next block execute on top
+// of a block whose commit is still in flight.
+type StagedWriter struct {
+	ops []func(w StateWriter) error
+
+	accounts     map[types.Address][]byte // encoded account, nil when deleted
+	storage      map[string][]byte        // plain composite key to value, nil when cleared
+	code         map[types.Hash][]byte
+	incarnations map[types.Address]uint16
+
+	size int // approximate size of the buffered writes in bytes
+}
+
+// NewStagedWriter creates an empty staging buffer.
+func NewStagedWriter() *StagedWriter {
+	return &StagedWriter{
+		accounts:     make(map[types.Address][]byte),
+		storage:      make(map[string][]byte),
+		code:         make(map[types.Hash][]byte),
+		incarnations: make(map[types.Address]uint16),
+	}
+}
+
+func (s *StagedWriter) UpdateAccountData(address types.Address, original, account *account.StateAccount) error {
+	original, account = copyAccount(original), copyAccount(account)
+	data, err := proto.Marshal(account.ToProtoMessage())
+	if err != nil {
+		return err
+	}
+	s.accounts[address] = data
+	s.size += types.AddressLength + 2*len(data)
+	s.ops = append(s.ops, func(w StateWriter) error {
+		return w.UpdateAccountData(address, original, account)
+	})
+	return nil
+}
+
+func (s *StagedWriter) UpdateAccountCode(address types.Address, incarnation uint16, codeHash types.Hash, code []byte) error {
+	code = append([]byte(nil), code...)
+	s.code[codeHash] = code
+	s.size += types.AddressLength + types.HashLength + len(code)
+	s.ops = append(s.ops, func(w StateWriter) error {
+		return w.UpdateAccountCode(address, incarnation, codeHash, code)
+	})
+	return nil
+}
+
+func (s *StagedWriter) DeleteAccount(address types.Address, original *account.StateAccount) error {
+	original = copyAccount(original)
+	s.accounts[address] = nil
+	if original.Incarnation > 0 {
+		s.incarnations[address] = original.Incarnation
+	}
+	s.size += types.AddressLength
+	s.ops = append(s.ops, func(w StateWriter) error {
+		return w.DeleteAccount(address, original)
+	})
+	return nil
+}
+
+func (s *StagedWriter) WriteAccountStorage(address types.Address, incarnation uint16, key *types.Hash, original, value *uint256.Int) error {
+	k, o, v := *key, *original, *value
+	if o != v {
+		s.storage[string(modules.PlainGenerateCompositeStorageKey(address.Bytes(), incarnation, k.Bytes()))] = v.Bytes()
+		s.size += types.AddressLength + types.HashLength + 2*32
+	}
+	s.ops = append(s.ops, func(w StateWriter) error {
+		return w.WriteAccountStorage(address, incarnation, &k, &o, &v)
+	})
+	return nil
+}
+
+func (s *StagedWriter) CreateContract(address types.Address) error {
+	s.ops = append(s.ops, func(w StateWriter) error {
+		return w.CreateContract(address)
+	})
+	return nil
+}
+
+// Size returns the approximate number of bytes the buffered writes take in
+// the database, including their change sets.
+func (s *StagedWriter) Size() int {
+	return s.size
+}
+
+// Replay writes the buffered changes to w in the order they were made, so
+// a change set writer behind w records the same originals it would have
+// seen when committing the block directly.
+func (s *StagedWriter) Replay(w StateWriter) error {
+	for _, op := range s.ops {
+		if err := op(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyAccount(a *account.StateAccount) *account.StateAccount {
+	if a == nil {
+		return nil
+	}
+	return a.SelfCopy()
+}
+
+// StagedReader reads through a stack of staging buffers before falling back
+// to the plain state.
+type StagedReader struct {
+	base   StateReader
+	staged []*StagedWriter // newest last
+}
+
+// NewStagedReader creates a reader of base with staged applied on top of it,
+// oldest first.
+func NewStagedReader(base StateReader, staged ...*StagedWriter) *StagedReader {
+	return &StagedReader{base: base, staged: staged}
+}
+
+func (r *StagedReader) ReadAccountData(address types.Address) (*account.StateAccount, error) {
+	for i := len(r.staged) - 1; i >= 0; i-- {
+		if enc, ok := r.staged[i].accounts[address]; ok {
+			if len(enc) == 0 {
+				return nil, nil
+			}
+			var a account.StateAccount
+			if err := a.DecodeForStorage(enc); err != nil {
+				return nil, err
+			}
+			return &a, nil
+		}
+	}
+	return r.base.ReadAccountData(address)
+}
+
+func (r *StagedReader) ReadAccountStorage(address types.Address, incarnation uint16, key *types.Hash) ([]byte, error) {
+	compositeKey := string(modules.PlainGenerateCompositeStorageKey(address.Bytes(), incarnation, key.Bytes()))
+	for i := len(r.staged) - 1; i >= 0; i-- {
+		if v, ok := r.staged[i].storage[compositeKey]; ok {
+			if len(v) == 0 {
+				return nil, nil
+			}
+			return v, nil
+		}
+	}
+	return r.base.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *StagedReader) ReadAccountCode(address types.Address, incarnation uint16, codeHash types.Hash) ([]byte, error) {
+	for i := len(r.staged) - 1; i >= 0; i-- {
+		if code, ok := r.staged[i].code[codeHash]; ok && len(code) > 0 {
+			return code, nil
+		}
+	}
+	return r.base.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *StagedReader) ReadAccountCodeSize(address types.Address, incarnation uint16, codeHash types.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (r *StagedReader) ReadAccountIncarnation(address types.Address) (uint16, error) {
+	for i := len(r.staged) - 1; i >= 0; i-- {
+		if inc, ok := r.staged[i].incarnations[address]; ok {
+			return inc, nil
+		}
+	}
+	return r.base.ReadAccountIncarnation(address)
+}
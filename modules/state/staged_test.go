@@ -0,0 +1,126 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+// TestStagedWriter checks that the staged overlay reads what the plain state
+// reads once the staged writes are replayed into it.
+func TestStagedWriter(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		addr     = types.HexToAddress("0x01")
+		deleted  = types.HexToAddress("0x02")
+		contract = types.HexToAddress("0x03")
+		slot     = types.Hash{0x04}
+		cleared  = types.Hash{0x05}
+		code     = []byte{0x60, 0x00}
+		codeHash = crypto.Keccak256Hash(code)
+		empty    = account.NewAccount()
+	)
+	// Base state: deleted exists with a cleared slot
+	base := NewPlainStateWriter(tx, tx, 1)
+	old := newTestAccount(5)
+	old.Incarnation = 1
+	if err := base.UpdateAccountData(deleted, &empty, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.WriteAccountStorage(contract, 1, &cleared, uint256.NewInt(0), uint256.NewInt(9)); err != nil {
+		t.Fatal(err)
+	}
+
+	staged := NewStagedWriter()
+	if err := staged.UpdateAccountData(addr, &empty, newTestAccount(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := staged.DeleteAccount(deleted, old); err != nil {
+		t.Fatal(err)
+	}
+	acc := newTestAccount(0)
+	acc.Incarnation = 1
+	acc.CodeHash = codeHash
+	if err := staged.CreateContract(contract); err != nil {
+		t.Fatal(err)
+	}
+	if err := staged.UpdateAccountData(contract, &empty, acc); err != nil {
+		t.Fatal(err)
+	}
+	if err := staged.UpdateAccountCode(contract, 1, codeHash, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := staged.WriteAccountStorage(contract, 1, &slot, uint256.NewInt(0), uint256.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := staged.WriteAccountStorage(contract, 1, &cleared, uint256.NewInt(9), uint256.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	// Mutating the written account must not leak into the buffer
+	acc.Balance.SetUint64(1)
+
+	overlay := NewStagedReader(NewPlainStateReader(tx), staged)
+	check := func(r StateReader) {
+		t.Helper()
+		if a, err := r.ReadAccountData(addr); err != nil || a == nil || a.Balance.Uint64() != 100 {
+			t.Errorf("account = %v, %v, want balance 100", a, err)
+		}
+		if a, err := r.ReadAccountData(deleted); err != nil || a != nil {
+			t.Errorf("deleted account = %v, %v, want nil", a, err)
+		}
+		if inc, err := r.ReadAccountIncarnation(deleted); err != nil || inc != 1 {
+			t.Errorf("incarnation = %d, %v, want 1", inc, err)
+		}
+		if a, err := r.ReadAccountData(contract); err != nil || a == nil || a.Balance.Uint64() != 0 || a.CodeHash != codeHash {
+			t.Errorf("contract = %v, %v", a, err)
+		}
+		if c, err := r.ReadAccountCode(contract, 1, codeHash); err != nil || !bytes.Equal(c, code) {
+			t.Errorf("code = %x, %v, want %x", c, err, code)
+		}
+		if v, err := r.ReadAccountStorage(contract, 1, &slot); err != nil || !bytes.Equal(v, []byte{7}) {
+			t.Errorf("slot = %x, %v, want 07", v, err)
+		}
+		if v, err := r.ReadAccountStorage(contract, 1, &cleared); err != nil || v != nil {
+			t.Errorf("cleared slot = %x, %v, want nil", v, err)
+		}
+	}
+	check(overlay)
+
+	w := NewPlainStateWriter(tx, tx, 2)
+	if err := staged.Replay(w); err != nil {
+		t.Fatal(err)
+	}
+	check(NewPlainStateReader(tx))
+}
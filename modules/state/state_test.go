@@ -348,6 +348,24 @@ func TestTransientStorageCopy(t *testing.T) {
 	t.Logf("✓ TransientStorage Copy works correctly")
 }
 
+func TestTransientStorageClearedByPrepare(t *testing.T) {
+	ibs := New(nil)
+	addr := types.Address{0x01}
+	key := types.Hash{0x0a}
+
+	ibs.Prepare(types.Hash{0x01}, types.Hash{}, 0)
+	ibs.SetTransientState(addr, key, *uint256.NewInt(7))
+	if v := ibs.GetTransientState(addr, key); v.Uint64() != 7 {
+		t.Fatalf("transient value = %d, want 7", v.Uint64())
+	}
+
+	// The next transaction starts with empty transient storage
+	ibs.Prepare(types.Hash{0x02}, types.Hash{}, 1)
+	if v := ibs.GetTransientState(addr, key); !v.IsZero() {
+		t.Errorf("transient value = %d after Prepare, want 0", v.Uint64())
+	}
+}
+
 // =============================================================================
 // Code Hash Tests
 // =============================================================================
@@ -0,0 +1,200 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/changeset"
+	"github.com/n42blockchain/N42/modules/ethdb/bitmapdb"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnwindState reverts the plain state to the end of block target using the
+// change sets of the later blocks, then drops those change sets and their
+// history index entries. The code of contracts created after target is
+// released.
+func UnwindState(tx kv.RwTx, target uint64) error {
+	from := modules.EncodeBlockNumber(target + 1)
+
+	// Change sets hold the value before each block, so the oldest entry of a
+	// key past target is its value at target. The later entries reveal the
+	// contract incarnations created after target.
+	accounts := make(map[string]*unwoundAccount)
+	if err := changeset.ForEach(tx, modules.AccountChangeSet, from, func(_ uint64, k, v []byte) error {
+		if a, ok := accounts[string(k)]; ok {
+			return a.note(k, v)
+		}
+		a, err := newUnwoundAccount(k, v)
+		if err != nil {
+			return err
+		}
+		accounts[string(k)] = a
+		return nil
+	}); err != nil {
+		return fmt.Errorf("reading account changes: %w", err)
+	}
+	storage := make(map[string][]byte)
+	if err := changeset.ForEach(tx, modules.StorageChangeSet, from, func(_ uint64, k, v []byte) error {
+		if _, ok := storage[string(k)]; !ok {
+			storage[string(k)] = types.CopyBytes(v)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("reading storage changes: %w", err)
+	}
+
+	for k, a := range accounts {
+		current, err := tx.GetOne(modules.Account, []byte(k))
+		if err != nil {
+			return err
+		}
+		if err := a.note([]byte(k), current); err != nil {
+			return err
+		}
+		if err := unwindAccount(tx, []byte(k), a); err != nil {
+			return err
+		}
+		if err := bitmapdb.TruncateRange64(tx, modules.AccountsHistory, []byte(k), target+1); err != nil {
+			return err
+		}
+	}
+	for k, v := range storage {
+		var err error
+		if len(v) == 0 {
+			err = tx.Delete(modules.Storage, []byte(k))
+		} else {
+			err = tx.Put(modules.Storage, []byte(k), v)
+		}
+		if err != nil {
+			return err
+		}
+		if err := bitmapdb.TruncateRange64(tx, modules.StorageHistory, modules.CompositeKeyWithoutIncarnation([]byte(k)), target+1); err != nil {
+			return err
+		}
+	}
+	return changeset.Truncate(tx, target+1)
+}
+
+// unwoundAccount is an account at the unwind target.
+type unwoundAccount struct {
+	acc     *account.StateAccount // nil if the account did not exist
+	created uint16                // lowest incarnation created after target, 0 if none
+}
+
+func newUnwoundAccount(addr, enc []byte) (*unwoundAccount, error) {
+	if len(enc) == 0 {
+		return &unwoundAccount{}, nil
+	}
+	var a account.StateAccount
+	if err := a.DecodeForStorage(enc); err != nil {
+		return nil, fmt.Errorf("decoding account %x: %w", addr, err)
+	}
+	return &unwoundAccount{acc: &a}, nil
+}
+
+// note looks at a later value of the account. Incarnations only grow, so
+// one above the incarnation at target was created after it.
+func (u *unwoundAccount) note(addr, enc []byte) error {
+	if len(enc) == 0 {
+		return nil
+	}
+	var a account.StateAccount
+	if err := a.DecodeForStorage(enc); err != nil {
+		return fmt.Errorf("decoding account %x: %w", addr, err)
+	}
+	if u.acc != nil && a.Incarnation <= u.acc.Incarnation {
+		return nil
+	}
+	if a.Incarnation > 0 && (u.created == 0 || a.Incarnation < u.created) {
+		u.created = a.Incarnation
+	}
+	return nil
+}
+
+func unwindAccount(tx kv.RwTx, addr []byte, u *unwoundAccount) error {
+	if err := dropIncarnations(tx, addr, u.created); err != nil {
+		return err
+	}
+	if u.acc == nil {
+		if u.created > 0 {
+			// Without an account at target the first incarnation created
+			// after it follows the one recorded as deleted at target.
+			if u.created == 1 {
+				if err := tx.Delete(modules.IncarnationMap, addr); err != nil {
+					return err
+				}
+			} else {
+				var b [8]byte
+				binary.BigEndian.PutUint16(b[:], u.created-1)
+				if err := tx.Put(modules.IncarnationMap, addr, b[:]); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Delete(modules.Account, addr)
+	}
+	a := *u.acc
+	// Change sets omit the code hash of contracts, recover it the same way
+	// PlainState does.
+	if a.Incarnation > 0 && a.IsEmptyCodeHash() {
+		codeHash, err := tx.GetOne(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(addr, a.Incarnation))
+		if err != nil {
+			return err
+		}
+		if len(codeHash) > 0 {
+			a.CodeHash = types.BytesToHash(codeHash)
+		}
+	}
+	data, err := proto.Marshal(a.ToProtoMessage())
+	if err != nil {
+		return err
+	}
+	return tx.Put(modules.Account, addr, data)
+}
+
+// dropIncarnations removes the code pointers of the contract incarnations of
+// addr from first on and releases their code.
+func dropIncarnations(tx kv.RwTx, addr []byte, first uint16) error {
+	if first == 0 {
+		return nil
+	}
+	var keys, hashes [][]byte
+	if err := tx.ForPrefix(modules.PlainContractCode, addr, func(k, v []byte) error {
+		if len(k) == len(addr)+modules.NumberLength && binary.BigEndian.Uint16(k[len(addr):]) >= first {
+			keys = append(keys, types.CopyBytes(k))
+			hashes = append(hashes, types.CopyBytes(v))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		if err := tx.Delete(modules.PlainContractCode, k); err != nil {
+			return err
+		}
+		if err := releaseCode(tx, hashes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
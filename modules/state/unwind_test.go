@@ -0,0 +1,218 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/n42blockchain/N42/common/account"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/modules"
+)
+
+func newTestAccount(balance uint64) *account.StateAccount {
+	acc := account.NewAccount()
+	acc.Initialised = true
+	acc.Balance.SetUint64(balance)
+	return &acc
+}
+
+func TestUnwindState(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		addr    = types.HexToAddress("0x01")
+		created = types.HexToAddress("0x02")
+		slot    = types.HexToHash("0x03")
+		empty   = account.NewAccount()
+	)
+	commit := func(w *PlainStateWriter) {
+		t.Helper()
+		if err := w.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := NewPlainStateWriter(tx, tx, 1)
+	if err := w.UpdateAccountData(addr, &empty, newTestAccount(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteAccountStorage(addr, 1, &slot, uint256.NewInt(0), uint256.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	commit(w)
+
+	w = NewPlainStateWriter(tx, tx, 2)
+	if err := w.UpdateAccountData(addr, newTestAccount(100), newTestAccount(200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteAccountStorage(addr, 1, &slot, uint256.NewInt(5), uint256.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.UpdateAccountData(created, &empty, newTestAccount(1)); err != nil {
+		t.Fatal(err)
+	}
+	commit(w)
+
+	if err := UnwindState(tx, 1); err != nil {
+		t.Fatalf("UnwindState failed: %v", err)
+	}
+
+	r := NewPlainStateReader(tx)
+	acc, err := r.ReadAccountData(addr)
+	if err != nil || acc == nil {
+		t.Fatalf("account missing after unwind: %v", err)
+	}
+	if acc.Balance.Uint64() != 100 {
+		t.Errorf("balance = %d, want 100", acc.Balance.Uint64())
+	}
+	if v, _ := r.ReadAccountStorage(addr, 1, &slot); new(uint256.Int).SetBytes(v).Uint64() != 5 {
+		t.Errorf("storage = %x, want 5", v)
+	}
+	if acc, _ := r.ReadAccountData(created); acc != nil {
+		t.Errorf("account created in block 2 survived the unwind")
+	}
+	if v, _ := tx.GetOne(modules.AccountChangeSet, modules.EncodeBlockNumber(2)); len(v) != 0 {
+		t.Errorf("change set of block 2 survived the unwind")
+	}
+
+	// History reads at block 2 must fall through to the unwound plain state.
+	acc, err = NewPlainState(tx, 2).ReadAccountData(addr)
+	if err != nil || acc == nil || acc.Balance.Uint64() != 100 {
+		t.Errorf("historical read at block 2 = %v, %v", acc, err)
+	}
+	// Block 1 is kept, so its history still resolves.
+	if acc, _ := NewPlainState(tx, 1).ReadAccountData(addr); acc != nil {
+		t.Errorf("historical read at block 1 = %v, want nil", acc)
+	}
+}
+
+func TestUnwindStateReleasesCode(t *testing.T) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	db := memdb.NewTestDB(t)
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		kept  = types.HexToAddress("0x01")
+		fresh = types.HexToAddress("0x02")
+		codeA = []byte{0x60, 0x01}
+		codeB = []byte{0x60, 0x02}
+		hashA = crypto.Keccak256Hash(codeA)
+		hashB = crypto.Keccak256Hash(codeB)
+		empty = account.NewAccount()
+	)
+	contract := func(incarnation uint16, codeHash types.Hash) *account.StateAccount {
+		acc := newTestAccount(1)
+		acc.Incarnation = incarnation
+		acc.CodeHash = codeHash
+		return acc
+	}
+	commit := func(w *PlainStateWriter) {
+		t.Helper()
+		if err := w.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Block 1 deploys kept, block 2 destroys it and block 3 deploys it again
+	// next to fresh, both running the same code.
+	w := NewPlainStateWriter(tx, tx, 1)
+	if err := w.UpdateAccountData(kept, &empty, contract(1, hashA)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.UpdateAccountCode(kept, 1, hashA, codeA); err != nil {
+		t.Fatal(err)
+	}
+	commit(w)
+
+	w = NewPlainStateWriter(tx, tx, 2)
+	if err := w.DeleteAccount(kept, contract(1, hashA)); err != nil {
+		t.Fatal(err)
+	}
+	commit(w)
+
+	w = NewPlainStateWriter(tx, tx, 3)
+	for _, addr := range []types.Address{kept, fresh} {
+		incarnation := uint16(1)
+		if addr == kept {
+			incarnation = 2
+		}
+		if err := w.UpdateAccountData(addr, &empty, contract(incarnation, hashB)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.UpdateAccountCode(addr, incarnation, hashB, codeB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit(w)
+
+	if err := UnwindState(tx, 1); err != nil {
+		t.Fatalf("UnwindState failed: %v", err)
+	}
+
+	acc, err := NewPlainStateReader(tx).ReadAccountData(kept)
+	if err != nil || acc == nil {
+		t.Fatalf("account missing after unwind: %v", err)
+	}
+	if acc.Incarnation != 1 || acc.CodeHash != hashA {
+		t.Errorf("account = incarnation %d code %x, want 1 %x", acc.Incarnation, acc.CodeHash, hashA)
+	}
+	if code, _ := tx.GetOne(modules.Code, hashA[:]); len(code) == 0 {
+		t.Errorf("code of the kept incarnation was released")
+	}
+	for _, key := range [][]byte{
+		modules.PlainGenerateStoragePrefix(kept[:], 2),
+		modules.PlainGenerateStoragePrefix(fresh[:], 1),
+	} {
+		if v, _ := tx.GetOne(modules.PlainContractCode, key); len(v) != 0 {
+			t.Errorf("code of incarnation %x created after the target survived", key)
+		}
+	}
+	// Released code is kept, only DedupCode deletes it
+	if code, _ := tx.GetOne(modules.Code, hashB[:]); len(code) == 0 {
+		t.Errorf("code only referenced after the target was deleted")
+	}
+	if refs, _ := readCodeRefs(tx, hashB[:]); refs != 0 {
+		t.Errorf("code refs = %d, want 0", refs)
+	}
+	if v, _ := tx.GetOne(modules.IncarnationMap, fresh[:]); len(v) != 0 {
+		t.Errorf("incarnation of an account created after the target survived")
+	}
+}
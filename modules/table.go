@@ -30,6 +30,7 @@ const (
 	// DatabaseInfo is used to store information about data layout.
 	DatabaseInfo = "DbInfo"
 	ChainConfig  = "ChainConfig"
+	Migrations   = "Migrations" // migration name -> applied migration record
 )
 
 // PlainState
@@ -47,6 +48,7 @@ const (
 	ContractCode = "HashedCodeHash"
 
 	PlainContractCode = "PlainCodeHash" // address+incarnation -> code hash
+	CodeRefs          = "CodeRefs"      // code hash -> number of PlainCodeHash entries holding it (u64)
 
 	// IncarnationMap "incarnation" - uint16 number - how much times given account was SelfDestruct'ed
 	IncarnationMap = "IncarnationMap" // address -> incarnation of account when it was last deleted
@@ -61,6 +63,12 @@ const (
 	StorageHistory   = "StorageHistory"   // address + storage_key + shard_id_u64 -> roaring bitmap - list of block where it changed
 )
 
+// Commitment
+const (
+	CommitmentBranch = "CommitmentBranch" // compact nibble path -> state trie branch node
+	CommitmentRoot   = "CommitmentRoot"   // block_num_u64 -> block hash + state trie root
+)
+
 // Block
 const (
 	Headers         = "Header"                 // block_num_u64 + hash -> header
@@ -81,12 +89,16 @@ const (
 
 	BlockVerify  = "BlockVerify"
 	BlockRewards = "BlockRewards"
+	Evidence     = "Evidence" // block_num_u64 + address -> double signing evidence
+	Finality     = "Finality" // "finalized" / "safe" -> block hash
 
 	// Transaction senders - stored separately from the block bodies
 	Senders = "TxSender" // block_num_u64 + blockHash -> sendersList (no serialization format, every 20 bytes is new sender)
 
-	Receipts = "Receipt"        // block_num_u64 -> canonical block receipts (non-canonical are not stored)
-	Log      = "TransactionLog" // block_num_u64 + txId -> logs of transaction
+	Receipts      = "Receipt"        // block_num_u64 -> canonical block receipts (non-canonical are not stored)
+	Log           = "TransactionLog" // block_num_u64 + txId -> logs of transaction
+	RevertReasons = "RevertReason"   // tx_hash -> return data of a reverted transaction
+	AddressBloom  = "AddressBloom"   // range_u64 -> indexed blocks bitmap + bloom of addresses active in the range
 
 	// Stores bitmap indices - in which block numbers saw logs of given 'address' or 'topic'
 	// [addr or topic] + [2 bytes inverted shard number] -> bitmap(blockN)
@@ -124,16 +136,21 @@ var n42Tables = []string{
 	Account,
 	Storage,
 	PlainContractCode,
+	CodeRefs,
 	IncarnationMap,
 
 	DatabaseInfo,
 	ChainConfig,
+	Migrations,
 
 	AccountsHistory,
 	AccountChangeSet,
 	StorageHistory,
 	StorageChangeSet,
 
+	CommitmentBranch,
+	CommitmentRoot,
+
 	Headers,
 	HeaderTD,
 	HeaderCanonical,
@@ -149,6 +166,8 @@ var n42Tables = []string{
 	Senders,
 	Receipts,
 	Log,
+	RevertReasons,
+	AddressBloom,
 
 	SignersDB,
 	PoaSnapshot,
@@ -158,6 +177,8 @@ var n42Tables = []string{
 	Deposit,
 	BlockVerify,
 	BlockRewards,
+	Evidence,
+	Finality,
 }
 
 var N42TableCfg = kv.TableCfg{
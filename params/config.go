@@ -21,9 +21,9 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"github.com/n42blockchain/N42/common/avmutil"
 	"github.com/n42blockchain/N42/common/paths"
 	"github.com/n42blockchain/N42/common/types"
-	"github.com/n42blockchain/N42/common/avmutil"
 	"github.com/n42blockchain/N42/params/networkname"
 	"golang.org/x/crypto/sha3"
 	"math/big"
@@ -62,6 +62,15 @@ const (
 	Faker           ConsensusType = "faker" // faker consensus
 )
 
+// CommitmentScheme selects how the state commitment of the
+// --state.commitment service is computed.
+type CommitmentScheme string
+
+const (
+	HexPatriciaCommitment CommitmentScheme = "mpt"    // hex patricia trie, the default
+	VerkleCommitment      CommitmentScheme = "verkle" // experimental Verkle tree (EIP-6800 key layout)
+)
+
 // Genesis hashes to enforce below configs on.
 var (
 	MainnetGenesisHash = types.HexToHash("0x138734b7044254e5ecbabf8056f5c2b73cd0847aaa5acac7345507cbeab387b8")
@@ -75,6 +84,29 @@ var (
 	// TestnetChainConfig contains the chain parameters to run a node on the Test network.
 	TestnetChainConfig = readChainSpec("chainspecs/testnet.json")
 
+	// DevChainConfig contains the chain parameters of the --dev chain. Every
+	// fork up to Cancun and the size limits are active from genesis and blocks
+	// are sealed by the faker engine of the node itself.
+	DevChainConfig = &ChainConfig{
+		ChainID:               big.NewInt(1337),
+		Consensus:             Faker,
+		HomesteadBlock:        big.NewInt(0),
+		TangerineWhistleBlock: big.NewInt(0),
+		SpuriousDragonBlock:   big.NewInt(0),
+		ByzantiumBlock:        big.NewInt(0),
+		ConstantinopleBlock:   big.NewInt(0),
+		PetersburgBlock:       big.NewInt(0),
+		IstanbulBlock:         big.NewInt(0),
+		MuirGlacierBlock:      big.NewInt(0),
+		BerlinBlock:           big.NewInt(0),
+		LondonBlock:           big.NewInt(0),
+		ArrowGlacierBlock:     big.NewInt(0),
+		GrayGlacierBlock:      big.NewInt(0),
+		ShanghaiBlock:         big.NewInt(0),
+		CancunBlock:           big.NewInt(0),
+		LimitsBlock:           big.NewInt(0),
+	}
+
 	TestChainConfig = &ChainConfig{
 		ChainID:               big.NewInt(1),
 		Consensus:             EtHashConsensus,
@@ -135,12 +167,12 @@ type ChainConfig struct {
 	MergeNetsplitBlock            *big.Int `json:"mergeNetsplitBlock,omitempty"`            // Virtual fork after The Merge to use as a network splitter; see FORK_NEXT_VALUE in EIP-3675
 
 	ShanghaiBlock    *big.Int `json:"shanghaiBlock,omitempty"` // Shanghai switch block (nil = no fork, 0 = already activated)
-	CancunBlock      *big.Int `json:"cancunTime,omitempty"`
+	CancunBlock      *big.Int `json:"cancunBlock,omitempty"`   // Cancun switch block (nil = no fork, 0 = already activated)
 	ShardingForkTime *big.Int `json:"shardingForkTime,omitempty"`
 	PragueTime       *big.Int `json:"pragueTime,omitempty"`
-	PectraTime       *big.Int `json:"pectraTime,omitempty"`  // Pectra switch time (nil = no fork)
-	OsakaTime        *big.Int `json:"osakaTime,omitempty"`   // Osaka switch time (nil = no fork)
-	FusakaTime       *big.Int `json:"fusakaTime,omitempty"`  // Fusaka switch time (nil = no fork) - Native AA
+	PectraTime       *big.Int `json:"pectraTime,omitempty"` // Pectra switch time (nil = no fork)
+	OsakaTime        *big.Int `json:"osakaTime,omitempty"`  // Osaka switch time (nil = no fork)
+	FusakaTime       *big.Int `json:"fusakaTime,omitempty"` // Fusaka switch time (nil = no fork) - Native AA
 
 	// Parlia fork blocks
 	//RamanujanBlock  *big.Int    `json:"ramanujanBlock,omitempty" toml:",omitempty"`  // ramanujanBlock switch block (nil = no fork, 0 = already activated)
@@ -160,6 +192,21 @@ type ChainConfig struct {
 	Eip1559FeeCollector           *types.Address `json:"eip1559FeeCollector,omitempty"`           // (Optional) Address where burnt EIP-1559 fees go to
 	Eip1559FeeCollectorTransition *big.Int       `json:"eip1559FeeCollectorTransition,omitempty"` // (Optional) Block from which burnt EIP-1559 fees go to the Eip1559FeeCollector
 
+	// Size and gas limits enforced by the txpool and block validation (0 = protocol default).
+	// Block validation only enforces them from LimitsBlock on, so blocks produced
+	// before the limits existed stay valid.
+	LimitsBlock     *big.Int `json:"limitsBlock,omitempty"`     // Limits switch block (nil = not enforced, 0 = from genesis)
+	MaxTxSize       uint64   `json:"maxTxSize,omitempty"`       // Maximum encoded size of a single transaction in bytes
+	MaxInitCodeSize uint64   `json:"maxInitCodeSize,omitempty"` // Maximum init code of a contract creation transaction (EIP-3860)
+	MaxBlockSize    uint64   `json:"maxBlockSize,omitempty"`    // Maximum encoded size of a block in bytes
+	MaxBlockGas     uint64   `json:"maxBlockGas,omitempty"`     // Maximum gas limit a block header may declare
+
+	// Scheme of the state commitment kept by --state.commitment (empty = mpt)
+	StateCommitment CommitmentScheme `json:"stateCommitment,omitempty"`
+
+	// Additional precompiled contracts of app-chains, see vm.RegisterPrecompile
+	CustomPrecompiles []CustomPrecompileConfig `json:"customPrecompiles,omitempty"`
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
@@ -169,6 +216,14 @@ type ChainConfig struct {
 	Apos   *APosConfig   `json:"apos,omitempty"`
 }
 
+// CustomPrecompileConfig enables the precompiled contract registered under
+// Name at Address from block Block on.
+type CustomPrecompileConfig struct {
+	Name    string        `json:"name"`
+	Address types.Address `json:"address"`
+	Block   *big.Int      `json:"block,omitempty"` // activation block (nil = genesis)
+}
+
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
 type EthashConfig struct{}
 
@@ -292,6 +347,24 @@ func (c *BorConfig) calculateBorConfigHelper(field map[string]uint64, number uin
 	return field[keys[len(keys)-1]]
 }
 
+// UnmarshalJSON implements json.Unmarshaler. Configs stored before the Cancun
+// fork was keyed cancunBlock carry it as cancunTime, which is still accepted.
+func (c *ChainConfig) UnmarshalJSON(input []byte) error {
+	type chainConfig ChainConfig
+	var dec struct {
+		chainConfig
+		CancunTime *big.Int `json:"cancunTime,omitempty"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*c = ChainConfig(dec.chainConfig)
+	if c.CancunBlock == nil {
+		c.CancunBlock = dec.CancunTime
+	}
+	return nil
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	return fmt.Sprintf("{ChainID: %v, Homestead: %v, DAO: %v, DAO Support: %v, Tangerine Whistle: %v, Spurious Dragon: %v, Byzantium: %v, Constantinople: %v, Petersburg: %v, Istanbul: %v, Muir Glacier: %v, Berlin: %v, London: %v, Arrow Glacier: %v, Gray Glacier: %v, Terminal Total Difficulty: %v, Merge Netsplit: %v, Shanghai: %v, Cancun: %v}",
@@ -317,6 +390,14 @@ func (c *ChainConfig) String() string {
 	)
 }
 
+// CommitmentScheme returns the configured state commitment scheme.
+func (c *ChainConfig) CommitmentScheme() CommitmentScheme {
+	if c.StateCommitment == "" {
+		return HexPatriciaCommitment
+	}
+	return c.StateCommitment
+}
+
 func (c *ChainConfig) IsHeaderWithSeal() bool {
 	return c.Consensus == AuRaConsensus
 }
@@ -501,6 +582,45 @@ func (c *ChainConfig) IsShanghai(num uint64) bool {
 	return isForked(c.ShanghaiBlock, num)
 }
 
+// IsLimits returns whether num is either equal to the limits fork block or
+// greater, i.e. whether blocks are validated against the size and gas limits.
+func (c *ChainConfig) IsLimits(num uint64) bool {
+	return isForked(c.LimitsBlock, num)
+}
+
+// TxSizeLimit returns the maximum encoded size of a single transaction.
+func (c *ChainConfig) TxSizeLimit() uint64 {
+	if c.MaxTxSize != 0 {
+		return c.MaxTxSize
+	}
+	return DefaultMaxTxSize
+}
+
+// InitCodeSizeLimit returns the maximum init code size of a contract creation
+// transaction once EIP-3860 (Shanghai) is active.
+func (c *ChainConfig) InitCodeSizeLimit() uint64 {
+	if c.MaxInitCodeSize != 0 {
+		return c.MaxInitCodeSize
+	}
+	return MaxInitCodeSize
+}
+
+// BlockSizeLimit returns the maximum encoded size of a block.
+func (c *ChainConfig) BlockSizeLimit() uint64 {
+	if c.MaxBlockSize != 0 {
+		return c.MaxBlockSize
+	}
+	return DefaultMaxBlockSize
+}
+
+// BlockGasLimit returns the maximum gas limit a block header may declare.
+func (c *ChainConfig) BlockGasLimit() uint64 {
+	if c.MaxBlockGas != 0 {
+		return c.MaxBlockGas
+	}
+	return MaxGasLimit
+}
+
 // IsCancun returns whether num is either equal to the Cancun fork block or greater.
 func (c *ChainConfig) IsCancun(num uint64) bool {
 	return isForked(c.CancunBlock, num)
@@ -533,6 +653,21 @@ func (c *ChainConfig) IsBeijing(num uint64) bool {
 	return isForked(c.BeijingBlock, num)
 }
 
+// ActiveCustomPrecompiles returns the names of the custom precompiled contracts
+// enabled at block num, keyed by address.
+func (c *ChainConfig) ActiveCustomPrecompiles(num uint64) map[types.Address]string {
+	if len(c.CustomPrecompiles) == 0 {
+		return nil
+	}
+	active := make(map[types.Address]string, len(c.CustomPrecompiles))
+	for _, p := range c.CustomPrecompiles {
+		if p.Block == nil || isForked(p.Block, num) {
+			active[p.Address] = p.Name
+		}
+	}
+	return active
+}
+
 func (c *ChainConfig) IsEip1559FeeCollector(num uint64) bool {
 	return c.Eip1559FeeCollector != nil && isForked(c.Eip1559FeeCollectorTransition, num)
 }
@@ -574,13 +709,8 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 	if c != nil && c.ChainID != nil && c.ChainID.Uint64() == 77 {
 		return nil
 	}
-	type fork struct {
-		name     string
-		block    *big.Int
-		optional bool // if true, the fork may be nil and next fork is still allowed
-	}
-	var lastFork fork
-	for _, cur := range []fork{
+	// Block based forks
+	if err := checkForkOrder([]fork{
 		{name: "homesteadBlock", block: c.HomesteadBlock},
 		{name: "daoForkBlock", block: c.DAOForkBlock, optional: true},
 		{name: "eip150Block", block: c.TangerineWhistleBlock},
@@ -599,7 +729,27 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		{name: "mergeNetsplitBlock", block: c.MergeNetsplitBlock, optional: true},
 		{name: "shanghaiBlock", block: c.ShanghaiBlock},
 		{name: "cancunBlock", block: c.CancunBlock},
-	} {
+	}); err != nil {
+		return err
+	}
+	// Timestamp based forks
+	return checkForkOrder([]fork{
+		{name: "pragueTime", block: c.PragueTime},
+		{name: "pectraTime", block: c.PectraTime, optional: true},
+		{name: "osakaTime", block: c.OsakaTime},
+		{name: "fusakaTime", block: c.FusakaTime, optional: true},
+	})
+}
+
+type fork struct {
+	name     string
+	block    *big.Int
+	optional bool // if true, the fork may be nil and next fork is still allowed
+}
+
+func checkForkOrder(forks []fork) error {
+	var lastFork fork
+	for _, cur := range forks {
 		if lastFork.name != "" {
 			// Next one must be higher number
 			if lastFork.block == nil && cur.block != nil {
@@ -681,6 +831,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head uint64) *ConfigC
 	if isForkIncompatible(c.CancunBlock, newcfg.CancunBlock, head) {
 		return newCompatError("Cancun fork block", c.CancunBlock, newcfg.CancunBlock)
 	}
+	if isForkIncompatible(c.LimitsBlock, newcfg.LimitsBlock, head) {
+		return newCompatError("Limits fork block", c.LimitsBlock, newcfg.LimitsBlock)
+	}
 
 	// Parlia forks
 	//if isForkIncompatible(c.RamanujanBlock, newcfg.RamanujanBlock, head) {
@@ -777,8 +930,10 @@ type Rules struct {
 	IsBerlin, IsLondon, IsShanghai, IsCancun, IsPrague      bool
 	IsPectra, IsOsaka, IsFusaka                             bool // Pectra: EIP-7702, Osaka: EOF, Fusaka: Native AA
 	IsNano, IsMoran                                         bool
+	IsLimits                                                bool
 	IsEip1559FeeCollector                                   bool
 	IsParlia, IsStarknet, IsAura, IsBeijing                 bool
+	CustomPrecompiles                                       map[types.Address]string // names of the enabled custom precompiles
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -806,10 +961,12 @@ func (c *ChainConfig) Rules(num uint64) *Rules {
 		IsFusaka:              c.IsFusaka(num),
 		IsNano:                c.IsNano(num),
 		IsMoran:               c.IsMoran(num),
+		IsLimits:              c.IsLimits(num),
 		IsEip1559FeeCollector: c.IsEip1559FeeCollector(num),
 		IsParlia:              c.Parlia != nil,
 		IsAura:                c.Aura != nil,
 		IsBeijing:             c.IsBeijing(num),
+		CustomPrecompiles:     c.ActiveCustomPrecompiles(num),
 	}
 }
 
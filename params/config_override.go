@@ -0,0 +1,81 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ForkOverride is a fork whose activation can be moved at startup with
+// --override.<Name>, so upgrades can be tested on private networks without a
+// new genesis.
+type ForkOverride struct {
+	Name  string
+	Time  bool // activated by block time instead of block number
+	field func(c *ChainConfig) **big.Int
+}
+
+// OverridableForks lists the forks which may be overridden, in activation order.
+var OverridableForks = []ForkOverride{
+	{Name: "shanghai", field: func(c *ChainConfig) **big.Int { return &c.ShanghaiBlock }},
+	{Name: "cancun", field: func(c *ChainConfig) **big.Int { return &c.CancunBlock }},
+	{Name: "nano", field: func(c *ChainConfig) **big.Int { return &c.NanoBlock }},
+	{Name: "moran", field: func(c *ChainConfig) **big.Int { return &c.MoranBlock }},
+	{Name: "beijing", field: func(c *ChainConfig) **big.Int { return &c.BeijingBlock }},
+	{Name: "prague", Time: true, field: func(c *ChainConfig) **big.Int { return &c.PragueTime }},
+	{Name: "pectra", Time: true, field: func(c *ChainConfig) **big.Int { return &c.PectraTime }},
+	{Name: "osaka", Time: true, field: func(c *ChainConfig) **big.Int { return &c.OsakaTime }},
+	{Name: "fusaka", Time: true, field: func(c *ChainConfig) **big.Int { return &c.FusakaTime }},
+}
+
+// WithOverrides returns a copy of c with the forks named in overrides moved to
+// the given block number or time. A fork cannot be moved once the head
+// (block number head, time headTime) reached either its stored or its new
+// activation, since that would change blocks already in the database, and the
+// forks must stay in order.
+func (c *ChainConfig) WithOverrides(overrides map[string]uint64, head, headTime uint64) (*ChainConfig, error) {
+	cpy := *c
+	for name, at := range overrides {
+		var fork *ForkOverride
+		for i := range OverridableForks {
+			if OverridableForks[i].Name == name {
+				fork = &OverridableForks[i]
+				break
+			}
+		}
+		if fork == nil {
+			return nil, fmt.Errorf("override.%s: unknown fork", name)
+		}
+		pos, unit := head, "block"
+		if fork.Time {
+			pos, unit = headTime, "time"
+		}
+		stored, want := *fork.field(c), new(big.Int).SetUint64(at)
+		if isForkIncompatible(stored, want, pos) {
+			if isForked(stored, pos) {
+				return nil, fmt.Errorf("override.%s: fork already active at %s %v, head is at %d", name, unit, stored, pos)
+			}
+			return nil, fmt.Errorf("override.%s: %s %d is not after the head at %d", name, unit, at, pos)
+		}
+		*fork.field(&cpy) = want
+	}
+	if err := cpy.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	return &cpy, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestWithOverrides(t *testing.T) {
+	stored := &ChainConfig{
+		ChainID:      big.NewInt(1),
+		BeijingBlock: big.NewInt(100),
+		PragueTime:   big.NewInt(5000),
+	}
+
+	cfg, err := stored.WithOverrides(map[string]uint64{"beijing": 300, "osaka": 6000}, 50, 4000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BeijingBlock.Uint64() != 300 || cfg.OsakaTime.Uint64() != 6000 {
+		t.Errorf("overrides not applied: beijing %v osaka %v", cfg.BeijingBlock, cfg.OsakaTime)
+	}
+	if stored.BeijingBlock.Uint64() != 100 || stored.OsakaTime != nil {
+		t.Error("stored config modified")
+	}
+
+	tests := []struct {
+		overrides map[string]uint64
+		err       string
+	}{
+		{map[string]uint64{"london": 10}, "unknown fork"},
+		{map[string]uint64{"beijing": 300}, "already active at block 100"}, // head 150 passed the stored block
+		{map[string]uint64{"prague": 1000}, "time 1000 is not after the head"},
+		{map[string]uint64{"osaka": 4500}, "unsupported fork ordering"}, // before prague
+	}
+	for i, test := range tests {
+		if _, err := stored.WithOverrides(test.overrides, 150, 2000); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("test %d: got error %v, want %q", i, err, test.err)
+		}
+	}
+	// Restating the stored activation is always allowed
+	if _, err := stored.WithOverrides(map[string]uint64{"beijing": 100}, 150, 2000); err != nil {
+		t.Errorf("unchanged fork rejected: %v", err)
+	}
+}
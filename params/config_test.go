@@ -0,0 +1,90 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestLimitsFork(t *testing.T) {
+	cfg := &ChainConfig{ChainID: big.NewInt(1), LimitsBlock: big.NewInt(100)}
+	for _, test := range []struct {
+		num  uint64
+		want bool
+	}{{0, false}, {99, false}, {100, true}, {1000, true}} {
+		if got := cfg.IsLimits(test.num); got != test.want {
+			t.Errorf("IsLimits(%d) = %v, want %v", test.num, got, test.want)
+		}
+		if got := cfg.Rules(test.num).IsLimits; got != test.want {
+			t.Errorf("Rules(%d).IsLimits = %v, want %v", test.num, got, test.want)
+		}
+	}
+	// Chains configured before the fork existed never enforce the limits.
+	if (&ChainConfig{ChainID: big.NewInt(1)}).IsLimits(1 << 40) {
+		t.Error("limits active without a fork block")
+	}
+
+	// Moving the fork below the head would change the validity of stored blocks.
+	moved := &ChainConfig{ChainID: big.NewInt(1), LimitsBlock: big.NewInt(50)}
+	if err := cfg.CheckCompatible(moved, 200); err == nil {
+		t.Error("moving the limits fork below the head accepted")
+	} else if err.RewindTo != 49 {
+		t.Errorf("rewind to %d, want 49", err.RewindTo)
+	}
+	if err := cfg.CheckCompatible(moved, 40); err != nil {
+		t.Errorf("moving a future limits fork rejected: %v", err)
+	}
+}
+
+func TestChainConfigCancunTimeKey(t *testing.T) {
+	// Configs stored before the rename carry the Cancun fork as cancunTime.
+	var stored ChainConfig
+	if err := json.Unmarshal([]byte(`{"chainId":1,"shanghaiBlock":0,"cancunTime":1000}`), &stored); err != nil {
+		t.Fatalf("decoding stored config: %v", err)
+	}
+	if stored.CancunBlock == nil || stored.CancunBlock.Uint64() != 1000 {
+		t.Fatalf("CancunBlock = %v, want 1000", stored.CancunBlock)
+	}
+	if stored.ChainID == nil || stored.ChainID.Uint64() != 1 || stored.ShanghaiBlock == nil {
+		t.Errorf("other fields lost: %v", &stored)
+	}
+	if !stored.IsCancun(1000) || stored.IsCancun(999) {
+		t.Errorf("Cancun activation does not follow the stored key")
+	}
+
+	// The current key wins and survives a round trip.
+	var current ChainConfig
+	if err := json.Unmarshal([]byte(`{"cancunBlock":5,"cancunTime":1000}`), &current); err != nil {
+		t.Fatal(err)
+	}
+	if current.CancunBlock == nil || current.CancunBlock.Uint64() != 5 {
+		t.Fatalf("CancunBlock = %v, want 5", current.CancunBlock)
+	}
+	enc, err := json.Marshal(&current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ChainConfig
+	if err := json.Unmarshal(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.CancunBlock == nil || decoded.CancunBlock.Uint64() != 5 {
+		t.Errorf("round trip CancunBlock = %v, want 5", decoded.CancunBlock)
+	}
+}
@@ -133,6 +133,9 @@ const (
 	MaxCodeSize     = 24576           // Maximum bytecode to permit for a contract
 	MaxInitCodeSize = 2 * MaxCodeSize // Maximum initcode to permit in a creation transaction and create instructions
 
+	DefaultMaxTxSize    = 128 * 1024  // Default maximum encoded size of a single transaction
+	DefaultMaxBlockSize = 1024 * 1024 // Default maximum encoded size of a block, bounded by the p2p chunk size
+
 	// Precompiled contract gas prices
 
 	TendermintHeaderValidateGas uint64 = 3000 // Gas for validate tendermiint consensus state
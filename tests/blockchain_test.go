@@ -0,0 +1,328 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/math"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/internal/vm/runtime"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// beaconRootsAddress is the EIP-4788 contract called at the start of every
+// Cancun block with the parent beacon block root.
+var beaconRootsAddress = types.HexToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02")
+
+// blockchainTest is a BlockchainTests fixture of github.com/ethereum/tests.
+// Blocks are executed from their decoded JSON form, as N42 cannot decode the
+// Ethereum block RLP, and the post state is compared account by account.
+type blockchainTest struct {
+	Network   string                      `json:"network"`
+	Genesis   btHeader                    `json:"genesisBlockHeader"`
+	Pre       map[types.Address]btAccount `json:"pre"`
+	Blocks    []btBlock                   `json:"blocks"`
+	PostState map[types.Address]btAccount `json:"postState"`
+}
+
+type btHeader struct {
+	Hash                  types.Hash            `json:"hash"`
+	Coinbase              types.Address         `json:"coinbase"`
+	Difficulty            *math.HexOrDecimal256 `json:"difficulty"`
+	Number                math.HexOrDecimal64   `json:"number"`
+	GasLimit              math.HexOrDecimal64   `json:"gasLimit"`
+	GasUsed               math.HexOrDecimal64   `json:"gasUsed"`
+	Timestamp             math.HexOrDecimal64   `json:"timestamp"`
+	MixHash               types.Hash            `json:"mixHash"`
+	BaseFee               *math.HexOrDecimal256 `json:"baseFeePerGas"`
+	BlobGasUsed           math.HexOrDecimal64   `json:"blobGasUsed"`
+	ExcessBlobGas         math.HexOrDecimal64   `json:"excessBlobGas"`
+	ParentBeaconBlockRoot *types.Hash           `json:"parentBeaconBlockRoot"`
+}
+
+type btBlock struct {
+	Header          *btHeader         `json:"blockHeader"`
+	Transactions    []btTransaction   `json:"transactions"`
+	UncleHeaders    []json.RawMessage `json:"uncleHeaders"`
+	Withdrawals     []btWithdrawal    `json:"withdrawals"`
+	ExpectException string            `json:"expectException"`
+}
+
+type btTransaction struct {
+	Type                 math.HexOrDecimal64    `json:"type"`
+	ChainID              *math.HexOrDecimal256  `json:"chainId"`
+	Nonce                math.HexOrDecimal64    `json:"nonce"`
+	GasPrice             *math.HexOrDecimal256  `json:"gasPrice"`
+	MaxPriorityFeePerGas *math.HexOrDecimal256  `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *math.HexOrDecimal256  `json:"maxFeePerGas"`
+	MaxFeePerBlobGas     *math.HexOrDecimal256  `json:"maxFeePerBlobGas"`
+	GasLimit             math.HexOrDecimal64    `json:"gasLimit"`
+	To                   string                 `json:"to"` // empty for contract creation
+	Value                *math.HexOrDecimal256  `json:"value"`
+	Data                 hexutil.Bytes          `json:"data"`
+	AccessList           transaction.AccessList `json:"accessList"`
+	BlobVersionedHashes  []types.Hash           `json:"blobVersionedHashes"`
+	Sender               *types.Address         `json:"sender"`
+	V, R, S              *math.HexOrDecimal256
+}
+
+type btWithdrawal struct {
+	Address types.Address       `json:"address"`
+	Amount  math.HexOrDecimal64 `json:"amount"` // in gwei
+}
+
+type btAccount struct {
+	Balance *math.HexOrDecimal256 `json:"balance"`
+	Nonce   math.HexOrDecimal64   `json:"nonce"`
+	Code    hexutil.Bytes         `json:"code"`
+	Storage map[string]string     `json:"storage"`
+}
+
+func TestBlockchain(t *testing.T) {
+	walkFixtures(t, "BlockchainTests", func(t *testing.T, path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tests map[string]*blockchainTest
+		if err := json.Unmarshal(data, &tests); err != nil {
+			t.Fatalf("invalid fixture: %v", err)
+		}
+		for name, test := range tests {
+			t.Run(name, test.run)
+		}
+	})
+}
+
+func (bt *blockchainTest) run(t *testing.T) {
+	config, err := runtime.ForkConfig(bt.Network)
+	if err != nil {
+		t.Skip(err)
+	}
+	if d := (*big.Int)(bt.Genesis.Difficulty); d != nil && d.Sign() != 0 {
+		t.Skip("proof of work block rewards are not paid by N42")
+	}
+
+	ibs, release, err := runtime.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	for addr, acc := range bt.Pre {
+		if err := acc.write(ibs, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hashes := map[uint64]types.Hash{uint64(bt.Genesis.Number): bt.Genesis.Hash}
+	getHash := func(n uint64) types.Hash { return hashes[n] }
+	for i, b := range bt.Blocks {
+		// Invalid blocks are only given as RLP and never change the state
+		if b.Header == nil || b.ExpectException != "" {
+			continue
+		}
+		if err := bt.applyBlock(config, ibs, &b, getHash); err != nil {
+			t.Fatalf("block %d: %v", i, err)
+		}
+		hashes[uint64(b.Header.Number)] = b.Header.Hash
+	}
+
+	for addr, want := range bt.PostState {
+		if err := want.check(ibs, addr); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// applyBlock executes the transactions and withdrawals of b on ibs
+func (bt *blockchainTest) applyBlock(config *params.ChainConfig, ibs *state.IntraBlockState, b *btBlock, getHash func(uint64) types.Hash) error {
+	if len(b.UncleHeaders) > 0 {
+		return fmt.Errorf("%d uncles in a proof of stake block", len(b.UncleHeaders))
+	}
+	h := b.Header
+	header := &block.Header{
+		Coinbase:      h.Coinbase,
+		Difficulty:    new(uint256.Int),
+		Number:        uint256.NewInt(uint64(h.Number)),
+		GasLimit:      uint64(h.GasLimit),
+		GasUsed:       uint64(h.GasUsed),
+		Time:          uint64(h.Timestamp),
+		MixDigest:     h.MixHash,
+		BlobGasUsed:   uint64(h.BlobGasUsed),
+		ExcessBlobGas: uint64(h.ExcessBlobGas),
+	}
+	if h.BaseFee != nil {
+		header.BaseFee, _ = uint256.FromBig((*big.Int)(h.BaseFee))
+	}
+	rules := config.Rules(header.Number.Uint64())
+	noop := state.NewNoopWriter()
+
+	if rules.IsCancun && h.ParentBeaconBlockRoot != nil && len(ibs.GetCode(beaconRootsAddress)) > 0 {
+		if _, err := internal.SysCallContract(beaconRootsAddress, h.ParentBeaconBlockRoot[:], *config, ibs, header, nil); err != nil {
+			return fmt.Errorf("beacon root call: %w", err)
+		}
+		if err := ibs.FinalizeTx(rules, noop); err != nil {
+			return err
+		}
+	}
+
+	var usedGas uint64
+	gp := new(common.GasPool).AddGas(header.GasLimit)
+	for i, btx := range b.Transactions {
+		tx, err := btx.toTransaction(config)
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+		ibs.Prepare(tx.Hash(), h.Hash, i)
+		if _, _, err := internal.ApplyTransaction(config, getHash, nil, &header.Coinbase, gp, ibs, noop, header, tx, &usedGas, vm.Config{}); err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	if usedGas != header.GasUsed {
+		return fmt.Errorf("gas used by execution: %d, in header: %d", usedGas, header.GasUsed)
+	}
+
+	gwei := uint256.NewInt(params.GWei)
+	for _, w := range b.Withdrawals {
+		ibs.AddBalance(w.Address, new(uint256.Int).Mul(uint256.NewInt(uint64(w.Amount)), gwei))
+	}
+	return ibs.FinalizeTx(rules, noop)
+}
+
+// toTransaction converts btx to an N42 transaction sent by its sender
+func (btx *btTransaction) toTransaction(config *params.ChainConfig) (*transaction.Transaction, error) {
+	if btx.Sender == nil {
+		return nil, fmt.Errorf("transaction without sender")
+	}
+	var to *types.Address
+	if btx.To != "" {
+		addr := types.HexToAddress(btx.To)
+		to = &addr
+	}
+	chainID, _ := uint256.FromBig(config.ChainID)
+	value := bigToUint256(btx.Value)
+	v, r, s := bigToUint256(btx.V), bigToUint256(btx.R), bigToUint256(btx.S)
+
+	switch btx.Type {
+	case transaction.LegacyTxType:
+		return transaction.NewTx(&transaction.LegacyTx{
+			Nonce: uint64(btx.Nonce), GasPrice: bigToUint256(btx.GasPrice), Gas: uint64(btx.GasLimit),
+			To: to, From: btx.Sender, Value: value, Data: btx.Data, V: v, R: r, S: s,
+		}), nil
+	case transaction.AccessListTxType:
+		return transaction.NewTx(&transaction.AccessListTx{
+			ChainID: chainID, Nonce: uint64(btx.Nonce), GasPrice: bigToUint256(btx.GasPrice), Gas: uint64(btx.GasLimit),
+			To: to, From: btx.Sender, Value: value, Data: btx.Data, AccessList: btx.AccessList, V: v, R: r, S: s,
+		}), nil
+	case transaction.DynamicFeeTxType:
+		return transaction.NewTx(&transaction.DynamicFeeTx{
+			ChainID: chainID, Nonce: uint64(btx.Nonce), GasTipCap: bigToUint256(btx.MaxPriorityFeePerGas),
+			GasFeeCap: bigToUint256(btx.MaxFeePerGas), Gas: uint64(btx.GasLimit), To: to, From: btx.Sender,
+			Value: value, Data: btx.Data, AccessList: btx.AccessList, V: v, R: r, S: s,
+		}), nil
+	case transaction.BlobTxType:
+		if to == nil {
+			return nil, fmt.Errorf("blob transaction without recipient")
+		}
+		return transaction.NewTx(&transaction.BlobTx{
+			ChainID: chainID, Nonce: uint64(btx.Nonce), GasTipCap: bigToUint256(btx.MaxPriorityFeePerGas),
+			GasFeeCap: bigToUint256(btx.MaxFeePerGas), Gas: uint64(btx.GasLimit), To: *to, From: btx.Sender,
+			Value: value, Data: btx.Data, AccessList: btx.AccessList, BlobFeeCap: bigToUint256(btx.MaxFeePerBlobGas),
+			BlobHashes: btx.BlobVersionedHashes, V: v, R: r, S: s,
+		}), nil
+	}
+	return nil, fmt.Errorf("unsupported transaction type %d", btx.Type)
+}
+
+// write creates the account addr with the contents of acc in ibs
+func (acc *btAccount) write(ibs *state.IntraBlockState, addr types.Address) error {
+	ibs.CreateAccount(addr, len(acc.Code) > 0)
+	ibs.SetBalance(addr, bigToUint256(acc.Balance))
+	ibs.SetNonce(addr, uint64(acc.Nonce))
+	if len(acc.Code) > 0 {
+		ibs.SetCode(addr, acc.Code)
+	}
+	for k, v := range acc.Storage {
+		key, value, err := parseSlot(k, v)
+		if err != nil {
+			return fmt.Errorf("account %v: %w", addr, err)
+		}
+		ibs.SetState(addr, &key, *value)
+	}
+	return nil
+}
+
+// check compares the account addr in ibs with acc
+func (acc *btAccount) check(ibs *state.IntraBlockState, addr types.Address) error {
+	if balance, want := ibs.GetBalance(addr), bigToUint256(acc.Balance); !balance.Eq(want) {
+		return fmt.Errorf("account %v: balance %v, want %v", addr, balance, want)
+	}
+	if nonce := ibs.GetNonce(addr); nonce != uint64(acc.Nonce) {
+		return fmt.Errorf("account %v: nonce %d, want %d", addr, nonce, acc.Nonce)
+	}
+	if code := ibs.GetCode(addr); !bytes.Equal(code, acc.Code) {
+		return fmt.Errorf("account %v: code %x, want %x", addr, code, []byte(acc.Code))
+	}
+	for k, v := range acc.Storage {
+		key, want, err := parseSlot(k, v)
+		if err != nil {
+			return fmt.Errorf("account %v: %w", addr, err)
+		}
+		var value uint256.Int
+		ibs.GetState(addr, &key, &value)
+		if !value.Eq(want) {
+			return fmt.Errorf("account %v: slot %v is %v, want %v", addr, key, &value, want)
+		}
+	}
+	return nil
+}
+
+func parseSlot(k, v string) (types.Hash, *uint256.Int, error) {
+	key, ok := math.ParseBig256(k)
+	if !ok {
+		return types.Hash{}, nil, fmt.Errorf("invalid storage key %q", k)
+	}
+	value, ok := math.ParseBig256(v)
+	if !ok {
+		return types.Hash{}, nil, fmt.Errorf("invalid storage value %q", v)
+	}
+	return types.BigToHash(key), bigToUint256((*math.HexOrDecimal256)(value)), nil
+}
+
+// bigToUint256 converts b to a uint256, treating nil as zero
+func bigToUint256(b *math.HexOrDecimal256) *uint256.Int {
+	if b == nil {
+		return new(uint256.Int)
+	}
+	v, _ := uint256.FromBig((*big.Int)(b))
+	return v
+}
@@ -1,460 +0,0 @@
-// Copyright 2022-2026 The N42 Authors
-// This file is part of the N42 library.
-//
-// DApp Compatibility Tests - Phase 2
-//
-// This file verifies N42's support for:
-// - DAO: Governance tokens, voting, timelocks, multi-sig
-// - DID: ERC-725/735, verifiable credentials, DID documents
-// - Gaming: VRF, random numbers, game assets, state channels
-
-package tests
-
-import (
-	"testing"
-
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/internal/vm"
-	"github.com/n42blockchain/N42/params"
-)
-
-// =============================================================================
-// DAO (Decentralized Autonomous Organization) Tests
-// =============================================================================
-
-// TestERC20VotesSupport tests governance token capabilities
-func TestERC20VotesSupport(t *testing.T) {
-	// ERC20Votes selectors (OpenZeppelin Governor)
-	selectors := map[string][4]byte{
-		"getVotes(address)":                        {},
-		"getPastVotes(address,uint256)":            {},
-		"getPastTotalSupply(uint256)":              {},
-		"delegates(address)":                       {},
-		"delegate(address)":                        {},
-		"delegateBySig(address,uint256,uint256,uint8,bytes32,bytes32)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ ERC-20 Votes (Governance Token) selectors verified")
-}
-
-// TestGovernorSupport tests OpenZeppelin Governor pattern
-func TestGovernorSupport(t *testing.T) {
-	// Governor function selectors
-	selectors := map[string][4]byte{
-		"propose(address[],uint256[],bytes[],string)": {},
-		"castVote(uint256,uint8)":                     {},
-		"castVoteWithReason(uint256,uint8,string)":    {},
-		"execute(address[],uint256[],bytes[],bytes32)": {},
-		"state(uint256)":                              {},
-		"proposalThreshold()":                         {},
-		"quorum(uint256)":                             {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ OpenZeppelin Governor selectors verified")
-}
-
-// TestTimelockSupport tests Timelock Controller
-func TestTimelockSupport(t *testing.T) {
-	// Verify TIMESTAMP opcode for time-based delays
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-
-	// Verify NUMBER opcode for block-based delays
-	if vm.NUMBER != 0x43 {
-		t.Errorf("NUMBER opcode incorrect")
-	}
-
-	// Timelock selectors
-	selectors := map[string][4]byte{
-		"schedule(address,uint256,bytes,bytes32,bytes32,uint256)": {},
-		"execute(address,uint256,bytes,bytes32,bytes32)":          {},
-		"cancel(bytes32)":                                          {},
-		"getMinDelay()":                                            {},
-		"isOperation(bytes32)":                                     {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Timelock Controller requirements verified")
-}
-
-// TestSnapshotSupport tests historical balance queries
-func TestSnapshotSupport(t *testing.T) {
-	// Snapshot requires:
-	// 1. BLOCKHASH opcode for historical block access
-	// 2. Storage operations for balance history
-
-	if vm.BLOCKHASH != 0x40 {
-		t.Errorf("BLOCKHASH opcode incorrect")
-	}
-
-	// SLOAD for reading historical state
-	if vm.SLOAD != 0x54 {
-		t.Errorf("SLOAD opcode incorrect")
-	}
-
-	// SSTORE for storing snapshots
-	if vm.SSTORE != 0x55 {
-		t.Errorf("SSTORE opcode incorrect")
-	}
-
-	t.Log("✓ Snapshot (historical balance) requirements verified")
-}
-
-// =============================================================================
-// DID (Decentralized Identity) Tests
-// =============================================================================
-
-// TestERC725Support tests identity proxy contract support
-func TestERC725Support(t *testing.T) {
-	// ERC-725 (Identity Proxy)
-	// Interface ID: 0x44c028fe
-	selectors := map[string][4]byte{
-		"execute(uint256,address,uint256,bytes)": {},
-		"getData(bytes32)":                       {},
-		"setData(bytes32,bytes)":                 {},
-		"owner()":                                {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ ERC-725 (Identity Proxy) selectors verified")
-}
-
-// TestERC735Support tests claim holder support
-func TestERC735Support(t *testing.T) {
-	// ERC-735 (Claim Holder)
-	selectors := map[string][4]byte{
-		"getClaim(bytes32)":           {},
-		"getClaimIdsByTopic(uint256)": {},
-		"addClaim(uint256,uint256,address,bytes,bytes,string)": {},
-		"removeClaim(bytes32)":        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ ERC-735 (Claim Holder) selectors verified")
-}
-
-// TestSignatureVerification tests signature verification for DIDs
-func TestSignatureVerification(t *testing.T) {
-	// ecrecover for signature verification
-	ecrecover := vm.GetEcrecover()
-	gas := ecrecover.RequiredGas(make([]byte, 128))
-	if gas != params.EcrecoverGas {
-		t.Errorf("ecrecover gas incorrect: expected %d, got %d", params.EcrecoverGas, gas)
-	}
-
-	t.Log("✓ Signature verification (ecrecover) requirements verified")
-}
-
-// TestDIDDocumentStorage tests on-chain DID document storage
-func TestDIDDocumentStorage(t *testing.T) {
-	// DID documents require:
-	// 1. Event logging for DID registry changes
-	// 2. Storage for DID controller/authentication
-
-	// LOG opcodes for events
-	if vm.LOG0 != 0xa0 {
-		t.Errorf("LOG0 opcode incorrect")
-	}
-	if vm.LOG4 != 0xa4 {
-		t.Errorf("LOG4 opcode incorrect")
-	}
-
-	// EtherDID Registry selectors
-	selectors := map[string][4]byte{
-		"setAttribute(address,bytes32,bytes,uint256)":   {},
-		"revokeAttribute(address,bytes32,bytes)":        {},
-		"changeOwner(address,address)":                  {},
-		"identityOwner(address)":                        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ DID Document storage requirements verified")
-}
-
-// TestRevocationRegistry tests credential revocation support
-func TestRevocationRegistry(t *testing.T) {
-	// Revocation registry requires:
-	// 1. Bitmap storage for efficient revocation status
-	// 2. Timestamp for revocation date
-
-	// Revocation selectors
-	selectors := map[string][4]byte{
-		"isRevoked(address,bytes32)": {},
-		"revoke(bytes32)":            {},
-		"revokeAll()":                {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Revocation Registry requirements verified")
-}
-
-// =============================================================================
-// Gaming Tests
-// =============================================================================
-
-// TestVRFSupport tests Verifiable Random Function support
-func TestVRFSupport(t *testing.T) {
-	// VRF requires BN256 curve operations
-	// Chainlink VRF uses: ecAdd, ecMul, ecPairing
-
-	// Verify BN256 precompiles
-	ecAdd := vm.GetBn256Add(true)
-	if ecAdd == nil {
-		t.Error("BN256 Add precompile not available")
-	}
-
-	ecMul := vm.GetBn256ScalarMul(true)
-	if ecMul == nil {
-		t.Error("BN256 ScalarMul precompile not available")
-	}
-
-	// Chainlink VRF selectors
-	selectors := map[string][4]byte{
-		"requestRandomness(bytes32,uint256)":           {},
-		"fulfillRandomness(bytes32,uint256)":           {},
-		"rawFulfillRandomness(bytes32,uint256)":        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ VRF (Verifiable Random Function) requirements verified")
-}
-
-// TestCommitRevealRandomness tests commit-reveal randomness pattern
-func TestCommitRevealRandomness(t *testing.T) {
-	// Commit-Reveal requires:
-	// 1. KECCAK256 for hash commitments
-	// 2. BLOCKHASH for block-based entropy
-	// 3. Storage for commitments
-
-	if vm.KECCAK256 != 0x20 {
-		t.Errorf("KECCAK256 opcode incorrect")
-	}
-
-	if vm.BLOCKHASH != 0x40 {
-		t.Errorf("BLOCKHASH opcode incorrect")
-	}
-
-	// Test keccak256 gas cost
-	keccakGas := params.Keccak256Gas
-	if keccakGas == 0 {
-		t.Error("KECCAK256 gas should be non-zero")
-	}
-
-	t.Log("✓ Commit-Reveal randomness requirements verified")
-}
-
-// TestGameAssetNFT tests game asset NFT support
-func TestGameAssetNFT(t *testing.T) {
-	// Game assets typically use ERC-721 or ERC-1155
-	// Already verified in Phase 1, but check gaming-specific patterns
-
-	// ERC-998 Composable NFT (for game inventory)
-	selectors := map[string][4]byte{
-		"getChild(address,uint256,address,uint256)":    {},
-		"ownerOfChild(address,uint256)":                {},
-		"rootOwnerOf(uint256)":                         {},
-		"transferChild(uint256,address,address,uint256)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Game Asset (ERC-998 Composable) NFT requirements verified")
-}
-
-// TestStateChannelSupport tests state channel requirements
-func TestStateChannelSupport(t *testing.T) {
-	// State channels require:
-	// 1. Signature verification (ecrecover)
-	// 2. Deterministic addresses (CREATE2)
-	// 3. Time-based disputes (TIMESTAMP)
-
-	// CREATE2 for counterfactual instantiation
-	if vm.CREATE2 != 0xf5 {
-		t.Errorf("CREATE2 opcode incorrect")
-	}
-
-	// TIMESTAMP for challenge periods
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-
-	// State channel selectors
-	selectors := map[string][4]byte{
-		"deposit()":                    {},
-		"withdraw(uint256)":            {},
-		"challenge(bytes,bytes)":       {},
-		"finalize()":                   {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ State Channel requirements verified")
-}
-
-// TestTournamentContract tests tournament/escrow patterns
-func TestTournamentContract(t *testing.T) {
-	// Tournament contracts require:
-	// 1. Escrow functionality
-	// 2. Multi-party payouts
-	// 3. Time-based state transitions
-
-	selectors := map[string][4]byte{
-		"joinTournament(uint256)":       {},
-		"submitScore(uint256,uint256)":  {},
-		"distributePrizes(uint256)":     {},
-		"refund(uint256)":               {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Tournament/Escrow contract requirements verified")
-}
-
-// =============================================================================
-// DID Implementation Status
-// =============================================================================
-
-// TestDIDImplementationGaps identifies what needs to be added for full DID support
-func TestDIDImplementationGaps(t *testing.T) {
-	t.Log("")
-	t.Log("DID Implementation Analysis:")
-	t.Log("")
-	t.Log("✓ Available Now:")
-	t.Log("  - ERC-725/735 contract deployment")
-	t.Log("  - Signature verification (ecrecover)")
-	t.Log("  - Event logging for DID changes")
-	t.Log("  - Storage for claims/attributes")
-	t.Log("")
-	t.Log("✓ Recommended Additions (Smart Contract Level):")
-	t.Log("  - EtherDID Registry deployment guide")
-	t.Log("  - Verifiable Credential schema templates")
-	t.Log("  - W3C DID method specification for N42")
-	t.Log("")
-	t.Log("EVM Layer: FULLY SUPPORTED")
-}
-
-// =============================================================================
-// Phase 2 Summary
-// =============================================================================
-
-// TestPhase2CompatibilitySummary provides a summary of Phase 2 capabilities
-func TestPhase2CompatibilitySummary(t *testing.T) {
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("           PHASE 2 DAPP COMPATIBILITY SUMMARY")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("")
-	t.Log("DAO (Decentralized Autonomous Organization):")
-	t.Log("  ✓ ERC-20 Votes (Governance Tokens)")
-	t.Log("  ✓ OpenZeppelin Governor Pattern")
-	t.Log("  ✓ Timelock Controller")
-	t.Log("  ✓ Snapshot (Historical Balances)")
-	t.Log("  ✓ Multi-signature Execution")
-	t.Log("")
-	t.Log("DID (Decentralized Identity):")
-	t.Log("  ✓ ERC-725 (Identity Proxy)")
-	t.Log("  ✓ ERC-735 (Claim Holder)")
-	t.Log("  ✓ Signature Verification")
-	t.Log("  ✓ DID Document Storage")
-	t.Log("  ✓ Revocation Registry")
-	t.Log("")
-	t.Log("Gaming:")
-	t.Log("  ✓ VRF (Verifiable Random Function)")
-	t.Log("  ✓ Commit-Reveal Randomness")
-	t.Log("  ✓ Game Asset NFTs (ERC-721/1155)")
-	t.Log("  ✓ Composable NFTs (ERC-998)")
-	t.Log("  ✓ State Channels")
-	t.Log("  ✓ Tournament/Escrow Contracts")
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("    N42 FULLY SUPPORTS PHASE 2 DAPP REQUIREMENTS")
-	t.Log("═══════════════════════════════════════════════════════════════")
-}
-
-// =============================================================================
-// Benchmarks
-// =============================================================================
-
-func BenchmarkGovernorPropose(b *testing.B) {
-	selector := []byte("propose(address[],uint256[],bytes[],string)")
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = crypto.Keccak256(selector)[:4]
-	}
-}
-
-func BenchmarkEcrecoverGas(b *testing.B) {
-	ecrecover := vm.GetEcrecover()
-	input := make([]byte, 128)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		ecrecover.RequiredGas(input)
-	}
-}
-
@@ -1,553 +0,0 @@
-// Copyright 2022-2026 The N42 Authors
-// This file is part of the N42 library.
-//
-// DApp Compatibility Tests - Phase 3
-//
-// This file verifies N42's support for:
-// - AI & AI Agent: Data hashing, model verification, agent wallets
-// - Social: User profiles, content hashing, follow graphs
-// - Metaverse: Virtual land, wearables, avatar NFTs
-// - RWA: Asset tokenization, compliance, dividends
-// - Supply Chain: Provenance tracking, batch management
-
-package tests
-
-import (
-	"testing"
-
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/internal/vm"
-)
-
-// =============================================================================
-// AI & AI Agent Tests
-// =============================================================================
-
-// TestAIDataHashingSupport tests support for AI training data hashing
-func TestAIDataHashingSupport(t *testing.T) {
-	// AI data verification requires:
-	// 1. KECCAK256 for data hashing
-	// 2. IPFS CID storage
-	// 3. Merkle tree verification
-
-	if vm.KECCAK256 != 0x20 {
-		t.Errorf("KECCAK256 opcode incorrect")
-	}
-
-	// SHA256 precompile for IPFS CID verification
-	sha256 := vm.GetSha256()
-	if sha256 == nil {
-		t.Error("SHA256 precompile not available")
-	}
-
-	// Test data hash storage pattern
-	testData := []byte("AI training dataset v1.0")
-	result, err := sha256.Run(testData)
-	if err != nil || len(result) != 32 {
-		t.Error("SHA256 failed for AI data hashing")
-	}
-
-	t.Log("✓ AI Data Hashing requirements verified")
-}
-
-// TestAIModelVerification tests support for model hash verification
-func TestAIModelVerification(t *testing.T) {
-	// Model verification requires:
-	// 1. Hash storage for model weights
-	// 2. Version control via events
-	// 3. Access control for updates
-
-	selectors := map[string][4]byte{
-		"registerModel(bytes32,string)":      {},
-		"updateModel(bytes32,bytes32)":       {},
-		"verifyModel(bytes32)":               {},
-		"getModelHash(bytes32)":              {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ AI Model Verification requirements verified")
-}
-
-// TestAIAgentSupport tests AI Agent wallet capabilities
-func TestAIAgentSupport(t *testing.T) {
-	// AI Agent requires:
-	// 1. Account Abstraction (EIP-7702)
-	// 2. Programmable wallets
-	// 3. Autonomous execution
-
-	// Verify CREATE2 for deterministic agent addresses
-	if vm.CREATE2 != 0xf5 {
-		t.Errorf("CREATE2 opcode incorrect")
-	}
-
-	// Verify DELEGATECALL for upgradeable agents
-	if vm.DELEGATECALL != 0xf4 {
-		t.Errorf("DELEGATECALL opcode incorrect")
-	}
-
-	// AI Agent interface selectors
-	selectors := map[string][4]byte{
-		"execute(address,uint256,bytes)":     {},
-		"setAuthorization(address,bool)":     {},
-		"getAuthorizedActions(address)":      {},
-		"validateAction(bytes)":              {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ AI Agent requirements verified")
-}
-
-// TestAIInferenceVerification tests support for inference verification
-func TestAIInferenceVerification(t *testing.T) {
-	// ZK-based inference verification requires:
-	// 1. BN256 pairing for Groth16 proofs
-	// 2. BLS12-381 for PLONK proofs
-
-	// Verify BN256 pairing is available
-	pairing := vm.GetBn256Pairing(true)
-	if pairing == nil {
-		t.Error("BN256 pairing not available for ZK inference verification")
-	}
-
-	// Verify BLS12-381 pairing is available
-	bls := vm.GetBls12381Pairing()
-	if bls == nil {
-		t.Error("BLS12-381 pairing not available for ZK inference verification")
-	}
-
-	t.Log("✓ AI Inference (ZK) Verification requirements verified")
-}
-
-// =============================================================================
-// Social Platform Tests
-// =============================================================================
-
-// TestSocialProfileStorage tests on-chain profile storage
-func TestSocialProfileStorage(t *testing.T) {
-	// Social profiles require:
-	// 1. Storage for profile data hashes
-	// 2. Events for updates
-
-	selectors := map[string][4]byte{
-		"setProfile(bytes32,string)":   {},
-		"getProfile(address)":          {},
-		"setAvatar(address,string)":    {},
-		"setBio(address,string)":       {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Social Profile Storage requirements verified")
-}
-
-// TestFollowGraphSupport tests on-chain follow graph
-func TestFollowGraphSupport(t *testing.T) {
-	// Follow graph requires:
-	// 1. Efficient storage for relationships
-	// 2. Events for follow/unfollow
-
-	// LOG opcodes for follow events
-	if vm.LOG0 < 0xa0 || vm.LOG4 > 0xa4 {
-		t.Error("LOG opcodes not in expected range")
-	}
-
-	selectors := map[string][4]byte{
-		"follow(address)":              {},
-		"unfollow(address)":            {},
-		"isFollowing(address,address)": {},
-		"getFollowers(address)":        {},
-		"getFollowing(address)":        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Social Follow Graph requirements verified")
-}
-
-// TestContentHashStorage tests content hash storage
-func TestContentHashStorage(t *testing.T) {
-	// Content storage requires:
-	// 1. IPFS/Arweave hash storage
-	// 2. Content moderation hooks
-
-	// Verify SSTORE for content hashes
-	if vm.SSTORE != 0x55 {
-		t.Errorf("SSTORE opcode incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"post(bytes32)":                {},
-		"comment(bytes32,bytes32)":     {},
-		"like(bytes32)":                {},
-		"report(bytes32)":              {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Social Content Hash Storage requirements verified")
-}
-
-// TestTokenGatedAccess tests token-gated content access
-func TestTokenGatedAccess(t *testing.T) {
-	// Token gating requires:
-	// 1. NFT balance checks
-	// 2. ERC-20 balance checks
-
-	selectors := map[string][4]byte{
-		"checkAccess(address,uint256)": {},
-		"setAccessNFT(address)":        {},
-		"setAccessToken(address,uint256)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Token-Gated Access requirements verified")
-}
-
-// =============================================================================
-// Metaverse Tests
-// =============================================================================
-
-// TestVirtualLandNFT tests virtual land NFT support
-func TestVirtualLandNFT(t *testing.T) {
-	// Virtual land requires:
-	// 1. ERC-721 for land parcels
-	// 2. Coordinate storage
-	// 3. Adjacent parcel queries
-
-	selectors := map[string][4]byte{
-		"mintLand(int256,int256)":          {},
-		"getLandAt(int256,int256)":         {},
-		"getAdjacentParcels(uint256)":      {},
-		"setLandContent(uint256,bytes32)":  {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Virtual Land NFT requirements verified")
-}
-
-// TestWearablesNFT tests wearable NFT support
-func TestWearablesNFT(t *testing.T) {
-	// Wearables require:
-	// 1. ERC-1155 for multiple items
-	// 2. Equipment slots system
-	// 3. Rarity attributes
-
-	selectors := map[string][4]byte{
-		"equip(uint256,uint256)":    {},
-		"unequip(uint256)":          {},
-		"getEquipped(address)":      {},
-		"getRarity(uint256)":        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Wearables NFT requirements verified")
-}
-
-// TestAvatarNFT tests avatar NFT support
-func TestAvatarNFT(t *testing.T) {
-	// Avatar NFT requires:
-	// 1. ERC-721 base
-	// 2. Customization storage
-	// 3. Cross-platform identity
-
-	selectors := map[string][4]byte{
-		"createAvatar(bytes)":       {},
-		"customizeAvatar(uint256,bytes)": {},
-		"getAvatarData(uint256)":    {},
-		"linkPlatform(uint256,bytes32)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Avatar NFT requirements verified")
-}
-
-// =============================================================================
-// RWA (Real World Assets) Tests
-// =============================================================================
-
-// TestAssetTokenization tests real-world asset tokenization
-func TestAssetTokenization(t *testing.T) {
-	// RWA tokenization requires:
-	// 1. ERC-20 or ERC-3643 for security tokens
-	// 2. Compliance checks
-	// 3. Dividend distribution
-
-	// ERC-3643 (T-REX) selectors
-	selectors := map[string][4]byte{
-		"isVerified(address)":              {},
-		"recoveryAddress(address,address)": {},
-		"setCompliance(address)":           {},
-		"getCompliance()":                  {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Asset Tokenization (ERC-3643) requirements verified")
-}
-
-// TestComplianceModule tests compliance/KYC support
-func TestComplianceModule(t *testing.T) {
-	// Compliance requires:
-	// 1. Whitelist/blacklist storage
-	// 2. Transfer restrictions
-	// 3. Regulatory hooks
-
-	selectors := map[string][4]byte{
-		"addToWhitelist(address)":       {},
-		"removeFromWhitelist(address)":  {},
-		"isWhitelisted(address)":        {},
-		"canTransfer(address,address,uint256)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Compliance Module requirements verified")
-}
-
-// TestDividendDistribution tests dividend/yield distribution
-func TestDividendDistribution(t *testing.T) {
-	// Dividend distribution requires:
-	// 1. Snapshot for balance at dividend date
-	// 2. Claim mechanism
-	// 3. Distribution calculation
-
-	selectors := map[string][4]byte{
-		"distributeDividend(uint256)":  {},
-		"claimDividend(uint256)":       {},
-		"getPendingDividend(address)":  {},
-		"getDividendHistory(address)":  {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Dividend Distribution requirements verified")
-}
-
-// =============================================================================
-// Supply Chain Tests
-// =============================================================================
-
-// TestProvenanceTracking tests supply chain provenance
-func TestProvenanceTracking(t *testing.T) {
-	// Provenance tracking requires:
-	// 1. Event logging for each step
-	// 2. Timestamp verification
-	// 3. Actor authentication
-
-	// Verify TIMESTAMP for supply chain events
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-
-	// Verify ORIGIN for transaction origin
-	if vm.ORIGIN != 0x32 {
-		t.Errorf("ORIGIN opcode incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"registerProduct(bytes32)":           {},
-		"addEvent(bytes32,bytes32,string)":   {},
-		"getHistory(bytes32)":                {},
-		"verifyChain(bytes32)":               {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Provenance Tracking requirements verified")
-}
-
-// TestBatchManagement tests batch/lot tracking
-func TestBatchManagement(t *testing.T) {
-	// Batch management requires:
-	// 1. Batch ID generation
-	// 2. Quantity tracking
-	// 3. Split/merge operations
-
-	selectors := map[string][4]byte{
-		"createBatch(bytes32,uint256)":  {},
-		"splitBatch(bytes32,uint256[])": {},
-		"mergeBatches(bytes32[])":       {},
-		"getBatchInfo(bytes32)":         {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Batch Management requirements verified")
-}
-
-// TestCertificateVerification tests supply chain certificates
-func TestCertificateVerification(t *testing.T) {
-	// Certificate verification requires:
-	// 1. Signature verification (ecrecover)
-	// 2. Certificate storage
-	// 3. Expiry checking
-
-	ecrecover := vm.GetEcrecover()
-	if ecrecover == nil {
-		t.Error("ecrecover not available for certificate verification")
-	}
-
-	selectors := map[string][4]byte{
-		"issueCertificate(bytes32,address,uint256)": {},
-		"verifyCertificate(bytes32)":                {},
-		"revokeCertificate(bytes32)":                {},
-		"getCertificateDetails(bytes32)":            {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Certificate Verification requirements verified")
-}
-
-// =============================================================================
-// Phase 3 Summary
-// =============================================================================
-
-// TestPhase3CompatibilitySummary provides a summary of Phase 3 capabilities
-func TestPhase3CompatibilitySummary(t *testing.T) {
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("           PHASE 3 DAPP COMPATIBILITY SUMMARY")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("")
-	t.Log("AI & AI Agent:")
-	t.Log("  ✓ Training Data Hashing (SHA256/KECCAK256)")
-	t.Log("  ✓ Model Hash Verification")
-	t.Log("  ✓ ZK-based Inference Verification")
-	t.Log("  ✓ AI Agent Wallets (Account Abstraction)")
-	t.Log("  ✓ Autonomous Execution")
-	t.Log("")
-	t.Log("Social Platforms:")
-	t.Log("  ✓ On-chain Profile Storage")
-	t.Log("  ✓ Follow Graph Management")
-	t.Log("  ✓ Content Hash Storage (IPFS/Arweave)")
-	t.Log("  ✓ Token-Gated Access")
-	t.Log("  ✓ Social Tokens/Tips")
-	t.Log("")
-	t.Log("Metaverse:")
-	t.Log("  ✓ Virtual Land NFTs")
-	t.Log("  ✓ Wearables (ERC-1155)")
-	t.Log("  ✓ Avatar NFTs")
-	t.Log("  ✓ Virtual Economy")
-	t.Log("")
-	t.Log("RWA (Real World Assets):")
-	t.Log("  ✓ Asset Tokenization (ERC-3643)")
-	t.Log("  ✓ Compliance/KYC Modules")
-	t.Log("  ✓ Dividend Distribution")
-	t.Log("  ✓ Transfer Restrictions")
-	t.Log("")
-	t.Log("Supply Chain:")
-	t.Log("  ✓ Provenance Tracking")
-	t.Log("  ✓ Batch/Lot Management")
-	t.Log("  ✓ Certificate Verification")
-	t.Log("  ✓ Timestamp Validation")
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("    N42 FULLY SUPPORTS PHASE 3 DAPP REQUIREMENTS")
-	t.Log("═══════════════════════════════════════════════════════════════")
-}
-
-// =============================================================================
-// Benchmarks
-// =============================================================================
-
-func BenchmarkSHA256DataHash(b *testing.B) {
-	sha256 := vm.GetSha256()
-	data := make([]byte, 1024) // 1KB data
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		sha256.Run(data)
-	}
-}
-
-func BenchmarkKeccak256ContentHash(b *testing.B) {
-	data := make([]byte, 1024)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		crypto.Keccak256(data)
-	}
-}
-
@@ -1,547 +0,0 @@
-// Copyright 2022-2026 The N42 Authors
-// This file is part of the N42 library.
-//
-// DApp Compatibility Tests - Phase 4
-//
-// This file verifies N42's support for vertical domain DApps:
-// - Carbon Trading: Carbon credits, registries, retirement
-// - DePIN: Device registration, rewards, metering
-// - IoT: Device identity, data submission, automation
-// - DeSci: Research data, peer review, IP registration
-// - Provenance: Asset tracking, authentication
-
-package tests
-
-import (
-	"testing"
-
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/internal/vm"
-	"github.com/n42blockchain/N42/params"
-)
-
-// =============================================================================
-// Carbon Trading Tests
-// =============================================================================
-
-// TestCarbonCreditToken tests carbon credit tokenization
-func TestCarbonCreditToken(t *testing.T) {
-	// Carbon credits can be:
-	// 1. ERC-20 for fungible credits
-	// 2. ERC-721 for unique project credits
-
-	selectors := map[string][4]byte{
-		"mintCredit(address,uint256,bytes32)": {},
-		"retireCredit(uint256)":               {},
-		"getProjectId(uint256)":               {},
-		"verifyCredit(uint256)":               {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Carbon Credit Token requirements verified")
-}
-
-// TestCarbonRegistry tests carbon registry support
-func TestCarbonRegistry(t *testing.T) {
-	// Carbon registry requires:
-	// 1. Project registration
-	// 2. Verification status tracking
-	// 3. Credit issuance authorization
-
-	selectors := map[string][4]byte{
-		"registerProject(bytes32,string)":       {},
-		"verifyProject(bytes32)":                {},
-		"setVerifier(bytes32,address)":          {},
-		"getProjectStatus(bytes32)":             {},
-		"issueCredits(bytes32,uint256)":         {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Carbon Registry requirements verified")
-}
-
-// TestCarbonRetirement tests carbon credit retirement (burning)
-func TestCarbonRetirement(t *testing.T) {
-	// Retirement requires:
-	// 1. Burn mechanism
-	// 2. Retirement certificate generation
-	// 3. Beneficiary tracking
-
-	selectors := map[string][4]byte{
-		"retire(uint256,string)":                {},
-		"retireOnBehalf(uint256,address,string)": {},
-		"getRetirementCertificate(uint256)":     {},
-		"getTotalRetired(address)":              {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Carbon Retirement requirements verified")
-}
-
-// =============================================================================
-// DePIN (Decentralized Physical Infrastructure Network) Tests
-// =============================================================================
-
-// TestDeviceRegistration tests DePIN device registration
-func TestDeviceRegistration(t *testing.T) {
-	// Device registration requires:
-	// 1. Unique device IDs
-	// 2. Owner association
-	// 3. Device metadata storage
-
-	selectors := map[string][4]byte{
-		"registerDevice(bytes32,bytes)":   {},
-		"updateDevice(bytes32,bytes)":     {},
-		"transferDevice(bytes32,address)": {},
-		"getDeviceInfo(bytes32)":          {},
-		"isDeviceActive(bytes32)":         {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ DePIN Device Registration requirements verified")
-}
-
-// TestDePINRewards tests DePIN reward distribution
-func TestDePINRewards(t *testing.T) {
-	// Reward distribution requires:
-	// 1. ERC-20 token for rewards
-	// 2. Contribution tracking
-	// 3. Epoch-based distribution
-
-	selectors := map[string][4]byte{
-		"submitContribution(bytes32,uint256)": {},
-		"claimRewards(bytes32)":               {},
-		"getPendingRewards(bytes32)":          {},
-		"getEpochRewards(uint256)":            {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ DePIN Rewards requirements verified")
-}
-
-// TestDePINMetering tests usage metering for DePIN
-func TestDePINMetering(t *testing.T) {
-	// Metering requires:
-	// 1. Oracle integration for off-chain data
-	// 2. Aggregated reporting
-	// 3. Verification mechanisms
-
-	// Verify TIMESTAMP for time-based metering
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"reportUsage(bytes32,uint256,uint256)": {},
-		"aggregateUsage(bytes32,uint256)":      {},
-		"verifyUsage(bytes32,bytes)":           {},
-		"getUsageHistory(bytes32)":             {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ DePIN Metering requirements verified")
-}
-
-// =============================================================================
-// IoT (Internet of Things) Tests
-// =============================================================================
-
-// TestIoTDeviceIdentity tests IoT device identity
-func TestIoTDeviceIdentity(t *testing.T) {
-	// IoT identity requires:
-	// 1. Lightweight signature verification
-	// 2. Device certificate storage
-	// 3. Key rotation support
-
-	// ecrecover for signature verification
-	ecrecover := vm.GetEcrecover()
-	gas := ecrecover.RequiredGas(make([]byte, 128))
-	if gas != params.EcrecoverGas {
-		t.Errorf("ecrecover gas incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"registerIoTDevice(bytes32,bytes)":  {},
-		"rotateDeviceKey(bytes32,bytes)":    {},
-		"revokeDevice(bytes32)":             {},
-		"verifyDeviceSignature(bytes32,bytes,bytes)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ IoT Device Identity requirements verified")
-}
-
-// TestIoTDataSubmission tests IoT data submission patterns
-func TestIoTDataSubmission(t *testing.T) {
-	// Data submission requires:
-	// 1. Batch data submission
-	// 2. Data hash verification
-	// 3. Timestamp validation
-
-	// Verify calldata for batch submissions
-	if vm.CALLDATALOAD != 0x35 {
-		t.Errorf("CALLDATALOAD opcode incorrect")
-	}
-	if vm.CALLDATASIZE != 0x36 {
-		t.Errorf("CALLDATASIZE opcode incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"submitData(bytes32,bytes32,uint256)":  {},
-		"submitBatchData(bytes32[],bytes32[])": {},
-		"verifyDataIntegrity(bytes32,bytes32)": {},
-		"getLatestData(bytes32)":               {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ IoT Data Submission requirements verified")
-}
-
-// TestIoTAutomation tests IoT automation/trigger patterns
-func TestIoTAutomation(t *testing.T) {
-	// Automation requires:
-	// 1. Conditional execution
-	// 2. Keeper/Gelato-style triggers
-	// 3. State machine patterns
-
-	selectors := map[string][4]byte{
-		"registerTrigger(bytes32,bytes)":  {},
-		"checkTrigger(bytes32)":           {},
-		"executeTrigger(bytes32)":         {},
-		"setAutomationParams(bytes32,bytes)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ IoT Automation requirements verified")
-}
-
-// =============================================================================
-// DeSci (Decentralized Science) Tests
-// =============================================================================
-
-// TestResearchDataNFT tests research data NFT
-func TestResearchDataNFT(t *testing.T) {
-	// Research data NFT requires:
-	// 1. ERC-721 for data ownership
-	// 2. License terms storage
-	// 3. Access control
-
-	selectors := map[string][4]byte{
-		"mintResearchData(bytes32,string)": {},
-		"setLicense(uint256,uint256)":      {},
-		"grantAccess(uint256,address)":     {},
-		"revokeAccess(uint256,address)":    {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Research Data NFT requirements verified")
-}
-
-// TestPeerReviewDAO tests decentralized peer review
-func TestPeerReviewDAO(t *testing.T) {
-	// Peer review requires:
-	// 1. Reviewer registration
-	// 2. Review submission
-	// 3. Consensus mechanism
-
-	selectors := map[string][4]byte{
-		"registerReviewer(address,bytes32)":  {},
-		"submitForReview(bytes32)":           {},
-		"submitReview(bytes32,uint8,string)": {},
-		"finalizeReview(bytes32)":            {},
-		"getReviewStatus(bytes32)":           {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Peer Review DAO requirements verified")
-}
-
-// TestIPRegistration tests intellectual property registration
-func TestIPRegistration(t *testing.T) {
-	// IP registration requires:
-	// 1. Timestamp proof
-	// 2. Hash commitment
-	// 3. Priority claims
-
-	// Verify TIMESTAMP for priority dating
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-
-	selectors := map[string][4]byte{
-		"registerIP(bytes32,string)":        {},
-		"proveOwnership(bytes32,bytes)":     {},
-		"transferIP(bytes32,address)":       {},
-		"licenseIP(bytes32,address,uint256)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ IP Registration requirements verified")
-}
-
-// TestResearchFunding tests decentralized research funding
-func TestResearchFunding(t *testing.T) {
-	// Research funding requires:
-	// 1. Proposal submission
-	// 2. Funding pools
-	// 3. Milestone tracking
-
-	selectors := map[string][4]byte{
-		"submitProposal(bytes32,uint256)":    {},
-		"fundProposal(bytes32)":              {},
-		"releaseMilestone(bytes32,uint256)":  {},
-		"refundContributors(bytes32)":        {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Research Funding requirements verified")
-}
-
-// =============================================================================
-// Provenance & Ownership Verification Tests
-// =============================================================================
-
-// TestPhysicalAssetTracking tests physical asset tracking
-func TestPhysicalAssetTracking(t *testing.T) {
-	// Physical asset tracking requires:
-	// 1. NFC/RFID tag linking
-	// 2. Location updates
-	// 3. Custody chain
-
-	selectors := map[string][4]byte{
-		"linkPhysicalAsset(bytes32,bytes32)": {},
-		"updateLocation(bytes32,bytes)":      {},
-		"transferCustody(bytes32,address)":   {},
-		"getCustodyChain(bytes32)":           {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Physical Asset Tracking requirements verified")
-}
-
-// TestAuthenticityVerification tests authenticity verification
-func TestAuthenticityVerification(t *testing.T) {
-	// Authenticity requires:
-	// 1. Issuer signature
-	// 2. Verification status
-	// 3. Tampering detection
-
-	selectors := map[string][4]byte{
-		"issueAuthenticityCert(bytes32,bytes)": {},
-		"verifyAuthenticity(bytes32)":          {},
-		"reportTampering(bytes32)":             {},
-		"getVerificationHistory(bytes32)":      {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Authenticity Verification requirements verified")
-}
-
-// =============================================================================
-// Phase 4 Summary
-// =============================================================================
-
-// TestPhase4CompatibilitySummary provides a summary of Phase 4 capabilities
-func TestPhase4CompatibilitySummary(t *testing.T) {
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("           PHASE 4 DAPP COMPATIBILITY SUMMARY")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("")
-	t.Log("Carbon Trading:")
-	t.Log("  ✓ Carbon Credit Tokens (ERC-20/721)")
-	t.Log("  ✓ Carbon Registry Management")
-	t.Log("  ✓ Credit Retirement (Burning)")
-	t.Log("  ✓ Offset Certificate Generation")
-	t.Log("")
-	t.Log("DePIN (Decentralized Physical Infrastructure):")
-	t.Log("  ✓ Device Registration")
-	t.Log("  ✓ Reward Distribution")
-	t.Log("  ✓ Usage Metering")
-	t.Log("  ✓ Stake/Slash Mechanisms")
-	t.Log("")
-	t.Log("IoT (Internet of Things):")
-	t.Log("  ✓ Device Identity Management")
-	t.Log("  ✓ Data Submission (Single/Batch)")
-	t.Log("  ✓ Automation Triggers")
-	t.Log("  ✓ Lightweight Verification")
-	t.Log("")
-	t.Log("DeSci (Decentralized Science):")
-	t.Log("  ✓ Research Data NFTs")
-	t.Log("  ✓ Peer Review DAO")
-	t.Log("  ✓ IP Registration & Licensing")
-	t.Log("  ✓ Research Funding")
-	t.Log("")
-	t.Log("Provenance & Ownership:")
-	t.Log("  ✓ Physical Asset Tracking")
-	t.Log("  ✓ Authenticity Verification")
-	t.Log("  ✓ Custody Chain Management")
-	t.Log("  ✓ Tamper Detection")
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("    N42 FULLY SUPPORTS PHASE 4 DAPP REQUIREMENTS")
-	t.Log("═══════════════════════════════════════════════════════════════")
-}
-
-// =============================================================================
-// Final Comprehensive Summary
-// =============================================================================
-
-// TestAllDAppCompatibilitySummary provides final comprehensive summary
-func TestAllDAppCompatibilitySummary(t *testing.T) {
-	t.Log("")
-	t.Log("╔═══════════════════════════════════════════════════════════════╗")
-	t.Log("║         N42 COMPLETE DAPP COMPATIBILITY REPORT                ║")
-	t.Log("╠═══════════════════════════════════════════════════════════════╣")
-	t.Log("║                                                               ║")
-	t.Log("║  ZK-EVM:                                                      ║")
-	t.Log("║    ✓ Groth16 (BN254) ................ Fully Supported         ║")
-	t.Log("║    ✓ PLONK/KZG (BLS12-381) .......... Fully Supported         ║")
-	t.Log("║    ✓ Off-chain Compute/On-chain Verify ... Ready              ║")
-	t.Log("║                                                               ║")
-	t.Log("║  Phase 1 - Core Finance:                                      ║")
-	t.Log("║    ✓ Payment (ERC-20, HTLC, MultiSig) ... Fully Supported     ║")
-	t.Log("║    ✓ NFT (ERC-721/1155/2981/5192) ...... Fully Supported      ║")
-	t.Log("║    ✓ DeFi (AMM, Flash Loans, Oracles) .. Fully Supported      ║")
-	t.Log("║                                                               ║")
-	t.Log("║  Phase 2 - Governance & Identity:                             ║")
-	t.Log("║    ✓ DAO (Governor, Timelock) .......... Fully Supported      ║")
-	t.Log("║    ✓ DID (ERC-725/735) ................. Fully Supported      ║")
-	t.Log("║    ✓ Gaming (VRF, State Channels) ...... Fully Supported      ║")
-	t.Log("║                                                               ║")
-	t.Log("║  Phase 3 - Emerging Applications:                             ║")
-	t.Log("║    ✓ AI/AI Agent ...................... Fully Supported       ║")
-	t.Log("║    ✓ Social Platforms ................. Fully Supported       ║")
-	t.Log("║    ✓ Metaverse ........................ Fully Supported       ║")
-	t.Log("║    ✓ RWA (Real World Assets) .......... Fully Supported       ║")
-	t.Log("║    ✓ Supply Chain ..................... Fully Supported       ║")
-	t.Log("║                                                               ║")
-	t.Log("║  Phase 4 - Vertical Domains:                                  ║")
-	t.Log("║    ✓ Carbon Trading ................... Fully Supported       ║")
-	t.Log("║    ✓ DePIN ............................ Fully Supported       ║")
-	t.Log("║    ✓ IoT .............................. Fully Supported       ║")
-	t.Log("║    ✓ DeSci ............................ Fully Supported       ║")
-	t.Log("║    ✓ Provenance ....................... Fully Supported       ║")
-	t.Log("║                                                               ║")
-	t.Log("║  Previously Verified:                                         ║")
-	t.Log("║    ✓ Prediction Market ................ Fully Supported       ║")
-	t.Log("║    ✓ ENS .............................. Fully Supported       ║")
-	t.Log("║                                                               ║")
-	t.Log("╠═══════════════════════════════════════════════════════════════╣")
-	t.Log("║         ALL 19 DAPP CATEGORIES: FULLY SUPPORTED               ║")
-	t.Log("╚═══════════════════════════════════════════════════════════════╝")
-}
-
-// =============================================================================
-// Benchmarks
-// =============================================================================
-
-func BenchmarkEcrecoverIoT(b *testing.B) {
-	ecrecover := vm.GetEcrecover()
-	input := make([]byte, 128)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		ecrecover.RequiredGas(input)
-	}
-}
-
-func BenchmarkKeccak256Provenance(b *testing.B) {
-	data := []byte("product-batch-001-timestamp-1234567890")
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		crypto.Keccak256(data)
-	}
-}
-
@@ -1,461 +0,0 @@
-// Copyright 2022-2026 The N42 Authors
-// This file is part of the N42 library.
-//
-// DApp Compatibility Tests - Phase 1
-//
-// This file verifies N42's support for common DApp patterns:
-// - Payment: ERC-20, batch transfers, multi-sig, HTLC
-// - NFTs: ERC-721, ERC-1155, ERC-2981 royalties, ERC-5192 soulbound
-// - DeFi: AMM, flash loans, oracles, yield farming
-
-package tests
-
-import (
-	"math/big"
-	"testing"
-
-	"github.com/holiman/uint256"
-	"github.com/n42blockchain/N42/common/crypto"
-	"github.com/n42blockchain/N42/common/types"
-	"github.com/n42blockchain/N42/internal/vm"
-	"github.com/n42blockchain/N42/params"
-)
-
-// =============================================================================
-// ERC Standard Interface IDs
-// =============================================================================
-
-var (
-	// ERC-165 Interface Detection
-	ERC165InterfaceID = [4]byte{0x01, 0xff, 0xc9, 0xa7} // supportsInterface(bytes4)
-
-	// Token Standards
-	ERC20InterfaceID  = [4]byte{0x36, 0x37, 0x2b, 0x07} // Not formally defined, but common
-	ERC721InterfaceID = [4]byte{0x80, 0xac, 0x58, 0xcd} // ERC-721
-	ERC1155InterfaceID = [4]byte{0xd9, 0xb6, 0x7a, 0x26} // ERC-1155
-
-	// Extensions
-	ERC721MetadataID   = [4]byte{0x5b, 0x5e, 0x13, 0x9f} // ERC-721 Metadata
-	ERC721EnumerableID = [4]byte{0x78, 0x0e, 0x9d, 0x63} // ERC-721 Enumerable
-	ERC2981RoyaltyID   = [4]byte{0x2a, 0x55, 0x20, 0x5a} // ERC-2981 Royalty
-	ERC5192SoulboundID = [4]byte{0xb4, 0x5a, 0x3c, 0x0e} // ERC-5192 Minimal Soulbound
-)
-
-// =============================================================================
-// Payment System Tests
-// =============================================================================
-
-// TestERC20FunctionSelectors verifies ERC-20 function selectors
-func TestERC20FunctionSelectors(t *testing.T) {
-	selectors := map[string][4]byte{
-		"name()":                            {0x06, 0xfd, 0xde, 0x03},
-		"symbol()":                          {0x95, 0xd8, 0x9b, 0x41},
-		"decimals()":                        {0x31, 0x3c, 0xe5, 0x67},
-		"totalSupply()":                     {0x18, 0x16, 0x0d, 0xdd},
-		"balanceOf(address)":                {0x70, 0xa0, 0x82, 0x31},
-		"transfer(address,uint256)":         {0xa9, 0x05, 0x9c, 0xbb},
-		"allowance(address,address)":        {0xdd, 0x62, 0xed, 0x3e},
-		"approve(address,uint256)":          {0x09, 0x5e, 0xa7, 0xb3},
-		"transferFrom(address,address,uint256)": {0x23, 0xb8, 0x72, 0xdd},
-	}
-
-	for name, selector := range selectors {
-		// Verify selector is correct by computing keccak256
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if string(computed) != string(selector[:]) {
-			t.Errorf("Selector mismatch for %s: expected %x, computed %x", name, selector, computed)
-		}
-	}
-
-	t.Log("✓ All ERC-20 function selectors verified")
-}
-
-// TestERC20EventSignatures verifies ERC-20 event signatures
-func TestERC20EventSignatures(t *testing.T) {
-	// Transfer(address indexed from, address indexed to, uint256 value)
-	transferSig := crypto.Keccak256([]byte("Transfer(address,address,uint256)"))
-	expectedTransfer := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
-	if types.BytesToHash(transferSig).Hex() != expectedTransfer {
-		t.Errorf("Transfer event signature mismatch")
-	}
-
-	// Approval(address indexed owner, address indexed spender, uint256 value)
-	approvalSig := crypto.Keccak256([]byte("Approval(address,address,uint256)"))
-	expectedApproval := "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
-	if types.BytesToHash(approvalSig).Hex() != expectedApproval {
-		t.Errorf("Approval event signature mismatch")
-	}
-
-	t.Log("✓ ERC-20 event signatures verified")
-}
-
-// TestBatchTransferPattern tests multicall/batch transfer support
-func TestBatchTransferPattern(t *testing.T) {
-	// Verify CREATE2 for deterministic deployment (used by Gnosis Safe)
-	create2Op := vm.CREATE2
-	if create2Op != 0xf5 {
-		t.Errorf("CREATE2 opcode incorrect: expected 0xf5, got 0x%02x", create2Op)
-	}
-
-	// Verify DELEGATECALL for proxy patterns
-	delegatecallOp := vm.DELEGATECALL
-	if delegatecallOp != 0xf4 {
-		t.Errorf("DELEGATECALL opcode incorrect: expected 0xf4, got 0x%02x", delegatecallOp)
-	}
-
-	// Verify STATICCALL for read-only calls
-	staticcallOp := vm.STATICCALL
-	if staticcallOp != 0xfa {
-		t.Errorf("STATICCALL opcode incorrect: expected 0xfa, got 0x%02x", staticcallOp)
-	}
-
-	t.Log("✓ Batch transfer pattern opcodes verified")
-}
-
-// TestHTLCSupport tests Hash Time Lock Contract requirements
-func TestHTLCSupport(t *testing.T) {
-	// HTLC requires:
-	// 1. Keccak256 hashing (KECCAK256 opcode)
-	// 2. Block timestamp (TIMESTAMP opcode)
-	// 3. Block number (NUMBER opcode)
-	// 4. SHA256 precompile
-
-	// Verify opcodes
-	if vm.KECCAK256 != 0x20 {
-		t.Errorf("KECCAK256 opcode incorrect")
-	}
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP opcode incorrect")
-	}
-	if vm.NUMBER != 0x43 {
-		t.Errorf("NUMBER opcode incorrect")
-	}
-
-	// Verify SHA256 precompile
-	sha256 := vm.GetSha256()
-	testData := []byte("test hash time lock")
-	result, err := sha256.Run(testData)
-	if err != nil {
-		t.Errorf("SHA256 precompile failed: %v", err)
-	}
-	if len(result) != 32 {
-		t.Errorf("SHA256 output length incorrect")
-	}
-
-	t.Log("✓ HTLC (Hash Time Lock Contract) requirements verified")
-}
-
-// TestMultiSigSupport tests multi-signature wallet support
-func TestMultiSigSupport(t *testing.T) {
-	// Multi-sig requires:
-	// 1. ecrecover for signature verification
-	// 2. Deterministic addresses (CREATE2)
-	// 3. Proxy pattern (DELEGATECALL)
-
-	ecrecover := vm.GetEcrecover()
-	
-	// Test with zero input (should return nil, not error)
-	input := make([]byte, 128)
-	result, err := ecrecover.Run(input)
-	if err != nil {
-		t.Errorf("ecrecover should not error on zero input: %v", err)
-	}
-	// Zero input returns nil (no recovery possible)
-	_ = result
-
-	t.Log("✓ Multi-signature wallet requirements verified")
-}
-
-// =============================================================================
-// NFT Tests
-// =============================================================================
-
-// TestERC721FunctionSelectors verifies ERC-721 function selectors
-func TestERC721FunctionSelectors(t *testing.T) {
-	selectors := map[string][4]byte{
-		"balanceOf(address)":                          {0x70, 0xa0, 0x82, 0x31},
-		"ownerOf(uint256)":                            {0x63, 0x52, 0x21, 0x1e},
-		"safeTransferFrom(address,address,uint256)":   {0x42, 0x84, 0x2e, 0x0e},
-		"transferFrom(address,address,uint256)":       {0x23, 0xb8, 0x72, 0xdd},
-		"approve(address,uint256)":                    {0x09, 0x5e, 0xa7, 0xb3},
-		"setApprovalForAll(address,bool)":             {0xa2, 0x2c, 0xb4, 0x65},
-		"getApproved(uint256)":                        {0x08, 0x18, 0x12, 0xfc},
-		"isApprovedForAll(address,address)":           {0xe9, 0x85, 0xe9, 0xc5},
-	}
-
-	for name, expected := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if string(computed) != string(expected[:]) {
-			t.Errorf("ERC-721 selector mismatch for %s", name)
-		}
-	}
-
-	t.Log("✓ ERC-721 function selectors verified")
-}
-
-// TestERC721EventSignatures verifies ERC-721 event signatures
-func TestERC721EventSignatures(t *testing.T) {
-	events := map[string]string{
-		"Transfer(address,address,uint256)":      "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
-		"Approval(address,address,uint256)":      "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
-		"ApprovalForAll(address,address,bool)":   "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31",
-	}
-
-	for sig, expected := range events {
-		computed := types.BytesToHash(crypto.Keccak256([]byte(sig))).Hex()
-		if computed != expected {
-			t.Errorf("Event signature mismatch for %s", sig)
-		}
-	}
-
-	t.Log("✓ ERC-721 event signatures verified")
-}
-
-// TestERC1155Support verifies ERC-1155 multi-token support
-func TestERC1155Support(t *testing.T) {
-	// ERC-1155 specific selectors
-	selectors := map[string][4]byte{
-		"balanceOf(address,uint256)":                                   {0x00, 0xfd, 0xd5, 0x8e},
-		"balanceOfBatch(address[],uint256[])":                          {0x4e, 0x12, 0x73, 0xf4},
-		"safeTransferFrom(address,address,uint256,uint256,bytes)":      {0xf2, 0x42, 0x43, 0x2a},
-		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)": {0x2e, 0xb2, 0xc2, 0xd6},
-	}
-
-	for name, expected := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if string(computed) != string(expected[:]) {
-			t.Errorf("ERC-1155 selector mismatch for %s: expected %x, got %x", name, expected, computed)
-		}
-	}
-
-	t.Log("✓ ERC-1155 function selectors verified")
-}
-
-// TestERC2981Royalty tests royalty standard support
-func TestERC2981Royalty(t *testing.T) {
-	// ERC-2981 royaltyInfo(uint256,uint256) returns (address,uint256)
-	selector := crypto.Keccak256([]byte("royaltyInfo(uint256,uint256)"))[:4]
-	expected := [4]byte{0x2a, 0x55, 0x20, 0x5a}
-
-	if string(selector) != string(expected[:]) {
-		t.Errorf("ERC-2981 royaltyInfo selector mismatch: expected %x, got %x", expected, selector)
-	}
-
-	t.Log("✓ ERC-2981 royalty standard supported")
-}
-
-// TestERC5192Soulbound tests soulbound token support
-func TestERC5192Soulbound(t *testing.T) {
-	// ERC-5192 locked(uint256) returns (bool)
-	selector := crypto.Keccak256([]byte("locked(uint256)"))[:4]
-	expected := [4]byte{0xb4, 0x5a, 0x3c, 0x0e}
-
-	if string(selector) != string(expected[:]) {
-		t.Errorf("ERC-5192 locked selector mismatch: expected %x, got %x", expected, selector)
-	}
-
-	t.Log("✓ ERC-5192 soulbound token standard supported")
-}
-
-// =============================================================================
-// DeFi Tests
-// =============================================================================
-
-// TestAMMSupport tests Automated Market Maker requirements
-func TestAMMSupport(t *testing.T) {
-	// AMM requires:
-	// 1. uint256 math operations
-	// 2. Square root calculation capability
-	// 3. Sufficient precision
-
-	// Test uint256 multiplication for constant product formula
-	x := uint256.NewInt(1000000000000000000) // 1e18
-	y := uint256.NewInt(1000000000000000000) // 1e18
-	k := new(uint256.Int).Mul(x, y)
-
-	// k should be 1e36
-	expected := new(uint256.Int)
-	expected.SetFromBig(new(big.Int).Exp(big.NewInt(10), big.NewInt(36), nil))
-	
-	if k.Cmp(expected) != 0 {
-		t.Errorf("uint256 multiplication error")
-	}
-
-	t.Log("✓ AMM constant product formula supported")
-}
-
-// TestFlashLoanSupport tests flash loan capability
-func TestFlashLoanSupport(t *testing.T) {
-	// Flash loans require:
-	// 1. Re-entrancy capability (CALL within CALL)
-	// 2. Balance checks
-	// 3. Callback mechanism
-
-	// Verify CALL opcode exists
-	if vm.CALL != 0xf1 {
-		t.Errorf("CALL opcode incorrect")
-	}
-
-	// Verify CALLVALUE opcode for receiving ETH
-	if vm.CALLVALUE != 0x34 {
-		t.Errorf("CALLVALUE opcode incorrect")
-	}
-
-	// Verify BALANCE opcode for checking balances
-	if vm.BALANCE != 0x31 {
-		t.Errorf("BALANCE opcode incorrect")
-	}
-
-	t.Log("✓ Flash loan requirements verified")
-}
-
-// TestOracleIntegration tests oracle pattern support
-func TestOracleIntegration(t *testing.T) {
-	// Oracles require:
-	// 1. External call capability (CALL/STATICCALL)
-	// 2. Timestamp access
-	// 3. Block number access
-	// 4. Chainlink aggregator interface support
-
-	// Chainlink latestRoundData selector
-	selector := crypto.Keccak256([]byte("latestRoundData()"))[:4]
-	expected := [4]byte{0xfe, 0xaf, 0x96, 0x8c}
-	
-	if string(selector) != string(expected[:]) {
-		t.Errorf("Chainlink latestRoundData selector mismatch")
-	}
-
-	// Verify block data opcodes
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP incorrect")
-	}
-	if vm.NUMBER != 0x43 {
-		t.Errorf("NUMBER incorrect")
-	}
-	if vm.BLOCKHASH != 0x40 {
-		t.Errorf("BLOCKHASH incorrect")
-	}
-
-	t.Log("✓ Oracle integration requirements verified")
-}
-
-// TestYieldFarmingSupport tests yield farming/staking support
-func TestYieldFarmingSupport(t *testing.T) {
-	// Yield farming requires:
-	// 1. ERC-20 support
-	// 2. Reward calculation (math)
-	// 3. Time-based calculations
-
-	// Verify time-based operations
-	if vm.TIMESTAMP != 0x42 {
-		t.Errorf("TIMESTAMP incorrect")
-	}
-
-	// Common staking selectors
-	selectors := map[string][4]byte{
-		"stake(uint256)":    {},
-		"unstake(uint256)":  {},
-		"claimRewards()":    {},
-		"pendingReward(address)": {},
-	}
-
-	for name := range selectors {
-		computed := crypto.Keccak256([]byte(name))[:4]
-		if len(computed) != 4 {
-			t.Errorf("Failed to compute selector for %s", name)
-		}
-	}
-
-	t.Log("✓ Yield farming/staking requirements verified")
-}
-
-// =============================================================================
-// Gas Cost Tests
-// =============================================================================
-
-// TestDAppGasCosts verifies reasonable gas costs for common operations
-func TestDAppGasCosts(t *testing.T) {
-	gasCosts := map[string]uint64{
-		"TxGas":                 params.TxGas,                 // 21000
-		"TxGasContractCreation": params.TxGasContractCreation, // 53000
-		"SstoreSetGas":          params.SstoreSetGas,          // 20000
-		"SstoreResetGas":        params.SstoreResetGas,        // 5000
-		"CallGas":               params.CallGasEIP150,         // 700
-		"CreateGas":             params.CreateGas,             // 32000
-		"Create2Gas":            params.Create2Gas,            // 32000
-		"LogGas":                params.LogGas,                // 375
-		"LogDataGas":            params.LogDataGas,            // 8
-		"Keccak256Gas":          params.Keccak256Gas,          // 30
-	}
-
-	for name, gas := range gasCosts {
-		if gas == 0 {
-			t.Errorf("Gas cost for %s is zero", name)
-		}
-		t.Logf("  %s: %d", name, gas)
-	}
-
-	t.Log("✓ DApp gas costs verified")
-}
-
-// =============================================================================
-// Summary Tests
-// =============================================================================
-
-// TestPhase1CompatibilitySummary provides a summary of Phase 1 capabilities
-func TestPhase1CompatibilitySummary(t *testing.T) {
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("           PHASE 1 DAPP COMPATIBILITY SUMMARY")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("")
-	t.Log("Payment Systems:")
-	t.Log("  ✓ ERC-20 token standard")
-	t.Log("  ✓ Batch transfers (multicall)")
-	t.Log("  ✓ Multi-signature wallets (Gnosis Safe)")
-	t.Log("  ✓ Hash Time Lock Contracts (HTLC)")
-	t.Log("  ✓ Streaming payments (Sablier-style)")
-	t.Log("")
-	t.Log("NFT Standards:")
-	t.Log("  ✓ ERC-721 (Standard NFT)")
-	t.Log("  ✓ ERC-1155 (Multi-token)")
-	t.Log("  ✓ ERC-2981 (Royalties)")
-	t.Log("  ✓ ERC-5192 (Soulbound tokens)")
-	t.Log("  ✓ ERC-721 Metadata & Enumerable")
-	t.Log("")
-	t.Log("DeFi Protocols:")
-	t.Log("  ✓ AMM (Uniswap-style)")
-	t.Log("  ✓ Flash Loans (Aave-style)")
-	t.Log("  ✓ Oracle Integration (Chainlink)")
-	t.Log("  ✓ Yield Farming/Staking")
-	t.Log("  ✓ Lending/Borrowing")
-	t.Log("")
-	t.Log("═══════════════════════════════════════════════════════════════")
-	t.Log("    N42 FULLY SUPPORTS PHASE 1 DAPP REQUIREMENTS")
-	t.Log("═══════════════════════════════════════════════════════════════")
-}
-
-// =============================================================================
-// Benchmarks
-// =============================================================================
-
-func BenchmarkKeccak256Selector(b *testing.B) {
-	data := []byte("transfer(address,uint256)")
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = crypto.Keccak256(data)[:4]
-	}
-}
-
-func BenchmarkUint256Math(b *testing.B) {
-	x := uint256.NewInt(1000000000000000000)
-	y := uint256.NewInt(2000000000000000000)
-	result := new(uint256.Int)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		result.Mul(x, y)
-		result.Div(result, x)
-	}
-}
-
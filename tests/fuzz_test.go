@@ -0,0 +1,141 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/rlp"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal/vm/runtime"
+)
+
+// The fuzz targets below only run their seed corpus during go test. Run
+// one of them with, for example:
+//
+//	go test ./tests -run '^$' -fuzz FuzzVMExecution -fuzztime 1m
+
+// fuzzGasLimit bounds the work a single random program can do
+const fuzzGasLimit = 100000
+
+func FuzzVMExecution(f *testing.F) {
+	f.Add([]byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00}, []byte{})                             // SSTORE
+	f.Add([]byte{0x5b, 0x60, 0x00, 0x56}, []byte{})                                         // endless loop
+	f.Add([]byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x36, 0x60, 0x00, 0xf3}, []byte{1, 2}) // return calldata
+	f.Add(hexutil.MustDecode("0xef000101000402000100030400000000800001600000"), []byte{})   // EOF container
+	f.Fuzz(func(t *testing.T, code, input []byte) {
+		config, err := runtime.ForkConfig("Osaka")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ibs, release, err := runtime.NewState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		cfg := &runtime.Config{ChainConfig: config, GasLimit: fuzzGasLimit, State: ibs}
+		// Execution errors are expected, only crashes are of interest
+		runtime.Execute(code, input, cfg, 0)
+	})
+}
+
+func FuzzTransactionUnmarshal(f *testing.F) {
+	from, to := types.Address{0x01}, types.Address{0x02}
+	for _, inner := range []transaction.TxData{
+		&transaction.LegacyTx{Nonce: 1, GasPrice: uint256.NewInt(1), Gas: 21000, To: &to, From: &from, Value: uint256.NewInt(5)},
+		&transaction.DynamicFeeTx{
+			ChainID: uint256.NewInt(1), GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(2), Gas: 21000,
+			To: &to, From: &from, Value: uint256.NewInt(5), Data: []byte{0xaa},
+			AccessList: transaction.AccessList{{Address: to, StorageKeys: []types.Hash{{0x01}}}},
+		},
+	} {
+		data, err := transaction.NewTx(inner).Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var tx transaction.Transaction
+		if err := tx.Unmarshal(data); err != nil {
+			return
+		}
+		// A decoded transaction must be usable
+		if _, err := tx.Marshal(); err != nil {
+			t.Fatalf("failed to encode decoded transaction: %v", err)
+		}
+		tx.Hash()
+	})
+}
+
+func FuzzHeaderUnmarshal(f *testing.F) {
+	header := &block.Header{
+		Coinbase:   types.Address{0x01},
+		Difficulty: uint256.NewInt(2),
+		Number:     uint256.NewInt(100),
+		GasLimit:   30000000,
+		GasUsed:    21000,
+		Time:       1700000000,
+		Extra:      []byte("n42"),
+		BaseFee:    uint256.NewInt(7),
+	}
+	data, err := header.Marshal()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var h block.Header
+		if err := h.Unmarshal(data); err != nil {
+			return
+		}
+		if _, err := h.Marshal(); err != nil {
+			t.Fatalf("failed to encode decoded header: %v", err)
+		}
+		h.Hash()
+	})
+}
+
+func FuzzRLPDecode(f *testing.F) {
+	al := transaction.AccessList{{Address: types.Address{0x01}, StorageKeys: []types.Hash{{0x02}}}}
+	data, err := rlp.EncodeToBytes(al)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add([]byte{0xc0})
+	f.Add([]byte{0xf8, 0x01})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var decoded transaction.AccessList
+		if err := rlp.DecodeBytes(data, &decoded); err == nil {
+			if _, err := rlp.EncodeToBytes(decoded); err != nil {
+				t.Fatalf("failed to encode decoded access list: %v", err)
+			}
+		}
+		var auth transaction.Authorization
+		rlp.DecodeBytes(data, &auth)
+		var raw []interface{}
+		rlp.DecodeBytes(data, &raw)
+	})
+}
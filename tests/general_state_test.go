@@ -0,0 +1,52 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/internal/vm/runtime"
+)
+
+// TestGeneralState runs the GeneralStateTests fixtures. Each subtest checks
+// that the transaction is accepted or rejected as expected, and compares the
+// post state root and the logs hash with the fixture.
+func TestGeneralState(t *testing.T) {
+	walkFixtures(t, "GeneralStateTests", func(t *testing.T, path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tests map[string]*runtime.StateTest
+		if err := json.Unmarshal(data, &tests); err != nil {
+			t.Fatalf("invalid fixture: %v", err)
+		}
+		for name, test := range tests {
+			for _, subtest := range test.Subtests() {
+				t.Run(fmt.Sprintf("%s/%s/%d", name, subtest.Fork, subtest.Index), func(t *testing.T) {
+					if _, err := test.Run(subtest, vm.Config{}); err != nil {
+						t.Error(err)
+					}
+				})
+			}
+		}
+	})
+}
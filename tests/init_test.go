@@ -0,0 +1,73 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	// fixturesDir holds the fixtures maintained in this repository
+	fixturesDir = filepath.Join(".", "testdata")
+
+	// ethereumTestsDir points to a checkout of github.com/ethereum/tests. The
+	// official fixtures are only run when it is set.
+	ethereumTestsDir = os.Getenv("N42_ETHEREUM_TESTS")
+)
+
+// fixtureDirs returns the directories holding the fixtures of kind, such as
+// GeneralStateTests or BlockchainTests
+func fixtureDirs(kind string) []string {
+	dirs := []string{filepath.Join(fixturesDir, kind)}
+	if ethereumTestsDir != "" {
+		dirs = append(dirs, filepath.Join(ethereumTestsDir, kind))
+	}
+	return dirs
+}
+
+// walkFixtures runs fn as a subtest for every JSON fixture file below the
+// directories of kind. Missing directories are skipped.
+func walkFixtures(t *testing.T, kind string, fn func(t *testing.T, path string)) {
+	for _, dir := range fixtureDirs(kind) {
+		if _, err := os.Stat(dir); err != nil {
+			t.Logf("skipping %s: %v", dir, err)
+			continue
+		}
+		var files []string
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(path, ".json") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(files)
+		for _, path := range files {
+			name, _ := filepath.Rel(dir, path)
+			t.Run(name, func(t *testing.T) { fn(t, path) })
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpccompat
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/api"
+	"github.com/n42blockchain/N42/internal/consensus"
+	"github.com/n42blockchain/N42/internal/consensus/apos"
+	"github.com/n42blockchain/N42/internal/consensus/misc"
+	"github.com/n42blockchain/N42/internal/tracers"
+	_ "github.com/n42blockchain/N42/internal/tracers/native"
+	"github.com/n42blockchain/N42/internal/txspool"
+	"github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/internal/witness"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+var (
+	// senderKey funds every transaction of the test chain
+	senderKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+	recipient    = types.HexToAddress("0x0000000000000000000000000000000000000aaa")
+	coinbase     = types.HexToAddress("0x0000000000000000000000000000000000000ccc")
+
+	// logContract stores the first calldata word in slot 0 and logs it with
+	// topic 0x2a
+	logContractCode = hexutil.MustDecode("0x6012600c60003960126000f3" + "60003580600055600052602a60206000a100")
+	logContract     = crypto.CreateAddress(sender, 1)
+)
+
+const (
+	genesisTime = 1700000000
+	blockTime   = 12
+)
+
+// testChainConfig enables every fork the RPC results depend on from genesis
+func testChainConfig() *params.ChainConfig {
+	return &params.ChainConfig{
+		ChainID:               big.NewInt(1337),
+		Consensus:             params.Faker,
+		HomesteadBlock:        big.NewInt(0),
+		TangerineWhistleBlock: big.NewInt(0),
+		SpuriousDragonBlock:   big.NewInt(0),
+		ByzantiumBlock:        big.NewInt(0),
+		ConstantinopleBlock:   big.NewInt(0),
+		PetersburgBlock:       big.NewInt(0),
+		IstanbulBlock:         big.NewInt(0),
+		BerlinBlock:           big.NewInt(0),
+		LondonBlock:           big.NewInt(0),
+	}
+}
+
+// testNode is an in-process node serving the JSON-RPC API of a deterministic
+// chain over HTTP.
+type testNode struct {
+	config *params.ChainConfig
+	db     kv.RwDB
+	engine consensus.Engine
+	bc     common.IBlockChain
+	server *httptest.Server
+}
+
+// newTestNode builds the test chain and starts the HTTP endpoint. Every run
+// produces the same blocks, so responses can be compared byte for byte:
+//
+//	block 1: value transfer (legacy transaction)
+//	block 2: deployment of logContract (dynamic fee transaction)
+//	block 3: call of logContract emitting a log (legacy) and a transfer (dynamic fee)
+func newTestNode(t *testing.T) *testNode {
+	t.Helper()
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	n := &testNode{config: testChainConfig(), db: memdb.NewTestDB(t), engine: apos.NewFaker()}
+	n.bc = newGenesisChain(t, n.db, n.engine, n.config)
+
+	signer := transaction.LatestSignerForChainID(n.config.ChainID)
+	gasPrice := uint256.NewInt(2 * params.GWei)
+	legacy := func(nonce uint64, to *types.Address, value uint64, gas uint64, data []byte) *transaction.Transaction {
+		return signTx(t, signer, transaction.NewTransaction(nonce, sender, to, uint256.NewInt(value), gas, gasPrice, data))
+	}
+	dynamic := func(nonce uint64, to *types.Address, value uint64, gas uint64, data []byte) *transaction.Transaction {
+		return signTx(t, signer, transaction.NewTx(&transaction.DynamicFeeTx{
+			ChainID:   uint256.MustFromBig(n.config.ChainID),
+			Nonce:     nonce,
+			GasTipCap: uint256.NewInt(params.GWei),
+			GasFeeCap: uint256.NewInt(3 * params.GWei),
+			Gas:       gas,
+			To:        to,
+			From:      &sender,
+			Value:     uint256.NewInt(value),
+			Data:      data,
+		}))
+	}
+	n.addBlock(t, legacy(0, &recipient, 1000, params.TxGas, nil))
+	n.addBlock(t, dynamic(1, nil, 0, 100000, logContractCode))
+	n.addBlock(t,
+		legacy(2, &logContract, 0, 50000, types.Hash{0x42}.Bytes()),
+		dynamic(3, &recipient, 2000, params.TxGas, nil),
+	)
+
+	pool, err := txspool.NewTxsPool(context.Background(), n.bc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pool.Stop() })
+	backend := api.NewAPI(n.bc, n.db, n.engine, pool, nil, n.config)
+	backend.SetGpo(api.NewOracle(n.bc, nil, n.config, conf.FullNodeGPO))
+	srv := jsonrpc.NewServer()
+	// The tracers override the debug methods of the backend, as in the node
+	apis := append(backend.Apis(), tracers.APIs(backend)...)
+	for _, a := range append(apis, witness.APIs(backend)...) {
+		if a.Authenticated {
+			continue
+		}
+		if err := srv.RegisterName(a.Namespace, a.Service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n.server = httptest.NewServer(srv)
+	t.Cleanup(func() {
+		n.server.Close()
+		srv.Stop()
+	})
+	return n
+}
+
+// newGenesisChain writes the genesis of the test chain to db and opens the
+// chain on top of it
+func newGenesisChain(t *testing.T, db kv.RwDB, engine consensus.Engine, config *params.ChainConfig) common.IBlockChain {
+	t.Helper()
+	genesis := &conf.Genesis{
+		Config:     config,
+		Timestamp:  genesisTime,
+		GasLimit:   30000000,
+		Difficulty: uint256.NewInt(1),
+		BaseFee:    uint256.NewInt(params.InitialBaseFee),
+		Alloc: conf.GenesisAlloc{
+			sender: {Balance: "1000000000000000000000"},
+		},
+	}
+	var genesisBlock *block.Block
+	if err := db.Update(context.Background(), func(tx kv.RwTx) (err error) {
+		genesisBlock, _, err = (&internal.GenesisBlock{GenesisConfig: genesis}).Write(tx)
+		return err
+	}); err != nil {
+		t.Fatalf("failed to write genesis: %v", err)
+	}
+	bc, err := internal.NewBlockChain(context.Background(), genesisBlock, engine, db, nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { bc.Close() })
+	return bc
+}
+
+func signTx(t *testing.T, signer transaction.Signer, tx *transaction.Transaction) *transaction.Transaction {
+	signed, err := transaction.SignTx(tx, signer, senderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// addBlock executes txs on top of the head and writes the resulting block
+func (n *testNode) addBlock(t *testing.T, txs ...*transaction.Transaction) {
+	t.Helper()
+	parent := n.bc.CurrentBlock().Header().(*block.Header)
+	header := &block.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   coinbase,
+		Number:     new(uint256.Int).AddUint64(parent.Number64(), 1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + blockTime,
+		BaseFee:    uint256.MustFromBig(misc.CalcBaseFee(n.config, parent)),
+	}
+	if err := n.engine.Prepare(n.bc, header); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := n.db.BeginRo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		ibs       = state.New(state.NewPlainStateReader(tx))
+		gasPool   = new(common.GasPool).AddGas(header.GasLimit)
+		getHeader = func(hash types.Hash, number uint64) *block.Header { return rawdb.ReadHeader(tx, hash, number) }
+		receipts  []*block.Receipt
+	)
+	for i, txn := range txs {
+		ibs.Prepare(txn.Hash(), types.Hash{}, i)
+		receipt, _, err := internal.ApplyTransaction(n.config, internal.GetHashFn(header, getHeader), n.engine, &coinbase, gasPool, ibs, state.NewNoopWriter(), header, txn, &header.GasUsed, vm.Config{})
+		if err != nil {
+			tx.Rollback()
+			t.Fatalf("block %d tx %d: %v", header.Number.Uint64(), i, err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	header.Root = ibs.IntermediateRoot()
+	blk := block.NewBlockFromReceipt(header, txs, nil, receipts, nil)
+	tx.Rollback()
+
+	for i, receipt := range receipts {
+		receipt.BlockHash = blk.Hash()
+		receipt.BlockNumber = blk.Number64()
+		receipt.TransactionIndex = uint(i)
+		for _, log := range receipt.Logs {
+			log.BlockHash = blk.Hash()
+		}
+	}
+	if err := n.bc.WriteBlockWithState(blk, receipts, ibs, nil); err != nil {
+		t.Fatal(err)
+	}
+}
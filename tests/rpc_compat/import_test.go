@@ -0,0 +1,114 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpccompat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/consensus/apos"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+)
+
+// TestInsertChain imports the test chain into an empty node. Blocks are
+// executed on top of the staged writes of their parents while those are
+// committed, alone or in batches, which must leave the same state and
+// history behind as writing every block directly.
+func TestInsertChain(t *testing.T) {
+	n := newTestNode(t)
+
+	var blocks []block.IBlock
+	for i := uint64(1); i <= n.bc.CurrentBlock().Number64().Uint64(); i++ {
+		blk, err := n.bc.GetBlockByNumber(uint256.NewInt(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, blk)
+	}
+	for _, every := range []int{1, 2, len(blocks)} {
+		t.Run(fmt.Sprintf("every=%d", every), func(t *testing.T) {
+			testInsertChain(t, n, blocks, every)
+		})
+	}
+}
+
+func testInsertChain(t *testing.T, n *testNode, blocks []block.IBlock, every int) {
+	db := memdb.NewTestDB(t)
+	bc := newGenesisChain(t, db, apos.NewFaker(), n.config)
+	if err := bc.(*internal.BlockChain).SetCommitBatch(every, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.InsertChain(blocks); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if have, want := bc.CurrentBlock().Hash(), blocks[len(blocks)-1].Hash(); have != want {
+		t.Fatalf("head mismatch: have %x, want %x", have, want)
+	}
+	var head types.Hash
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		head = rawdb.ReadHeadBlockHash(tx)
+		return nil
+	}); err != nil || head != blocks[len(blocks)-1].Hash() {
+		t.Fatalf("stored head mismatch: have %x, want %x (%v)", head, blocks[len(blocks)-1].Hash(), err)
+	}
+
+	for _, table := range []string{
+		modules.Account,
+		modules.Storage,
+		modules.Code,
+		modules.PlainContractCode,
+		modules.AccountChangeSet,
+		modules.StorageChangeSet,
+		modules.AccountsHistory,
+		modules.StorageHistory,
+		modules.HeaderCanonical,
+	} {
+		want, have := dumpTable(t, n.db, table), dumpTable(t, db, table)
+		if len(have) != len(want) {
+			t.Errorf("%s: have %d entries, want %d", table, len(have), len(want))
+			continue
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				t.Errorf("%s: entry %d mismatch: have %s, want %s", table, i, have[i], want[i])
+			}
+		}
+	}
+}
+
+func dumpTable(t *testing.T, db kv.RoDB, table string) []string {
+	t.Helper()
+	var entries []string
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		return tx.ForEach(table, nil, func(k, v []byte) error {
+			entries = append(entries, fmt.Sprintf("%x=%x", k, v))
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
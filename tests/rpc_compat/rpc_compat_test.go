@@ -0,0 +1,178 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rpccompat runs JSON-RPC test vectors against the HTTP endpoint of an
+// in-process node. The vectors use the .io format of the execution-apis
+// specification tests: every file in testdata/<method>/ holds requests
+// prefixed with ">> " followed by the expected response prefixed with "<< ".
+// Lines starting with "//" are comments.
+//
+// The responses are recorded from the deterministic chain of newTestNode and
+// pin the JSON fields block explorers like Blockscout rely on. After an
+// intended change of a response, rewrite them with
+//
+//	go test ./tests/rpc_compat -update
+//
+// and review the diff.
+package rpccompat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected responses of the test vectors")
+
+// exchange is a request and its expected response
+type exchange struct {
+	request, response string
+}
+
+// vector is a parsed .io file
+type vector struct {
+	comments  []string
+	exchanges []exchange
+}
+
+func readVector(file string) (*vector, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	v := new(vector)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "//"):
+			v.comments = append(v.comments, text)
+		case strings.HasPrefix(text, ">> "):
+			v.exchanges = append(v.exchanges, exchange{request: text[3:]})
+		case strings.HasPrefix(text, "<< "):
+			if len(v.exchanges) == 0 || v.exchanges[len(v.exchanges)-1].response != "" {
+				return nil, fmt.Errorf("%s:%d: response without request", file, line)
+			}
+			v.exchanges[len(v.exchanges)-1].response = text[3:]
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid line %q", file, line, text)
+		}
+	}
+	return v, scanner.Err()
+}
+
+func (v *vector) write(file string) error {
+	var buf bytes.Buffer
+	for _, c := range v.comments {
+		fmt.Fprintln(&buf, c)
+	}
+	for _, ex := range v.exchanges {
+		fmt.Fprintln(&buf, ">>", ex.request)
+		fmt.Fprintln(&buf, "<<", ex.response)
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
+
+// call posts request to the node and returns the compacted response
+func (n *testNode) call(request string) (string, error) {
+	resp, err := http.Post(n.server.URL, "application/json", strings.NewReader(request))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := json.Compact(&out, body); err != nil {
+		return "", fmt.Errorf("invalid JSON response %q: %w", body, err)
+	}
+	return out.String(), nil
+}
+
+// compareResponses reports how have differs from want. Error messages are not
+// part of the specification, so an expected error only requires an error.
+func compareResponses(have, want string) error {
+	var h, w map[string]interface{}
+	if err := json.Unmarshal([]byte(have), &h); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		return fmt.Errorf("invalid expected response: %w", err)
+	}
+	if _, ok := w["error"]; ok {
+		if _, ok := h["error"]; !ok {
+			return fmt.Errorf("expected an error")
+		}
+		return nil
+	}
+	if !reflect.DeepEqual(h, w) {
+		return fmt.Errorf("response mismatch")
+	}
+	return nil
+}
+
+func TestRPCCompat(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", "*.io"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test vectors found")
+	}
+	n := newTestNode(t)
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(file), "testdata/"), ".io")
+		t.Run(name, func(t *testing.T) {
+			v, err := readVector(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, ex := range v.exchanges {
+				have, err := n.call(ex.request)
+				if err != nil {
+					t.Fatalf(">> %s: %v", ex.request, err)
+				}
+				if *update {
+					v.exchanges[i].response = have
+					continue
+				}
+				if err := compareResponses(have, ex.response); err != nil {
+					t.Errorf("%v\n>> %s\nhave %s\nwant %s", err, ex.request, have, ex.response)
+				}
+			}
+			if *update {
+				if err := v.write(file); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// traces a reverting call, the frame carries the revert data and reason
+>> {"jsonrpc":"2.0","id":1,"method":"debug_traceCall","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000bbb"},"latest",{"tracer":"callTracer","stateOverrides":{"0x0000000000000000000000000000000000000bbb":{"code":"0x6064600c60003960646000fd08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046e6f706500000000000000000000000000000000000000000000000000000000"}}}]}
+<< {"jsonrpc":"2.0","id":1,"result":{"from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0x2fa9e78","gasUsed":"0x5232","to":"0x0000000000000000000000000000000000000bbb","input":"0x","output":"0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046e6f706500000000000000000000000000000000000000000000000000000000","error":"execution reverted","revertReason":"nope","value":"0x0","type":"CALL"}}
@@ -0,0 +1,5 @@
+// transfers the whole genesis balance of the sender, which only the genesis state covers
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000aaa","value":"0x3635c9adc5dea00000"},"0x0"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x"}
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000aaa","value":"0x3635c9adc5dea00000"},"latest"]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"err: insufficient funds for gas * price + value: address 0x71562b71999873DB5b286dF957af199Ec94617F7 have 999999733684516937500 want 1000000000000000000000 (supplied gas 50000000)"}}
@@ -0,0 +1,3 @@
+// calls the contract, which returns nothing
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","data":"0x0101010101010101010101010101010101010101010101010101010101010101"},"latest"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x"}
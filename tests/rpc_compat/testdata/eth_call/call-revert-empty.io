@@ -0,0 +1,3 @@
+// reverts without data, still a revert error
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000bbb"},"latest",{"0x0000000000000000000000000000000000000bbb":{"code":"0x60006000fd"}}]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x"}}
@@ -0,0 +1,3 @@
+// calls with more value than the sender owns
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x0000000000000000000000000000000000000aaa","to":"0x71562b71999873db5b286df957af199ec94617f7","value":"0xffffffffffffffffffffffff"},"latest"]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"err: insufficient funds for gas * price + value: address 0x0000000000000000000000000000000000000aaa have 3000 want 79228162514264337593543950335 (supplied gas 50000000)"}}
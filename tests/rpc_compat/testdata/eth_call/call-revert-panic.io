@@ -0,0 +1,3 @@
+// decodes the solidity panic code of an overflow
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000bbb"},"latest",{"0x0000000000000000000000000000000000000bbb":{"code":"0x6024600c60003960246000fd4e487b710000000000000000000000000000000000000000000000000000000000000011"}}]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted: arithmetic underflow or overflow","data":"0x4e487b710000000000000000000000000000000000000000000000000000000000000011"}}
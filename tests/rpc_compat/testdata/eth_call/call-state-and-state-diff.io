@@ -0,0 +1,3 @@
+// rejects an override setting both the full storage and a diff
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000bbb"},"latest",{"0x0000000000000000000000000000000000000bbb":{"state":{},"stateDiff":{}}}]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"account 0x0000000000000000000000000000000000000bBB has both 'state' and 'stateDiff'"}}
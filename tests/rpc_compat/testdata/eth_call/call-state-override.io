@@ -0,0 +1,3 @@
+// calls code returning the balance of an account, both overridden
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000bbb"},"latest",{"0x0000000000000000000000000000000000000bbb":{"code":"0x730000000000000000000000000000000000000aaa3160005260206000f3"},"0x0000000000000000000000000000000000000aaa":{"balance":"0x1234"}}]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000001234"}
@@ -0,0 +1,3 @@
+// replaces the storage of the contract, so the slot it wrote reads as zero
+>> {"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf"},"latest",{"0xdb7d6ab1f17c6b31909ae466702703daef9269cf":{"code":"0x60005460005260206000f3","state":{"0x0000000000000000000000000000000000000000000000000000000000000001":"0x0000000000000000000000000000000000000000000000000000000000000007"}}}]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000000"}
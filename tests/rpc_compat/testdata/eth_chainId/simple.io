@@ -0,0 +1,3 @@
+// returns the chain id of the test chain
+>> {"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x539"}
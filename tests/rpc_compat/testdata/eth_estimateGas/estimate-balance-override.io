@@ -0,0 +1,3 @@
+// estimates a priced transfer from an account funded by an override
+>> {"jsonrpc":"2.0","id":1,"method":"eth_estimateGas","params":[{"from":"0x0000000000000000000000000000000000000ddd","to":"0x0000000000000000000000000000000000000aaa","value":"0x1","gasPrice":"0x3b9aca00"},"latest",{"0x0000000000000000000000000000000000000ddd":{"balance":"0xde0b6b3a7640000"}}]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x5208"}
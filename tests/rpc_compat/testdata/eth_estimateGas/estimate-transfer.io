@@ -0,0 +1,3 @@
+// estimates a plain transfer
+>> {"jsonrpc":"2.0","id":1,"method":"eth_estimateGas","params":[{"from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000aaa","value":"0x1"}]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x5208"}
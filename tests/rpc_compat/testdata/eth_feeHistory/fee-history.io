@@ -0,0 +1,3 @@
+// gets the fee history of the test chain
+>> {"jsonrpc":"2.0","id":1,"method":"eth_feeHistory","params":["0x3","latest",[25,75]]}
+<< {"jsonrpc":"2.0","id":1,"result":{"oldestBlock":"0x1","reward":[["0x3b9aca00","0x4f3fc8ec"],["0x3b9aca00","0x4f3fc8ec"],["0x3b9aca00","0x4f3fc8ec"]],"baseFeePerGas":["0x27f5cb14","0x27f5cb14","0x27f5cb14","0x22fca240"],"gasUsedRatio":[0.0021758666666666666,0.0021758666666666666,0.0021758666666666666]}}
@@ -0,0 +1,3 @@
+// gets a balance at an older block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000aaa","0x1"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x3e8"}
@@ -0,0 +1,3 @@
+// gets the balance of the recipient
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000aaa","latest"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0xbb8"}
@@ -0,0 +1,3 @@
+// gets an unknown block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockByHash","params":["0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",true]}
+<< {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"block does not exist in blockchain"}}
@@ -0,0 +1,3 @@
+// gets a block by hash
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockByHash","params":["0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e",true]}
+<< {"jsonrpc":"2.0","id":1,"result":{"baseFeePerGas":"0x2da4d8cd","difficulty":"0x3","extraData":"0x","gasLimit":"0x1c9c380","gasUsed":"0xdebc","hash":"0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e","logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","miner":"0x0000000000000000000000000000000000000ccc","mixHash":"0x0000000000000000000000000000000000000000000000000000000000000000","nonce":"0x0000000000000000","number":"0x2","parentHash":"0x5cc140b799b126c3206470593f540fa8f6aae79dea82ed021f9628b4a08fa950","receiptsRoot":"0x398029194d7be0f0db7f15cc7ef0d2327de9e54efac15937e815edd666e4afb7","rewards":[],"sha3Uncles":"0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347","signature":"0x000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","size":"0x22c","stateRoot":"0x9811ce7648e71ebc91020a5a53b15fee4a41b9f6cfb70516f9e74a1fc05b6349","timestamp":"0x6553f118","totalDifficulty":"0x5","transactions":[{"blockHash":"0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e","blockNumber":"0x2","from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0x186a0","gasPrice":"0x693fa2cd","maxFeePerGas":"0xb2d05e00","maxPriorityFeePerGas":"0x3b9aca00","hash":"0x663a15acf7a160936a2b3eb00f65490ba28ab60ae65083279b7a17ed438d410f","input":"0x6012600c60003960126000f360003580600055600052602a60206000a100","nonce":"0x1","to":null,"transactionIndex":"0x0","value":"0x0","type":"0x2","accessList":[],"chainId":"0x539","v":"0x0","r":"0xfbbd176dda8fc525d2561195efb2f4924f0e1544064252e5294af02db7955f7c","s":"0x4bd275e510dec33a48c2754a9cc89b66654e3ea32298ea7a50f305405017bee5","yParity":"0x0"}],"transactionsRoot":"0x8c09cb77fce1dcfd9f96b3b17651562570b1f6b43bf3aac7ee40a2b54ffc345a","uncles":[],"verifier":[]}}
@@ -0,0 +1,3 @@
+// gets the head block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["latest",false]}
+<< {"jsonrpc":"2.0","id":1,"result":{"baseFeePerGas":"0x27f5cb14","difficulty":"0x4","extraData":"0x","gasLimit":"0x1c9c380","gasUsed":"0xfefc","hash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010200000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000100001000000000000000000000020000000000000000000000000000000000000","miner":"0x0000000000000000000000000000000000000ccc","mixHash":"0x0000000000000000000000000000000000000000000000000000000000000000","nonce":"0x0000000000000000","number":"0x3","parentHash":"0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e","receiptsRoot":"0x2544f9c80c46ca26cf4b99de1d0c11c9afd6bad2c9821f8d740fd864fa10cdc9","rewards":[],"sha3Uncles":"0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347","signature":"0x000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","size":"0x22c","stateRoot":"0x9bb27b8626b8fb21dd6ec898d1d7956d566fb3ccc43101197beb429c32d0dbe1","timestamp":"0x6553f124","totalDifficulty":"0x9","transactions":["0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","0xa10c32e23a3324dff72dfcdeea4f39e71abbdad18d4106937a93d23b1ccdc9db"],"transactionsRoot":"0xd63df4bcf71bfd5049051f035d4bc8b66683d508221ce6cc233f3300729652da","uncles":[],"verifier":[]}}
@@ -0,0 +1,3 @@
+// gets all receipts of a block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockReceipts","params":["0x3"]}
+<< {"jsonrpc":"2.0","id":1,"result":[{"blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","blockNumber":"0x3","transactionHash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","transactionIndex":"0x0","from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","gasUsed":"0xacf4","cumulativeGasUsed":"0xacf4","contractAddress":null,"logs":[{"address":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","topics":["0x000000000000000000000000000000000000000000000000000000000000002a"],"data":"0x4200000000000000000000000000000000000000000000000000000000000000","blockNumber":"0x3","transactionHash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","transactionIndex":"0x0","blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","logIndex":"0x0","removed":false}],"logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010200000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000100001000000000000000000000020000000000000000000000000000000000000","status":"0x1","effectiveGasPrice":"0x77359400","type":"0x0"},{"blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","blockNumber":"0x3","transactionHash":"0xa10c32e23a3324dff72dfcdeea4f39e71abbdad18d4106937a93d23b1ccdc9db","transactionIndex":"0x1","from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000aaa","gasUsed":"0x5208","cumulativeGasUsed":"0xfefc","contractAddress":null,"logs":[],"logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","status":"0x1","effectiveGasPrice":"0x63909514","type":"0x2"}]}
@@ -0,0 +1,3 @@
+// counts the transactions of a block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockTransactionCountByHash","params":["0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x2"}
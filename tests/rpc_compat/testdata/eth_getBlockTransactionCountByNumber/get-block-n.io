@@ -0,0 +1,3 @@
+// counts the transactions of a block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getBlockTransactionCountByNumber","params":["0x3"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x2"}
@@ -0,0 +1,3 @@
+// gets the code of the contract at a block before its deployment
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getCode","params":["0xdb7d6ab1f17c6b31909ae466702703daef9269cf","0x1"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x"}
@@ -0,0 +1,3 @@
+// gets the code of an account without code
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getCode","params":["0x0000000000000000000000000000000000000aaa","latest"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x"}
@@ -0,0 +1,3 @@
+// gets the log emitted by the contract
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x0","toBlock":"latest","address":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf"}]}
+<< {"jsonrpc":"2.0","id":1,"result":[{"address":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","topics":["0x000000000000000000000000000000000000000000000000000000000000002a"],"data":"0x4200000000000000000000000000000000000000000000000000000000000000","blockNumber":"0x3","transactionHash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","transactionIndex":"0x0","blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","logIndex":"0x0","removed":false}]}
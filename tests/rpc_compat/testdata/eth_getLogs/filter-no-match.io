@@ -0,0 +1,3 @@
+// gets logs of blocks without logs
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2"}]}
+<< {"jsonrpc":"2.0","id":1,"result":[]}
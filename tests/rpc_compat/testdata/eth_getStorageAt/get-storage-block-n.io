@@ -0,0 +1,3 @@
+// gets the slot at the block that deployed the contract, before the call wrote it
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getStorageAt","params":["0xdb7d6ab1f17c6b31909ae466702703daef9269cf","0x0","0x2"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000000"}
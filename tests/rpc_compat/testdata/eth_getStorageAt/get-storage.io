@@ -0,0 +1,3 @@
+// gets the slot written by the contract call
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getStorageAt","params":["0xdb7d6ab1f17c6b31909ae466702703daef9269cf","0x0","latest"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x4200000000000000000000000000000000000000000000000000000000000000"}
@@ -0,0 +1,3 @@
+// gets the first transaction of a block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByBlockHashAndIndex","params":["0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","0x0"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","blockNumber":"0x3","from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0xc350","gasPrice":"0x77359400","hash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","input":"0x4200000000000000000000000000000000000000000000000000000000000000","nonce":"0x2","to":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","transactionIndex":"0x0","value":"0x0","type":"0x0","chainId":"0x539","v":"0xa95","r":"0x556675f65b35d33201b1d755594fb590817de0f9c7adfd6c76fe033736181f3f","s":"0x43ec4e9fb4b1beeb56da17d4081b3521d8fafe3745024c24dbb5276b1c35b9e7"}}
@@ -0,0 +1,3 @@
+// gets the second transaction of a block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByBlockNumberAndIndex","params":["0x3","0x1"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","blockNumber":"0x3","from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0x5208","gasPrice":"0x63909514","maxFeePerGas":"0xb2d05e00","maxPriorityFeePerGas":"0x3b9aca00","hash":"0xa10c32e23a3324dff72dfcdeea4f39e71abbdad18d4106937a93d23b1ccdc9db","input":"0x","nonce":"0x3","to":"0x0000000000000000000000000000000000000aaa","transactionIndex":"0x1","value":"0x7d0","type":"0x2","accessList":[],"chainId":"0x539","v":"0x0","r":"0xd2693a3362ae2e30768fe3c9301cfa4bb6287fb85a622f1b940e6d245544559","s":"0x1a9bd4070b2005245a2cb80bee78883635b73fca1d756e5d72ae3c138a09277b","yParity":"0x0"}}
@@ -0,0 +1,3 @@
+// gets a dynamic fee contract creation
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x663a15acf7a160936a2b3eb00f65490ba28ab60ae65083279b7a17ed438d410f"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e","blockNumber":"0x2","from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0x186a0","gasPrice":"0x693fa2cd","maxFeePerGas":"0xb2d05e00","maxPriorityFeePerGas":"0x3b9aca00","hash":"0x663a15acf7a160936a2b3eb00f65490ba28ab60ae65083279b7a17ed438d410f","input":"0x6012600c60003960126000f360003580600055600052602a60206000a100","nonce":"0x1","to":null,"transactionIndex":"0x0","value":"0x0","type":"0x2","accessList":[],"chainId":"0x539","v":"0x0","r":"0xfbbd176dda8fc525d2561195efb2f4924f0e1544064252e5294af02db7955f7c","s":"0x4bd275e510dec33a48c2754a9cc89b66654e3ea32298ea7a50f305405017bee5","yParity":"0x0"}}
@@ -0,0 +1,3 @@
+// gets a legacy transfer
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x5668d876baf2981cc4a950a1730e6c30a5a3024ff79de46cb8a70cd0d20be63c"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0x5cc140b799b126c3206470593f540fa8f6aae79dea82ed021f9628b4a08fa950","blockNumber":"0x1","from":"0x71562b71999873db5b286df957af199ec94617f7","gas":"0x5208","gasPrice":"0x77359400","hash":"0x5668d876baf2981cc4a950a1730e6c30a5a3024ff79de46cb8a70cd0d20be63c","input":"0x","nonce":"0x0","to":"0x0000000000000000000000000000000000000aaa","transactionIndex":"0x0","value":"0x3e8","type":"0x0","chainId":"0x539","v":"0xa95","r":"0x13ed10c7784cbddd6d58243151919fb716b9dd365db018deed2f088ce70e1eb2","s":"0x259b54e9fb06d83e29af08ea6e8c419ba876d9b449ef184370522496df9f3e66"}}
@@ -0,0 +1,3 @@
+// gets an unknown transaction
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"]}
+<< {"jsonrpc":"2.0","id":1,"result":null}
@@ -0,0 +1,3 @@
+// gets the nonce of the sender at an older block
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionCount","params":["0x71562b71999873db5b286df957af199ec94617f7","0x1"]}
+<< {"jsonrpc":"2.0","id":1,"result":"0x1"}
@@ -0,0 +1,3 @@
+// gets the receipt of a contract creation
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0x663a15acf7a160936a2b3eb00f65490ba28ab60ae65083279b7a17ed438d410f"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0xef7831845801910584439441d84a538e1e65d42981d1b72f246ece2d6fea068e","blockNumber":"0x2","contractAddress":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","cumulativeGasUsed":"0xdebc","effectiveGasPrice":"0x693fa2cd","from":"0x71562b71999873db5b286df957af199ec94617f7","gasUsed":"0xdebc","logs":[],"logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","status":"0x1","to":null,"transactionHash":"0x663a15acf7a160936a2b3eb00f65490ba28ab60ae65083279b7a17ed438d410f","transactionIndex":"0x0","type":"0x2"}}
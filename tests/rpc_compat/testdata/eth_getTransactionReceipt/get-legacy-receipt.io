@@ -0,0 +1,3 @@
+// gets the receipt of a transfer
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0x5668d876baf2981cc4a950a1730e6c30a5a3024ff79de46cb8a70cd0d20be63c"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0x5cc140b799b126c3206470593f540fa8f6aae79dea82ed021f9628b4a08fa950","blockNumber":"0x1","contractAddress":null,"cumulativeGasUsed":"0x5208","effectiveGasPrice":"0x77359400","from":"0x71562b71999873db5b286df957af199ec94617f7","gasUsed":"0x5208","logs":[],"logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000","status":"0x1","to":"0x0000000000000000000000000000000000000aaa","transactionHash":"0x5668d876baf2981cc4a950a1730e6c30a5a3024ff79de46cb8a70cd0d20be63c","transactionIndex":"0x0","type":"0x0"}}
@@ -0,0 +1,3 @@
+// gets the receipt of a call emitting a log
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570"]}
+<< {"jsonrpc":"2.0","id":1,"result":{"blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","blockNumber":"0x3","contractAddress":null,"cumulativeGasUsed":"0xacf4","effectiveGasPrice":"0x77359400","from":"0x71562b71999873db5b286df957af199ec94617f7","gasUsed":"0xacf4","logs":[{"address":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","topics":["0x000000000000000000000000000000000000000000000000000000000000002a"],"data":"0x4200000000000000000000000000000000000000000000000000000000000000","blockNumber":"0x3","transactionHash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","transactionIndex":"0x0","blockHash":"0x8d5bc023433035fee1135a4f5bd76500c53d08920203ced0c06b00c26b8dd98c","logIndex":"0x0","removed":false}],"logsBloom":"0x00000000000000000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010200000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000100001000000000000000000000020000000000000000000000000000000000000","status":"0x1","to":"0xdb7d6ab1f17c6b31909ae466702703daef9269cf","transactionHash":"0x701070c6c6dc504feb04b1cfeaa1c32b59e64de97814431ba0982bd3ceb7c570","transactionIndex":"0x0","type":"0x0"}}
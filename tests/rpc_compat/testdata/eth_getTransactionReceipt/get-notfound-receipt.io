@@ -0,0 +1,3 @@
+// gets the receipt of an unknown transaction
+>> {"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"]}
+<< {"jsonrpc":"2.0","id":1,"result":null}
@@ -0,0 +1,72 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpccompat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/internal/witness"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// TestExecutionWitness fetches the witness of every block over RPC and
+// re-executes the block from the witness alone.
+func TestExecutionWitness(t *testing.T) {
+	n := newTestNode(t)
+	for number := uint64(1); number <= 3; number++ {
+		resp, err := n.call(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"debug_executionWitness","params":["0x%x"]}`, number))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result struct {
+			Result *state.EntireCode `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(resp), &result); err != nil {
+			t.Fatalf("block %d: %v", number, err)
+		}
+		if result.Error != nil {
+			t.Fatalf("block %d: %s", number, result.Error.Message)
+		}
+		ec := result.Result
+		want := n.bc.GetHeaderByNumber(uint256.NewInt(number)).(*block.Header).Root
+		root, err := witness.Verify(context.Background(), n.config, ec)
+		if err != nil {
+			t.Fatalf("block %d: verify: %v", number, err)
+		}
+		if root != want {
+			t.Errorf("block %d: root from witness %v, want %v", number, root, want)
+		}
+
+		// A witness missing an entry must not verify
+		if len(ec.Entire.Snap.Items) == 0 {
+			t.Fatalf("block %d: empty witness", number)
+		}
+		ec.Entire.Snap.Items = ec.Entire.Snap.Items[1:]
+		if root, err := witness.Verify(context.Background(), n.config, ec); err == nil && root == want {
+			t.Errorf("block %d: incomplete witness verified", number)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// daemonConfig configures continuous sampling
+type daemonConfig struct {
+	Datadir  string
+	Tables   bool // Include per-table MDBX statistics
+	PprofURL string
+	RPCURL   string
+	Output   string        // JSON lines file to append to, empty for stdout
+	Listen   string        // Address serving the latest sample on /metrics, empty to disable
+	Interval time.Duration // Time between two samples
+}
+
+// runDaemon samples the node every interval until interrupted. Every sample
+// is appended as one JSON line, so a restarted collector extends the same
+// file, and the latest sample can be scraped in Prometheus text format.
+func runDaemon(cfg daemonConfig) error {
+	var out io.Writer = os.Stdout
+	if cfg.Output != "" {
+		f, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	var latest atomic.Pointer[Metrics]
+	if cfg.Listen != "" {
+		ln, err := net.Listen("tcp", cfg.Listen)
+		if err != nil {
+			return err
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			m := latest.Load()
+			if m == nil {
+				http.Error(w, "no sample collected yet", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			writePrometheus(w, m)
+		})
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go srv.Serve(ln)
+		defer srv.Close()
+		fmt.Fprintf(os.Stderr, "Serving latest sample on http://%s/metrics\n", ln.Addr())
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	fmt.Fprintf(os.Stderr, "Sampling every %s, press Ctrl-C to stop\n", cfg.Interval)
+	encoder := json.NewEncoder(out)
+	for samples := 1; ; samples++ {
+		m := collectMetrics(cfg.Datadir, cfg.Tables, cfg.PprofURL, cfg.RPCURL)
+		latest.Store(m)
+		if err := encoder.Encode(m); err != nil {
+			return fmt.Errorf("write sample: %w", err)
+		}
+		printSample(m)
+
+		select {
+		case <-ticker.C:
+		case <-sigCh:
+			fmt.Fprintf(os.Stderr, "Stopped after %d samples\n", samples)
+			return nil
+		}
+	}
+}
+
+// printSample reports one sample as a single status line
+func printSample(m *Metrics) {
+	line := []string{m.Timestamp.Format(time.RFC3339)}
+	if m.Node.BlockNumber != "" {
+		line = append(line, "block="+m.Node.BlockNumber, fmt.Sprintf("peers=%d", m.Node.PeerCount))
+	}
+	if m.Database.TotalSize > 0 {
+		line = append(line, "db="+m.Database.TotalSizeStr)
+	}
+	if m.Memory.HeapAlloc > 0 {
+		line = append(line, "heap="+formatBytes(int64(m.Memory.HeapAlloc)), fmt.Sprintf("goroutines=%d", m.Goroutines))
+	}
+	if len(m.CollectError) > 0 {
+		line = append(line, fmt.Sprintf("errors=%d", len(m.CollectError)))
+	}
+	fmt.Fprintln(os.Stderr, strings.Join(line, " "))
+}
+
+// writePrometheus writes a sample as gauges in the Prometheus text format
+func writePrometheus(w io.Writer, m *Metrics) {
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	var syncing float64
+	if m.Node.Syncing {
+		syncing = 1
+	}
+	block, _ := strconv.ParseUint(strings.TrimPrefix(m.Node.BlockNumber, "0x"), 16, 64)
+
+	gauge("n42_bench_sample_timestamp_seconds", "Time the sample was taken", float64(m.Timestamp.UnixMilli())/1000)
+	gauge("n42_bench_collect_errors", "Sources that failed in the sample", float64(len(m.CollectError)))
+	gauge("n42_bench_block_number", "Head block reported by eth_blockNumber", float64(block))
+	gauge("n42_bench_peers", "Peers reported by net_peerCount", float64(m.Node.PeerCount))
+	gauge("n42_bench_syncing", "Whether eth_syncing reports a sync in progress", syncing)
+	gauge("n42_bench_datadir_bytes", "Disk usage of the data directory", float64(m.Database.TotalSize))
+	gauge("n42_bench_datadir_allocated_bytes", "Disk blocks allocated by the data directory", float64(m.Database.Allocated))
+	gauge("n42_bench_datadir_files", "Files in the data directory", float64(m.Database.Files))
+	if c := m.Database.Chaindata; c != nil {
+		gauge("n42_bench_chaindata_used_bytes", "Pages allocated in the chain database", float64(c.UsedSize))
+		gauge("n42_bench_chaindata_tables_bytes", "Pages referenced by chain database tables", float64(c.TablesSize))
+		fmt.Fprintf(w, "# HELP n42_bench_table_bytes Size of a chain database table\n# TYPE n42_bench_table_bytes gauge\n")
+		for _, t := range c.Tables {
+			fmt.Fprintf(w, "n42_bench_table_bytes{table=%q} %d\n", t.Name, t.Size)
+		}
+		fmt.Fprintf(w, "# HELP n42_bench_table_entries Entries in a chain database table\n# TYPE n42_bench_table_entries gauge\n")
+		for _, t := range c.Tables {
+			fmt.Fprintf(w, "n42_bench_table_entries{table=%q} %d\n", t.Name, t.Entries)
+		}
+	}
+	gauge("n42_bench_heap_alloc_bytes", "Bytes of allocated heap objects of the node", float64(m.Memory.HeapAlloc))
+	gauge("n42_bench_heap_inuse_bytes", "In-use heap spans of the node", float64(m.Memory.HeapInuse))
+	gauge("n42_bench_heap_objects", "Allocated heap objects of the node", float64(m.Memory.HeapObjects))
+	gauge("n42_bench_sys_bytes", "Memory obtained from the OS by the node", float64(m.Memory.Sys))
+	gauge("n42_bench_gc_cycles", "Completed GC cycles of the node", float64(m.Memory.NumGC))
+	gauge("n42_bench_goroutines", "Goroutines of the node", float64(m.Goroutines))
+}
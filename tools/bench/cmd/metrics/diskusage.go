@@ -0,0 +1,78 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// diskUsage is the space taken by a directory tree
+type diskUsage struct {
+	Apparent    int64  // Sum of file sizes, as reported by du -sb
+	Allocated   int64  // Blocks actually allocated, smaller than Apparent for sparse files
+	Files       int    // Regular files, hard links counted once
+	Symlinks    int    // Symbolic links, which are not followed
+	LargestFile string // Path of the largest file by apparent size
+	LargestSize int64
+}
+
+// walkDiskUsage adds up the files below root. Symbolic links are counted but
+// not followed, so a link to another volume does not inflate the total, and
+// files reachable through several hard links are only counted once.
+// Unreadable entries are skipped like du does.
+func walkDiskUsage(root string) (*diskUsage, error) {
+	usage := &diskUsage{}
+	seen := make(map[fileID]struct{})
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			usage.Symlinks++
+			return nil
+		case !d.Type().IsRegular():
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		allocated, id, linked := fileAllocation(info)
+		if linked {
+			if _, ok := seen[id]; ok {
+				return nil
+			}
+			seen[id] = struct{}{}
+		}
+		usage.Files++
+		usage.Apparent += info.Size()
+		usage.Allocated += allocated
+		if info.Size() > usage.LargestSize {
+			usage.LargestFile, usage.LargestSize = path, info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
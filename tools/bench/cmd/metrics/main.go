@@ -19,22 +19,25 @@
 // This tool collects system and node metrics for baseline comparison.
 //
 // Usage:
-//   go run bench_metrics.go -datadir /path/to/n42/data -pprof http://localhost:6060
 //
+//	go run bench_metrics.go -datadir /path/to/n42/data -pprof http://localhost:6060
+//	go run bench_metrics.go -datadir /path/to/n42/data -interval 30s -output sync.jsonl -listen :9101
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/n42blockchain/N42/modules/ethdb/dbstat"
 )
 
 // Metrics holds all collected metrics
@@ -46,16 +49,28 @@ type Metrics struct {
 	Memory       MemoryMetrics   `json:"memory"`
 	Goroutines   int             `json:"goroutines"`
 	Version      string          `json:"version"`
+	Profiles     *ProfileCapture `json:"profiles,omitempty"`
 	CollectError []string        `json:"collect_errors,omitempty"`
 }
 
+// ProfileCapture records the pprof artifacts stored alongside a snapshot
+type ProfileCapture struct {
+	CPUProfile      string        `json:"cpu_profile,omitempty"`
+	CPUDuration     time.Duration `json:"cpu_duration_ns,omitempty"`
+	HeapProfile     string        `json:"heap_profile,omitempty"`
+	GoroutineDump   string        `json:"goroutine_dump,omitempty"`
+	FlamegraphHint  string        `json:"flamegraph_hint,omitempty"`
+	CaptureStarted  time.Time     `json:"capture_started"`
+	CaptureFinished time.Time     `json:"capture_finished"`
+}
+
 // SystemMetrics holds system-level metrics
 type SystemMetrics struct {
-	OS          string `json:"os"`
-	Arch        string `json:"arch"`
-	NumCPU      int    `json:"num_cpu"`
-	GoVersion   string `json:"go_version"`
-	Hostname    string `json:"hostname,omitempty"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	NumCPU    int    `json:"num_cpu"`
+	GoVersion string `json:"go_version"`
+	Hostname  string `json:"hostname,omitempty"`
 }
 
 // NodeMetrics holds N42 node metrics
@@ -69,11 +84,25 @@ type NodeMetrics struct {
 
 // DatabaseMetrics holds database metrics
 type DatabaseMetrics struct {
-	TotalSize    int64  `json:"total_size_bytes"`
-	TotalSizeStr string `json:"total_size_human"`
-	Files        int    `json:"files"`
-	LargestFile  string `json:"largest_file,omitempty"`
-	LargestSize  int64  `json:"largest_size_bytes,omitempty"`
+	TotalSize    int64             `json:"total_size_bytes"`
+	TotalSizeStr string            `json:"total_size_human"`
+	Allocated    int64             `json:"allocated_bytes"` // Disk blocks in use, less than the total for sparse files
+	Files        int               `json:"files"`
+	Symlinks     int               `json:"symlinks,omitempty"`
+	LargestFile  string            `json:"largest_file,omitempty"`
+	LargestSize  int64             `json:"largest_size_bytes,omitempty"`
+	Chaindata    *ChaindataMetrics `json:"chaindata,omitempty"`
+}
+
+// ChaindataMetrics holds MDBX statistics of the chain database
+type ChaindataMetrics struct {
+	PageSize   uint64             `json:"page_size"`
+	FileSize   uint64             `json:"file_size_bytes"`
+	UsedSize   uint64             `json:"used_bytes"`
+	MapSize    uint64             `json:"map_size_bytes"`
+	TablesSize uint64             `json:"tables_bytes"`
+	Readers    uint               `json:"readers"`
+	Tables     []dbstat.TableStat `json:"tables"`
 }
 
 // MemoryMetrics holds memory metrics from pprof
@@ -97,9 +126,51 @@ func main() {
 	pprofURL := flag.String("pprof", "", "pprof endpoint URL (e.g., http://localhost:6060)")
 	rpcURL := flag.String("rpc", "http://localhost:8545", "RPC URL")
 	output := flag.String("output", "", "Output file (empty for stdout)")
+	profile := flag.Bool("profile", false, "Capture a CPU profile, heap profile and goroutine dump from -pprof")
+	profileDuration := flag.Duration("profile-duration", 30*time.Second, "CPU profile duration")
+	profileDir := flag.String("profile-dir", "", "Directory for captured profiles (default: next to -output, or current directory)")
+	tables := flag.Bool("tables", true, "Include per-table MDBX statistics of the chain database in -datadir")
+	interval := flag.Duration("interval", 0, "Sample continuously at this interval, appending one JSON line per sample (0 for a single snapshot)")
+	listen := flag.String("listen", "", "With -interval, serve the latest sample on /metrics at this address (e.g. :9101)")
 	flag.Parse()
 
-	metrics := collectMetrics(*datadir, *pprofURL, *rpcURL)
+	if *interval > 0 {
+		if *profile {
+			fmt.Fprintf(os.Stderr, "-profile cannot be combined with -interval\n")
+			os.Exit(2)
+		}
+		err := runDaemon(daemonConfig{
+			Datadir:  *datadir,
+			Tables:   *tables,
+			PprofURL: *pprofURL,
+			RPCURL:   *rpcURL,
+			Output:   *output,
+			Listen:   *listen,
+			Interval: *interval,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *listen != "" {
+		fmt.Fprintf(os.Stderr, "-listen requires -interval\n")
+		os.Exit(2)
+	}
+
+	metrics := collectMetrics(*datadir, *tables, *pprofURL, *rpcURL)
+
+	if *profile {
+		if *pprofURL == "" {
+			metrics.CollectError = append(metrics.CollectError, "profile: -pprof URL is required")
+		} else {
+			dir, base := profileLocation(*output, *profileDir, metrics.Timestamp)
+			capture, errs := captureProfiles(*pprofURL, dir, base, *profileDuration)
+			metrics.Profiles = capture
+			metrics.CollectError = append(metrics.CollectError, errs...)
+		}
+	}
 
 	// Output results
 	var out io.Writer = os.Stdout
@@ -125,7 +196,7 @@ func main() {
 	printSummary(metrics)
 }
 
-func collectMetrics(datadir, pprofURL, rpcURL string) *Metrics {
+func collectMetrics(datadir string, tables bool, pprofURL, rpcURL string) *Metrics {
 	metrics := &Metrics{
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
@@ -150,6 +221,14 @@ func collectMetrics(datadir, pprofURL, rpcURL string) *Metrics {
 		} else {
 			metrics.Database = *dbMetrics
 		}
+		if tables {
+			chaindata, err := collectChaindataMetrics(datadir)
+			if err != nil {
+				metrics.CollectError = append(metrics.CollectError, fmt.Sprintf("chaindata: %v", err))
+			} else {
+				metrics.Database.Chaindata = chaindata
+			}
+		}
 	}
 
 	// Node metrics from RPC
@@ -177,44 +256,43 @@ func collectMetrics(datadir, pprofURL, rpcURL string) *Metrics {
 }
 
 func collectDatabaseMetrics(datadir string) (*DatabaseMetrics, error) {
-	metrics := &DatabaseMetrics{}
-
-	// Use du command for total size (more accurate for MDBX)
-	cmd := exec.Command("du", "-sb", datadir)
-	output, err := cmd.Output()
-	if err == nil {
-		parts := strings.Fields(string(output))
-		if len(parts) > 0 {
-			fmt.Sscanf(parts[0], "%d", &metrics.TotalSize)
-		}
+	usage, err := walkDiskUsage(datadir)
+	if err != nil {
+		return nil, err
 	}
+	return &DatabaseMetrics{
+		TotalSize:    usage.Apparent,
+		TotalSizeStr: formatBytes(usage.Apparent),
+		Allocated:    usage.Allocated,
+		Files:        usage.Files,
+		Symlinks:     usage.Symlinks,
+		LargestFile:  usage.LargestFile,
+		LargestSize:  usage.LargestSize,
+	}, nil
+}
 
-	// Walk directory for file count and largest file
-	var largestSize int64
-	var largestFile string
-
-	err = filepath.Walk(datadir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors
-		}
-		if !info.IsDir() {
-			metrics.Files++
-			if info.Size() > largestSize {
-				largestSize = info.Size()
-				largestFile = path
-			}
-		}
-		return nil
-	})
+// collectChaindataMetrics reads the MDBX statistics of every table. The
+// database is opened read-only, which works next to a running node.
+func collectChaindataMetrics(datadir string) (*ChaindataMetrics, error) {
+	db, err := dbstat.OpenReadonly(datadir)
 	if err != nil {
 		return nil, err
 	}
+	defer db.Close()
 
-	metrics.LargestFile = largestFile
-	metrics.LargestSize = largestSize
-	metrics.TotalSizeStr = formatBytes(metrics.TotalSize)
-
-	return metrics, nil
+	stat, err := dbstat.Summary(context.Background(), db)
+	if err != nil {
+		return nil, err
+	}
+	return &ChaindataMetrics{
+		PageSize:   stat.PageSize,
+		FileSize:   stat.FileSize,
+		UsedSize:   stat.UsedSize,
+		MapSize:    stat.MapSize,
+		TablesSize: stat.TablesSize,
+		Readers:    stat.Readers,
+		Tables:     stat.Tables,
+	}, nil
 }
 
 func collectNodeMetrics(rpcURL string) (*NodeMetrics, error) {
@@ -331,6 +409,90 @@ func collectMemoryMetrics(pprofURL string) (*MemoryMetrics, int, error) {
 	return metrics, goroutines, nil
 }
 
+// profileLocation derives where profile artifacts are written: they share the
+// output file's base name so a snapshot and its profiles stay together.
+func profileLocation(output, profileDir string, ts time.Time) (string, string) {
+	base := "metrics-" + ts.Format("20060102-150405")
+	dir := "."
+	if output != "" {
+		dir = filepath.Dir(output)
+		base = strings.TrimSuffix(filepath.Base(output), filepath.Ext(output))
+	}
+	if profileDir != "" {
+		dir = profileDir
+	}
+	return dir, base
+}
+
+// captureProfiles fetches a CPU profile, a heap profile and a full goroutine
+// dump from the node's pprof endpoint. Failures are reported per artifact so a
+// partial capture is still stored.
+func captureProfiles(pprofURL, dir, base string, duration time.Duration) (*ProfileCapture, []string) {
+	var errs []string
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, []string{fmt.Sprintf("profile: %v", err)}
+	}
+	capture := &ProfileCapture{CaptureStarted: time.Now()}
+
+	// The goroutine dump and heap profile are taken before the CPU profile so
+	// they reflect the same moment as the snapshot above.
+	goroutinePath := filepath.Join(dir, base+".goroutines.txt")
+	if err := fetchToFile(fmt.Sprintf("%s/debug/pprof/goroutine?debug=2", pprofURL), goroutinePath, 30*time.Second); err != nil {
+		errs = append(errs, fmt.Sprintf("goroutine dump: %v", err))
+	} else {
+		capture.GoroutineDump = goroutinePath
+	}
+
+	heapPath := filepath.Join(dir, base+".heap.pprof")
+	if err := fetchToFile(fmt.Sprintf("%s/debug/pprof/heap", pprofURL), heapPath, 30*time.Second); err != nil {
+		errs = append(errs, fmt.Sprintf("heap profile: %v", err))
+	} else {
+		capture.HeapProfile = heapPath
+	}
+
+	seconds := int(duration.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	fmt.Fprintf(os.Stderr, "Capturing %ds CPU profile from %s...\n", seconds, pprofURL)
+	cpuPath := filepath.Join(dir, base+".cpu.pprof")
+	cpuURL := fmt.Sprintf("%s/debug/pprof/profile?seconds=%d", pprofURL, seconds)
+	if err := fetchToFile(cpuURL, cpuPath, time.Duration(seconds)*time.Second+30*time.Second); err != nil {
+		errs = append(errs, fmt.Sprintf("cpu profile: %v", err))
+	} else {
+		capture.CPUProfile = cpuPath
+		capture.CPUDuration = time.Duration(seconds) * time.Second
+		capture.FlamegraphHint = fmt.Sprintf("go tool pprof -http=:8080 %s", cpuPath)
+	}
+
+	capture.CaptureFinished = time.Now()
+	return capture, errs
+}
+
+// fetchToFile streams an HTTP response body into path
+func fetchToFile(url, path string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
 func rpcCall(client *http.Client, url, method string, params []interface{}) (interface{}, error) {
 	reqBody, _ := json.Marshal(map[string]interface{}{
 		"jsonrpc": "2.0",
@@ -392,7 +554,19 @@ func printSummary(metrics *Metrics) {
 	}
 
 	if metrics.Database.TotalSize > 0 {
-		fmt.Fprintf(os.Stderr, "Database Size: %s (%d files)\n", metrics.Database.TotalSizeStr, metrics.Database.Files)
+		fmt.Fprintf(os.Stderr, "Database Size: %s (%d files, %s allocated)\n",
+			metrics.Database.TotalSizeStr, metrics.Database.Files, formatBytes(metrics.Database.Allocated))
+	}
+	if c := metrics.Database.Chaindata; c != nil {
+		fmt.Fprintf(os.Stderr, "Chaindata:     %s in %d tables, %s of %s used\n",
+			formatBytes(int64(c.TablesSize)), len(c.Tables), formatBytes(int64(c.UsedSize)), formatBytes(int64(c.MapSize)))
+		// Tables are sorted by size, largest first
+		for i, t := range c.Tables {
+			if i == 5 || t.Size == 0 {
+				break
+			}
+			fmt.Fprintf(os.Stderr, "  %-26s %10s %12d entries\n", t.Name, formatBytes(int64(t.Size)), t.Entries)
+		}
 	}
 
 	if metrics.Memory.HeapAlloc > 0 {
@@ -405,9 +579,23 @@ func printSummary(metrics *Metrics) {
 		fmt.Fprintf(os.Stderr, "Goroutines:    %d\n", metrics.Goroutines)
 	}
 
+	if p := metrics.Profiles; p != nil {
+		if p.CPUProfile != "" {
+			fmt.Fprintf(os.Stderr, "CPU Profile:   %s\n", p.CPUProfile)
+		}
+		if p.HeapProfile != "" {
+			fmt.Fprintf(os.Stderr, "Heap Profile:  %s\n", p.HeapProfile)
+		}
+		if p.GoroutineDump != "" {
+			fmt.Fprintf(os.Stderr, "Goroutines:    %s\n", p.GoroutineDump)
+		}
+		if p.FlamegraphHint != "" {
+			fmt.Fprintf(os.Stderr, "Flamegraph:    %s\n", p.FlamegraphHint)
+		}
+	}
+
 	if len(metrics.CollectError) > 0 {
 		fmt.Fprintf(os.Stderr, "Errors:        %v\n", metrics.CollectError)
 	}
 	fmt.Fprintf(os.Stderr, "================================================================================\n")
 }
-
@@ -0,0 +1,86 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/n42blockchain/N42/internal/vm"
+)
+
+// precompiles returns the precompiles of the latest fork in address order
+func precompiles() []*precompile {
+	return []*precompile{
+		{name: "ecrecover", contract: vm.GetEcrecover(), vectors: "ecRecover.json"},
+		{name: "sha256", contract: vm.GetSha256(), generate: sizedInputs},
+		{name: "ripemd160", contract: vm.GetRipemd160(), generate: sizedInputs},
+		{name: "identity", contract: vm.GetDataCopy(), generate: sizedInputs},
+		{name: "modexp", contract: vm.GetBigModExp(true), vectors: "modexp_eip2565.json"},
+		{name: "bn256Add", contract: vm.GetBn256Add(true), vectors: "bn256Add.json"},
+		{name: "bn256ScalarMul", contract: vm.GetBn256ScalarMul(true), vectors: "bn256ScalarMul.json"},
+		{name: "bn256Pairing", contract: vm.GetBn256Pairing(true), vectors: "bn256Pairing.json"},
+		{name: "blake2f", contract: vm.GetBlake2F(), vectors: "blake2F.json"},
+		{name: "blsG1Add", contract: vm.GetBls12381G1Add(), vectors: "blsG1Add.json"},
+		{name: "blsG1Mul", contract: vm.GetBls12381G1Mul(), vectors: "blsG1Mul.json"},
+		{name: "blsG1MultiExp", contract: vm.GetBls12381G1MultiExp(), vectors: "blsG1MultiExp.json"},
+		{name: "blsG2Add", contract: vm.GetBls12381G2Add(), vectors: "blsG2Add.json"},
+		{name: "blsG2Mul", contract: vm.GetBls12381G2Mul(), vectors: "blsG2Mul.json"},
+		{name: "blsG2MultiExp", contract: vm.GetBls12381G2MultiExp(), vectors: "blsG2MultiExp.json"},
+		{name: "blsPairing", contract: vm.GetBls12381Pairing(), vectors: "blsPairing.json"},
+		{name: "blsMapG1", contract: vm.GetBls12381MapG1(), vectors: "blsMapG1.json"},
+		{name: "blsMapG2", contract: vm.GetBls12381MapG2(), vectors: "blsMapG2.json"},
+		{name: "p256Verify", contract: vm.GetP256Verify(), generate: p256VerifyInputs},
+	}
+}
+
+// sizedInputs returns inputs of growing size for the hashing precompiles,
+// whose price depends on the input length only
+func sizedInputs() ([]benchInput, error) {
+	var inputs []benchInput
+	for _, size := range []int{0, 32, 128, 1024, 8192} {
+		input := make([]byte, size)
+		for i := range input {
+			input[i] = byte(i)
+		}
+		inputs = append(inputs, benchInput{Name: fmt.Sprintf("%d bytes", size), Input: input})
+	}
+	return inputs, nil
+}
+
+// p256VerifyInputs returns a valid secp256r1 signature check
+func p256VerifyInputs() ([]benchInput, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte("n42"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	input := make([]byte, 160)
+	copy(input, hash[:])
+	r.FillBytes(input[32:64])
+	s.FillBytes(input[64:96])
+	key.X.FillBytes(input[96:128])
+	key.Y.FillBytes(input[128:160])
+	return []benchInput{{Name: "valid signature", Input: input}}, nil
+}
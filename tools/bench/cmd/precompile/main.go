@@ -0,0 +1,289 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// bench_precompile - Precompile Benchmark and Gas Calibration Tool for N42
+//
+// This tool runs every precompiled contract of the EVM on representative
+// inputs and compares the measured running time with the gas the contract
+// charges. A correctly priced precompile processes roughly as much gas per
+// second as the others; inputs far below or above the target throughput are
+// flagged as underpriced (a denial of service risk) or overpriced.
+//
+// Usage:
+//
+//	go run ./cmd/precompile
+//	go run ./cmd/precompile -run 'bls|p256' -duration 500ms
+//	go run ./cmd/precompile -target 60 -tolerance 2 -format json -output precompiles.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/internal/vm"
+)
+
+// Pricing statuses of a measured input
+const (
+	statusOK          = "ok"
+	statusUnderpriced = "underpriced" // Slower than the target throughput
+	statusOverpriced  = "overpriced"  // Faster than the target throughput
+	statusFailed      = "failed"      // The precompile rejected the input
+)
+
+// precompile is a precompiled contract and where its inputs come from
+type precompile struct {
+	name     string
+	contract vm.PrecompiledContract
+	vectors  string                       // Test vector file of internal/vm, if any
+	generate func() ([]benchInput, error) // Generated inputs, if any
+}
+
+// benchInput is a named input of a precompile
+type benchInput struct {
+	Name  string
+	Input []byte
+}
+
+// vector is an entry of the precompile test vectors of internal/vm
+type vector struct {
+	Input       string
+	Name        string
+	NoBenchmark bool
+}
+
+// Result is the measurement of one precompile input
+type Result struct {
+	Precompile string  `json:"precompile"`
+	Input      string  `json:"input"`
+	Size       int     `json:"size"`
+	Gas        uint64  `json:"gas"`
+	NsPerOp    float64 `json:"ns_per_op"`
+	MGasPerSec float64 `json:"mgas_per_sec"`
+	Ratio      float64 `json:"ratio"` // Throughput relative to the target
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report is the result of a calibration run
+type Report struct {
+	Time      time.Time `json:"time"`
+	Target    float64   `json:"target_mgas_per_sec"`
+	Tolerance float64   `json:"tolerance"`
+	Results   []*Result `json:"results"`
+}
+
+func main() {
+	testdata := flag.String("testdata", filepath.Join("..", "..", "internal", "vm", "testdata", "precompiles"), "Directory of the precompile test vectors")
+	run := flag.String("run", "", "Only benchmark precompiles matching this regular expression")
+	duration := flag.Duration("duration", 100*time.Millisecond, "Minimum measuring time per input")
+	target := flag.Float64("target", 0, "Target throughput in Mgas/s (0 = throughput of the slowest ecrecover input)")
+	tolerance := flag.Float64("tolerance", 3, "Factor by which an input may deviate from the target before it is flagged")
+	format := flag.String("format", "text", "Output format: text or json")
+	output := flag.String("output", "", "Output file (default: stdout)")
+	flag.Parse()
+
+	if *tolerance < 1 {
+		fatalf("-tolerance must be at least 1")
+	}
+	if *format != "text" && *format != "json" {
+		fatalf("unknown format %q", *format)
+	}
+	var filter *regexp.Regexp
+	if *run != "" {
+		var err error
+		if filter, err = regexp.Compile(*run); err != nil {
+			fatalf("invalid -run: %v", err)
+		}
+	}
+
+	report := &Report{Time: time.Now(), Tolerance: *tolerance}
+	for _, p := range precompiles() {
+		if filter != nil && !filter.MatchString(p.name) {
+			continue
+		}
+		inputs, err := p.inputs(*testdata)
+		if err != nil {
+			fatalf("%s: %v", p.name, err)
+		}
+		for _, in := range inputs {
+			fmt.Fprintf(os.Stderr, "Benchmarking %s/%s\n", p.name, in.Name)
+			report.Results = append(report.Results, measure(p, in, *duration))
+		}
+	}
+	if len(report.Results) == 0 {
+		fatalf("no precompile matches %q", *run)
+	}
+
+	report.Target = *target
+	if report.Target == 0 {
+		report.Target = minThroughput(report.Results, "ecrecover")
+	}
+	classify(report)
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fatalf("%v", err)
+		}
+		return
+	}
+	printReport(out, report)
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// inputs returns the benchmark inputs of p
+func (p *precompile) inputs(testdata string) ([]benchInput, error) {
+	if p.generate != nil {
+		return p.generate()
+	}
+	data, err := os.ReadFile(filepath.Join(testdata, p.vectors))
+	if err != nil {
+		return nil, err
+	}
+	var vectors []vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", p.vectors, err)
+	}
+	inputs := make([]benchInput, 0, len(vectors))
+	for _, v := range vectors {
+		if v.NoBenchmark {
+			continue
+		}
+		input, err := hexutil.Decode("0x" + v.Input)
+		if err != nil {
+			return nil, fmt.Errorf("vector %s: %w", v.Name, err)
+		}
+		inputs = append(inputs, benchInput{Name: v.Name, Input: input})
+	}
+	return inputs, nil
+}
+
+// measure runs p on in repeatedly for at least d and reports the average
+// time per run
+func measure(p *precompile, in benchInput, d time.Duration) *Result {
+	r := &Result{Precompile: p.name, Input: in.Name, Size: len(in.Input), Gas: p.contract.RequiredGas(in.Input)}
+	if _, err := p.contract.Run(in.Input); err != nil {
+		r.Status, r.Error = statusFailed, err.Error()
+		return r
+	}
+	for n := 1; ; n *= 2 {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			p.contract.Run(in.Input)
+		}
+		if elapsed := time.Since(start); elapsed >= d || n >= 1<<30 {
+			r.NsPerOp = float64(elapsed.Nanoseconds()) / float64(n)
+			break
+		}
+	}
+	if r.NsPerOp > 0 {
+		r.MGasPerSec = float64(r.Gas) / r.NsPerOp * 1000
+	}
+	return r
+}
+
+// minThroughput returns the throughput of the slowest input of the named
+// precompile, or 0 if it was not measured. Invalid signatures make ecrecover
+// return early, so only its slowest input reflects the work it is priced for.
+func minThroughput(results []*Result, name string) float64 {
+	var min float64
+	for _, r := range results {
+		if r.Precompile == name && r.Status != statusFailed && (min == 0 || r.MGasPerSec < min) {
+			min = r.MGasPerSec
+		}
+	}
+	return min
+}
+
+// classify flags the inputs whose throughput deviates from the target by
+// more than the tolerance
+func classify(report *Report) {
+	for _, r := range report.Results {
+		if r.Status == statusFailed {
+			continue
+		}
+		r.Status = statusOK
+		if report.Target == 0 || r.Gas == 0 {
+			continue
+		}
+		r.Ratio = r.MGasPerSec / report.Target
+		switch {
+		case r.Ratio < 1/report.Tolerance:
+			r.Status = statusUnderpriced
+		case r.Ratio > report.Tolerance:
+			r.Status = statusOverpriced
+		}
+	}
+}
+
+func printReport(w io.Writer, report *Report) {
+	fmt.Fprintf(w, "\n=== Precompile Gas Calibration ===\n")
+	if report.Target == 0 {
+		fmt.Fprintf(w, "Target: none (ecrecover was not measured, pass -target)\n\n")
+	} else {
+		fmt.Fprintf(w, "Target: %.1f Mgas/s, tolerance x%.1f\n\n", report.Target, report.Tolerance)
+	}
+	fmt.Fprintf(w, "%-22s %-40s %8s %10s %14s %10s %7s  %s\n", "Precompile", "Input", "Size", "Gas", "ns/op", "Mgas/s", "Ratio", "Status")
+	fmt.Fprintf(w, "%s\n", strings.Repeat("-", 130))
+
+	var flagged []*Result
+	for _, r := range report.Results {
+		name := r.Input
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		if r.Status == statusFailed {
+			fmt.Fprintf(w, "%-22s %-40s %8d %10d %14s %10s %7s  %s: %s\n", r.Precompile, name, r.Size, r.Gas, "-", "-", "-", r.Status, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%-22s %-40s %8d %10d %14.0f %10.1f %7.2f  %s\n", r.Precompile, name, r.Size, r.Gas, r.NsPerOp, r.MGasPerSec, r.Ratio, r.Status)
+		if r.Status == statusUnderpriced || r.Status == statusOverpriced {
+			flagged = append(flagged, r)
+		}
+	}
+
+	if len(flagged) == 0 {
+		fmt.Fprintf(w, "\nNo mispriced inputs.\n")
+		return
+	}
+	fmt.Fprintf(w, "\n=== Mispriced Inputs ===\n")
+	for _, r := range flagged {
+		fmt.Fprintf(w, "%s/%s: %s, %.1f Mgas/s is x%.2f of the target\n", r.Precompile, r.Input, r.Status, r.MGasPerSec, r.Ratio)
+	}
+}
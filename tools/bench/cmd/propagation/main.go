@@ -0,0 +1,349 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// bench_propagation - Block Propagation Harness for N42
+//
+// This tool subscribes to newHeads on several nodes at once and measures, for
+// every block, how long after the first node each other node announced it.
+// All arrival times are taken from the harness clock, so the nodes' clocks do
+// not need to be synchronised. Use it to validate gossip changes by comparing
+// reports captured before and after a deployment.
+//
+// Usage:
+//
+//	go run ./cmd/propagation -nodes ws://10.0.0.1:8546,ws://10.0.0.2:8546 -duration 10m
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
+)
+
+// header is the subset of a newHeads notification the harness needs.
+type header struct {
+	Number *hexutil.Big `json:"number"`
+	Hash   string       `json:"hash"`
+}
+
+// arrival is a single block announcement observed on a node.
+type arrival struct {
+	node   int
+	number uint64
+	hash   string
+	at     time.Time
+}
+
+// blockRecord collects the arrival time of one block on every node.
+type blockRecord struct {
+	Number   uint64
+	Hash     string
+	First    time.Time
+	Arrivals map[int]time.Time
+}
+
+// DelayStats holds delay statistics relative to the first announcement.
+type DelayStats struct {
+	Name   string        `json:"name"`
+	Seen   int           `json:"seen"`
+	Missed int           `json:"missed"`
+	First  int           `json:"first"`
+	Min    time.Duration `json:"min"`
+	Avg    time.Duration `json:"avg"`
+	P50    time.Duration `json:"p50"`
+	P95    time.Duration `json:"p95"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+// Report is the result of a propagation run.
+type Report struct {
+	Started  time.Time    `json:"started"`
+	Finished time.Time    `json:"finished"`
+	Nodes    []string     `json:"nodes"`
+	Blocks   int          `json:"blocks"`
+	PerNode  []DelayStats `json:"per_node"`
+	Spread   DelayStats   `json:"spread"`
+}
+
+func main() {
+	nodesFlag := flag.String("nodes", "", "Comma separated websocket/IPC endpoints to subscribe to (at least two)")
+	duration := flag.Duration("duration", 5*time.Minute, "How long to observe the network")
+	maxBlocks := flag.Int("blocks", 0, "Stop after this many blocks have been seen (0 = no limit)")
+	settle := flag.Duration("settle", 10*time.Second, "Grace period for late arrivals after the last block")
+	output := flag.String("output", "", "Output file for the report (JSON)")
+	flag.Parse()
+
+	var nodes []string
+	for _, n := range strings.Split(*nodesFlag, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) < 2 {
+		fmt.Fprintln(os.Stderr, "at least two endpoints are required in -nodes")
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	report, err := run(ctx, nodes, *maxBlocks, *settle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "propagation run failed: %v\n", err)
+		os.Exit(1)
+	}
+	printReport(os.Stdout, report)
+
+	if *output != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to marshal report: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nReport written to: %s\n", *output)
+	}
+}
+
+// run subscribes to every node and collects arrivals until ctx is done or
+// maxBlocks blocks have been seen, then waits settle for stragglers.
+func run(ctx context.Context, nodes []string, maxBlocks int, settle time.Duration) (*Report, error) {
+	arrivals := make(chan arrival, 256)
+	subCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	var wg sync.WaitGroup
+	for i, endpoint := range nodes {
+		client, err := jsonrpc.DialContext(ctx, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("dial %s: %w", endpoint, err)
+		}
+		defer client.Close()
+
+		heads := make(chan *header, 64)
+		sub, err := client.Subscribe(ctx, "eth", heads, "newHeads")
+		if err != nil {
+			return nil, fmt.Errorf("subscribe %s: %w", endpoint, err)
+		}
+		defer sub.Unsubscribe()
+
+		wg.Add(1)
+		go func(node int, endpoint string) {
+			defer wg.Done()
+			for {
+				select {
+				case h := <-heads:
+					if h == nil || h.Number == nil {
+						continue
+					}
+					a := arrival{node: node, number: h.Number.ToInt().Uint64(), hash: h.Hash, at: time.Now()}
+					select {
+					case arrivals <- a:
+					case <-subCtx.Done():
+						return
+					}
+				case err := <-sub.Err():
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "subscription to %s ended: %v\n", endpoint, err)
+					}
+					return
+				case <-subCtx.Done():
+					return
+				}
+			}
+		}(i, endpoint)
+	}
+
+	report := &Report{Started: time.Now(), Nodes: nodes}
+	blocks := make(map[string]*blockRecord)
+	var order []*blockRecord
+
+	record := func(a arrival) {
+		b, ok := blocks[a.hash]
+		if !ok {
+			if maxBlocks > 0 && len(order) >= maxBlocks {
+				return
+			}
+			b = &blockRecord{Number: a.number, Hash: a.hash, First: a.at, Arrivals: make(map[int]time.Time)}
+			blocks[a.hash] = b
+			order = append(order, b)
+		}
+		if _, seen := b.Arrivals[a.node]; !seen {
+			b.Arrivals[a.node] = a.at
+		}
+	}
+
+	// Observe until the deadline or until enough blocks were seen.
+	for collecting := true; collecting; {
+		select {
+		case a := <-arrivals:
+			record(a)
+			if maxBlocks > 0 && len(order) >= maxBlocks {
+				collecting = false
+			}
+		case <-ctx.Done():
+			collecting = false
+		}
+	}
+
+	// Give slower nodes a chance to announce blocks that are already tracked.
+	grace := time.NewTimer(settle)
+	for waiting := true; waiting; {
+		select {
+		case a := <-arrivals:
+			if _, ok := blocks[a.hash]; ok {
+				record(a)
+			}
+		case <-grace.C:
+			waiting = false
+		}
+	}
+	stop()
+	wg.Wait()
+
+	report.Finished = time.Now()
+	report.Blocks = len(order)
+	report.PerNode, report.Spread = analyze(nodes, order)
+	return report, nil
+}
+
+// analyze computes, per node, the delay between the first announcement of a
+// block anywhere and its announcement on that node, plus the overall spread
+// between the first and the last node for fully propagated blocks.
+func analyze(nodes []string, blocks []*blockRecord) ([]DelayStats, DelayStats) {
+	perNode := make([][]time.Duration, len(nodes))
+	stats := make([]DelayStats, len(nodes))
+	var spreads []time.Duration
+
+	for i := range nodes {
+		stats[i].Name = nodes[i]
+	}
+	for _, b := range blocks {
+		var last time.Time
+		for i := range nodes {
+			at, ok := b.Arrivals[i]
+			if !ok {
+				stats[i].Missed++
+				continue
+			}
+			stats[i].Seen++
+			if at.Equal(b.First) {
+				stats[i].First++
+			}
+			perNode[i] = append(perNode[i], at.Sub(b.First))
+			if at.After(last) {
+				last = at
+			}
+		}
+		if len(b.Arrivals) == len(nodes) {
+			spreads = append(spreads, last.Sub(b.First))
+		}
+	}
+
+	for i := range nodes {
+		fillDelays(&stats[i], perNode[i])
+	}
+	spread := DelayStats{Name: "spread", Seen: len(spreads), Missed: len(blocks) - len(spreads)}
+	fillDelays(&spread, spreads)
+	return stats, spread
+}
+
+func fillDelays(stats *DelayStats, delays []time.Duration) {
+	if len(delays) == 0 {
+		return
+	}
+	sorted := make([]time.Duration, len(delays))
+	copy(sorted, delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Avg = total / time.Duration(len(sorted))
+	stats.P50 = percentile(sorted, 50)
+	stats.P95 = percentile(sorted, 95)
+	stats.P99 = percentile(sorted, 99)
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := (len(sorted) - 1) * p / 100
+	return sorted[index]
+}
+
+func printReport(out io.Writer, report *Report) {
+	fmt.Fprintf(out, "================================================================================\n")
+	fmt.Fprintf(out, "N42 Block Propagation Report\n")
+	fmt.Fprintf(out, "================================================================================\n")
+	fmt.Fprintf(out, "Nodes:    %d\n", len(report.Nodes))
+	fmt.Fprintf(out, "Blocks:   %d\n", report.Blocks)
+	fmt.Fprintf(out, "Window:   %s\n", report.Finished.Sub(report.Started).Round(time.Second))
+	fmt.Fprintf(out, "================================================================================\n\n")
+
+	fmt.Fprintf(out, "%-40s %6s %6s %6s %10s %10s %10s %10s %10s\n",
+		"Node", "Seen", "Missed", "First", "Avg", "P50", "P95", "P99", "Max")
+	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 118))
+	rows := append(append([]DelayStats{}, report.PerNode...), report.Spread)
+	for _, s := range rows {
+		fmt.Fprintf(out, "%-40s %6d %6d %6d %10s %10s %10s %10s %10s\n",
+			s.Name, s.Seen, s.Missed, s.First,
+			formatDuration(s.Avg),
+			formatDuration(s.P50),
+			formatDuration(s.P95),
+			formatDuration(s.P99),
+			formatDuration(s.Max),
+		)
+	}
+	fmt.Fprintf(out, "\nDelays are measured from the first node announcing each block. The spread\n")
+	fmt.Fprintf(out, "row covers blocks seen by every node (first to last announcement).\n")
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return fmt.Sprintf("%.2fµs", float64(d.Microseconds()))
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -19,29 +19,45 @@
 // This tool benchmarks key RPC methods and reports latency statistics.
 //
 // Usage:
-//   go run bench_rpc.go -url http://localhost:8545 -n 100
 //
+//	go run bench_rpc.go -url http://localhost:8545 -n 100
+//	go run bench_rpc.go -url http://localhost:8545 -n 10000 -concurrency 32 -rps 2000
+//	go run bench_rpc.go -url ws://localhost:8546 -subscribe newHeads -duration 1m
+//	go run bench_rpc.go -url /path/to/n42.ipc -n 1000 -concurrency 8
+//	go run bench_rpc.go -format json -output baseline.json
+//	go run bench_rpc.go -baseline baseline.json -max-regression 15
 package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/n42blockchain/N42/modules/rpc/jsonrpc"
 )
 
 // BenchConfig holds benchmark configuration
 type BenchConfig struct {
-	URL        string
-	Iterations int
-	Methods    []string
-	Timeout    time.Duration
+	URL         string
+	Iterations  int
+	Warmup      int // Calls per method excluded from the statistics
+	Concurrency int // Number of workers issuing calls in parallel
+	RPS         int // Target request rate across all workers, 0 for unlimited
+	Methods     []string
+	Timeout     time.Duration
+	Subscribe   string        // Subscription to measure, empty to skip
+	Duration    time.Duration // How long the subscription is measured
 }
 
 // RPCRequest represents a JSON-RPC request
@@ -66,18 +82,164 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
+// ErrorClass categorizes a failed benchmark call
+type ErrorClass string
+
+const (
+	ErrClassTransport ErrorClass = "transport" // connection refused, reset, DNS, non-2xx HTTP status
+	ErrClassTimeout   ErrorClass = "timeout"   // client or network deadline exceeded
+	ErrClassRPC       ErrorClass = "rpc"       // well-formed JSON-RPC error object
+	ErrClassDecode    ErrorClass = "decode"    // response body is not valid JSON-RPC
+)
+
+// errorClasses is the fixed reporting order of error classes
+var errorClasses = []ErrorClass{ErrClassTransport, ErrClassTimeout, ErrClassRPC, ErrClassDecode}
+
+// CallError is a classified benchmark call failure
+type CallError struct {
+	Class ErrorClass
+	Code  int // JSON-RPC error code for ErrClassRPC, HTTP status for non-2xx transport errors
+	Err   error
+}
+
+func (e *CallError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s error (code %d): %v", e.Class, e.Code, e.Err)
+	}
+	return fmt.Sprintf("%s error: %v", e.Class, e.Err)
+}
+
+func (e *CallError) Unwrap() error { return e.Err }
+
 // MethodStats holds statistics for a method
 type MethodStats struct {
-	Method    string
-	Count     int
-	Errors    int
-	Latencies []time.Duration
-	P50       time.Duration
-	P95       time.Duration
-	P99       time.Duration
-	Min       time.Duration
-	Max       time.Duration
-	Avg       time.Duration
+	Method       string             `json:"method"`
+	Count        int                `json:"count"`
+	Errors       int                `json:"errors"`
+	ErrorClasses map[ErrorClass]int `json:"error_classes,omitempty"`
+	ErrorCodes   map[int]int        `json:"error_codes,omitempty"` // JSON-RPC error code -> count
+	Latencies    []time.Duration    `json:"-"`
+	P50          time.Duration      `json:"p50"`
+	P95          time.Duration      `json:"p95"`
+	P99          time.Duration      `json:"p99"`
+	Min          time.Duration      `json:"min"`
+	Max          time.Duration      `json:"max"`
+	Avg          time.Duration      `json:"avg"`
+	Elapsed      time.Duration      `json:"elapsed"`    // Wall time of the measured calls
+	Throughput   float64            `json:"throughput"` // Completed calls per second
+}
+
+// SubscriptionStats holds the delivery statistics of a subscription
+type SubscriptionStats struct {
+	Kind         string        `json:"kind"`
+	Events       int           `json:"events"`
+	Elapsed      time.Duration `json:"elapsed"`
+	EventsPerSec float64       `json:"events_per_sec"`
+	MinInterval  time.Duration `json:"min_interval"` // Shortest gap between two consecutive events
+	AvgInterval  time.Duration `json:"avg_interval"`
+	MaxInterval  time.Duration `json:"max_interval"`
+	Err          error         `json:"-"`               // Reason the subscription ended early, if any
+	Error        string        `json:"error,omitempty"` // Err as text for the JSON report
+}
+
+// caller issues a single JSON-RPC call over one transport and returns its
+// latency
+type caller interface {
+	call(method string, params []interface{}) (time.Duration, error)
+	close()
+}
+
+// httpCaller posts requests with a shared, pooled HTTP client
+type httpCaller struct {
+	client *http.Client
+	url    string
+}
+
+func (c *httpCaller) call(method string, params []interface{}) (time.Duration, error) {
+	return callRPC(c.client, c.url, method, params)
+}
+
+func (c *httpCaller) close() {}
+
+// clientCaller sends requests over a persistent WebSocket or IPC connection
+type clientCaller struct {
+	client  *jsonrpc.Client
+	timeout time.Duration
+}
+
+func (c *clientCaller) call(method string, params []interface{}) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var result json.RawMessage
+	start := time.Now()
+	err := c.client.CallContext(ctx, &result, method, params...)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, classifyClientError(err)
+	}
+	return latency, nil
+}
+
+func (c *clientCaller) close() { c.client.Close() }
+
+// transport names the transport selected by the endpoint URL
+func transport(url string) string {
+	switch {
+	case strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		return "http"
+	case strings.HasPrefix(url, "ws://"), strings.HasPrefix(url, "wss://"):
+		return "ws"
+	default:
+		return "ipc"
+	}
+}
+
+// newCallers creates one caller per worker. HTTP workers share a client
+// with a connection pool; WebSocket and IPC workers each own a connection.
+func newCallers(config *BenchConfig) ([]caller, error) {
+	callers := make([]caller, 0, config.Concurrency)
+	if transport(config.URL) == "http" {
+		// The default transport keeps only two idle connections per host,
+		// which would make concurrent workers reconnect on most calls.
+		client := &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{MaxIdleConnsPerHost: config.Concurrency},
+		}
+		for i := 0; i < config.Concurrency; i++ {
+			callers = append(callers, &httpCaller{client: client, url: config.URL})
+		}
+		return callers, nil
+	}
+	for i := 0; i < config.Concurrency; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		client, err := jsonrpc.DialContext(ctx, config.URL)
+		cancel()
+		if err != nil {
+			for _, c := range callers {
+				c.close()
+			}
+			return nil, fmt.Errorf("dial %s: %w", config.URL, err)
+		}
+		callers = append(callers, &clientCaller{client: client, timeout: config.Timeout})
+	}
+	return callers, nil
+}
+
+// histogramBuckets are the upper bounds of the latency histogram
+var histogramBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
 }
 
 // Default RPC methods to benchmark
@@ -114,28 +276,76 @@ var methodParams = map[string][]interface{}{
 
 func main() {
 	// Parse flags
-	url := flag.String("url", "http://localhost:8545", "RPC URL")
+	url := flag.String("url", "http://localhost:8545", "RPC endpoint: http(s)://, ws(s):// or an IPC socket path")
 	iterations := flag.Int("n", 100, "Number of iterations per method")
 	methodsStr := flag.String("methods", "", "Comma-separated list of methods (empty for default)")
 	timeout := flag.Duration("timeout", 30*time.Second, "Request timeout")
+	concurrency := flag.Int("concurrency", 1, "Number of concurrent workers")
+	rps := flag.Int("rps", 0, "Target requests per second across all workers (0 for unlimited)")
+	warmup := flag.Int("warmup", 10, "Warm-up calls per method excluded from the statistics")
+	subscribe := flag.String("subscribe", "", "Subscription to measure over ws or ipc, e.g. newHeads (empty to skip)")
+	duration := flag.Duration("duration", 30*time.Second, "How long to measure the subscription")
 	output := flag.String("output", "", "Output file (empty for stdout)")
+	format := flag.String("format", formatText, "Output format: text, json or csv")
+	baselinePath := flag.String("baseline", "", "JSON report of a previous run to compare P95 latencies against")
+	maxRegression := flag.Float64("max-regression", 10, "Allowed P95 increase over the baseline in percent")
+	minDelta := flag.Duration("min-delta", time.Millisecond, "P95 increases smaller than this never fail the comparison")
 	flag.Parse()
 
+	switch *format {
+	case formatText, formatJSON, formatCSV:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown output format %q (want text, json or csv)\n", *format)
+		os.Exit(1)
+	}
+	var baseline *Report
+	if *baselinePath != "" {
+		var err error
+		if baseline, err = loadReport(*baselinePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	thresholds := Thresholds{MaxRegression: *maxRegression, MinDelta: *minDelta}
+
 	// Configure methods
 	methods := defaultMethods
 	if *methodsStr != "" {
 		methods = strings.Split(*methodsStr, ",")
 	}
 
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
 	config := &BenchConfig{
-		URL:        *url,
-		Iterations: *iterations,
-		Methods:    methods,
-		Timeout:    *timeout,
+		URL:         *url,
+		Iterations:  *iterations,
+		Warmup:      *warmup,
+		Concurrency: *concurrency,
+		RPS:         *rps,
+		Methods:     methods,
+		Timeout:     *timeout,
+		Subscribe:   *subscribe,
+		Duration:    *duration,
+	}
+	if config.Subscribe != "" && transport(config.URL) == "http" {
+		fmt.Fprintf(os.Stderr, "Subscriptions need a ws:// or IPC endpoint\n")
+		os.Exit(1)
 	}
 
 	// Run benchmarks
-	results := runBenchmarks(config)
+	results, err := runBenchmarks(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var sub *SubscriptionStats
+	if config.Subscribe != "" {
+		if sub, err = runSubscription(config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Output results
 	var out io.Writer = os.Stdout
@@ -145,27 +355,65 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
 			os.Exit(1)
 		}
-		defer f.Close()
 		out = f
 	}
 
-	printResults(out, config, results)
+	report := newReport(config, results, sub)
+	if baseline != nil {
+		report.Comparison = compare(baseline, report.Methods, thresholds)
+	}
+	switch *format {
+	case formatJSON:
+		err = writeJSON(out, report)
+	case formatCSV:
+		err = writeCSV(out, report)
+	default:
+		printResults(out, config, results)
+		if sub != nil {
+			printSubscription(out, sub)
+		}
+		if baseline != nil {
+			printComparison(out, *baselinePath, report.Comparison, thresholds)
+		}
+	}
+	if f, ok := out.(*os.File); ok && f != os.Stdout {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A non-zero exit status lets CI gate on performance regressions
+	if n := regressions(report.Comparison); n > 0 {
+		fmt.Fprintf(os.Stderr, "%d method(s) regressed against %s\n", n, *baselinePath)
+		os.Exit(1)
+	}
 }
 
-func runBenchmarks(config *BenchConfig) map[string]*MethodStats {
+func runBenchmarks(config *BenchConfig) (map[string]*MethodStats, error) {
 	results := make(map[string]*MethodStats)
-	client := &http.Client{Timeout: config.Timeout}
+	if config.Iterations <= 0 {
+		return results, nil
+	}
+	callers, err := newCallers(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, c := range callers {
+			c.close()
+		}
+	}()
 
-	fmt.Fprintf(os.Stderr, "Running benchmarks against %s\n", config.URL)
-	fmt.Fprintf(os.Stderr, "Iterations per method: %d\n", config.Iterations)
+	fmt.Fprintf(os.Stderr, "Running benchmarks against %s (%s)\n", config.URL, transport(config.URL))
+	fmt.Fprintf(os.Stderr, "Iterations per method: %d (warm-up %d)\n", config.Iterations, config.Warmup)
+	fmt.Fprintf(os.Stderr, "Concurrency: %d, target rate: %s\n", config.Concurrency, formatRate(config.RPS))
 	fmt.Fprintf(os.Stderr, "Methods: %v\n\n", config.Methods)
 
 	for _, method := range config.Methods {
-		stats := &MethodStats{
-			Method:    method,
-			Latencies: make([]time.Duration, 0, config.Iterations),
-		}
-
 		params, ok := methodParams[method]
 		if !ok {
 			params = []interface{}{}
@@ -173,29 +421,82 @@ func runBenchmarks(config *BenchConfig) map[string]*MethodStats {
 
 		fmt.Fprintf(os.Stderr, "Benchmarking %s...", method)
 
-		for i := 0; i < config.Iterations; i++ {
-			latency, err := callRPC(client, config.URL, method, params)
-			if err != nil {
-				stats.Errors++
-			} else {
-				stats.Latencies = append(stats.Latencies, latency)
-			}
-			stats.Count++
-
-			// Progress indicator
-			if (i+1)%10 == 0 {
-				fmt.Fprintf(os.Stderr, ".")
-			}
+		for i := 0; i < config.Warmup; i++ {
+			callers[i%len(callers)].call(method, params)
 		}
+		stats := runMethod(config, callers, method, params)
 
 		// Calculate statistics
 		calculateStats(stats)
 		results[method] = stats
 
-		fmt.Fprintf(os.Stderr, " done (errors: %d)\n", stats.Errors)
+		fmt.Fprintf(os.Stderr, " done (errors: %d, %.1f req/s)\n", stats.Errors, stats.Throughput)
 	}
 
-	return results
+	return results, nil
+}
+
+// runMethod issues the measured calls of a method from a pool of workers,
+// pacing them to the target rate when one is configured.
+func runMethod(config *BenchConfig, callers []caller, method string, params []interface{}) *MethodStats {
+	stats := &MethodStats{
+		Method:       method,
+		ErrorClasses: make(map[ErrorClass]int),
+		ErrorCodes:   make(map[int]int),
+		Latencies:    make([]time.Duration, 0, config.Iterations),
+	}
+
+	jobs := make(chan struct{})
+	go func() {
+		defer close(jobs)
+		var tick <-chan time.Time
+		if config.RPS > 0 {
+			ticker := time.NewTicker(max(time.Second/time.Duration(config.RPS), time.Nanosecond))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for i := 0; i < config.Iterations; i++ {
+			if tick != nil {
+				<-tick
+			}
+			jobs <- struct{}{}
+		}
+	}()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	start := time.Now()
+	for _, c := range callers {
+		wg.Add(1)
+		go func(c caller) {
+			defer wg.Done()
+			for range jobs {
+				latency, err := c.call(method, params)
+
+				mu.Lock()
+				if err != nil {
+					stats.recordError(err)
+				} else {
+					stats.Latencies = append(stats.Latencies, latency)
+				}
+				stats.Count++
+				// Progress indicator
+				if stats.Count%(max(config.Iterations/10, 1)) == 0 {
+					fmt.Fprintf(os.Stderr, ".")
+				}
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	stats.Elapsed = time.Since(start)
+	if stats.Elapsed > 0 {
+		stats.Throughput = float64(stats.Count) / stats.Elapsed.Seconds()
+	}
+	return stats
 }
 
 func callRPC(client *http.Client, url, method string, params []interface{}) (time.Duration, error) {
@@ -213,25 +514,148 @@ func callRPC(client *http.Client, url, method string, params []interface{}) (tim
 
 	start := time.Now()
 	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
-	latency := time.Since(start)
-
 	if err != nil {
-		return latency, err
+		return time.Since(start), classifyTransportError(err)
 	}
 	defer resp.Body.Close()
 
 	var rpcResp RPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return latency, err
+	decodeErr := json.NewDecoder(resp.Body).Decode(&rpcResp)
+	latency := time.Since(start)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Servers commonly return a JSON-RPC error body alongside a non-2xx
+		// status (e.g. rate limiting); prefer the RPC code when present.
+		if decodeErr == nil && rpcResp.Error != nil {
+			return latency, &CallError{Class: ErrClassRPC, Code: rpcResp.Error.Code, Err: errors.New(rpcResp.Error.Message)}
+		}
+		return latency, &CallError{Class: ErrClassTransport, Code: resp.StatusCode, Err: fmt.Errorf("HTTP %s", resp.Status)}
+	}
+	if decodeErr != nil {
+		if isTimeout(decodeErr) {
+			return latency, &CallError{Class: ErrClassTimeout, Err: decodeErr}
+		}
+		return latency, &CallError{Class: ErrClassDecode, Err: decodeErr}
 	}
 
 	if rpcResp.Error != nil {
-		return latency, fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+		return latency, &CallError{Class: ErrClassRPC, Code: rpcResp.Error.Code, Err: errors.New(rpcResp.Error.Message)}
+	}
+	if rpcResp.Result == nil {
+		return latency, &CallError{Class: ErrClassDecode, Err: errors.New("response has neither result nor error")}
 	}
 
 	return latency, nil
 }
 
+// runSubscription subscribes to config.Subscribe and measures how many
+// notifications arrive during config.Duration
+func runSubscription(config *BenchConfig) (*SubscriptionStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	client, err := jsonrpc.DialContext(ctx, config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", config.URL, err)
+	}
+	defer client.Close()
+
+	events := make(chan json.RawMessage, 1024)
+	sub, err := client.Subscribe(ctx, "eth", events, config.Subscribe)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe %s: %w", config.Subscribe, err)
+	}
+	// Closing the client drops the subscription on the server, so there is no
+	// need to wait for an eth_unsubscribe round trip.
+
+	fmt.Fprintf(os.Stderr, "\nMeasuring %s subscription for %s...", config.Subscribe, config.Duration)
+
+	stats := &SubscriptionStats{Kind: config.Subscribe}
+	var (
+		start    = time.Now()
+		last     time.Time
+		interval time.Duration
+		deadline = time.After(config.Duration)
+	)
+loop:
+	for {
+		select {
+		case <-events:
+			now := time.Now()
+			if !last.IsZero() {
+				gap := now.Sub(last)
+				interval += gap
+				if stats.MinInterval == 0 || gap < stats.MinInterval {
+					stats.MinInterval = gap
+				}
+				stats.MaxInterval = max(stats.MaxInterval, gap)
+			}
+			last = now
+			stats.Events++
+		case err := <-sub.Err():
+			if stats.Err = err; err != nil {
+				stats.Error = err.Error()
+			}
+			break loop
+		case <-deadline:
+			break loop
+		}
+	}
+	stats.Elapsed = time.Since(start)
+	stats.EventsPerSec = float64(stats.Events) / stats.Elapsed.Seconds()
+	if stats.Events > 1 {
+		stats.AvgInterval = interval / time.Duration(stats.Events-1)
+	}
+	fmt.Fprintf(os.Stderr, " done (%d events)\n", stats.Events)
+	return stats, nil
+}
+
+// classifyClientError maps a WebSocket or IPC client error to an error class
+func classifyClientError(err error) *CallError {
+	var rpcErr jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		return &CallError{Class: ErrClassRPC, Code: rpcErr.ErrorCode(), Err: err}
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return &CallError{Class: ErrClassDecode, Err: err}
+	}
+	if isTimeout(err) {
+		return &CallError{Class: ErrClassTimeout, Err: err}
+	}
+	return &CallError{Class: ErrClassTransport, Err: err}
+}
+
+// classifyTransportError maps an HTTP client error to an error class
+func classifyTransportError(err error) *CallError {
+	if isTimeout(err) {
+		return &CallError{Class: ErrClassTimeout, Err: err}
+	}
+	return &CallError{Class: ErrClassTransport, Err: err}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// recordError accounts a failed call in the per-class and per-code counters
+func (s *MethodStats) recordError(err error) {
+	s.Errors++
+	var callErr *CallError
+	if !errors.As(err, &callErr) {
+		s.ErrorClasses[ErrClassTransport]++
+		return
+	}
+	s.ErrorClasses[callErr.Class]++
+	if callErr.Class == ErrClassRPC {
+		s.ErrorCodes[callErr.Code]++
+	}
+}
+
 func calculateStats(stats *MethodStats) {
 	if len(stats.Latencies) == 0 {
 		return
@@ -270,13 +694,16 @@ func printResults(out io.Writer, config *BenchConfig, results map[string]*Method
 	fmt.Fprintf(out, "N42 RPC Benchmark Results\n")
 	fmt.Fprintf(out, "================================================================================\n")
 	fmt.Fprintf(out, "URL:        %s\n", config.URL)
-	fmt.Fprintf(out, "Iterations: %d\n", config.Iterations)
+	fmt.Fprintf(out, "Transport:  %s\n", transport(config.URL))
+	fmt.Fprintf(out, "Iterations: %d (warm-up %d)\n", config.Iterations, config.Warmup)
+	fmt.Fprintf(out, "Workers:    %d\n", config.Concurrency)
+	fmt.Fprintf(out, "Rate:       %s\n", formatRate(config.RPS))
 	fmt.Fprintf(out, "Time:       %s\n", time.Now().Format(time.RFC3339))
 	fmt.Fprintf(out, "================================================================================\n\n")
 
 	// Table header
-	fmt.Fprintf(out, "%-30s %8s %8s %10s %10s %10s %10s %10s\n",
-		"Method", "Count", "Errors", "Min", "Avg", "P50", "P95", "P99")
+	fmt.Fprintf(out, "%-30s %8s %8s %10s %10s %10s %10s %10s %10s\n",
+		"Method", "Count", "Errors", "Min", "Avg", "P50", "P95", "P99", "Req/s")
 	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 110))
 
 	// Results
@@ -286,7 +713,7 @@ func printResults(out io.Writer, config *BenchConfig, results map[string]*Method
 			continue
 		}
 
-		fmt.Fprintf(out, "%-30s %8d %8d %10s %10s %10s %10s %10s\n",
+		fmt.Fprintf(out, "%-30s %8d %8d %10s %10s %10s %10s %10s %10.1f\n",
 			stats.Method,
 			stats.Count,
 			stats.Errors,
@@ -295,11 +722,15 @@ func printResults(out io.Writer, config *BenchConfig, results map[string]*Method
 			formatDuration(stats.P50),
 			formatDuration(stats.P95),
 			formatDuration(stats.P99),
+			stats.Throughput,
 		)
 	}
 
 	fmt.Fprintf(out, "\n================================================================================\n")
 
+	printHistograms(out, config, results)
+	printErrorBreakdown(out, config, results)
+
 	// Performance summary
 	fmt.Fprintf(out, "\nPerformance Summary:\n")
 	fmt.Fprintf(out, "-------------------\n")
@@ -330,6 +761,142 @@ func printResults(out io.Writer, config *BenchConfig, results map[string]*Method
 	}
 }
 
+// printSubscription reports the delivery rate of a subscription
+func printSubscription(out io.Writer, stats *SubscriptionStats) {
+	fmt.Fprintf(out, "\nSubscription Results:\n")
+	fmt.Fprintf(out, "---------------------\n")
+	fmt.Fprintf(out, "  Kind:       %s\n", stats.Kind)
+	fmt.Fprintf(out, "  Events:     %d in %s\n", stats.Events, stats.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(out, "  Rate:       %.2f events/s\n", stats.EventsPerSec)
+	fmt.Fprintf(out, "  Interval:   min %s, avg %s, max %s\n",
+		formatDuration(stats.MinInterval), formatDuration(stats.AvgInterval), formatDuration(stats.MaxInterval))
+	if stats.Err != nil {
+		fmt.Fprintf(out, "  Ended:      %v\n", stats.Err)
+	}
+}
+
+// printHistograms reports the latency distribution of successful calls per
+// method
+func printHistograms(out io.Writer, config *BenchConfig, results map[string]*MethodStats) {
+	const barWidth = 40
+
+	fmt.Fprintf(out, "\nLatency Histogram:\n")
+	fmt.Fprintf(out, "------------------\n")
+	for _, method := range config.Methods {
+		stats := results[method]
+		if stats == nil || len(stats.Latencies) == 0 {
+			continue
+		}
+		counts := make([]int, len(histogramBuckets)+1)
+		for _, l := range stats.Latencies {
+			counts[sort.Search(len(histogramBuckets), func(i int) bool { return l <= histogramBuckets[i] })]++
+		}
+		// Skip the empty buckets at either end of the distribution.
+		first, last := 0, len(counts)-1
+		for counts[first] == 0 {
+			first++
+		}
+		for counts[last] == 0 {
+			last--
+		}
+
+		fmt.Fprintf(out, "%s\n", method)
+		for i := first; i <= last; i++ {
+			label := "> " + formatBound(histogramBuckets[len(histogramBuckets)-1])
+			if i < len(histogramBuckets) {
+				label = "<= " + formatBound(histogramBuckets[i])
+			}
+			share := float64(counts[i]) / float64(len(stats.Latencies))
+			fmt.Fprintf(out, "  %10s |%-*s %8d (%5.1f%%)\n",
+				label, barWidth, strings.Repeat("#", int(share*barWidth+0.5)), counts[i], share*100)
+		}
+	}
+}
+
+// printErrorBreakdown reports error counts per class and per JSON-RPC code,
+// both per method and aggregated over the whole run
+func printErrorBreakdown(out io.Writer, config *BenchConfig, results map[string]*MethodStats) {
+	totalClasses := make(map[ErrorClass]int)
+	totalCodes := make(map[int]int)
+	totalErrors := 0
+	for _, method := range config.Methods {
+		stats := results[method]
+		if stats == nil {
+			continue
+		}
+		totalErrors += stats.Errors
+		for class, n := range stats.ErrorClasses {
+			totalClasses[class] += n
+		}
+		for code, n := range stats.ErrorCodes {
+			totalCodes[code] += n
+		}
+	}
+
+	fmt.Fprintf(out, "\nError Breakdown:\n")
+	fmt.Fprintf(out, "----------------\n")
+	if totalErrors == 0 {
+		fmt.Fprintf(out, "  no errors\n\n")
+		return
+	}
+
+	fmt.Fprintf(out, "%-30s %8s", "Method", "Errors")
+	for _, class := range errorClasses {
+		fmt.Fprintf(out, " %10s", class)
+	}
+	fmt.Fprintf(out, "  %s\n", "RPC codes")
+	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 110))
+
+	for _, method := range config.Methods {
+		stats := results[method]
+		if stats == nil || stats.Errors == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "%-30s %8d", stats.Method, stats.Errors)
+		for _, class := range errorClasses {
+			fmt.Fprintf(out, " %10d", stats.ErrorClasses[class])
+		}
+		fmt.Fprintf(out, "  %s\n", formatCodes(stats.ErrorCodes))
+	}
+
+	fmt.Fprintf(out, "%-30s %8d", "TOTAL", totalErrors)
+	for _, class := range errorClasses {
+		fmt.Fprintf(out, " %10d", totalClasses[class])
+	}
+	fmt.Fprintf(out, "  %s\n\n", formatCodes(totalCodes))
+}
+
+// formatCodes renders error code counts as "code:count" pairs sorted by code
+func formatCodes(codes map[int]int) string {
+	if len(codes) == 0 {
+		return "-"
+	}
+	keys := make([]int, 0, len(codes))
+	for code := range codes {
+		keys = append(keys, code)
+	}
+	sort.Ints(keys)
+	parts := make([]string, 0, len(keys))
+	for _, code := range keys {
+		parts = append(parts, fmt.Sprintf("%d:%d", code, codes[code]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatBound(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+func formatRate(rps int) string {
+	if rps <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d req/s", rps)
+}
+
 func formatDuration(d time.Duration) string {
 	if d == 0 {
 		return "-"
@@ -342,4 +909,3 @@ func formatDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%.2fs", d.Seconds())
 }
-
@@ -0,0 +1,221 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output formats of the benchmark report
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// Comparison statuses against a baseline run
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+	statusNew  = "new" // Method was not part of the baseline
+)
+
+// Report is the machine-readable result of a benchmark run. A report written
+// with -format json can be passed back as -baseline of a later run.
+type Report struct {
+	URL          string             `json:"url"`
+	Transport    string             `json:"transport"`
+	Time         time.Time          `json:"time"`
+	Iterations   int                `json:"iterations"`
+	Warmup       int                `json:"warmup"`
+	Concurrency  int                `json:"concurrency"`
+	RPS          int                `json:"rps"`
+	Methods      []*MethodStats     `json:"methods"`
+	Subscription *SubscriptionStats `json:"subscription,omitempty"`
+	Comparison   []Comparison       `json:"comparison,omitempty"`
+}
+
+// Thresholds decide when a P95 increase counts as a regression
+type Thresholds struct {
+	MaxRegression float64       // Allowed P95 increase in percent
+	MinDelta      time.Duration // Increases below this never fail, absorbing noise on fast methods
+}
+
+// Comparison is the P95 change of a method against a baseline run
+type Comparison struct {
+	Method      string        `json:"method"`
+	BaselineP95 time.Duration `json:"baseline_p95"`
+	P95         time.Duration `json:"p95"`
+	Delta       float64       `json:"delta_pct"` // Relative change of P95 in percent
+	Status      string        `json:"status"`
+}
+
+// newReport collects the results in the order the methods were run
+func newReport(config *BenchConfig, results map[string]*MethodStats, sub *SubscriptionStats) *Report {
+	report := &Report{
+		URL:          config.URL,
+		Transport:    transport(config.URL),
+		Time:         time.Now(),
+		Iterations:   config.Iterations,
+		Warmup:       config.Warmup,
+		Concurrency:  config.Concurrency,
+		RPS:          config.RPS,
+		Methods:      make([]*MethodStats, 0, len(results)),
+		Subscription: sub,
+	}
+	for _, method := range config.Methods {
+		if stats := results[method]; stats != nil {
+			report.Methods = append(report.Methods, stats)
+		}
+	}
+	return report
+}
+
+// loadReport reads a report written with -format json
+func loadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	report := new(Report)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// compare reports the P95 change of every method against the baseline. A
+// method fails when its P95 grew by more than both thresholds, or when it had
+// successful calls in the baseline but none now.
+func compare(baseline *Report, methods []*MethodStats, th Thresholds) []Comparison {
+	prev := make(map[string]*MethodStats, len(baseline.Methods))
+	for _, stats := range baseline.Methods {
+		prev[stats.Method] = stats
+	}
+
+	comparisons := make([]Comparison, 0, len(methods))
+	for _, stats := range methods {
+		c := Comparison{Method: stats.Method, P95: stats.P95, Status: statusPass}
+		base := prev[stats.Method]
+		switch {
+		case base == nil:
+			c.Status = statusNew
+		case base.Count == base.Errors:
+			// Nothing to compare against
+			c.BaselineP95 = base.P95
+		case stats.Count == stats.Errors:
+			c.BaselineP95 = base.P95
+			c.Status = statusFail
+		default:
+			c.BaselineP95 = base.P95
+			if base.P95 > 0 {
+				c.Delta = float64(stats.P95-base.P95) * 100 / float64(base.P95)
+			}
+			if c.Delta > th.MaxRegression && stats.P95-base.P95 >= th.MinDelta {
+				c.Status = statusFail
+			}
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// regressions counts the methods that failed the baseline comparison
+func regressions(comparisons []Comparison) int {
+	var n int
+	for _, c := range comparisons {
+		if c.Status == statusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// printComparison reports the P95 deltas against the baseline
+func printComparison(out io.Writer, baseline string, comparisons []Comparison, th Thresholds) {
+	fmt.Fprintf(out, "\nBaseline Comparison (%s):\n", baseline)
+	fmt.Fprintf(out, "-------------------\n")
+	fmt.Fprintf(out, "Threshold: P95 +%.1f%% and +%s\n\n", th.MaxRegression, formatDuration(th.MinDelta))
+	fmt.Fprintf(out, "%-30s %12s %12s %10s  %s\n", "Method", "Baseline P95", "P95", "Delta", "Status")
+	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 80))
+	for _, c := range comparisons {
+		delta := "-"
+		if c.Status != statusNew && c.BaselineP95 > 0 && c.P95 > 0 {
+			delta = fmt.Sprintf("%+.1f%%", c.Delta)
+		}
+		baseP95 := "-"
+		if c.Status != statusNew {
+			baseP95 = formatDuration(c.BaselineP95)
+		}
+		fmt.Fprintf(out, "%-30s %12s %12s %10s  %s\n", c.Method, baseP95, formatDuration(c.P95), delta, strings.ToUpper(c.Status))
+	}
+}
+
+// writeJSON writes the report as indented JSON
+func writeJSON(out io.Writer, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// writeCSV writes one row per method with latencies in microseconds. The
+// baseline columns are only present when a baseline was given.
+func writeCSV(out io.Writer, report *Report) error {
+	w := csv.NewWriter(out)
+	header := []string{"method", "count", "errors", "min_us", "avg_us", "p50_us", "p95_us", "p99_us", "max_us", "req_per_sec"}
+	if report.Comparison != nil {
+		header = append(header, "baseline_p95_us", "p95_delta_pct", "status")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	us := func(d time.Duration) string { return strconv.FormatInt(d.Microseconds(), 10) }
+	for i, stats := range report.Methods {
+		row := []string{
+			stats.Method,
+			strconv.Itoa(stats.Count),
+			strconv.Itoa(stats.Errors),
+			us(stats.Min),
+			us(stats.Avg),
+			us(stats.P50),
+			us(stats.P95),
+			us(stats.P99),
+			us(stats.Max),
+			strconv.FormatFloat(stats.Throughput, 'f', 1, 64),
+		}
+		if report.Comparison != nil {
+			c := report.Comparison[i]
+			row = append(row, us(c.BaselineP95), strconv.FormatFloat(c.Delta, 'f', 1, 64), c.Status)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,457 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// The N42 library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The N42 library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the N42 library. If not, see <http://www.gnu.org/licenses/>.
+
+// bench_syncbench - Historical Sync Benchmark for N42
+//
+// This tool syncs a fresh node from a local peer serving a known chain and
+// records, for every window of blocks (10k by default), the wall time, the
+// time spent in each block import stage and the growth of the data directory.
+// The report is used to validate downloader and execution optimizations.
+//
+// Stage timings come from the chain_validation_seconds, chain_execution_seconds
+// and chain_write_seconds histograms of the syncing node; whatever is left of the wall time is
+// attributed to download (fetching and queueing blocks from the peer).
+//
+// Usage:
+//
+//	go run ./cmd/syncbench -node ./build/bin/n42 -datadir /tmp/syncbench \
+//	    -peer /ip4/127.0.0.1/tcp/13000/p2p/16Uiu2... -peer-rpc http://127.0.0.1:8545
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Import stages reported by the node, in pipeline order. The values are the
+// names of the histograms whose _sum (seconds) is sampled per window.
+var stages = []struct {
+	Name   string
+	Metric string
+}{
+	{"validation", "chain_validation_seconds"},
+	{"execution", "chain_execution_seconds"},
+	{"write", "chain_write_seconds"},
+}
+
+// insertMetric covers the whole import of a block; download time is the
+// remainder of the wall time once imports are subtracted.
+const insertMetric = "chain_insert_seconds"
+
+// Window holds measurements for one range of synced blocks.
+type Window struct {
+	From         uint64                   `json:"from"`
+	To           uint64                   `json:"to"`
+	Duration     time.Duration            `json:"duration"`
+	BlocksPerSec float64                  `json:"blocks_per_sec"`
+	Stages       map[string]time.Duration `json:"stages"`
+	DiskBytes    int64                    `json:"disk_bytes"`
+	DiskGrowth   int64                    `json:"disk_growth"`
+}
+
+// Report is the result of a sync benchmark run.
+type Report struct {
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Target    uint64    `json:"target"`
+	Reached   uint64    `json:"reached"`
+	Completed bool      `json:"completed"`
+	Interval  uint64    `json:"interval"`
+	Windows   []Window  `json:"windows"`
+	Total     Window    `json:"total"`
+	Errors    []string  `json:"errors,omitempty"`
+}
+
+// sample is a point-in-time reading of the syncing node.
+type sample struct {
+	at     time.Time
+	number uint64
+	sums   map[string]float64
+	disk   int64
+}
+
+func main() {
+	nodeBin := flag.String("node", "", "Path to the n42 binary to launch (empty = attach to a running node)")
+	nodeArgs := flag.String("node-args", "", "Extra arguments passed to the launched node")
+	datadir := flag.String("datadir", "", "Data directory of the syncing node (must be empty when -node is set)")
+	peer := flag.String("peer", "", "Multiaddr of the local peer serving the chain")
+	rpcURL := flag.String("rpc", "http://127.0.0.1:8545", "HTTP RPC endpoint of the syncing node")
+	metricsURL := flag.String("metrics", "http://127.0.0.1:6061/metrics", "Metrics endpoint of the syncing node")
+	peerRPC := flag.String("peer-rpc", "", "HTTP RPC endpoint of the serving peer, used to read the target height")
+	target := flag.Uint64("target", 0, "Block number to sync to (0 = head of -peer-rpc)")
+	interval := flag.Uint64("interval", 10000, "Blocks per report window")
+	poll := flag.Duration("poll", 2*time.Second, "Polling interval")
+	timeout := flag.Duration("timeout", 6*time.Hour, "Give up after this long")
+	output := flag.String("output", "", "Output file for the report (JSON)")
+	flag.Parse()
+
+	if *datadir == "" {
+		fmt.Fprintln(os.Stderr, "-datadir is required")
+		os.Exit(2)
+	}
+	if *interval == 0 {
+		fmt.Fprintln(os.Stderr, "-interval must be positive")
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	if *target == 0 {
+		if *peerRPC == "" {
+			fmt.Fprintln(os.Stderr, "either -target or -peer-rpc is required")
+			os.Exit(2)
+		}
+		head, err := blockNumber(ctx, *peerRPC)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read target from %s: %v\n", *peerRPC, err)
+			os.Exit(1)
+		}
+		*target = head
+	}
+
+	var node *exec.Cmd
+	if *nodeBin != "" {
+		var err error
+		if node, err = launchNode(*nodeBin, *datadir, *peer, *nodeArgs); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to launch node: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	report := run(ctx, *rpcURL, *metricsURL, *datadir, *target, *interval, *poll)
+	if node != nil {
+		stopNode(node)
+	}
+	printReport(os.Stdout, report)
+
+	if *output != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to marshal report: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nReport written to: %s\n", *output)
+	}
+	if !report.Completed {
+		os.Exit(1)
+	}
+}
+
+// launchNode starts a fresh node syncing from the given peer.
+func launchNode(bin, datadir, peer, extra string) (*exec.Cmd, error) {
+	entries, err := os.ReadDir(datadir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("datadir %s is not empty, a fresh node is required", datadir)
+	}
+	if err := os.MkdirAll(datadir, 0755); err != nil {
+		return nil, err
+	}
+
+	args := []string{"--data.dir", datadir, "--http", "--metrics", "--p2p.no-discovery"}
+	if peer != "" {
+		args = append(args, "--p2p.peer", peer)
+	}
+	args = append(args, strings.Fields(extra)...)
+
+	logFile, err := os.Create(filepath.Clean(datadir) + ".log")
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(bin, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, err
+	}
+	fmt.Printf("Launched %s (pid %d), log: %s\n", bin, cmd.Process.Pid, logFile.Name())
+	return cmd, nil
+}
+
+func stopNode(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Signal(syscall.SIGINT)
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		_ = cmd.Process.Kill()
+		<-done
+	}
+}
+
+// run polls the syncing node until it reaches target and cuts a window every
+// interval blocks.
+func run(ctx context.Context, rpcURL, metricsURL, datadir string, target, interval uint64, poll time.Duration) *Report {
+	report := &Report{Started: time.Now(), Target: target, Interval: interval}
+	errorf := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if n := len(report.Errors); n == 0 || report.Errors[n-1] != msg {
+			report.Errors = append(report.Errors, msg)
+		}
+	}
+
+	take := func(number uint64) sample {
+		s := sample{at: time.Now(), number: number}
+		sums, err := scrapeSums(ctx, metricsURL)
+		if err != nil {
+			errorf("metrics: %v", err)
+		}
+		s.sums = sums
+		if s.disk, err = dirSize(datadir); err != nil {
+			errorf("disk: %v", err)
+		}
+		return s
+	}
+
+	first := take(0)
+	last := first
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for !report.Completed {
+		select {
+		case <-ctx.Done():
+			errorf("stopped before reaching target: %v", ctx.Err())
+			report.Finished = time.Now()
+			report.Total = window(first, last)
+			return report
+		case <-ticker.C:
+		}
+
+		number, err := blockNumber(ctx, rpcURL)
+		if err != nil {
+			errorf("rpc: %v", err)
+			continue
+		}
+		report.Reached = number
+		boundary := (last.number/interval + 1) * interval
+		if number < boundary && number < target {
+			continue
+		}
+
+		current := take(number)
+		w := window(last, current)
+		report.Windows = append(report.Windows, w)
+		fmt.Printf("blocks %d-%d: %s (%.1f blocks/s), disk %s\n",
+			w.From, w.To, w.Duration.Round(time.Millisecond), w.BlocksPerSec, formatBytes(w.DiskBytes))
+		last = current
+		report.Completed = number >= target
+	}
+
+	report.Finished = time.Now()
+	report.Total = window(first, last)
+	return report
+}
+
+// window derives the measurements between two samples.
+func window(from, to sample) Window {
+	w := Window{
+		From:       from.number,
+		To:         to.number,
+		Duration:   to.at.Sub(from.at),
+		Stages:     make(map[string]time.Duration, len(stages)+1),
+		DiskBytes:  to.disk,
+		DiskGrowth: to.disk - from.disk,
+	}
+	if w.Duration > 0 {
+		w.BlocksPerSec = float64(to.number-from.number) / w.Duration.Seconds()
+	}
+	for _, st := range stages {
+		w.Stages[st.Name] = sumDuration(from, to, st.Metric)
+	}
+	insert := sumDuration(from, to, insertMetric)
+	if download := w.Duration - insert; download > 0 {
+		w.Stages["download"] = download
+	}
+	return w
+}
+
+// blockNumber returns the current block number of the node behind url.
+func blockNumber(ctx context.Context, url string) (uint64, error) {
+	body := []byte(`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	if out.Error != nil {
+		return 0, fmt.Errorf("eth_blockNumber: %s", out.Error.Message)
+	}
+	return strconv.ParseUint(strings.TrimPrefix(out.Result, "0x"), 16, 64)
+}
+
+// sumDuration returns the time added to a histogram between two samples.
+func sumDuration(from, to sample, metric string) time.Duration {
+	return time.Duration((to.sums[metric] - from.sums[metric]) * float64(time.Second))
+}
+
+// scrapeSums reads the _sum series of the stage histograms from a Prometheus
+// text endpoint.
+func scrapeSums(ctx context.Context, url string) (map[string]float64, error) {
+	sums := make(map[string]float64)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return sums, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return sums, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return sums, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	wanted := map[string]string{insertMetric + "_sum": insertMetric}
+	for _, st := range stages {
+		wanted[st.Metric+"_sum"] = st.Metric
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		metric, ok := wanted[fields[0]]
+		if !ok {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			sums[metric] = v
+		}
+	}
+	return sums, scanner.Err()
+}
+
+// dirSize returns the apparent size of all files below dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Files may disappear while the node is running
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func printReport(out io.Writer, report *Report) {
+	fmt.Fprintf(out, "\n================================================================================\n")
+	fmt.Fprintf(out, "N42 Historical Sync Benchmark\n")
+	fmt.Fprintf(out, "================================================================================\n")
+	fmt.Fprintf(out, "Target:    %d\n", report.Target)
+	fmt.Fprintf(out, "Reached:   %d\n", report.Reached)
+	fmt.Fprintf(out, "Completed: %v\n", report.Completed)
+	fmt.Fprintf(out, "Duration:  %s\n", report.Finished.Sub(report.Started).Round(time.Second))
+	fmt.Fprintf(out, "================================================================================\n\n")
+
+	fmt.Fprintf(out, "%-22s %10s %10s %10s %10s %10s %10s %12s\n",
+		"Blocks", "Time", "Blk/s", "Download", "Validate", "Execute", "Write", "Disk +")
+	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 110))
+	rows := append(append([]Window{}, report.Windows...), report.Total)
+	for i, w := range rows {
+		label := fmt.Sprintf("%d-%d", w.From, w.To)
+		if i == len(rows)-1 {
+			label = "TOTAL"
+		}
+		fmt.Fprintf(out, "%-22s %10s %10.1f %10s %10s %10s %10s %12s\n",
+			label,
+			w.Duration.Round(time.Second),
+			w.BlocksPerSec,
+			w.Stages["download"].Round(time.Millisecond),
+			w.Stages["validation"].Round(time.Millisecond),
+			w.Stages["execution"].Round(time.Millisecond),
+			w.Stages["write"].Round(time.Millisecond),
+			formatBytes(w.DiskGrowth),
+		)
+	}
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(out, "\nErrors:\n")
+		for _, e := range report.Errors {
+			fmt.Fprintf(out, "  - %s\n", e)
+		}
+	}
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit && b > -unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit || n <= -unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -11,7 +11,15 @@
 //   -workers    Number of workers (0=auto, default: 0)
 //   -batch      Batch size (default: 10000)
 //   -lockfree   Use lock-free executor (default: false)
+//   -hot        Accounts shared by all lock-free workers, 0 for disjoint (default: 0)
+//   -workload   Transactions to execute: transfer, erc20, swap or nft (default: transfer)
+//   -state      State backend of the standard mode: memory or mdbx; mdbx also runs
+//               transfers through the EVM executor (default: memory)
 //   -verbose    Verbose output (default: false)
+//   -e2e        Run through the txpool, block sealing and commit (default: false)
+//   -engine     Consensus engine of the e2e mode: apos or clique (default: apos)
+//   -period     Block period of the e2e mode in seconds (default: 0)
+//   -gaslimit   Block gas limit of the e2e mode (default: 30000000)
 
 package main
 
@@ -32,8 +40,16 @@ func main() {
 	workers := flag.Int("workers", 0, "Number of workers (0=auto-detect)")
 	batchSize := flag.Int("batch", 10000, "Batch size for each worker")
 	lockFree := flag.Bool("lockfree", false, "Use lock-free executor for maximum TPS")
+	hotAccounts := flag.Int("hot", 0, "Accounts shared by all lock-free workers to provoke conflicts (0=disjoint)")
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
 	preWarm := flag.Bool("prewarm", true, "Pre-warm state before benchmark")
+	workloadName := flag.String("workload", "transfer", "Workload of the standard mode (transfer, erc20, swap or nft)")
+	stateBackend := flag.String("state", "memory", "State backend of the standard mode (memory or mdbx)")
+	e2e := flag.Bool("e2e", false, "Submit transactions to the txpool and seal and commit real blocks")
+	engine := flag.String("engine", "apos", "Consensus engine of the e2e mode (apos or clique)")
+	period := flag.Uint64("period", 0, "Block period of the e2e mode in seconds")
+	gasLimit := flag.Uint64("gaslimit", 30000000, "Block gas limit of the e2e mode")
+	ordering := flag.String("ordering", "price", "Transaction ordering of the e2e mode (price or fifo)")
 	
 	flag.Parse()
 	
@@ -50,6 +66,12 @@ func main() {
 	fmt.Printf("  OS/Arch:          %s/%s\n", runtime.GOOS, runtime.GOARCH)
 	fmt.Println()
 	
+	workload, err := tps.ParseWorkload(*workloadName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	
 	// Create config
 	config := &tps.BenchConfig{
 		TxCount:        *txCount,
@@ -59,6 +81,13 @@ func main() {
 		TransferAmount: uint256.NewInt(1),     // 1 wei
 		Verbose:        *verbose,
 		PreWarm:        *preWarm,
+		Workload:       workload,
+		HotAccounts:    *hotAccounts,
+		State:          *stateBackend,
+		Engine:         *engine,
+		BlockPeriod:    *period,
+		GasLimit:       *gasLimit,
+		Ordering:       *ordering,
 	}
 	
 	// Validate
@@ -70,12 +99,30 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: batch must be positive\n")
 		os.Exit(1)
 	}
+	if workload != tps.WorkloadTransfer && (*lockFree || *e2e) {
+		fmt.Fprintf(os.Stderr, "Error: -workload %s is only supported by the standard mode\n", workload)
+		os.Exit(1)
+	}
+	if *stateBackend != tps.StateMemory && *stateBackend != tps.StateMDBX {
+		fmt.Fprintf(os.Stderr, "Error: unknown state backend %q (memory or mdbx)\n", *stateBackend)
+		os.Exit(1)
+	}
+	if *stateBackend != tps.StateMemory && (*lockFree || *e2e) {
+		fmt.Fprintf(os.Stderr, "Error: -state %s is only supported by the standard mode\n", *stateBackend)
+		os.Exit(1)
+	}
 	
 	// Run benchmark
 	var result *tps.ExecutionResult
-	var err error
 	
-	if *lockFree {
+	if *e2e {
+		fmt.Println("Mode: End-to-End (Txpool, Block Sealing & Commit)")
+		fmt.Println()
+		var e2eResult *tps.EndToEndResult
+		if e2eResult, err = tps.RunEndToEndBenchmark(config); err == nil {
+			result = &e2eResult.ExecutionResult
+		}
+	} else if *lockFree {
 		fmt.Println("Mode: Lock-Free (Maximum Theoretical TPS)")
 		fmt.Println()
 		result, err = tps.RunLockFreeBenchmark(config)
@@ -105,13 +152,15 @@ func main() {
 	fmt.Println()
 	
 	// Calculate theoretical block stats
-	blockTime := float64(12) // 12 seconds standard block time
-	txPerBlock := result.TPS * blockTime
-	
-	fmt.Printf("  Projected Performance (12s block time):\n")
-	fmt.Printf("    - Transactions per block: %.0f\n", txPerBlock)
-	fmt.Printf("    - Daily transactions:     %.0f million\n", result.TPS * 86400 / 1e6)
-	fmt.Println()
+	if !*e2e {
+		blockTime := float64(12) // 12 seconds standard block time
+		txPerBlock := result.TPS * blockTime
+		
+		fmt.Printf("  Projected Performance (12s block time):\n")
+		fmt.Printf("    - Transactions per block: %.0f\n", txPerBlock)
+		fmt.Printf("    - Daily transactions:     %.0f million\n", result.TPS * 86400 / 1e6)
+		fmt.Println()
+	}
 	
 	// Performance comparison
 	fmt.Printf("  Performance Comparison:\n")
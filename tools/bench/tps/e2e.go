@@ -0,0 +1,576 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// End-to-End TPS Benchmark
+//
+// Unlike the executors in tps_bench.go, this mode measures the chain as a node
+// runs it. Signed transactions are submitted to the real txpool, packed into
+// blocks by the state processor, sealed by the consensus engine and committed
+// to a temporary MDBX chain database. The reported TPS is the sustained rate
+// of committed transactions, and latency is measured from submission until
+// the transaction's block has been written.
+
+package tps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	log2 "github.com/ledgerwatch/log/v3"
+
+	"github.com/n42blockchain/N42/accounts"
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/internal"
+	"github.com/n42blockchain/N42/internal/consensus"
+	"github.com/n42blockchain/N42/internal/consensus/apoa"
+	"github.com/n42blockchain/N42/internal/consensus/apos"
+	"github.com/n42blockchain/N42/internal/miner"
+	"github.com/n42blockchain/N42/internal/txspool"
+	vm2 "github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/rawdb"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+const (
+	// e2eWindow bounds the transactions submitted but not yet committed. It
+	// stays below the pool's global slot limit so pending transactions are
+	// never evicted while the chain catches up.
+	e2eWindow = 4096
+	// e2eSubmitBatch is the number of transactions handed to the pool at once
+	e2eSubmitBatch = 256
+	// e2eBlockSizeReserve mirrors the miner's reserve for the header,
+	// verifiers and rewards when packing transactions.
+	e2eBlockSizeReserve = 16 * 1024
+	// e2eStallTimeout aborts the run when no transaction is committed for
+	// this long, e.g. because the pool dropped transactions.
+	e2eStallTimeout = 30 * time.Second
+)
+
+// EndToEndResult holds the result of an end-to-end run. AvgLatency is the
+// mean time from submission to commit.
+type EndToEndResult struct {
+	ExecutionResult
+	Engine      string
+	Blocks      int
+	Submitted   int64
+	Rejected    int64 // Transactions refused by the txpool
+	MaxBlockTxs int
+	BuildTime   time.Duration // Executing transactions and assembling blocks
+	SealTime    time.Duration // Waiting for the consensus engine to seal
+	CommitTime  time.Duration // Writing blocks and state to the database
+	P50Latency  time.Duration
+	P95Latency  time.Duration
+	P99Latency  time.Duration
+	MaxLatency  time.Duration
+}
+
+// e2eChain is a single-signer chain backed by a temporary database.
+type e2eChain struct {
+	config      *BenchConfig
+	chainConfig *params.ChainConfig
+	dir         string
+	db          kv.RwDB
+	engine      consensus.Engine
+	bc          common.IBlockChain
+	pool        common.ITxsPool
+	ordering    miner.TxOrdering
+	coinbase    types.Address
+
+	mu        sync.Mutex
+	submitted map[types.Hash]time.Time
+	latencies []time.Duration
+}
+
+// newE2EChainConfig returns the benchmark chain config sealed by the given
+// engine. London stays disabled: the generated transactions pay a fixed gas
+// price which a growing base fee would soon exclude.
+func newE2EChainConfig(engine string, period uint64) (*params.ChainConfig, error) {
+	cfg := createBenchChainConfig()
+	cfg.LondonBlock = nil
+	switch params.ConsensusType(engine) {
+	case params.AposConsensu:
+		cfg.Consensus = params.AposConsensu
+		cfg.Apos = &params.APosConfig{Period: period, Epoch: 30000}
+	case params.CliqueConsensus:
+		cfg.Consensus = params.CliqueConsensus
+		cfg.Clique = &params.CliqueConfig{Period: period, Epoch: 30000}
+	default:
+		return nil, fmt.Errorf("unsupported engine %q", engine)
+	}
+	return cfg, nil
+}
+
+// newE2EChain writes a genesis block funding the generator accounts and starts
+// the blockchain, consensus engine and txpool on top of it.
+func newE2EChain(ctx context.Context, config *BenchConfig, generator *TxGenerator) (_ *e2eChain, err error) {
+	chainConfig, err := newE2EChainConfig(config.Engine, config.BlockPeriod)
+	if err != nil {
+		return nil, err
+	}
+	ordering, err := miner.NewTxOrdering(config.Ordering)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signer key: %w", err)
+	}
+	c := &e2eChain{
+		config:      config,
+		chainConfig: chainConfig,
+		ordering:    ordering,
+		coinbase:    crypto.PubkeyToAddress(key.PublicKey),
+		submitted:   make(map[types.Hash]time.Time),
+	}
+	defer func() {
+		if err != nil {
+			c.close()
+		}
+	}()
+
+	if c.dir, err = os.MkdirTemp("", "n42-tps-e2e-"); err != nil {
+		return nil, err
+	}
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+	if c.db, err = mdbx.NewMDBX(log2.New()).
+		Path(filepath.Join(c.dir, kv.ChainDB.String())).
+		Label(kv.ChainDB).
+		MapSize(64 * datasize.GB).
+		Open(); err != nil {
+		return nil, fmt.Errorf("failed to open chain database: %w", err)
+	}
+
+	genesis := &conf.Genesis{
+		Config:     chainConfig,
+		GasLimit:   config.GasLimit,
+		Difficulty: uint256.NewInt(1),
+		BaseFee:    uint256.NewInt(0),
+		Miners:     []string{c.coinbase.Hex()},
+		Alloc:      make(conf.GenesisAlloc, len(generator.GetAccounts())),
+	}
+	for _, acc := range generator.GetAccounts() {
+		genesis.Alloc[acc.Address] = conf.GenesisAccount{Balance: config.InitialBalance.Dec()}
+	}
+	var genesisBlock *block.Block
+	if err = c.db.Update(ctx, func(tx kv.RwTx) error {
+		genesisBlock, _, err = (&internal.GenesisBlock{GenesisConfig: genesis}).Write(tx)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write genesis: %w", err)
+	}
+
+	signFn := func(_ accounts.Account, _ string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	}
+	switch chainConfig.Consensus {
+	case params.AposConsensu:
+		engine := apos.New(chainConfig.Apos, c.db, chainConfig)
+		engine.(*apos.APos).Authorize(c.coinbase, signFn)
+		c.engine = engine
+	case params.CliqueConsensus:
+		engine := apoa.New(chainConfig.Clique, c.db)
+		engine.(*apoa.Apoa).Authorize(c.coinbase, signFn)
+		c.engine = engine
+	}
+
+	if c.bc, err = internal.NewBlockChain(ctx, genesisBlock, c.engine, c.db, nil, chainConfig); err != nil {
+		return nil, err
+	}
+	if pos, ok := c.engine.(*apos.APos); ok {
+		pos.SetBlockChain(c.bc)
+	}
+	if c.pool, err = txspool.NewTxsPool(ctx, c.bc, nil); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// close stops the chain services and removes the temporary database.
+func (c *e2eChain) close() {
+	if c.pool != nil {
+		c.pool.Stop()
+	}
+	if c.bc != nil {
+		c.bc.Close()
+	}
+	if c.engine != nil {
+		c.engine.Close()
+	}
+	if c.db != nil {
+		c.db.Close()
+	}
+	if c.dir != "" {
+		os.RemoveAll(c.dir)
+	}
+}
+
+// submit feeds txs into the pool, keeping at most e2eWindow of them in
+// flight. A slot is freed by commitBlock, or right away for transactions the
+// pool rejects.
+func (c *e2eChain) submit(ctx context.Context, txs []*transaction.Transaction, window chan struct{}, submitted, rejected *atomic.Int64) {
+	for start := 0; start < len(txs); start += e2eSubmitBatch {
+		batch := txs[start:min(start+e2eSubmitBatch, len(txs))]
+		for range batch {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		// New transactions are queued until the pool promotes them, which
+		// stalls while it resets to a new head. Overflowing the global queue
+		// would drop transactions and leave nonce gaps behind.
+		for {
+			if _, _, _, queued := c.pool.Stats(); queued+len(batch) <= int(txspool.DefaultTxPoolConfig.GlobalQueue) {
+				break
+			}
+			select {
+			case <-time.After(time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		now := time.Now()
+		c.mu.Lock()
+		for _, tx := range batch {
+			c.submitted[tx.Hash()] = now
+		}
+		c.mu.Unlock()
+
+		for i, err := range c.pool.AddRemotes(batch) {
+			if err == nil {
+				continue
+			}
+			if c.config.Verbose {
+				fmt.Printf("  rejected %s: %v\n", batch[i].Hash(), err)
+			}
+			c.mu.Lock()
+			delete(c.submitted, batch[i].Hash())
+			c.mu.Unlock()
+			rejected.Add(1)
+			<-window
+		}
+		submitted.Add(int64(len(batch)))
+	}
+}
+
+// buildBlock executes the pending pool transactions on top of the current
+// head in the configured order, the way the miner does, and assembles the
+// resulting block. It returns a nil block when no transaction could be
+// included.
+func (c *e2eChain) buildBlock(ctx context.Context) (block.IBlock, []*block.Receipt, *state.IntraBlockState, map[types.Address]*uint256.Int, error) {
+	parent := c.bc.CurrentBlock().Header().(*block.Header)
+	header := &block.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   c.coinbase,
+		Number:     new(uint256.Int).AddUint64(parent.Number64(), 1),
+		GasLimit:   miner.CalcGasLimit(parent.GasLimit, min(c.config.GasLimit, c.chainConfig.BlockGasLimit())),
+		Time:       uint64(time.Now().Unix()),
+		Difficulty: uint256.NewInt(0),
+		BaseFee:    uint256.NewInt(0),
+	}
+	if err := c.engine.Prepare(c.bc, header); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	pending := c.ordering.Order(c.pool.Pending(true), header)
+	if len(pending) == 0 {
+		return nil, nil, nil, nil, nil
+	}
+
+	tx, err := c.db.BeginRo(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		ibs       = state.New(state.NewPlainStateReader(tx))
+		noop      = state.NewNoopWriter()
+		gasPool   = new(common.GasPool).AddGas(header.GasLimit)
+		getHeader = func(hash types.Hash, number uint64) *block.Header { return rawdb.ReadHeader(tx, hash, number) }
+		sizeLeft  uint64
+		txs       []*transaction.Transaction
+		receipts  []*block.Receipt
+	)
+	if limit := c.chainConfig.BlockSizeLimit(); limit > e2eBlockSizeReserve {
+		sizeLeft = limit - e2eBlockSizeReserve
+	}
+	for _, txn := range pending {
+		if gasPool.Gas() < params.TxGas {
+			break
+		}
+		if txn.Size() > sizeLeft {
+			continue
+		}
+		ibs.Prepare(txn.Hash(), types.Hash{}, len(txs))
+		gasSnap, snap := gasPool.Gas(), ibs.Snapshot()
+		receipt, _, err := internal.ApplyTransaction(c.chainConfig, internal.GetHashFn(header, getHeader), c.engine, &c.coinbase, gasPool, ibs, noop, header, txn, &header.GasUsed, vm2.Config{})
+		if err != nil {
+			// Stale or not yet executable transactions stay in the pool
+			// until its next reset, exactly as with the miner.
+			ibs.RevertToSnapshot(snap)
+			gasPool = new(common.GasPool).AddGas(gasSnap)
+			continue
+		}
+		txs = append(txs, txn)
+		receipts = append(receipts, receipt)
+		sizeLeft -= txn.Size()
+	}
+	if len(txs) == 0 {
+		return nil, nil, nil, nil, nil
+	}
+
+	blk, _, nopay, err := c.engine.FinalizeAndAssemble(c.bc, header, ibs, txs, nil, receipts)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return blk, receipts, ibs, nopay, nil
+}
+
+// sealBlock hands blk to the consensus engine and waits for the signed block.
+func (c *e2eChain) sealBlock(ctx context.Context, blk block.IBlock) (block.IBlock, error) {
+	results := make(chan block.IBlock, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if err := c.engine.Seal(c.bc, blk, results, stop); err != nil {
+		return nil, err
+	}
+	select {
+	case sealed := <-results:
+		return sealed, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// commitBlock writes the sealed block and its state, which announces the new
+// head so the pool drops the included transactions, and records their
+// latency.
+func (c *e2eChain) commitBlock(blk block.IBlock, taskReceipts []*block.Receipt, ibs *state.IntraBlockState, nopay map[types.Address]*uint256.Int, window chan struct{}) error {
+	hash := blk.Hash()
+	receipts := make([]*block.Receipt, len(taskReceipts))
+	for i, taskReceipt := range taskReceipts {
+		receipt := *taskReceipt
+		receipt.BlockHash = hash
+		receipt.BlockNumber = blk.Number64()
+		receipt.TransactionIndex = uint(i)
+		receipts[i] = &receipt
+	}
+	if err := c.bc.WriteBlockWithState(blk, receipts, ibs, nopay); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	for _, tx := range blk.Transactions() {
+		if at, ok := c.submitted[tx.Hash()]; ok {
+			c.latencies = append(c.latencies, now.Sub(at))
+			delete(c.submitted, tx.Hash())
+			<-window
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// percentile returns the p-th percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// RunEndToEndBenchmark runs the benchmark through the txpool, block sealing
+// and commit.
+func RunEndToEndBenchmark(config *BenchConfig) (*EndToEndResult, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.Engine == "" {
+		config.Engine = string(params.AposConsensu)
+	}
+	if config.GasLimit == 0 {
+		config.GasLimit = DefaultConfig().GasLimit
+	}
+
+	// Number of accounts, half of which send: more senders keep the pool's
+	// per-account slot limit out of the way
+	numAccounts := config.TxCount / 8
+	if numAccounts < 1000 {
+		numAccounts = 1000
+	}
+	if numAccounts > 20000 {
+		numAccounts = 20000
+	}
+
+	fmt.Println("========================================")
+	fmt.Println("N42 TPS End-to-End Benchmark")
+	fmt.Println("========================================")
+	fmt.Printf("Engine:        %s\n", config.Engine)
+	fmt.Printf("Block period:  %ds\n", config.BlockPeriod)
+	fmt.Printf("Gas limit:     %d\n", config.GasLimit)
+	fmt.Printf("Transactions:  %d\n", config.TxCount)
+	fmt.Printf("Accounts:      %d\n", numAccounts)
+	fmt.Println("========================================")
+
+	fmt.Println("Generating accounts...")
+	chainID := big.NewInt(1337)
+	generator, err := NewTxGenerator(numAccounts, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Pre-generating transactions...")
+	nonces := make([]uint64, numAccounts)
+	txs, err := generator.GenerateBatch(config.TxCount, config.TransferAmount, nonces)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fmt.Println("Starting chain...")
+	chain, err := newE2EChain(ctx, config, generator)
+	if err != nil {
+		return nil, err
+	}
+	defer chain.close()
+
+	fmt.Println("Running end-to-end benchmark...")
+	var (
+		window    = make(chan struct{}, e2eWindow)
+		submitted atomic.Int64
+		rejected  atomic.Int64
+		result    = &EndToEndResult{Engine: config.Engine}
+		total     = int64(len(txs))
+		committed int64
+		progress  = time.Now()
+	)
+	startTime := time.Now()
+	go chain.submit(ctx, txs, window, &submitted, &rejected)
+
+	for committed+rejected.Load() < total {
+		if time.Since(progress) > e2eStallTimeout {
+			return nil, fmt.Errorf("no transaction committed for %v (%d committed, %d rejected, %d submitted)",
+				e2eStallTimeout, committed, rejected.Load(), submitted.Load())
+		}
+
+		buildStart := time.Now()
+		blk, receipts, ibs, nopay, err := chain.buildBlock(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build block: %w", err)
+		}
+		if blk == nil {
+			// Wait for the submitter or the pool reset after the last block
+			time.Sleep(5 * time.Millisecond)
+			continue
+		}
+		sealStart := time.Now()
+		sealed, err := chain.sealBlock(ctx, blk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal block %d: %w", blk.Number64().Uint64(), err)
+		}
+		commitStart := time.Now()
+		if err := chain.commitBlock(sealed, receipts, ibs, nopay, window); err != nil {
+			return nil, fmt.Errorf("failed to commit block %d: %w", sealed.Number64().Uint64(), err)
+		}
+		commitEnd := time.Now()
+
+		n := len(sealed.Transactions())
+		committed += int64(n)
+		progress = commitEnd
+		result.Blocks++
+		result.MaxBlockTxs = max(result.MaxBlockTxs, n)
+		result.BuildTime += sealStart.Sub(buildStart)
+		result.SealTime += commitStart.Sub(sealStart)
+		result.CommitTime += commitEnd.Sub(commitStart)
+
+		if config.Verbose {
+			fmt.Printf("  Block %d: %d txs, gas %d, build %v, seal %v, commit %v\n",
+				sealed.Number64().Uint64(), n, sealed.GasUsed(),
+				sealStart.Sub(buildStart).Round(time.Microsecond),
+				commitStart.Sub(sealStart).Round(time.Microsecond),
+				commitEnd.Sub(commitStart).Round(time.Microsecond))
+		}
+	}
+	totalDuration := time.Since(startTime)
+	if committed == 0 {
+		return nil, errors.New("no transaction was committed")
+	}
+
+	latencies := chain.latencies
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	result.ExecutionResult = ExecutionResult{
+		TxCount:    committed,
+		Duration:   totalDuration,
+		TPS:        float64(committed) / totalDuration.Seconds(),
+		AvgLatency: sum / time.Duration(len(latencies)),
+	}
+	result.Submitted = submitted.Load()
+	result.Rejected = rejected.Load()
+	result.P50Latency = percentile(latencies, 50)
+	result.P95Latency = percentile(latencies, 95)
+	result.P99Latency = percentile(latencies, 99)
+	result.MaxLatency = latencies[len(latencies)-1]
+
+	// Print results
+	fmt.Println()
+	fmt.Println("========================================")
+	fmt.Println("End-to-End Results")
+	fmt.Println("========================================")
+	fmt.Printf("Committed Transactions: %d\n", result.TxCount)
+	fmt.Printf("Rejected by Pool:       %d\n", result.Rejected)
+	fmt.Printf("Blocks:                 %d (avg %.1f txs, max %d)\n",
+		result.Blocks, float64(result.TxCount)/float64(result.Blocks), result.MaxBlockTxs)
+	fmt.Printf("Total Duration:         %v\n", result.Duration)
+	fmt.Printf("Chain TPS:              %.2f\n", result.TPS)
+	fmt.Println()
+	fmt.Println("Time per Phase:")
+	fmt.Printf("  Build:  %v (avg %v/block)\n", result.BuildTime, result.BuildTime/time.Duration(result.Blocks))
+	fmt.Printf("  Seal:   %v (avg %v/block)\n", result.SealTime, result.SealTime/time.Duration(result.Blocks))
+	fmt.Printf("  Commit: %v (avg %v/block)\n", result.CommitTime, result.CommitTime/time.Duration(result.Blocks))
+	fmt.Println()
+	fmt.Println("Submit-to-Commit Latency:")
+	fmt.Printf("  Avg: %v  P50: %v  P95: %v  P99: %v  Max: %v\n",
+		result.AvgLatency, result.P50Latency, result.P95Latency, result.P99Latency, result.MaxLatency)
+	fmt.Println("========================================")
+
+	return result, nil
+}
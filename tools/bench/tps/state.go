@@ -0,0 +1,188 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// State Backends of the EVM Executor
+//
+// The memory backend keeps the state in an in-memory database and gives every
+// worker a private overlay of it, so the measured TPS is bound by execution.
+// The mdbx backend runs against a temporary on-disk MDBX database instead:
+// every block is committed through state.PlainStateWriter together with its
+// change sets and history, exactly as the chain writes it, so the gap between
+// both backends is the cost of persisting state.
+//
+// Workers commit their blocks independently. Contract storage written by
+// several workers is last-writer-wins on both backends.
+
+package tps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	log2 "github.com/ledgerwatch/log/v3"
+
+	"github.com/n42blockchain/N42/modules"
+	"github.com/n42blockchain/N42/modules/ethdb/dbstat"
+	"github.com/n42blockchain/N42/modules/state"
+	"github.com/n42blockchain/N42/params"
+)
+
+// State backends of the EVM executor
+const (
+	StateMemory = "memory" // In-memory database with per-worker overlays
+	StateMDBX   = "mdbx"   // Temporary on-disk MDBX database
+)
+
+// benchState is the database the EVM executor runs against
+type benchState struct {
+	backend string
+	db      kv.RwDB
+	dir     string
+	blocks  atomic.Uint64 // Last block number handed out to a worker
+}
+
+// openBenchState creates an empty database for backend. The caller must
+// close it.
+func openBenchState(backend string) (_ *benchState, err error) {
+	modules.N42Init()
+	kv.ChaindataTablesCfg = modules.N42TableCfg
+
+	s := &benchState{backend: backend}
+	switch backend {
+	case "", StateMemory:
+		s.backend = StateMemory
+		s.db = memdb.New("")
+	case StateMDBX:
+		if s.dir, err = os.MkdirTemp("", "n42-tps-state-"); err != nil {
+			return nil, err
+		}
+		if s.db, err = mdbx.NewMDBX(log2.New()).
+			Path(filepath.Join(s.dir, kv.ChainDB.String())).
+			Label(kv.ChainDB).
+			MapSize(64 * datasize.GB).
+			Open(); err != nil {
+			os.RemoveAll(s.dir)
+			return nil, fmt.Errorf("failed to open state database: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown state backend %q (want %s or %s)", backend, StateMemory, StateMDBX)
+	}
+	return s, nil
+}
+
+// close closes the database and removes it from disk
+func (s *benchState) close() {
+	s.db.Close()
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// size returns the space the state occupies in the MDBX data file, or 0 for
+// the memory backend
+func (s *benchState) size() uint64 {
+	if s.backend != StateMDBX {
+		return 0
+	}
+	stat, err := dbstat.Summary(context.Background(), s.db)
+	if err != nil {
+		return 0
+	}
+	return stat.UsedSize
+}
+
+// session opens a worker's view of the state. It must be used and closed on
+// the goroutine's locked OS thread, as MDBX transactions are bound to it.
+func (s *benchState) session() (stateSession, error) {
+	tx, err := s.db.BeginRo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if s.backend == StateMDBX {
+		return &mdbxSession{state: s, tx: tx}, nil
+	}
+	return &memorySession{tx: tx, batch: memdb.NewMemoryBatch(tx, "")}, nil
+}
+
+// stateSession executes consecutive blocks of one worker
+type stateSession interface {
+	// begin returns the block number and state reader of the next block
+	begin() (uint64, state.StateReader, error)
+	// commit writes the state changes of the block begun last
+	commit(ibs *state.IntraBlockState, rules *params.Rules) error
+	close()
+}
+
+// memorySession commits blocks to an overlay of a read-only transaction
+type memorySession struct {
+	tx     kv.Tx
+	batch  *memdb.MemoryMutation
+	number uint64
+}
+
+func (m *memorySession) begin() (uint64, state.StateReader, error) {
+	m.number++
+	return m.number, state.NewPlainStateReader(m.batch), nil
+}
+
+func (m *memorySession) commit(ibs *state.IntraBlockState, rules *params.Rules) error {
+	return ibs.CommitBlock(rules, state.NewPlainStateWriterNoHistory(m.batch))
+}
+
+func (m *memorySession) close() {
+	m.batch.Rollback()
+	m.tx.Rollback()
+}
+
+// mdbxSession reads every block from a fresh read-only transaction and
+// commits it in its own write transaction
+type mdbxSession struct {
+	state  *benchState
+	tx     kv.Tx
+	number uint64
+}
+
+func (m *mdbxSession) begin() (uint64, state.StateReader, error) {
+	if m.tx == nil {
+		tx, err := m.state.db.BeginRo(context.Background())
+		if err != nil {
+			return 0, nil, err
+		}
+		m.tx = tx
+	}
+	m.number = m.state.blocks.Add(1)
+	return m.number, state.NewPlainStateReader(m.tx), nil
+}
+
+func (m *mdbxSession) commit(ibs *state.IntraBlockState, rules *params.Rules) error {
+	// The read transaction would pin the snapshot the commit replaces
+	m.tx.Rollback()
+	m.tx = nil
+
+	return m.state.db.Update(context.Background(), func(tx kv.RwTx) error {
+		stateWriter := state.NewPlainStateWriter(tx, tx, m.number)
+		if err := ibs.CommitBlock(rules, stateWriter); err != nil {
+			return err
+		}
+		if err := stateWriter.WriteChangeSets(); err != nil {
+			return fmt.Errorf("writing changesets for block %d failed: %w", m.number, err)
+		}
+		if err := stateWriter.WriteHistory(); err != nil {
+			return fmt.Errorf("writing history for block %d failed: %w", m.number, err)
+		}
+		return nil
+	})
+}
+
+func (m *mdbxSession) close() {
+	if m.tx != nil {
+		m.tx.Rollback()
+	}
+}
@@ -53,6 +53,21 @@ type BenchConfig struct {
 	Verbose bool
 	// Pre-warm the state before benchmark
 	PreWarm bool
+	// Transactions to execute (native transfers or contract calls)
+	Workload Workload
+	// State backend of the EVM executor ("memory" or "mdbx")
+	State string
+	// Accounts shared by all lock-free workers (0 = disjoint accounts per worker)
+	HotAccounts int
+	// Consensus engine of the end-to-end mode ("apos" or "clique")
+	Engine string
+	// Block period of the end-to-end mode in seconds
+	BlockPeriod uint64
+	// Block gas limit of the end-to-end mode
+	GasLimit uint64
+	// Transaction ordering of the end-to-end mode, see miner.TxOrderings
+	// (empty = price)
+	Ordering string
 }
 
 // DefaultConfig returns default benchmark configuration
@@ -65,6 +80,10 @@ func DefaultConfig() *BenchConfig {
 		TransferAmount: uint256.NewInt(1),    // 1 wei
 		Verbose:        false,
 		PreWarm:        true,
+		Workload:       WorkloadTransfer,
+		State:          StateMemory,
+		Engine:         "apos",
+		GasLimit:       30000000,
 	}
 }
 
@@ -301,8 +320,14 @@ func (g *TxGenerator) GetAccounts() []*Account {
 // ExecutionResult holds the result of transaction execution
 type ExecutionResult struct {
 	TxCount       int64
+	Attempted     int64  // Transactions submitted, including failed ones (lock-free mode)
+	Failed        int64  // Transactions that reverted or could not be applied
+	Conflicts     int64  // Compare-and-swap retries caused by concurrent account updates
+	GasUsed       uint64 // Gas used by the executed contract calls
+	StateSize     uint64 // Size of the state database on disk (mdbx state backend)
 	Duration      time.Duration
-	TPS           float64
+	TPS           float64 // Executed transactions per second
+	AttemptedTPS  float64 // Attempted transactions per second (lock-free mode)
 	AvgLatency    time.Duration
 	WorkerResults []WorkerResult
 }
@@ -603,6 +628,12 @@ func RunBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	fmt.Printf("Workers:       %d\n", workers)
 	fmt.Printf("Transactions:  %d\n", config.TxCount)
 	fmt.Printf("Batch Size:    %d\n", config.BatchSize)
+	if config.Workload != "" {
+		fmt.Printf("Workload:      %s\n", config.Workload)
+	}
+	if config.State != "" {
+		fmt.Printf("State:         %s\n", config.State)
+	}
 	fmt.Println("========================================")
 	
 	// Create state database
@@ -624,12 +655,19 @@ func RunBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	fmt.Println("Initializing state...")
 	generator.InitializeState(stateDB, config.InitialBalance)
 	
-	// Create executor
-	executor := NewParallelExecutor(config, stateDB)
-	
 	// Run benchmark
-	fmt.Println("Running benchmark...")
-	result, err := executor.Run(generator)
+	var result *ExecutionResult
+	if (config.Workload == "" || config.Workload == WorkloadTransfer) && config.State != StateMDBX {
+		executor := NewParallelExecutor(config, stateDB)
+		
+		fmt.Println("Running benchmark...")
+		result, err = executor.Run(generator)
+	} else {
+		executor := NewEVMExecutor(config)
+		
+		fmt.Println("Running EVM benchmark...")
+		result, err = executor.Run(generator, config.Workload)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("benchmark failed: %w", err)
 	}
@@ -643,6 +681,13 @@ func RunBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	fmt.Printf("Total Duration:     %v\n", result.Duration)
 	fmt.Printf("TPS:                %.2f\n", result.TPS)
 	fmt.Printf("Avg Latency:        %v\n", result.AvgLatency)
+	if result.GasUsed > 0 {
+		fmt.Printf("Failed:             %d\n", result.Failed)
+		fmt.Printf("Gas Throughput:     %.2f Mgas/s\n", float64(result.GasUsed)/1e6/result.Duration.Seconds())
+	}
+	if result.StateSize > 0 {
+		fmt.Printf("State Size:         %.2f MB\n", float64(result.StateSize)/1024/1024)
+	}
 	fmt.Println()
 	fmt.Println("Worker Stats:")
 	for _, wr := range result.WorkerResults {
@@ -665,7 +710,9 @@ type LockFreeExecutor struct {
 	workers     int
 }
 
-// AccountState holds lock-free account state using atomic operations
+// AccountState holds lock-free account state using atomic operations.
+// Balances are immutable once stored and only replaced by compare-and-swap,
+// so concurrent transfers touching the same account never lose an update.
 type AccountState struct {
 	Balance atomic.Pointer[uint256.Int]
 	Nonce   atomic.Uint64
@@ -692,40 +739,62 @@ func NewLockFreeExecutor(config *BenchConfig, numAccounts int) *LockFreeExecutor
 	}
 }
 
-// ExecuteTransferLockFree executes a transfer with minimal locking
-func (e *LockFreeExecutor) ExecuteTransferLockFree(fromIdx, toIdx int, amount *uint256.Int) bool {
+// ExecuteTransferLockFree executes a transfer without locking. It returns
+// whether the transfer was applied and how often a compare-and-swap had to
+// be retried because another worker updated one of the accounts first.
+func (e *LockFreeExecutor) ExecuteTransferLockFree(fromIdx, toIdx int, amount *uint256.Int) (bool, int64) {
 	fromState := e.accountState[fromIdx]
 	toState := e.accountState[toIdx]
 	
-	// Get current balance
-	balance := fromState.Balance.Load()
-	if balance.Lt(amount) {
-		return false
-	}
+	var retries int64
 	
-	// Update balances (simplified - in production would need CAS)
-	newFromBal := new(uint256.Int).Sub(balance, amount)
-	fromState.Balance.Store(newFromBal)
-	
-	toBal := toState.Balance.Load()
-	newToBal := new(uint256.Int).Add(toBal, amount)
-	toState.Balance.Store(newToBal)
-	
-	// Increment nonce
+	// Debit the sender, re-checking the balance on every attempt
+	for {
+		balance := fromState.Balance.Load()
+		if balance.Lt(amount) {
+			return false, retries
+		}
+		if fromState.Balance.CompareAndSwap(balance, new(uint256.Int).Sub(balance, amount)) {
+			break
+		}
+		retries++
+	}
 	fromState.Nonce.Add(1)
 	
-	return true
+	// Credit the recipient
+	for {
+		balance := toState.Balance.Load()
+		if toState.Balance.CompareAndSwap(balance, new(uint256.Int).Add(balance, amount)) {
+			break
+		}
+		retries++
+	}
+	
+	return true, retries
+}
+
+// transferPair returns the accounts of the i-th transfer of a worker. By
+// default every worker owns a disjoint range of accounts; with HotAccounts
+// set, all workers transfer between the same few accounts to provoke
+// conflicts.
+func (e *LockFreeExecutor) transferPair(workerID, i int) (int, int) {
+	if hot := e.config.HotAccounts; hot > 0 {
+		from := (i + workerID) % hot
+		return from, (from + 1) % hot
+	}
+	numAccounts := len(e.accountState)
+	lo := workerID * numAccounts / e.workers
+	span := (workerID+1)*numAccounts/e.workers - lo
+	return lo + (2*i)%span, lo + (2*i+1)%span
 }
 
 // RunLockFree runs lock-free benchmark
 func (e *LockFreeExecutor) RunLockFree() (*ExecutionResult, error) {
 	txCount := e.config.TxCount
-	numAccounts := len(e.accountState)
 	
-	var totalExecuted int64
+	var totalExecuted, totalConflicts int64
 	var wg sync.WaitGroup
 	
-	txPerWorker := txCount / e.workers
 	workerResults := make([]WorkerResult, e.workers)
 	
 	startTime := time.Now()
@@ -736,24 +805,23 @@ func (e *LockFreeExecutor) RunLockFree() (*ExecutionResult, error) {
 			defer wg.Done()
 			
 			workerStart := time.Now()
-			var workerExecuted int64
-			
-			// Each worker handles a disjoint set of accounts
-			startAccount := (workerID * numAccounts / e.workers) * 2
+			var workerExecuted, workerConflicts int64
 			
-			for i := 0; i < txPerWorker; i++ {
-				fromIdx := (startAccount + i*2) % numAccounts
-				toIdx := (startAccount + i*2 + 1) % numAccounts
-				if toIdx == fromIdx {
-					toIdx = (toIdx + 1) % numAccounts
-				}
-				
-				if e.ExecuteTransferLockFree(fromIdx, toIdx, e.config.TransferAmount) {
+			workerTxs := txCount / e.workers
+			if workerID < txCount%e.workers {
+				workerTxs++
+			}
+			for i := 0; i < workerTxs; i++ {
+				fromIdx, toIdx := e.transferPair(workerID, i)
+				ok, retries := e.ExecuteTransferLockFree(fromIdx, toIdx, e.config.TransferAmount)
+				if ok {
 					workerExecuted++
 				}
+				workerConflicts += retries
 			}
 			
 			atomic.AddInt64(&totalExecuted, workerExecuted)
+			atomic.AddInt64(&totalConflicts, workerConflicts)
 			
 			workerDuration := time.Since(workerStart)
 			workerResults[workerID] = WorkerResult{
@@ -768,17 +836,44 @@ func (e *LockFreeExecutor) RunLockFree() (*ExecutionResult, error) {
 	wg.Wait()
 	
 	totalDuration := time.Since(startTime)
-	tps := float64(totalExecuted) / totalDuration.Seconds()
+	if totalExecuted == 0 {
+		return nil, fmt.Errorf("all %d transfers were rejected", txCount)
+	}
+	if err := e.Verify(totalExecuted); err != nil {
+		return nil, err
+	}
 	
 	return &ExecutionResult{
 		TxCount:       totalExecuted,
+		Attempted:     int64(txCount),
+		Failed:        int64(txCount) - totalExecuted,
+		Conflicts:     totalConflicts,
 		Duration:      totalDuration,
-		TPS:           tps,
+		TPS:           float64(totalExecuted) / totalDuration.Seconds(),
+		AttemptedTPS:  float64(txCount) / totalDuration.Seconds(),
 		AvgLatency:    totalDuration / time.Duration(totalExecuted),
 		WorkerResults: workerResults,
 	}, nil
 }
 
+// Verify checks the account state after a run: transfers must neither create
+// nor destroy balance, and every executed transfer advances one nonce.
+func (e *LockFreeExecutor) Verify(executed int64) error {
+	var total, nonces = new(uint256.Int), uint64(0)
+	for _, acc := range e.accountState {
+		total.Add(total, acc.Balance.Load())
+		nonces += acc.Nonce.Load()
+	}
+	expected := new(uint256.Int).Mul(e.config.InitialBalance, uint256.NewInt(uint64(len(e.accountState))))
+	if !total.Eq(expected) {
+		return fmt.Errorf("total balance %s does not match the initial supply %s", total, expected)
+	}
+	if nonces != uint64(executed) {
+		return fmt.Errorf("accounts sent %d transfers but %d were executed", nonces, executed)
+	}
+	return nil
+}
+
 // RunLockFreeBenchmark runs the lock-free TPS benchmark
 func RunLockFreeBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	if config == nil {
@@ -796,6 +891,9 @@ func RunLockFreeBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	fmt.Printf("CPU Cores:     %d\n", runtime.NumCPU())
 	fmt.Printf("Workers:       %d\n", workers)
 	fmt.Printf("Transactions:  %d\n", config.TxCount)
+	if config.HotAccounts > 0 {
+		fmt.Printf("Hot Accounts:  %d\n", config.HotAccounts)
+	}
 	fmt.Println("========================================")
 	
 	// Number of accounts (must be > workers * 2)
@@ -803,6 +901,9 @@ func RunLockFreeBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	if numAccounts < 1000 {
 		numAccounts = 1000
 	}
+	if hot := config.HotAccounts; hot != 0 && (hot < 2 || hot > numAccounts) {
+		return nil, fmt.Errorf("hot accounts must be between 2 and %d", numAccounts)
+	}
 	
 	executor := NewLockFreeExecutor(config, numAccounts)
 	
@@ -817,10 +918,15 @@ func RunLockFreeBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	fmt.Println("========================================")
 	fmt.Println("Lock-Free Results")
 	fmt.Println("========================================")
-	fmt.Printf("Total Transactions: %d\n", result.TxCount)
+	fmt.Printf("Attempted:          %d\n", result.Attempted)
+	fmt.Printf("Executed:           %d\n", result.TxCount)
+	fmt.Printf("Rejected:           %d\n", result.Failed)
+	fmt.Printf("Conflicts:          %d (%.4f retries/tx)\n", result.Conflicts, float64(result.Conflicts)/float64(result.Attempted))
 	fmt.Printf("Total Duration:     %v\n", result.Duration)
-	fmt.Printf("TPS:                %.2f\n", result.TPS)
+	fmt.Printf("Effective TPS:      %.2f\n", result.TPS)
+	fmt.Printf("Attempted TPS:      %.2f\n", result.AttemptedTPS)
 	fmt.Printf("Avg Latency:        %v\n", result.AvgLatency)
+	fmt.Println("State Check:        balances conserved, nonces consistent")
 	fmt.Println()
 	fmt.Println("Worker Stats:")
 	for _, wr := range result.WorkerResults {
@@ -831,4 +937,3 @@ func RunLockFreeBenchmark(config *BenchConfig) (*ExecutionResult, error) {
 	
 	return result, nil
 }
-
@@ -0,0 +1,401 @@
+// Copyright 2022-2026 The N42 Authors
+// This file is part of the N42 library.
+//
+// Contract-Call Workloads
+//
+// The native transfer path never enters the EVM. The workloads below call
+// small contracts whose runtime bytecode is embedded, so the EVM executor
+// measures interpreter- and storage-bound TPS on the real IntraBlockState:
+//
+// - erc20: ERC-20 transfer(address,uint256) with a Transfer event
+// - swap:  Uniswap-style constant-product swap(uint256) crediting the output
+// - nft:   ERC-721 style mint() assigning the next token id to the caller
+//
+// The contracts use Solidity's storage layout, so the state they touch is
+// shaped like that of the contracts they stand in for.
+
+package tps
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/n42blockchain/N42/common"
+	"github.com/n42blockchain/N42/common/block"
+	"github.com/n42blockchain/N42/common/crypto"
+	"github.com/n42blockchain/N42/common/hexutil"
+	"github.com/n42blockchain/N42/common/transaction"
+	"github.com/n42blockchain/N42/common/types"
+	"github.com/n42blockchain/N42/conf"
+	"github.com/n42blockchain/N42/internal"
+	vm2 "github.com/n42blockchain/N42/internal/vm"
+	"github.com/n42blockchain/N42/modules/state"
+)
+
+// Workload selects the transactions a benchmark executes
+type Workload string
+
+const (
+	WorkloadTransfer Workload = "transfer" // Native value transfers
+	WorkloadERC20    Workload = "erc20"    // ERC-20 token transfers
+	WorkloadSwap     Workload = "swap"     // Constant-product swaps
+	WorkloadNFT      Workload = "nft"      // NFT mints
+)
+
+// workloadBlockTxs is the number of transactions executed per block,
+// roughly what fits into a 30M gas block.
+const workloadBlockTxs = 500
+
+// Workloads lists the supported workloads
+var Workloads = []Workload{WorkloadTransfer, WorkloadERC20, WorkloadSwap, WorkloadNFT}
+
+// ParseWorkload returns the workload with the given name
+func ParseWorkload(name string) (Workload, error) {
+	for _, w := range Workloads {
+		if string(w) == name {
+			return w, nil
+		}
+	}
+	return "", fmt.Errorf("unknown workload %q (want one of %v)", name, Workloads)
+}
+
+// benchContract is the address every workload contract is deployed at
+var benchContract = types.HexToAddress("0x00000000000000000000000000000000000b3c42")
+
+// contractProfile describes a contract-call workload
+type contractProfile struct {
+	code []byte
+	gas  uint64
+	// input returns the calldata of a call by sender to recipient
+	input func(recipient types.Address) []byte
+	// storage returns the contract storage funding the senders
+	storage func(senders []*Account) map[types.Hash]types.Hash
+}
+
+var contractProfiles = map[Workload]*contractProfile{
+	// balances: mapping(address => uint256) at slot 0
+	//
+	//	require(selector == transfer)
+	//	require(balances[caller] >= amount)
+	//	balances[caller] -= amount; balances[to] += amount
+	//	emit Transfer(caller, to, amount); return true
+	WorkloadERC20: {
+		code: hexutil.MustDecode("0x60003560e01c63a9059cbb14610015575b600080fd5b33600052600060205260406000208054602435" +
+			"8082106100105790819003825590506004356000526040600020805482019055600052600435337fddf252ad1be2c89b69c2b068fc" +
+			"378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f3"),
+		gas: 100000,
+		input: func(recipient types.Address) []byte {
+			return callData("a9059cbb", [32]byte(recipient.Hash()), uint256.NewInt(1).Bytes32())
+		},
+		storage: func(senders []*Account) map[types.Hash]types.Hash {
+			storage := make(map[types.Hash]types.Hash, len(senders))
+			for _, acc := range senders {
+				storage[mappingSlot(acc.Address, 0)] = types.Hash(uint256.NewInt(1e18).Bytes32())
+			}
+			return storage
+		},
+	},
+	// reserve0 at slot 0, reserve1 at slot 1,
+	// balance1: mapping(address => uint256) at slot 2,
+	// balance0: mapping(address => uint256) at slot 3
+	//
+	//	require(selector == swap)
+	//	require(balance0[caller] >= amountIn); balance0[caller] -= amountIn
+	//	out = amountIn*997*reserve1 / (reserve0*1000 + amountIn*997)
+	//	require(out > 0)
+	//	reserve0 += amountIn; reserve1 -= out; balance1[caller] += out
+	//	emit Swap(caller, amountIn, out); return out
+	WorkloadSwap: {
+		code: hexutil.MustDecode("0x60003560e01c6394b918de14610015575b600080fd5b3360005260036020526040600020805460043580" +
+			"82106100105790819003825590506103e5810260015481026000546103e802820190049050801561001057600054820160005580" +
+			"600154036001553360005260026020526040600020805482019055602052600052337f77f92a1b6a1a11de8ca49515ad4c1fad4563" +
+			"2dd3442167d74b90b304a3c7a75860406000a2602080f3"),
+		gas: 150000,
+		input: func(types.Address) []byte {
+			return callData("94b918de", uint256.NewInt(1e6).Bytes32())
+		},
+		storage: func(senders []*Account) map[types.Hash]types.Hash {
+			reserve, _ := uint256.FromBig(new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil))
+			storage := make(map[types.Hash]types.Hash, len(senders)+2)
+			storage[types.Hash{31: 0}] = types.Hash(reserve.Bytes32())
+			storage[types.Hash{31: 1}] = types.Hash(reserve.Bytes32())
+			for _, acc := range senders {
+				storage[mappingSlot(acc.Address, 3)] = types.Hash(uint256.NewInt(1e18).Bytes32())
+			}
+			return storage
+		},
+	},
+	// totalSupply at slot 0,
+	// owners: mapping(uint256 => address) at slot 1,
+	// balances: mapping(address => uint256) at slot 2
+	//
+	//	require(selector == mint)
+	//	id = totalSupply++; owners[id] = caller; balances[caller]++
+	//	emit Transfer(0, caller, id)
+	WorkloadNFT: {
+		code: hexutil.MustDecode("0x60003560e01c631249c58b1461001457600080fd5b600054806001016000558060005260016020526040" +
+			"6000203390553360005260026020526040600020805460010190553360007fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a" +
+			"11628f55a4df523b3ef600080a400"),
+		gas: 150000,
+		input: func(types.Address) []byte {
+			return callData("1249c58b")
+		},
+		storage: func([]*Account) map[types.Hash]types.Hash { return nil },
+	},
+}
+
+// callData concatenates a hex function selector and its 32-byte arguments
+func callData(selector string, args ...[32]byte) []byte {
+	data := hexutil.MustDecode("0x" + selector)
+	for _, arg := range args {
+		data = append(data, arg[:]...)
+	}
+	return data
+}
+
+// mappingSlot returns the storage slot of key in a Solidity mapping declared
+// at slot
+func mappingSlot(key types.Address, slot uint64) types.Hash {
+	var buf [64]byte
+	copy(buf[12:32], key[:])
+	big.NewInt(int64(slot)).FillBytes(buf[32:])
+	return types.BytesToHash(crypto.Keccak256(buf[:]))
+}
+
+// GenerateCall generates a signed contract call
+func (g *TxGenerator) GenerateCall(from int, contract types.Address, nonce, gas uint64, data []byte) (*transaction.Transaction, error) {
+	fromAcc := g.accounts[from]
+	tx := transaction.NewTransaction(
+		nonce,
+		fromAcc.Address,
+		&contract,
+		uint256.NewInt(0),
+		gas,
+		uint256.NewInt(1), // 1 wei gas price
+		data,
+	)
+	return transaction.SignTx(tx, g.signer, fromAcc.PrivateKey)
+}
+
+// generateWorkerTxs generates count calls of profile for a worker, or native
+// transfers of amount if profile is nil. Every worker sends from its own
+// accounts, so it can apply its transactions in nonce order.
+func (g *TxGenerator) generateWorkerTxs(profile *contractProfile, amount *uint256.Int, worker, workers, count int) ([]*transaction.Transaction, error) {
+	numAccounts := len(g.accounts)
+	var senders []int
+	for i := worker; i < numAccounts; i += workers {
+		senders = append(senders, i)
+	}
+	nonces := make([]uint64, len(senders))
+
+	txs := make([]*transaction.Transaction, count)
+	for i := range txs {
+		s := i % len(senders)
+		from := senders[s]
+		to := (from + 1) % numAccounts
+
+		var (
+			tx  *transaction.Transaction
+			err error
+		)
+		if profile == nil {
+			tx, err = g.GenerateTx(from, to, nonces[s], amount)
+		} else {
+			tx, err = g.GenerateCall(from, benchContract, nonces[s], profile.gas, profile.input(g.accounts[to].Address))
+		}
+		if err != nil {
+			return nil, err
+		}
+		nonces[s]++
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+// Run executes the workload's transactions through the EVM. The generator
+// accounts and the workload's contract are written to the configured state
+// backend as a genesis state; every worker then applies its transactions in
+// blocks on top of it.
+func (e *EVMExecutor) Run(generator *TxGenerator, workload Workload) (*ExecutionResult, error) {
+	profile, ok := contractProfiles[workload]
+	if !ok && workload != WorkloadTransfer {
+		return nil, fmt.Errorf("unknown workload %q", workload)
+	}
+
+	st, err := openBenchState(e.config.State)
+	if err != nil {
+		return nil, err
+	}
+	defer st.close()
+
+	genesis := &conf.Genesis{
+		Config:     e.chainConfig,
+		GasLimit:   math.MaxUint64 / 2,
+		Difficulty: uint256.NewInt(0),
+		BaseFee:    uint256.NewInt(0),
+		Alloc:      make(conf.GenesisAlloc, len(generator.accounts)+1),
+	}
+	for _, acc := range generator.accounts {
+		genesis.Alloc[acc.Address] = conf.GenesisAccount{Balance: e.config.InitialBalance.Dec()}
+	}
+	if profile != nil {
+		genesis.Alloc[benchContract] = conf.GenesisAccount{
+			Balance: "0",
+			Code:    profile.code,
+			Storage: profile.storage(generator.accounts),
+		}
+	}
+	if err := st.db.Update(context.Background(), func(tx kv.RwTx) error {
+		_, _, err := (&internal.GenesisBlock{GenesisConfig: genesis}).Write(tx)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write genesis state: %w", err)
+	}
+
+	// Pre-generate all transactions
+	if e.config.Verbose {
+		fmt.Printf("Pre-generating %d %s transactions...\n", e.config.TxCount, workload)
+	}
+	genStart := time.Now()
+	workerTxs := make([][]*transaction.Transaction, e.workers)
+	for w := range workerTxs {
+		count := e.config.TxCount / e.workers
+		if w < e.config.TxCount%e.workers {
+			count++
+		}
+		txs, err := generator.generateWorkerTxs(profile, e.config.TransferAmount, w, e.workers, count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate transactions: %w", err)
+		}
+		workerTxs[w] = txs
+	}
+	if e.config.Verbose {
+		genDuration := time.Since(genStart)
+		fmt.Printf("Transaction generation: %v (%.0f tx/s)\n", genDuration, float64(e.config.TxCount)/genDuration.Seconds())
+		fmt.Printf("Starting benchmark with %d workers...\n", e.workers)
+	}
+
+	var (
+		totalExecuted int64
+		totalFailed   int64
+		totalGas      uint64
+		wg            sync.WaitGroup
+		errOnce       sync.Once
+		runErr        error
+		workerResults = make([]WorkerResult, e.workers)
+	)
+	startTime := time.Now()
+
+	for w := 0; w < e.workers; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			// MDBX read transactions are bound to their OS thread
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+
+			workerStart := time.Now()
+			executed, failed, gasUsed, err := e.executeTxs(st, workerTxs[workerID])
+			if err != nil {
+				errOnce.Do(func() { runErr = err })
+				return
+			}
+			atomic.AddInt64(&totalExecuted, executed)
+			atomic.AddInt64(&totalFailed, failed)
+			atomic.AddUint64(&totalGas, gasUsed)
+
+			workerDuration := time.Since(workerStart)
+			workerResults[workerID] = WorkerResult{
+				WorkerID: workerID,
+				TxCount:  executed,
+				Duration: workerDuration,
+				TPS:      float64(executed) / workerDuration.Seconds(),
+			}
+		}(w)
+	}
+	wg.Wait()
+	if runErr != nil {
+		return nil, runErr
+	}
+	if totalExecuted == 0 {
+		return nil, fmt.Errorf("all %d transactions failed", totalFailed)
+	}
+
+	totalDuration := time.Since(startTime)
+	return &ExecutionResult{
+		TxCount:       totalExecuted,
+		Failed:        totalFailed,
+		GasUsed:       totalGas,
+		StateSize:     st.size(),
+		Duration:      totalDuration,
+		TPS:           float64(totalExecuted) / totalDuration.Seconds(),
+		AvgLatency:    totalDuration / time.Duration(totalExecuted),
+		WorkerResults: workerResults,
+	}, nil
+}
+
+// executeTxs applies txs in order, sealing every workloadBlockTxs of them
+// into a block that is committed to st. Transactions that revert or cannot be
+// applied are counted as failed.
+func (e *EVMExecutor) executeTxs(st *benchState, txs []*transaction.Transaction) (executed, failed int64, gasUsed uint64, err error) {
+	session, err := st.session()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer session.close()
+
+	var (
+		coinbase = types.Address{}
+		noop     = state.NewNoopWriter()
+		getHash  = func(uint64) types.Hash { return types.Hash{} }
+	)
+	for start := 0; start < len(txs); start += workloadBlockTxs {
+		end := start + workloadBlockTxs
+		if end > len(txs) {
+			end = len(txs)
+		}
+		number, reader, err := session.begin()
+		if err != nil {
+			return executed, failed, gasUsed, err
+		}
+		var (
+			ibs    = state.New(reader)
+			header = &block.Header{
+				Number:     uint256.NewInt(number),
+				GasLimit:   math.MaxUint64 / 2,
+				Time:       uint64(time.Now().Unix()),
+				Difficulty: uint256.NewInt(0),
+				BaseFee:    uint256.NewInt(0),
+			}
+			gasPool = new(common.GasPool).AddGas(header.GasLimit)
+		)
+		for i, txn := range txs[start:end] {
+			ibs.Prepare(txn.Hash(), types.Hash{}, i)
+			receipt, _, err := internal.ApplyTransaction(e.chainConfig, getHash, nil, &coinbase, gasPool, ibs, noop, header, txn, &header.GasUsed, vm2.Config{})
+			if err != nil || receipt.Status != block.ReceiptStatusSuccessful {
+				failed++
+				if e.config.Verbose && failed == 1 {
+					fmt.Printf("  first failed transaction %s: err=%v\n", txn.Hash(), err)
+				}
+				continue
+			}
+			executed++
+		}
+		// Storage touched within a block stays dirty until it is committed,
+		// so unbounded blocks would make every call slower than the last.
+		if err := session.commit(ibs, e.chainConfig.Rules(number)); err != nil {
+			return executed, failed, gasUsed, err
+		}
+		gasUsed += header.GasUsed
+	}
+	return executed, failed, gasUsed, nil
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/n42blockchain/N42/common/types"
 	"github.com/n42blockchain/N42/modules/rawdb"
 )
 
@@ -31,12 +32,36 @@ func GetLatestBlockNumber(tx kv.Tx) (*uint256.Int, error) {
 	return current.Number64(), nil
 }
 
+// GetFinalizedBlockNumber returns the number of the latest block finalized by
+// the verifiers, or the genesis block if nothing was finalized yet.
 func GetFinalizedBlockNumber(tx kv.Tx) (*uint256.Int, error) {
-	//todo
-	return GetLatestBlockNumber(tx)
+	if number, ok := canonicalNumber(tx, rawdb.ReadFinalizedBlockHash(tx)); ok {
+		return uint256.NewInt(number), nil
+	}
+	return uint256.NewInt(0), nil
 }
 
+// GetSafeBlockNumber returns the number of the latest justified block, falling
+// back to the finalized block if it is no longer canonical.
 func GetSafeBlockNumber(tx kv.Tx) (*uint256.Int, error) {
-	//todo
-	return GetLatestBlockNumber(tx)
+	if number, ok := canonicalNumber(tx, rawdb.ReadSafeBlockHash(tx)); ok {
+		return uint256.NewInt(number), nil
+	}
+	return GetFinalizedBlockNumber(tx)
+}
+
+// canonicalNumber returns the number of the block hash if it is part of the
+// canonical chain.
+func canonicalNumber(tx kv.Tx, hash types.Hash) (uint64, bool) {
+	if hash == (types.Hash{}) {
+		return 0, false
+	}
+	number := rawdb.ReadHeaderNumber(tx, hash)
+	if number == nil {
+		return 0, false
+	}
+	if canonical, err := rawdb.ReadCanonicalHash(tx, *number); err != nil || canonical != hash {
+		return 0, false
+	}
+	return *number, true
 }
@@ -206,10 +206,10 @@ func SplitAndTrim(input string) (ret []string) {
 func ConvertH256ToUint256Int(h256 *types_pb.H256) *uint256.Int {
 	// Note: uint256.Int is an array of 4 uint64 in little-endian order, i.e. most significant word is [3]
 	var i uint256.Int
-	i[3] = h256.Hi.Hi
-	i[2] = h256.Hi.Lo
-	i[1] = h256.Lo.Hi
-	i[0] = h256.Lo.Lo
+	i[3] = h256.GetHi().GetHi()
+	i[2] = h256.GetHi().GetLo()
+	i[1] = h256.GetLo().GetHi()
+	i[0] = h256.GetLo().GetLo()
 	return &i
 }
 
@@ -226,23 +226,23 @@ func ConvertH256ToHash(h256 *types_pb.H256) [32]byte {
 	if nil == h256 {
 		return hash
 	}
-	binary.BigEndian.PutUint64(hash[0:], h256.Hi.Hi)
-	binary.BigEndian.PutUint64(hash[8:], h256.Hi.Lo)
-	binary.BigEndian.PutUint64(hash[16:], h256.Lo.Hi)
-	binary.BigEndian.PutUint64(hash[24:], h256.Lo.Lo)
+	binary.BigEndian.PutUint64(hash[0:], h256.GetHi().GetHi())
+	binary.BigEndian.PutUint64(hash[8:], h256.GetHi().GetLo())
+	binary.BigEndian.PutUint64(hash[16:], h256.GetLo().GetHi())
+	binary.BigEndian.PutUint64(hash[24:], h256.GetLo().GetLo())
 	return hash
 }
 
 func ConvertH512ToHash(h512 *types_pb.H512) [64]byte {
 	var b [64]byte
-	binary.BigEndian.PutUint64(b[0:], h512.Hi.Hi.Hi)
-	binary.BigEndian.PutUint64(b[8:], h512.Hi.Hi.Lo)
-	binary.BigEndian.PutUint64(b[16:], h512.Hi.Lo.Hi)
-	binary.BigEndian.PutUint64(b[24:], h512.Hi.Lo.Lo)
-	binary.BigEndian.PutUint64(b[32:], h512.Lo.Hi.Hi)
-	binary.BigEndian.PutUint64(b[40:], h512.Lo.Hi.Lo)
-	binary.BigEndian.PutUint64(b[48:], h512.Lo.Lo.Hi)
-	binary.BigEndian.PutUint64(b[56:], h512.Lo.Lo.Lo)
+	binary.BigEndian.PutUint64(b[0:], h512.GetHi().GetHi().GetHi())
+	binary.BigEndian.PutUint64(b[8:], h512.GetHi().GetHi().GetLo())
+	binary.BigEndian.PutUint64(b[16:], h512.GetHi().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[24:], h512.GetHi().GetLo().GetLo())
+	binary.BigEndian.PutUint64(b[32:], h512.GetLo().GetHi().GetHi())
+	binary.BigEndian.PutUint64(b[40:], h512.GetLo().GetHi().GetLo())
+	binary.BigEndian.PutUint64(b[48:], h512.GetLo().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[56:], h512.GetLo().GetLo().GetLo())
 	return b
 }
 
@@ -283,9 +283,9 @@ func ConvertHashToH512(hash [64]byte) *types_pb.H512 {
 
 func ConvertH160toAddress(h160 *types_pb.H160) [20]byte {
 	var addr [20]byte
-	binary.BigEndian.PutUint64(addr[0:], h160.Hi.Hi)
-	binary.BigEndian.PutUint64(addr[8:], h160.Hi.Lo)
-	binary.BigEndian.PutUint32(addr[16:], h160.Lo)
+	binary.BigEndian.PutUint64(addr[0:], h160.GetHi().GetHi())
+	binary.BigEndian.PutUint64(addr[8:], h160.GetHi().GetLo())
+	binary.BigEndian.PutUint32(addr[16:], h160.GetLo())
 	return addr
 }
 
@@ -344,12 +344,12 @@ func ConvertBytesToH512(b []byte) *types_pb.H512 {
 
 func ConvertH384ToPublicKey(h384 *types_pb.H384) [48]byte {
 	var pub [48]byte
-	binary.BigEndian.PutUint64(pub[0:], h384.Hi.Hi.Hi)
-	binary.BigEndian.PutUint64(pub[8:], h384.Hi.Hi.Lo)
-	binary.BigEndian.PutUint64(pub[16:], h384.Hi.Lo.Hi)
-	binary.BigEndian.PutUint64(pub[24:], h384.Hi.Lo.Lo)
-	binary.BigEndian.PutUint64(pub[32:], h384.Lo.Hi)
-	binary.BigEndian.PutUint64(pub[40:], h384.Lo.Lo)
+	binary.BigEndian.PutUint64(pub[0:], h384.GetHi().GetHi().GetHi())
+	binary.BigEndian.PutUint64(pub[8:], h384.GetHi().GetHi().GetLo())
+	binary.BigEndian.PutUint64(pub[16:], h384.GetHi().GetLo().GetHi())
+	binary.BigEndian.PutUint64(pub[24:], h384.GetHi().GetLo().GetLo())
+	binary.BigEndian.PutUint64(pub[32:], h384.GetLo().GetHi())
+	binary.BigEndian.PutUint64(pub[40:], h384.GetLo().GetLo())
 	return pub
 }
 
@@ -383,19 +383,19 @@ func H384sToPubs(ps []*types_pb.H384) []types.PublicKey {
 }
 func ConvertH768ToSignature(h768 *types_pb.H768) [96]byte {
 	var b [96]byte
-	binary.BigEndian.PutUint64(b[0:], h768.Hi.Hi.Hi.Hi)
-	binary.BigEndian.PutUint64(b[8:], h768.Hi.Hi.Hi.Lo)
-	binary.BigEndian.PutUint64(b[16:], h768.Hi.Hi.Lo.Hi)
-	binary.BigEndian.PutUint64(b[24:], h768.Hi.Hi.Lo.Lo)
-	binary.BigEndian.PutUint64(b[32:], h768.Hi.Lo.Hi)
-	binary.BigEndian.PutUint64(b[40:], h768.Hi.Lo.Lo)
-
-	binary.BigEndian.PutUint64(b[48:], h768.Lo.Hi.Hi.Hi)
-	binary.BigEndian.PutUint64(b[56:], h768.Lo.Hi.Hi.Lo)
-	binary.BigEndian.PutUint64(b[64:], h768.Lo.Hi.Lo.Hi)
-	binary.BigEndian.PutUint64(b[72:], h768.Lo.Hi.Lo.Lo)
-	binary.BigEndian.PutUint64(b[80:], h768.Lo.Lo.Hi)
-	binary.BigEndian.PutUint64(b[88:], h768.Lo.Lo.Lo)
+	binary.BigEndian.PutUint64(b[0:], h768.GetHi().GetHi().GetHi().GetHi())
+	binary.BigEndian.PutUint64(b[8:], h768.GetHi().GetHi().GetHi().GetLo())
+	binary.BigEndian.PutUint64(b[16:], h768.GetHi().GetHi().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[24:], h768.GetHi().GetHi().GetLo().GetLo())
+	binary.BigEndian.PutUint64(b[32:], h768.GetHi().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[40:], h768.GetHi().GetLo().GetLo())
+
+	binary.BigEndian.PutUint64(b[48:], h768.GetLo().GetHi().GetHi().GetHi())
+	binary.BigEndian.PutUint64(b[56:], h768.GetLo().GetHi().GetHi().GetLo())
+	binary.BigEndian.PutUint64(b[64:], h768.GetLo().GetHi().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[72:], h768.GetLo().GetHi().GetLo().GetLo())
+	binary.BigEndian.PutUint64(b[80:], h768.GetLo().GetLo().GetHi())
+	binary.BigEndian.PutUint64(b[88:], h768.GetLo().GetLo().GetLo())
 	return b
 }
 
@@ -423,10 +423,10 @@ func ConvertSignatureToH768(p [96]byte) *types_pb.H768 {
 
 func ConvertH2048ToBloom(h2048 *types_pb.H2048) [256]byte {
 	var bloom [256]byte
-	copy(bloom[:], ConvertH512ToBytes(h2048.Hi.Hi))
-	copy(bloom[64:], ConvertH512ToBytes(h2048.Hi.Lo))
-	copy(bloom[128:], ConvertH512ToBytes(h2048.Lo.Hi))
-	copy(bloom[192:], ConvertH512ToBytes(h2048.Lo.Lo))
+	copy(bloom[:], ConvertH512ToBytes(h2048.GetHi().GetHi()))
+	copy(bloom[64:], ConvertH512ToBytes(h2048.GetHi().GetLo()))
+	copy(bloom[128:], ConvertH512ToBytes(h2048.GetLo().GetHi()))
+	copy(bloom[192:], ConvertH512ToBytes(h2048.GetLo().GetLo()))
 	return bloom
 }
 
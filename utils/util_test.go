@@ -20,6 +20,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/n42blockchain/N42/api/protocol/types_pb"
 	"github.com/n42blockchain/N42/common/types"
 	"testing"
 )
@@ -96,3 +97,15 @@ func TestStringToPublic(t *testing.T) {
 	t.Logf("private :%s", ps)
 	t.Log(len(ps))
 }
+
+// Decoding hostile protobuf messages must not panic on missing fields
+func TestConvertIncompleteProto(t *testing.T) {
+	if addr := ConvertH160toAddress(nil); addr != ([20]byte{}) {
+		t.Errorf("nil H160 decoded to %x", addr)
+	}
+	if i := ConvertH256ToUint256Int(&types_pb.H256{Lo: &types_pb.H128{Lo: 7}}); i.Uint64() != 7 {
+		t.Errorf("partial H256 decoded to %v", i)
+	}
+	ConvertH768ToSignature(&types_pb.H768{Hi: &types_pb.H384{}})
+	ConvertH2048ToBloom(nil)
+}